@@ -56,6 +56,11 @@ type ProxyConfig struct {
 	//   - A split-horizon DNS entry that resolves to the proxy IP
 	//   - A mDNS name (e.g. "macbook.local")
 	ExtraSANHosts []string `json:"extra_san_hosts"`
+
+	// MailBridge enables the cross-town mail bridge at /v1/mail/relay.
+	// Peer towns are taken from the "towns" section of the town's
+	// config/messaging.json; only those towns are accepted.
+	MailBridge bool `json:"mail_bridge"`
 }
 
 // loadConfig reads the config file at path and returns a ProxyConfig.
@@ -6,6 +6,7 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log/slog"
 	"net"
 	"os"
@@ -15,7 +16,10 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/proxy"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // defaultAllowedSubcmds lists the safe subcommands for gt and bd.
@@ -33,7 +37,8 @@ func main() {
 		allowedCmds    = flag.String("allowed-cmds", "gt,bd", "comma-separated list of allowed commands")
 		allowedSubcmds = flag.String("allowed-subcmds", discoverAllowedSubcmds(),
 			`semicolon-separated list of "cmd:sub1,sub2,..." subcommand allowlists`)
-		townRoot = flag.String("town-root", "", "Gas Town root directory (default: $GT_TOWN or ~/gt)")
+		townRoot   = flag.String("town-root", "", "Gas Town root directory (default: $GT_TOWN or ~/gt)")
+		mailBridge = flag.Bool("mail-bridge", false, "accept mail relayed from peer towns listed in config/messaging.json")
 	)
 	flag.Parse()
 
@@ -77,6 +82,9 @@ func main() {
 	if !explicitFlags["allowed-subcmds"] && len(fileCfg.AllowedSubcommands) > 0 {
 		*allowedSubcmds = buildAllowedSubcmds(fileCfg.AllowedSubcommands)
 	}
+	if !explicitFlags["mail-bridge"] && fileCfg.MailBridge {
+		*mailBridge = true
+	}
 
 	if *caDir == "" {
 		*caDir = filepath.Join(home, "gt", ".runtime", "ca")
@@ -136,6 +144,15 @@ func main() {
 		ExtraSANHosts:      extraSANHosts,
 	}
 
+	if *mailBridge {
+		bridge, err := newMailBridge(*townRoot)
+		if err != nil {
+			slog.Error("mail bridge setup failed", "err", err)
+			os.Exit(1)
+		}
+		cfg.MailBridge = bridge
+	}
+
 	srv, err := proxy.New(cfg, ca)
 	if err != nil {
 		slog.Error("invalid server config", "err", err)
@@ -151,6 +168,34 @@ func main() {
 	}
 }
 
+// newMailBridge builds the cross-town mail bridge handler for townRoot.
+// Relayed messages are delivered through the town's mail router; only peer
+// towns configured in config/messaging.json are accepted.
+func newMailBridge(townRoot string) (*mail.BridgeHandler, error) {
+	townName, err := workspace.GetTownName(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("determining town name: %w", err)
+	}
+	msgCfg, err := config.LoadOrCreateMessagingConfig(config.MessagingConfigPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading messaging config: %w", err)
+	}
+	peers := make([]string, 0, len(msgCfg.Towns))
+	for name := range msgCfg.Towns {
+		peers = append(peers, name)
+	}
+	if len(peers) == 0 {
+		slog.Warn("mail bridge enabled but no peer towns configured — all relays will be rejected")
+	}
+	slog.Info("mail bridge enabled", "town", townName, "peers", peers)
+
+	router := mail.NewRouterWithTownRoot(townRoot, townRoot)
+	return mail.NewBridgeHandler(townName, peers, func(msg *mail.Message) error {
+		defer router.WaitPendingNotifications()
+		return router.Send(msg)
+	}), nil
+}
+
 // discoverAllowedSubcmds calls "gt proxy-subcmds" to auto-discover the allowed
 // subcommand list. Falls back to defaultAllowedSubcmds if the command is
 // unavailable or returns empty output.
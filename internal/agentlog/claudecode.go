@@ -237,18 +237,59 @@ func tailJSONL(ctx context.Context, path, projectDir string, since time.Time, se
 	}
 }
 
+// ReadTranscript reads a whole Claude Code conversation: <nativeSessionID>.jsonl
+// in workDir's project directory, or the newest JSONL file there modified at
+// or after since when the ID is unknown.
+func (a *ClaudeCodeAdapter) ReadTranscript(nativeSessionID, workDir string, since time.Time) ([]AgentEvent, error) {
+	projectDir, err := claudeProjectDirFor(workDir)
+	if err != nil {
+		return nil, err
+	}
+	path := ""
+	if nativeSessionID != "" {
+		candidate := filepath.Join(projectDir, nativeSessionID+".jsonl")
+		if _, err := os.Stat(candidate); err == nil {
+			path = candidate
+		}
+	}
+	if path == "" {
+		newest, ok := newestJSONLIn(projectDir, since)
+		if !ok {
+			return nil, fmt.Errorf("%w in %s", ErrNoTranscript, projectDir)
+		}
+		path = newest
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	nativeID := nativeSessionIDFromPath(path)
+	var events []AgentEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 256*1024), 16*1024*1024)
+	for scanner.Scan() {
+		events = append(events, parseClaudeCodeLine(scanner.Text(), "", a.AgentType(), nativeID)...)
+	}
+	return events, scanner.Err()
+}
+
 // ── Claude Code JSONL structures ──────────────────────────────────────────────
 
 // ccEntry is a top-level line in a Claude Code JSONL file.
 type ccEntry struct {
-	Type      string     `json:"type"`
-	Message   *ccMessage `json:"message,omitempty"`
-	Timestamp string     `json:"timestamp,omitempty"`
+	Type        string     `json:"type"`
+	Message     *ccMessage `json:"message,omitempty"`
+	Timestamp   string     `json:"timestamp,omitempty"`
+	IsSidechain bool       `json:"isSidechain,omitempty"` // subagent (Task tool) turn
 }
 
 // ccMessage is the message field of a ccEntry.
 type ccMessage struct {
 	Role    string      `json:"role"`
+	Model   string      `json:"model,omitempty"`
 	Content []ccContent `json:"content"`
 	Usage   *ccUsage    `json:"usage,omitempty"`
 }
@@ -353,6 +394,7 @@ func parseClaudeCodeLine(line, sessionID, agentType, nativeSessionID string) []A
 				OutputTokens:        u.OutputTokens,
 				CacheReadTokens:     u.CacheReadInputTokens,
 				CacheCreationTokens: u.CacheCreationInputTokens,
+				Model:               entry.Message.Model,
 			})
 		}
 	}
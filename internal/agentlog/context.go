@@ -0,0 +1,132 @@
+package agentlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// contextTailBytes is how much of the end of a conversation log is scanned
+// for the latest turn's usage before falling back to the whole file.
+const contextTailBytes = 1 << 20
+
+// ContextUsage is the context-window occupancy of an agent's latest turn.
+type ContextUsage struct {
+	NativeSessionID string
+	Model           string
+	Tokens          int // prompt size of the turn: input plus cache read and creation tokens
+	Timestamp       time.Time
+}
+
+// ContextTokens returns the prompt size of a "usage" event: its input tokens
+// plus cached tokens, which together are what the turn held in context.
+func (e AgentEvent) ContextTokens() int {
+	return e.InputTokens + e.CacheReadTokens + e.CacheCreationTokens
+}
+
+// ErrContextUnsupported is returned for agents whose adapter can't read
+// context usage.
+var ErrContextUnsupported = errors.New("context usage not supported")
+
+// ContextUsageReader is implemented by adapters that can report how full a
+// live conversation's context window is without tailing it.
+type ContextUsageReader interface {
+	// LastContextUsage returns the usage of the latest main-conversation turn
+	// in the newest conversation for workDir active at or after since, or
+	// nil when that conversation has no assistant turns yet.
+	LastContextUsage(workDir string, since time.Time) (*ContextUsage, error)
+}
+
+// LastContextUsage reads the latest turn's context usage with the adapter for
+// agentType.
+func LastContextUsage(agentType, workDir string, since time.Time) (*ContextUsage, error) {
+	reader, ok := NewAdapter(agentType).(ContextUsageReader)
+	if !ok {
+		return nil, fmt.Errorf("%w for agent type %q", ErrContextUnsupported, agentType)
+	}
+	return reader.LastContextUsage(workDir, since)
+}
+
+// LastContextUsage reads the tail of the newest Claude Code conversation for
+// workDir and returns the usage of its last main-conversation assistant turn.
+// Subagent (sidechain) turns are skipped: they run in their own context.
+func (a *ClaudeCodeAdapter) LastContextUsage(workDir string, since time.Time) (*ContextUsage, error) {
+	projectDir, err := claudeProjectDirFor(workDir)
+	if err != nil {
+		return nil, err
+	}
+	path, ok := newestJSONLIn(projectDir, since)
+	if !ok {
+		return nil, fmt.Errorf("%w in %s", ErrNoTranscript, projectDir)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := max(info.Size()-contextTailBytes, 0)
+	usage, err := lastContextUsageFrom(f, offset)
+	if err == nil && usage == nil && offset > 0 {
+		// The tail held no complete assistant turn (e.g. one huge tool result).
+		usage, err = lastContextUsageFrom(f, 0)
+	}
+	if err != nil || usage == nil {
+		return nil, err
+	}
+	usage.NativeSessionID = nativeSessionIDFromPath(path)
+	return usage, nil
+}
+
+// lastContextUsageFrom scans f from offset and returns the last usage found.
+// A nonzero offset usually lands mid-line, so the first line is discarded.
+func lastContextUsageFrom(f *os.File, offset int64) (*ContextUsage, error) {
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 256*1024), 16*1024*1024)
+	if offset > 0 {
+		scanner.Scan()
+	}
+	var last *ContextUsage
+	for scanner.Scan() {
+		if u := contextUsageFromLine(scanner.Bytes()); u != nil {
+			last = u
+		}
+	}
+	return last, scanner.Err()
+}
+
+// contextUsageFromLine returns the context usage recorded on one JSONL line,
+// or nil if the line is not a main-conversation assistant turn with usage.
+func contextUsageFromLine(line []byte) *ContextUsage {
+	if !bytes.Contains(line, []byte(`"usage"`)) {
+		return nil
+	}
+	var entry ccEntry
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return nil
+	}
+	if entry.Type != "assistant" || entry.IsSidechain || entry.Message == nil || entry.Message.Usage == nil {
+		return nil
+	}
+	u := entry.Message.Usage
+	tokens := u.InputTokens + u.CacheReadInputTokens + u.CacheCreationInputTokens
+	if tokens == 0 {
+		// Synthetic messages (e.g. API errors) carry zeroed usage.
+		return nil
+	}
+	ts, _ := time.Parse(time.RFC3339, entry.Timestamp)
+	return &ContextUsage{Model: entry.Message.Model, Tokens: tokens, Timestamp: ts}
+}
@@ -0,0 +1,82 @@
+package agentlog
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClaudeCodeLastContextUsage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	workDir := "/tmp/gt/gastown/crew/max"
+	projectDir, err := claudeProjectDirFor(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(projectDir, "abc-123.jsonl")
+
+	writeFile(t, path, `{"type":"user","message":{"role":"user","content":[{"type":"text","text":"hi"}]}}`+"\n")
+	if u, err := LastContextUsage("claudecode", workDir, time.Time{}); err != nil || u != nil {
+		t.Fatalf("no turns yet: LastContextUsage = %+v, %v; want nil, nil", u, err)
+	}
+
+	writeFile(t, path, strings.Join([]string{
+		`{"type":"assistant","timestamp":"2026-01-02T03:04:05.000Z","message":{"role":"assistant","model":"claude-opus-4-1","content":[],"usage":{"input_tokens":10,"output_tokens":50,"cache_read_input_tokens":1000,"cache_creation_input_tokens":200}}}`,
+		`{"type":"assistant","timestamp":"2026-01-02T03:05:00.000Z","message":{"role":"assistant","model":"claude-opus-4-1","content":[],"usage":{"input_tokens":20,"output_tokens":80,"cache_read_input_tokens":150000,"cache_creation_input_tokens":500}}}`,
+		`{"type":"assistant","isSidechain":true,"message":{"role":"assistant","model":"claude-haiku-4-5","content":[],"usage":{"input_tokens":5,"cache_read_input_tokens":9000}}}`,
+		`{"type":"assistant","message":{"role":"assistant","model":"<synthetic>","content":[],"usage":{"input_tokens":0,"output_tokens":0}}}`,
+		`{"type":"user","message":{"role":"user","content":[{"type":"text","text":"next"}]}}`,
+	}, "\n")+"\n")
+
+	u, err := LastContextUsage("claudecode", workDir, time.Time{})
+	if err != nil {
+		t.Fatalf("LastContextUsage: %v", err)
+	}
+	if u == nil || u.Tokens != 150520 || u.Model != "claude-opus-4-1" || u.NativeSessionID != "abc-123" {
+		t.Fatalf("LastContextUsage = %+v, want the last main-conversation turn", u)
+	}
+	if want := time.Date(2026, 1, 2, 3, 5, 0, 0, time.UTC); !u.Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", u.Timestamp, want)
+	}
+
+	if _, err := LastContextUsage("claudecode", "/tmp/elsewhere", time.Time{}); !errors.Is(err, ErrNoTranscript) {
+		t.Errorf("missing project: err = %v, want ErrNoTranscript", err)
+	}
+	if _, err := LastContextUsage("opencode", workDir, time.Time{}); !errors.Is(err, ErrContextUnsupported) {
+		t.Errorf("opencode: err = %v, want ErrContextUnsupported", err)
+	}
+}
+
+func TestClaudeCodeLastContextUsage_LongTail(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	workDir := "/tmp/gt/gastown/crew/max"
+	projectDir, err := claudeProjectDirFor(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A tool result larger than the tail window after the last turn forces a
+	// full scan.
+	big := strings.Repeat("x", contextTailBytes+1024)
+	writeFile(t, filepath.Join(projectDir, "s.jsonl"), strings.Join([]string{
+		`{"type":"assistant","message":{"role":"assistant","content":[],"usage":{"input_tokens":7,"cache_read_input_tokens":42000}}}`,
+		`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","content":"` + big + `"}]}}`,
+	}, "\n")+"\n")
+
+	u, err := LastContextUsage("claudecode", workDir, time.Time{})
+	if err != nil || u == nil || u.Tokens != 42007 {
+		t.Fatalf("LastContextUsage = %+v, %v; want 42007 tokens", u, err)
+	}
+}
+
+func TestParseClaudeCodeLine_UsageModel(t *testing.T) {
+	line := `{"type":"assistant","message":{"role":"assistant","model":"claude-sonnet-4-5","content":[],"usage":{"input_tokens":3,"output_tokens":4,"cache_read_input_tokens":5,"cache_creation_input_tokens":6}}}`
+	events := parseClaudeCodeLine(line, "hq-mayor", "claudecode", "n")
+	if len(events) != 1 || events[0].EventType != "usage" {
+		t.Fatalf("events = %+v, want one usage event", events)
+	}
+	if events[0].Model != "claude-sonnet-4-5" || events[0].ContextTokens() != 14 {
+		t.Errorf("usage = %+v, ContextTokens() = %d; want model and 14", events[0], events[0].ContextTokens())
+	}
+}
@@ -24,10 +24,11 @@ type AgentEvent struct {
 
 	// Token usage fields — non-zero only for EventType == "usage".
 	// One "usage" event is emitted per assistant turn (not per content block).
-	InputTokens         int // input_tokens from Claude API usage
-	OutputTokens        int // output_tokens from Claude API usage
-	CacheReadTokens     int // cache_read_input_tokens
-	CacheCreationTokens int // cache_creation_input_tokens
+	InputTokens         int    // input_tokens from Claude API usage
+	OutputTokens        int    // output_tokens from Claude API usage
+	CacheReadTokens     int    // cache_read_input_tokens
+	CacheCreationTokens int    // cache_creation_input_tokens
+	Model               string // model that produced the turn, when the log records it
 }
 
 // AgentAdapter watches an agent's conversation log and streams normalized events.
@@ -2,13 +2,23 @@ package agentlog
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"time"
 )
 
-// OpenCodeAdapter is a placeholder for future OpenCode conversation log support.
-// OpenCode stores conversations differently from Claude Code; implement Watch
-// here when adding OpenCode telemetry.
+// OpenCodeAdapter reads OpenCode conversation storage. Live watching is not
+// implemented yet; ReadTranscript reads finished sessions.
+//
+// OpenCode keeps one JSON file per session, message and message part under
+// $XDG_DATA_HOME/opencode/storage (default ~/.local/share/opencode/storage):
+//
+//	session/<projectID>/<sessionID>.json
+//	message/<sessionID>/<messageID>.json
+//	part/<messageID>/<partID>.json
 //
 // See: https://github.com/sst/opencode for OpenCode's storage format.
 type OpenCodeAdapter struct{}
@@ -19,3 +29,173 @@ func (a *OpenCodeAdapter) AgentType() string { return "opencode" }
 func (a *OpenCodeAdapter) Watch(_ context.Context, _, _ string, _ time.Time) (<-chan AgentEvent, error) {
 	return nil, fmt.Errorf("opencode adapter not yet implemented")
 }
+
+// ocTime holds OpenCode's millisecond timestamps.
+type ocTime struct {
+	Created int64 `json:"created"`
+	Updated int64 `json:"updated"`
+}
+
+type ocSession struct {
+	ID        string `json:"id"`
+	Directory string `json:"directory"`
+	Time      ocTime `json:"time"`
+}
+
+type ocMessage struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
+	Time ocTime `json:"time"`
+}
+
+type ocPart struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Tool  string `json:"tool"`
+	State struct {
+		Input  json.RawMessage `json:"input"`
+		Output string          `json:"output"`
+	} `json:"state"`
+}
+
+// openCodeStorageDir returns OpenCode's storage root.
+func openCodeStorageDir() (string, error) {
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "opencode", "storage"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home dir: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "opencode", "storage"), nil
+}
+
+// ReadTranscript reads one OpenCode session: nativeSessionID if it exists,
+// otherwise the most recently updated session for workDir updated at or
+// after since.
+func (a *OpenCodeAdapter) ReadTranscript(nativeSessionID, workDir string, since time.Time) ([]AgentEvent, error) {
+	storage, err := openCodeStorageDir()
+	if err != nil {
+		return nil, err
+	}
+	session, err := findOpenCodeSession(storage, nativeSessionID, workDir, since)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []ocMessage
+	readJSONDir(filepath.Join(storage, "message", session.ID), func(data []byte) {
+		var m ocMessage
+		if json.Unmarshal(data, &m) == nil && m.ID != "" {
+			messages = append(messages, m)
+		}
+	})
+	sort.SliceStable(messages, func(i, j int) bool {
+		if messages[i].Time.Created != messages[j].Time.Created {
+			return messages[i].Time.Created < messages[j].Time.Created
+		}
+		return messages[i].ID < messages[j].ID
+	})
+
+	var events []AgentEvent
+	for _, m := range messages {
+		var parts []ocPart
+		readJSONDir(filepath.Join(storage, "part", m.ID), func(data []byte) {
+			var p ocPart
+			if json.Unmarshal(data, &p) == nil {
+				parts = append(parts, p)
+			}
+		})
+		// Part IDs are time-ordered.
+		sort.SliceStable(parts, func(i, j int) bool { return parts[i].ID < parts[j].ID })
+
+		ts := time.UnixMilli(m.Time.Created)
+		emit := func(eventType, content string) {
+			if content == "" {
+				return
+			}
+			events = append(events, AgentEvent{
+				AgentType:       a.AgentType(),
+				NativeSessionID: session.ID,
+				EventType:       eventType,
+				Role:            m.Role,
+				Content:         content,
+				Timestamp:       ts,
+			})
+		}
+		for _, p := range parts {
+			switch p.Type {
+			case "text":
+				emit("text", p.Text)
+			case "reasoning":
+				emit("thinking", p.Text)
+			case "tool":
+				emit("tool_use", p.Tool+": "+string(p.State.Input))
+				emit("tool_result", p.State.Output)
+			}
+		}
+	}
+	return events, nil
+}
+
+// findOpenCodeSession locates a session file across all projects.
+func findOpenCodeSession(storage, nativeSessionID, workDir string, since time.Time) (*ocSession, error) {
+	absWorkDir, _ := filepath.Abs(workDir)
+	projects, _ := os.ReadDir(filepath.Join(storage, "session"))
+	var best *ocSession
+	for _, project := range projects {
+		if !project.IsDir() {
+			continue
+		}
+		readJSONDir(filepath.Join(storage, "session", project.Name()), func(data []byte) {
+			var s ocSession
+			if json.Unmarshal(data, &s) != nil || s.ID == "" {
+				return
+			}
+			if nativeSessionID != "" {
+				if s.ID == nativeSessionID {
+					best = &s
+				}
+				return
+			}
+			if workDir == "" || filepath.Clean(s.Directory) != absWorkDir {
+				return
+			}
+			updated := time.UnixMilli(max(s.Time.Updated, s.Time.Created))
+			if !since.IsZero() && updated.Before(since) {
+				return
+			}
+			if best == nil || max(s.Time.Updated, s.Time.Created) > max(best.Time.Updated, best.Time.Created) {
+				best = &s
+			}
+		})
+	}
+	if best == nil {
+		if nativeSessionID != "" && workDir != "" {
+			// The recorded ID may be a Gas Town fallback ID rather than
+			// OpenCode's own; look the session up by directory instead.
+			return findOpenCodeSession(storage, "", workDir, since)
+		}
+		return nil, fmt.Errorf("%w in %s", ErrNoTranscript, storage)
+	}
+	return best, nil
+}
+
+// readJSONDir calls fn with the contents of each .json file in dir.
+func readJSONDir(dir string, fn func([]byte)) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		fn(data)
+	}
+}
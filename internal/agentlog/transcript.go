@@ -0,0 +1,39 @@
+package agentlog
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoTranscript is returned when no conversation log matches a request.
+var ErrNoTranscript = errors.New("no transcript found")
+
+// TranscriptReader is implemented by adapters that can read back a finished
+// conversation in one go, rather than tailing a live one. gt seance uses it
+// to question predecessors whose agent cannot resume sessions.
+type TranscriptReader interface {
+	// ReadTranscript returns the normalized events of one conversation in
+	// order. nativeSessionID selects the conversation when it is known;
+	// otherwise the newest conversation for workDir that was active at or
+	// after since is used.
+	ReadTranscript(nativeSessionID, workDir string, since time.Time) ([]AgentEvent, error)
+}
+
+// ReadTranscript reads a conversation with the adapter for agentType.
+func ReadTranscript(agentType, nativeSessionID, workDir string, since time.Time) ([]AgentEvent, error) {
+	reader, ok := NewAdapter(agentType).(TranscriptReader)
+	if !ok {
+		return nil, fmt.Errorf("no transcript reader for agent type %q", agentType)
+	}
+	return reader.ReadTranscript(nativeSessionID, workDir, since)
+}
+
+// AgentTypeForPreset maps a Gas Town agent preset name (config.AgentPreset)
+// to the adapter name NewAdapter expects.
+func AgentTypeForPreset(preset string) string {
+	if preset == "claude" {
+		return "claudecode"
+	}
+	return preset
+}
@@ -0,0 +1,117 @@
+package agentlog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClaudeCodeReadTranscript(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	workDir := "/tmp/gt/gastown/crew/max"
+	projectDir, err := claudeProjectDirFor(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(projectDir, "abc-123.jsonl"), strings.Join([]string{
+		`{"type":"user","message":{"role":"user","content":[{"type":"text","text":"where is the config?"}]}}`,
+		`{"type":"summary","summary":"ignored"}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"in settings/config.json"}]}}`,
+	}, "\n")+"\n")
+
+	events, err := ReadTranscript("claudecode", "abc-123", workDir, time.Time{})
+	if err != nil {
+		t.Fatalf("ReadTranscript: %v", err)
+	}
+	if len(events) != 2 || events[1].Content != "in settings/config.json" || events[1].NativeSessionID != "abc-123" {
+		t.Fatalf("events = %+v", events)
+	}
+
+	// Unknown native ID: fall back to the newest file in the project.
+	events, err = ReadTranscript("claudecode", "gastown/crew/max-4242", workDir, time.Time{})
+	if err != nil || len(events) != 2 {
+		t.Fatalf("fallback ReadTranscript = %d events, %v", len(events), err)
+	}
+
+	if _, err := ReadTranscript("claudecode", "", "/tmp/elsewhere", time.Time{}); !errors.Is(err, ErrNoTranscript) {
+		t.Errorf("missing project: err = %v, want ErrNoTranscript", err)
+	}
+}
+
+func TestOpenCodeReadTranscript(t *testing.T) {
+	data := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", data)
+	storage := filepath.Join(data, "opencode", "storage")
+	workDir := t.TempDir()
+
+	writeFile(t, filepath.Join(storage, "session", "proj1", "ses_old.json"),
+		`{"id":"ses_old","directory":"`+workDir+`","time":{"created":1000,"updated":2000}}`)
+	writeFile(t, filepath.Join(storage, "session", "proj1", "ses_new.json"),
+		`{"id":"ses_new","directory":"`+workDir+`","time":{"created":3000,"updated":4000}}`)
+	writeFile(t, filepath.Join(storage, "message", "ses_new", "msg_b.json"),
+		`{"id":"msg_b","role":"assistant","time":{"created":3200}}`)
+	writeFile(t, filepath.Join(storage, "message", "ses_new", "msg_a.json"),
+		`{"id":"msg_a","role":"user","time":{"created":3100}}`)
+	writeFile(t, filepath.Join(storage, "part", "msg_a", "prt_1.json"),
+		`{"id":"prt_1","type":"text","text":"run the tests"}`)
+	writeFile(t, filepath.Join(storage, "part", "msg_b", "prt_2.json"),
+		`{"id":"prt_2","type":"tool","tool":"bash","state":{"input":{"command":"go test"},"output":"ok"}}`)
+	writeFile(t, filepath.Join(storage, "part", "msg_b", "prt_3.json"),
+		`{"id":"prt_3","type":"text","text":"tests pass"}`)
+
+	events, err := ReadTranscript("opencode", "", workDir, time.Time{})
+	if err != nil {
+		t.Fatalf("ReadTranscript: %v", err)
+	}
+	var got []string
+	for _, ev := range events {
+		if ev.NativeSessionID != "ses_new" {
+			t.Errorf("event from session %q, want ses_new", ev.NativeSessionID)
+		}
+		got = append(got, ev.Role+"/"+ev.EventType+": "+ev.Content)
+	}
+	want := []string{
+		"user/text: run the tests",
+		`assistant/tool_use: bash: {"command":"go test"}`,
+		"assistant/tool_result: ok",
+		"assistant/text: tests pass",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("events:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// An explicit session ID wins over the newest session.
+	events, err = ReadTranscript("opencode", "ses_old", workDir, time.Time{})
+	if err != nil || len(events) != 0 {
+		t.Errorf("ses_old: %d events, %v", len(events), err)
+	}
+}
+
+func TestReadTranscriptUnsupportedAgent(t *testing.T) {
+	if _, err := ReadTranscript("kiro", "", "", time.Time{}); err == nil {
+		t.Error("expected an error for an agent without a transcript reader")
+	}
+}
+
+func TestAgentTypeForPreset(t *testing.T) {
+	if got := AgentTypeForPreset("claude"); got != "claudecode" {
+		t.Errorf("claude -> %q", got)
+	}
+	if got := AgentTypeForPreset("opencode"); got != "opencode" {
+		t.Errorf("opencode -> %q", got)
+	}
+}
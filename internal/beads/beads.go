@@ -274,7 +274,11 @@ func (b *Beads) Init(prefix string) error {
 }
 
 // run executes a bd command and returns stdout.
+// Read-only list/show commands are served in-process when possible.
 func (b *Beads) run(args ...string) (_ []byte, retErr error) {
+	if out, ok := b.queryInProcess(args); ok {
+		return out, nil
+	}
 	start := time.Now()
 	// Declare buffers before defer so the closure captures them after cmd.Run.
 	var stdout, stderr bytes.Buffer
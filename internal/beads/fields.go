@@ -397,6 +397,9 @@ type MRFields struct {
 	LastConflictSHA string // SHA of main when conflict occurred
 	ConflictTaskID  string // Link to conflict-resolution task (if any)
 
+	// FlakyRetries counts requeues after failures caused only by known-flaky tests.
+	FlakyRetries int
+
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
@@ -407,8 +410,15 @@ type MRFields struct {
 	PreVerified     bool   // Polecat ran full gates after rebasing onto target
 	PreVerifiedAt   string // ISO 8601 timestamp when verification completed
 	PreVerifiedBase string // Target branch SHA at verification time
+
+	// Outputs holds values reported by pre/post-merge actions (preview URLs,
+	// artifact paths), keyed "<action>.<key>". Stored as "output_<key>" lines.
+	Outputs map[string]string
 }
 
+// mrOutputPrefix marks merge action output lines in an MR description.
+const mrOutputPrefix = "output_"
+
 // ParseMRFields extracts structured merge-request fields from an issue's description.
 // Fields are expected as "key: value" lines, with optional prose text mixed in.
 // Returns nil if no MR fields are found.
@@ -469,6 +479,11 @@ func ParseMRFields(issue *Issue) *MRFields {
 				fields.RetryCount = n
 				hasFields = true
 			}
+		case "flaky_retries", "flaky-retries", "flakyretries":
+			if n, err := parseIntField(value); err == nil {
+				fields.FlakyRetries = n
+				hasFields = true
+			}
 		case "last_conflict_sha", "last-conflict-sha", "lastconflictsha":
 			fields.LastConflictSHA = value
 			hasFields = true
@@ -490,6 +505,14 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "pre_verified_base", "pre-verified-base", "preverifiedbase":
 			fields.PreVerifiedBase = value
 			hasFields = true
+		default:
+			if name, ok := strings.CutPrefix(strings.ToLower(key), mrOutputPrefix); ok && name != "" {
+				if fields.Outputs == nil {
+					fields.Outputs = make(map[string]string)
+				}
+				fields.Outputs[name] = value
+				hasFields = true
+			}
 		}
 	}
 
@@ -542,6 +565,9 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.RetryCount > 0 {
 		lines = append(lines, fmt.Sprintf("retry_count: %d", fields.RetryCount))
 	}
+	if fields.FlakyRetries > 0 {
+		lines = append(lines, fmt.Sprintf("flaky_retries: %d", fields.FlakyRetries))
+	}
 	if fields.LastConflictSHA != "" {
 		lines = append(lines, "last_conflict_sha: "+fields.LastConflictSHA)
 	}
@@ -563,6 +589,16 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.PreVerifiedBase != "" {
 		lines = append(lines, "pre_verified_base: "+fields.PreVerifiedBase)
 	}
+	if len(fields.Outputs) > 0 {
+		keys := make([]string, 0, len(fields.Outputs))
+		for k := range fields.Outputs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			lines = append(lines, mrOutputPrefix+k+": "+fields.Outputs[k])
+		}
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -596,6 +632,9 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"retry_count":        true,
 		"retry-count":        true,
 		"retrycount":         true,
+		"flaky_retries":      true,
+		"flaky-retries":      true,
+		"flakyretries":       true,
 		"last_conflict_sha":  true,
 		"last-conflict-sha":  true,
 		"lastconflictsha":    true,
@@ -639,7 +678,7 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 			}
 
 			key := strings.ToLower(strings.TrimSpace(trimmed[:colonIdx]))
-			if !mrKeys[key] {
+			if !mrKeys[key] && !strings.HasPrefix(key, mrOutputPrefix) {
 				otherLines = append(otherLines, line)
 			}
 			// Skip MR field lines - they'll be replaced
@@ -0,0 +1,595 @@
+package beads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	beadsdk "github.com/steveyegge/beads"
+)
+
+// In-process reads.
+//
+// The hot read paths (gt status, the mail inbox, witness patrol, refinery MR
+// listing, the dashboard) issue many bd list/show calls per run, and each one
+// is a process spawn plus a fresh Dolt connection. For databases in Dolt
+// server mode those reads are answered from a pooled beads storage client
+// instead, producing the same JSON bd would print. Anything the in-process
+// path does not understand — other commands, unsupported flags, unknown or
+// partial IDs, storage errors — falls back to the bd subprocess, which stays
+// the source of truth for writes and for error messages.
+
+// EnvInProcess disables in-process reads when set to "0".
+const EnvInProcess = "GT_BEADS_INPROCESS"
+
+const (
+	// inProcessTimeout bounds a single in-process query when the caller has
+	// no deadline of its own.
+	inProcessTimeout = 30 * time.Second
+
+	// storeRetryAfter is how long a beads directory whose store failed to
+	// open is left to the subprocess before another open is attempted.
+	storeRetryAfter = 30 * time.Second
+
+	// bdDefaultListLimit and bdAgentListLimit mirror bd list's default
+	// --limit outside and inside agent mode.
+	bdDefaultListLimit = 50
+	bdAgentListLimit   = 20
+)
+
+// errNotServed means a query must go to the bd subprocess.
+var errNotServed = errors.New("not served in-process")
+
+// openStore opens a storage client for a beads directory. Tests replace it.
+var openStore = func(ctx context.Context, beadsDir string) (beadsdk.Storage, error) {
+	return beadsdk.OpenFromConfig(ctx, beadsDir)
+}
+
+// storePool holds one storage client per beads directory for the life of
+// the process. The clients are safe for concurrent use.
+var storePool = struct {
+	sync.Mutex
+	stores map[string]beadsdk.Storage
+	failed map[string]time.Time
+}{
+	stores: make(map[string]beadsdk.Storage),
+	failed: make(map[string]time.Time),
+}
+
+// pooledStore returns the pooled client for beadsDir, opening it on first use.
+func pooledStore(ctx context.Context, beadsDir string) (beadsdk.Storage, error) {
+	storePool.Lock()
+	defer storePool.Unlock()
+	if store, ok := storePool.stores[beadsDir]; ok {
+		return store, nil
+	}
+	if at, ok := storePool.failed[beadsDir]; ok && time.Since(at) < storeRetryAfter {
+		return nil, errNotServed
+	}
+	store, err := openStore(ctx, beadsDir)
+	if err != nil {
+		storePool.failed[beadsDir] = time.Now()
+		return nil, err
+	}
+	delete(storePool.failed, beadsDir)
+	storePool.stores[beadsDir] = store
+	return store, nil
+}
+
+// dropStore closes and forgets the client for beadsDir after a query error,
+// so the next read reopens it (or falls back while it cannot be reopened).
+func dropStore(beadsDir string, store beadsdk.Storage) {
+	storePool.Lock()
+	defer storePool.Unlock()
+	if storePool.stores[beadsDir] == store {
+		delete(storePool.stores, beadsDir)
+		storePool.failed[beadsDir] = time.Now()
+		_ = store.Close()
+	}
+}
+
+// CloseStores closes every pooled storage client. Long-running processes
+// call it on shutdown; later reads reopen clients as needed.
+func CloseStores() {
+	storePool.Lock()
+	defer storePool.Unlock()
+	for dir, store := range storePool.stores {
+		_ = store.Close()
+		delete(storePool.stores, dir)
+	}
+	storePool.failed = make(map[string]time.Time)
+}
+
+// inProcessEnabled reports whether reads for beadsDir may be served
+// in-process. Only server-mode databases qualify: an embedded database is
+// locked by whichever process opens it, and bd subprocesses need it too.
+func inProcessEnabled(beadsDir string) bool {
+	if beadsDir == "" || os.Getenv(EnvInProcess) == "0" {
+		return false
+	}
+	// bd subprocesses get BEADS_DOLT_PORT translated from GT_DOLT_PORT (see
+	// translateDoltPort); the storage client only reads BEADS_DOLT_PORT, so
+	// leave mismatched environments to the subprocess.
+	if gtPort := os.Getenv("GT_DOLT_PORT"); gtPort != "" && os.Getenv("BEADS_DOLT_PORT") != gtPort {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(beadsDir, "metadata.json")) //nolint:gosec // G304: path is constructed from the beads dir
+	if err != nil {
+		return false
+	}
+	var metadata struct {
+		DoltMode string `json:"dolt_mode"`
+	}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return false
+	}
+	return metadata.DoltMode == "server"
+}
+
+// QueryJSON answers a read-only bd command from the pooled storage client
+// for beadsDir, returning the JSON bd would have printed. Supported are
+// "show <id>... --json", "list --json" with the filters Gas Town uses, and
+// "mol wisp list --json".
+// ok is false when the query was not served and the caller should run bd.
+func QueryJSON(ctx context.Context, beadsDir string, args []string) (out []byte, ok bool) {
+	req, supported := parseReadArgs(args)
+	if !supported || !inProcessEnabled(beadsDir) {
+		return nil, false
+	}
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, inProcessTimeout)
+		defer cancel()
+	}
+
+	store, err := pooledStore(ctx, beadsDir)
+	if err != nil {
+		return nil, false
+	}
+	var result interface{}
+	switch req.kind {
+	case readShow:
+		result, err = showIssues(ctx, store, req.ids)
+	case readList:
+		result, err = listIssues(ctx, store, req)
+	case readWisps:
+		result, err = listWisps(ctx, store, req.all)
+	}
+	if err != nil {
+		if !errors.Is(err, errNotServed) {
+			dropStore(beadsDir, store)
+		}
+		return nil, false
+	}
+	out, err = json.Marshal(result)
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+// readKind is the bd command a readRequest stands for.
+type readKind int
+
+const (
+	readShow  readKind = iota // bd show
+	readList                  // bd list
+	readWisps                 // bd mol wisp list
+)
+
+// readRequest is a parsed read-only bd invocation.
+type readRequest struct {
+	kind readKind
+	ids  []string
+
+	status       string
+	all          bool
+	labels       []string
+	issueType    string
+	assignee     string
+	noAssignee   bool
+	parent       string
+	priority     *int
+	descContains string
+	includeInfra bool
+	limit        int
+	limitSet     bool
+}
+
+// bdTypeAliases mirrors bd list's --type aliases.
+var bdTypeAliases = map[string]string{
+	"mr":   "merge-request",
+	"feat": "feature",
+	"mol":  "molecule",
+	"dec":  "decision",
+	"adr":  "decision",
+}
+
+// parseReadArgs parses the subset of bd show/list arguments served
+// in-process. Any other command or flag makes the whole call unsupported.
+func parseReadArgs(args []string) (*readRequest, bool) {
+	req := &readRequest{}
+	var rest []string
+	switch {
+	case len(args) > 0 && args[0] == "show":
+		req.kind, rest = readShow, args[1:]
+	case len(args) > 0 && args[0] == "list":
+		req.kind, rest = readList, args[1:]
+	case len(args) > 2 && args[0] == "mol" && args[1] == "wisp" && args[2] == "list":
+		req.kind, rest = readWisps, args[3:]
+	default:
+		return nil, false
+	}
+
+	jsonOut := false
+	for i := 0; i < len(rest); i++ {
+		arg := rest[i]
+		if !strings.HasPrefix(arg, "-") {
+			if req.kind != readShow {
+				return nil, false
+			}
+			req.ids = append(req.ids, arg)
+			continue
+		}
+		name, value, hasValue := strings.Cut(arg, "=")
+		flagValue := func() (string, bool) {
+			if hasValue {
+				return value, true
+			}
+			if i+1 < len(rest) {
+				i++
+				return rest[i], true
+			}
+			return "", false
+		}
+
+		switch name {
+		case "--json":
+			jsonOut = true
+			continue
+		case "--all", "--include-infra", "--no-assignee":
+			if hasValue || req.kind == readShow || (req.kind == readWisps && name != "--all") {
+				return nil, false
+			}
+			switch name {
+			case "--all":
+				req.all = true
+			case "--include-infra":
+				req.includeInfra = true
+			case "--no-assignee":
+				req.noAssignee = true
+			}
+			continue
+		}
+		if req.kind != readList {
+			return nil, false
+		}
+
+		v, ok := flagValue()
+		if !ok {
+			return nil, false
+		}
+		switch name {
+		case "--status", "-s":
+			req.status = v
+		case "--label", "-l":
+			req.labels = append(req.labels, strings.Split(v, ",")...)
+		case "--type", "-t":
+			if alias, ok := bdTypeAliases[v]; ok {
+				v = alias
+			}
+			req.issueType = v
+		case "--assignee", "-a":
+			req.assignee = v
+		case "--parent":
+			req.parent = v
+		case "--desc-contains":
+			req.descContains = v
+		case "--priority", "-p":
+			p, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(v), "P"))
+			if err != nil || p < 0 || p > 4 {
+				return nil, false
+			}
+			req.priority = &p
+		case "--limit", "-n":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return nil, false
+			}
+			req.limit, req.limitSet = n, true
+		default:
+			return nil, false
+		}
+	}
+	if !jsonOut || (req.kind == readShow && len(req.ids) == 0) {
+		return nil, false
+	}
+	return req, true
+}
+
+// Optional batch methods of the Dolt store, not part of beadsdk.Storage.
+type (
+	labelBatcher interface {
+		GetLabelsForIssues(ctx context.Context, issueIDs []string) (map[string][]string, error)
+	}
+	dependencyBatcher interface {
+		GetDependencyRecordsForIssues(ctx context.Context, issueIDs []string) (map[string][]*beadsdk.Dependency, error)
+		GetDependencyCounts(ctx context.Context, issueIDs []string) (map[string]*beadsdk.DependencyCounts, error)
+	}
+	commentCounter interface {
+		GetCommentCounts(ctx context.Context, issueIDs []string) (map[string]int, error)
+	}
+	infraTyper interface {
+		GetInfraTypes(ctx context.Context) map[string]bool
+	}
+)
+
+// issueDetails mirrors the element type of bd show --json.
+type issueDetails struct {
+	beadsdk.Issue
+	Labels       []string                               `json:"labels,omitempty"`
+	Dependencies []*beadsdk.IssueWithDependencyMetadata `json:"dependencies,omitempty"`
+	Dependents   []*beadsdk.IssueWithDependencyMetadata `json:"dependents,omitempty"`
+	Parent       *string                                `json:"parent,omitempty"`
+}
+
+// showIssues fetches all ids in one batched query and renders them like
+// bd show --json, in request order. Comments are not loaded. Any ID that is
+// not an exact match (a partial ID, or one routed to another database) hands
+// the whole call to bd, which resolves it and reports misses.
+func showIssues(ctx context.Context, store beadsdk.Storage, ids []string) ([]*issueDetails, error) {
+	issues, err := store.GetIssuesByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*beadsdk.Issue, len(issues))
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+	}
+	for _, id := range ids {
+		if byID[id] == nil {
+			return nil, errNotServed
+		}
+	}
+
+	labels, err := labelsFor(ctx, store, ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*issueDetails, 0, len(ids))
+	for _, id := range ids {
+		d := &issueDetails{Issue: *byID[id], Labels: labels[id]}
+		if d.Dependencies, err = store.GetDependenciesWithMetadata(ctx, id); err != nil {
+			return nil, err
+		}
+		if d.Dependents, err = store.GetDependentsWithMetadata(ctx, id); err != nil {
+			return nil, err
+		}
+		for _, dep := range d.Dependencies {
+			if dep.DependencyType == beadsdk.DepParentChild {
+				parent := dep.ID
+				d.Parent = &parent
+				break
+			}
+		}
+		result = append(result, d)
+	}
+	return result, nil
+}
+
+// listIssues runs a bd list query with bd's default filters: closed issues,
+// templates, gates and infrastructure types are hidden unless asked for.
+func listIssues(ctx context.Context, store beadsdk.Storage, req *readRequest) ([]*beadsdk.IssueWithCounts, error) {
+	filter := beadsdk.IssueFilter{Limit: listLimit(req)}
+	switch {
+	case req.status != "" && req.status != "all":
+		s := beadsdk.Status(req.status)
+		filter.Status = &s
+	case req.status == "" && !req.all:
+		filter.ExcludeStatus = []beadsdk.Status{beadsdk.StatusClosed}
+	}
+	filter.Labels = normalizeLabels(req.labels)
+	if req.issueType != "" {
+		t := beadsdk.IssueType(req.issueType)
+		filter.IssueType = &t
+	}
+	if req.assignee != "" {
+		filter.Assignee = &req.assignee
+	}
+	filter.NoAssignee = req.noAssignee
+	if req.parent != "" {
+		filter.ParentID = &req.parent
+	}
+	filter.Priority = req.priority
+	filter.DescriptionContains = req.descContains
+
+	isTemplate := false
+	filter.IsTemplate = &isTemplate
+	if req.issueType != "gate" {
+		filter.ExcludeTypes = append(filter.ExcludeTypes, "gate")
+	}
+	infra := infraTypes(ctx, store)
+	if infra[req.issueType] {
+		ephemeral := true
+		filter.Ephemeral = &ephemeral
+	} else if !req.includeInfra {
+		for t := range infra {
+			filter.ExcludeTypes = append(filter.ExcludeTypes, beadsdk.IssueType(t))
+		}
+	}
+
+	issues, err := store.SearchIssues(ctx, "", filter)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	labels, err := labelsFor(ctx, store, ids)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		deps     map[string][]*beadsdk.Dependency
+		counts   map[string]*beadsdk.DependencyCounts
+		comments map[string]int
+	)
+	if batcher, ok := store.(dependencyBatcher); ok && len(ids) > 0 {
+		deps, _ = batcher.GetDependencyRecordsForIssues(ctx, ids)
+		counts, _ = batcher.GetDependencyCounts(ctx, ids)
+	}
+	if counter, ok := store.(commentCounter); ok && len(ids) > 0 {
+		comments, _ = counter.GetCommentCounts(ctx, ids)
+	}
+
+	result := make([]*beadsdk.IssueWithCounts, len(issues))
+	for i, issue := range issues {
+		issue.Labels = labels[issue.ID]
+		issue.Dependencies = deps[issue.ID]
+		withCounts := &beadsdk.IssueWithCounts{Issue: issue, CommentCount: comments[issue.ID]}
+		if c := counts[issue.ID]; c != nil {
+			withCounts.DependencyCount = c.DependencyCount
+			withCounts.DependentCount = c.DependentCount
+		}
+		for _, dep := range deps[issue.ID] {
+			if dep.Type == beadsdk.DepParentChild {
+				parent := dep.DependsOnID
+				withCounts.Parent = &parent
+				break
+			}
+		}
+		result[i] = withCounts
+	}
+	return result, nil
+}
+
+// wispListItem and wispList mirror bd mol wisp list --json.
+type wispListItem struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	Priority  int       `json:"priority"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Old       bool      `json:"old,omitempty"`
+}
+
+type wispList struct {
+	Wisps    []wispListItem `json:"wisps"`
+	Count    int            `json:"count"`
+	OldCount int            `json:"old_count,omitempty"`
+}
+
+const (
+	// bdWispListLimit and bdWispOldAfter mirror bd mol wisp list.
+	bdWispListLimit = 5000
+	bdWispOldAfter  = 24 * time.Hour
+)
+
+// listWisps lists ephemeral issues like bd mol wisp list, most recently
+// updated first. Closed wisps are hidden unless all is set.
+func listWisps(ctx context.Context, store beadsdk.Storage, all bool) (*wispList, error) {
+	ephemeral := true
+	issues, err := store.SearchIssues(ctx, "", beadsdk.IssueFilter{Ephemeral: &ephemeral, Limit: bdWispListLimit})
+	if err != nil {
+		return nil, err
+	}
+	result := &wispList{Wisps: make([]wispListItem, 0, len(issues))}
+	now := time.Now()
+	for _, issue := range issues {
+		if !all && issue.Status == beadsdk.StatusClosed {
+			continue
+		}
+		item := wispListItem{
+			ID:        issue.ID,
+			Title:     issue.Title,
+			Status:    string(issue.Status),
+			Priority:  issue.Priority,
+			CreatedAt: issue.CreatedAt,
+			UpdatedAt: issue.UpdatedAt,
+			Old:       now.Sub(issue.UpdatedAt) > bdWispOldAfter,
+		}
+		if item.Old {
+			result.OldCount++
+		}
+		result.Wisps = append(result.Wisps, item)
+	}
+	sort.SliceStable(result.Wisps, func(i, j int) bool {
+		return result.Wisps[i].UpdatedAt.After(result.Wisps[j].UpdatedAt)
+	})
+	result.Count = len(result.Wisps)
+	return result, nil
+}
+
+// listLimit resolves the effective --limit the way bd list does.
+func listLimit(req *readRequest) int {
+	switch {
+	case req.limitSet:
+		return req.limit
+	case req.all:
+		return 0
+	case os.Getenv("BD_AGENT_MODE") == "1" || os.Getenv("CLAUDE_CODE") != "":
+		return bdAgentListLimit
+	}
+	return bdDefaultListLimit
+}
+
+// labelsFor loads labels for ids, in one query when the store supports it.
+func labelsFor(ctx context.Context, store beadsdk.Storage, ids []string) (map[string][]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if batcher, ok := store.(labelBatcher); ok {
+		return batcher.GetLabelsForIssues(ctx, ids)
+	}
+	labels := make(map[string][]string, len(ids))
+	for _, id := range ids {
+		l, err := store.GetLabels(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		labels[id] = l
+	}
+	return labels, nil
+}
+
+// infraTypes returns the database's infrastructure issue types.
+func infraTypes(ctx context.Context, store beadsdk.Storage) map[string]bool {
+	if typer, ok := store.(infraTyper); ok {
+		return typer.GetInfraTypes(ctx)
+	}
+	return map[string]bool{"agent": true, "rig": true, "role": true, "message": true}
+}
+
+// normalizeLabels trims, dedupes and drops empty labels, as bd does.
+func normalizeLabels(labels []string) []string {
+	seen := make(map[string]bool, len(labels))
+	var out []string
+	for _, l := range labels {
+		l = strings.TrimSpace(l)
+		if l == "" || seen[l] {
+			continue
+		}
+		seen[l] = true
+		out = append(out, l)
+	}
+	return out
+}
+
+// queryInProcess serves a read-only command for this wrapper's database
+// from the pooled storage client. Isolated wrappers (tests) always use the
+// subprocess so their --db pinning applies.
+func (b *Beads) queryInProcess(args []string) ([]byte, bool) {
+	if b.isolated {
+		return nil, false
+	}
+	return QueryJSON(context.Background(), b.getResolvedBeadsDir(), args)
+}
@@ -0,0 +1,590 @@
+package beads
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	beadsdk "github.com/steveyegge/beads"
+
+	"github.com/steveyegge/gastown/internal/testutil"
+)
+
+// fakeStore is an in-memory beadsdk.Storage covering the read methods the
+// in-process path uses. Unimplemented methods panic via the nil embedding.
+type fakeStore struct {
+	beadsdk.Storage
+	issues  []*beadsdk.Issue
+	labels  map[string][]string
+	deps    map[string][]*beadsdk.IssueWithDependencyMetadata
+	filters []beadsdk.IssueFilter
+	closed  bool
+}
+
+func (s *fakeStore) GetIssuesByIDs(_ context.Context, ids []string) ([]*beadsdk.Issue, error) {
+	var out []*beadsdk.Issue
+	for _, id := range ids {
+		for _, issue := range s.issues {
+			if issue.ID == id {
+				c := *issue
+				out = append(out, &c)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (s *fakeStore) SearchIssues(_ context.Context, _ string, f beadsdk.IssueFilter) ([]*beadsdk.Issue, error) {
+	s.filters = append(s.filters, f)
+	var out []*beadsdk.Issue
+	for _, issue := range s.issues {
+		if f.Status != nil && issue.Status != *f.Status {
+			continue
+		}
+		if f.Ephemeral != nil && issue.Ephemeral != *f.Ephemeral {
+			continue
+		}
+		if f.Assignee != nil && issue.Assignee != *f.Assignee {
+			continue
+		}
+		if !hasAllLabels(s.labels[issue.ID], f.Labels) {
+			continue
+		}
+		c := *issue
+		out = append(out, &c)
+	}
+	return out, nil
+}
+
+func hasAllLabels(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			found = found || h == w
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *fakeStore) GetLabels(_ context.Context, id string) ([]string, error) {
+	return s.labels[id], nil
+}
+
+func (s *fakeStore) GetDependenciesWithMetadata(_ context.Context, id string) ([]*beadsdk.IssueWithDependencyMetadata, error) {
+	return s.deps[id], nil
+}
+
+func (s *fakeStore) GetDependentsWithMetadata(context.Context, string) ([]*beadsdk.IssueWithDependencyMetadata, error) {
+	return nil, nil
+}
+
+func (s *fakeStore) Close() error {
+	s.closed = true
+	return nil
+}
+
+func newFakeStore() *fakeStore {
+	now := time.Now()
+	return &fakeStore{
+		issues: []*beadsdk.Issue{
+			{ID: "gt-agent", Title: "witness", Status: beadsdk.StatusOpen, IssueType: "agent", HookBead: "gt-work", UpdatedAt: now},
+			{ID: "gt-work", Title: "Fix the thing", Status: beadsdk.StatusInProgress, Assignee: "gastown/polecats/nux", UpdatedAt: now},
+			{ID: "gt-msg", Title: "Hello", Status: beadsdk.StatusOpen, Assignee: "mayor/", UpdatedAt: now},
+			{ID: "gt-wisp-1", Title: "patrol", Status: beadsdk.StatusOpen, Ephemeral: true, UpdatedAt: now.Add(-48 * time.Hour)},
+		},
+		labels: map[string][]string{
+			"gt-agent": {"gt:agent"},
+			"gt-msg":   {"gt:message", "from:gastown/witness"},
+		},
+		deps: map[string][]*beadsdk.IssueWithDependencyMetadata{
+			"gt-work": {{Issue: beadsdk.Issue{ID: "gt-epic", Title: "Epic"}, DependencyType: beadsdk.DepParentChild}},
+		},
+	}
+}
+
+// useFakeStore points the in-process path at store for a server-mode beads
+// directory under a new town, returning the town's work dir.
+func useFakeStore(tb testing.TB, store beadsdk.Storage) string {
+	tb.Helper()
+	workDir := tb.TempDir()
+	beadsDir := filepath.Join(workDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, "metadata.json"), []byte(`{"dolt_mode":"server"}`), 0644); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Setenv("GT_DOLT_PORT", "")
+	tb.Setenv(EnvInProcess, "")
+
+	original := openStore
+	openStore = func(context.Context, string) (beadsdk.Storage, error) { return store, nil }
+	tb.Cleanup(func() {
+		openStore = original
+		CloseStores()
+	})
+	return workDir
+}
+
+func TestParseReadArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		ok   bool
+	}{
+		{[]string{"show", "gt-1", "--json"}, true},
+		{[]string{"show", "--json", "gt-1", "gt-2"}, true},
+		{[]string{"show", "gt-1"}, false},
+		{[]string{"show", "--json"}, false},
+		{[]string{"show", "gt-1", "--json", "--children"}, false},
+		{[]string{"list", "--json", "--label=gt:message", "--assignee", "mayor/", "--limit", "0"}, true},
+		{[]string{"list", "--type=merge-request", "--status=open", "--json", "--desc-contains", "branch: x"}, true},
+		{[]string{"list", "--json", "--priority=P1", "--no-assignee", "--include-infra"}, true},
+		{[]string{"list", "--json", "--sort", "priority"}, false},
+		{[]string{"list", "--json", "--limit"}, false},
+		{[]string{"list", "stray", "--json"}, false},
+		{[]string{"mol", "wisp", "list", "--json"}, true},
+		{[]string{"mol", "wisp", "list", "--json", "--label=x"}, false},
+		{[]string{"update", "gt-1", "--status=closed"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if _, ok := parseReadArgs(tt.args); ok != tt.ok {
+			t.Errorf("parseReadArgs(%q) ok = %v, want %v", tt.args, ok, tt.ok)
+		}
+	}
+
+	req, _ := parseReadArgs([]string{"list", "--json", "--label", "cleanup,polecat:nux", "--type=mr", "-n", "5"})
+	if strings.Join(req.labels, "|") != "cleanup|polecat:nux" || req.issueType != "merge-request" || req.limit != 5 || !req.limitSet {
+		t.Errorf("parsed list request = %+v", req)
+	}
+}
+
+func TestQueryJSONShow(t *testing.T) {
+	store := newFakeStore()
+	workDir := useFakeStore(t, store)
+	beadsDir := filepath.Join(workDir, ".beads")
+
+	out, ok := QueryJSON(context.Background(), beadsDir, []string{"show", "gt-work", "gt-agent", "--json"})
+	if !ok {
+		t.Fatal("show was not served in-process")
+	}
+	var issues []*Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out)
+	}
+	if len(issues) != 2 || issues[0].ID != "gt-work" || issues[1].ID != "gt-agent" {
+		t.Fatalf("issues = %+v, want gt-work then gt-agent", issues)
+	}
+	if issues[0].Parent != "gt-epic" || issues[0].Status != "in_progress" {
+		t.Errorf("gt-work = %+v, want parent gt-epic and status in_progress", issues[0])
+	}
+	if issues[1].HookBead != "gt-work" || !HasLabel(issues[1], "gt:agent") {
+		t.Errorf("gt-agent = %+v, want hook_bead and gt:agent label", issues[1])
+	}
+
+	// An unknown (or partial) ID leaves the whole call to bd.
+	if _, ok := QueryJSON(context.Background(), beadsDir, []string{"show", "gt-work", "gt-nope", "--json"}); ok {
+		t.Error("show with a missing ID should fall back to bd")
+	}
+	if store.closed {
+		t.Error("a miss should not drop the pooled store")
+	}
+}
+
+func TestQueryJSONList(t *testing.T) {
+	store := newFakeStore()
+	workDir := useFakeStore(t, store)
+	beadsDir := filepath.Join(workDir, ".beads")
+
+	out, ok := QueryJSON(context.Background(), beadsDir, []string{"list", "--label", "gt:message", "--assignee", "mayor/", "--json", "--limit", "0"})
+	if !ok {
+		t.Fatal("list was not served in-process")
+	}
+	var issues []*Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out)
+	}
+	if len(issues) != 1 || issues[0].ID != "gt-msg" || !HasLabel(issues[0], "from:gastown/witness") {
+		t.Fatalf("issues = %+v, want gt-msg with labels", issues)
+	}
+
+	f := store.filters[0]
+	if f.Limit != 0 || len(f.ExcludeStatus) != 1 || f.ExcludeStatus[0] != beadsdk.StatusClosed {
+		t.Errorf("filter limit/status = %d/%v, want unlimited excluding closed", f.Limit, f.ExcludeStatus)
+	}
+	if f.IsTemplate == nil || *f.IsTemplate {
+		t.Error("templates should be excluded by default")
+	}
+	excluded := fmt.Sprint(f.ExcludeTypes)
+	for _, typ := range []string{"gate", "agent", "message"} {
+		if !strings.Contains(excluded, typ) {
+			t.Errorf("ExcludeTypes = %s, want %s excluded", excluded, typ)
+		}
+	}
+
+	t.Setenv("BD_AGENT_MODE", "")
+	t.Setenv("CLAUDE_CODE", "")
+	if _, ok := QueryJSON(context.Background(), beadsDir, []string{"list", "--json", "--status=all", "--type=agent"}); !ok {
+		t.Fatal("typed list was not served in-process")
+	}
+	f = store.filters[1]
+	if f.Limit != bdDefaultListLimit || f.Status != nil || f.ExcludeStatus != nil {
+		t.Errorf("filter = %+v, want default limit and no status filter", f)
+	}
+	if f.Ephemeral == nil || !*f.Ephemeral || strings.Contains(fmt.Sprint(f.ExcludeTypes), "agent") {
+		t.Errorf("listing an infra type should search wisps without excluding it: %+v", f)
+	}
+}
+
+func TestQueryJSONWispList(t *testing.T) {
+	workDir := useFakeStore(t, newFakeStore())
+
+	out, ok := QueryJSON(context.Background(), filepath.Join(workDir, ".beads"), []string{"mol", "wisp", "list", "--json"})
+	if !ok {
+		t.Fatal("wisp list was not served in-process")
+	}
+	var got wispList
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out)
+	}
+	if got.Count != 1 || got.Wisps[0].ID != "gt-wisp-1" || !got.Wisps[0].Old || got.OldCount != 1 {
+		t.Errorf("wisp list = %+v", got)
+	}
+}
+
+func TestQueryJSONFallback(t *testing.T) {
+	workDir := useFakeStore(t, newFakeStore())
+	beadsDir := filepath.Join(workDir, ".beads")
+	show := []string{"show", "gt-work", "--json"}
+
+	if _, ok := QueryJSON(context.Background(), beadsDir, []string{"close", "gt-work"}); ok {
+		t.Error("writes must go to bd")
+	}
+	if _, ok := QueryJSON(context.Background(), t.TempDir(), show); ok {
+		t.Error("a beads dir without server-mode metadata must go to bd")
+	}
+
+	t.Setenv(EnvInProcess, "0")
+	if _, ok := QueryJSON(context.Background(), beadsDir, show); ok {
+		t.Errorf("%s=0 should disable in-process reads", EnvInProcess)
+	}
+	t.Setenv(EnvInProcess, "")
+
+	t.Setenv("GT_DOLT_PORT", "13307")
+	t.Setenv("BEADS_DOLT_PORT", "")
+	if _, ok := QueryJSON(context.Background(), beadsDir, show); ok {
+		t.Error("an untranslated GT_DOLT_PORT should leave reads to bd")
+	}
+}
+
+func TestQueryJSONOpenFailureBacksOff(t *testing.T) {
+	workDir := useFakeStore(t, nil)
+	opens := 0
+	openStore = func(context.Context, string) (beadsdk.Storage, error) {
+		opens++
+		return nil, fmt.Errorf("connection refused")
+	}
+	beadsDir := filepath.Join(workDir, ".beads")
+	for i := 0; i < 3; i++ {
+		if _, ok := QueryJSON(context.Background(), beadsDir, []string{"show", "gt-work", "--json"}); ok {
+			t.Fatal("query served without a store")
+		}
+	}
+	if opens != 1 {
+		t.Errorf("store opened %d times, want 1 within the retry window", opens)
+	}
+}
+
+func TestBeadsShowInProcess(t *testing.T) {
+	workDir := useFakeStore(t, newFakeStore())
+	t.Setenv("PATH", t.TempDir()) // no bd: any subprocess call fails
+
+	issue, err := New(workDir).Show("gt-work")
+	if err != nil {
+		t.Fatalf("Show: %v", err)
+	}
+	if issue.Title != "Fix the thing" {
+		t.Errorf("Show = %+v", issue)
+	}
+
+	// Isolated wrappers pin the database with --db and always spawn bd.
+	if _, err := NewIsolated(workDir).Show("gt-work"); err == nil {
+		t.Error("isolated Show should not be served in-process")
+	}
+}
+
+// realBeadsDB initializes a server-mode beads database in a new work dir on
+// the shared Dolt test container and seeds it with an agent bead, its hooked
+// work and a message, mirroring newFakeStore. IDs use the returned prefix.
+// Skips when bd or Docker is unavailable.
+func realBeadsDB(tb testing.TB) (workDir, prefix string) {
+	tb.Helper()
+	if _, err := exec.LookPath("bd"); err != nil {
+		tb.Skip("bd CLI not installed, skipping")
+	}
+	testutil.RequireDoltContainer(tb)
+	port, _ := strconv.Atoi(testutil.DoltContainerPort())
+
+	var buf [4]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		tb.Fatal(err)
+	}
+	prefix = "ip" + hex.EncodeToString(buf[:])
+	workDir = tb.TempDir()
+	seed := NewIsolatedWithPort(workDir, port)
+	if err := seed.Init(prefix); err != nil {
+		tb.Fatalf("bd init: %v", err)
+	}
+	tb.Cleanup(func() {
+		db, err := sql.Open("mysql", "root:@tcp("+testutil.DoltContainerAddr()+")/")
+		if err != nil {
+			return
+		}
+		defer db.Close()
+		_, _ = db.Exec("DROP DATABASE IF EXISTS `beads_" + prefix + "`")
+		_, _ = db.Exec("CALL dolt_purge_dropped_databases()")
+	})
+
+	beadsDir := filepath.Join(workDir, ".beads")
+	tb.Setenv(EnvInProcess, "")
+	if !inProcessEnabled(beadsDir) {
+		tb.Skip("bd init did not create a server-mode database")
+	}
+	_ = EnsureCustomTypes(beadsDir)
+	work, agent, msg := prefix+"-work", prefix+"-agent", prefix+"-msg"
+	for _, args := range [][]string{
+		{"create", "--json", "--id=" + work, "--title=Fix the thing", "--type=task", "--assignee=gastown/polecats/nux"},
+		{"update", work, "--status=in_progress"},
+		{"create", "--json", "--id=" + agent, "--title=witness", "--type=agent", "--labels=gt:agent",
+			"--description=" + FormatAgentDescription("witness", &AgentFields{HookBead: work})},
+		{"create", "--json", "--id=" + msg, "--title=Hello", "--type=task", "--labels=gt:message,from:gastown/witness", "--assignee=mayor/"},
+	} {
+		if _, err := seed.Run(args...); err != nil {
+			tb.Fatalf("bd %s: %v", strings.Join(args, " "), err)
+		}
+	}
+	tb.Cleanup(CloseStores)
+	return workDir, prefix
+}
+
+func TestQueryJSONMatchesBd(t *testing.T) {
+	workDir, prefix := realBeadsDB(t)
+	beadsDir := filepath.Join(workDir, ".beads")
+	client := New(workDir)
+
+	for _, args := range [][]string{
+		{"show", prefix + "-work", "--json"},
+		{"show", prefix + "-agent", prefix + "-msg", "--json"},
+		{"list", "--json"},
+		{"list", "--json", "--limit", "0"},
+		{"list", "--json", "--label=gt:message", "--assignee", "mayor/", "--limit", "0"},
+		{"list", "--json", "--label=gt:agent", "--include-infra"},
+		{"list", "--json", "--status=in_progress", "--limit=0"},
+		{"list", "--json", "--no-assignee", "--include-infra"},
+	} {
+		got, ok := QueryJSON(context.Background(), beadsDir, args)
+		if !ok {
+			t.Errorf("%q was not served in-process", args)
+			continue
+		}
+		t.Setenv(EnvInProcess, "0")
+		want, err := client.Run(args...)
+		t.Setenv(EnvInProcess, "")
+		if err != nil {
+			t.Fatalf("bd %s: %v", strings.Join(args, " "), err)
+		}
+
+		var gotJSON, wantJSON interface{}
+		if err := json.Unmarshal(got, &gotJSON); err != nil {
+			t.Fatalf("in-process %q: %v\n%s", args, err, got)
+		}
+		if err := json.Unmarshal(want, &wantJSON); err != nil {
+			t.Fatalf("bd %q: %v\n%s", args, err, want)
+		}
+		if !reflect.DeepEqual(gotJSON, wantJSON) {
+			t.Errorf("%q differs from bd --json:\nin-process: %s\nbd:         %s", args, got, want)
+		}
+	}
+}
+
+func TestUnsupportedFlagsRunBd(t *testing.T) {
+	workDir := useFakeStore(t, newFakeStore())
+	procs := stubBd(t)
+	client := New(workDir)
+
+	for _, tt := range []struct {
+		args   []string
+		served bool
+	}{
+		{[]string{"show", "gt-work", "--json"}, true},
+		{[]string{"list", "--json", "--label=gt:message", "--limit=0"}, true},
+		{[]string{"show", "gt-work", "--json", "--children"}, false},
+		{[]string{"show", "gt-work", "--json", "--all"}, false},
+		{[]string{"show", "gt-work", "--json", "--status=open"}, false},
+		{[]string{"list", "--json", "--sort", "priority"}, false},
+		{[]string{"list", "--json", "--reverse"}, false},
+		{[]string{"list", "--json", "--all=true"}, false},
+		{[]string{"list", "--json", "--no-assignee=yes"}, false},
+		{[]string{"list", "--json", "--priority=P7"}, false},
+		{[]string{"list", "--json", "--priority=high"}, false},
+		{[]string{"list", "--json", "--limit=-1"}, false},
+		{[]string{"list", "--json", "--label"}, false},
+		{[]string{"list", "--json", "-x", "y"}, false},
+		{[]string{"list", "--status=open"}, false},
+		{[]string{"mol", "wisp", "list", "--json", "--include-infra"}, false},
+		{[]string{"mol", "wisp", "list", "--json", "--status=open"}, false},
+	} {
+		before := procs()
+		if _, err := client.Run(tt.args...); err != nil {
+			t.Errorf("Run(%q): %v", tt.args, err)
+			continue
+		}
+		spawned := procs() - before
+		if served := spawned == 0; served != tt.served {
+			t.Errorf("Run(%q) spawned bd %d times, want served in-process = %v", tt.args, spawned, tt.served)
+		}
+	}
+}
+
+// Benchmarks for the hot read paths against a real server-mode beads
+// database. Each runs the bd reads a path issues per cycle, once in-process
+// and once through bd, and reports the bd processes spawned per cycle.
+// They skip without bd and Docker.
+
+// hotPath is one cycle of a hot read path against a Beads wrapper whose
+// database was seeded by realBeadsDB with prefix.
+type hotPath func(b *Beads, prefix string) error
+
+// benchStatus mirrors gt status for one rig: agent beads from the issues and
+// wisps tables, then their hooked work in one batch.
+func benchStatus(b *Beads, _ string) error {
+	agents, err := b.ListAgentBeads()
+	if err != nil {
+		return err
+	}
+	var hooks []string
+	for _, a := range agents {
+		if fields := ParseAgentFields(a.Description); fields != nil && fields.HookBead != "" {
+			hooks = append(hooks, fields.HookBead)
+		}
+	}
+	_, err = b.ShowMultiple(hooks)
+	return err
+}
+
+// benchMailInbox mirrors gt mail inbox: the assignee and CC queries.
+func benchMailInbox(b *Beads, _ string) error {
+	for _, args := range [][]string{
+		{"list", "--label", "gt:message", "--assignee", "mayor/", "--json", "--limit", "0"},
+		{"list", "--label", "gt:message", "--label", "cc:mayor/", "--json", "--limit", "0"},
+	} {
+		if _, err := b.Run(args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// benchPatrol mirrors a witness patrol over four polecats: each agent bead,
+// then the in-progress and hooked scans for orphaned work.
+func benchPatrol(b *Beads, prefix string) error {
+	for i := 0; i < 4; i++ {
+		if _, err := b.Run("show", prefix+"-agent", "--json"); err != nil {
+			return err
+		}
+	}
+	for _, status := range []string{"in_progress", "hooked"} {
+		if _, err := b.Run("list", "--status="+status, "--json", "--limit=0"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func BenchmarkStatus(b *testing.B)    { benchmarkHotPath(b, benchStatus) }
+func BenchmarkMailInbox(b *testing.B) { benchmarkHotPath(b, benchMailInbox) }
+func BenchmarkPatrol(b *testing.B)    { benchmarkHotPath(b, benchPatrol) }
+
+func benchmarkHotPath(b *testing.B, path hotPath) {
+	workDir, prefix := realBeadsDB(b)
+	procs := countBd(b)
+	b.Run("in-process", func(b *testing.B) {
+		runHotPath(b, New(workDir), prefix, path, procs)
+	})
+	b.Run("subprocess", func(b *testing.B) {
+		b.Setenv(EnvInProcess, "0")
+		runHotPath(b, New(workDir), prefix, path, procs)
+	})
+}
+
+func runHotPath(b *testing.B, client *Beads, prefix string, path hotPath, procs func() int) {
+	b.Helper()
+	start := procs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := path(client, prefix); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(procs()-start)/float64(b.N), "procs/op")
+}
+
+// countBd puts a bd on PATH that counts its invocations and runs the real
+// bd, returning a function reading the count.
+func countBd(tb testing.TB) func() int {
+	tb.Helper()
+	bdPath, err := exec.LookPath("bd")
+	if err != nil {
+		tb.Skip("bd CLI not installed, skipping")
+	}
+	dir := tb.TempDir()
+	countFile := filepath.Join(dir, "count")
+	script := "#!/bin/sh\necho >> \"" + countFile + "\"\nexec \"" + bdPath + "\" \"$@\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "bd"), []byte(script), 0755); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return func() int {
+		data, _ := os.ReadFile(countFile)
+		return len(data)
+	}
+}
+
+// stubBd puts a bd on PATH that prints a canned issue list (or wisp list)
+// and returns a function counting its invocations.
+func stubBd(tb testing.TB) func() int {
+	tb.Helper()
+	dir := tb.TempDir()
+	countFile := filepath.Join(dir, "count")
+	script := `#!/bin/sh
+echo >> "` + countFile + `"
+case "$*" in
+  *"wisp list"*) echo '{"wisps":[],"count":0}' ;;
+  *) echo '[{"id":"gt-agent","title":"witness","status":"open","hook_bead":"gt-work","labels":["gt:agent"]}]' ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "bd"), []byte(script), 0755); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return func() int {
+		data, _ := os.ReadFile(countFile)
+		return len(data)
+	}
+}
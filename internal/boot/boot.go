@@ -77,7 +77,8 @@ func (b *Boot) IsRunning() bool {
 
 // IsSessionAlive checks if the Boot tmux session exists.
 func (b *Boot) IsSessionAlive() bool {
-	has, err := b.tmux.HasSession(session.BootSessionName())
+	name := session.BootSessionName()
+	has, err := session.BackendForWith(b.tmux, b.townRoot, name).HasSession(name)
 	return err == nil && has
 }
 
@@ -169,7 +170,8 @@ func (b *Boot) Spawn(agentOverride string) error {
 func (b *Boot) spawnTmux(agentOverride string) error {
 	// Kill any stale session first (Boot is ephemeral).
 	if b.IsSessionAlive() {
-		_ = b.tmux.KillSessionWithProcesses(session.BootSessionName())
+		name := session.BootSessionName()
+		_ = session.BackendForWith(b.tmux, b.townRoot, name).KillSessionWithProcesses(name)
 	}
 
 	// Ensure boot directory exists (it should have CLAUDE.md with Boot context)
@@ -178,7 +180,7 @@ func (b *Boot) spawnTmux(agentOverride string) error {
 	}
 
 	// Use unified session lifecycle for config → settings → command → create → env.
-	_, err := session.StartSession(b.tmux, session.SessionConfig{
+	_, err := session.StartSession(session.NewBackendWith(b.tmux, b.townRoot), session.SessionConfig{
 		SessionID: session.BootSessionName(),
 		WorkDir:   b.bootDir,
 		Role:      "boot",
@@ -125,7 +125,9 @@ func Capture(polecatDir string) (*Checkpoint, error) {
 	cmd.Dir = polecatDir
 	output, err := cmd.Output()
 	if err == nil {
-		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		// Trim only the trailing newline: porcelain lines start with a
+		// status column that may be a space (" M file").
+		lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
 		for _, line := range lines {
 			if len(line) > 3 {
 				// Format: XY filename
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/selector"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// selectorHelp is the --select flag description shared by every command
+// that accepts a selector.
+const selectorHelp = "Target agents matching a selector (e.g. role=polecat,rig=web,idle>30m)"
+
+// selectorLongHelp documents the selector language in command help.
+const selectorLongHelp = `Selectors (--select) are comma-separated terms that must all match:
+  role=polecat|crew   rig=web   name=Toast   address=web/polecats/*
+  state=working       hook~gt-abc           hook= (nothing hooked)
+  session=running     idle>30m  (also >=, <, <=; units s, m, h, d)
+= and != take globs and |-separated alternatives; ~ and !~ match substrings.
+Agents without a running session are only considered when the selector
+tests state, hook or session.`
+
+// parseSelectFlag parses a --select value.
+func parseSelectFlag(s string) (*selector.Selector, error) {
+	sel, err := selector.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --select: %w", err)
+	}
+	return sel, nil
+}
+
+// selectAgents evaluates a selector against the town's agents and returns
+// the matches sorted by address. Running sessions on every backend (tmux
+// and the town's PTY supervisors) are always candidates; agent beads are
+// read only when the selector tests state, hook or session, and add agents
+// that have no session.
+func selectAgents(townRoot string, sel *selector.Selector) ([]*selector.Agent, error) {
+	names, err := session.ListAll(nil, townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+
+	byAddress := make(map[string]*selector.Agent)
+	backend := session.NewTownBackend(nil, townRoot)
+	now := time.Now()
+	for _, s := range filterAndSortSessions(names, true) {
+		a := sessionSelectorAgent(s)
+		if a == nil {
+			continue
+		}
+		if sel.Uses(selector.KeyIdle) {
+			if last, ok := sessionActivity(backend, s.Name); ok {
+				a.Idle, a.IdleKnown = now.Sub(last), true
+			}
+		}
+		byAddress[a.Address] = a
+	}
+
+	if townRoot != "" && sel.Uses(selector.KeyState, selector.KeyHook, selector.KeySession) {
+		for _, bead := range listSelectorAgentBeads(townRoot) {
+			if a := byAddress[bead.Address]; a != nil {
+				a.State, a.Hook = bead.State, bead.Hook
+				continue
+			}
+			byAddress[bead.Address] = bead
+		}
+	}
+
+	var agents []*selector.Agent
+	for _, a := range byAddress {
+		agents = append(agents, a)
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].Address < agents[j].Address })
+	return sel.Filter(agents), nil
+}
+
+// sessionActivity returns when a session last produced output, from
+// whichever backend runs it.
+func sessionActivity(backend *session.TownBackend, name string) (time.Time, bool) {
+	info, err := session.Info(backend.For(name), name)
+	if err != nil || info.Activity == "" {
+		return time.Time{}, false
+	}
+	secs, err := strconv.ParseInt(info.Activity, 10, 64)
+	if err != nil || secs <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(secs, 0), true
+}
+
+// sessionSelectorAgent describes a running agent session for selection.
+// Non-agent sessions (personal, test) return nil.
+func sessionSelectorAgent(s *AgentSession) *selector.Agent {
+	a := &selector.Agent{Session: s.Name, Rig: s.Rig, Name: s.AgentName}
+	switch s.Type {
+	case AgentMayor:
+		a.Role, a.Address, a.Rig = constants.RoleMayor, constants.RoleMayor, ""
+	case AgentDeacon:
+		a.Role, a.Address, a.Rig = constants.RoleDeacon, constants.RoleDeacon, ""
+	case AgentWitness:
+		a.Role, a.Address = constants.RoleWitness, s.Rig+"/"+constants.RoleWitness
+	case AgentRefinery:
+		a.Role, a.Address = constants.RoleRefinery, s.Rig+"/"+constants.RoleRefinery
+	case AgentCrew:
+		a.Role, a.Address = constants.RoleCrew, s.Rig+"/crew/"+s.AgentName
+	case AgentPolecat:
+		a.Role, a.Address = constants.RolePolecat, s.Rig+"/polecats/"+s.AgentName
+	default:
+		return nil
+	}
+	return a
+}
+
+// listSelectorAgentBeads reads agent beads from the town database and every
+// routed rig database. Unreadable databases are skipped: selection is
+// best-effort over whatever the town can see.
+func listSelectorAgentBeads(townRoot string) []*selector.Agent {
+	dirs := []string{townRoot}
+	if routes, err := beads.LoadRoutes(filepath.Join(townRoot, ".beads")); err == nil {
+		for _, route := range routes {
+			if strings.HasPrefix(route.Prefix, "hq-") || route.Path == "." {
+				continue
+			}
+			dirs = append(dirs, filepath.Join(townRoot, route.Path))
+		}
+	}
+
+	var agents []*selector.Agent
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		issues, err := beads.New(dir).ListAgentBeads()
+		if err != nil {
+			continue
+		}
+		for id, issue := range issues {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if a := beadSelectorAgent(id, beads.ParseAgentFields(issue.Description)); a != nil {
+				agents = append(agents, a)
+			}
+		}
+	}
+	return agents
+}
+
+// beadSelectorAgent describes an agent bead for selection. The bead's own
+// role_type and rig fields win over what the ID encodes, since collapsed
+// IDs carry the prefix rather than the rig name.
+func beadSelectorAgent(id string, fields *beads.AgentFields) *selector.Agent {
+	rig, role, name, ok := beads.ParseAgentBeadID(id)
+	if !ok {
+		return nil
+	}
+	a := &selector.Agent{Role: role, Rig: rig, Name: name}
+	if fields != nil {
+		if fields.RoleType != "" {
+			a.Role = fields.RoleType
+		}
+		if fields.Rig != "" {
+			a.Rig = fields.Rig
+		}
+		a.State, a.Hook = fields.AgentState, fields.HookBead
+	}
+
+	switch a.Role {
+	case constants.RoleMayor, constants.RoleDeacon:
+		a.Rig, a.Address = "", a.Role
+	case constants.RoleWitness, constants.RoleRefinery:
+		a.Address = a.Rig + "/" + a.Role
+	case constants.RoleCrew:
+		a.Address = a.Rig + "/crew/" + a.Name
+	case constants.RolePolecat:
+		a.Address = a.Rig + "/polecats/" + a.Name
+	case "dog":
+		a.Rig, a.Address = "", "deacon/dogs/"+a.Name
+	default:
+		return nil
+	}
+	return a
+}
+
+// printSelectedAgents lists selector matches, for --dry-run.
+func printSelectedAgents(sel *selector.Selector, agents []*selector.Agent) {
+	fmt.Printf("Selector %s matches %d agent(s):\n\n", style.Bold.Render(sel.String()), len(agents))
+	for _, a := range agents {
+		var details []string
+		if !a.Running() {
+			details = append(details, "no session")
+		}
+		if a.State != "" {
+			details = append(details, "state "+a.State)
+		}
+		if a.Hook != "" {
+			details = append(details, "hook "+a.Hook)
+		}
+		if a.IdleKnown {
+			details = append(details, "idle "+a.Idle.Round(time.Second).String())
+		}
+		fmt.Printf("  %-36s %s\n", a.Address, style.Dim.Render(strings.Join(details, ", ")))
+	}
+}
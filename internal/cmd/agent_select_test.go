@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestSessionSelectorAgent(t *testing.T) {
+	tests := []struct {
+		session *AgentSession
+		want    string
+		role    string
+	}{
+		{&AgentSession{Name: "hq-mayor", Type: AgentMayor}, "mayor", "mayor"},
+		{&AgentSession{Name: "gt-witness", Type: AgentWitness, Rig: "gastown"}, "gastown/witness", "witness"},
+		{&AgentSession{Name: "gt-crew-max", Type: AgentCrew, Rig: "gastown", AgentName: "max"}, "gastown/crew/max", "crew"},
+		{&AgentSession{Name: "gt-Toast", Type: AgentPolecat, Rig: "gastown", AgentName: "Toast"}, "gastown/polecats/Toast", "polecat"},
+	}
+	for _, tt := range tests {
+		a := sessionSelectorAgent(tt.session)
+		if a == nil || a.Address != tt.want || a.Role != tt.role || !a.Running() {
+			t.Errorf("sessionSelectorAgent(%s) = %+v, want %s (%s)", tt.session.Name, a, tt.want, tt.role)
+		}
+	}
+	if a := sessionSelectorAgent(&AgentSession{Name: "personal", Type: AgentPersonal}); a != nil {
+		t.Errorf("personal session selected: %+v", a)
+	}
+}
+
+func TestBeadSelectorAgent(t *testing.T) {
+	a := beadSelectorAgent("gt-gastown-polecat-Toast", &beads.AgentFields{AgentState: "working", HookBead: "gt-abc"})
+	if a == nil || a.Address != "gastown/polecats/Toast" || a.State != "working" || a.Hook != "gt-abc" || a.Running() {
+		t.Errorf("polecat bead = %+v", a)
+	}
+	if a := beadSelectorAgent("not-an-agent", nil); a != nil {
+		t.Errorf("non-agent ID selected: %+v", a)
+	}
+}
@@ -15,12 +15,14 @@ var (
 	broadcastRig    string
 	broadcastAll    bool
 	broadcastDryRun bool
+	broadcastSelect string
 )
 
 func init() {
 	broadcastCmd.Flags().StringVar(&broadcastRig, "rig", "", "Only broadcast to workers in this rig")
 	broadcastCmd.Flags().BoolVar(&broadcastAll, "all", false, "Include all agents (mayor, witness, etc.), not just workers")
 	broadcastCmd.Flags().BoolVar(&broadcastDryRun, "dry-run", false, "Show what would be sent without sending")
+	broadcastCmd.Flags().StringVar(&broadcastSelect, "select", "", selectorHelp)
 	rootCmd.AddCommand(broadcastCmd)
 }
 
@@ -35,11 +37,17 @@ Use --all to include infrastructure agents (mayor, deacon, witness, refinery).
 
 The message is sent as a nudge to each worker's Claude Code session.
 
+With --select, the selector picks the recipients instead of the worker
+default; --rig still narrows it.
+
+` + selectorLongHelp + `
+
 Examples:
   gt broadcast "Check your mail"
   gt broadcast --rig greenplace "New priority work available"
   gt broadcast --all "System maintenance in 5 minutes"
-  gt broadcast --dry-run "Test message"`,
+  gt broadcast --dry-run "Test message"
+  gt broadcast --select 'role=polecat,idle>30m' "Still with us?"`,
 	Args: cobra.ExactArgs(1),
 	RunE: runBroadcast,
 }
@@ -57,6 +65,26 @@ func runBroadcast(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("listing sessions: %w", err)
 	}
 
+	// Resolve --select to the set of matching sessions
+	var selected map[string]bool
+	if broadcastSelect != "" {
+		sel, err := parseSelectFlag(broadcastSelect)
+		if err != nil {
+			return err
+		}
+		townRoot, _ := workspace.FindFromCwd()
+		matches, err := selectAgents(townRoot, sel)
+		if err != nil {
+			return err
+		}
+		selected = make(map[string]bool)
+		for _, a := range matches {
+			if a.Running() {
+				selected[a.Session] = true
+			}
+		}
+	}
+
 	// Get sender identity to exclude self
 	sender := os.Getenv("BD_ACTOR")
 
@@ -68,8 +96,13 @@ func runBroadcast(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		// Unless --all, only include workers (crew + polecats)
-		if !broadcastAll {
+		// With --select, the selector decides who is included
+		if selected != nil {
+			if !selected[agent.Name] {
+				continue
+			}
+		} else if !broadcastAll {
+			// Unless --all, only include workers (crew + polecats)
 			if agent.Type != AgentCrew && agent.Type != AgentPolecat {
 				continue
 			}
@@ -88,6 +121,9 @@ func runBroadcast(cmd *cobra.Command, args []string) error {
 		if broadcastRig != "" {
 			fmt.Printf("  (filtered by rig: %s)\n", broadcastRig)
 		}
+		if broadcastSelect != "" {
+			fmt.Printf("  (filtered by selector: %s)\n", broadcastSelect)
+		}
 		return nil
 	}
 
@@ -3,10 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -17,6 +19,11 @@ var (
 	doctorRestartSessions bool
 	doctorNoStart         bool
 	doctorSlow            string
+	doctorJobs            int
+	doctorList            bool
+	doctorCustom          bool
+	doctorFormat          string
+	doctorTrend           bool
 )
 
 var doctorCmd = &cobra.Command{
@@ -108,10 +115,47 @@ Patrol checks:
   - patrol-not-stuck         Detect stale wisps (>1h)
   - patrol-plugins-accessible Verify plugin directories
 
+Custom checks:
+  User-defined checks are declared in the doctor_checks list of town
+  settings (settings/config.json) and rig settings (<rig>/settings/config.json):
+
+    "doctor_checks": [{
+      "name": "pre-commit-hook",
+      "description": "pre-commit hook installed in every polecat worktree",
+      "command": "test -x \"$(git rev-parse --git-path hooks)/pre-commit\"",
+      "dir": "polecats/*/*",
+      "severity": "warning",
+      "fix": "pre-commit install"
+    }]
+
+  A check passes when command exits with expect_exit (default 0) and its
+  output matches the expect_output regex, if set. dir is relative to the
+  town or rig root and may be a glob. Rig checks are named <rig>/<name>.
+  Other fields: category (default Custom), timeout (default 30s) and
+  depends_on. Use --custom to run only these checks.
+
+Output formats:
+  --format=json|junit|sarif prints a machine-readable report to stdout
+  instead of the streaming text output (for CI and editors).
+
+History and trends:
+  Every run is recorded in .runtime/doctor/history.jsonl (last 100 runs).
+  --trend reads that history without running checks and shows new failures
+  since the previous run, how long each failing check has been failing, and
+  checks whose duration changed notably. Combine with --format=json for
+  patrols that should escalate only new failures.
+
+Use --list to show all registered checks (including custom ones) without running them.
 Use --fix to attempt automatic fixes for issues that support it.
 Use --no-start with --fix to suppress starting the daemon and agents.
 Use --rig to check a specific rig instead of the entire workspace.
-Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).`,
+Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).
+
+Independent checks run in parallel (--jobs, default 8; --jobs=1 runs them
+one at a time). Results are still printed in order. Checks that depend on
+a failing prerequisite (e.g. rig checks when town-config-valid fails) are
+skipped. With --fix, once a fix has been applied every later check runs
+again, one at a time in dependency order, so it sees the fixed state.`,
 	RunE: runDoctor,
 }
 
@@ -124,6 +168,11 @@ func init() {
 	doctorCmd.Flags().StringVar(&doctorSlow, "slow", "", "Highlight slow checks (optional threshold, default 1s)")
 	// Allow --slow without a value (uses default 1s)
 	doctorCmd.Flags().Lookup("slow").NoOptDefVal = "1s"
+	doctorCmd.Flags().BoolVar(&doctorList, "list", false, "List registered checks without running them")
+	doctorCmd.Flags().BoolVar(&doctorCustom, "custom", false, "Run only user-defined checks from town and rig settings")
+	doctorCmd.Flags().StringVar(&doctorFormat, "format", doctor.FormatText, "Output format: text, json, junit or sarif")
+	doctorCmd.Flags().BoolVar(&doctorTrend, "trend", false, "Show regressions, failing streaks and duration changes from run history")
+	doctorCmd.Flags().IntVarP(&doctorJobs, "jobs", "j", doctor.DefaultJobs, "Number of checks to run in parallel")
 	rootCmd.AddCommand(doctorCmd)
 }
 
@@ -134,6 +183,13 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	if !slices.Contains(doctor.Formats, doctorFormat) {
+		return fmt.Errorf("invalid --format %q (want text, json, junit or sarif)", doctorFormat)
+	}
+	if doctorTrend {
+		return runDoctorTrend(townRoot)
+	}
+
 	// Create check context
 	ctx := &doctor.CheckContext{
 		TownRoot:        townRoot,
@@ -145,7 +201,67 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	// Create doctor and register checks
 	d := doctor.NewDoctor()
+	d.SetJobs(doctorJobs)
+
+	if !doctorCustom {
+		registerDoctorChecks(d)
+	}
+	// User-defined checks from town and rig settings run after the built-ins
+	d.RegisterAll(doctor.CustomChecks(townRoot, doctorRig)...)
+
+	if doctorList {
+		printDoctorChecks(d.Checks())
+		return nil
+	}
+
+	// Parse slow threshold (0 = disabled)
+	var slowThreshold time.Duration
+	if doctorSlow != "" {
+		var err error
+		slowThreshold, err = time.ParseDuration(doctorSlow)
+		if err != nil {
+			return fmt.Errorf("invalid --slow duration %q: %w", doctorSlow, err)
+		}
+	}
+
+	var report *doctor.Report
+	if doctorFormat == doctor.FormatText {
+		// Run checks with streaming output
+		fmt.Println() // Initial blank line
+		if doctorFix {
+			report = d.FixStreaming(ctx, os.Stdout, slowThreshold)
+		} else {
+			report = d.RunStreaming(ctx, os.Stdout, slowThreshold)
+		}
+
+		// Print summary (checks were already printed during streaming)
+		report.PrintSummaryOnly(os.Stdout, doctorVerbose, slowThreshold)
+	} else {
+		if doctorFix {
+			report = d.Fix(ctx)
+		} else {
+			report = d.Run(ctx)
+		}
+		if err := report.Write(os.Stdout, doctorFormat); err != nil {
+			return fmt.Errorf("writing %s report: %w", doctorFormat, err)
+		}
+	}
+
+	// Record the run for --trend (best effort)
+	if err := doctor.AppendHistory(townRoot, doctor.NewHistoryEntry(report, doctorScope()), 0); err != nil {
+		style.PrintWarning("could not record doctor history: %v", err)
+	}
+
+	// Exit with error code if there are errors
+	if report.HasErrors() {
+		return fmt.Errorf("doctor found %d error(s)", report.Summary.Errors)
+	}
 
+	return nil
+}
+
+// registerDoctorChecks registers the built-in checks in dependency-friendly order.
+func registerDoctorChecks(d *doctor.Doctor) {
 	// Register workspace-level checks first (fundamental)
 	d.RegisterAll(doctor.WorkspaceChecks()...)
 
@@ -261,37 +377,42 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	// Worktree gitdir validity (runs across all rigs, or specific rig with --rig)
 	d.Register(doctor.NewWorktreeGitdirCheck())
 
+	// Polecat sandbox: configured rigs can sandbox and running polecats are confined
+	d.Register(doctor.NewSandboxCheck())
+
 	// Rig-specific checks (only when --rig is specified)
 	if doctorRig != "" {
 		d.RegisterAll(doctor.RigChecks()...)
 	}
+}
 
-	// Parse slow threshold (0 = disabled)
-	var slowThreshold time.Duration
-	if doctorSlow != "" {
-		var err error
-		slowThreshold, err = time.ParseDuration(doctorSlow)
-		if err != nil {
-			return fmt.Errorf("invalid --slow duration %q: %w", doctorSlow, err)
+// printDoctorChecks lists registered checks grouped by category.
+func printDoctorChecks(checks []doctor.Check) {
+	byCategory := make(map[string][]doctor.Check)
+	var categories []string
+	for _, c := range checks {
+		cat := "Other"
+		if cg, ok := c.(interface{ Category() string }); ok && cg.Category() != "" {
+			cat = cg.Category()
 		}
+		if _, seen := byCategory[cat]; !seen && !slices.Contains(doctor.CategoryOrder, cat) {
+			categories = append(categories, cat)
+		}
+		byCategory[cat] = append(byCategory[cat], c)
 	}
 
-	// Run checks with streaming output
-	fmt.Println() // Initial blank line
-	var report *doctor.Report
-	if doctorFix {
-		report = d.FixStreaming(ctx, os.Stdout, slowThreshold)
-	} else {
-		report = d.RunStreaming(ctx, os.Stdout, slowThreshold)
-	}
-
-	// Print summary (checks were already printed during streaming)
-	report.PrintSummaryOnly(os.Stdout, doctorVerbose, slowThreshold)
-
-	// Exit with error code if there are errors
-	if report.HasErrors() {
-		return fmt.Errorf("doctor found %d error(s)", report.Summary.Errors)
+	for _, cat := range slices.Concat(doctor.CategoryOrder, categories) {
+		if len(byCategory[cat]) == 0 {
+			continue
+		}
+		fmt.Println(style.Bold.Render(cat))
+		for _, c := range byCategory[cat] {
+			desc := c.Description()
+			if c.CanFix() {
+				desc += " (fixable)"
+			}
+			fmt.Printf("  %-28s %s\n", c.Name(), style.Dim.Render(desc))
+		}
+		fmt.Println()
 	}
-
-	return nil
 }
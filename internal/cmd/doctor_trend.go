@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// doctorScope describes which checks a gt doctor run covered, for history.
+func doctorScope() string {
+	switch {
+	case doctorCustom && doctorRig != "":
+		return "custom,rig:" + doctorRig
+	case doctorCustom:
+		return "custom"
+	case doctorRig != "":
+		return "rig:" + doctorRig
+	default:
+		return ""
+	}
+}
+
+// runDoctorTrend prints the trend computed from recorded doctor runs.
+func runDoctorTrend(townRoot string) error {
+	history, err := doctor.LoadHistory(townRoot)
+	if err != nil {
+		return err
+	}
+	trend := doctor.ComputeTrend(history)
+
+	if doctorFormat == doctor.FormatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(trend)
+	}
+	if doctorFormat != doctor.FormatText {
+		return fmt.Errorf("--trend supports --format text or json, not %s", doctorFormat)
+	}
+
+	if trend == nil {
+		fmt.Println("No doctor history yet. Run 'gt doctor' to record one.")
+		return nil
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("Doctor trend"),
+		style.Dim.Render(fmt.Sprintf("(%d run(s) recorded, latest %s)", trend.Runs, formatAge(trend.Latest))))
+	if trend.Previous.IsZero() {
+		fmt.Println(style.Dim.Render("  Only one run recorded; regressions need a previous run."))
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("New failures since previous run"))
+	if len(trend.Regressions) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("none"))
+	}
+	for _, c := range trend.Regressions {
+		was := "new check"
+		if c.Was != nil {
+			was = "was " + c.Was.String()
+		}
+		fmt.Printf("  %s %s %s\n", doctorStatusPrefix(c.Status), c.Name, style.Dim.Render("("+was+")"))
+	}
+
+	if len(trend.Recoveries) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Recovered"))
+		for _, c := range trend.Recoveries {
+			fmt.Printf("  %s %s %s\n", style.SuccessPrefix, c.Name, style.Dim.Render("(was "+c.Was.String()+")"))
+		}
+	}
+
+	if len(trend.Failing) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Failing"))
+		for _, f := range trend.Failing {
+			streak := "first failure"
+			if f.Runs > 1 {
+				streak = fmt.Sprintf("failing for %d runs, since %s", f.Runs, formatAge(f.Since))
+			}
+			fmt.Printf("  %s %-32s %s\n", doctorStatusPrefix(f.Status), f.Name, style.Dim.Render(streak))
+		}
+	}
+
+	if len(trend.Durations) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Duration changes (latest vs median of earlier runs)"))
+		for _, d := range trend.Durations {
+			latest := time.Duration(d.LatestMs) * time.Millisecond
+			median := time.Duration(d.MedianMs) * time.Millisecond
+			change := "slower"
+			if latest < median {
+				change = "faster"
+			}
+			fmt.Printf("  %-34s %8s  %s\n", d.Name, latest,
+				style.Dim.Render(fmt.Sprintf("median %s over %d run(s), %s", median, d.Samples, change)))
+		}
+	}
+	return nil
+}
+
+func doctorStatusPrefix(s doctor.CheckStatus) string {
+	if s == doctor.StatusError {
+		return style.ErrorPrefix
+	}
+	return style.WarningPrefix
+}
@@ -92,9 +92,11 @@ func runDown(cmd *cobra.Command, args []string) error {
 	}
 
 	t := tmux.NewTmux()
-	if !t.IsAvailable() {
+	if !t.IsAvailable() && session.BackendName(townRoot) != config.SessionBackendPTY {
 		return fmt.Errorf("tmux not available (is tmux installed and on PATH?)")
 	}
+	// Sessions may run in tmux or under PTY supervisors.
+	sessions := session.NewTownBackend(t, townRoot)
 
 	// Phase 0: Acquire shutdown lock (skip for dry-run)
 	if !downDryRun {
@@ -154,12 +156,12 @@ func runDown(cmd *cobra.Command, args []string) error {
 	for _, rigName := range rigs {
 		sessionName := session.RefinerySessionName(session.PrefixFor(rigName))
 		if downDryRun {
-			if running, _ := t.HasSession(sessionName); running {
+			if running, _ := sessions.HasSession(sessionName); running {
 				printDownStatus(fmt.Sprintf("Refinery (%s)", rigName), true, "would stop")
 			}
 			continue
 		}
-		wasRunning, err := stopSession(t, sessionName)
+		wasRunning, err := stopSession(sessions, sessionName)
 		if err != nil {
 			printDownStatus(fmt.Sprintf("Refinery (%s)", rigName), false, err.Error())
 			allOK = false
@@ -174,12 +176,12 @@ func runDown(cmd *cobra.Command, args []string) error {
 	for _, rigName := range rigs {
 		sessionName := session.WitnessSessionName(session.PrefixFor(rigName))
 		if downDryRun {
-			if running, _ := t.HasSession(sessionName); running {
+			if running, _ := sessions.HasSession(sessionName); running {
 				printDownStatus(fmt.Sprintf("Witness (%s)", rigName), true, "would stop")
 			}
 			continue
 		}
-		wasRunning, err := stopSession(t, sessionName)
+		wasRunning, err := stopSession(sessions, sessionName)
 		if err != nil {
 			printDownStatus(fmt.Sprintf("Witness (%s)", rigName), false, err.Error())
 			allOK = false
@@ -193,12 +195,12 @@ func runDown(cmd *cobra.Command, args []string) error {
 	// Phase 3: Stop town-level sessions (Mayor, Boot, Deacon)
 	for _, ts := range session.TownSessions() {
 		if downDryRun {
-			if running, _ := t.HasSession(ts.SessionID); running {
+			if running, _ := sessions.HasSession(ts.SessionID); running {
 				printDownStatus(ts.Name, true, "would stop")
 			}
 			continue
 		}
-		stopped, err := session.StopTownSession(t, ts, downForce)
+		stopped, err := session.StopTownSession(sessions, ts, downForce)
 		if err != nil {
 			printDownStatus(ts.Name, false, err.Error())
 			allOK = false
@@ -276,7 +278,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 		cleanupOrphanedClaude(defaultDownOrphanGraceSecs)
 
 		time.Sleep(500 * time.Millisecond)
-		respawned := verifyShutdown(t, townRoot)
+		respawned := verifyShutdown(sessions, townRoot)
 		if len(respawned) > 0 {
 			fmt.Println()
 			fmt.Printf("%s Warning: Some processes may have respawned:\n", style.Bold.Render("⚠"))
@@ -411,9 +413,9 @@ func printDownStatus(name string, ok bool, detail string) {
 	}
 }
 
-// stopSession gracefully stops a tmux session.
+// stopSession gracefully stops a session.
 // Returns (wasRunning, error) - wasRunning is true if session existed and was stopped.
-func stopSession(t *tmux.Tmux, sessionName string) (bool, error) {
+func stopSession(t session.Backend, sessionName string) (bool, error) {
 	running, err := t.HasSession(sessionName)
 	if err != nil {
 		return false, err
@@ -462,14 +464,14 @@ func acquireShutdownLock(townRoot string) (*flock.Flock, error) {
 
 // verifyShutdown checks for respawned processes after shutdown.
 // Returns list of things that are still running or respawned.
-func verifyShutdown(t *tmux.Tmux, townRoot string) []string {
+func verifyShutdown(t session.Backend, townRoot string) []string {
 	var respawned []string
 
 	sessions, err := t.ListSessions()
 	if err == nil {
 		for _, sess := range sessions {
 			if session.IsKnownSession(sess) {
-				respawned = append(respawned, fmt.Sprintf("tmux session %s", sess))
+				respawned = append(respawned, fmt.Sprintf("session %s", sess))
 			}
 		}
 	}
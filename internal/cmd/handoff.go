@@ -52,6 +52,15 @@ The --collect (-c) flag gathers current state (hooked work, inbox, ready beads,
 in-progress items) and includes it in the handoff mail. This provides context
 for the next session without manual summarization.
 
+Every self-handoff also writes a structured handoff document to
+.runtime/handoff.json: hooked bead, molecule step, branch and commit, files
+in flight and recent commands (captured from git and the agent's
+conversation log), plus open questions and next actions given with
+--question and --next. The successor's gt prime renders it, and
+gt resume --diff shows what changed since it was written.
+
+  gt handoff --next "Run the migration" --question "Is the cache safe to drop?"
+
 The --cycle flag triggers automatic session cycling (used by PreCompact hooks).
 Unlike --auto (state only) or normal handoff (polecat→gt-done redirect), --cycle
 always does a full respawn regardless of role. This enables crew workers and
@@ -73,6 +82,8 @@ var (
 	handoffCycle      bool
 	handoffReason     string
 	handoffNoGitCheck bool
+	handoffQuestions  []string
+	handoffNext       []string
 )
 
 func init() {
@@ -86,6 +97,8 @@ func init() {
 	handoffCmd.Flags().BoolVar(&handoffCycle, "cycle", false, "Auto-cycle session (for PreCompact hooks that want full session replacement)")
 	handoffCmd.Flags().StringVar(&handoffReason, "reason", "", "Reason for handoff (e.g., 'compaction', 'idle')")
 	handoffCmd.Flags().BoolVar(&handoffNoGitCheck, "no-git-check", false, "Skip git workspace cleanliness check")
+	handoffCmd.Flags().StringArrayVar(&handoffQuestions, "question", nil, "Open question for the successor (repeatable)")
+	handoffCmd.Flags().StringArrayVar(&handoffNext, "next", nil, "Next action for the successor, in order (repeatable)")
 	rootCmd.AddCommand(handoffCmd)
 }
 
@@ -268,6 +281,7 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		if handoffSubject != "" || handoffMessage != "" {
 			fmt.Printf("Would send handoff mail: subject=%q (auto-hooked)\n", handoffSubject)
 		}
+		fmt.Printf("Would write handoff document: %s\n", constants.DirRuntime+"/"+constants.FileHandoffDocument)
 		fmt.Printf("Would execute: tmux clear-history -t %s\n", pane)
 		fmt.Printf("Would execute: tmux respawn-pane -k -t %s %s\n", pane, restartCmd)
 		return nil
@@ -280,6 +294,9 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 	// Placed after the dry-run guard to avoid mutating session state during dry-run.
 	updateSessionEnvForHandoff(t, currentSession, "")
 
+	// Write the structured handoff document; its rendering is the mail body.
+	handoffMessage = saveHandoffDocument(currentSession, handoffReason, handoffMessage)
+
 	// Send handoff mail to self (defaults applied inside sendHandoffMail).
 	// The mail is auto-hooked so the next session picks it up.
 	beadID, err := sendHandoffMail(handoffSubject, handoffMessage)
@@ -368,6 +385,7 @@ func runHandoffAuto() error {
 
 	if handoffDryRun {
 		fmt.Printf("[auto-handoff] Would send mail: subject=%q\n", subject)
+		fmt.Printf("[auto-handoff] Would write handoff document\n")
 		fmt.Printf("[auto-handoff] Would write handoff marker\n")
 		return nil
 	}
@@ -375,6 +393,14 @@ func runHandoffAuto() error {
 	// Close any in-progress molecule steps before state save (gt-e26g).
 	cleanupMoleculeOnHandoff()
 
+	sessionName := "auto-handoff"
+	if tmux.IsInsideTmux() {
+		if name, err := getCurrentTmuxSession(); err == nil {
+			sessionName = name
+		}
+	}
+	message = saveHandoffDocument(sessionName, handoffReason, message)
+
 	// Send handoff mail to self
 	beadID, err := sendHandoffMail(subject, message)
 	if err != nil {
@@ -389,12 +415,6 @@ func runHandoffAuto() error {
 		runtimeDir := filepath.Join(cwd, constants.DirRuntime)
 		_ = os.MkdirAll(runtimeDir, 0755)
 		markerPath := filepath.Join(runtimeDir, constants.FileHandoffMarker)
-		sessionName := "auto-handoff"
-		if tmux.IsInsideTmux() {
-			if name, err := getCurrentTmuxSession(); err == nil {
-				sessionName = name
-			}
-		}
 		_ = os.WriteFile(markerPath, []byte(sessionName), 0644)
 	}
 
@@ -474,6 +494,7 @@ func runHandoffCycle() error {
 
 	if handoffDryRun {
 		fmt.Printf("[cycle] Would send handoff mail: subject=%q\n", subject)
+		fmt.Printf("[cycle] Would write handoff document\n")
 		fmt.Printf("[cycle] Would write handoff marker\n")
 		fmt.Printf("[cycle] Would execute: tmux clear-history -t %s\n", pane)
 		fmt.Printf("[cycle] Would execute: tmux respawn-pane -k -t %s <restart-cmd>\n", pane)
@@ -483,6 +504,8 @@ func runHandoffCycle() error {
 	// Close any in-progress molecule steps before cycling (gt-e26g).
 	cleanupMoleculeOnHandoff()
 
+	message = saveHandoffDocument(currentSession, handoffReason, message)
+
 	// Send handoff mail to self (auto-hooked for successor)
 	beadID, err := sendHandoffMail(subject, message)
 	if err != nil {
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/handoff"
+	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// handoffDocStaleAfter is how long gt prime keeps showing a handoff document.
+const handoffDocStaleAfter = 24 * time.Hour
+
+// captureHandoffDocument builds the structured handoff for the current
+// session. Git state, hooked work, molecule step and the commands and files
+// from the agent's recent tool calls are captured automatically; open
+// questions, next actions and notes come from the handing-off agent.
+func captureHandoffDocument(workDir, session, reason, notes string) *handoff.Document {
+	doc := captureWorkState(workDir)
+	doc.Session = session
+	doc.SessionID = runtime.SessionIDFromEnv()
+	doc.Reason = reason
+	doc.Notes = notes
+	doc.OpenQuestions = handoffQuestions
+	doc.NextActions = handoffNext
+
+	agent := os.Getenv("GT_AGENT")
+	if agent == "" {
+		agent = string(config.DefaultAgentPreset())
+	}
+	if events, err := agentlog.ReadTranscript(agentlog.AgentTypeForPreset(agent), doc.SessionID, workDir, time.Time{}); err == nil {
+		doc.AddToolActivity(workDir, events)
+	}
+	return doc
+}
+
+// captureWorkState captures the automatic part of a handoff document for
+// workDir: git state plus the agent's hooked bead and molecule step.
+func captureWorkState(workDir string) *handoff.Document {
+	doc := handoff.Capture(workDir)
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if roleInfo, err := GetRoleWithContext(workDir, townRoot); err == nil {
+			doc.Agent = getAgentIdentity(RoleContext{Role: roleInfo.Role, Rig: roleInfo.Rig, Polecat: roleInfo.Polecat})
+			doc.HookedBead = detectHookedBead(workDir, roleInfo)
+			doc.Molecule, doc.Step, doc.StepTitle = detectMoleculeContext(workDir, roleInfo)
+		}
+	}
+	return doc
+}
+
+// saveHandoffDocument captures and writes the handoff document for the
+// current directory and returns it rendered as the handoff mail body.
+// Failures are warnings: the mail still carries notes, and the respawn
+// matters more than the record.
+func saveHandoffDocument(session, reason, notes string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return notes
+	}
+	doc := captureHandoffDocument(cwd, session, reason, notes)
+	if err := handoff.Write(cwd, doc); err != nil {
+		style.PrintWarning("could not write handoff document: %v", err)
+	}
+	return doc.Markdown()
+}
+
+// outputHandoffDocument renders the predecessor's handoff document, if a
+// recent one exists, so the successor starts from where things stand.
+// The first successor to prime consumes the document; later sessions in
+// the same workspace don't see it again. The document itself is kept for
+// gt resume --diff.
+func outputHandoffDocument(ctx RoleContext) {
+	doc, err := handoff.Read(ctx.WorkDir)
+	if err != nil || doc == nil || doc.IsStale(handoffDocStaleAfter) {
+		return
+	}
+	sessionID := runtime.SessionIDFromEnv()
+	if sessionID != "" && sessionID == doc.SessionID {
+		return // the predecessor itself, before it has gone
+	}
+	wasConsumed := !doc.ConsumedAt.IsZero()
+	if !doc.Consume(sessionID) {
+		return
+	}
+	if !wasConsumed {
+		if err := handoff.Write(ctx.WorkDir, doc); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not mark handoff consumed: %v\n", err)
+		}
+	}
+
+	from := doc.Session
+	if from == "" {
+		from = doc.Agent
+	}
+	fmt.Println()
+	fmt.Printf("%s\n\n", style.Bold.Render("## 🤝 Handoff From Your Predecessor"))
+	fmt.Printf("Written %s ago", doc.Age().Round(time.Minute))
+	if from != "" {
+		fmt.Printf(" by %s", from)
+	}
+	fmt.Print(".\n\n")
+	fmt.Println(doc.Markdown())
+	fmt.Println()
+	fmt.Println("Start from the next actions above. See what changed since: `gt resume --diff`")
+	fmt.Println()
+}
@@ -859,3 +859,75 @@ func TestEnforceHandoffCooldown(t *testing.T) {
 		}
 	})
 }
+
+func TestSaveHandoffDocument(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, args := range [][]string{
+		{"git", "init", "-b", "main"},
+		{"git", "config", "user.email", "test@test.com"},
+		{"git", "config", "user.name", "Test"},
+		{"git", "commit", "--allow-empty", "-m", "initial commit"},
+	} {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = tmpDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v failed: %s", args, out)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "wip.go"), []byte("package wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(tmpDir)
+	t.Setenv("HOME", t.TempDir()) // no conversation log to read
+
+	oldQuestions, oldNext := handoffQuestions, handoffNext
+	handoffQuestions = []string{"Is the cache safe to drop?"}
+	handoffNext = []string{"Run the migration"}
+	t.Cleanup(func() { handoffQuestions, handoffNext = oldQuestions, oldNext })
+
+	body := saveHandoffDocument("gt-crew-max", "compaction", "Notes for later")
+	for _, want := range []string{"**Branch:** main @", "Run the migration", "Is the cache safe to drop?", "wip.go", "Notes for later"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("mail body missing %q:\n%s", want, body)
+		}
+	}
+
+	output := captureStdout(t, func() {
+		outputHandoffDocument(RoleContext{WorkDir: tmpDir})
+	})
+	if !strings.Contains(output, "Handoff From Your Predecessor") || !strings.Contains(output, "by gt-crew-max") {
+		t.Errorf("prime output missing handoff:\n%s", output)
+	}
+
+	// The first successor consumed it; another session doesn't see it.
+	t.Setenv("GT_SESSION_ID_ENV", "TEST_SESSION_ID")
+	t.Setenv("TEST_SESSION_ID", "other-session")
+	output = captureStdout(t, func() {
+		outputHandoffDocument(RoleContext{WorkDir: tmpDir})
+	})
+	if strings.Contains(output, "Handoff From Your Predecessor") {
+		t.Errorf("consumed handoff rendered again:\n%s", output)
+	}
+
+	// Commit the work in flight; resume --diff reports it.
+	for _, args := range [][]string{
+		{"git", "add", "wip.go"},
+		{"git", "commit", "-m", "land wip"},
+	} {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = tmpDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v failed: %s", args, out)
+		}
+	}
+	output = captureStdout(t, func() {
+		if err := runResumeDiff(); err != nil {
+			t.Errorf("runResumeDiff: %v", err)
+		}
+	})
+	for _, want := range []string{"Commits (1):", "land wip", "Settled: wip.go"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("resume --diff output missing %q:\n%s", want, output)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/charmbracelet/lipgloss"
@@ -18,7 +20,9 @@ var hooksDiffCmd = &cobra.Command{
 
 Compares the current .claude/settings.json files against what would
 be generated from base + overrides. Uses color to highlight additions
-and removals.
+and removals. Roles running another agent also get their native hook
+files compared, with hooks that agent can't express listed as not
+translated.
 
 Exit codes:
   0 - No changes pending
@@ -79,27 +83,23 @@ func runHooksDiff(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("loading current settings for %s: %w", target.DisplayKey(), err)
 		}
 
-		if hooks.HooksEqual(expected, &current.Hooks) {
-			continue
-		}
-
-		// Compute relative path from town root for display
-		relPath, err := filepath.Rel(townRoot, target.Path)
-		if err != nil {
-			relPath = target.Path
+		if !hooks.HooksEqual(expected, &current.Hooks) {
+			if printHooksDiff(townRoot, target.Path, diffHooksConfigs(&current.Hooks, expected)) {
+				hasChanges = true
+			}
 		}
 
-		changes := diffHooksConfigs(&current.Hooks, expected)
-		if len(changes) == 0 {
+		native, err := resolveNativeHooks(townRoot, target)
+		if err != nil || native == nil {
 			continue
 		}
-
-		hasChanges = true
-		fmt.Printf("%s:\n", style.Bold.Render(relPath))
-		for _, change := range changes {
-			fmt.Print(change)
+		changed, err := diffNativeHooks(townRoot, target, native)
+		if err != nil {
+			return err
+		}
+		if changed {
+			hasChanges = true
 		}
-		fmt.Println()
 	}
 
 	if !hasChanges {
@@ -111,6 +111,64 @@ func runHooksDiff(cmd *cobra.Command, args []string) error {
 	return NewSilentExit(1)
 }
 
+// printHooksDiff prints the changes for one file, reporting whether there
+// were any.
+func printHooksDiff(townRoot, path string, changes []string) bool {
+	if len(changes) == 0 {
+		return false
+	}
+	// Compute relative path from town root for display
+	relPath, err := filepath.Rel(townRoot, path)
+	if err != nil {
+		relPath = path
+	}
+	fmt.Printf("%s:\n", style.Bold.Render(relPath))
+	for _, change := range changes {
+		fmt.Print(change)
+	}
+	fmt.Println()
+	return true
+}
+
+// diffNativeHooks diffs a target's provider-native hook files. The hooks in
+// each file are read back into the abstract config and diffed like
+// settings.json; files whose hooks match but whose rendering differs (a
+// hand-edited file, or a newer generator) are reported as regenerated.
+func diffNativeHooks(townRoot string, target hooks.Target, native *nativeHooks) (bool, error) {
+	supported, unsupported := native.translator.Supported(native.expected)
+	changed := false
+	for _, path := range native.paths {
+		current, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return false, err
+		}
+		want, _, err := native.translator.Render(native.expected, current)
+		if err != nil {
+			return false, fmt.Errorf("rendering %s: %w", path, err)
+		}
+		if bytes.Equal(current, want) {
+			continue
+		}
+		have, err := native.translator.Parse(current)
+		if err != nil {
+			have = &hooks.HooksConfig{}
+		}
+		changes := diffHooksConfigs(have, supported)
+		if len(changes) == 0 {
+			changes = []string{fmt.Sprintf("  %s\n", diffAdd.Render("~ regenerated ("+native.provider+" format)"))}
+		}
+		if printHooksDiff(townRoot, path, changes) {
+			changed = true
+		}
+	}
+	if len(native.paths) > 0 {
+		for _, u := range unsupported {
+			fmt.Printf("  %s %s (%s): %s\n", style.Warning.Render("!"), target.DisplayKey(), native.provider, style.Dim.Render("not translated: "+u.String()))
+		}
+	}
+	return changed, nil
+}
+
 // diffHooksConfigs compares current and expected configs, returning formatted diff lines.
 func diffHooksConfigs(current, expected *hooks.HooksConfig) []string {
 	var lines []string
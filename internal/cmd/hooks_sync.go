@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/hooks"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -16,7 +19,7 @@ var hooksSyncDryRun bool
 
 var hooksSyncCmd = &cobra.Command{
 	Use:   "sync",
-	Short: "Regenerate all .claude/settings.json files",
+	Short: "Regenerate all agent hook settings",
 	Long: `Regenerate all .claude/settings.json files from the base config and overrides.
 
 For each target (mayor, deacon, rig/crew, rig/witness, etc.):
@@ -26,6 +29,15 @@ For each target (mayor, deacon, rig/crew, rig/witness, etc.):
 4. Merge hooks section into existing settings.json (preserving all fields)
 5. Write updated settings.json
 
+When a role runs another agent (gemini, cursor, copilot, opencode, pi, omp),
+the same hooks are also translated into that agent's native format in each
+of the role's working directories: Gemini and Cursor hook settings, a
+Copilot hooks file (.github/hooks/gastown.json), or a generated OpenCode,
+pi or omp extension. In project clones and worktrees these files are
+listed in the repository's .git/info/exclude so they are never committed.
+Hooks the agent can't express (for example WorktreeCreate, or tool-scoped
+guards on Copilot) are reported as not translated.
+
 Examples:
   gt hooks sync             # Regenerate all settings.json files
   gt hooks sync --dry-run   # Show what would change without writing`,
@@ -61,6 +73,16 @@ func runHooksSync(cmd *cobra.Command, args []string) error {
 	errors := 0
 	integrityErrors := 0
 	var failedTargets []string
+	count := func(result syncResult) {
+		switch result {
+		case syncCreated:
+			created++
+		case syncUpdated:
+			updated++
+		case syncUnchanged:
+			unchanged++
+		}
+	}
 
 	for _, target := range targets {
 		result, err := syncTarget(target, hooksSyncDryRun)
@@ -82,29 +104,30 @@ func runHooksSync(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		relPath, pathErr := filepath.Rel(townRoot, target.Path)
-		if pathErr != nil {
-			relPath = target.Path
-		}
+		printSyncResult(townRoot, target.Path, result, hooksSyncDryRun)
+		count(result)
 
-		switch result {
-		case syncCreated:
-			if hooksSyncDryRun {
-				fmt.Printf("  %s %s %s\n", style.Warning.Render("~"), relPath, style.Dim.Render("(would create)"))
-			} else {
-				fmt.Printf("  %s %s %s\n", style.Success.Render("✓"), relPath, style.Dim.Render("(created)"))
-			}
-			created++
-		case syncUpdated:
-			if hooksSyncDryRun {
-				fmt.Printf("  %s %s %s\n", style.Warning.Render("~"), relPath, style.Dim.Render("(would update)"))
-			} else {
-				fmt.Printf("  %s %s %s\n", style.Success.Render("✓"), relPath, style.Dim.Render("(updated)"))
-			}
-			updated++
-		case syncUnchanged:
-			fmt.Printf("  %s %s %s\n", style.Dim.Render("·"), relPath, style.Dim.Render("(unchanged)"))
-			unchanged++
+		// Agents on other providers get the same hooks in their native format.
+		native, err := resolveNativeHooks(townRoot, target)
+		if err != nil {
+			fmt.Printf("  %s %s (%s)\n", style.Dim.Render("·"), target.DisplayKey(), style.Dim.Render(err.Error()))
+			continue
+		}
+		if native == nil {
+			continue
+		}
+		results, unsupported, err := syncNativeHooks(native, hooksSyncDryRun)
+		for _, r := range results {
+			printSyncResult(townRoot, r.path, r.result, hooksSyncDryRun)
+			count(r.result)
+		}
+		for _, u := range unsupported {
+			fmt.Printf("  %s %s (%s): %s\n", style.Warning.Render("!"), target.DisplayKey(), native.provider, style.Dim.Render("not translated: "+u.String()))
+		}
+		if err != nil {
+			fmt.Printf("  %s %s (%s): %v\n", style.Error.Render("✖"), target.DisplayKey(), native.provider, err)
+			errors++
+			failedTargets = append(failedTargets, target.DisplayKey()+" ("+native.provider+")")
 		}
 	}
 
@@ -211,3 +234,143 @@ func syncTarget(target hooks.Target, dryRun bool) (syncResult, error) {
 	}
 	return syncCreated, nil
 }
+
+// printSyncResult prints one synced file.
+func printSyncResult(townRoot, path string, result syncResult, dryRun bool) {
+	relPath, err := filepath.Rel(townRoot, path)
+	if err != nil {
+		relPath = path
+	}
+	switch result {
+	case syncCreated:
+		if dryRun {
+			fmt.Printf("  %s %s %s\n", style.Warning.Render("~"), relPath, style.Dim.Render("(would create)"))
+		} else {
+			fmt.Printf("  %s %s %s\n", style.Success.Render("✓"), relPath, style.Dim.Render("(created)"))
+		}
+	case syncUpdated:
+		if dryRun {
+			fmt.Printf("  %s %s %s\n", style.Warning.Render("~"), relPath, style.Dim.Render("(would update)"))
+		} else {
+			fmt.Printf("  %s %s %s\n", style.Success.Render("✓"), relPath, style.Dim.Render("(updated)"))
+		}
+	case syncUnchanged:
+		fmt.Printf("  %s %s %s\n", style.Dim.Render("·"), relPath, style.Dim.Render("(unchanged)"))
+	}
+}
+
+// nativeHooks are the provider-native hook files of a target whose role
+// runs an agent other than Claude Code.
+type nativeHooks struct {
+	provider   string
+	translator *hooks.Translator
+	expected   *hooks.HooksConfig
+	paths      []string // one per agent working directory
+	townRoot   string
+}
+
+// resolveNativeHooks returns the native hook files for a target, or nil if
+// its role runs Claude Code (or an agent without hooks).
+func resolveNativeHooks(townRoot string, target hooks.Target) (*nativeHooks, error) {
+	rigPath := ""
+	if target.Rig != "" {
+		rigPath = filepath.Join(townRoot, target.Rig)
+	}
+	rc := config.ResolveRoleAgentConfig(target.Role, townRoot, rigPath)
+	if rc == nil || rc.Hooks == nil {
+		return nil, nil
+	}
+	provider := rc.Hooks.Provider
+	if provider == "" || provider == "none" || provider == "claude" {
+		return nil, nil
+	}
+	tr, err := hooks.TranslatorFor(provider)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := hooks.ComputeExpected(target.Key)
+	if err != nil {
+		return nil, fmt.Errorf("computing expected config: %w", err)
+	}
+	native := &nativeHooks{provider: provider, translator: tr, expected: expected, townRoot: townRoot}
+	for _, dir := range target.WorkDirs() {
+		native.paths = append(native.paths, tr.Path(dir, rc.Hooks.Dir, rc.Hooks.SettingsFile))
+	}
+	return native, nil
+}
+
+// excludeNativeHooks keeps a native hook file written into a project clone
+// (such as Copilot's .github/hooks/gastown.json) out of the project's
+// commits by listing it in the repository's info/exclude. Files outside a
+// git work tree, or in the town's own repository, are left alone.
+func excludeNativeHooks(townRoot, path string) error {
+	// git reports the work tree with symlinks resolved.
+	dir, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	g := git.NewGit(dir)
+	top, err := g.TopLevel()
+	if err != nil {
+		return nil // not in a git work tree
+	}
+	if town, err := filepath.EvalSymlinks(townRoot); err == nil && town == top {
+		return nil
+	}
+	rel, err := filepath.Rel(top, filepath.Join(dir, filepath.Base(path)))
+	if err != nil {
+		return err
+	}
+	return g.Exclude(rel)
+}
+
+// nativeSyncResult is the outcome for one native hook file.
+type nativeSyncResult struct {
+	path   string
+	result syncResult
+}
+
+// syncNativeHooks renders a target's hooks into each native file. It
+// returns the hooks the provider can't express alongside the results.
+func syncNativeHooks(native *nativeHooks, dryRun bool) ([]nativeSyncResult, []hooks.Unsupported, error) {
+	var results []nativeSyncResult
+	var unsupported []hooks.Unsupported
+	for _, path := range native.paths {
+		current, err := os.ReadFile(path)
+		fileExists := err == nil
+		if err != nil && !os.IsNotExist(err) {
+			return results, unsupported, err
+		}
+		data, skipped, err := native.translator.Render(native.expected, current)
+		if err != nil {
+			return results, unsupported, fmt.Errorf("%s: %w", path, err)
+		}
+		unsupported = skipped
+
+		result := syncCreated
+		switch {
+		case fileExists && bytes.Equal(current, data):
+			result = syncUnchanged
+		case fileExists:
+			result = syncUpdated
+		}
+		if result != syncUnchanged && !dryRun {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return results, unsupported, fmt.Errorf("creating hooks directory: %w", err)
+			}
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				return results, unsupported, fmt.Errorf("writing %s: %w", path, err)
+			}
+		}
+		if !dryRun {
+			if err := excludeNativeHooks(native.townRoot, path); err != nil {
+				return results, unsupported, fmt.Errorf("excluding %s from git: %w", path, err)
+			}
+		}
+		results = append(results, nativeSyncResult{path: path, result: result})
+	}
+	if len(native.paths) == 0 {
+		_, unsupported = native.translator.Supported(native.expected)
+	}
+	return results, unsupported, nil
+}
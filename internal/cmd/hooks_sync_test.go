@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -297,3 +298,43 @@ func TestRunHooksSyncFailsClosedOnIntegrityViolation(t *testing.T) {
 		t.Fatalf("expected fail-closed error, got: %v", err)
 	}
 }
+
+func TestSyncNativeHooksExcludesFromProjectRepo(t *testing.T) {
+	townRoot := t.TempDir()
+	clone := filepath.Join(townRoot, "gastown", "crew", "max")
+	if err := os.MkdirAll(clone, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "-C", clone, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+
+	tr, err := hooks.TranslatorFor("copilot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	native := &nativeHooks{
+		provider:   "copilot",
+		translator: tr,
+		expected: &hooks.HooksConfig{
+			SessionStart: []hooks.HookEntry{{Hooks: []hooks.Hook{{Type: "command", Command: "gt prime"}}}},
+		},
+		paths:    []string{tr.Path(clone, "", "")},
+		townRoot: townRoot,
+	}
+	if _, _, err := syncNativeHooks(native, false); err != nil {
+		t.Fatalf("syncNativeHooks: %v", err)
+	}
+
+	exclude, err := os.ReadFile(filepath.Join(clone, ".git", "info", "exclude"))
+	if err != nil || !strings.Contains(string(exclude), "/.github/hooks/gastown.json\n") {
+		t.Errorf("info/exclude = %q, %v; want the hook file listed", exclude, err)
+	}
+	status, err := exec.Command("git", "-C", clone, "status", "--porcelain").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(strings.TrimSpace(string(status))) != 0 {
+		t.Errorf("hook file shows up in git status: %q", status)
+	}
+}
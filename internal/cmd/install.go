@@ -274,6 +274,82 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		fmt.Printf("   • mayor/rigs.json already exists, preserving\n")
 	}
 
+	if err := scaffoldTown(absPath, townScaffoldOptions{
+		Git:     installGit,
+		GitHub:  installGitHub,
+		Private: !installPublic,
+		NoBeads: installNoBeads,
+	}); err != nil {
+		return err
+	}
+
+	if installShell {
+		fmt.Println()
+		if err := shell.Install(); err != nil {
+			fmt.Printf("   %s Could not install shell integration: %v\n", style.Dim.Render("⚠"), err)
+		} else {
+			fmt.Printf("   ✓ Installed shell integration (%s)\n", shell.RCFilePath(shell.DetectShell()))
+		}
+		if err := state.Enable(Version); err != nil {
+			fmt.Printf("   %s Could not enable Gas Town: %v\n", style.Dim.Render("⚠"), err)
+		} else {
+			fmt.Printf("   ✓ Enabled Gas Town globally\n")
+		}
+	}
+
+	if installWrappers {
+		fmt.Println()
+		if err := wrappers.Install(); err != nil {
+			fmt.Printf("   %s Could not install wrapper scripts: %v\n", style.Dim.Render("⚠"), err)
+		} else {
+			fmt.Printf("   ✓ Installed gt-codex and gt-opencode to %s\n", wrappers.BinDir())
+		}
+	}
+
+	// Configure supervisor (launchd/systemd) for daemon auto-restart
+	if installSupervisor {
+		fmt.Println()
+		if msg, err := templates.ProvisionSupervisor(absPath); err != nil {
+			fmt.Printf("   %s Could not configure supervisor: %v\n", style.Dim.Render("⚠"), err)
+		} else {
+			fmt.Printf("   ✓ %s\n", msg)
+		}
+	}
+
+	fmt.Printf("\n%s HQ created successfully!\n", style.Bold.Render("✓"))
+	fmt.Println()
+	fmt.Println("Next steps:")
+	step := 1
+	if !installGit && installGitHub == "" {
+		fmt.Printf("  %d. Initialize git: %s\n", step, style.Dim.Render("gt git-init"))
+		step++
+	}
+	fmt.Printf("  %d. Add a rig: %s\n", step, style.Dim.Render("gt rig add <name> <git-url>"))
+	step++
+	fmt.Printf("  %d. (Optional) Configure agents: %s\n", step, style.Dim.Render("gt config agent list"))
+	step++
+	fmt.Printf("  %d. Enter the Mayor's office: %s\n", step, style.Dim.Render("gt mayor attach"))
+	fmt.Println()
+	fmt.Printf("Note: Dolt server is running (stop with %s)\n", style.Dim.Render("gt dolt stop"))
+
+	return nil
+}
+
+// townScaffoldOptions controls scaffoldTown.
+type townScaffoldOptions struct {
+	Git     bool   // initialize a git repo for the HQ
+	GitHub  string // also create a GitHub repo (owner/name)
+	Private bool   // make the GitHub repo private
+	NoBeads bool   // skip the HQ Dolt database and town beads
+}
+
+// scaffoldTown lays down everything a town needs around mayor/town.json and
+// mayor/rigs.json: the root CLAUDE.md, mayor/deacon/boot directories and
+// settings, plugins/, daemon.json, the git harness, the HQ Dolt database and
+// town beads (agent beads, formulas, routing), the overseer identity,
+// escalation config, slash commands and hook settings. Files that already
+// exist are kept, so gt town import runs it over a restored bundle.
+func scaffoldTown(absPath string, opts townScaffoldOptions) error {
 	// Create a generic CLAUDE.md at the town root as an identity anchor.
 	// Claude Code sets its CWD to the git root (~/gt/), so mayor/CLAUDE.md is
 	// not loaded directly. This town-root file ensures agents running from within
@@ -292,7 +368,7 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	// IMPORTANT: Settings must be in ~/gt/mayor/.claude/, NOT ~/gt/.claude/
 	// Settings at town root would be found by ALL agents via directory traversal,
 	// causing crew/polecat/etc to cd to town root before running commands.
-	// mayorDir already defined above
+	mayorDir := filepath.Join(absPath, "mayor")
 	if err := os.MkdirAll(mayorDir, 0755); err != nil {
 		fmt.Printf("   %s Could not create mayor directory: %v\n", style.Dim.Render("⚠"), err)
 	} else {
@@ -343,9 +419,9 @@ func runInstall(cmd *cobra.Command, args []string) error {
 
 	// Initialize git BEFORE beads so that bd can compute repository fingerprint.
 	// The fingerprint is required for the daemon to start properly.
-	if installGit || installGitHub != "" {
+	if opts.Git || opts.GitHub != "" {
 		fmt.Println()
-		if err := InitGitForHarness(absPath, installGitHub, !installPublic); err != nil {
+		if err := InitGitForHarness(absPath, opts.GitHub, opts.Private); err != nil {
 			return fmt.Errorf("git initialization failed: %w", err)
 		}
 	}
@@ -353,7 +429,7 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	// Initialize town-level beads database (optional)
 	// Town beads (hq- prefix) stores mayor mail, cross-rig coordination, and handoffs.
 	// Rig beads are separate and have their own prefixes.
-	if !installNoBeads {
+	if !opts.NoBeads {
 		// Set up Dolt: identity → init-rig hq → server start.
 		// This ordering works because InitRig falls through to `dolt init`
 		// when the server isn't running yet.
@@ -419,9 +495,12 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Create default escalation config in settings/escalation.json
+	// Create default escalation config in settings/escalation.json, keeping
+	// one that is already there (reinstalls, imported towns).
 	escalationPath := config.EscalationConfigPath(absPath)
-	if err := config.SaveEscalationConfig(escalationPath, config.NewEscalationConfig()); err != nil {
+	if _, err := os.Stat(escalationPath); err == nil {
+		fmt.Printf("   • settings/escalation.json already exists, preserving\n")
+	} else if err := config.SaveEscalationConfig(escalationPath, config.NewEscalationConfig()); err != nil {
 		fmt.Printf("   %s Could not create escalation config: %v\n", style.Dim.Render("⚠"), err)
 	} else {
 		fmt.Printf("   ✓ Created settings/escalation.json\n")
@@ -448,55 +527,6 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if installShell {
-		fmt.Println()
-		if err := shell.Install(); err != nil {
-			fmt.Printf("   %s Could not install shell integration: %v\n", style.Dim.Render("⚠"), err)
-		} else {
-			fmt.Printf("   ✓ Installed shell integration (%s)\n", shell.RCFilePath(shell.DetectShell()))
-		}
-		if err := state.Enable(Version); err != nil {
-			fmt.Printf("   %s Could not enable Gas Town: %v\n", style.Dim.Render("⚠"), err)
-		} else {
-			fmt.Printf("   ✓ Enabled Gas Town globally\n")
-		}
-	}
-
-	if installWrappers {
-		fmt.Println()
-		if err := wrappers.Install(); err != nil {
-			fmt.Printf("   %s Could not install wrapper scripts: %v\n", style.Dim.Render("⚠"), err)
-		} else {
-			fmt.Printf("   ✓ Installed gt-codex and gt-opencode to %s\n", wrappers.BinDir())
-		}
-	}
-
-	// Configure supervisor (launchd/systemd) for daemon auto-restart
-	if installSupervisor {
-		fmt.Println()
-		if msg, err := templates.ProvisionSupervisor(absPath); err != nil {
-			fmt.Printf("   %s Could not configure supervisor: %v\n", style.Dim.Render("⚠"), err)
-		} else {
-			fmt.Printf("   ✓ %s\n", msg)
-		}
-	}
-
-	fmt.Printf("\n%s HQ created successfully!\n", style.Bold.Render("✓"))
-	fmt.Println()
-	fmt.Println("Next steps:")
-	step := 1
-	if !installGit && installGitHub == "" {
-		fmt.Printf("  %d. Initialize git: %s\n", step, style.Dim.Render("gt git-init"))
-		step++
-	}
-	fmt.Printf("  %d. Add a rig: %s\n", step, style.Dim.Render("gt rig add <name> <git-url>"))
-	step++
-	fmt.Printf("  %d. (Optional) Configure agents: %s\n", step, style.Dim.Render("gt config agent list"))
-	step++
-	fmt.Printf("  %d. Enter the Mayor's office: %s\n", step, style.Dim.Render("gt mayor attach"))
-	fmt.Println()
-	fmt.Printf("Note: Dolt server is running (stop with %s)\n", style.Dim.Render("gt dolt stop"))
-
 	return nil
 }
 
@@ -20,6 +20,8 @@ var (
 	mailTo            string   // --to flag (alternative to positional arg)
 	mailSendSelf      bool
 	mailCC            []string // CC recipients
+	mailSendSelect    string   // --select: recipients from a selector
+	mailSendDryRun    bool
 	mailInboxJSON     bool
 	mailReadJSON      bool
 	mailInboxUnread   bool
@@ -106,6 +108,11 @@ Addresses:
   <rig>/           - Broadcast to a rig
   list:<name>      - Send to a mailing list (fans out to all members)
 
+With --select, the message fans out to every agent the selector matches
+instead of an address. --dry-run lists the recipients without sending.
+
+` + selectorLongHelp + `
+
 Mailing lists are defined in ~/gt/config/messaging.json and allow
 sending to multiple recipients at once. Each recipient gets their
 own copy of the message.
@@ -135,6 +142,7 @@ Examples:
   gt mail send --self -s "Handoff" -m "Context for next session"
   gt mail send greenplace/Toast -s "Update" -m "Progress report" --cc overseer
   gt mail send list:oncall -s "Alert" -m "System down"
+  gt mail send --select 'role=polecat,state=stuck' -s "Stuck?" -m "Escalate if blocked"
 
   # Read body from stdin (avoids shell quoting issues):
   gt mail send mayor/ -s "Update" --stdin <<'BODY'
@@ -472,6 +480,8 @@ func init() {
 	mailSendCmd.Flags().StringVar(&mailTo, "to", "", "Recipient address (alternative to positional argument)")
 	mailSendCmd.Flags().BoolVar(&mailSendSelf, "self", false, "Send to self (auto-detect from cwd)")
 	mailSendCmd.Flags().StringArrayVar(&mailCC, "cc", nil, "CC recipients (can be used multiple times)")
+	mailSendCmd.Flags().StringVar(&mailSendSelect, "select", "", selectorHelp+" instead of an address")
+	mailSendCmd.Flags().BoolVar(&mailSendDryRun, "dry-run", false, "With --select, list the recipients without sending")
 	_ = mailSendCmd.MarkFlagRequired("subject") // cobra flags: error only at runtime if missing
 
 	// Inbox flags
@@ -32,7 +32,14 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 
 	var to string
 
-	if mailSendSelf {
+	if mailSendSelect != "" {
+		if mailSendSelf || mailTo != "" || len(args) > 0 {
+			return fmt.Errorf("--select cannot be combined with an address, --to or --self")
+		}
+		to = "select:" + mailSendSelect
+	} else if mailSendDryRun {
+		return fmt.Errorf("--dry-run requires --select")
+	} else if mailSendSelf {
 		// Auto-detect identity from cwd
 		cwd, err := os.Getwd()
 		if err != nil {
@@ -140,7 +147,22 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	b := beads.New(townRoot)
 	resolver := mail.NewResolver(b, townRoot)
 
-	recipients, err := resolver.Resolve(to)
+	var recipients []mail.Recipient
+	if mailSendSelect != "" {
+		recipients, err = selectMailRecipients(townRoot, mailSendSelect)
+		if err != nil {
+			return err
+		}
+		if mailSendDryRun {
+			return nil
+		}
+		if len(recipients) == 0 {
+			fmt.Printf("%s No agents match %s; nothing sent\n", style.WarningPrefix, mailSendSelect)
+			return nil
+		}
+	} else {
+		recipients, err = resolver.Resolve(to)
+	}
 	if err != nil {
 		// Validation errors are definitive — do not fall back to legacy routing,
 		// which would silently deliver to a dead inbox.
@@ -227,6 +249,28 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// selectMailRecipients resolves --select to one agent recipient per match.
+// With --dry-run it prints the matches.
+func selectMailRecipients(townRoot, selectExpr string) ([]mail.Recipient, error) {
+	sel, err := parseSelectFlag(selectExpr)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := selectAgents(townRoot, sel)
+	if err != nil {
+		return nil, err
+	}
+	if mailSendDryRun {
+		printSelectedAgents(sel, matches)
+		fmt.Printf("\nSubject: %s\n", mailSubject)
+	}
+	recipients := make([]mail.Recipient, 0, len(matches))
+	for _, a := range matches {
+		recipients = append(recipients, mail.Recipient{Address: a.Address, Type: mail.RecipientAgent})
+	}
+	return recipients, nil
+}
+
 // generateThreadID creates a random thread ID for new message threads.
 func generateThreadID() string {
 	b := make([]byte, 6)
@@ -2,14 +2,20 @@ package cmd
 
 import (
 	"fmt"
-	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/memory"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
+var memoriesStale bool
+
 func init() {
+	memoriesCmd.Flags().BoolVar(&memoriesStale, "stale", false, "Show only expired memories and memories older than operational.memory.stale_after")
 	memoriesCmd.GroupID = GroupWork
 	rootCmd.AddCommand(memoriesCmd)
 }
@@ -21,11 +27,18 @@ var memoriesCmd = &cobra.Command{
 
 Without arguments, lists all memories. With a search term, filters
 memories whose key or value contains the term (case-insensitive).
+Each memory shows its scope, author and age.
+
+Use --stale to curate: it lists memories that have expired or are older
+than operational.memory.stale_after in town settings (default 90 days).
+Refresh the ones still true with 'gt remember --key <key>' and remove the
+rest with 'gt forget <key>'.
 
 Examples:
   gt memories                    # List all memories
   gt memories refinery           # Search for memories about refinery
-  gt memories "worktree"         # Search for worktree-related memories`,
+  gt memories "worktree"         # Search for worktree-related memories
+  gt memories --stale            # Memories due for review`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runMemories,
 }
@@ -41,53 +54,76 @@ func runMemories(cmd *cobra.Command, args []string) error {
 		search = strings.ToLower(args[0])
 	}
 
-	// Filter for memory.* keys and optional search
-	type memory struct {
-		key   string
-		value string
+	var staleAfter time.Duration
+	if memoriesStale {
+		townRoot, _ := workspace.FindFromCwd()
+		staleAfter = config.LoadOperationalConfig(townRoot).GetMemoryConfig().StaleAfterD()
 	}
-	var memories []memory
 
-	for k, v := range kvs {
-		if !strings.HasPrefix(k, memoryKeyPrefix) {
+	// Filter for memory.* keys, staleness and optional search
+	now := time.Now()
+	var memories []memory.Memory
+	for _, m := range memory.FromKV(kvs) {
+		if memoriesStale && !m.Stale(now, staleAfter) {
 			continue
 		}
-		shortKey := strings.TrimPrefix(k, memoryKeyPrefix)
-
 		if search != "" {
-			if !strings.Contains(strings.ToLower(shortKey), search) &&
-				!strings.Contains(strings.ToLower(v), search) {
+			if !strings.Contains(strings.ToLower(m.Key), search) &&
+				!strings.Contains(strings.ToLower(m.Content), search) {
 				continue
 			}
 		}
-
-		memories = append(memories, memory{key: shortKey, value: v})
+		memories = append(memories, m)
 	}
 
-	sort.Slice(memories, func(i, j int) bool {
-		return memories[i].key < memories[j].key
-	})
-
 	if len(memories) == 0 {
-		if search != "" {
+		switch {
+		case memoriesStale:
+			fmt.Println("No stale memories.")
+		case search != "":
 			fmt.Printf("No memories matching %q\n", search)
-		} else {
+		default:
 			fmt.Println("No memories stored. Use 'gt remember \"insight\"' to add one.")
 		}
 		return nil
 	}
 
 	header := "Memories"
+	if memoriesStale {
+		header = "Stale memories"
+	}
 	if search != "" {
-		header = fmt.Sprintf("Memories matching %q", search)
+		header = fmt.Sprintf("%s matching %q", header, search)
 	}
 	fmt.Printf("%s (%d):\n\n", style.Bold.Render(header), len(memories))
 
 	for _, m := range memories {
-		fmt.Printf("  %s\n", style.Bold.Render(m.key))
+		fmt.Printf("  %s %s\n", style.Bold.Render(m.Key), style.Dim.Render(formatMemoryMeta(m, now)))
 		// Wrap long values for readability
-		fmt.Printf("    %s\n\n", m.value)
+		fmt.Printf("    %s\n\n", m.Content)
 	}
 
+	if memoriesStale {
+		fmt.Printf("%s\n", style.Dim.Render("Refresh with 'gt remember --key <key> ...' or remove with 'gt forget <key>'."))
+	}
 	return nil
 }
+
+// formatMemoryMeta renders a memory's scope, author, age and expiry.
+func formatMemoryMeta(m memory.Memory, now time.Time) string {
+	parts := []string{m.Scope.String()}
+	if m.Author != "" {
+		parts = append(parts, "by "+m.Author)
+	}
+	if !m.CreatedAt.IsZero() {
+		parts = append(parts, formatAge(m.CreatedAt))
+	}
+	if !m.ExpiresAt.IsZero() {
+		if m.Expired(now) {
+			parts = append(parts, "expired "+m.ExpiresAt.Local().Format("2006-01-02"))
+		} else {
+			parts = append(parts, "expires "+m.ExpiresAt.Local().Format("2006-01-02"))
+		}
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
@@ -1,13 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/refinery"
@@ -143,7 +146,11 @@ Examples:
   gt mq list greenplace
   gt mq list greenplace --ready
   gt mq list greenplace --status=open
-  gt mq list greenplace --worker=Nux`,
+  gt mq list greenplace --worker=Nux
+  gt mq list greenplace --lane=frontend
+
+Inside a merge lane refinery session (GT_REFINERY_LANE set), only the
+lane's own MRs are listed.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMQList,
 }
@@ -164,7 +171,10 @@ Examples:
 }
 
 // Post-merge flags
-var mqPostMergeSkipBranchDelete bool
+var (
+	mqPostMergeSkipBranchDelete bool
+	mqPostMergeCommit           string
+)
 
 var mqPostMergeCmd = &cobra.Command{
 	Use:   "post-merge <rig> <mr-id>",
@@ -174,14 +184,23 @@ var mqPostMergeCmd = &cobra.Command{
 This command consolidates post-merge steps into a single atomic operation:
   1. Close the MR bead (status: merged)
   2. Close the source issue
-  3. Delete the remote polecat branch (unless --skip-branch-delete)
+  3. Run merge_queue.post_merge actions (preview deploys, release tags),
+     attaching their outputs to the MR bead
+  4. Delete the remote polecat branch (unless --skip-branch-delete)
+
+Post-merge action failures are reported but never fail the command, since
+the merge has already landed. Pass --merge-commit so actions see the landed
+SHA in GT_MERGE_COMMIT. All merge action outputs recorded on the MR bead
+(from 'gt mq gate', 'gt mq land', 'gt mq speculate' and the post-merge
+actions) are printed as Output-<key> lines to copy into the MERGED mail.
 
 Designed for use by the refinery formula after a successful merge to main.
 The branch name is read from the MR bead, so no manual branch argument is needed.
 
 Examples:
   gt mq post-merge gastown gt-mr-abc123
-  gt mq post-merge gastown gt-mr-abc123 --skip-branch-delete`,
+  gt mq post-merge gastown gt-mr-abc123 --skip-branch-delete
+  gt mq post-merge gastown gt-mr-abc123 --merge-commit $(git rev-parse HEAD)`,
 	Args: cobra.ExactArgs(2),
 	RunE: runMQPostMerge,
 }
@@ -319,6 +338,7 @@ func init() {
 	mqListCmd.Flags().StringVar(&mqListEpic, "epic", "", "Show MRs targeting integration/<epic>")
 	mqListCmd.Flags().BoolVar(&mqListJSON, "json", false, "Output as JSON")
 	mqListCmd.Flags().BoolVar(&mqListVerify, "verify", false, "Verify branches exist in git (shows MISSING for deleted branches)")
+	mqListCmd.Flags().StringVar(&refineryLane, "lane", "", "Show only MRs routed to this merge lane (default: $GT_REFINERY_LANE)")
 
 	// Reject flags
 	mqRejectCmd.Flags().StringVarP(&mqRejectReason, "reason", "r", "", "Reason for rejection (required unless --stdin)")
@@ -330,6 +350,7 @@ func init() {
 
 	// Post-merge flags
 	mqPostMergeCmd.Flags().BoolVar(&mqPostMergeSkipBranchDelete, "skip-branch-delete", false, "Skip remote branch deletion")
+	mqPostMergeCmd.Flags().StringVar(&mqPostMergeCommit, "merge-commit", "", "SHA of the landed merge commit (passed to post-merge actions)")
 
 	// Add subcommands
 	mqCmd.AddCommand(mqSubmitCmd)
@@ -526,6 +547,10 @@ func runMQPostMerge(_ *cobra.Command, args []string) error {
 		fmt.Printf("  %s Source issue: %s %s\n", style.Dim.Render("○"), result.SourceIssueID, style.Dim.Render("(already closed or not found)"))
 	}
 
+	// Run configured post-merge actions (preview deploys, release tags)
+	runMQPostMergeActions(r, mr)
+	printMergedOutputs(r, mr.ID)
+
 	// Delete remote branch unless skipped
 	if mr.Branch == "" {
 		fmt.Printf("  %s No branch name in MR (skipping branch delete)\n", style.Dim.Render("○"))
@@ -575,3 +600,77 @@ func runMQPostMerge(_ *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// printMergedOutputs prints the merge action outputs recorded on the MR bead
+// (pre-merge and post-merge) as MERGED mail fields, so the patrol can pass
+// them to the witness.
+func printMergedOutputs(r *rig.Rig, mrID string) {
+	mrBead, err := beads.New(r.BeadsPath()).Show(mrID)
+	if err != nil {
+		return
+	}
+	fields := beads.ParseMRFields(mrBead)
+	if fields == nil || len(fields.Outputs) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(fields.Outputs))
+	for k := range fields.Outputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Printf("  Outputs (add to the MERGED mail):\n")
+	for _, k := range keys {
+		fmt.Printf("    Output-%s: %s\n", k, fields.Outputs[k])
+	}
+}
+
+// runMQPostMergeActions runs the rig's merge_queue.post_merge actions for a
+// merged MR and attaches their outputs to the MR bead. Failures are reported
+// but never fail the command: the merge has already landed.
+func runMQPostMergeActions(r *rig.Rig, mr *refinery.MergeRequest) {
+	e := refinery.NewEngineer(r)
+	if err := e.LoadConfig(); err != nil {
+		fmt.Printf("  %s post-merge actions: loading config: %v\n", style.Warning.Render("⚠"), err)
+		return
+	}
+	if len(e.Config().PostMerge) == 0 {
+		return
+	}
+
+	b := beads.New(r.BeadsPath())
+	mrBead, err := b.Show(mr.ID)
+	if err != nil {
+		fmt.Printf("  %s post-merge actions: fetching MR bead: %v\n", style.Warning.Render("⚠"), err)
+		return
+	}
+	mrFields := beads.ParseMRFields(mrBead)
+	if mrFields == nil {
+		mrFields = &beads.MRFields{}
+	}
+
+	mergeCommit := mqPostMergeCommit
+	if mergeCommit == "" {
+		mergeCommit = mrFields.MergeCommit
+	}
+	e.SetOutput(io.Discard)
+	result := e.RunPostMergeActions(context.Background(), refinery.MergeActionEnv{
+		MRID:        mr.ID,
+		Branch:      mr.Branch,
+		Target:      mr.TargetBranch,
+		SourceIssue: mr.IssueID,
+		Worker:      mr.Worker,
+		MergeCommit: mergeCommit,
+	})
+
+	printMQWarnings(refinery.PhasePostMerge, result.Warnings)
+	if result.Blocked != "" {
+		fmt.Printf("  %s post-merge actions stopped: %s\n", style.Warning.Render("⚠"), result.Blocked)
+	}
+	if len(result.Warnings) == 0 && result.Blocked == "" {
+		fmt.Printf("  %s Post-merge actions passed (%d)\n", style.Success.Render("✓"), len(e.Config().PostMerge))
+	}
+	if len(result.Outputs) == 0 {
+		return
+	}
+	attachMROutputs(b, mr.ID, result.Outputs, mergeCommit)
+}
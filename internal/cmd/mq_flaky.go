@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ flaky command flags
+var (
+	mqFlakyLimit int
+	mqFlakyJSON  bool
+)
+
+var mqFlakyCmd = &cobra.Command{
+	Use:   "flaky <rig>",
+	Short: "Show the flakiest tests seen by the refinery",
+	Long: `Show the worst flaky tests recorded by the rig's refinery.
+
+The refinery records per-test outcomes for gates that emit JUnit XML or
+'go test -json' output. A test that fails and then passes when the gate is
+retried (retry_flaky_tests) counts as a flake. Tests that meet the
+merge_queue.flaky thresholds are known-flaky: failures caused only by
+known-flaky tests don't send the polecat back for rework.
+
+The MRS column lists the most recent MRs that hit each failure; inspect
+them with 'gt mq status <mr-id>'.
+
+Examples:
+  gt mq flaky gastown              # Top 10 flaky tests
+  gt mq flaky gastown --limit 25   # Top 25
+  gt mq flaky gastown --json       # Output as JSON`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQFlaky,
+}
+
+func init() {
+	mqFlakyCmd.Flags().IntVarP(&mqFlakyLimit, "limit", "n", 10, "Maximum number of tests to show (0 for all)")
+	mqFlakyCmd.Flags().BoolVar(&mqFlakyJSON, "json", false, "Output as JSON")
+
+	mqCmd.AddCommand(mqFlakyCmd)
+}
+
+// flakyTestJSON is the JSON output row for gt mq flaky.
+type flakyTestJSON struct {
+	*refinery.TestHistory
+	FlakeRate  float64 `json:"flake_rate"`
+	KnownFlaky bool    `json:"known_flaky"`
+}
+
+func runMQFlaky(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	// Load merge queue config for the known-flaky thresholds.
+	e := refinery.NewEngineer(r)
+	if err := e.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	cfg := e.Config().Flaky
+
+	history, err := refinery.LoadFlakyHistory(refinery.FlakyHistoryPath(r))
+	if err != nil {
+		return fmt.Errorf("loading flaky history: %w", err)
+	}
+	worst := history.Worst(mqFlakyLimit)
+
+	if mqFlakyJSON {
+		rows := make([]flakyTestJSON, 0, len(worst))
+		for _, th := range worst {
+			rows = append(rows, flakyTestJSON{TestHistory: th, FlakeRate: th.FlakeRate(), KnownFlaky: th.IsFlaky(cfg)})
+		}
+		return outputJSON(rows)
+	}
+
+	fmt.Printf("%s Flaky tests for '%s':\n\n", style.Bold.Render("🎲"), rigName)
+	if len(worst) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no flakes recorded)"))
+		return nil
+	}
+
+	table := style.NewTable(
+		style.Column{Name: "TEST", Width: 48},
+		style.Column{Name: "GATE", Width: 10},
+		style.Column{Name: "FLAKES", Width: 6, Align: style.AlignRight},
+		style.Column{Name: "RUNS", Width: 5, Align: style.AlignRight},
+		style.Column{Name: "RATE", Width: 5, Align: style.AlignRight},
+		style.Column{Name: "LAST", Width: 6, Align: style.AlignRight},
+		style.Column{Name: "MRS", Width: 30},
+	)
+	for _, th := range worst {
+		rate := fmt.Sprintf("%.0f%%", th.FlakeRate()*100)
+		if th.IsFlaky(cfg) {
+			rate = style.Warning.Render(rate)
+		}
+		last := ""
+		if !th.LastFlakeAt.IsZero() {
+			last = formatMRAge(th.LastFlakeAt.Format(time.RFC3339))
+		}
+		table.AddRow(th.Test, th.Gate,
+			fmt.Sprintf("%d", th.Flakes), fmt.Sprintf("%d", th.Runs), rate,
+			style.Dim.Render(last), strings.Join(th.MRs, ", "))
+	}
+	fmt.Print(table.Render())
+	fmt.Printf("\n  %s\n", style.Dim.Render("Highlighted rates are known-flaky. Inspect an MR with 'gt mq status <mr-id>'."))
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var mqGateCmd = &cobra.Command{
+	Use:   "gate <rig> <mr-id>",
+	Short: "Run merge queue gates and pre-merge actions on the work branch",
+	Long: `Verify the commit checked out in the refinery worktree before landing it.
+
+Run by the refinery patrol's run-tests step, from the refinery (or lane)
+worktree with the rebased work branch checked out. It runs:
+  1. The merge_queue gates from the rig's config.json (or its legacy
+     test_command), recording per-test outcomes for 'gt mq flaky'
+  2. The merge_queue.pre_merge actions against HEAD (GT_MERGE_COMMIT)
+
+Pre-merge action outputs are printed and attached to the MR bead. Exits
+non-zero if a gate or a blocking pre-merge action fails; nothing is pushed
+either way. Passes trivially when no gates or actions are configured.
+
+On failure it prints the FailureType (and Flaky-Tests, if any) for the
+MERGE_FAILED mail. FailureType "flaky" means only known-flaky tests failed:
+the MR stays queued without rework, up to merge_queue.flaky.max_requeues
+times. Past that the refinery escalates and reports "tests".
+
+Examples:
+  gt mq gate gastown gt-mr-abc123`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQGate,
+}
+
+func init() {
+	mqGateCmd.Flags().StringVar(&refineryLane, "lane", "", "Merge lane (default: $GT_REFINERY_LANE)")
+
+	mqCmd.AddCommand(mqGateCmd)
+}
+
+func runMQGate(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	mrID := args[1]
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	eng, err := newRefineryEngineer(r)
+	if err != nil {
+		return err
+	}
+	b := beads.New(r.BeadsPath())
+	env, err := mqActionEnv(b, mrID)
+	if err != nil {
+		return err
+	}
+	if env.Target == "" {
+		env.Target = r.DefaultBranch()
+	}
+
+	result := eng.Gate(context.Background(), env)
+	printMQWarnings(refinery.PhasePreMerge, result.Warnings)
+	if len(result.Outputs) > 0 {
+		attachMROutputs(b, mrID, result.Outputs, "")
+	}
+	if !result.Success {
+		// Fields for the patrol's MERGE_FAILED mail to the witness.
+		fmt.Printf("FailureType: %s\n", eng.GateFailureType(mrID, result))
+		if len(result.FlakyTests) > 0 {
+			fmt.Printf("Flaky-Tests: %s\n", strings.Join(result.FlakyTests, ", "))
+		}
+		return fmt.Errorf("gating %s: %s", mrID, result.Error)
+	}
+
+	fmt.Printf("%s Gates and pre-merge actions passed for %s\n", style.Bold.Render("✓"), mrID)
+	return nil
+}
+
+// mqActionEnv describes an MR bead to the refinery's merge actions.
+func mqActionEnv(b *beads.Beads, mrID string) (refinery.MergeActionEnv, error) {
+	issue, err := b.Show(mrID)
+	if err != nil {
+		return refinery.MergeActionEnv{}, fmt.Errorf("fetching MR %s: %w", mrID, err)
+	}
+	env := refinery.MergeActionEnv{MRID: mrID}
+	if fields := beads.ParseMRFields(issue); fields != nil {
+		env.Branch = fields.Branch
+		env.Target = fields.Target
+		env.SourceIssue = fields.SourceIssue
+		env.Worker = fields.Worker
+	}
+	return env, nil
+}
+
+// printMQWarnings reports failed warning-severity merge actions.
+func printMQWarnings(phase string, warnings []string) {
+	for _, w := range warnings {
+		fmt.Printf("  %s %s action %s\n", style.Warning.Render("⚠"), phase, w)
+	}
+}
+
+// attachMROutputs prints merge action outputs and records them (and the
+// landed commit, if known) on the MR bead. Failures are reported, not
+// returned: the outputs are informational.
+func attachMROutputs(b *beads.Beads, mrID string, outputs map[string]string, mergeCommit string) {
+	keys := make([]string, 0, len(outputs))
+	for k := range outputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Printf("    %s: %s\n", k, outputs[k])
+	}
+
+	mrBead, err := b.Show(mrID)
+	if err != nil {
+		fmt.Printf("  %s attaching outputs to MR: %v\n", style.Warning.Render("⚠"), err)
+		return
+	}
+	mrFields := beads.ParseMRFields(mrBead)
+	if mrFields == nil {
+		mrFields = &beads.MRFields{}
+	}
+	if mrFields.Outputs == nil {
+		mrFields.Outputs = make(map[string]string, len(outputs))
+	}
+	for k, v := range outputs {
+		mrFields.Outputs[k] = v
+	}
+	if mergeCommit != "" {
+		mrFields.MergeCommit = mergeCommit
+	}
+	newDesc := beads.SetMRFields(mrBead, mrFields)
+	if err := b.Update(mrID, beads.UpdateOptions{Description: &newDesc}); err != nil {
+		fmt.Printf("  %s attaching outputs to MR: %v\n", style.Warning.Render("⚠"), err)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ land command flags
+var (
+	mqLandTarget string
+)
+
+var mqLandCmd = &cobra.Command{
+	Use:   "land <rig> <mr-id>",
+	Short: "Push the tested merge commit to its target branch",
+	Long: `Push the commit checked out in the refinery worktree to the MR's target.
+
+This is the push half of the refinery patrol's merge-push step. Run it from
+the refinery (or lane) worktree with the rebased, tested work branch checked
+out. It:
+  1. Acquires the merge slot (default-branch pushes only)
+  2. Rebases HEAD onto the latest origin/<target>, re-running the
+     merge_queue gates and pre_merge actions (as 'gt mq gate' does) if
+     another refinery lane landed in the meantime
+  3. Pushes HEAD to origin/<target> by refspec
+
+The target branch is never checked out, so merge lanes can share a repo.
+The landed commit SHA is printed on the last line (pass it to
+'gt mq post-merge --merge-commit').
+
+The target defaults to the MR bead's target branch, falling back to the rig's
+default branch.
+
+Examples:
+  gt mq land gastown gt-mr-abc123
+  gt mq land gastown gt-mr-abc123 --target integration/gt-epic`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQLand,
+}
+
+func init() {
+	mqLandCmd.Flags().StringVar(&mqLandTarget, "target", "", "Target branch (default: MR target, then rig default branch)")
+	mqLandCmd.Flags().StringVar(&refineryLane, "lane", "", "Merge lane (default: $GT_REFINERY_LANE)")
+
+	mqCmd.AddCommand(mqLandCmd)
+}
+
+func runMQLand(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	mrID := args[1]
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	eng, err := newRefineryEngineer(r)
+	if err != nil {
+		return err
+	}
+
+	b := beads.New(r.BeadsPath())
+	env, err := mqActionEnv(b, mrID)
+	if err != nil {
+		return err
+	}
+	if mqLandTarget != "" {
+		env.Target = mqLandTarget
+	}
+	if env.Target == "" {
+		env.Target = r.DefaultBranch()
+	}
+	target := env.Target
+
+	result := eng.Land(context.Background(), env)
+	printMQWarnings(refinery.PhasePreMerge, result.Warnings)
+	if len(result.Outputs) > 0 {
+		attachMROutputs(b, mrID, result.Outputs, "")
+	}
+	if !result.Success {
+		if result.Conflict {
+			return fmt.Errorf("landing %s on %s: conflict: %s", mrID, target, result.Error)
+		}
+		return fmt.Errorf("landing %s on %s: %s", mrID, target, result.Error)
+	}
+
+	fmt.Printf("%s Landed %s on origin/%s\n", style.Bold.Render("✓"), mrID, target)
+	fmt.Println(result.MergeCommit)
+	return nil
+}
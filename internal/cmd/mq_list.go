@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -74,6 +75,12 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// In a lane refinery (or with --lane), only show the lane's own MRs.
+	inLane, err := mqLaneFilter(r)
+	if err != nil {
+		return err
+	}
+
 	// Apply additional filters and calculate scores
 	now := time.Now()
 	type scoredIssue struct {
@@ -102,6 +109,10 @@ func runMQList(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		if !inLane(issue) {
+			continue
+		}
+
 		// Parse MR fields
 		fields := beads.ParseMRFields(issue)
 
@@ -424,3 +435,28 @@ func verifyBranch(verify bool, client branchVerifier, fields *beads.MRFields) (b
 	}
 	return false, false
 }
+
+// mqLaneFilter returns a predicate selecting the MRs routed to the merge lane
+// chosen by --lane or GT_REFINERY_LANE. With no lane selected every MR passes.
+// MRs whose path routing fails are shown by their label-only lane, with a
+// warning so the failure is not silent.
+func mqLaneFilter(r *rig.Rig) (func(*beads.Issue) bool, error) {
+	lane, err := resolveRefineryLane(r)
+	if err != nil {
+		return nil, err
+	}
+	if lane == "" {
+		return func(*beads.Issue) bool { return true }, nil
+	}
+	eng, err := newRefineryEngineer(r)
+	if err != nil {
+		return nil, err
+	}
+	return func(issue *beads.Issue) bool {
+		got, err := eng.LaneForIssue(issue)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v (using lane %q)\n", style.Warning.Render("⚠"), err, got)
+		}
+		return got == lane
+	}, nil
+}
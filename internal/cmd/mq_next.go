@@ -43,6 +43,7 @@ func init() {
 	mqNextCmd.Flags().StringVar(&mqNextStrategy, "strategy", "priority", "Ordering strategy: 'priority' or 'fifo'")
 	mqNextCmd.Flags().BoolVar(&mqNextJSON, "json", false, "Output as JSON")
 	mqNextCmd.Flags().BoolVarP(&mqNextQuiet, "quiet", "q", false, "Just print the MR ID")
+	mqNextCmd.Flags().StringVar(&refineryLane, "lane", "", "Only consider MRs routed to this merge lane (default: $GT_REFINERY_LANE)")
 
 	mqCmd.AddCommand(mqNextCmd)
 }
@@ -70,13 +71,21 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("querying merge queue: %w", err)
 	}
 
-	// Filter to only ready MRs (no blockers)
+	inLane, err := mqLaneFilter(r)
+	if err != nil {
+		return err
+	}
+
+	// Filter to only ready MRs (no blockers) in this refinery's lane
 	var ready []*beads.Issue
 	for _, issue := range issues {
 		// Skip closed MRs (workaround for bd list not respecting --status filter)
 		if issue.Status != "open" {
 			continue
 		}
+		if !inLane(issue) {
+			continue
+		}
 		if len(issue.BlockedBy) == 0 && issue.BlockedByCount == 0 {
 			ready = append(ready, issue)
 		}
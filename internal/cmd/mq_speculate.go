@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var mqSpeculateCmd = &cobra.Command{
+	Use:   "speculate <rig>",
+	Short: "Gate and land ready MRs with speculative pipelines",
+	Long: `Drain the ready queue with Zuul-style speculative pipelines.
+
+Enabled by merge_queue.speculative.depth > 1 in the rig's config.json;
+otherwise this prints that speculation is disabled and does nothing. The
+refinery patrol runs it from queue-scan before processing MRs one at a time.
+
+The ready MRs for the highest-scored MR's target branch are stacked as a
+chain of squash commits (target+1, target+1+2, ...). Each commit is verified
+as 'gt mq gate' would (gates, then pre_merge actions) in its own worktree,
+depth commits at a time, and passing commits are pushed in queue order as
+fast-forwards. When an MR fails, the pipelines stacked on it are restarted
+without it.
+
+One line is printed per MR it settled:
+  MERGED <mr-id> <merge-commit>   landed; run 'gt mq post-merge' and send MERGED
+  FAILED <mr-id>                  followed by FailureType (and Flaky-Tests)
+                                  lines for the MERGE_FAILED mail
+
+MRs that are not listed (other targets, or MRs that did not stack cleanly
+on the MRs ahead of them) stay queued for the one-at-a-time path.
+
+Examples:
+  gt mq speculate gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQSpeculate,
+}
+
+func init() {
+	mqSpeculateCmd.Flags().StringVar(&refineryLane, "lane", "", "Merge lane (default: $GT_REFINERY_LANE)")
+
+	mqCmd.AddCommand(mqSpeculateCmd)
+}
+
+func runMQSpeculate(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	eng, err := newRefineryEngineer(r)
+	if err != nil {
+		return err
+	}
+	spec := eng.Config().Speculative
+	if spec == nil || spec.Depth <= 1 {
+		fmt.Println("Speculative pipelines disabled (merge_queue.speculative.depth <= 1)")
+		return nil
+	}
+
+	ready, err := eng.ListReadyMRs()
+	if err != nil {
+		return err
+	}
+	queue, target := speculativeQueue(ready, r.DefaultBranch())
+	if len(queue) < 2 {
+		fmt.Println("Fewer than two ready MRs; nothing to speculate on")
+		return nil
+	}
+
+	result := eng.ProcessSpeculative(context.Background(), queue, target, spec)
+
+	b := beads.New(r.BeadsPath())
+	for _, mr := range result.Merged {
+		mrResult := result.Results[mr.ID]
+		printMQWarnings(refinery.PhasePreMerge, mrResult.Warnings)
+		if len(mrResult.Outputs) > 0 {
+			attachMROutputs(b, mr.ID, mrResult.Outputs, "")
+		}
+		fmt.Printf("MERGED %s %s\n", mr.ID, mrResult.MergeCommit)
+	}
+	for _, mr := range result.Culprits {
+		mrResult := result.Results[mr.ID]
+		printMQWarnings(refinery.PhasePreMerge, mrResult.Warnings)
+		fmt.Printf("FAILED %s\n", mr.ID)
+		fmt.Printf("FailureType: %s\n", eng.GateFailureType(mr.ID, mrResult))
+		if len(mrResult.FlakyTests) > 0 {
+			fmt.Printf("Flaky-Tests: %s\n", strings.Join(mrResult.FlakyTests, ", "))
+		}
+	}
+
+	if result.Error != nil {
+		return fmt.Errorf("speculative pipelines stopped: %w", result.Error)
+	}
+	fmt.Printf("%s Speculated on %d MR(s): %d merged, %d failed, %d left queued (%d restart(s), %d cached)\n",
+		style.Bold.Render("✓"), len(queue), len(result.Merged), len(result.Culprits),
+		len(queue)-len(result.Merged)-len(result.Culprits), result.Restarts, result.CacheHits)
+	return nil
+}
+
+// speculativeQueue orders ready MRs by score and keeps those sharing the
+// highest-scored MR's target, since a speculative chain lands on one branch.
+func speculativeQueue(ready []*refinery.MRInfo, defaultBranch string) ([]*refinery.MRInfo, string) {
+	if len(ready) == 0 {
+		return nil, ""
+	}
+	now := time.Now()
+	sorted := append([]*refinery.MRInfo{}, ready...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ScoreAt(now) > sorted[j].ScoreAt(now)
+	})
+
+	targetOf := func(mr *refinery.MRInfo) string {
+		if mr.Target == "" {
+			return defaultBranch
+		}
+		return mr.Target
+	}
+	target := targetOf(sorted[0])
+	var queue []*refinery.MRInfo
+	for _, mr := range sorted {
+		if targetOf(mr) == target {
+			queue = append(queue, mr)
+		}
+	}
+	return queue, target
+}
@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
 )
 
 func TestParseBranchName(t *testing.T) {
@@ -424,3 +426,29 @@ func TestMRFilteringByLabel(t *testing.T) {
 		})
 	}
 }
+
+func TestSpeculativeQueue(t *testing.T) {
+	created := time.Now().Add(-time.Hour)
+	ready := []*refinery.MRInfo{
+		{ID: "mr-low", Priority: 3, CreatedAt: created},
+		{ID: "mr-epic", Priority: 0, Target: "integration/gt-epic", CreatedAt: created},
+		{ID: "mr-main", Priority: 1, Target: "main", CreatedAt: created},
+		{ID: "mr-top", Priority: 0, CreatedAt: created.Add(-time.Minute)},
+	}
+
+	queue, target := speculativeQueue(ready, "main")
+	if target != "main" {
+		t.Fatalf("target = %q, want main", target)
+	}
+	var ids []string
+	for _, mr := range queue {
+		ids = append(ids, mr.ID)
+	}
+	if got := strings.Join(ids, ","); got != "mr-top,mr-main,mr-low" {
+		t.Errorf("queue = %s, want mr-top,mr-main,mr-low (score order, main only)", got)
+	}
+
+	if queue, target := speculativeQueue(nil, "main"); queue != nil || target != "" {
+		t.Errorf("speculativeQueue(nil) = %v, %q, want empty", queue, target)
+	}
+}
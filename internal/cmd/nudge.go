@@ -29,6 +29,8 @@ var (
 	nudgeIfFreshFlag  bool
 	nudgeModeFlag     string
 	nudgePriorityFlag string
+	nudgeSelectFlag   string
+	nudgeDryRunFlag   bool
 )
 
 // Nudge delivery modes.
@@ -52,6 +54,8 @@ func init() {
 	nudgeCmd.Flags().BoolVar(&nudgeIfFreshFlag, "if-fresh", false, "Only send if caller's tmux session is <60s old (suppresses compaction nudges)")
 	nudgeCmd.Flags().StringVar(&nudgeModeFlag, "mode", NudgeModeImmediate, "Delivery mode: immediate (default), queue, or wait-idle")
 	nudgeCmd.Flags().StringVar(&nudgePriorityFlag, "priority", nudge.PriorityNormal, "Queue priority: normal (default) or urgent")
+	nudgeCmd.Flags().StringVar(&nudgeSelectFlag, "select", "", selectorHelp+" instead of a <target>")
+	nudgeCmd.Flags().BoolVar(&nudgeDryRunFlag, "dry-run", false, "With --select, list the matched agents without nudging")
 }
 
 var nudgeCmd = &cobra.Command{
@@ -93,6 +97,8 @@ Channel syntax:
                   ~/gt/config/messaging.json under "nudge_channels".
                   Patterns like "gastown/polecats/*" are expanded.
 
+` + selectorLongHelp + `
+
 DND (Do Not Disturb):
   If the target has DND enabled (gt dnd on), the nudge is skipped.
   Use --force to override DND and send anyway.
@@ -104,6 +110,8 @@ Examples:
   gt nudge witness "Check polecat health"
   gt nudge deacon session-started
   gt nudge channel:workers "New priority work available"
+  gt nudge --select 'role=polecat,rig=web,idle>30m' "Still working?"
+  gt nudge --select 'hook~gt-abc' --dry-run -m "x"
 
   # Use --stdin for messages with special characters or formatting:
   gt nudge gastown/alpha --stdin <<'EOF'
@@ -111,7 +119,13 @@ Examples:
   - Task 1: complete
   - Task 2: in progress
   EOF`,
-	Args: cobra.RangeArgs(1, 2),
+	Args: func(cmd *cobra.Command, args []string) error {
+		// With --select the selector replaces <target>.
+		if nudgeSelectFlag != "" {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.RangeArgs(1, 2)(cmd, args)
+	},
 	RunE: runNudge,
 }
 
@@ -124,10 +138,10 @@ const ifFreshMaxAge = 60 * time.Second
 var waitIdleTimeout = 15 * time.Second
 
 // deliverNudge routes a nudge based on the --mode flag.
-// For "immediate" mode: sends directly via tmux (current behavior).
+// For "immediate" mode: sends directly to the session (current behavior).
 // For "queue" mode: writes to the nudge queue for cooperative delivery.
 // For "wait-idle" mode: waits for idle, then delivers or falls back to queue.
-func deliverNudge(t *tmux.Tmux, sessionName, message, sender string) error {
+func deliverNudge(t session.Backend, sessionName, message, sender string) error {
 	townRoot, _ := workspace.FindFromCwd()
 
 	// For direct tmux delivery, prefix with sender attribution.
@@ -152,8 +166,11 @@ func deliverNudge(t *tmux.Tmux, sessionName, message, sender string) error {
 			// rather than silently degrading to immediate (destructive) delivery.
 			return fmt.Errorf("--mode=wait-idle requires a Gas Town workspace")
 		}
-		// Try to wait for idle
-		err := t.WaitForIdle(sessionName, waitIdleTimeout)
+		// Try to wait for idle. Backends that can't detect idleness queue.
+		err := tmux.ErrIdleTimeout
+		if w, ok := t.(session.IdleWaiter); ok {
+			err = w.WaitForIdle(sessionName, waitIdleTimeout)
+		}
 		if err == nil {
 			// Agent is idle — safe to deliver directly
 			return t.NudgeSession(sessionName, prefixedMessage)
@@ -227,7 +244,12 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
-	target := args[0]
+	// With --select there is no <target>: every argument is the message.
+	var target string
+	msgArgs := args
+	if nudgeSelectFlag == "" {
+		target, msgArgs = args[0], args[1:]
+	}
 
 	// Handle --stdin: read message from stdin (avoids shell quoting issues)
 	if nudgeStdinFlag {
@@ -245,8 +267,8 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 	var message string
 	if nudgeMessageFlag != "" {
 		message = nudgeMessageFlag
-	} else if len(args) >= 2 {
-		message = args[1]
+	} else if len(msgArgs) >= 1 {
+		message = msgArgs[0]
 	} else {
 		return fmt.Errorf("message required: use -m flag or provide as second argument")
 	}
@@ -272,6 +294,13 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
+	if nudgeSelectFlag != "" {
+		return runNudgeSelect(nudgeSelectFlag, message, sender)
+	}
+	if nudgeDryRunFlag {
+		return fmt.Errorf("--dry-run requires --select")
+	}
+
 	// Handle channel syntax: channel:<name>
 	if strings.HasPrefix(target, "channel:") {
 		channelName := strings.TrimPrefix(target, "channel:")
@@ -289,8 +318,6 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
-	t := tmux.NewTmux()
-
 	// Expand role shortcuts to session names
 	// These shortcuts let users type "mayor" instead of "gt-mayor"
 	switch target {
@@ -317,7 +344,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 	if target == constants.RoleDeacon {
 		deaconSession := session.DeaconSessionName()
 		// Check if Deacon session exists
-		exists, err := t.HasSession(deaconSession)
+		exists, err := session.BackendFor(townRoot, deaconSession).HasSession(deaconSession)
 		if err != nil {
 			return fmt.Errorf("checking deacon session: %w", err)
 		}
@@ -327,7 +354,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 			return nil
 		}
 
-		if err := deliverNudge(t, deaconSession, message, sender); err != nil {
+		if err := deliverNudge(session.BackendFor(townRoot, deaconSession), deaconSession, message, sender); err != nil {
 			return fmt.Errorf("nudging deacon: %w", err)
 		}
 
@@ -370,7 +397,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 			// Try crew first (matches mail system's addressToSessionIDs pattern),
 			// then fall back to polecat.
 			crewSession := crewSessionName(rigName, polecatName)
-			if exists, _ := t.HasSession(crewSession); exists {
+			if exists, _ := session.BackendFor(townRoot, crewSession).HasSession(crewSession); exists {
 				sessionName = crewSession
 			} else {
 				mgr, _, err := getSessionManager(rigName)
@@ -385,7 +412,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 		// Without this, queue mode silently succeeds for nonexistent sessions —
 		// the file is written but never drained.
 		if nudgeModeFlag != NudgeModeImmediate {
-			exists, err := t.HasSession(sessionName)
+			exists, err := session.BackendFor(townRoot, sessionName).HasSession(sessionName)
 			if err != nil {
 				return fmt.Errorf("checking session: %w", err)
 			}
@@ -395,7 +422,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 		}
 
 		// Send nudge using the configured delivery mode
-		if err := deliverNudge(t, sessionName, message, sender); err != nil {
+		if err := deliverNudge(session.BackendFor(townRoot, sessionName), sessionName, message, sender); err != nil {
 			return fmt.Errorf("nudging session: %w", err)
 		}
 
@@ -408,7 +435,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 		_ = events.LogFeed(events.TypeNudge, sender, events.NudgePayload(rigName, target, message))
 	} else {
 		// Raw session name (legacy)
-		exists, err := t.HasSession(target)
+		exists, err := session.BackendFor(townRoot, target).HasSession(target)
 		if err != nil {
 			return fmt.Errorf("checking session: %w", err)
 		}
@@ -416,7 +443,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 			return fmt.Errorf("session %q not found", target)
 		}
 
-		if err := deliverNudge(t, target, message, sender); err != nil {
+		if err := deliverNudge(session.BackendFor(townRoot, target), target, message, sender); err != nil {
 			return fmt.Errorf("nudging session: %w", err)
 		}
 
@@ -483,13 +510,56 @@ func runNudgeChannel(channelName, message, sender string) error {
 		return nil
 	}
 
+	fmt.Printf("Nudging channel %q (%d target(s), mode=%s)...\n\n", channelName, len(targets), nudgeModeFlag)
+	return nudgeSessions(townRoot, targets, message, sender, "Channel nudge", "channel:"+channelName)
+}
+
+// runNudgeSelect nudges every running agent a selector matches.
+func runNudgeSelect(selectExpr, message, sender string) error {
+	sel, err := parseSelectFlag(selectExpr)
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	matches, err := selectAgents(townRoot, sel)
+	if err != nil {
+		return err
+	}
+	if nudgeDryRunFlag {
+		printSelectedAgents(sel, matches)
+		fmt.Printf("\nMessage: %s\n", message)
+		return nil
+	}
+
+	var targets []string
+	for _, a := range matches {
+		// Only live sessions can be nudged
+		if a.Running() {
+			targets = append(targets, a.Session)
+		}
+	}
+	if len(targets) == 0 {
+		fmt.Printf("%s No running agents match %s\n", style.WarningPrefix, sel)
+		return nil
+	}
+
+	fmt.Printf("Nudging %s (%d target(s), mode=%s)...\n\n", sel, len(targets), nudgeModeFlag)
+	return nudgeSessions(townRoot, targets, message, sender, "Selector nudge", "select:"+sel.String())
+}
+
+// nudgeSessions delivers a nudge to each session in turn, skipping targets
+// with DND enabled, and prints a summary. Shared by channel and selector
+// nudges; label names the operation in the summary and eventTarget is the
+// target recorded in the feed.
+func nudgeSessions(townRoot string, targets []string, message, sender, label, eventTarget string) error {
 	// Send nudges via deliverNudge (respects --mode flag)
 	t := tmux.NewTmux()
 	var succeeded, failed, skipped int
 	var failures []string
 
-	fmt.Printf("Nudging channel %q (%d target(s), mode=%s)...\n\n", channelName, len(targets), nudgeModeFlag)
-
 	for i, sessionName := range targets {
 		// Check DND status before nudging each target
 		// Convert session name back to address format for DND lookup
@@ -520,10 +590,10 @@ func runNudgeChannel(channelName, message, sender string) error {
 	fmt.Println()
 
 	// Log nudge event
-	_ = events.LogFeed(events.TypeNudge, sender, events.NudgePayload("", "channel:"+channelName, message))
+	_ = events.LogFeed(events.TypeNudge, sender, events.NudgePayload("", eventTarget, message))
 
 	if failed > 0 {
-		summary := fmt.Sprintf("Channel nudge complete: %d succeeded, %d failed", succeeded, failed)
+		summary := fmt.Sprintf("%s complete: %d succeeded, %d failed", label, succeeded, failed)
 		if skipped > 0 {
 			summary += fmt.Sprintf(", %d skipped (DND)", skipped)
 		}
@@ -534,7 +604,7 @@ func runNudgeChannel(channelName, message, sender string) error {
 		return fmt.Errorf("%d nudge(s) failed", failed)
 	}
 
-	summary := fmt.Sprintf("Channel nudge complete: %d target(s) nudged", succeeded)
+	summary := fmt.Sprintf("%s complete: %d target(s) nudged", label, succeeded)
 	if skipped > 0 {
 		summary += fmt.Sprintf(", %d skipped (DND)", skipped)
 	}
@@ -261,6 +261,9 @@ func printPluginSummary(p *plugin.Plugin) {
 	if desc != "" {
 		fmt.Printf("      %s\n", style.Dim.Render(desc))
 	}
+	if len(p.EnabledRigs) > 0 {
+		fmt.Printf("      %s\n", style.Dim.Render("enabled by: "+strings.Join(p.EnabledRigs, ", ")))
+	}
 }
 
 func runPluginShow(cmd *cobra.Command, args []string) error {
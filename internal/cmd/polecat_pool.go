@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+var polecatPoolFillCmd = &cobra.Command{
+	Use:   "pool-fill <rig>",
+	Short: "Top up a rig's warm polecat pool",
+	Long: `Top up a rig's warm polecat pool.
+
+With polecat_pool set in the rig's settings/config.json, the daemon keeps
+that many idle polecats warm: each worktree is checked out at the latest
+commit of the default branch with the rig's warmup command already run,
+so gt sling can hand one out without a fetch, dependency install or build.
+
+  "polecat_pool": {
+    "size": 3,
+    "warmup": "npm ci && npm run build",
+    "warmup_timeout": "20m"
+  }
+
+This command runs one fill pass: idle polecats are re-warmed when the
+default branch or warmup command changed, and new polecats are created
+when there are fewer idle ones than the pool size. The daemon runs it on
+every heartbeat, and gt sling runs it in the background after handing out
+a warm polecat. A fill already in progress for the rig is left alone.
+
+Examples:
+  gt polecat pool-fill gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolecatPoolFill,
+}
+
+func init() {
+	polecatCmd.AddCommand(polecatPoolFillCmd)
+}
+
+func runPolecatPoolFill(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	mgr, _, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	pool := mgr.PoolConfig()
+	if pool == nil {
+		fmt.Printf("%s Rig %s has no warm pool (set polecat_pool.size in settings/config.json)\n", style.Dim.Render("○"), rigName)
+		return nil
+	}
+
+	result, err := mgr.FillPool(pool)
+	if errors.Is(err, polecat.ErrPoolFillInProgress) {
+		fmt.Printf("%s Pool fill already running for %s\n", style.Dim.Render("○"), rigName)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("filling polecat pool: %w", err)
+	}
+
+	for _, name := range result.Created {
+		fmt.Printf("  %s %s %s\n", style.Success.Render("✓"), name, style.Dim.Render("(created)"))
+	}
+	for _, name := range result.Warmed {
+		fmt.Printf("  %s %s %s\n", style.Success.Render("✓"), name, style.Dim.Render("(warmed)"))
+	}
+	for _, err := range result.Errors {
+		fmt.Printf("  %s %v\n", style.Error.Render("✖"), err)
+	}
+	fmt.Printf("%s Pool for %s: %d/%d warm\n", style.Bold.Render("✓"), rigName, len(result.Warm), result.Size)
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%d polecat(s) could not be warmed", len(result.Errors))
+	}
+	return nil
+}
+
+// refillPolecatPool starts a detached gt polecat pool-fill for a rig, so a
+// warm polecat handed out by sling is replaced without delaying dispatch.
+func refillPolecatPool(townRoot, rigName string) {
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = "gt"
+	}
+	cmd := exec.Command(gtPath, "polecat", "pool-fill", rigName) //nolint:gosec // G204: args are internal
+	cmd.Dir = townRoot
+	util.SetProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		style.PrintWarning("could not start polecat pool refill: %v", err)
+		return
+	}
+	_ = cmd.Process.Release()
+}
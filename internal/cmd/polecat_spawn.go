@@ -210,6 +210,11 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 			sessionName := polecatSessMgr.SessionName(polecatName)
 
 			fmt.Printf("%s Polecat %s reused (idle → working, session start deferred)\n", style.Bold.Render("✓"), polecatName)
+
+			// Replace the polecat that just left the warm pool.
+			if polecatMgr.PoolConfig() != nil {
+				refillPolecatPool(townRoot, rigName)
+			}
 			_ = events.LogFeed(events.TypeSpawn, "gt", events.SpawnPayload(rigName, polecatName))
 
 			effectiveBranch := strings.TrimPrefix(baseBranch, "origin/")
@@ -292,6 +297,11 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 
 	fmt.Printf("%s Polecat %s spawned (session start deferred)\n", style.Bold.Render("✓"), polecatName)
 
+	// A cold spawn on a pooled rig means the pool ran dry; start refilling it.
+	if polecatMgr.PoolConfig() != nil {
+		refillPolecatPool(townRoot, rigName)
+	}
+
 	// Log spawn event to activity feed
 	_ = events.LogFeed(events.TypeSpawn, "gt", events.SpawnPayload(rigName, polecatName))
 
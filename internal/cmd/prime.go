@@ -9,14 +9,15 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/cli"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/lock"
+	"github.com/steveyegge/gastown/internal/memory"
 	"github.com/steveyegge/gastown/internal/state"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/telemetry"
@@ -178,9 +179,11 @@ func runPrime(cmd *cobra.Command, args []string) (retErr error) {
 	hasSlungWork := checkSlungWork(ctx, hookedBead)
 	explain(hasSlungWork, "Autonomous mode: hooked/in-progress work detected")
 
+	outputHandoffDocument(ctx)
+
 	outputMoleculeContext(ctx)
 	outputCheckpointContext(ctx)
-	runPrimeExternalTools(cwd)
+	runPrimeExternalTools(ctx, cwd, hookedBead)
 
 	if ctx.Role == RoleMayor {
 		checkPendingEscalations(ctx)
@@ -390,7 +393,7 @@ func outputRoleContext(ctx RoleContext) (string, error) {
 
 // runPrimeExternalTools runs bd prime, memory injection, and gt mail check --inject.
 // Skipped in dry-run mode with explain output.
-func runPrimeExternalTools(cwd string) {
+func runPrimeExternalTools(ctx RoleContext, cwd string, hookedBead *beads.Issue) {
 	if primeDryRun {
 		explain(true, "bd prime: skipped in dry-run mode")
 		explain(true, "memory injection: skipped in dry-run mode")
@@ -398,7 +401,7 @@ func runPrimeExternalTools(cwd string) {
 		return
 	}
 	runBdPrime(cwd)
-	runMemoryInject()
+	runMemoryInject(ctx, hookedBead)
 	runMailCheckInject(cwd)
 }
 
@@ -429,35 +432,50 @@ func runBdPrime(workDir string) {
 	}
 }
 
-// runMemoryInject loads memories from beads kv and outputs them during prime.
-// This replaces MEMORY.md injection with bead-backed agent memory.
-func runMemoryInject() {
+// runMemoryInject loads memories from beads kv and outputs the ones relevant
+// to this agent during prime. This replaces MEMORY.md injection with
+// bead-backed agent memory.
+//
+// Memories are filtered by scope (role, rig, and paths mentioned in the
+// hooked bead), ranked by scope specificity, keyword overlap with the hooked
+// bead and recency, and capped to operational.memory.prime_token_budget.
+func runMemoryInject(ctx RoleContext, hookedBead *beads.Issue) {
 	kvs, err := bdKvListJSON()
 	if err != nil {
 		return // Silently skip if kv list fails
 	}
-
-	var memories []string
-	for k, v := range kvs {
-		if !strings.HasPrefix(k, memoryKeyPrefix) {
-			continue
-		}
-		shortKey := strings.TrimPrefix(k, memoryKeyPrefix)
-		memories = append(memories, fmt.Sprintf("- **%s**: %s", shortKey, v))
-	}
-
+	memories := memory.FromKV(kvs)
 	if len(memories) == 0 {
 		return
 	}
 
-	sort.Strings(memories)
+	budget := config.LoadOperationalConfig(ctx.TownRoot).GetMemoryConfig().PrimeTokenBudgetV()
+	sel := memory.Select(memories, memoryContext(ctx, hookedBead), budget)
+	explain(true, fmt.Sprintf("Memory injection: %d of %d memories selected (~%d tokens, budget %d, %d dropped by budget)",
+		len(sel.Selected), len(memories), sel.Tokens, budget, sel.Dropped))
+	if len(sel.Selected) == 0 {
+		return
+	}
 
 	fmt.Println()
 	fmt.Println("# Agent Memories")
 	fmt.Println()
-	for _, m := range memories {
-		fmt.Println(m)
+	for _, m := range sel.Selected {
+		fmt.Printf("- **%s**: %s\n", m.Key, m.Content)
+	}
+	if sel.Dropped > 0 {
+		fmt.Printf("\n_%d more memories omitted (token budget); search with `gt memories <term>`._\n", sel.Dropped)
+	}
+}
+
+// memoryContext describes the current agent for memory selection.
+func memoryContext(ctx RoleContext, hookedBead *beads.Issue) memory.Context {
+	mc := memory.Context{Role: string(ctx.Role), Rig: ctx.Rig}
+	if hookedBead != nil {
+		mc.Text = hookedBead.Title + "\n" + hookedBead.Description
+		mc.Paths = memory.PathsInText(mc.Text)
 	}
+	return mc
 }
 
 // runMailCheckInject runs `gt mail check --inject` and outputs the result.
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -297,6 +298,13 @@ func buildRefineryPatrolVars(ctx RoleContext) []string {
 	}
 	vars = append(vars, fmt.Sprintf("target_branch=%s", defaultBranch))
 
+	// Merge lane refineries share the rig's repo, and git refuses to check
+	// out a branch that another lane's worktree has checked out, so each
+	// lane needs its own scratch branch.
+	if lane := os.Getenv("GT_REFINERY_LANE"); lane != "" {
+		vars = append(vars, fmt.Sprintf("work_branch=temp-%s", lane))
+	}
+
 	// MQ-specific vars require settings/config.json with a merge_queue section
 	settingsPath := filepath.Join(rigPath, "settings", "config.json")
 	settings, sErr := config.LoadRigSettings(settingsPath)
@@ -136,18 +136,26 @@ func readSessionFile(dir string) string {
 // resolveSessionIDForPrime finds the session ID from available sources.
 // Priority: GT_SESSION_ID env, CLAUDE_SESSION_ID env, persisted file, fallback.
 func resolveSessionIDForPrime(actor string) string {
+	if id, ok := agentSessionID(); ok {
+		return id
+	}
+	// Fallback to generated identifier
+	return fmt.Sprintf("%s-%d", actor, os.Getpid())
+}
+
+// agentSessionID returns the session ID the agent itself assigned, which
+// it can resume. Agents without a session ID variable have none.
+func agentSessionID() (string, bool) {
 	// 1. Try runtime's session ID lookup (checks GT_SESSION_ID_ENV, then CLAUDE_SESSION_ID)
 	if id := runtime.SessionIDFromEnv(); id != "" {
-		return id
+		return id, true
 	}
 
 	// 2. Persisted session file (from gt prime --hook)
 	if id := ReadPersistedSessionID(); id != "" {
-		return id
+		return id, true
 	}
-
-	// 3. Fallback to generated identifier
-	return fmt.Sprintf("%s-%d", actor, os.Getpid())
+	return "", false
 }
 
 // emitSessionEvent emits a session_start event for seance discovery.
@@ -165,7 +173,10 @@ func emitSessionEvent(ctx RoleContext) {
 	}
 
 	// Get session ID from multiple sources
-	sessionID := resolveSessionIDForPrime(actor)
+	sessionID, resumable := agentSessionID()
+	if !resumable {
+		sessionID = resolveSessionIDForPrime(actor)
+	}
 
 	// Determine topic from hook state or default
 	topic := ""
@@ -175,6 +186,15 @@ func emitSessionEvent(ctx RoleContext) {
 
 	// Emit the event
 	payload := events.SessionPayload(sessionID, actor, topic, ctx.WorkDir)
+	// Record the agent so gt seance can resume the session with it.
+	if agent := os.Getenv("GT_AGENT"); agent != "" {
+		payload["agent"] = agent
+	}
+	// A generated ID names no session the agent knows, so gt seance must
+	// not try to resume it.
+	if !resumable {
+		payload["resumable"] = false
+	}
 	_ = events.LogFeed(events.TypeSessionStart, actor, payload)
 }
 
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/pty"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	ptyListJSON      bool
+	ptyCaptureLines  int
+	ptySendKey       bool
+	ptySuperviseTown string
+	ptySuperviseName string
+	ptySuperviseDir  string
+)
+
+var ptyCmd = &cobra.Command{
+	Use:     "pty",
+	GroupID: GroupAgents,
+	Short:   "Manage sessions run by the native PTY backend",
+	Long: `Manage agent sessions run by gt's native PTY backend.
+
+Towns choose their session backend in settings/config.json:
+
+  {"session_backend": "pty", "experimental_pty_backend": true}
+
+The pty backend is experimental and sessions refuse to start without the
+opt-in: many gt commands still drive tmux directly.
+
+With the pty backend, each agent runs under its own "gt pty supervise"
+process instead of a tmux server. The supervisor owns the agent's
+pseudo-terminal, keeps a scrollback ring buffer and serves a Unix socket
+under .runtime/pty/ for input injection, capture and attach. A crashed
+supervisor only takes down its own agent.
+
+Attach with "gt pty attach <session>" and detach with Ctrl-].`,
+	RunE: requireSubcommand,
+}
+
+var ptyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List PTY sessions in this town",
+	Args:  cobra.NoArgs,
+	RunE:  runPtyList,
+}
+
+var ptyAttachCmd = &cobra.Command{
+	Use:   "attach <session>",
+	Short: "Attach the terminal to a PTY session (detach with Ctrl-])",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPtyAttach,
+}
+
+var ptyCaptureCmd = &cobra.Command{
+	Use:   "capture <session>",
+	Short: "Print a PTY session's recent output",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPtyCapture,
+}
+
+var ptySendCmd = &cobra.Command{
+	Use:   "send <session> <text>",
+	Short: "Type text into a PTY session and press Enter",
+	Long: `Type text into a PTY session and press Enter.
+
+With --key, the text is a tmux-style key name ("C-c", "Escape", "Enter")
+and is sent without pressing Enter.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runPtySend,
+}
+
+var ptyKillCmd = &cobra.Command{
+	Use:   "kill <session>",
+	Short: "Kill a PTY session and its processes",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPtyKill,
+}
+
+var ptySuperviseCmd = &cobra.Command{
+	Use:    "supervise --town <root> --name <session> --dir <dir> -- <command>",
+	Short:  "Run a PTY session supervisor (started by the pty backend)",
+	Hidden: true,
+	Args:   cobra.MinimumNArgs(1),
+	RunE:   runPtySupervise,
+}
+
+func init() {
+	ptyListCmd.Flags().BoolVar(&ptyListJSON, "json", false, "Output as JSON")
+	ptyCaptureCmd.Flags().IntVarP(&ptyCaptureLines, "lines", "n", 50, "Number of lines to print (0 for all scrollback)")
+	ptySendCmd.Flags().BoolVar(&ptySendKey, "key", false, "Send a tmux-style key name instead of text")
+
+	ptySuperviseCmd.Flags().StringVar(&ptySuperviseTown, "town", "", "Town root")
+	ptySuperviseCmd.Flags().StringVar(&ptySuperviseName, "name", "", "Session name")
+	ptySuperviseCmd.Flags().StringVar(&ptySuperviseDir, "dir", "", "Working directory")
+	_ = ptySuperviseCmd.MarkFlagRequired("town")
+	_ = ptySuperviseCmd.MarkFlagRequired("name")
+
+	ptyCmd.AddCommand(ptyListCmd, ptyAttachCmd, ptyCaptureCmd, ptySendCmd, ptyKillCmd, ptySuperviseCmd)
+	rootCmd.AddCommand(ptyCmd)
+}
+
+// ptyBackend returns the PTY backend for the current town.
+func ptyBackend() (*pty.Backend, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, err
+	}
+	return pty.NewBackend(townRoot), nil
+}
+
+// ptySessionInfo is one row of gt pty list.
+type ptySessionInfo struct {
+	Name     string    `json:"name"`
+	PID      int       `json:"pid,omitempty"`
+	Alive    bool      `json:"alive"`
+	Started  time.Time `json:"started,omitzero"`
+	Restarts int       `json:"restarts,omitempty"`
+}
+
+func runPtyList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	b := pty.NewBackend(townRoot)
+	names, err := b.ListSessions()
+	if err != nil {
+		return err
+	}
+	infos := make([]ptySessionInfo, 0, len(names))
+	for _, name := range names {
+		st, err := b.Status(name)
+		if err != nil {
+			continue
+		}
+		infos = append(infos, ptySessionInfo{Name: name, PID: st.PID, Alive: st.Alive, Started: st.Started, Restarts: st.Restart})
+	}
+
+	if ptyListJSON {
+		return outputJSON(infos)
+	}
+	if backend := session.BackendName(townRoot); backend != config.SessionBackendPTY {
+		fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("(town session backend is %q)", backend)))
+	}
+	if len(infos) == 0 {
+		fmt.Println("No PTY sessions running.")
+		return nil
+	}
+	for _, info := range infos {
+		state := style.Success.Render("running")
+		if !info.Alive {
+			state = style.Warning.Render("exited")
+		}
+		line := fmt.Sprintf("%-28s %s", info.Name, state)
+		if info.PID > 0 {
+			line += style.Dim.Render(fmt.Sprintf("  pid %d", info.PID))
+		}
+		if !info.Started.IsZero() {
+			line += style.Dim.Render("  started " + formatAge(info.Started))
+		}
+		if info.Restarts > 0 {
+			line += style.Dim.Render(fmt.Sprintf("  %d restarts", info.Restarts))
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runPtyAttach(cmd *cobra.Command, args []string) error {
+	b, err := ptyBackend()
+	if err != nil {
+		return err
+	}
+	return b.Attach(args[0])
+}
+
+func runPtyCapture(cmd *cobra.Command, args []string) error {
+	b, err := ptyBackend()
+	if err != nil {
+		return err
+	}
+	out, err := b.CapturePane(args[0], ptyCaptureLines)
+	if err != nil {
+		return err
+	}
+	fmt.Println(out)
+	return nil
+}
+
+func runPtySend(cmd *cobra.Command, args []string) error {
+	b, err := ptyBackend()
+	if err != nil {
+		return err
+	}
+	text := strings.Join(args[1:], " ")
+	if ptySendKey {
+		return b.SendKeysRaw(args[0], text)
+	}
+	return b.SendKeys(args[0], text)
+}
+
+func runPtyKill(cmd *cobra.Command, args []string) error {
+	b, err := ptyBackend()
+	if err != nil {
+		return err
+	}
+	if err := b.KillSessionWithProcesses(args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("%s Killed %s\n", style.SuccessPrefix, args[0])
+	return nil
+}
+
+func runPtySupervise(cmd *cobra.Command, args []string) error {
+	s := &pty.Supervisor{
+		Name:    ptySuperviseName,
+		Dir:     ptySuperviseDir,
+		Command: strings.Join(args, " "),
+		Socket:  pty.SocketPath(ptySuperviseTown, ptySuperviseName),
+	}
+	return s.Run()
+}
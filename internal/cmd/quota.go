@@ -15,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/quota"
+	sessionpkg "github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	ttmux "github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/util"
@@ -231,9 +232,8 @@ func runQuotaScan(cmd *cobra.Command, args []string) error {
 	acctCfg, loadErr := config.LoadAccountsConfig(accountsPath)
 	// acctCfg can be nil if no accounts configured — scan still works
 
-	// Create scanner
-	t := ttmux.NewTmux()
-	scanner, err := quota.NewScanner(t, nil, acctCfg)
+	// Create scanner over tmux and PTY sessions
+	scanner, err := quota.NewScanner(sessionpkg.NewTownBackend(nil, townRoot), nil, acctCfg)
 	if err != nil {
 		return fmt.Errorf("creating scanner: %w", err)
 	}
@@ -410,7 +410,8 @@ func runQuotaRotate(cmd *cobra.Command, args []string) error {
 
 	// Create scanner and plan rotation
 	t := ttmux.NewTmux()
-	scanner, err := quota.NewScanner(t, nil, acctCfg)
+	sessions := sessionpkg.NewTownBackend(t, townRoot)
+	scanner, err := quota.NewScanner(sessions, nil, acctCfg)
 	if err != nil {
 		return fmt.Errorf("creating scanner: %w", err)
 	}
@@ -478,7 +479,7 @@ func runQuotaRotate(cmd *cobra.Command, args []string) error {
 	skippedBusy := 0
 	if rotateIdle {
 		for session := range plan.Assignments {
-			if !t.IsIdle(session) {
+			if !isSessionIdle(t, sessions, session) {
 				if !quotaJSON {
 					fmt.Printf(" %s %-25s %s\n",
 						style.Dim.Render("-"), session,
@@ -562,7 +563,7 @@ func runQuotaRotate(cmd *cobra.Command, args []string) error {
 	var results []quota.RotateResult
 	for _, session := range sortedSessions {
 		newAccount := plan.Assignments[session]
-		result := executeKeychainRotation(t, mgr, acctCfg, session, newAccount, swappedConfigDirs)
+		result := executeKeychainRotation(t, sessions, mgr, acctCfg, session, newAccount, swappedConfigDirs)
 		results = append(results, result)
 
 		if !quotaJSON {
@@ -662,6 +663,7 @@ func accountHandles(acctCfg *config.AccountsConfig) []string {
 // rotation batch — multiple sessions sharing a config dir only need one swap.
 func executeKeychainRotation(
 	t *ttmux.Tmux,
+	sessions *sessionpkg.TownBackend,
 	mgr *quota.Manager,
 	acctCfg *config.AccountsConfig,
 	session, newAccount string,
@@ -673,7 +675,7 @@ func executeKeychainRotation(
 	}
 
 	// Read the session's current CLAUDE_CONFIG_DIR, falling back to ~/.claude
-	currentConfigDir, err := t.GetEnvironment(session, "CLAUDE_CONFIG_DIR")
+	currentConfigDir, err := sessions.GetEnvironment(session, "CLAUDE_CONFIG_DIR")
 	if err != nil || strings.TrimSpace(currentConfigDir) == "" {
 		home, homeErr := os.UserHomeDir()
 		if homeErr != nil {
@@ -737,6 +739,14 @@ func executeKeychainRotation(
 	// (the config dir still maps to the old account).
 	restartCmd = fmt.Sprintf("export CLAUDE_CONFIG_DIR=%q && export GT_QUOTA_ACCOUNT=%q && %s", currentConfigDir, newAccount, restartCmd)
 
+	if !sessions.IsTmux(session) {
+		if err := restartPTYSession(sessions, session, restartCmd); err != nil {
+			result.Error = fmt.Sprintf("restarting session: %v", err)
+			return result
+		}
+		return finishRotation(sessions, mgr, result, session, newAccount)
+	}
+
 	// Get target pane
 	pane, err := t.GetPaneID(session)
 	if err != nil {
@@ -765,10 +775,15 @@ func executeKeychainRotation(
 		return result
 	}
 
-	// Set GT_QUOTA_ACCOUNT in the tmux session environment so the scanner
+	return finishRotation(sessions, mgr, result, session, newAccount)
+}
+
+// finishRotation records a restarted session's new account.
+func finishRotation(sessions *sessionpkg.TownBackend, mgr *quota.Manager, result quota.RotateResult, session, newAccount string) quota.RotateResult {
+	// Set GT_QUOTA_ACCOUNT in the session environment so the scanner
 	// can resolve the active account. The shell export in restartCmd only
 	// affects the process env; this sets it where GetEnvironment reads it.
-	if err := t.SetEnvironment(session, "GT_QUOTA_ACCOUNT", newAccount); err != nil {
+	if err := sessions.SetEnvironment(session, "GT_QUOTA_ACCOUNT", newAccount); err != nil {
 		style.PrintWarning("could not set GT_QUOTA_ACCOUNT for %s: %v", session, err)
 	}
 
@@ -795,6 +810,38 @@ func executeKeychainRotation(
 
 
 
+// isSessionIdle reports whether a session's agent is at its idle prompt.
+func isSessionIdle(t *ttmux.Tmux, sessions *sessionpkg.TownBackend, session string) bool {
+	if sessions.IsTmux(session) {
+		return t.IsIdle(session)
+	}
+	return sessions.WaitForIdle(session, time.Second) == nil
+}
+
+// restartPTYSession replaces a PTY session's agent with command, keeping
+// its working directory and session environment. PTY sessions have no
+// pane to respawn, so the session itself is restarted.
+func restartPTYSession(sessions *sessionpkg.TownBackend, session, command string) error {
+	b := sessions.For(session)
+	workDir, err := sessionpkg.WorkDir(b, session)
+	if err != nil {
+		return fmt.Errorf("reading work dir: %w", err)
+	}
+	env, _ := sessionpkg.Environment(b, session)
+	if err := b.KillSessionWithProcesses(session); err != nil {
+		return fmt.Errorf("stopping session: %w", err)
+	}
+	if err := b.NewSessionWithCommand(session, workDir, command); err != nil {
+		return fmt.Errorf("starting session: %w", err)
+	}
+	for k, v := range env {
+		if err := b.SetEnvironment(session, k, v); err != nil {
+			style.PrintWarning("could not restore %s for %s: %v", k, session, err)
+		}
+	}
+	return nil
+}
+
 // Watch command flags
 var (
 	watchInterval time.Duration
@@ -863,7 +910,8 @@ func runQuotaWatch(cmd *cobra.Command, args []string) error {
 
 func runWatchCycle(townRoot string, acctCfg *config.AccountsConfig) {
 	t := ttmux.NewTmux()
-	scanner, err := quota.NewScanner(t, nil, acctCfg)
+	sessions := sessionpkg.NewTownBackend(t, townRoot)
+	scanner, err := quota.NewScanner(sessions, nil, acctCfg)
 	if err != nil {
 		style.PrintWarning("creating scanner: %v", err)
 		return
@@ -918,7 +966,7 @@ func runWatchCycle(townRoot string, acctCfg *config.AccountsConfig) {
 	swappedConfigDirs := make(map[string]*quota.KeychainCredential)
 	for _, session := range slices.Sorted(maps.Keys(plan.Assignments)) {
 		newAccount := plan.Assignments[session]
-		result := executeKeychainRotation(t, mgr, acctCfg, session, newAccount, swappedConfigDirs)
+		result := executeKeychainRotation(t, sessions, mgr, acctCfg, session, newAccount, swappedConfigDirs)
 		if result.Rotated {
 			fmt.Printf(" [%s] %s %s → %s\n",
 				style.Dim.Render(now),
@@ -10,7 +10,6 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/refinery"
-	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -23,6 +22,8 @@ var (
 	refineryStatusJSON    bool
 	refineryQueueJSON     bool
 	refineryAgentOverride string
+	refineryLane          string
+	refineryAllLanes      bool
 )
 
 var refineryCmd = &cobra.Command{
@@ -45,6 +46,14 @@ The polecat is already nuked by the time the Refinery processes.
 
 One Refinery per rig. Persistent agent that processes work as it arrives.
 
+Large rigs can split the queue into parallel merge lanes by configuring
+merge_queue.lanes in the rig's config.json. Each lane runs its own Refinery
+(gt refinery start --lane <name>) with its own worktree and claim set.
+MRs are routed by a "gt:lane:<name>" label, a lane's labels, or the path
+prefixes they touch; everything else goes to the "default" lane. Pushes to
+the target stay serialized: each lane rebases onto the latest target under
+the merge slot before pushing, so history stays linear.
+
 Role shortcuts: "refinery" in mail/nudge addresses resolves to this rig's Refinery.`,
 }
 
@@ -62,7 +71,9 @@ If rig is not specified, infers it from the current directory.
 Examples:
   gt refinery start greenplace
   gt refinery start greenplace --foreground
-  gt refinery start              # infer rig from cwd`,
+  gt refinery start              # infer rig from cwd
+  gt refinery start --lane api   # start one merge lane
+  gt refinery start --all-lanes  # start every configured lane`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runRefineryStart,
 }
@@ -230,6 +241,12 @@ func init() {
 	// Start flags
 	refineryStartCmd.Flags().BoolVar(&refineryForeground, "foreground", false, "Run in foreground (default: background)")
 	refineryStartCmd.Flags().StringVar(&refineryAgentOverride, "agent", "", "Agent alias to run the Refinery with (overrides town default)")
+	refineryStartCmd.Flags().BoolVar(&refineryAllLanes, "all-lanes", false, "Start one Refinery per configured merge lane")
+
+	// Lane flags (default: $GT_REFINERY_LANE)
+	for _, c := range []*cobra.Command{refineryStartCmd, refineryStopCmd, refineryRestartCmd, refineryAttachCmd, refineryStatusCmd, refineryReadyCmd, refineryClaimCmd} {
+		c.Flags().StringVar(&refineryLane, "lane", "", "Merge lane to operate on (default: $GT_REFINERY_LANE)")
+	}
 
 	// Attach flags
 	refineryAttachCmd.Flags().StringVar(&refineryAgentOverride, "agent", "", "Agent alias to run the Refinery with (overrides town default)")
@@ -289,17 +306,63 @@ func getRefineryManager(rigName string) (*refinery.Manager, *rig.Rig, string, er
 		return nil, nil, "", err
 	}
 
+	lane, err := resolveRefineryLane(r)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if lane != "" {
+		return refinery.NewLaneManager(r, lane), r, rigName, nil
+	}
+
 	mgr := refinery.NewManager(r)
 	return mgr, r, rigName, nil
 }
 
+// resolveRefineryLane returns the merge lane selected by --lane or
+// GT_REFINERY_LANE, verified against the rig's merge_queue.lanes config.
+// Returns "" when no lane is selected.
+func resolveRefineryLane(r *rig.Rig) (string, error) {
+	lane := refineryLane
+	if lane == "" {
+		lane = os.Getenv("GT_REFINERY_LANE")
+	}
+	if lane == "" {
+		return "", nil
+	}
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return "", fmt.Errorf("loading merge queue config: %w", err)
+	}
+	if !eng.Config().HasLane(lane) {
+		return "", fmt.Errorf("unknown merge lane %q for rig %s (configure merge_queue.lanes in config.json)", lane, r.Name)
+	}
+	return lane, nil
+}
+
+// newRefineryEngineer creates an engineer for the rig with its merge queue
+// config loaded, scoped to the selected lane if any.
+func newRefineryEngineer(r *rig.Rig) (*refinery.Engineer, error) {
+	lane, err := resolveRefineryLane(r)
+	if err != nil {
+		return nil, err
+	}
+	eng := refinery.NewEngineer(r)
+	if lane != "" {
+		eng = refinery.NewLaneEngineer(r, lane)
+	}
+	if err := eng.LoadConfig(); err != nil {
+		return nil, fmt.Errorf("loading merge queue config: %w", err)
+	}
+	return eng, nil
+}
+
 func runRefineryStart(cmd *cobra.Command, args []string) error {
 	rigName := ""
 	if len(args) > 0 {
 		rigName = args[0]
 	}
 
-	mgr, _, rigName, err := getRefineryManager(rigName)
+	mgr, r, rigName, err := getRefineryManager(rigName)
 	if err != nil {
 		return err
 	}
@@ -308,6 +371,10 @@ func runRefineryStart(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if refineryAllLanes {
+		return startAllRefineryLanes(r, rigName)
+	}
+
 	fmt.Printf("Starting refinery for %s...\n", rigName)
 
 	if err := mgr.Start(refineryForeground, refineryAgentOverride); err != nil {
@@ -328,6 +395,39 @@ func runRefineryStart(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// startAllRefineryLanes starts one refinery per configured merge lane,
+// including the catch-all default lane.
+func startAllRefineryLanes(r *rig.Rig, rigName string) error {
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	lanes := eng.Config().LaneNames()
+	if len(lanes) == 0 {
+		return fmt.Errorf("rig %s has no merge lanes (configure merge_queue.lanes in config.json)", rigName)
+	}
+
+	var failed []string
+	for _, lane := range lanes {
+		fmt.Printf("Starting refinery lane %s for %s...\n", lane, rigName)
+		mgr := refinery.NewLaneManager(r, lane)
+		if err := mgr.Start(false, refineryAgentOverride); err != nil {
+			if err == refinery.ErrAlreadyRunning {
+				fmt.Printf("  %s Lane %s is already running\n", style.Dim.Render("⚠"), lane)
+				continue
+			}
+			fmt.Printf("  %s Lane %s: %v\n", style.Warning.Render("✗"), lane, err)
+			failed = append(failed, lane)
+			continue
+		}
+		fmt.Printf("  %s Lane %s started (%s)\n", style.Bold.Render("✓"), lane, mgr.SessionName())
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to start lanes: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
 func runRefineryStop(cmd *cobra.Command, args []string) error {
 	rigName := ""
 	if len(args) > 0 {
@@ -355,6 +455,7 @@ func runRefineryStop(cmd *cobra.Command, args []string) error {
 type RefineryStatusOutput struct {
 	Running     bool   `json:"running"`
 	RigName     string `json:"rig_name"`
+	Lane        string `json:"lane,omitempty"`
 	Session     string `json:"session,omitempty"`
 	QueueLength int    `json:"queue_length"`
 }
@@ -365,10 +466,11 @@ func runRefineryStatus(cmd *cobra.Command, args []string) error {
 		rigName = args[0]
 	}
 
-	mgr, _, rigName, err := getRefineryManager(rigName)
+	mgr, r, rigName, err := getRefineryManager(rigName)
 	if err != nil {
 		return err
 	}
+	lane, _ := resolveRefineryLane(r)
 
 	// ZFC: tmux is source of truth for running state
 	running, _ := mgr.IsRunning()
@@ -383,6 +485,7 @@ func runRefineryStatus(cmd *cobra.Command, args []string) error {
 		output := RefineryStatusOutput{
 			Running:     running,
 			RigName:     rigName,
+			Lane:        lane,
 			QueueLength: queueLen,
 		}
 		if sessionInfo != nil {
@@ -394,7 +497,11 @@ func runRefineryStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Human-readable output
-	fmt.Printf("%s Refinery: %s\n\n", style.Bold.Render("⚙"), rigName)
+	if lane != "" {
+		fmt.Printf("%s Refinery: %s (lane %s)\n\n", style.Bold.Render("⚙"), rigName, lane)
+	} else {
+		fmt.Printf("%s Refinery: %s\n\n", style.Bold.Render("⚙"), rigName)
+	}
 
 	if running {
 		fmt.Printf("  State: %s\n", style.Bold.Render("● running"))
@@ -504,7 +611,7 @@ func runRefineryAttach(cmd *cobra.Command, args []string) error {
 	}
 
 	// Session name follows the same pattern as refinery manager
-	sessionID := session.RefinerySessionName(session.PrefixFor(rigName))
+	sessionID := mgr.SessionName()
 
 	// Check if session exists
 	t := tmux.NewTmux()
@@ -584,7 +691,13 @@ func runRefineryClaim(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	eng := refinery.NewEngineer(r)
+	eng, err := newRefineryEngineer(r)
+	if err != nil {
+		return err
+	}
+	if eng.Lane() != "" && os.Getenv("GT_REFINERY_WORKER") == "" {
+		workerID = refinery.LaneWorkerID(eng.Lane())
+	}
 	if err := eng.ClaimMR(mrID, workerID); err != nil {
 		return fmt.Errorf("claiming MR: %w", err)
 	}
@@ -697,8 +810,12 @@ func runRefineryReady(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Create engineer for the rig (it has beads access for status checking)
-	eng := refinery.NewEngineer(r)
+	// Create engineer for the rig (it has beads access for status checking).
+	// With a lane selected, listings are scoped to that lane's claim set.
+	eng, err := newRefineryEngineer(r)
+	if err != nil {
+		return err
+	}
 
 	if refineryReadyAll {
 		return runRefineryReadyAll(eng, rigName)
@@ -739,7 +856,11 @@ func runRefineryReady(cmd *cobra.Command, args []string) error {
 	for i, mr := range ready {
 		priority := fmt.Sprintf("P%d", mr.Priority)
 		fmt.Printf("  %d. [%s] %s → %s\n", i+1, priority, mr.Branch, mr.Target)
-		fmt.Printf("     ID: %s  Worker: %s\n", mr.ID, mr.Worker)
+		if mr.Lane != "" {
+			fmt.Printf("     ID: %s  Worker: %s  Lane: %s\n", mr.ID, mr.Worker, mr.Lane)
+		} else {
+			fmt.Printf("     ID: %s  Worker: %s\n", mr.ID, mr.Worker)
+		}
 	}
 
 	if len(anomalies) > 0 {
@@ -748,6 +869,9 @@ func runRefineryReady(cmd *cobra.Command, args []string) error {
 			line := fmt.Sprintf("  %d. [%s] %s", i+1, anomaly.Type, anomaly.ID)
 			fmt.Println(line)
 			fmt.Printf("     Branch: %s\n", anomaly.Branch)
+			if anomaly.Lane != "" {
+				fmt.Printf("     Lane: %s\n", anomaly.Lane)
+			}
 			if anomaly.Assignee != "" {
 				fmt.Printf("     Assignee: %s\n", anomaly.Assignee)
 			}
@@ -8,17 +8,25 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/memory"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
-const memoryKeyPrefix = "memory."
+const memoryKeyPrefix = memory.KeyPrefix
 
-var rememberKey string
+var (
+	rememberKey     string
+	rememberScope   []string
+	rememberExpires string
+)
 
 func init() {
 	rememberCmd.Flags().StringVar(&rememberKey, "key", "", "Explicit key slug (default: auto-generated from content)")
+	rememberCmd.Flags().StringArrayVar(&rememberScope, "scope", nil, "Where to inject: town (default), rig:<name>, role:<role>, path:<glob> (repeatable)")
+	rememberCmd.Flags().StringVar(&rememberExpires, "expires", "", "Expire after a duration (e.g. 72h, 30d)")
 	rememberCmd.GroupID = GroupWork
 	rootCmd.AddCommand(rememberCmd)
 }
@@ -34,9 +42,23 @@ This replaces filesystem-based MEMORY.md with bead-backed storage.
 The key is auto-generated from the content if not specified.
 Use --key to provide an explicit slug for easy retrieval.
 
+Memories are town-wide by default. Use --scope to inject a memory only
+where it matters; kinds combine with AND, repeated values of one kind
+with OR:
+  rig:<name>    Agents working in that rig
+  role:<role>   Agents with that role (mayor, refinery, polecat, ...)
+  path:<glob>   Work whose hooked bead mentions a matching path
+                ("internal/refinery/**", "*.proto")
+
+Each memory records its author and creation time. Use --expires for
+short-lived knowledge; expired memories are no longer injected and show
+up in 'gt memories --stale'.
+
 Examples:
   gt remember "Refinery uses worktree, cannot checkout main"
-  gt remember --key refinery-worktree "Refinery uses worktree, cannot checkout main"
+  gt remember --key refinery-worktree --scope role:refinery "Refinery uses worktree, cannot checkout main"
+  gt remember --scope rig:gastown --scope path:internal/tmux/** "tmux tests need a live server"
+  gt remember --expires 7d "main is frozen for the release"
   gt remember "Always use --stdin for multi-line mail"`,
 	Args: cobra.ExactArgs(1),
 	RunE: runRemember,
@@ -58,6 +80,29 @@ func runRemember(cmd *cobra.Command, args []string) error {
 
 	fullKey := memoryKeyPrefix + key
 
+	scope, err := memory.ParseScope(rememberScope)
+	if err != nil {
+		return err
+	}
+	m := memory.Memory{
+		Key:       key,
+		Content:   content,
+		Scope:     scope,
+		Author:    detectSender(),
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if rememberExpires != "" {
+		ttl, err := parseDuration(rememberExpires)
+		if err != nil || ttl <= 0 {
+			return fmt.Errorf("invalid --expires %q: use a positive duration like 72h or 30d", rememberExpires)
+		}
+		m.ExpiresAt = m.CreatedAt.Add(ttl)
+	}
+	value, err := m.Encode()
+	if err != nil {
+		return fmt.Errorf("encoding memory: %w", err)
+	}
+
 	// Check if key already exists
 	existing, _ := bdKvGet(fullKey)
 	verb := "Stored"
@@ -65,11 +110,12 @@ func runRemember(cmd *cobra.Command, args []string) error {
 		verb = "Updated"
 	}
 
-	if err := bdKvSet(fullKey, content); err != nil {
+	if err := bdKvSet(fullKey, value); err != nil {
 		return fmt.Errorf("storing memory: %w", err)
 	}
 
-	fmt.Printf("%s %s memory: %s\n", style.Success.Render("✓"), verb, style.Bold.Render(key))
+	fmt.Printf("%s %s memory: %s %s\n", style.Success.Render("✓"), verb, style.Bold.Render(key),
+		style.Dim.Render("("+scope.String()+")"))
 	return nil
 }
 
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/replay"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	replayAt     string
+	replayFrom   string
+	replayTo     string
+	replayJSON   bool
+	replayNoDolt bool
+)
+
+// replayHistoryTimeout bounds the Dolt AS OF queries.
+const replayHistoryTimeout = 30 * time.Second
+
+var replayCmd = &cobra.Command{
+	Use:     "replay",
+	GroupID: GroupDiag,
+	Short:   "Rebuild town state at a past time for postmortems",
+	Long: `Rebuild what the town looked like at a past moment.
+
+Replay merges the events log (.events.jsonl) and the town log into one
+timeline and folds it into the state of the town: agents and their
+status and hooks, the merge queue and open escalations. When the Dolt
+server is reachable, hooks, convoy progress, merge queue contents and
+escalations are read exactly as they were with AS OF queries. MRs that
+were ephemeral wisps never reach Dolt history, so the merge queue keeps
+the ones known only from the timeline.
+
+With --at, shows the state at that instant. With --from/--to, shows the
+timeline between the two and the state at the end of the window.
+
+Times may be RFC3339, "2006-01-02 15:04[:05]", "15:04" (the most recent
+such time) or a duration ago ("90m", "6h", "2d").
+
+Examples:
+  gt replay --at 03:10                 # State at 3:10 this morning
+  gt replay --at 6h                    # State six hours ago
+  gt replay --from 01:00 --to 04:00    # What happened overnight
+  gt replay --from 2h --json           # Timeline report as JSON`,
+	RunE: runReplay,
+}
+
+func init() {
+	replayCmd.Flags().StringVar(&replayAt, "at", "", "Show town state at this time")
+	replayCmd.Flags().StringVar(&replayFrom, "from", "", "Start of the timeline window")
+	replayCmd.Flags().StringVar(&replayTo, "to", "", "End of the timeline window (default: now)")
+	replayCmd.Flags().BoolVar(&replayJSON, "json", false, "Output as JSON")
+	replayCmd.Flags().BoolVar(&replayNoDolt, "no-dolt", false, "Use only the event logs, not Dolt history")
+	rootCmd.AddCommand(replayCmd)
+}
+
+// replayEntry is a timeline entry as shown in reports.
+type replayEntry struct {
+	replay.Entry
+	Summary string `json:"summary"`
+}
+
+// replayReport is the --from/--to JSON output.
+type replayReport struct {
+	From     time.Time     `json:"from"`
+	To       time.Time     `json:"to"`
+	Timeline []replayEntry `json:"timeline"`
+	Start    *replay.State `json:"start"`
+	End      *replay.State `json:"end"`
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	if replayAt == "" && replayFrom == "" {
+		return fmt.Errorf("specify --at <time> or --from <time> [--to <time>]")
+	}
+	if replayAt != "" && (replayFrom != "" || replayTo != "") {
+		return fmt.Errorf("--at cannot be combined with --from/--to")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	now := time.Now()
+	entries, err := replay.Load(townRoot)
+	if err != nil {
+		return err
+	}
+
+	var history replay.History
+	var historyErr error
+	if !replayNoDolt {
+		history, historyErr = replay.NewDoltHistory(townRoot)
+	}
+	stateAt := func(at time.Time) *replay.State {
+		s := replay.Rebuild(entries, at)
+		if replayNoDolt {
+			return s
+		}
+		if historyErr != nil {
+			s.HistoryError = historyErr.Error()
+			return s
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), replayHistoryTimeout)
+		defer cancel()
+		snap, err := history.SnapshotAt(ctx, at)
+		if err != nil {
+			s.HistoryError = err.Error()
+			return s
+		}
+		s.Overlay(snap)
+		return s
+	}
+
+	if replayAt != "" {
+		at, err := parseReplayTime(replayAt, now)
+		if err != nil {
+			return fmt.Errorf("invalid --at: %w", err)
+		}
+		state := stateAt(at)
+		if replayJSON {
+			return printReplayJSON(state)
+		}
+		printReplayState(state, now)
+		return nil
+	}
+
+	from, err := parseReplayTime(replayFrom, now)
+	if err != nil {
+		return fmt.Errorf("invalid --from: %w", err)
+	}
+	to := now
+	if replayTo != "" {
+		if to, err = parseReplayTime(replayTo, now); err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+	}
+	if to.Before(from) {
+		return fmt.Errorf("--to (%s) is before --from (%s)", to.Format(time.DateTime), from.Format(time.DateTime))
+	}
+
+	report := replayReport{From: from, To: to, Timeline: []replayEntry{}}
+	for _, e := range replay.Window(entries, from, to) {
+		report.Timeline = append(report.Timeline, replayEntry{Entry: e, Summary: e.Summary()})
+	}
+	report.Start = stateAt(from)
+	report.End = stateAt(to)
+	if replayJSON {
+		return printReplayJSON(report)
+	}
+	printReplayTimeline(report)
+	fmt.Println()
+	printReplayState(report.End, now)
+	return nil
+}
+
+// parseReplayTime parses an absolute time, a clock time (the most recent
+// occurrence) or a duration ago.
+func parseReplayTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{time.DateTime, "2006-01-02 15:04", time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range []string{time.TimeOnly, "15:04"} {
+		if c, err := time.Parse(layout, s); err == nil {
+			t := time.Date(now.Year(), now.Month(), now.Day(), c.Hour(), c.Minute(), c.Second(), 0, now.Location())
+			if t.After(now) {
+				t = t.AddDate(0, 0, -1)
+			}
+			return t, nil
+		}
+	}
+	if d, err := parseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", s)
+}
+
+func printReplayJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printReplayTimeline(r replayReport) {
+	fmt.Printf("%s Timeline %s → %s (%d entries)\n\n", style.Bold.Render("📜"),
+		r.From.Format("2006-01-02 15:04:05"), r.To.Format("2006-01-02 15:04:05"), len(r.Timeline))
+	if len(r.Timeline) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(nothing recorded)"))
+		return
+	}
+	day := ""
+	for _, e := range r.Timeline {
+		t := e.Time.Local()
+		if d := t.Format("2006-01-02"); d != day {
+			day = d
+			fmt.Printf("  %s\n", style.Bold.Render(d))
+		}
+		fmt.Printf("  %s  %-28s %s %s\n", t.Format("15:04:05"), orNone(e.Actor), e.Summary,
+			style.Dim.Render("["+e.Source+"]"))
+	}
+}
+
+func printReplayState(s *replay.State, now time.Time) {
+	fmt.Printf("%s Town state at %s (%s ago)\n", style.Bold.Render("🕰"),
+		s.At.Local().Format("2006-01-02 15:04:05"), now.Sub(s.At).Round(time.Minute))
+	fmt.Printf("  Sources: %s\n", strings.Join(s.Sources, ", "))
+	if s.HistoryError != "" {
+		fmt.Printf("  %s Dolt history unavailable: %s\n", style.Warning.Render("⚠"), s.HistoryError)
+	}
+
+	fmt.Printf("\n%s (%d)\n", style.Bold.Render("Agents"), len(s.Agents))
+	for _, a := range s.Agents {
+		line := fmt.Sprintf("  %s %-32s %-8s", replayAgentIcon(a.Status), a.Address, orNone(a.Status))
+		if !a.Since.IsZero() {
+			line += " since " + a.Since.Local().Format("15:04")
+		}
+		if a.Hook != "" {
+			line += "  hook " + a.Hook
+		}
+		fmt.Println(line)
+	}
+
+	if s.Convoys != nil { // Only Dolt history knows convoys
+		fmt.Printf("\n%s (%d)\n", style.Bold.Render("Convoys"), len(s.Convoys))
+		for _, c := range s.Convoys {
+			fmt.Printf("  %s %s  %d/%d closed\n", c.ID, c.Title, c.Closed, c.Tracked)
+		}
+	}
+
+	fmt.Printf("\n%s (%d)\n", style.Bold.Render("Merge queue"), len(s.MergeQueue))
+	for _, mr := range s.MergeQueue {
+		fmt.Printf("  %-40s %-8s %s %s\n", mr.Branch, mr.Status, mr.ID, style.Dim.Render(mr.Worker))
+	}
+
+	fmt.Printf("\n%s (%d)\n", style.Bold.Render("Escalations"), len(s.Escalations))
+	for _, esc := range s.Escalations {
+		acked := ""
+		if esc.Acked {
+			acked = style.Dim.Render(" (acked)")
+		}
+		fmt.Printf("  %s [%s] from %s: %s%s\n", esc.ID, orNone(esc.Severity), orNone(esc.From), esc.Reason, acked)
+	}
+}
+
+func replayAgentIcon(status string) string {
+	switch status {
+	case replay.AgentRunning:
+		return style.Success.Render("●")
+	case replay.AgentCrashed:
+		return style.Error.Render("✗")
+	case replay.AgentDone:
+		return style.Dim.Render("✓")
+	}
+	return style.Dim.Render("○")
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseReplayTime(t *testing.T) {
+	loc := time.FixedZone("test", -7*3600)
+	now := time.Date(2026, 3, 2, 9, 30, 0, 0, loc)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-03-01T23:15:00Z", time.Date(2026, 3, 1, 23, 15, 0, 0, time.UTC)},
+		{"2026-03-01 03:10:05", time.Date(2026, 3, 1, 3, 10, 5, 0, loc)},
+		{"2026-03-01 03:10", time.Date(2026, 3, 1, 3, 10, 0, 0, loc)},
+		{"2026-03-01", time.Date(2026, 3, 1, 0, 0, 0, 0, loc)},
+		{"03:10", time.Date(2026, 3, 2, 3, 10, 0, 0, loc)}, // earlier today
+		{"22:00", time.Date(2026, 3, 1, 22, 0, 0, 0, loc)}, // later today → yesterday
+		{"6h", now.Add(-6 * time.Hour)},
+		{"2d", now.Add(-48 * time.Hour)},
+	}
+	for _, tt := range tests {
+		got, err := parseReplayTime(tt.in, now)
+		if err != nil {
+			t.Errorf("parseReplayTime(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseReplayTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := parseReplayTime("last tuesday", now); err == nil {
+		t.Error("parseReplayTime(\"last tuesday\") succeeded, want error")
+	}
+}
@@ -3,10 +3,15 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/handoff"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -21,19 +26,104 @@ var resumeCmd = &cobra.Command{
 The resume command checks for messages with "HANDOFF" in the subject
 and displays them formatted for easy continuation.
 
+With --diff, it instead compares the structured handoff document that
+gt handoff left in this workspace with the workspace as it is now: commits
+made since, branch, hook and molecule step changes, and which files in
+flight were settled or newly touched.
+
 Examples:
-  gt resume    # Check inbox for handoff messages`,
+  gt resume           # Check inbox for handoff messages
+  gt resume --diff    # What changed since the handoff was written`,
 	RunE: runResume,
 }
 
+var resumeDiff bool
+
 func init() {
+	resumeCmd.Flags().BoolVar(&resumeDiff, "diff", false, "Show what changed since the handoff document was written")
 	rootCmd.AddCommand(resumeCmd)
 }
 
 func runResume(cmd *cobra.Command, args []string) error {
+	if resumeDiff {
+		return runResumeDiff()
+	}
 	return checkHandoffMessages()
 }
 
+// runResumeDiff compares the workspace's handoff document with its
+// current state.
+func runResumeDiff() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	doc, err := handoff.Read(cwd)
+	if err != nil {
+		return err
+	}
+	if doc == nil {
+		fmt.Printf("%s No handoff document in this workspace\n", style.Dim.Render("○"))
+		fmt.Printf("  gt handoff writes one to %s\n", filepath.Join(constants.DirRuntime, constants.FileHandoffDocument))
+		return nil
+	}
+
+	cur := captureWorkState(cwd)
+	diff := handoff.Compare(doc, cur)
+	commits, commitsErr := handoff.CommitsSince(cwd, doc.Commit)
+	diff.Commits = commits
+
+	from := doc.Session
+	if from == "" {
+		from = doc.Agent
+	}
+	fmt.Printf("%s Handoff written %s ago", style.Bold.Render("🤝"), doc.Age().Round(time.Minute))
+	if from != "" {
+		fmt.Printf(" by %s", from)
+	}
+	fmt.Print("\n\n")
+	fmt.Println(doc.Markdown())
+	fmt.Printf("\n%s\n", style.Bold.Render("Since the handoff:"))
+
+	if commitsErr != nil {
+		fmt.Printf("  %s commits: %v\n", style.Warning.Render("⚠"), commitsErr)
+	}
+	if diff.Empty() && commitsErr == nil {
+		fmt.Printf("  %s Nothing changed\n", style.Success.Render("✓"))
+		return nil
+	}
+	if len(diff.Commits) > 0 {
+		fmt.Printf("  Commits (%d):\n", len(diff.Commits))
+		for _, c := range diff.Commits {
+			fmt.Printf("    %s\n", c)
+		}
+	}
+	if diff.BranchChanged {
+		fmt.Printf("  Branch: %s → %s\n", orNone(doc.Branch), orNone(diff.Branch))
+	}
+	if diff.HookChanged {
+		fmt.Printf("  Hook: %s → %s\n", orNone(doc.HookedBead), orNone(diff.HookedBead))
+	}
+	if diff.StepChanged {
+		fmt.Printf("  Step: %s → %s\n", orNone(doc.Step), orNone(diff.Step))
+	}
+	if len(diff.Settled) > 0 {
+		fmt.Printf("  Settled: %s\n", strings.Join(diff.Settled, ", "))
+	}
+	if len(diff.NewInFlight) > 0 {
+		fmt.Printf("  Newly in flight: %s\n", strings.Join(diff.NewInFlight, ", "))
+	}
+	return nil
+}
+
+// orNone returns s, or "(none)" when it is empty.
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
 // checkHandoffMessages checks the inbox for handoff messages and displays them.
 func checkHandoffMessages() error {
 	// Get inbox in JSON format
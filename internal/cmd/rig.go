@@ -23,6 +23,7 @@ import (
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/rigtemplate"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/suggest"
@@ -69,6 +70,9 @@ The command also:
   - Creates ~/gt/plugins/ (town-level) if it doesn't exist
   - Creates <rig>/plugins/ (rig-level)
 
+Use --template to apply a rig template (settings, merge queue gates, hook
+overrides, plugins) to the new rig; see gt rig template.
+
 Use --adopt to register an existing directory instead of creating new:
   - Reads existing config.json if present
   - Auto-detects git URL from origin remote (git-url argument not required)
@@ -77,6 +81,7 @@ Use --adopt to register an existing directory instead of creating new:
 Example:
   gt rig add gastown https://github.com/steveyegge/gastown
   gt rig add my-project git@github.com:user/repo.git --prefix mp
+  gt rig add api git@github.com:user/api.git --template go-service
   gt rig add existing-rig --adopt`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runRigAdd,
@@ -303,6 +308,7 @@ var (
 	rigAddAdopt        bool
 	rigAddAdoptURL     string
 	rigAddAdoptForce   bool
+	rigAddTemplate     string
 	rigResetHandoff    bool
 	rigResetMail       bool
 	rigResetStale      bool
@@ -363,6 +369,7 @@ func init() {
 	rigAddCmd.Flags().BoolVar(&rigAddAdopt, "adopt", false, "Adopt an existing directory instead of creating new")
 	rigAddCmd.Flags().StringVar(&rigAddAdoptURL, "url", "", "Git remote URL for --adopt (default: auto-detected from origin)")
 	rigAddCmd.Flags().BoolVar(&rigAddAdoptForce, "force", false, "With --adopt, register even if git remote cannot be detected")
+	rigAddCmd.Flags().StringVar(&rigAddTemplate, "template", "", "Rig template to apply (see gt rig template list)")
 
 	rigResetCmd.Flags().BoolVar(&rigResetHandoff, "handoff", false, "Clear handoff content")
 	rigResetCmd.Flags().BoolVar(&rigResetMail, "mail", false, "Clear stale mail messages")
@@ -510,6 +517,14 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Load the template up front so a typo fails before cloning
+	var tmpl *rigtemplate.Template
+	if rigAddTemplate != "" {
+		if tmpl, err = rigtemplate.Load(townRoot, rigAddTemplate); err != nil {
+			return err
+		}
+	}
+
 	// Create rig manager
 	g := git.NewGit(townRoot)
 	mgr := rig.NewManager(townRoot, rigsConfig, g)
@@ -588,6 +603,20 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Apply the template before syncing hooks so its overrides are picked up
+	if tmpl != nil {
+		if plan, err := rigtemplate.NewPlan(townRoot, name, newRig.Path, tmpl); err != nil {
+			fmt.Printf("  %s Could not apply template %s: %v\n", style.Warning.Render("!"), tmpl.Name, err)
+		} else if err := plan.Apply(false); err != nil {
+			fmt.Printf("  %s Could not apply template %s: %v\n", style.Warning.Render("!"), tmpl.Name, err)
+		} else {
+			fmt.Printf("  Applied template %s (%d change(s))\n", tmpl.Name, len(plan.Changes)-len(plan.Conflicts()))
+			for _, c := range plan.Conflicts() {
+				fmt.Printf("  %s Kept existing %s %s (template wants %s)\n", style.Warning.Render("!"), c.Kind, c.Key, c.New)
+			}
+		}
+	}
+
 	// Sync hooks for the new rig's targets
 	if err := syncRigHooks(townRoot, name); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to sync hooks for new rig: %v\n", err)
@@ -16,9 +16,10 @@ import (
 )
 
 var (
-	quickAddUser  string
-	quickAddYes   bool
-	quickAddQuiet bool
+	quickAddUser     string
+	quickAddYes      bool
+	quickAddQuiet    bool
+	quickAddTemplate string
 )
 
 var rigQuickAddCmd = &cobra.Command{
@@ -33,7 +34,8 @@ It infers the rig name from the directory and git URL from the remote.
 Examples:
   gt rig quick-add                    # Add current directory
   gt rig quick-add ~/Repos/myproject  # Add specific path
-  gt rig quick-add --yes              # Non-interactive`,
+  gt rig quick-add --yes              # Non-interactive
+  gt rig quick-add --template go-service`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runRigQuickAdd,
 }
@@ -43,6 +45,7 @@ func init() {
 	rigQuickAddCmd.Flags().StringVar(&quickAddUser, "user", "", "Crew workspace name (default: $USER)")
 	rigQuickAddCmd.Flags().BoolVar(&quickAddYes, "yes", false, "Non-interactive, assume yes")
 	rigQuickAddCmd.Flags().BoolVar(&quickAddQuiet, "quiet", false, "Minimal output")
+	rigQuickAddCmd.Flags().StringVar(&quickAddTemplate, "template", "", "Rig template to apply (see gt rig template list)")
 }
 
 func runRigQuickAdd(cmd *cobra.Command, args []string) error {
@@ -94,6 +97,9 @@ func runRigQuickAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	addArgs := []string{"rig", "add", rigName, gitURL}
+	if quickAddTemplate != "" {
+		addArgs = append(addArgs, "--template", quickAddTemplate)
+	}
 	addCmd := exec.Command("gt", addArgs...)
 	addCmd.Dir = townRoot
 	addCmd.Stdout = os.Stdout
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/rigtemplate"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	rigApplyTemplateDryRun bool
+	rigApplyTemplateForce  bool
+)
+
+var rigTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "List and show rig templates",
+	Long: `List and show rig templates.
+
+A rig template is a named bundle of rig configuration stored at town level
+in settings/rig-templates/<name>.json. It can carry:
+  - settings         partial rig settings/config.json (merge queue gates,
+                     namepool theme, agent and role_agents overrides, ...)
+  - default_formula  workflow.default_formula for gt formula run
+  - hooks            hook overrides per rig role (crew, witness, refinery,
+                     polecats), written as <rig>/<role> overrides
+  - plugins          town plugins (plugins/<name>) to enable for the rig
+
+Apply a template when adding a rig (gt rig add --template) or later with
+gt rig apply-template.
+
+Example template (settings/rig-templates/go-service.json):
+  {
+    "type": "rig-template",
+    "version": 1,
+    "description": "Go service",
+    "settings": {
+      "merge_queue": {"test_command": "go test -race ./...", "lint_command": "golangci-lint run"},
+      "namepool": {"style": "minerals"}
+    },
+    "default_formula": "shiny",
+    "plugins": ["github-sheriff"]
+  }`,
+	RunE: requireSubcommand,
+}
+
+var rigTemplateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List rig templates",
+	Args:  cobra.NoArgs,
+	RunE:  runRigTemplateList,
+}
+
+var rigTemplateShowCmd = &cobra.Command{
+	Use:   "show <template>",
+	Short: "Show a rig template",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRigTemplateShow,
+}
+
+var rigApplyTemplateCmd = &cobra.Command{
+	Use:   "apply-template <rig> <template>",
+	Short: "Apply a rig template to an existing rig",
+	Long: `Apply a rig template to an existing rig.
+
+Shows what would change, then applies it. A change conflicts with the rig
+when the rig has already customized that setting, hook matcher or plugin to
+something else; conflicts are reported and left alone unless --force is
+given. Settings still at their defaults are not conflicts.
+
+Examples:
+  gt rig apply-template myproject go-service --dry-run   # Preview only
+  gt rig apply-template myproject go-service             # Apply, keep customizations
+  gt rig apply-template myproject go-service --force     # Overwrite conflicts too`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRigApplyTemplate,
+}
+
+func init() {
+	rigApplyTemplateCmd.Flags().BoolVar(&rigApplyTemplateDryRun, "dry-run", false, "Show the changes without applying them")
+	rigApplyTemplateCmd.Flags().BoolVarP(&rigApplyTemplateForce, "force", "f", false, "Overwrite rig customizations that conflict with the template")
+
+	rigTemplateCmd.AddCommand(rigTemplateListCmd)
+	rigTemplateCmd.AddCommand(rigTemplateShowCmd)
+	rigCmd.AddCommand(rigTemplateCmd)
+	rigCmd.AddCommand(rigApplyTemplateCmd)
+}
+
+func runRigTemplateList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	templates, err := rigtemplate.List(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(templates) == 0 {
+		fmt.Println("No rig templates.")
+		fmt.Printf("\nCreate one in %s\n", style.Dim.Render(rigtemplate.Dir(townRoot)+"/<name>.json"))
+		return nil
+	}
+	for _, t := range templates {
+		fmt.Printf("  %-20s %s\n", style.Bold.Render(t.Name), style.Dim.Render(t.Description))
+	}
+	return nil
+}
+
+func runRigTemplateShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	t, err := rigtemplate.Load(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("formatting template: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func runRigApplyTemplate(cmd *cobra.Command, args []string) error {
+	rigName, templateName := args[0], args[1]
+
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	t, err := rigtemplate.Load(townRoot, templateName)
+	if err != nil {
+		return err
+	}
+	plan, err := rigtemplate.NewPlan(townRoot, rigName, r.Path, t)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Template %s → rig %s\n", style.Bold.Render(t.Name), style.Bold.Render(rigName))
+	if len(plan.Changes) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("Rig already matches the template"))
+		return nil
+	}
+	printRigTemplatePlan(plan, rigApplyTemplateForce)
+	if rigApplyTemplateDryRun {
+		return nil
+	}
+
+	return applyRigTemplate(townRoot, plan, rigApplyTemplateForce)
+}
+
+// applyRigTemplate applies a plan and resyncs the rig's hooks if the plan
+// touched any hook overrides.
+func applyRigTemplate(townRoot string, plan *rigtemplate.Plan, force bool) error {
+	if err := plan.Apply(force); err != nil {
+		return fmt.Errorf("applying template %s: %w", plan.Template.Name, err)
+	}
+
+	conflicts := len(plan.Conflicts())
+	applied := len(plan.Changes)
+	if !force {
+		applied -= conflicts
+	}
+	fmt.Printf("%s Applied %d change(s) from template %s\n", style.Success.Render("✓"), applied, plan.Template.Name)
+	if conflicts > 0 && !force {
+		fmt.Printf("  %s %d conflict(s) left as-is (rerun gt rig apply-template with --force to overwrite)\n",
+			style.Warning.Render("!"), conflicts)
+	}
+
+	for _, c := range plan.Changes {
+		if c.Kind == rigtemplate.KindHook && (force || !c.Conflict) {
+			if err := syncRigHooks(townRoot, plan.RigName); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to sync hooks: %v\n", err)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// printRigTemplatePlan prints one line per change, marked "+" for a new
+// value, "~" for one replacing a default and "!" for a conflict with a rig
+// customization.
+func printRigTemplatePlan(plan *rigtemplate.Plan, force bool) {
+	for _, c := range plan.Changes {
+		mark, line := "+", fmt.Sprintf("%s %s: %s", c.Kind, c.Key, c.New)
+		if c.Old != "" {
+			mark, line = "~", fmt.Sprintf("%s %s: %s → %s", c.Kind, c.Key, c.Old, c.New)
+		}
+		switch {
+		case c.Conflict && force:
+			fmt.Printf("  %s %s %s\n", style.Warning.Render("!"), line, style.Dim.Render("(overwriting rig customization)"))
+		case c.Conflict:
+			fmt.Printf("  %s %s %s\n", style.Warning.Render("!"), line, style.Dim.Render("(conflict: rig customized, skipped)"))
+		default:
+			fmt.Printf("  %s %s\n", mark, line)
+		}
+	}
+}
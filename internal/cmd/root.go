@@ -72,6 +72,8 @@ var beadsExemptCommands = map[string]bool{
 	"health":              true, // Health check doesn't require beads
 	"upgrade":             true, // Post-install migration orchestrator
 	"heartbeat":           true, // Heartbeat state update — must be fast and dependency-free
+	"supervise":           true, // PTY session supervisor — long-lived, started by gt itself
+	"sim-agent":           true, // Simulated agent — long-lived, started by gt itself
 }
 
 // Commands exempt from the town root branch warning.
@@ -88,6 +90,11 @@ var branchCheckExemptCommands = map[string]bool{
 
 // persistentPreRun runs before every command.
 func persistentPreRun(cmd *cobra.Command, args []string) error {
+	// Sandbox stages wrap the agent's own terminal: stay silent.
+	if isSandboxStage(cmd) {
+		return nil
+	}
+
 	// Check if binary was built properly (via make build, not raw go build).
 	// Raw go build produces unsigned binaries that macOS may kill.
 	// Warning only - doesn't block execution.
@@ -151,6 +158,12 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// isSandboxStage returns true for the hidden gt sandbox plumbing commands
+// that run a polecat's agent.
+func isSandboxStage(cmd *cobra.Command) bool {
+	return cmd.Hidden && cmd.Parent() != nil && cmd.Parent().Name() == "sandbox"
+}
+
 // isRoleCommand returns true when the invoked command belongs to the `gt role` tree.
 // Role introspection commands are often used in scripts and tests that expect clean
 // output; beads version warnings are unrelated noise for these commands.
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/sandbox"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	sandboxPolicy     string
+	sandboxSetup      bool
+	sandboxProbeROnly string
+	sandboxCheckJSON  bool
+)
+
+var sandboxCmd = &cobra.Command{
+	Use:     "sandbox",
+	GroupID: GroupDiag,
+	Short:   "Inspect and test polecat sandboxing",
+	Long: `Inspect and test the polecat sandbox.
+
+Rigs opt in with a "sandbox" block in <rig>/settings/config.json:
+
+  "sandbox": {
+    "enabled": true,
+    "tool": "auto",
+    "network": "proxy",
+    "proxy_addr": "172.17.0.1:9876",
+    "forward": ["127.0.0.1:3307"],
+    "writable": ["~/go/pkg/mod"],
+    "hidden": ["~/.ssh"]
+  }
+
+New polecat sessions then run in Linux user, mount and network namespaces,
+through bubblewrap ("bwrap") or gt's own namespace setup ("unshare");
+"auto" (the default) prefers bubblewrap when it is installed. The
+agent can write only to its worktree, the rig's git directory, the town and
+rig .runtime directories and the configured writable paths. Unless network
+is "host", it can reach only the gt-proxy-server endpoint (default: from
+GT_PROXY_URL) and forwarded endpoints, on the same ports on localhost.
+Network "none" drops the proxy endpoint too.
+
+gt doctor verifies that running polecats of sandboxed rigs are confined.`,
+	RunE: requireSubcommand,
+}
+
+var sandboxCheckCmd = &cobra.Command{
+	Use:   "check <rig>",
+	Short: "Run a probe inside a rig's sandbox and report what it can do",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSandboxCheck,
+}
+
+var sandboxRunCmd = &cobra.Command{
+	Use:          "run --policy <file> -- <command...>",
+	SilenceUsage: true,
+	Short:        "Run a command in a sandbox (used by polecat sessions)",
+	Hidden:       true,
+	Args:         cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exitWith(sandbox.Run(sandboxPolicy, args))
+	},
+}
+
+var sandboxInnerCmd = &cobra.Command{
+	Use:          "inner --policy <file> -- <command...>",
+	SilenceUsage: true,
+	Short:        "Sandbox stage running inside the namespaces",
+	Hidden:       true,
+	Args:         cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exitWith(sandbox.Inner(sandboxPolicy, sandboxSetup, args))
+	},
+}
+
+var sandboxProbeCmd = &cobra.Command{
+	Use:          "probe --policy <file> --read-only <dir>",
+	SilenceUsage: true,
+	Short:        "Sandbox self-test, run inside the sandbox by gt sandbox check",
+	Hidden:       true,
+	Args:         cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := sandbox.LoadPolicy(sandboxPolicy)
+		if err != nil {
+			return err
+		}
+		return json.NewEncoder(os.Stdout).Encode(sandbox.Probe(p, sandboxProbeROnly))
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{sandboxRunCmd, sandboxInnerCmd, sandboxProbeCmd} {
+		c.Flags().StringVar(&sandboxPolicy, "policy", "", "Sandbox policy file")
+		_ = c.MarkFlagRequired("policy")
+	}
+	sandboxInnerCmd.Flags().BoolVar(&sandboxSetup, "setup", false, "Set up mounts and loopback (unshare tool)")
+	sandboxProbeCmd.Flags().StringVar(&sandboxProbeROnly, "read-only", "", "Directory that must not be writable")
+	sandboxCheckCmd.Flags().BoolVar(&sandboxCheckJSON, "json", false, "Output as JSON")
+
+	sandboxCmd.AddCommand(sandboxCheckCmd, sandboxRunCmd, sandboxInnerCmd, sandboxProbeCmd)
+	rootCmd.AddCommand(sandboxCmd)
+}
+
+// exitWith exits with a sandboxed command's exit code, so the session
+// sees the agent's status rather than gt's.
+func exitWith(code int, err error) error {
+	if err != nil {
+		return err
+	}
+	os.Exit(code)
+	return nil
+}
+
+func runSandboxCheck(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil || settings.Sandbox == nil {
+		return fmt.Errorf("rig %s has no sandbox settings", r.Name)
+	}
+	if err := sandbox.Supported(); err != nil {
+		return err
+	}
+
+	// Probe from a scratch worktree stand-in; the rig root must be read-only.
+	workDir := filepath.Join(r.Path, constants.DirRuntime, "sandbox-check")
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return err
+	}
+	session := "sandbox-check-" + r.Name
+	p, err := sandbox.NewPolicy(settings.Sandbox, sandbox.Options{
+		TownRoot: townRoot, RigPath: r.Path, WorkDir: workDir, Session: session,
+	})
+	if err != nil {
+		return err
+	}
+	policyPath := sandbox.PolicyPath(townRoot, session)
+	if err := p.Save(policyPath); err != nil {
+		return err
+	}
+	defer os.Remove(policyPath)
+
+	gt, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	probe := exec.Command(gt, "sandbox", "run", "--policy", policyPath, "--", //nolint:gosec // G204: re-executes gt itself
+		gt, "sandbox", "probe", "--policy", policyPath, "--read-only", r.Path)
+	probe.Stderr = os.Stderr
+	out, err := probe.Output()
+	if err != nil {
+		return fmt.Errorf("running sandbox probe (%s): %w", p.Tool, err)
+	}
+	var results []sandbox.ProbeResult
+	if err := json.Unmarshal(out, &results); err != nil {
+		return fmt.Errorf("parsing probe output: %w", err)
+	}
+
+	if sandboxCheckJSON {
+		if err := outputJSON(results); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("%s sandbox for %s (tool %s, network %s)\n", style.Bold.Render("Probing"), r.Name, p.Tool, p.Network)
+		for _, res := range results {
+			prefix := style.SuccessPrefix
+			if !res.OK {
+				prefix = style.ErrorPrefix
+			}
+			fmt.Printf("  %s %-12s %s\n", prefix, res.Name, style.Dim.Render(res.Info))
+		}
+	}
+	for _, res := range results {
+		if !res.OK {
+			return NewSilentExit(1)
+		}
+	}
+	return nil
+}
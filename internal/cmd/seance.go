@@ -6,11 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gofrs/flock"
 	"github.com/spf13/cobra"
@@ -27,6 +27,7 @@ var (
 	seanceRecent int
 	seanceTalk   string
 	seancePrompt string
+	seanceAgent  string
 	seanceJSON   bool
 )
 
@@ -38,8 +39,8 @@ var seanceCmd = &cobra.Command{
 
 "Where did you put the stuff you left for me?" - The #1 handoff question.
 
-Instead of parsing logs, seance spawns a Claude subprocess that resumes
-a predecessor session with full context. You can ask questions directly:
+Instead of parsing logs, seance spawns an agent that resumes a predecessor
+session with full context. You can ask questions directly:
   - "Why did you make this decision?"
   - "Where were you stuck?"
   - "What did you try that didn't work?"
@@ -54,8 +55,19 @@ THE SEANCE (talk to predecessor):
   gt seance --talk <session-id>              # Interactive conversation
   gt seance --talk <id> -p "Where is X?"     # One-shot question
 
-The --talk flag spawns: claude --fork-session --resume <id>
-This loads the predecessor's full context without modifying their session.
+The --talk flag resumes the predecessor with the agent it ran on, as
+recorded in its session_start event (override with --agent), in the
+working directory that event recorded. The predecessor's session is never
+modified:
+  - Agents that can fork sessions (claude) are run with
+    --fork-session --resume <id>.
+  - Agents that can only resume (gemini, codex, cursor, copilot, ...)
+    resume a copy of their session store, in a scratch home directory that
+    is removed afterwards.
+  - Agents that cannot resume, or keep no local sessions to copy
+    (opencode, amp, ...), and sessions recorded under a generated ID get a
+    transcript seance: the predecessor's normalized conversation log is
+    handed to the town's default agent, which answers in its place.
 
 Sessions are discovered from:
   1. Events emitted by SessionStart hooks (~/gt/.events.jsonl)
@@ -69,6 +81,7 @@ func init() {
 	seanceCmd.Flags().IntVarP(&seanceRecent, "recent", "n", 20, "Number of recent sessions to show")
 	seanceCmd.Flags().StringVarP(&seanceTalk, "talk", "t", "", "Session ID to commune with")
 	seanceCmd.Flags().StringVarP(&seancePrompt, "prompt", "p", "", "One-shot prompt (with --talk)")
+	seanceCmd.Flags().StringVar(&seanceAgent, "agent", "", "Agent the predecessor ran on (default: from its session event)")
 	seanceCmd.Flags().BoolVar(&seanceJSON, "json", false, "Output as JSON")
 
 	rootCmd.AddCommand(seanceCmd)
@@ -146,26 +159,30 @@ func runSeanceList() error {
 	// Column widths
 	idWidth := 12
 	roleWidth := 26
+	agentWidth := 8
 	timeWidth := 16
 	topicWidth := 28
 
-	fmt.Printf("%-*s  %-*s  %-*s  %-*s\n",
+	fmt.Printf("%-*s  %-*s  %-*s  %-*s  %-*s\n",
 		idWidth, "SESSION_ID",
 		roleWidth, "ROLE",
+		agentWidth, "AGENT",
 		timeWidth, "STARTED",
 		topicWidth, "TOPIC")
-	fmt.Printf("%s\n", strings.Repeat("─", idWidth+roleWidth+timeWidth+topicWidth+6))
+	fmt.Printf("%s\n", strings.Repeat("─", idWidth+roleWidth+agentWidth+timeWidth+topicWidth+8))
 
 	for _, s := range filtered {
 		sessionID := getPayloadString(s.Payload, "session_id")
-		if len(sessionID) > idWidth {
-			sessionID = sessionID[:idWidth-1] + "…"
-		}
+		sessionID = seanceCell(sessionID, idWidth)
 
 		role := s.Actor
-		if len(role) > roleWidth {
-			role = role[:roleWidth-1] + "…"
+		role = seanceCell(role, roleWidth)
+
+		agent := getPayloadString(s.Payload, "agent")
+		if agent == "" {
+			agent = "-"
 		}
+		agent = seanceCell(agent, agentWidth)
 
 		timeStr := formatEventTime(s.Timestamp)
 
@@ -173,13 +190,12 @@ func runSeanceList() error {
 		if topic == "" {
 			topic = "-"
 		}
-		if len(topic) > topicWidth {
-			topic = topic[:topicWidth-1] + "…"
-		}
+		topic = seanceCell(topic, topicWidth)
 
-		fmt.Printf("%-*s  %-*s  %-*s  %-*s\n",
+		fmt.Printf("%-*s  %-*s  %-*s  %-*s  %-*s\n",
 			idWidth, sessionID,
 			roleWidth, role,
+			agentWidth, agent,
 			timeWidth, timeStr,
 			topicWidth, topic)
 	}
@@ -191,27 +207,7 @@ func runSeanceList() error {
 	return nil
 }
 
-// resolveSeanceCommand finds the command for an agent that supports --fork-session.
-// Returns the resolved command path, or error if no agent supports fork session.
-func resolveSeanceCommand() (string, error) {
-	for _, name := range config.ListAgentPresets() {
-		preset := config.GetAgentPresetByName(name)
-		if preset != nil && preset.SupportsForkSession {
-			// Use RuntimeConfigFromPreset to resolve the actual command path
-			rc := config.RuntimeConfigFromPreset(preset.Name)
-			return rc.Command, nil
-		}
-	}
-	return "", fmt.Errorf("no agent supports fork session (seance requires --fork-session)")
-}
-
 func runSeanceTalk(sessionID, prompt string) error {
-	// Resolve the agent command that supports fork session
-	agentCmd, err := resolveSeanceCommand()
-	if err != nil {
-		return err
-	}
-
 	// Clean up any orphaned symlinks from previous interrupted sessions
 	cleanupOrphanedSessionSymlinks()
 
@@ -231,61 +227,49 @@ func runSeanceTalk(sessionID, prompt string) error {
 		}
 	}
 
-	fmt.Printf("%s Summoning session %s...\n\n", style.Bold.Render("🔮"), sessionID)
-	cleanup, err := symlinkSessionToCurrentAccount(townRoot, sessionID)
-	if err != nil {
-		// Not fatal - session might already be in current account
-		fmt.Printf("%s\n", style.Dim.Render("Note: "+err.Error()))
-	}
-	if cleanup != nil {
-		defer cleanup()
+	var event *sessionEvent
+	if townRoot != "" {
+		event = findSessionEvent(townRoot, sessionID)
 	}
+	agentName := seanceAgentName(seanceAgent, event)
 
-	// Build the command
-	args := []string{"--fork-session", "--resume", sessionID}
-
-	// Clear CLAUDECODE env var so the subprocess doesn't trigger the nested
-	// session guard. Seance is intentionally spawning a new Claude Code
-	// instance to read a predecessor's context — not a true nested session.
-	env := clearClaudeCodeEnv(os.Environ())
-
-	if prompt != "" {
-		// One-shot mode: -p flag (boolean) makes claude print and exit,
-		// prompt is a positional argument at the end.
-		args = append(args, "-p", prompt)
-
-		cmd := exec.Command(agentCmd, args...)
-		cmd.Env = env
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("seance failed: %w", err)
+	var plan *seancePlan
+	ok := false
+	if seanceResumable(event) {
+		plan, ok = planSeanceResume(agentName, sessionID, prompt)
+	}
+	if !ok {
+		var err error
+		plan, err = planSeanceTranscript(townRoot, agentName, sessionID, event, prompt)
+		if err != nil {
+			return err
 		}
-		return nil
 	}
+	plan.Dir = seanceWorkDir(event)
 
-	// Interactive mode
-	cmd := exec.Command(agentCmd, args...)
-	cmd.Env = env
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	fmt.Printf("%s\n", style.Dim.Render("You are now talking to your predecessor. Ask them anything."))
-	fmt.Printf("%s\n\n", style.Dim.Render("Exit with /exit or Ctrl+C"))
-
-	if err := cmd.Run(); err != nil {
-		// Exit errors are normal when user exits
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			if exitErr.ExitCode() == 0 || exitErr.ExitCode() == 130 {
-				return nil // Normal exit or Ctrl+C
-			}
+	fmt.Printf("%s Summoning session %s...\n\n", style.Bold.Render("🔮"), sessionID)
+	switch plan.Mode {
+	case seanceModeFork:
+		cleanup, err := symlinkSessionToCurrentAccount(townRoot, sessionID)
+		if err != nil {
+			// Not fatal - session might already be in current account
+			fmt.Printf("%s\n", style.Dim.Render("Note: "+err.Error()))
+		}
+		if cleanup != nil {
+			defer cleanup()
 		}
-		return fmt.Errorf("seance ended: %w", err)
+	case seanceModeResume:
+		cleanup, err := copySeanceStore(plan)
+		if err != nil {
+			return fmt.Errorf("%s cannot fork sessions, and copying its sessions failed: %w", agentName, err)
+		}
+		defer cleanup()
+		fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("Note: %s cannot fork sessions; resuming a copy of the predecessor's session.", agentName)))
+	case seanceModeTranscript:
+		fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("Note: %s cannot resume sessions; %s is answering from the predecessor's transcript.", agentName, plan.Agent)))
 	}
 
-	return nil
+	return runSeancePlan(plan, prompt != "")
 }
 
 // clearClaudeCodeEnv returns a copy of the environment with Claude Code
@@ -342,6 +326,15 @@ func discoverSessions(townRoot string) ([]sessionEvent, error) {
 	return sessions, scanner.Err()
 }
 
+// seanceCell fits s into a table column width runes wide, marking a cut
+// with "…". Counting runes keeps multi-byte roles and topics intact.
+func seanceCell(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
+
 // resolveSessionPrefix resolves a truncated session ID prefix to the full UUID
 // by searching session_start events. Returns an error if zero or multiple matches.
 func resolveSessionPrefix(townRoot, prefix string) (string, error) {
@@ -375,6 +368,21 @@ func resolveSessionPrefix(townRoot, prefix string) (string, error) {
 	}
 }
 
+// findSessionEvent returns the most recent session_start event for a
+// session ID, or nil if there is none.
+func findSessionEvent(townRoot, sessionID string) *sessionEvent {
+	sessions, err := discoverSessions(townRoot)
+	if err != nil {
+		return nil
+	}
+	for i := range sessions {
+		if getPayloadString(sessions[i].Payload, "session_id") == sessionID {
+			return &sessions[i]
+		}
+	}
+	return nil
+}
+
 func getPayloadString(payload map[string]interface{}, key string) string {
 	if v, ok := payload[key]; ok {
 		if s, ok := v.(string); ok {
@@ -0,0 +1,395 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// How a seance reaches the predecessor.
+const (
+	// seanceModeFork forks the predecessor's session (claude --fork-session),
+	// leaving the original untouched.
+	seanceModeFork = "fork"
+	// seanceModeResume resumes a copy of the predecessor's session with its
+	// own agent, for agents that cannot fork: the agent runs with a scratch
+	// home holding a copy of its session store.
+	seanceModeResume = "resume"
+	// seanceModeTranscript hands the predecessor's normalized transcript to
+	// the town's default agent, for agents that cannot resume sessions.
+	seanceModeTranscript = "transcript"
+)
+
+// seanceTranscriptLimit caps the transcript embedded in a prompt, keeping
+// it well under the per-argument limit of execve.
+const seanceTranscriptLimit = 96 * 1024
+
+// seanceToolResultLimit caps each tool result in the transcript; the
+// predecessor's reasoning matters more than full command output.
+const seanceToolResultLimit = 2000
+
+// seancePlan is the agent invocation for one seance.
+type seancePlan struct {
+	Mode    string
+	Agent   string // agent that runs the seance
+	Command string
+	Args    []string
+	Env     map[string]string
+	// Dir is the predecessor's working directory, where agents look up
+	// the sessions of a project.
+	Dir string
+	// Store is the session store (config.AgentPresetInfo.SessionStore) a
+	// resume seance copies.
+	Store string
+}
+
+// seanceAgentName returns the agent a predecessor session ran on: the
+// --agent override, then the agent recorded in its session_start event.
+// Sessions recorded before events carried an agent were all Claude.
+func seanceAgentName(override string, event *sessionEvent) string {
+	if override != "" {
+		return override
+	}
+	if event != nil {
+		if agent := getPayloadString(event.Payload, "agent"); agent != "" {
+			return agent
+		}
+	}
+	return string(config.AgentClaude)
+}
+
+// oneShotArgs returns the arguments that make an agent answer prompt and
+// exit: pre goes before any other arguments (a subcommand such as codex
+// exec), post after them. A preset without NonInteractive settings takes
+// -p, as Claude does.
+func oneShotArgs(preset *config.AgentPresetInfo, prompt string) (pre, post []string, ok bool) {
+	var ni *config.NonInteractiveConfig
+	if preset != nil {
+		ni = preset.NonInteractive
+	}
+	switch {
+	case ni == nil:
+		return nil, []string{"-p", prompt}, true
+	case ni.PromptFlag != "":
+		return nil, []string{ni.PromptFlag, prompt}, true
+	case ni.Subcommand != "":
+		return []string{ni.Subcommand}, []string{prompt}, true
+	default:
+		return nil, nil, false
+	}
+}
+
+// planSeanceResume builds a seance that resumes the predecessor's session
+// with its own agent: forked when the agent supports that, otherwise from
+// a copy of its session store. It returns false when the agent cannot
+// resume sessions, has no local session store to copy, or cannot take a
+// one-shot prompt while resuming.
+func planSeanceResume(agentName, sessionID, prompt string) (*seancePlan, bool) {
+	preset := config.GetAgentPresetByName(agentName)
+	if preset == nil || !config.SupportsSessionResume(agentName) {
+		return nil, false
+	}
+	if !preset.SupportsForkSession && preset.SessionStore == "" {
+		return nil, false
+	}
+
+	plan := &seancePlan{
+		Mode:    seanceModeResume,
+		Agent:   agentName,
+		Command: config.RuntimeConfigFromPreset(preset.Name).Command,
+		Env:     preset.Env,
+	}
+	// ResumeFlag is a flag (--resume <id>) or a subcommand, possibly of
+	// several words (amp threads continue <id>); both take the ID next.
+	resume := append(strings.Fields(preset.ResumeFlag), sessionID)
+	if preset.SupportsForkSession {
+		plan.Mode = seanceModeFork
+		resume = append([]string{"--fork-session"}, resume...)
+	} else {
+		plan.Store = preset.SessionStore
+	}
+
+	if prompt == "" {
+		plan.Args = resume
+		return plan, true
+	}
+	pre, post, ok := oneShotArgs(preset, prompt)
+	if !ok {
+		return nil, false
+	}
+	plan.Args = append(append(pre, resume...), post...)
+	return plan, true
+}
+
+// seanceResumable reports whether a predecessor's session ID is one its
+// agent can resume. Agents without a session ID variable are recorded
+// under a generated <actor>-<pid> ID, which only a transcript seance can
+// use; events from before that was marked are recognized by the ID.
+func seanceResumable(event *sessionEvent) bool {
+	if event == nil {
+		return true
+	}
+	if resumable, ok := event.Payload["resumable"].(bool); ok && !resumable {
+		return false
+	}
+	id := getPayloadString(event.Payload, "session_id")
+	return id == "" || id != getPayloadString(event.Payload, "actor_pid")
+}
+
+// seanceWorkDir returns the working directory recorded in a predecessor's
+// session_start event, if it still exists.
+func seanceWorkDir(event *sessionEvent) string {
+	if event == nil {
+		return ""
+	}
+	dir := getPayloadString(event.Payload, "cwd")
+	if dir == "" {
+		return ""
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return ""
+	}
+	return dir
+}
+
+// copySeanceStore copies the plan's session store into a scratch home
+// directory and points the agent at it, so resuming leaves the
+// predecessor's session untouched. The returned cleanup removes the copy.
+func copySeanceStore(plan *seancePlan) (func(), error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	src := filepath.Join(home, plan.Store)
+	if _, err := os.Stat(src); err != nil {
+		return nil, fmt.Errorf("no %s session store at %s: %w", plan.Agent, src, err)
+	}
+	scratch, err := os.MkdirTemp("", "gt-seance-*")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(scratch) }
+	if err := copyTree(src, filepath.Join(scratch, plan.Store)); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("copying %s: %w", src, err)
+	}
+
+	env := make(map[string]string, len(plan.Env)+1)
+	for k, v := range plan.Env {
+		env[k] = v
+	}
+	env["HOME"] = scratch
+	plan.Env = env
+	return cleanup, nil
+}
+
+// copyTree copies the regular files, directories and symlinks under src
+// to dst. Sockets and other special files are skipped.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(target, data, info.Mode().Perm())
+		}
+		return nil
+	})
+}
+
+// planSeanceTranscript builds a seance that feeds the predecessor's
+// transcript to the town's default agent. The transcript is read with the
+// agentlog adapter for the predecessor's agent, from the working directory
+// recorded in its session_start event.
+func planSeanceTranscript(townRoot, agentName, sessionID string, event *sessionEvent, prompt string) (*seancePlan, error) {
+	var workDir string
+	var since time.Time
+	if event != nil {
+		workDir = getPayloadString(event.Payload, "cwd")
+		since, _ = time.Parse(time.RFC3339, event.Timestamp)
+	}
+	transcript, err := agentlog.ReadTranscript(agentlog.AgentTypeForPreset(agentName), sessionID, workDir, since)
+	if errors.Is(err, agentlog.ErrNoTranscript) && workDir == "" {
+		return nil, fmt.Errorf("%s cannot resume sessions and session %s has no recorded working directory to find its transcript in", agentName, sessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s cannot resume sessions, and reading its transcript failed: %w", agentName, err)
+	}
+	if len(transcript) == 0 {
+		return nil, fmt.Errorf("%s cannot resume sessions, and the transcript of session %s is empty", agentName, sessionID)
+	}
+
+	var rc *config.RuntimeConfig
+	if townRoot != "" {
+		rc = config.ResolveAgentConfig(townRoot, "")
+	} else {
+		rc = config.RuntimeConfigFromPreset(config.DefaultAgentPreset())
+		rc.ResolvedAgent = string(config.DefaultAgentPreset())
+	}
+	preset := config.GetAgentPresetByName(rc.ResolvedAgent)
+
+	grounding := seanceTranscriptPrompt(agentName, sessionID, transcript, prompt)
+	plan := &seancePlan{
+		Mode:    seanceModeTranscript,
+		Agent:   rc.ResolvedAgent,
+		Command: rc.Command,
+		Env:     rc.Env,
+	}
+	if prompt != "" {
+		pre, post, ok := oneShotArgs(preset, grounding)
+		if !ok {
+			return nil, fmt.Errorf("default agent %s has no non-interactive mode for a one-shot seance", rc.ResolvedAgent)
+		}
+		plan.Args = append(pre, post...)
+		return plan, nil
+	}
+	if rc.PromptMode == "none" {
+		return nil, fmt.Errorf("default agent %s takes no initial prompt; ask a one-shot question with --prompt", rc.ResolvedAgent)
+	}
+	plan.Args = []string{grounding}
+	return plan, nil
+}
+
+// seanceTranscriptPrompt renders a predecessor's transcript as a prompt
+// that asks the agent to answer in the predecessor's place. Thinking and
+// token usage are dropped, tool results are shortened, and when the
+// transcript is still too long its beginning is cut: the end of a session
+// is what successors ask about.
+func seanceTranscriptPrompt(agentName, sessionID string, transcript []agentlog.AgentEvent, question string) string {
+	var lines []string
+	for _, ev := range transcript {
+		content := strings.TrimSpace(ev.Content)
+		if content == "" {
+			continue
+		}
+		switch ev.EventType {
+		case "text":
+			lines = append(lines, fmt.Sprintf("[%s] %s", ev.Role, content))
+		case "tool_use":
+			lines = append(lines, "[tool call] "+content)
+		case "tool_result":
+			if len(content) > seanceToolResultLimit {
+				content = headBytes(content, seanceToolResultLimit) + " …"
+			}
+			lines = append(lines, "[tool result] "+content)
+		}
+	}
+	body := strings.Join(lines, "\n")
+	if len(body) > seanceTranscriptLimit {
+		body = tailBytes(body, seanceTranscriptLimit)
+		if i := strings.IndexByte(body, '\n'); i >= 0 {
+			body = body[i+1:]
+		}
+		body = "[… earlier transcript omitted …]\n" + body
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are standing in for a predecessor Gas Town session (%s, run on %s) that cannot be resumed. ", sessionID, agentName)
+	b.WriteString("Below is its conversation transcript. Answer as that predecessor, from what the transcript shows; ")
+	b.WriteString("say so plainly when it does not cover something.\n\n")
+	b.WriteString("<transcript>\n")
+	b.WriteString(body)
+	b.WriteString("\n</transcript>\n\n")
+	if question != "" {
+		b.WriteString("Question: " + question)
+	} else {
+		b.WriteString("Reply with one line confirming you have read the transcript, then wait for questions.")
+	}
+	return b.String()
+}
+
+// runSeancePlan runs the seance agent, interactively unless oneShot.
+func runSeancePlan(plan *seancePlan, oneShot bool) error {
+	// Clear CLAUDECODE env var so the subprocess doesn't trigger the nested
+	// session guard. Seance is intentionally spawning a new agent instance
+	// to read a predecessor's context — not a true nested session.
+	env := clearClaudeCodeEnv(os.Environ())
+	for k, v := range plan.Env {
+		env = append(env, k+"="+v)
+	}
+
+	cmd := exec.Command(plan.Command, plan.Args...) //nolint:gosec // G204: command comes from the agent registry
+	cmd.Env = env
+	cmd.Dir = plan.Dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if oneShot {
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("seance failed: %w", err)
+		}
+		return nil
+	}
+
+	cmd.Stdin = os.Stdin
+	fmt.Printf("%s\n", style.Dim.Render("You are now talking to your predecessor. Ask them anything."))
+	fmt.Printf("%s\n\n", style.Dim.Render("Exit with /exit or Ctrl+C"))
+
+	if err := cmd.Run(); err != nil {
+		// Exit errors are normal when user exits
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if exitErr.ExitCode() == 0 || exitErr.ExitCode() == 130 {
+				return nil // Normal exit or Ctrl+C
+			}
+		}
+		return fmt.Errorf("seance ended: %w", err)
+	}
+	return nil
+}
+
+// headBytes returns at most n bytes from the start of s, cut on a rune
+// boundary.
+func headBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// tailBytes returns at most n bytes from the end of s, cut on a rune
+// boundary.
+func tailBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	i := len(s) - n
+	for i < len(s) && !utf8.RuneStart(s[i]) {
+		i++
+	}
+	return s[i:]
+}
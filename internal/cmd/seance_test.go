@@ -8,7 +8,9 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
+	"github.com/steveyegge/gastown/internal/agentlog"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 )
@@ -502,3 +504,250 @@ func TestResolveSessionPrefix(t *testing.T) {
 		}
 	})
 }
+
+func TestPlanSeanceResume(t *testing.T) {
+	tests := []struct {
+		agent    string
+		prompt   string
+		wantMode string
+		wantArgs string
+	}{
+		{"claude", "", seanceModeFork, "--fork-session --resume sid"},
+		{"claude", "Where is X?", seanceModeFork, "--fork-session --resume sid -p Where is X?"},
+		{"gemini", "", seanceModeResume, "--resume sid"},
+		{"gemini", "Where is X?", seanceModeResume, "--resume sid -p Where is X?"},
+		{"codex", "", seanceModeResume, "resume sid"},
+		{"codex", "Where is X?", seanceModeResume, "exec resume sid Where is X?"},
+		{"amp", "", "", ""},
+		{"copilot", "Where is X?", seanceModeResume, "--resume sid -p Where is X?"},
+		{"opencode", "", "", ""},
+		{"pi", "Where is X?", "", ""},
+		{"no-such-agent", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.agent+"/"+tt.prompt, func(t *testing.T) {
+			plan, ok := planSeanceResume(tt.agent, "sid", tt.prompt)
+			if tt.wantMode == "" {
+				if ok {
+					t.Fatalf("expected no resume plan, got %+v", plan)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("expected a resume plan")
+			}
+			if plan.Mode != tt.wantMode {
+				t.Errorf("Mode = %q, want %q", plan.Mode, tt.wantMode)
+			}
+			if got := strings.Join(plan.Args, " "); got != tt.wantArgs {
+				t.Errorf("Args = %q, want %q", got, tt.wantArgs)
+			}
+			if (plan.Store != "") != (plan.Mode == seanceModeResume) {
+				t.Errorf("Store = %q for mode %s", plan.Store, plan.Mode)
+			}
+		})
+	}
+}
+
+func TestSeanceResumable(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload map[string]interface{}
+		want    bool
+	}{
+		{"agent session", map[string]interface{}{"session_id": "abc", "actor_pid": "gastown/crew/max-42"}, true},
+		{"marked", map[string]interface{}{"session_id": "x", "actor_pid": "y", "resumable": false}, false},
+		{"generated", map[string]interface{}{"session_id": "gastown/crew/max-42", "actor_pid": "gastown/crew/max-42"}, false},
+	}
+	for _, tt := range tests {
+		if got := seanceResumable(&sessionEvent{Payload: tt.payload}); got != tt.want {
+			t.Errorf("%s: seanceResumable = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if !seanceResumable(nil) {
+		t.Error("a session without an event should be tried")
+	}
+}
+
+func TestSeanceWorkDir(t *testing.T) {
+	dir := t.TempDir()
+	if got := seanceWorkDir(&sessionEvent{Payload: map[string]interface{}{"cwd": dir}}); got != dir {
+		t.Errorf("seanceWorkDir = %q, want %q", got, dir)
+	}
+	gone := filepath.Join(dir, "gone")
+	if got := seanceWorkDir(&sessionEvent{Payload: map[string]interface{}{"cwd": gone}}); got != "" {
+		t.Errorf("seanceWorkDir(missing) = %q, want empty", got)
+	}
+}
+
+func TestCopySeanceStore(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	session := filepath.Join(home, ".gemini", "tmp", "proj", "chats", "session-sid.json")
+	if err := os.MkdirAll(filepath.Dir(session), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(session, []byte(`{"messages":[]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, ok := planSeanceResume("gemini", "sid", "")
+	if !ok {
+		t.Fatal("expected a resume plan")
+	}
+	cleanup, err := copySeanceStore(plan)
+	if err != nil {
+		t.Fatalf("copySeanceStore: %v", err)
+	}
+	scratch := plan.Env["HOME"]
+	if scratch == "" || scratch == home {
+		t.Fatalf("HOME = %q, want a scratch home", scratch)
+	}
+	copied := filepath.Join(scratch, ".gemini", "tmp", "proj", "chats", "session-sid.json")
+	if err := os.WriteFile(copied, []byte(`{"messages":["seance"]}`), 0600); err != nil {
+		t.Fatalf("copy missing: %v", err)
+	}
+	if data, _ := os.ReadFile(session); string(data) != `{"messages":[]}` {
+		t.Errorf("predecessor session modified: %s", data)
+	}
+	if preset := config.GetAgentPresetByName("gemini"); preset.Env["HOME"] != "" {
+		t.Error("copySeanceStore changed the preset's env")
+	}
+
+	cleanup()
+	if _, err := os.Stat(scratch); !os.IsNotExist(err) {
+		t.Errorf("scratch home not removed: %v", err)
+	}
+}
+
+func TestSeanceAgentName(t *testing.T) {
+	event := &sessionEvent{Payload: map[string]interface{}{"session_id": "sid", "agent": "gemini"}}
+	if got := seanceAgentName("", event); got != "gemini" {
+		t.Errorf("recorded agent: got %q", got)
+	}
+	if got := seanceAgentName("codex", event); got != "codex" {
+		t.Errorf("override: got %q", got)
+	}
+	if got := seanceAgentName("", &sessionEvent{Payload: map[string]interface{}{}}); got != "claude" {
+		t.Errorf("legacy event: got %q, want claude", got)
+	}
+}
+
+func TestFindSessionEvent(t *testing.T) {
+	townRoot := t.TempDir()
+	writeTestEvents(t, townRoot, []string{"aaa", "bbb", "aaa"})
+
+	event := findSessionEvent(townRoot, "aaa")
+	if event == nil || event.Timestamp != "2026-01-22T02:00:00Z" {
+		t.Fatalf("expected the most recent aaa event, got %+v", event)
+	}
+	if findSessionEvent(townRoot, "ccc") != nil {
+		t.Error("expected nil for an unknown session")
+	}
+}
+
+func TestPlanSeanceTranscript(t *testing.T) {
+	data := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", data)
+	storage := filepath.Join(data, "opencode", "storage")
+	workDir := t.TempDir()
+	files := map[string]string{
+		filepath.Join("session", "p", "ses_1.json"):  `{"id":"ses_1","directory":"` + workDir + `","time":{"created":1,"updated":2}}`,
+		filepath.Join("message", "ses_1", "m1.json"): `{"id":"m1","role":"assistant","time":{"created":1}}`,
+		filepath.Join("part", "m1", "p1.json"):       `{"id":"p1","type":"text","text":"the stash is in /tmp/stash"}`,
+		filepath.Join("part", "m1", "p2.json"):       `{"id":"p2","type":"reasoning","text":"secret musing"}`,
+	}
+	for rel, content := range files {
+		path := filepath.Join(storage, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	event := &sessionEvent{
+		Timestamp: "1970-01-01T00:00:00Z",
+		Payload:   map[string]interface{}{"session_id": "gastown/polecats/nux-42", "cwd": workDir},
+	}
+
+	plan, err := planSeanceTranscript("", "opencode", "gastown/polecats/nux-42", event, "Where is the stash?")
+	if err != nil {
+		t.Fatalf("planSeanceTranscript: %v", err)
+	}
+	if plan.Mode != seanceModeTranscript || plan.Agent != "claude" {
+		t.Errorf("plan = %+v, want a transcript seance on claude", plan)
+	}
+	if len(plan.Args) != 2 || plan.Args[0] != "-p" {
+		t.Fatalf("Args = %q, want -p <prompt>", plan.Args)
+	}
+	prompt := plan.Args[1]
+	for _, want := range []string{"the stash is in /tmp/stash", "Question: Where is the stash?", "opencode"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "secret musing") {
+		t.Error("thinking should not be fed to the seance agent")
+	}
+
+	if _, err := planSeanceTranscript("", "opencode", "sid", nil, ""); err == nil {
+		t.Error("expected an error without a recorded working directory")
+	}
+}
+
+func TestSeanceTranscriptPromptKeepsTail(t *testing.T) {
+	var transcript []agentlog.AgentEvent
+	for i := 0; i < 5000; i++ {
+		transcript = append(transcript, agentlog.AgentEvent{
+			EventType: "text",
+			Role:      "assistant",
+			Content:   fmt.Sprintf("step %d of a long session", i),
+		})
+	}
+	prompt := seanceTranscriptPrompt("opencode", "sid", transcript, "")
+	if len(prompt) > seanceTranscriptLimit+1024 {
+		t.Errorf("prompt is %d bytes, want about %d", len(prompt), seanceTranscriptLimit)
+	}
+	if !strings.Contains(prompt, "step 4999 of") || strings.Contains(prompt, "step 0 of") {
+		t.Error("expected the end of the transcript to be kept and the start dropped")
+	}
+	if !strings.Contains(prompt, "earlier transcript omitted") {
+		t.Error("expected a truncation marker")
+	}
+}
+
+func TestSeanceTruncationKeepsRunes(t *testing.T) {
+	if got := seanceCell("gastown/polecats/日本語ワーカー", 20); got != "gastown/polecats/日本…" {
+		t.Errorf("seanceCell() = %q", got)
+	}
+	if got := seanceCell("crew/zoë", 8); got != "crew/zoë" {
+		t.Errorf("seanceCell() = %q, want it unchanged", got)
+	}
+
+	s := strings.Repeat("é日", 100) // 2- and 3-byte runes
+	for n := 0; n <= 12; n++ {
+		head, tail := headBytes(s, n), tailBytes(s, n)
+		if len(head) > n || !utf8.ValidString(head) || !strings.HasPrefix(s, head) {
+			t.Errorf("headBytes(%d) = %q", n, head)
+		}
+		if len(tail) > n || !utf8.ValidString(tail) || !strings.HasSuffix(s, tail) {
+			t.Errorf("tailBytes(%d) = %q", n, tail)
+		}
+	}
+
+	// A long non-ASCII tool result, and a transcript over the limit with no
+	// newline to cut at, both stay valid UTF-8.
+	prompt := seanceTranscriptPrompt("opencode", "sid", []agentlog.AgentEvent{
+		{EventType: "tool_result", Role: "user", Content: strings.Repeat("ü", seanceToolResultLimit)},
+	}, "")
+	if !utf8.ValidString(prompt) || !strings.Contains(prompt, "ü …") {
+		t.Errorf("tool result not cut cleanly: valid=%v", utf8.ValidString(prompt))
+	}
+	prompt = seanceTranscriptPrompt("opencode", "sid", []agentlog.AgentEvent{
+		{EventType: "text", Role: "assistant", Content: strings.Repeat("日", seanceTranscriptLimit)},
+	}, "")
+	if !utf8.ValidString(prompt) || !strings.Contains(prompt, "earlier transcript omitted") {
+		t.Errorf("transcript not cut cleanly: valid=%v", utf8.ValidString(prompt))
+	}
+}
@@ -11,9 +11,11 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/selector"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/suggest"
@@ -32,6 +34,8 @@ var (
 	sessionRigFilter  string
 	sessionListJSON   bool
 	sessionStatusJSON bool
+	sessionSelect     string
+	sessionDryRun     bool
 )
 
 var sessionCmd = &cobra.Command{
@@ -70,8 +74,16 @@ var sessionStopCmd = &cobra.Command{
 	Long: `Stop a running polecat session.
 
 Attempts graceful shutdown first (Ctrl-C), then kills the tmux session.
-Use --force to skip graceful shutdown.`,
-	Args: cobra.ExactArgs(1),
+Use --force to skip graceful shutdown.
+
+With --select, stops every running polecat the selector matches.
+
+` + selectorLongHelp + `
+
+Examples:
+  gt session stop wyvern/Toast
+  gt session stop --select 'rig=wyvern,idle>2h' --dry-run`,
+	Args: sessionTargetArgs,
 	RunE: runSessionStop,
 }
 
@@ -135,8 +147,16 @@ var sessionRestartCmd = &cobra.Command{
 	Long: `Restart a polecat session (stop + start).
 
 Gracefully stops the current session and starts a fresh one.
-Use --force to skip graceful shutdown.`,
-	Args: cobra.ExactArgs(1),
+Use --force to skip graceful shutdown.
+
+With --select, restarts every polecat the selector matches.
+
+` + selectorLongHelp + `
+
+Examples:
+  gt session restart wyvern/Toast
+  gt session restart --select 'role=polecat,state=stuck'`,
+	Args: sessionTargetArgs,
 	RunE: runSessionRestart,
 }
 
@@ -174,6 +194,8 @@ func init() {
 
 	// Stop flags
 	sessionStopCmd.Flags().BoolVarP(&sessionForce, "force", "f", false, "Force immediate shutdown")
+	sessionStopCmd.Flags().StringVar(&sessionSelect, "select", "", selectorHelp)
+	sessionStopCmd.Flags().BoolVar(&sessionDryRun, "dry-run", false, "With --select, list the matched polecats without stopping them")
 
 	// List flags
 	sessionListCmd.Flags().StringVar(&sessionRigFilter, "rig", "", "Filter by rig name")
@@ -188,6 +210,8 @@ func init() {
 
 	// Restart flags
 	sessionRestartCmd.Flags().BoolVarP(&sessionForce, "force", "f", false, "Force immediate shutdown")
+	sessionRestartCmd.Flags().StringVar(&sessionSelect, "select", "", selectorHelp)
+	sessionRestartCmd.Flags().BoolVar(&sessionDryRun, "dry-run", false, "With --select, list the matched polecats without restarting them")
 
 	// Status flags
 	sessionStatusCmd.Flags().BoolVar(&sessionStatusJSON, "json", false, "Output as JSON")
@@ -289,8 +313,72 @@ func runSessionStart(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// sessionTargetArgs takes one <rig>/<polecat> target, or none with --select.
+func sessionTargetArgs(cmd *cobra.Command, args []string) error {
+	if sessionSelect != "" {
+		return cobra.NoArgs(cmd, args)
+	}
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
+// runSessionSelect applies a single-polecat session operation to every
+// polecat a --select selector matches. Other roles are skipped: session
+// commands manage polecats only.
+func runSessionSelect(verb string, runningOnly bool, op func(address string) error) error {
+	if sessionDryRun && sessionSelect == "" {
+		return fmt.Errorf("--dry-run requires --select")
+	}
+	sel, err := parseSelectFlag(sessionSelect)
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	matches, err := selectAgents(townRoot, sel)
+	if err != nil {
+		return err
+	}
+
+	var polecats []*selector.Agent
+	for _, a := range matches {
+		if a.Role == constants.RolePolecat && (a.Running() || !runningOnly) {
+			polecats = append(polecats, a)
+		}
+	}
+	if sessionDryRun {
+		printSelectedAgents(sel, polecats)
+		return nil
+	}
+	if len(polecats) == 0 {
+		fmt.Printf("No polecats match %s\n", sel)
+		return nil
+	}
+
+	var failed []string
+	for _, a := range polecats {
+		if err := op(a.Rig + "/" + a.Name); err != nil {
+			fmt.Printf("%s %s: %v\n", style.ErrorPrefix, a.Address, err)
+			failed = append(failed, a.Address)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("could not %s %d of %d session(s): %s", verb, len(failed), len(polecats), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
 func runSessionStop(cmd *cobra.Command, args []string) error {
-	rigName, polecatName, err := parseAddress(args[0])
+	if sessionSelect != "" || sessionDryRun {
+		return runSessionSelect("stop", true, stopPolecatSession)
+	}
+	return stopPolecatSession(args[0])
+}
+
+// stopPolecatSession stops one polecat session.
+func stopPolecatSession(address string) error {
+	rigName, polecatName, err := parseAddress(address)
 	if err != nil {
 		return err
 	}
@@ -495,7 +583,15 @@ func runSessionInject(cmd *cobra.Command, args []string) error {
 }
 
 func runSessionRestart(cmd *cobra.Command, args []string) error {
-	rigName, polecatName, err := parseAddress(args[0])
+	if sessionSelect != "" || sessionDryRun {
+		return runSessionSelect("restart", false, restartPolecatSession)
+	}
+	return restartPolecatSession(args[0])
+}
+
+// restartPolecatSession restarts one polecat session.
+func restartPolecatSession(address string) error {
+	rigName, polecatName, err := parseAddress(address)
 	if err != nil {
 		return err
 	}
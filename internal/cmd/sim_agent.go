@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/simagent"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var simAgentScript string
+
+var simAgentCmd = &cobra.Command{
+	Use:   "sim-agent [prompt]",
+	Short: "Scripted stand-in agent for offline testing (run by the sim preset)",
+	Long: `Run the deterministic simulated agent behind the "sim" agent preset.
+
+The sim agent reads its prime and hook like a real agent, then plays back
+the behaviors scripted for it: completing molecule steps, committing files,
+crashing, hanging, running out of context or hitting a rate limit. When
+the script ends it idles and runs any gt commands it is nudged with.
+
+The script is taken from --script, then $GT_SIM_SCRIPT, then
+<town>/settings/sim.yaml. Without one the agent primes, checks its hook
+and idles. Select it like any preset, e.g. "role_agents": {"polecat": "sim"}
+in settings/config.json.`,
+	Hidden: true,
+	Args:   cobra.MaximumNArgs(1),
+	RunE:   runSimAgent,
+}
+
+func init() {
+	simAgentCmd.Flags().StringVar(&simAgentScript, "script", "", "Script file (default: $GT_SIM_SCRIPT or <town>/settings/sim.yaml)")
+	rootCmd.AddCommand(simAgentCmd)
+}
+
+func runSimAgent(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	townRoot, _ := workspace.FindFromCwd()
+
+	script := simagent.DefaultScript()
+	if path := simagent.ScriptPath(simAgentScript, townRoot); path != "" {
+		if script, err = simagent.Load(path); err != nil {
+			return err
+		}
+	}
+
+	agent := &simagent.Agent{
+		Actions: script.ActionsFor(simagent.IdentityFromEnv()),
+		WorkDir: cwd,
+		Prompt:  strings.Join(args, " "),
+		Out:     os.Stdout,
+		In:      os.Stdin,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	err = agent.Run(ctx)
+	var exitErr *simagent.ExitError
+	if errors.As(err, &exitErr) {
+		fmt.Fprintln(os.Stderr, exitErr)
+		os.Exit(exitErr.Code)
+	}
+	return err
+}
@@ -0,0 +1,159 @@
+//go:build e2e
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// simTownScript crashes the deacon and every witness shortly after they
+// prime, so the daemon has to bring them back. Everyone else idles.
+const simTownScript = `agents:
+  deacon: [prime, {crash: 2s}]
+  witness: [prime, {crash: 2s}]
+default: [prime, idle]
+`
+
+// TestSimTownRecoversPatrolAgents starts a town whose agents all run the
+// sim preset, has the deacon and witness crash after priming, and checks
+// that the daemon restarts both. Each gt prime logs a session_start event,
+// so a second event for an actor means the daemon recovered it.
+func TestSimTownRecoversPatrolAgents(t *testing.T) {
+	tmpDir := t.TempDir()
+	hqPath := filepath.Join(tmpDir, "sim-hq")
+	gtBinary := buildGT(t)
+
+	// The sim preset runs "gt sim-agent", so gt must be on PATH under that name.
+	binDir := filepath.Join(tmpDir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(gtBinary, filepath.Join(binDir, "gt")); err != nil {
+		t.Fatal(err)
+	}
+
+	env := cleanE2EEnv()
+	env = append(env, "HOME="+tmpDir, "PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	_ = exec.Command("pkill", "-f", "dolt sql-server").Run()
+	configureGitIdentity(t, env)
+
+	runGTCmd(t, gtBinary, tmpDir, env, "install", hqPath, "--name", "sim-town", "--git")
+	t.Cleanup(func() {
+		for _, args := range [][]string{{"daemon", "stop"}, {"dolt", "stop"}} {
+			cmd := exec.Command(gtBinary, args...)
+			cmd.Dir = hqPath
+			cmd.Env = env
+			_ = cmd.Run()
+		}
+		_ = exec.Command("pkill", "-f", "dolt sql-server").Run()
+	})
+
+	runGTCmd(t, gtBinary, hqPath, env, "rig", "add", "simrig",
+		"https://github.com/octocat/Hello-World.git", "--prefix", "sr")
+	runGTCmd(t, gtBinary, hqPath, env, "config", "default-agent", "sim")
+
+	if err := os.WriteFile(filepath.Join(hqPath, "settings", "sim.yaml"), []byte(simTownScript), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Heartbeat every few seconds and retry a crashed agent after a second,
+	// instead of the production 3m heartbeat and 30s initial backoff.
+	updateJSONFile(t, filepath.Join(hqPath, "settings", "config.json"), func(m map[string]interface{}) {
+		op := jsonObject(m, "operational")
+		jsonObject(op, "daemon")["recovery_heartbeat_interval"] = "5s"
+	})
+	updateJSONFile(t, filepath.Join(hqPath, "mayor", "daemon.json"), func(m map[string]interface{}) {
+		patrols := jsonObject(m, "patrols")
+		jsonObject(patrols, "restart_tracker")["initial_backoff"] = int64(time.Second)
+	})
+
+	runGTCmd(t, gtBinary, hqPath, env, "daemon", "start")
+
+	want := []string{"deacon", "simrig/witness"}
+	deadline := time.Now().Add(3 * time.Minute)
+	for {
+		starts := countSessionStarts(t, hqPath)
+		recovered := true
+		for _, actor := range want {
+			if starts[actor] < 2 {
+				recovered = false
+			}
+		}
+		if recovered {
+			return
+		}
+		if time.Now().After(deadline) {
+			log, _ := os.ReadFile(filepath.Join(hqPath, "daemon", "daemon.log"))
+			t.Fatalf("daemon did not restart crashed agents; session starts: %v\ndaemon log:\n%s", starts, log)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// countSessionStarts counts session_start events per actor in the town's
+// event log.
+func countSessionStarts(t *testing.T, townRoot string) map[string]int {
+	t.Helper()
+	counts := make(map[string]int)
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return counts
+		}
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if json.Unmarshal(scanner.Bytes(), &e) != nil {
+			continue
+		}
+		if e.Type == events.TypeSessionStart {
+			counts[e.Actor]++
+		}
+	}
+	return counts
+}
+
+// updateJSONFile applies fn to the JSON object stored at path, starting
+// from an empty object when the file does not exist.
+func updateJSONFile(t *testing.T, path string, fn func(map[string]interface{})) {
+	t.Helper()
+	m := make(map[string]interface{})
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &m); err != nil {
+			t.Fatalf("parsing %s: %v", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	fn(m)
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// jsonObject returns m[key] as an object, creating it if absent.
+func jsonObject(m map[string]interface{}, key string) map[string]interface{} {
+	if obj, ok := m[key].(map[string]interface{}); ok {
+		return obj
+	}
+	obj := make(map[string]interface{})
+	m[key] = obj
+	return obj
+}
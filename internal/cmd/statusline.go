@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/ctxpressure"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -40,6 +41,9 @@ func init() {
 func runStatusLine(cmd *cobra.Command, args []string) error {
 	t := tmux.NewTmux()
 
+	// Context-window pressure follows whatever the role's status line shows.
+	defer printStatusLineContext(t, statusLineSession)
+
 	// Get session environment
 	var rigName, polecat, crew, issue, role string
 
@@ -87,6 +91,25 @@ func runStatusLine(cmd *cobra.Command, args []string) error {
 	return runWorkerStatusLine(t, statusLineSession, rigName, polecat, crew, issue)
 }
 
+// printStatusLineContext outputs the session's context-window pressure, e.g.
+// "🧠 82% |". Nothing is printed until the agent has finished a turn.
+func printStatusLineContext(t *tmux.Tmux, session string) {
+	if session == "" {
+		return
+	}
+	var cfg *config.ContextThresholds
+	if paneDir, err := t.GetPaneWorkDir(session); err == nil && paneDir != "" {
+		if townRoot, _ := workspace.Find(paneDir); townRoot != "" {
+			cfg = config.LoadOperationalConfig(townRoot).GetContextConfig()
+		}
+	}
+	reading, err := ctxpressure.ForSession(t, session, cfg)
+	if err != nil || reading == nil {
+		return
+	}
+	fmt.Printf(" %s |", reading.Badge())
+}
+
 // runWorkerStatusLine outputs status for crew or polecat sessions.
 func runWorkerStatusLine(t *tmux.Tmux, session, rigName, polecat, crew, issue string) error {
 	// Determine agent type and identity
@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/townbundle"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	townExportOutput string
+	townExportRedact bool
+	townExportDolt   bool
+
+	townImportCheck   bool
+	townImportNoRigs  bool
+	townImportNoDolt  bool
+	townImportNoStart bool
+	townImportGit     bool
+	townImportGitHub  string
+	townImportPublic  bool
+)
+
+var townExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the town's configuration to a bundle",
+	Long: `Export the town to a single versioned bundle for moving or cloning it.
+
+The bundle is a gzipped tar with a manifest (format version, checksums,
+rig git URLs) and the town's configuration:
+
+  mayor/town.json, rigs.json, config.json, accounts.json, daemon.json
+  settings/ (including namepool themes)
+  hooks/registry.toml
+  .beads/metadata.json, config.yaml, routes.jsonl
+  .beads/formulas/ (custom formulas)
+  plugins/ and each rig's settings/ and plugins/
+
+Rig clones are not included: gt town import re-clones them from git.
+
+--redact replaces tokens, passwords, API keys and credentials embedded in
+URLs in JSON and TOML files with "<redacted>"; the manifest lists every
+redacted field.
+--with-dolt adds a SQL dump of every town and rig database.
+
+Examples:
+  gt town export                            # hq-20260301-120000.gt.tar.gz
+  gt town export -o town.gt.tar.gz --redact
+  gt town export --with-dolt                # Include bead databases`,
+	RunE: runTownExport,
+}
+
+var townImportCmd = &cobra.Command{
+	Use:   "import <bundle> [path]",
+	Short: "Create a town from an exported bundle",
+	Long: `Create a town at path (default: current directory) from a bundle made
+by gt town export.
+
+Import validates the bundle (format version and checksums), writes the town
+configuration with the old town root and home directory rewritten to the
+new ones and restores bundled Dolt databases. It then scaffolds the rest
+of the town the way gt install does (CLAUDE.md, mayor/deacon/boot
+directories and settings, the HQ Dolt database and town beads, routing
+mode, slash commands and hooks), keeping everything the bundle provided.
+Finally it re-clones every rig from its git URL and runs the gt upgrade
+migrations and a doctor --fix.
+
+A rig that fails to clone is dropped from rigs.json with a warning; add it
+again with gt rig add. Redacted secrets must be filled back in by hand.
+
+Examples:
+  gt town import town.gt.tar.gz ~/gt
+  gt town import town.gt.tar.gz --check      # Validate and describe only
+  gt town import town.gt.tar.gz ~/gt --no-rigs
+  gt town import town.gt.tar.gz ~/gt --git   # Also make the HQ a git repo`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runTownImport,
+}
+
+func init() {
+	townExportCmd.Flags().StringVarP(&townExportOutput, "output", "o", "", "Bundle file to write (default: <town>-<timestamp>.gt.tar.gz)")
+	townExportCmd.Flags().BoolVar(&townExportRedact, "redact", false, "Replace secrets with placeholders")
+	townExportCmd.Flags().BoolVar(&townExportDolt, "with-dolt", false, "Include SQL dumps of the Dolt databases")
+
+	townImportCmd.Flags().BoolVar(&townImportCheck, "check", false, "Validate the bundle and show its contents without importing")
+	townImportCmd.Flags().BoolVar(&townImportNoRigs, "no-rigs", false, "Do not re-clone rigs")
+	townImportCmd.Flags().BoolVar(&townImportNoDolt, "no-dolt", false, "Do not restore bundled Dolt databases")
+	townImportCmd.Flags().BoolVar(&townImportNoStart, "no-start", false, "Suppress starting daemon/agents during doctor fix")
+	townImportCmd.Flags().BoolVar(&townImportGit, "git", false, "Initialize git with .gitignore")
+	townImportCmd.Flags().StringVar(&townImportGitHub, "github", "", "Create GitHub repo (format: owner/repo, private by default)")
+	townImportCmd.Flags().BoolVar(&townImportPublic, "public", false, "Make GitHub repo public (use with --github)")
+
+	townCmd.AddCommand(townExportCmd)
+	townCmd.AddCommand(townImportCmd)
+}
+
+func runTownExport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	out := townExportOutput
+	if out == "" {
+		name := filepath.Base(townRoot)
+		if town, err := config.LoadTownConfig(constants.MayorTownPath(townRoot)); err == nil && town.Name != "" {
+			name = town.Name
+		}
+		out = fmt.Sprintf("%s-%s.gt.tar.gz", name, time.Now().Format("20060102-150405"))
+	}
+
+	// Write next to the destination and rename, so a failed export never
+	// leaves a truncated bundle behind.
+	f, err := os.CreateTemp(filepath.Dir(out), ".gt-bundle-*")
+	if err != nil {
+		return fmt.Errorf("creating bundle: %w", err)
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	m, err := townbundle.Export(townRoot, f, townbundle.ExportOptions{
+		Redact:    townExportRedact,
+		Dolt:      townExportDolt,
+		GTVersion: Version,
+	})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("exporting town: %w", err)
+	}
+	if err := os.Rename(tmp, out); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+
+	fmt.Printf("%s Exported town to %s\n", style.Success.Render("✓"), style.Bold.Render(out))
+	printBundleManifest(m)
+	if !townExportRedact {
+		fmt.Printf("\n%s The bundle may contain secrets; use --redact to strip them.\n", style.Warning.Render("⚠"))
+	}
+	return nil
+}
+
+func runTownImport(cmd *cobra.Command, args []string) error {
+	b, err := townbundle.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading bundle: %w", err)
+	}
+	defer b.Close()
+	fmt.Printf("%s Bundle %s is valid\n", style.Success.Render("✓"), style.Bold.Render(args[0]))
+	printBundleManifest(b.Manifest)
+	if townImportCheck {
+		return nil
+	}
+
+	target := "."
+	if len(args) > 1 {
+		target = args[1]
+	}
+	townRoot, err := filepath.Abs(target)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(constants.MayorTownPath(townRoot)); err == nil {
+		return fmt.Errorf("%s is already a town; import into a new directory", townRoot)
+	}
+	home, _ := os.UserHomeDir()
+
+	// Preflight, as gt install does, before any workspace mutations.
+	if err := deps.EnsureBeads(true); err != nil {
+		return fmt.Errorf("beads dependency check failed: %w", err)
+	}
+	if err := doltserver.EnsureDoltIdentity(); err != nil {
+		return fmt.Errorf("configuring Dolt identity: %w", err)
+	}
+
+	fmt.Printf("\n%s Writing town configuration to %s...\n", style.Bold.Render("1."), townRoot)
+	written, err := b.InstallTown(townRoot, home)
+	if err != nil {
+		return fmt.Errorf("writing town configuration: %w", err)
+	}
+	fmt.Printf("   ✓ %d files\n", len(written))
+
+	// Databases are restored before scaffolding starts the Dolt server,
+	// which picks them up, and before town beads are initialized, which
+	// then finds the restored HQ database.
+	fmt.Printf("\n%s Restoring Dolt databases...\n", style.Bold.Render("2."))
+	switch {
+	case townImportNoDolt:
+		fmt.Printf("   %s\n", style.Dim.Render("skipped (--no-dolt)"))
+	case len(b.Manifest.Dumps) == 0:
+		fmt.Printf("   %s\n", style.Dim.Render("bundle has no databases"))
+	default:
+		dataDir := doltserver.DefaultConfig(townRoot).DataDir
+		for _, d := range b.Manifest.Dumps {
+			if err := restoreBundledDatabase(b, dataDir, d.Database); err != nil {
+				return fmt.Errorf("restoring database %s: %w", d.Database, err)
+			}
+			fmt.Printf("   ✓ %s\n", d.Database)
+		}
+	}
+
+	fmt.Printf("\n%s Scaffolding town...\n", style.Bold.Render("3."))
+	if err := scaffoldTown(townRoot, townScaffoldOptions{
+		Git:     townImportGit,
+		GitHub:  townImportGitHub,
+		Private: !townImportPublic,
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s Re-cloning rigs...\n", style.Bold.Render("4."))
+	var failed []townbundle.Rig
+	switch {
+	case townImportNoRigs:
+		fmt.Printf("   %s\n", style.Dim.Render("skipped (--no-rigs)"))
+	case len(b.Manifest.Rigs) == 0:
+		fmt.Printf("   %s\n", style.Dim.Render("bundle has no rigs"))
+	default:
+		if failed, err = importRigs(b, townRoot, home); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("\n%s Running migrations...\n", style.Bold.Render("5."))
+	upgradeNoStart = townImportNoStart
+	printUpgradeSummary(runUpgradeSteps(townRoot))
+
+	if len(failed) > 0 {
+		fmt.Printf("\n%s %d rig(s) could not be cloned; add them again with:\n", style.Warning.Render("⚠"), len(failed))
+		for _, r := range failed {
+			fmt.Printf("  gt rig add %s %s --prefix %s\n", r.Name, r.GitURL, r.Prefix)
+		}
+	}
+	if len(b.Manifest.Redacted) > 0 {
+		fmt.Printf("\n%s The bundle was redacted; fill these values back in:\n", style.Warning.Render("⚠"))
+		for _, field := range b.Manifest.Redacted {
+			fmt.Printf("  %s\n", field)
+		}
+	}
+	fmt.Printf("\n%s Town imported to %s\n", style.Success.Render("✓"), style.Bold.Render(townRoot))
+	return nil
+}
+
+func restoreBundledDatabase(b *townbundle.Bundle, dataDir, database string) error {
+	dump, err := b.OpenDump(database)
+	if err != nil {
+		return err
+	}
+	defer dump.Close()
+	return townbundle.RestoreDatabase(dataDir, database, dump)
+}
+
+// importRigs re-clones every bundled rig through the rig manager and then
+// restores its bundled files. A rig that fails to clone is dropped from
+// rigs.json and returned, so the rest of the town still comes up.
+func importRigs(b *townbundle.Bundle, townRoot, home string) ([]townbundle.Rig, error) {
+	if err := doltserver.EnsureDoltIdentity(); err != nil {
+		return nil, fmt.Errorf("configuring Dolt identity: %w", err)
+	}
+	if running, _, _ := doltserver.IsRunning(townRoot); !running {
+		if err := doltserver.Start(townRoot); err != nil {
+			return nil, fmt.Errorf("starting Dolt server (needed to add rigs): %w", err)
+		}
+	}
+
+	rigsPath := constants.MayorRigsPath(townRoot)
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		rigsConfig = &config.RigsConfig{
+			Version: 1,
+			Rigs:    make(map[string]config.RigEntry),
+		}
+	}
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+
+	var failed []townbundle.Rig
+	for _, r := range b.Manifest.Rigs {
+		fmt.Printf("   %s %s\n", style.Bold.Render(r.Name), style.Dim.Render(r.GitURL))
+		saved, had := rigsConfig.Rigs[r.Name]
+		// AddRig refuses rigs that are already registered.
+		delete(rigsConfig.Rigs, r.Name)
+		if _, err := mgr.AddRig(rig.AddRigOptions{
+			Name:        r.Name,
+			GitURL:      r.GitURL,
+			PushURL:     r.PushURL,
+			UpstreamURL: r.UpstreamURL,
+			BeadsPrefix: r.Prefix,
+		}); err != nil {
+			fmt.Printf("   %s %s: %v\n", style.Warning.Render("⚠"), r.Name, err)
+			failed = append(failed, r)
+			continue
+		}
+		if had {
+			entry := rigsConfig.Rigs[r.Name]
+			entry.AddedAt = saved.AddedAt
+			rigsConfig.Rigs[r.Name] = entry
+		}
+		if _, err := b.InstallRig(townRoot, home, r.Name); err != nil {
+			fmt.Printf("   %s %s: restoring rig files: %v\n", style.Warning.Render("⚠"), r.Name, err)
+		}
+		fmt.Printf("   ✓ %s\n", r.Name)
+	}
+
+	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		return failed, fmt.Errorf("saving rigs config: %w", err)
+	}
+	return failed, nil
+}
+
+func printBundleManifest(m *townbundle.Manifest) {
+	fmt.Printf("  Town:      %s (exported %s by gt %s)\n", orNone(m.TownName),
+		m.CreatedAt.Local().Format("2006-01-02 15:04"), orNone(m.GTVersion))
+	fmt.Printf("  From:      %s\n", m.SourceRoot)
+	fmt.Printf("  Files:     %d\n", len(m.Files))
+	fmt.Printf("  Rigs:      %d\n", len(m.Rigs))
+	for _, r := range m.Rigs {
+		fmt.Printf("    %-16s %s\n", r.Name, style.Dim.Render(r.GitURL))
+	}
+	fmt.Printf("  Databases: %d\n", len(m.Dumps))
+	if len(m.Redacted) > 0 {
+		fmt.Printf("  Redacted:  %d value(s)\n", len(m.Redacted))
+	}
+}
@@ -75,6 +75,15 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 		fmt.Printf("\n%s Post-install migration\n", style.Bold.Render("gt upgrade"))
 	}
 
+	// Print summary
+	printUpgradeSummary(runUpgradeSteps(townRoot))
+
+	return nil
+}
+
+// runUpgradeSteps runs every migration step in order. Shared with
+// gt town import, which finishes a restored town the same way.
+func runUpgradeSteps(townRoot string) []upgradeResult {
 	var results []upgradeResult
 
 	// Step 1: Run doctor --fix for structural checks
@@ -97,10 +106,7 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 	r5 := upgradeFormulas(townRoot)
 	results = append(results, r5)
 
-	// Print summary
-	printUpgradeSummary(results)
-
-	return nil
+	return results
 }
 
 // upgradeDoctor runs doctor --fix and returns the result.
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,8 +12,12 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/ctxpressure"
 	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -35,6 +40,8 @@ func runVitals(cmd *cobra.Command, args []string) error {
 	printVitalsDatabases(townRoot)
 	fmt.Println()
 	printVitalsBackups(townRoot)
+	fmt.Println()
+	printVitalsContext(townRoot)
 	return nil
 }
 
@@ -218,6 +225,43 @@ func printVitalsBackups(townRoot string) {
 	fmt.Println()
 }
 
+// printVitalsContext shows each agent session's context-window pressure
+// against the handoff threshold.
+func printVitalsContext(townRoot string) {
+	cfg := config.LoadOperationalConfig(townRoot).GetContextConfig()
+	threshold := cfg.HandoffThresholdV()
+	if threshold > 0 {
+		fmt.Printf("%s (handoff at %d%%)\n", style.Bold.Render("Context"), int(threshold*100+0.5))
+	} else {
+		fmt.Printf("%s (handoff disabled)\n", style.Bold.Render("Context"))
+	}
+
+	sessions, err := getAgentSessions(true)
+	if err != nil || len(sessions) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("no agent sessions"))
+		return
+	}
+	t := tmux.NewTmux()
+	for _, s := range sessions {
+		reading, err := ctxpressure.ForSession(session.BackendForWith(t, townRoot, s.Name), s.Name, cfg)
+		if errors.Is(err, ctxpressure.ErrUnsupported) {
+			fmt.Printf("  %-24s %s\n", s.Name, style.Dim.Render("unsupported (agent logs not readable)"))
+			continue
+		}
+		if err != nil || reading == nil {
+			fmt.Printf("  %-24s %s\n", s.Name, style.Dim.Render("-"))
+			continue
+		}
+		pct := fmt.Sprintf("%3d%%", reading.Percent())
+		if reading.Over(threshold) {
+			pct = style.Warning.Render(pct)
+		}
+		fmt.Printf("  %-24s %s  %7s  %s\n", s.Name, pct,
+			fmt.Sprintf("%dk/%dk", reading.Tokens/1000, reading.Window/1000),
+			style.Dim.Render(reading.Model))
+	}
+}
+
 func vitalsFormatCount(n int) string {
 	if n < 1000 {
 		return fmt.Sprintf("%d", n)
@@ -22,6 +22,8 @@ var (
 	warrantListAll bool
 	warrantForce   bool
 	warrantStdin   bool // Read reason from stdin
+	warrantSelect  string
+	warrantDryRun  bool
 )
 
 // Warrant represents a death warrant for an agent
@@ -62,10 +64,20 @@ The target should be an agent path like:
   - deacon/dogs/bravo
   - beads/polecats/charlie
 
+With --select, files the same warrant for every agent the selector matches.
+
+` + selectorLongHelp + `
+
 Examples:
   gt warrant file gastown/polecats/alpha --reason "Zombie: no session, idle >10m"
-  gt warrant file deacon/dogs/bravo --reason "Stuck: working on task for >2h"`,
-	Args: cobra.ExactArgs(1),
+  gt warrant file deacon/dogs/bravo --reason "Stuck: working on task for >2h"
+  gt warrant file --select 'role=polecat,state=stuck,idle>2h' -r "Stuck >2h" --dry-run`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if warrantSelect != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: runWarrantFile,
 }
 
@@ -105,6 +117,8 @@ func init() {
 	// File flags
 	warrantFileCmd.Flags().StringVarP(&warrantReason, "reason", "r", "", "Reason for the warrant (required unless --stdin)")
 	warrantFileCmd.Flags().BoolVar(&warrantStdin, "stdin", false, "Read reason from stdin (avoids shell quoting issues)")
+	warrantFileCmd.Flags().StringVar(&warrantSelect, "select", "", selectorHelp+" instead of a <target>")
+	warrantFileCmd.Flags().BoolVar(&warrantDryRun, "dry-run", false, "With --select, list the matched agents without filing")
 
 	// List flags
 	warrantListCmd.Flags().BoolVarP(&warrantListAll, "all", "a", false, "Include executed warrants")
@@ -153,8 +167,48 @@ func runWarrantFile(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("required flag \"reason\" not set (use --reason/-r or --stdin)")
 	}
 
-	target := args[0]
+	if warrantSelect != "" {
+		return fileSelectedWarrants()
+	}
+	if warrantDryRun {
+		return fmt.Errorf("--dry-run requires --select")
+	}
+	return fileWarrant(args[0])
+}
+
+// fileSelectedWarrants files a warrant for every agent --select matches.
+func fileSelectedWarrants() error {
+	sel, err := parseSelectFlag(warrantSelect)
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	matches, err := selectAgents(townRoot, sel)
+	if err != nil {
+		return err
+	}
+	if warrantDryRun {
+		printSelectedAgents(sel, matches)
+		fmt.Printf("\nReason: %s\n", warrantReason)
+		return nil
+	}
+	if len(matches) == 0 {
+		fmt.Printf("No agents match %s\n", sel)
+		return nil
+	}
+	for _, a := range matches {
+		if err := fileWarrant(a.Address); err != nil {
+			return fmt.Errorf("filing warrant for %s: %w", a.Address, err)
+		}
+	}
+	return nil
+}
 
+// fileWarrant files a warrant for one target with the current reason.
+func fileWarrant(target string) error {
 	warrantDir, err := getWarrantDir()
 	if err != nil {
 		return err
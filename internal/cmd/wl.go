@@ -17,6 +17,7 @@ import (
 var (
 	wlJoinHandle      string
 	wlJoinDisplayName string
+	wlJoinFork        string
 )
 
 var wlCmd = &cobra.Command{
@@ -26,9 +27,10 @@ var wlCmd = &cobra.Command{
 	RunE:    requireSubcommand,
 	Long: `Manage Wasteland federation — join communities, post work, earn reputation.
 
-The Wasteland is a federation of Gas Towns via DoltHub. Each rig has a
-sovereign fork of a shared commons database containing the wanted board
-(open work), rig registry, and validated completions.
+The Wasteland is a federation of Gas Towns via DoltHub (or any Dolt remote:
+file://, a self-hosted remotesapi, or S3). Each rig has a sovereign fork
+of a shared commons database containing the wanted board (open work), rig
+registry, and validated completions.
 
 Getting started:
   gt wl join steveyegge/wl-commons   # Join the default wasteland
@@ -54,10 +56,19 @@ Required environment variables:
   DOLTHUB_TOKEN  - Your DoltHub API token
   DOLTHUB_ORG    - Your DoltHub organization name
 
+The upstream may instead be a Dolt remote URL, for a commons kept off
+DoltHub: file:///path, a self-hosted remotesapi (http:// or https://), or
+an S3-compatible store (aws://[table:bucket]/db). --fork then names the
+remote URL your fork is pushed to; forking clones the commons and pushes
+it there. No DoltHub credentials are needed, and the handle defaults to
+the town name.
+
 Examples:
   gt wl join steveyegge/wl-commons
   gt wl join steveyegge/wl-commons --handle my-rig
-  gt wl join steveyegge/wl-commons --display-name "Alice's Workshop"`,
+  gt wl join steveyegge/wl-commons --display-name "Alice's Workshop"
+  gt wl join file:///srv/dolt/wl-commons --fork file:///srv/dolt/forks/alice
+  gt wl join https://dolt.internal:50051/wl-commons --fork https://dolt.internal:50051/alice-commons`,
 	Args: cobra.ExactArgs(1),
 	RunE: runWlJoin,
 }
@@ -65,6 +76,7 @@ Examples:
 func init() {
 	wlJoinCmd.Flags().StringVar(&wlJoinHandle, "handle", "", "Rig handle for registration (default: DoltHub org)")
 	wlJoinCmd.Flags().StringVar(&wlJoinDisplayName, "display-name", "", "Display name for the rig registry")
+	wlJoinCmd.Flags().StringVar(&wlJoinFork, "fork", "", "Dolt remote URL for your fork (required when upstream is a remote URL)")
 
 	wlCmd.AddCommand(wlJoinCmd)
 	rootCmd.AddCommand(wlCmd)
@@ -79,15 +91,32 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Require DoltHub credentials
-	token := doltserver.DoltHubToken()
-	if token == "" {
-		return fmt.Errorf("DOLTHUB_TOKEN environment variable is required\n\nGet your token from https://www.dolthub.com/settings/tokens")
-	}
+	// A commons on a generic Dolt remote needs a fork URL instead of
+	// DoltHub credentials.
+	remote := wasteland.IsRemoteURL(upstream)
+	var token, forkOrg string
+	if remote {
+		if wlJoinFork == "" {
+			return fmt.Errorf("--fork is required when joining a commons by remote URL\n\nSet it to a Dolt remote you can push to, e.g. file:///srv/dolt/forks/my-rig")
+		}
+		if _, _, err := wasteland.ParseUpstream(wlJoinFork); err != nil || !wasteland.IsRemoteURL(wlJoinFork) {
+			return fmt.Errorf("invalid --fork %q: expected a Dolt remote URL", wlJoinFork)
+		}
+	} else {
+		if wlJoinFork != "" {
+			return fmt.Errorf("--fork applies only to commons joined by remote URL; DoltHub forks go to DOLTHUB_ORG")
+		}
+
+		// Require DoltHub credentials
+		token = doltserver.DoltHubToken()
+		if token == "" {
+			return fmt.Errorf("DOLTHUB_TOKEN environment variable is required\n\nGet your token from https://www.dolthub.com/settings/tokens")
+		}
 
-	forkOrg := doltserver.DoltHubOrg()
-	if forkOrg == "" {
-		return fmt.Errorf("DOLTHUB_ORG environment variable is required\n\nSet this to your DoltHub organization name")
+		forkOrg = doltserver.DoltHubOrg()
+		if forkOrg == "" {
+			return fmt.Errorf("DOLTHUB_ORG environment variable is required\n\nSet this to your DoltHub organization name")
+		}
 	}
 
 	// Find town root
@@ -102,7 +131,7 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 		if existing.Upstream == upstream {
 			fmt.Printf("%s Already joined wasteland: %s\n", style.Bold.Render("⚠"), upstream)
 			fmt.Printf("  Handle: %s\n", existing.RigHandle)
-			fmt.Printf("  Fork: %s/%s\n", existing.ForkOrg, existing.ForkDB)
+			fmt.Printf("  Fork: %s\n", wlForkName(existing))
 			fmt.Printf("  Local: %s\n", existing.LocalDir)
 			return nil
 		}
@@ -121,6 +150,12 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 	if handle == "" {
 		handle = forkOrg
 	}
+	if handle == "" {
+		handle = townCfg.Name
+	}
+	if remote {
+		forkOrg = handle
+	}
 
 	displayName := wlJoinDisplayName
 	if displayName == "" {
@@ -135,11 +170,16 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 	gtVersion := "dev"
 
 	svc := wasteland.NewService()
+	forkName := forkOrg + "/" + upstream[strings.Index(upstream, "/")+1:]
+	if remote {
+		svc = wasteland.NewRemoteService(upstream, wlJoinFork)
+		forkName = wlJoinFork
+	}
 	svc.OnProgress = func(step string) {
 		fmt.Printf("  %s\n", step)
 	}
 
-	fmt.Printf("Joining wasteland %s (fork to %s/%s)...\n", upstream, forkOrg, upstream[strings.Index(upstream, "/")+1:])
+	fmt.Printf("Joining wasteland %s (fork to %s)...\n", upstream, forkName)
 	cfg, err := svc.Join(upstream, forkOrg, token, handle, displayName, ownerEmail, gtVersion, townRoot)
 	if err != nil {
 		return err
@@ -147,8 +187,17 @@ func runWlJoin(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("\n%s Joined wasteland: %s\n", style.Bold.Render("✓"), upstream)
 	fmt.Printf("  Handle: %s\n", cfg.RigHandle)
-	fmt.Printf("  Fork: %s/%s\n", cfg.ForkOrg, cfg.ForkDB)
+	fmt.Printf("  Fork: %s\n", wlForkName(cfg))
 	fmt.Printf("  Local: %s\n", cfg.LocalDir)
 	fmt.Printf("\n  %s\n", style.Dim.Render("Next: gt wl browse  — browse the wanted board"))
 	return nil
 }
+
+// wlForkName describes where a rig's fork lives: its remote URL, or the
+// DoltHub org/database path.
+func wlForkName(cfg *wasteland.Config) string {
+	if cfg.ForkURL != "" {
+		return cfg.ForkURL
+	}
+	return cfg.ForkOrg + "/" + cfg.ForkDB
+}
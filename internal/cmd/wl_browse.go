@@ -1,15 +1,21 @@
 package cmd
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -20,6 +26,7 @@ var (
 	wlBrowsePriority int
 	wlBrowseLimit    int
 	wlBrowseJSON     bool
+	wlBrowseSort     string
 )
 
 var wlBrowseCmd = &cobra.Command{
@@ -27,7 +34,11 @@ var wlBrowseCmd = &cobra.Command{
 	Short: "Browse wanted items on the commons board",
 	Args:  cobra.NoArgs,
 	RunE:  runWLBrowse,
-	Long: `Browse the Wasteland wanted board (hop/wl-commons).
+	Long: `Browse the Wasteland wanted board.
+
+Browses the upstream commons of the wasteland this town joined (gt wl
+join), whether it lives on DoltHub or another Dolt remote. A town that has
+not joined one browses the default commons, hop/wl-commons.
 
 Uses the clone-then-discard pattern: clones the commons database to a
 temporary directory, queries it, then deletes the clone.
@@ -39,7 +50,11 @@ EXAMPLES:
   gt wl browse --status claimed         # Claimed items
   gt wl browse --priority 0             # Critical priority only
   gt wl browse --limit 5               # Show 5 items
-  gt wl browse --json                   # JSON output`,
+  gt wl browse --json                   # JSON output
+  gt wl browse --status claimed --sort rep   # Most reputable claimants first
+
+--sort rep orders items by the reputation of the rig that claimed them
+(see gt wl rep) and adds CLAIMANT and REP columns.`,
 }
 
 func init() {
@@ -49,14 +64,31 @@ func init() {
 	wlBrowseCmd.Flags().IntVar(&wlBrowsePriority, "priority", -1, "Filter by priority (0=critical, 2=medium, 4=backlog)")
 	wlBrowseCmd.Flags().IntVar(&wlBrowseLimit, "limit", 50, "Maximum items to display")
 	wlBrowseCmd.Flags().BoolVar(&wlBrowseJSON, "json", false, "Output as JSON")
+	wlBrowseCmd.Flags().StringVar(&wlBrowseSort, "sort", "priority", "Sort order: priority, rep (claimant reputation)")
 
 	wlCmd.AddCommand(wlBrowseCmd)
 }
 
+// defaultWLCommons is browsed by towns that have not joined a wasteland.
+const defaultWLCommons = "hop/wl-commons"
+
 func runWLBrowse(cmd *cobra.Command, args []string) error {
-	if _, err := workspace.FindFromCwdOrError(); err != nil {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
+	upstream, err := wlBrowseUpstream(townRoot)
+	if err != nil {
+		return err
+	}
+	remoteURL, err := wasteland.UpstreamURL(upstream)
+	if err != nil {
+		return err
+	}
+
+	if wlBrowseSort != "priority" && wlBrowseSort != "rep" {
+		return fmt.Errorf("invalid --sort %q: want priority or rep", wlBrowseSort)
+	}
 
 	doltPath, err := exec.LookPath("dolt")
 	if err != nil {
@@ -69,37 +101,59 @@ func runWLBrowse(cmd *cobra.Command, args []string) error {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	commonsOrg := "hop"
-	commonsDB := "wl-commons"
-	cloneDir := filepath.Join(tmpDir, commonsDB)
+	// The clone is served under its directory name.
+	cloneDir := filepath.Join(tmpDir, doltserver.WLCommonsDB)
 
-	remote := fmt.Sprintf("%s/%s", commonsOrg, commonsDB)
-	fmt.Printf("Cloning %s...\n", style.Bold.Render(remote))
+	fmt.Printf("Cloning %s...\n", style.Bold.Render(upstream))
 
-	cloneCmd := exec.Command(doltPath, "clone", remote, cloneDir)
+	cloneCmd := exec.Command(doltPath, "clone", remoteURL, cloneDir)
 	cloneCmd.Stderr = os.Stderr
 	if err := cloneCmd.Run(); err != nil {
-		return fmt.Errorf("cloning %s: %w\nEnsure the database exists on DoltHub: https://www.dolthub.com/%s", remote, err, remote)
+		if wasteland.IsRemoteURL(upstream) {
+			return fmt.Errorf("cloning %s: %w", upstream, err)
+		}
+		return fmt.Errorf("cloning %s: %w\nEnsure the database exists on DoltHub: https://www.dolthub.com/%s", upstream, err, upstream)
 	}
 	fmt.Printf("%s Cloned successfully\n\n", style.Bold.Render("✓"))
 
-	query := buildBrowseQuery(BrowseFilter{
+	query, queryArgs := buildBrowseQuery(BrowseFilter{
 		Status:   wlBrowseStatus,
 		Project:  wlBrowseProject,
 		Type:     wlBrowseType,
 		Priority: wlBrowsePriority,
 		Limit:    wlBrowseLimit,
+		Sort:     wlBrowseSort,
 	})
 
+	db, stop, err := doltserver.OpenClone(doltPath, tmpDir, doltserver.WLCommonsDB)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	columns, rows, err := queryWLBrowse(db, query, queryArgs)
+	if err != nil {
+		return err
+	}
+
 	if wlBrowseJSON {
-		sqlCmd := exec.Command(doltPath, "sql", "-q", query, "-r", "json")
-		sqlCmd.Dir = cloneDir
-		sqlCmd.Stdout = os.Stdout
-		sqlCmd.Stderr = os.Stderr
-		return sqlCmd.Run()
+		return printWLBrowseJSON(columns, rows)
 	}
 
-	return renderWLBrowseTable(doltPath, cloneDir, query)
+	return renderWLBrowseTable(columns, rows)
+}
+
+// wlBrowseUpstream returns the commons to browse: the joined wasteland's
+// upstream, or defaultWLCommons when the town has not joined one.
+func wlBrowseUpstream(townRoot string) (string, error) {
+	cfg, err := wasteland.LoadConfig(townRoot)
+	if errors.Is(err, wasteland.ErrNotJoined) {
+		return defaultWLCommons, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("loading wasteland config: %w", err)
+	}
+	return cfg.Upstream, nil
 }
 
 // BrowseFilter holds filter parameters for building a browse query.
@@ -109,71 +163,159 @@ type BrowseFilter struct {
 	Type     string
 	Priority int
 	Limit    int
+	// Sort is "priority" (default) or "rep", which orders by the
+	// claimant's reputation and adds claimed_by and claimant_rep columns.
+	Sort string
 }
 
-func buildBrowseQuery(f BrowseFilter) string {
+// buildBrowseQuery returns the browse query and its arguments. Filter
+// values are only ever passed as arguments.
+func buildBrowseQuery(f BrowseFilter) (string, []any) {
 	var conditions []string
+	var args []any
 
 	if f.Status != "" {
-		conditions = append(conditions, fmt.Sprintf("status = '%s'", doltserver.EscapeSQL(f.Status)))
+		conditions = append(conditions, "status = ?")
+		args = append(args, f.Status)
 	}
 	if f.Project != "" {
-		conditions = append(conditions, fmt.Sprintf("project = '%s'", doltserver.EscapeSQL(f.Project)))
+		conditions = append(conditions, "project = ?")
+		args = append(args, f.Project)
 	}
 	if f.Type != "" {
-		conditions = append(conditions, fmt.Sprintf("type = '%s'", doltserver.EscapeSQL(f.Type)))
+		conditions = append(conditions, "type = ?")
+		args = append(args, f.Type)
 	}
 	if f.Priority >= 0 {
-		conditions = append(conditions, fmt.Sprintf("priority = %d", f.Priority))
+		conditions = append(conditions, "priority = ?")
+		args = append(args, f.Priority)
 	}
 
 	query := "SELECT id, title, project, type, priority, posted_by, status, effort_level FROM wanted"
+	if f.Sort == "rep" {
+		query = "SELECT id, title, project, type, priority, posted_by, status, effort_level, " +
+			"COALESCE(claimed_by, '') AS claimed_by, COALESCE(rep.score, 0) AS claimant_rep FROM wanted " +
+			"LEFT JOIN (" + doltserver.ReputationScoresSQL + ") rep ON rep.handle = wanted.claimed_by"
+	}
 	if len(conditions) > 0 {
 		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
-	query += " ORDER BY priority ASC, created_at DESC"
-	query += fmt.Sprintf(" LIMIT %d", f.Limit)
+	if f.Sort == "rep" {
+		query += " ORDER BY claimant_rep DESC, priority ASC, created_at DESC"
+	} else {
+		query += " ORDER BY priority ASC, created_at DESC"
+	}
+	query += " LIMIT ?"
+	args = append(args, f.Limit)
 
-	return query
+	return query, args
 }
 
-func renderWLBrowseTable(doltPath, cloneDir, query string) error {
-	sqlCmd := exec.Command(doltPath, "sql", "-q", query, "-r", "csv")
-	sqlCmd.Dir = cloneDir
-	output, err := sqlCmd.Output()
+// queryWLBrowse runs the browse query, returning the column names and the
+// rows with text values as strings and NULL as nil.
+func queryWLBrowse(db *sql.DB, query string, args []any) ([]string, [][]any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	rs, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rs.Close()
+
+	columns, err := rs.Columns()
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return fmt.Errorf("query failed: %s", string(exitErr.Stderr))
+		return nil, nil, fmt.Errorf("reading columns: %w", err)
+	}
+	var rows [][]any
+	for rs.Next() {
+		row := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range row {
+			ptrs[i] = &row[i]
+		}
+		if err := rs.Scan(ptrs...); err != nil {
+			return nil, nil, fmt.Errorf("reading row: %w", err)
 		}
-		return fmt.Errorf("running query: %w", err)
+		for i, v := range row {
+			if b, ok := v.([]byte); ok {
+				row[i] = string(b)
+			}
+		}
+		rows = append(rows, row)
+	}
+	if err := rs.Err(); err != nil {
+		return nil, nil, fmt.Errorf("reading rows: %w", err)
 	}
+	return columns, rows, nil
+}
 
-	rows := wlParseCSV(string(output))
-	if len(rows) <= 1 {
+// printWLBrowseJSON prints rows in the shape of dolt sql -r json.
+func printWLBrowseJSON(columns []string, rows [][]any) error {
+	out := struct {
+		Rows []map[string]any `json:"rows"`
+	}{Rows: []map[string]any{}}
+	for _, row := range rows {
+		obj := make(map[string]any, len(columns))
+		for i, col := range columns {
+			if row[i] != nil {
+				obj[col] = row[i]
+			}
+		}
+		out.Rows = append(out.Rows, obj)
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func renderWLBrowseTable(names []string, rows [][]any) error {
+	if len(rows) == 0 {
 		fmt.Println("No wanted items found matching your filters.")
 		return nil
 	}
 
-	tbl := style.NewTable(
-		style.Column{Name: "ID", Width: 12},
-		style.Column{Name: "TITLE", Width: 40},
-		style.Column{Name: "PROJECT", Width: 12},
-		style.Column{Name: "TYPE", Width: 10},
-		style.Column{Name: "PRI", Width: 4, Align: style.AlignRight},
-		style.Column{Name: "POSTED BY", Width: 16},
-		style.Column{Name: "STATUS", Width: 10},
-		style.Column{Name: "EFFORT", Width: 8},
-	)
+	columns := []style.Column{
+		{Name: "ID", Width: 12},
+		{Name: "TITLE", Width: 40},
+		{Name: "PROJECT", Width: 12},
+		{Name: "TYPE", Width: 10},
+		{Name: "PRI", Width: 4, Align: style.AlignRight},
+		{Name: "POSTED BY", Width: 16},
+		{Name: "STATUS", Width: 10},
+		{Name: "EFFORT", Width: 8},
+	}
+	// Reputation-sorted queries carry the claimant and its score.
+	withRep := len(names) >= 10
+	if withRep {
+		columns = append(columns,
+			style.Column{Name: "CLAIMANT", Width: 16},
+			style.Column{Name: "REP", Width: 5, Align: style.AlignRight})
+	}
+	tbl := style.NewTable(columns...)
 
-	for _, row := range rows[1:] {
-		if len(row) < 8 {
+	for _, values := range rows {
+		if len(values) < len(columns) {
 			continue
 		}
+		row := make([]string, len(values))
+		for i, v := range values {
+			if v != nil {
+				row[i] = fmt.Sprint(v)
+			}
+		}
 		pri := wlFormatPriority(row[4])
-		tbl.AddRow(row[0], row[1], row[2], row[3], pri, row[5], row[6], row[7])
+		cells := []string{row[0], row[1], row[2], row[3], pri, row[5], row[6], row[7]}
+		if withRep {
+			cells = append(cells, row[8], row[9])
+		}
+		tbl.AddRow(cells...)
 	}
 
-	fmt.Printf("Wanted items (%d):\n\n", len(rows)-1)
+	fmt.Printf("Wanted items (%d):\n\n", len(rows))
 	fmt.Print(tbl.Render())
 
 	return nil
@@ -1,10 +1,37 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/wasteland"
 )
 
+func TestWlBrowseUpstream(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	got, err := wlBrowseUpstream(townRoot)
+	if err != nil || got != defaultWLCommons {
+		t.Errorf("not joined: wlBrowseUpstream = %q, %v; want %q", got, err, defaultWLCommons)
+	}
+
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &wasteland.Config{Upstream: "file:///srv/dolt/wl-commons", ForkOrg: "alice"}
+	if err := wasteland.SaveConfig(townRoot, cfg); err != nil {
+		t.Fatal(err)
+	}
+	got, err = wlBrowseUpstream(townRoot)
+	if err != nil || got != cfg.Upstream {
+		t.Errorf("joined: wlBrowseUpstream = %q, %v; want %q", got, err, cfg.Upstream)
+	}
+}
+
 func TestWlParseCSV_Empty(t *testing.T) {
 	t.Parallel()
 	got := wlParseCSV("")
@@ -137,11 +164,14 @@ func TestBuildBrowseQuery_DefaultFilters(t *testing.T) {
 		Priority: -1,
 		Limit:    50,
 	}
-	got := buildBrowseQuery(f)
-	want := "SELECT id, title, project, type, priority, posted_by, status, effort_level FROM wanted WHERE status = 'open' ORDER BY priority ASC, created_at DESC LIMIT 50"
+	got, args := buildBrowseQuery(f)
+	want := "SELECT id, title, project, type, priority, posted_by, status, effort_level FROM wanted WHERE status = ? ORDER BY priority ASC, created_at DESC LIMIT ?"
 	if got != want {
 		t.Errorf("buildBrowseQuery(default) =\n  %q\nwant\n  %q", got, want)
 	}
+	if !reflect.DeepEqual(args, []any{"open", 50}) {
+		t.Errorf("buildBrowseQuery(default) args = %#v", args)
+	}
 }
 
 func TestBuildBrowseQuery_AllFilters(t *testing.T) {
@@ -153,19 +183,22 @@ func TestBuildBrowseQuery_AllFilters(t *testing.T) {
 		Priority: 0,
 		Limit:    5,
 	}
-	got := buildBrowseQuery(f)
+	got, args := buildBrowseQuery(f)
 	// All four conditions should be present
 	for _, substr := range []string{
-		"status = 'open'",
-		"project = 'gastown'",
-		"type = 'bug'",
-		"priority = 0",
-		"LIMIT 5",
+		"status = ?",
+		"project = ?",
+		"type = ?",
+		"priority = ?",
+		"LIMIT ?",
 	} {
 		if !strings.Contains(got, substr) {
 			t.Errorf("buildBrowseQuery(all) missing %q in %q", substr, got)
 		}
 	}
+	if !reflect.DeepEqual(args, []any{"open", "gastown", "bug", 0, 5}) {
+		t.Errorf("buildBrowseQuery(all) args = %#v", args)
+	}
 }
 
 func TestBuildBrowseQuery_NoFilters(t *testing.T) {
@@ -174,22 +207,68 @@ func TestBuildBrowseQuery_NoFilters(t *testing.T) {
 		Priority: -1,
 		Limit:    50,
 	}
-	got := buildBrowseQuery(f)
+	got, _ := buildBrowseQuery(f)
 	if strings.Contains(got, "WHERE") {
 		t.Errorf("buildBrowseQuery(none) should not have WHERE clause: %q", got)
 	}
 }
 
-func TestBuildBrowseQuery_EscapesSQL(t *testing.T) {
+func TestBuildBrowseQuery_SortByRep(t *testing.T) {
 	t.Parallel()
-	f := BrowseFilter{
-		Status:   "it's",
-		Priority: -1,
-		Limit:    50,
+	got, args := buildBrowseQuery(BrowseFilter{Status: "claimed", Priority: -1, Limit: 10, Sort: "rep"})
+	for _, substr := range []string{
+		"claimed_by, COALESCE(rep.score, 0) AS claimant_rep",
+		"LEFT JOIN (" + doltserver.ReputationScoresSQL + ") rep ON rep.handle = wanted.claimed_by",
+		"WHERE status = ?",
+		"ORDER BY claimant_rep DESC, priority ASC",
+		"LIMIT ?",
+	} {
+		if !strings.Contains(got, substr) {
+			t.Errorf("buildBrowseQuery(rep) missing %q in %q", substr, got)
+		}
 	}
-	got := buildBrowseQuery(f)
-	if !strings.Contains(got, "it''s") {
-		t.Errorf("buildBrowseQuery should escape single quotes: %q", got)
+	if !reflect.DeepEqual(args, []any{"claimed", 10}) {
+		t.Errorf("buildBrowseQuery(rep) args = %#v", args)
 	}
 }
 
+// FuzzBuildBrowseQuery checks that filter values never reach the query
+// text: the query depends only on which filters are set, and every value
+// is passed as an argument, one per placeholder.
+func FuzzBuildBrowseQuery(f *testing.F) {
+	for _, seed := range []string{"open", "it's", `trailing \`, "'; DROP TABLE wanted; --", "?", "emoji 🚧"} {
+		f.Add(seed, seed, seed, 1)
+	}
+	f.Fuzz(func(t *testing.T, status, project, typ string, priority int) {
+		filter := BrowseFilter{Status: status, Project: project, Type: typ, Priority: priority, Limit: 50, Sort: "rep"}
+		query, args := buildBrowseQuery(filter)
+
+		// The same filters set to a harmless value give the same query.
+		stand := func(v string) string {
+			if v == "" {
+				return ""
+			}
+			return "x"
+		}
+		ref, _ := buildBrowseQuery(BrowseFilter{Status: stand(status), Project: stand(project), Type: stand(typ), Priority: priority, Limit: 50, Sort: "rep"})
+		if query != ref {
+			t.Fatalf("query text depends on filter values:\n%s\nvs\n%s", query, ref)
+		}
+		if got, want := len(args), strings.Count(query, "?"); got != want {
+			t.Fatalf("%d args for %d placeholders", got, want)
+		}
+		var want []any
+		for _, v := range []string{status, project, typ} {
+			if v != "" {
+				want = append(want, v)
+			}
+		}
+		if priority >= 0 {
+			want = append(want, priority)
+		}
+		want = append(want, 50)
+		if !reflect.DeepEqual(args, want) {
+			t.Errorf("args = %#v, want %#v", args, want)
+		}
+	})
+}
@@ -50,6 +50,7 @@ func runWlClaim(cmd *cobra.Command, args []string) error {
 	}
 
 	store := doltserver.NewWLCommons(townRoot)
+	defer store.Close()
 	item, err := claimWanted(store, wantedID, rigHandle)
 	if err != nil {
 		return err
@@ -27,6 +27,9 @@ The --evidence flag provides the evidence URL (PR link, commit hash, etc.).
 A completion ID is generated as c-<hash> where hash is derived from the
 wanted ID, rig handle, and timestamp.
 
+The completion then waits for review (gt wl review) by the posting rig or
+independent rigs; once validated it counts toward your reputation (gt wl rep).
+
 Examples:
   gt wl done w-abc123 --evidence 'https://github.com/org/repo/pull/123'
   gt wl done w-abc123 --evidence 'commit abc123def'`,
@@ -60,6 +63,7 @@ func runWlDone(cmd *cobra.Command, args []string) error {
 	}
 
 	store := doltserver.NewWLCommons(townRoot)
+	defer store.Close()
 	completionID := generateCompletionID(wantedID, rigHandle)
 
 	if err := submitDone(store, wantedID, rigHandle, wlDoneEvidence, completionID); err != nil {
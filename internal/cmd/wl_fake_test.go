@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/steveyegge/gastown/internal/doltserver"
@@ -15,18 +16,28 @@ type fakeWLCommonsStore struct {
 	items map[string]*doltserver.WantedItem
 	dbOK  bool
 
+	// completions is keyed by wanted ID; rejected counts rejected
+	// completions per completer.
+	completions map[string]*doltserver.Completion
+	rejected    map[string]int
+	quorum      int
+
 	// Error injection fields
 	EnsureDBErr         error
 	InsertWantedErr     error
 	ClaimWantedErr      error
 	SubmitCompletionErr error
 	QueryWantedErr      error
+	ReviewErr           error
 }
 
 func newFakeWLCommonsStore() *fakeWLCommonsStore {
 	return &fakeWLCommonsStore{
-		items: make(map[string]*doltserver.WantedItem),
-		dbOK:  true,
+		items:       make(map[string]*doltserver.WantedItem),
+		dbOK:        true,
+		completions: make(map[string]*doltserver.Completion),
+		rejected:    make(map[string]int),
+		quorum:      doltserver.DefaultValidationQuorum,
 	}
 }
 
@@ -107,6 +118,15 @@ func (f *fakeWLCommonsStore) SubmitCompletion(completionID, wantedID, rigHandle,
 		return fmt.Errorf("wanted item %q is not claimed by %q (claimed by %q)", wantedID, rigHandle, item.ClaimedBy)
 	}
 	item.Status = "in_review"
+	f.completions[wantedID] = &doltserver.Completion{
+		ID:          completionID,
+		WantedID:    wantedID,
+		Title:       item.Title,
+		PostedBy:    item.PostedBy,
+		Effort:      item.EffortLevel,
+		CompletedBy: rigHandle,
+		Evidence:    evidence,
+	}
 	return nil
 }
 
@@ -125,3 +145,74 @@ func (f *fakeWLCommonsStore) QueryWanted(wantedID string) (*doltserver.WantedIte
 	cp := *item
 	return &cp, nil
 }
+
+func (f *fakeWLCommonsStore) QueryCompletion(wantedID string) (*doltserver.Completion, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.completionLocked(wantedID)
+}
+
+// completionLocked returns a copy of the completion for wantedID with the
+// item's current status. Callers hold f.mu.
+func (f *fakeWLCommonsStore) completionLocked(wantedID string) (*doltserver.Completion, error) {
+	c, ok := f.completions[wantedID]
+	if !ok {
+		return nil, fmt.Errorf("no completion submitted for wanted item %q", wantedID)
+	}
+	cp := *c
+	cp.Reviews = append([]doltserver.CompletionReview(nil), c.Reviews...)
+	cp.Status = f.items[wantedID].Status
+	return &cp, nil
+}
+
+func (f *fakeWLCommonsStore) ReviewCompletion(stampID, wantedID, reviewer, verdict, notes string) (string, error) {
+	if f.ReviewErr != nil {
+		return "", f.ReviewErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.completionLocked(wantedID)
+	if err != nil {
+		return "", err
+	}
+	if err := doltserver.CheckReview(c, reviewer, verdict); err != nil {
+		return "", err
+	}
+	stored := f.completions[wantedID]
+	stored.Reviews = append(stored.Reviews, doltserver.CompletionReview{StampID: stampID, Reviewer: reviewer, Verdict: verdict, Notes: notes})
+
+	outcome, deciders := doltserver.ResolveReviews(stored, f.quorum)
+	item := f.items[wantedID]
+	switch outcome {
+	case doltserver.ReviewValidated:
+		stored.ValidatedBy = strings.Join(deciders, ",")
+		item.Status = "completed"
+	case doltserver.ReviewRejected:
+		delete(f.completions, wantedID)
+		f.rejected[stored.CompletedBy]++
+		item.Status = "claimed"
+	}
+	return outcome, nil
+}
+
+func (f *fakeWLCommonsStore) QueryReputation(handle string) (*doltserver.Reputation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rep := &doltserver.Reputation{Handle: handle, Rejected: f.rejected[handle]}
+	for wantedID, c := range f.completions {
+		if c.CompletedBy != handle {
+			continue
+		}
+		if c.ValidatedBy == "" {
+			rep.Pending++
+			continue
+		}
+		cp, _ := f.completionLocked(wantedID)
+		rep.Validated = append(rep.Validated, cp)
+		rep.Score += doltserver.EffortPoints(c.Effort)
+	}
+	return rep, nil
+}
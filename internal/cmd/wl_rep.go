@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var wlRepJSON bool
+
+var wlRepCmd = &cobra.Command{
+	Use:   "rep [handle]",
+	Short: "Show a rig's reputation",
+	Long: `Show a rig's reputation on the commons (default: your own rig).
+
+Reputation comes from validated completions (see gt wl review). Each one
+earns points by the wanted item's effort level:
+
+  trivial 1, small 2, medium 3, large 5, epic 8
+
+Completions still in review and rejected completions are listed but earn
+nothing.
+
+Examples:
+  gt wl rep
+  gt wl rep alice-dev
+  gt wl rep alice-dev --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWlRep,
+}
+
+func init() {
+	wlRepCmd.Flags().BoolVar(&wlRepJSON, "json", false, "Output as JSON")
+
+	wlCmd.AddCommand(wlRepCmd)
+}
+
+func runWlRep(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var handle string
+	if len(args) > 0 {
+		handle = args[0]
+	} else {
+		wlCfg, err := wasteland.LoadConfig(townRoot)
+		if err != nil {
+			return fmt.Errorf("loading wasteland config: %w", err)
+		}
+		handle = wlCfg.RigHandle
+	}
+
+	if !doltserver.DatabaseExists(townRoot, doltserver.WLCommonsDB) {
+		return fmt.Errorf("database %q not found\nJoin a wasteland first with: gt wl join <org/db>", doltserver.WLCommonsDB)
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	defer store.Close()
+	rep, err := store.QueryReputation(handle)
+	if err != nil {
+		return fmt.Errorf("querying reputation: %w", err)
+	}
+
+	if wlRepJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rep)
+	}
+	printWlReputation(rep)
+	return nil
+}
+
+func printWlReputation(rep *doltserver.Reputation) {
+	fmt.Printf("%s  reputation %s\n", style.Bold.Render(rep.Handle), style.Bold.Render(fmt.Sprintf("%d", rep.Score)))
+	fmt.Printf("  Validated: %d\n", len(rep.Validated))
+	fmt.Printf("  In review: %d\n", rep.Pending)
+	fmt.Printf("  Rejected:  %d\n", rep.Rejected)
+	if len(rep.Validated) == 0 {
+		return
+	}
+
+	tbl := style.NewTable(
+		style.Column{Name: "WANTED", Width: 12},
+		style.Column{Name: "TITLE", Width: 40},
+		style.Column{Name: "EFFORT", Width: 8},
+		style.Column{Name: "PTS", Width: 4, Align: style.AlignRight},
+		style.Column{Name: "VALIDATED BY", Width: 24},
+	)
+	for _, c := range rep.Validated {
+		tbl.AddRow(c.WantedID, c.Title, c.Effort, fmt.Sprintf("%d", doltserver.EffortPoints(c.Effort)), c.ValidatedBy)
+	}
+	fmt.Println()
+	fmt.Print(tbl.Render())
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/wasteland"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	wlReviewApprove bool
+	wlReviewReject  bool
+	wlReviewNotes   string
+)
+
+var wlReviewCmd = &cobra.Command{
+	Use:   "review <wanted-id>",
+	Short: "Review the completion evidence for a wanted item",
+	Long: `Review a completion submitted with gt wl done.
+
+Without --approve or --reject, shows the completion's evidence (commit SHA,
+PR URL, test output) and the reviews so far.
+
+A completion is validated or rejected when:
+  - the rig that posted the wanted item reviews it, or
+  - enough independent rigs agree (the commons' validation_quorum, default 2).
+
+Rigs cannot review their own completions. Each review is recorded as a stamp
+in the commons. A validated completion earns the completer reputation (see
+gt wl rep) and marks the item completed; a rejected one goes back to the
+claimant, who can fix it and run gt wl done again.
+
+Examples:
+  gt wl review w-abc123                              # Show evidence and reviews
+  gt wl review w-abc123 --approve -m "CI green, PR merged"
+  gt wl review w-abc123 --reject -m "Tests fail on main"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWlReview,
+}
+
+func init() {
+	wlReviewCmd.Flags().BoolVar(&wlReviewApprove, "approve", false, "Approve the completion")
+	wlReviewCmd.Flags().BoolVar(&wlReviewReject, "reject", false, "Reject the completion")
+	wlReviewCmd.Flags().StringVarP(&wlReviewNotes, "message", "m", "", "Review notes")
+	wlReviewCmd.MarkFlagsMutuallyExclusive("approve", "reject")
+
+	wlCmd.AddCommand(wlReviewCmd)
+}
+
+func runWlReview(cmd *cobra.Command, args []string) error {
+	wantedID := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	wlCfg, err := wasteland.LoadConfig(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading wasteland config: %w", err)
+	}
+	rigHandle := wlCfg.RigHandle
+
+	if !doltserver.DatabaseExists(townRoot, doltserver.WLCommonsDB) {
+		return fmt.Errorf("database %q not found\nJoin a wasteland first with: gt wl join <org/db>", doltserver.WLCommonsDB)
+	}
+
+	store := doltserver.NewWLCommons(townRoot)
+	defer store.Close()
+	verdict := ""
+	switch {
+	case wlReviewApprove:
+		verdict = doltserver.VerdictApprove
+	case wlReviewReject:
+		verdict = doltserver.VerdictReject
+	}
+	if verdict == "" {
+		c, err := store.QueryCompletion(wantedID)
+		if err != nil {
+			return err
+		}
+		printWlCompletion(c)
+		return nil
+	}
+
+	outcome, err := reviewCompletion(store, wantedID, rigHandle, verdict, wlReviewNotes)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Reviewed %s: %s\n", style.Bold.Render("✓"), wantedID, verdict)
+	switch outcome {
+	case doltserver.ReviewValidated:
+		fmt.Printf("  Completion validated; %s is completed\n", wantedID)
+	case doltserver.ReviewRejected:
+		fmt.Printf("  Completion rejected; %s is back with its claimant\n", wantedID)
+	default:
+		fmt.Printf("  %s\n", style.Dim.Render("Awaiting more reviews"))
+	}
+	return nil
+}
+
+// reviewCompletion contains the testable business logic for reviewing a
+// completion. It returns the review outcome (pending, validated, rejected).
+func reviewCompletion(store doltserver.WLCommonsStore, wantedID, reviewer, verdict, notes string) (string, error) {
+	c, err := store.QueryCompletion(wantedID)
+	if err != nil {
+		return "", fmt.Errorf("querying completion: %w", err)
+	}
+	if err := doltserver.CheckReview(c, reviewer, verdict); err != nil {
+		return "", err
+	}
+
+	outcome, err := store.ReviewCompletion(generateStampID(c.ID, reviewer), wantedID, reviewer, verdict, notes)
+	if err != nil {
+		return "", fmt.Errorf("reviewing completion: %w", err)
+	}
+	return outcome, nil
+}
+
+func printWlCompletion(c *doltserver.Completion) {
+	fmt.Printf("%s %s\n", style.Bold.Render(c.WantedID), c.Title)
+	fmt.Printf("  Status:       %s\n", c.Status)
+	fmt.Printf("  Posted by:    %s\n", orNone(c.PostedBy))
+	fmt.Printf("  Completed by: %s\n", c.CompletedBy)
+	fmt.Printf("  Completion:   %s\n", c.ID)
+	fmt.Printf("  Evidence:     %s\n", c.Evidence)
+	if c.ValidatedBy != "" {
+		fmt.Printf("  Validated by: %s\n", c.ValidatedBy)
+	}
+	if len(c.Reviews) == 0 {
+		fmt.Printf("\n  %s\n", style.Dim.Render("No reviews yet"))
+		return
+	}
+	fmt.Printf("\nReviews:\n")
+	for _, r := range c.Reviews {
+		line := fmt.Sprintf("  %-8s %s", r.Verdict, r.Reviewer)
+		if r.Notes != "" {
+			line += style.Dim.Render(" — " + r.Notes)
+		}
+		fmt.Println(line)
+	}
+}
+
+func generateStampID(completionID, reviewer string) string {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	h := sha256.Sum256([]byte(completionID + "|" + reviewer + "|" + now))
+	return fmt.Sprintf("s-%x", h[:8])
+}
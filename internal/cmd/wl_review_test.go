@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// completedWanted posts, claims and completes a wanted item in store.
+func completedWanted(t *testing.T, store *fakeWLCommonsStore, wantedID, effort string) {
+	t.Helper()
+	if err := postWanted(store, &doltserver.WantedItem{ID: wantedID, Title: "Review me", PostedBy: "poster-rig", EffortLevel: effort}); err != nil {
+		t.Fatalf("postWanted() error: %v", err)
+	}
+	if _, err := claimWanted(store, wantedID, "worker-rig"); err != nil {
+		t.Fatalf("claimWanted() error: %v", err)
+	}
+	if err := submitDone(store, wantedID, "worker-rig", "https://pr/1", "c-"+wantedID); err != nil {
+		t.Fatalf("submitDone() error: %v", err)
+	}
+}
+
+func TestReviewCompletion_ValidatesAndEarnsReputation(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	completedWanted(t, store, "w-rev1", "large")
+	completedWanted(t, store, "w-rev2", "small")
+
+	if outcome, err := reviewCompletion(store, "w-rev1", "poster-rig", doltserver.VerdictApprove, "merged"); err != nil || outcome != doltserver.ReviewValidated {
+		t.Fatalf("poster review = %q, %v; want validated", outcome, err)
+	}
+	if outcome, err := reviewCompletion(store, "w-rev2", "peer-a", doltserver.VerdictApprove, ""); err != nil || outcome != doltserver.ReviewPending {
+		t.Fatalf("first peer review = %q, %v; want pending", outcome, err)
+	}
+
+	rep, err := store.QueryReputation("worker-rig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Score != doltserver.EffortPoints("large") || rep.Pending != 1 || len(rep.Validated) != 1 {
+		t.Errorf("reputation = %+v", rep)
+	}
+	got, _ := store.QueryWanted("w-rev1")
+	if got.Status != "completed" {
+		t.Errorf("Status = %q, want completed", got.Status)
+	}
+}
+
+func TestReviewCompletion_Errors(t *testing.T) {
+	t.Parallel()
+	store := newFakeWLCommonsStore()
+	completedWanted(t, store, "w-rev3", "medium")
+
+	tests := []struct {
+		name     string
+		wantedID string
+		reviewer string
+		verdict  string
+		want     string
+	}{
+		{"self review", "w-rev3", "worker-rig", doltserver.VerdictApprove, "own completion"},
+		{"bad verdict", "w-rev3", "peer-a", "lgtm", "invalid verdict"},
+		{"no completion", "w-none", "peer-a", doltserver.VerdictApprove, "no completion"},
+	}
+	for _, tt := range tests {
+		_, err := reviewCompletion(store, tt.wantedID, tt.reviewer, tt.verdict, "")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestGenerateStampID_Format(t *testing.T) {
+	t.Parallel()
+	id := generateStampID("c-abc", "my-rig")
+	if !strings.HasPrefix(id, "s-") || len(id) != 18 {
+		t.Errorf("generateStampID() = %q, want s-<16 hex>", id)
+	}
+}
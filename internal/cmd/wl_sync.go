@@ -22,7 +22,8 @@ var wlSyncCmd = &cobra.Command{
 	Long: `Sync your local wl-commons fork with the upstream hop/wl-commons.
 
 If you have a local fork of wl-commons (created by gt wl join), this pulls
-the latest changes from upstream.
+the latest changes from upstream. Commons joined by remote URL (file://,
+a self-hosted remotesapi, or an S3-compatible store) sync the same way.
 
 EXAMPLES:
   gt wl sync                # Pull upstream changes
@@ -48,7 +49,8 @@ func runWLSync(cmd *cobra.Command, args []string) error {
 
 	// Try loading wasteland config first (set by gt wl join)
 	forkDir := ""
-	if cfg, err := wasteland.LoadConfig(townRoot); err == nil {
+	cfg, err := wasteland.LoadConfig(townRoot)
+	if err == nil {
 		forkDir = cfg.LocalDir
 	}
 
@@ -58,11 +60,19 @@ func runWLSync(cmd *cobra.Command, args []string) error {
 	}
 
 	if forkDir == "" {
-		return fmt.Errorf("no local wl-commons fork found\n\nJoin a wasteland first: gt wl join <org/db>")
+		return fmt.Errorf("no local wl-commons fork found\n\nJoin a wasteland first: gt wl join <org/db|remote-url>")
 	}
 
 	fmt.Printf("Local fork: %s\n", style.Dim.Render(forkDir))
 
+	// Commons joined by URL carry their upstream in the config; make sure
+	// the clone still tracks it.
+	if cfg != nil && cfg.LocalDir == forkDir && wasteland.IsRemoteURL(cfg.Upstream) {
+		if err := wasteland.AddRemote(forkDir, "upstream", cfg.Upstream); err != nil {
+			return err
+		}
+	}
+
 	if wlSyncDryRun {
 		fmt.Printf("\n%s Dry run — checking upstream for changes...\n", style.Bold.Render("~"))
 
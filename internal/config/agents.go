@@ -36,6 +36,9 @@ const (
 	// AgentOmp is Oh My Pi (OMP) — Pi fork with hook-based lifecycle.
 	// Inspired by github.com/ProbabilityEngineer/pi-mono gastown integration.
 	AgentOmp AgentPreset = "omp"
+	// AgentSim is the scripted, offline simulated agent (gt sim-agent) used
+	// for deterministic end-to-end testing.
+	AgentSim AgentPreset = "sim"
 )
 
 // AgentPresetInfo contains the configuration details for an agent preset.
@@ -88,6 +91,13 @@ type AgentPresetInfo struct {
 	// Used by the seance command for session forking.
 	SupportsForkSession bool `json:"supports_fork_session,omitempty"`
 
+	// SessionStore is the directory under the home directory where the agent
+	// keeps its sessions (e.g., ".gemini", ".codex"). For agents that cannot
+	// fork, gt seance resumes a copy of it so the predecessor's session is
+	// never modified. Empty means sessions are not kept locally (amp threads
+	// live on its server) and the seance falls back to a transcript.
+	SessionStore string `json:"session_store,omitempty"`
+
 	// NonInteractive contains settings for non-interactive mode.
 	NonInteractive *NonInteractiveConfig `json:"non_interactive,omitempty"`
 
@@ -196,6 +206,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		ResumeStyle:         "flag",
 		SupportsHooks:       true,
 		SupportsForkSession: false,
+		SessionStore:        ".gemini",
 		NonInteractive: &NonInteractiveConfig{
 			PromptFlag: "-p",
 			OutputFlag: "--output-format json",
@@ -219,6 +230,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		ResumeStyle:         "subcommand",
 		SupportsHooks:       false, // Use env/files instead
 		SupportsForkSession: false,
+		SessionStore:        ".codex",
 		NonInteractive: &NonInteractiveConfig{
 			Subcommand: "exec",
 			OutputFlag: "--json",
@@ -238,6 +250,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		ResumeStyle:         "flag",
 		SupportsHooks:       true,
 		SupportsForkSession: false,
+		SessionStore:        ".cursor",
 		NonInteractive: &NonInteractiveConfig{
 			PromptFlag: "-p",
 			OutputFlag: "--output-format json",
@@ -260,6 +273,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		ResumeStyle:         "flag",
 		SupportsHooks:       false,
 		SupportsForkSession: false,
+		SessionStore:        ".augment",
 		// Runtime defaults
 		PromptMode:       "arg",
 		InstructionsFile: "AGENTS.md",
@@ -315,6 +329,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		ResumeStyle:         "flag",
 		SupportsHooks:       false, // Copilot instructions file is not executable hooks
 		SupportsForkSession: false,
+		SessionStore:        ".copilot",
 		NonInteractive: &NonInteractiveConfig{
 			PromptFlag: "-p",
 		},
@@ -366,6 +381,19 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 			PromptFlag: "--prompt",
 		},
 	},
+	AgentSim: {
+		Name:                AgentSim,
+		Command:             "gt",
+		Args:                []string{"sim-agent"},
+		ProcessNames:        []string{"gt"},
+		SupportsHooks:       false, // Startup fallback nudges deliver gt prime
+		SupportsForkSession: false,
+		// Runtime defaults
+		PromptMode:        "arg",
+		ReadyPromptPrefix: "sim> ", // simagent.ReadyPrompt
+		ReadyDelayMs:      500,
+		InstructionsFile:  "AGENTS.md",
+	},
 }
 
 // Registry state with proper synchronization.
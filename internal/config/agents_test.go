@@ -17,7 +17,7 @@ func isClaudeCmd(cmd string) bool {
 func TestBuiltinPresets(t *testing.T) {
 	t.Parallel()
 	// Ensure all built-in presets are accessible
-	presets := []AgentPreset{AgentClaude, AgentGemini, AgentCodex, AgentCursor, AgentAuggie, AgentAmp, AgentOpenCode, AgentCopilot, AgentPi, AgentOmp}
+	presets := []AgentPreset{AgentClaude, AgentGemini, AgentCodex, AgentCursor, AgentAuggie, AgentAmp, AgentOpenCode, AgentCopilot, AgentPi, AgentOmp, AgentSim}
 
 	for _, preset := range presets {
 		info := GetAgentPreset(preset)
@@ -55,6 +55,7 @@ func TestGetAgentPresetByName(t *testing.T) {
 		{"copilot", AgentCopilot, false},   // Built-in GitHub Copilot CLI agent
 		{"pi", AgentPi, false},             // Pi Coding Agent
 		{"omp", AgentOmp, false},           // Oh My Pi
+		{"sim", AgentSim, false},           // Scripted simulated agent
 		{"unknown", "", true},
 	}
 
@@ -138,6 +139,7 @@ func TestIsKnownPreset(t *testing.T) {
 		{"copilot", true},   // Built-in GitHub Copilot CLI agent
 		{"pi", true},        // Pi Coding Agent
 		{"omp", true},       // Oh My Pi
+		{"sim", true},       // Scripted simulated agent
 		{"unknown", false},
 		{"chatgpt", false},
 	}
@@ -630,7 +632,7 @@ func TestGetProcessNames(t *testing.T) {
 func TestListAgentPresetsMatchesConstants(t *testing.T) {
 	t.Parallel()
 	// Ensure all AgentPreset constants are returned by ListAgentPresets
-	allConstants := []AgentPreset{AgentClaude, AgentGemini, AgentCodex, AgentCursor, AgentAuggie, AgentAmp, AgentOpenCode, AgentCopilot, AgentPi}
+	allConstants := []AgentPreset{AgentClaude, AgentGemini, AgentCodex, AgentCursor, AgentAuggie, AgentAmp, AgentOpenCode, AgentCopilot, AgentPi, AgentOmp, AgentSim}
 	presets := ListAgentPresets()
 
 	// Convert to map for quick lookup
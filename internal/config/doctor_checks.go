@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Doctor check severities.
+const (
+	DoctorSeverityError   = "error"
+	DoctorSeverityWarning = "warning"
+)
+
+// DefaultDoctorCheckTimeout bounds a user-defined doctor check command.
+const DefaultDoctorCheckTimeout = 30 * time.Second
+
+// ErrInvalidDoctorCheck indicates a malformed doctor_checks entry.
+var ErrInvalidDoctorCheck = errors.New("invalid doctor check")
+
+// DoctorCheckConfig declares an external gt doctor check in town or rig
+// settings. The command runs through sh -c; it passes when its exit code is
+// ExpectExit (default 0) and, if ExpectOutput is set, its combined output
+// matches that regex.
+//
+// Example (rig settings/config.json):
+//
+//	"doctor_checks": [{
+//	  "name": "go-toolchain",
+//	  "description": "go toolchain matches go.mod",
+//	  "command": "test \"$(go env GOVERSION)\" = \"go$(go mod edit -json | jq -r .Go)\"",
+//	  "dir": "mayor/rig",
+//	  "severity": "warning"
+//	}, {
+//	  "name": "pre-commit-hook",
+//	  "command": "test -x \"$(git rev-parse --git-path hooks)/pre-commit\"",
+//	  "dir": "polecats/*/*",
+//	  "fix": "pre-commit install"
+//	}]
+type DoctorCheckConfig struct {
+	// Name identifies the check in gt doctor output. Rig checks are shown
+	// as "<rig>/<name>".
+	Name string `json:"name"`
+
+	// Description is shown by gt doctor --list.
+	Description string `json:"description,omitempty"`
+
+	// Command is the shell command to run.
+	Command string `json:"command"`
+
+	// Dir is the working directory, relative to the town root (town checks)
+	// or rig root (rig checks). It may be a glob; the check then runs in
+	// every matching directory and fails if any of them fails.
+	Dir string `json:"dir,omitempty"`
+
+	// ExpectExit is the passing exit code. Default: 0.
+	ExpectExit *int `json:"expect_exit,omitempty"`
+
+	// ExpectOutput is a regex the combined stdout/stderr must match.
+	ExpectOutput string `json:"expect_output,omitempty"`
+
+	// Severity of a failure: "error" (default) or "warning".
+	Severity string `json:"severity,omitempty"`
+
+	// Fix is an optional shell command run by gt doctor --fix, in the same
+	// directories as Command.
+	Fix string `json:"fix,omitempty"`
+
+	// Category groups the check in gt doctor output. Default: "Custom".
+	Category string `json:"category,omitempty"`
+
+	// Timeout bounds each command run, e.g. "2m". Default: 30s.
+	Timeout string `json:"timeout,omitempty"`
+
+	// DependsOn names checks that must not fail for this one to run.
+	// In rig settings, names of the rig's own checks may be used unprefixed.
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// Validate reports the first problem with c, if any.
+func (c *DoctorCheckConfig) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidDoctorCheck)
+	}
+	if c.Command == "" {
+		return fmt.Errorf("%w %q: command is required", ErrInvalidDoctorCheck, c.Name)
+	}
+	if c.Severity != "" && c.Severity != DoctorSeverityError && c.Severity != DoctorSeverityWarning {
+		return fmt.Errorf("%w %q: severity must be %q or %q, got %q",
+			ErrInvalidDoctorCheck, c.Name, DoctorSeverityError, DoctorSeverityWarning, c.Severity)
+	}
+	if c.ExpectOutput != "" {
+		if _, err := regexp.Compile(c.ExpectOutput); err != nil {
+			return fmt.Errorf("%w %q: expect_output: %v", ErrInvalidDoctorCheck, c.Name, err)
+		}
+	}
+	if c.Timeout != "" {
+		if d, err := time.ParseDuration(c.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("%w %q: invalid timeout %q", ErrInvalidDoctorCheck, c.Name, c.Timeout)
+		}
+	}
+	return nil
+}
+
+// ExpectExitV returns the passing exit code.
+func (c *DoctorCheckConfig) ExpectExitV() int {
+	if c.ExpectExit != nil {
+		return *c.ExpectExit
+	}
+	return 0
+}
+
+// TimeoutD returns the per-run timeout.
+func (c *DoctorCheckConfig) TimeoutD() time.Duration {
+	if d, err := time.ParseDuration(c.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return DefaultDoctorCheckTimeout
+}
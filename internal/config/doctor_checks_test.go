@@ -0,0 +1,44 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDoctorCheckConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  DoctorCheckConfig
+		ok   bool
+	}{
+		{"minimal", DoctorCheckConfig{Name: "c", Command: "true"}, true},
+		{"full", DoctorCheckConfig{Name: "c", Command: "true", Severity: "warning", ExpectOutput: `^ok`, Timeout: "2m"}, true},
+		{"no name", DoctorCheckConfig{Command: "true"}, false},
+		{"no command", DoctorCheckConfig{Name: "c"}, false},
+		{"bad severity", DoctorCheckConfig{Name: "c", Command: "true", Severity: "fatal"}, false},
+		{"bad regex", DoctorCheckConfig{Name: "c", Command: "true", ExpectOutput: "("}, false},
+		{"bad timeout", DoctorCheckConfig{Name: "c", Command: "true", Timeout: "-1s"}, false},
+	}
+	for _, tt := range tests {
+		err := tt.cfg.Validate()
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: Validate() = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidDoctorCheck) {
+			t.Errorf("%s: error %v does not wrap ErrInvalidDoctorCheck", tt.name, err)
+		}
+	}
+}
+
+func TestDoctorCheckConfig_Defaults(t *testing.T) {
+	var c DoctorCheckConfig
+	if c.ExpectExitV() != 0 || c.TimeoutD() != DefaultDoctorCheckTimeout {
+		t.Errorf("defaults = %d, %v", c.ExpectExitV(), c.TimeoutD())
+	}
+	three := 3
+	c = DoctorCheckConfig{ExpectExit: &three, Timeout: "90s"}
+	if c.ExpectExitV() != 3 || c.TimeoutD() != 90*time.Second {
+		t.Errorf("overrides = %d, %v", c.ExpectExitV(), c.TimeoutD())
+	}
+}
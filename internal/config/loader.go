@@ -206,6 +206,12 @@ func validateRigConfig(c *RigConfig) error {
 	return nil
 }
 
+// ValidateRigSettings checks a RigSettings the way LoadRigSettings and
+// SaveRigSettings do, for callers that write settings files themselves.
+func ValidateRigSettings(c *RigSettings) error {
+	return validateRigSettings(c)
+}
+
 // validateRigSettings validates a RigSettings.
 func validateRigSettings(c *RigSettings) error {
 	if c.Type != "rig-settings" && c.Type != "" {
@@ -893,6 +899,9 @@ func validateMessagingConfig(c *MessagingConfig) error {
 	if c.NudgeChannels == nil {
 		c.NudgeChannels = make(map[string][]string)
 	}
+	if c.Towns == nil {
+		c.Towns = make(map[string]RemoteTownConfig)
+	}
 
 	// Validate lists have at least one recipient
 	for name, recipients := range c.Lists {
@@ -931,6 +940,19 @@ func validateMessagingConfig(c *MessagingConfig) error {
 		}
 	}
 
+	// Validate remote towns have a bridge URL and mTLS credentials
+	for name, town := range c.Towns {
+		if name == "" || strings.ContainsAny(name, "/:") {
+			return fmt.Errorf("%w: invalid remote town name %q", ErrMissingField, name)
+		}
+		if !strings.HasPrefix(town.URL, "https://") {
+			return fmt.Errorf("%w: town '%s' url must be https://", ErrMissingField, name)
+		}
+		if town.CertFile == "" || town.KeyFile == "" || town.CAFile == "" {
+			return fmt.Errorf("%w: town '%s' cert_file, key_file and ca_file", ErrMissingField, name)
+		}
+	}
+
 	return nil
 }
 
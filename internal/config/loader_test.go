@@ -757,6 +757,36 @@ func TestMessagingConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid remote town",
+			config: &MessagingConfig{
+				Version: 1,
+				Towns: map[string]RemoteTownConfig{
+					"backend": {URL: "https://10.0.0.5:9876", CertFile: "c.crt", KeyFile: "c.key", CAFile: "ca.crt"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "remote town without https",
+			config: &MessagingConfig{
+				Version: 1,
+				Towns: map[string]RemoteTownConfig{
+					"backend": {URL: "http://10.0.0.5:9876", CertFile: "c.crt", KeyFile: "c.key", CAFile: "ca.crt"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "remote town missing credentials",
+			config: &MessagingConfig{
+				Version: 1,
+				Towns: map[string]RemoteTownConfig{
+					"backend": {URL: "https://10.0.0.5:9876"},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "valid config with nudge channels",
 			config: &MessagingConfig{
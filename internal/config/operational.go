@@ -2,6 +2,7 @@ package config
 
 import (
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -104,6 +105,19 @@ const (
 	DefaultWitnessDoneIntentRecentGrace  = 30 * time.Second
 )
 
+// Memory defaults.
+const (
+	DefaultMemoryPrimeTokenBudget = 1500
+	DefaultMemoryStaleAfter       = 90 * 24 * time.Hour
+)
+
+// Context defaults.
+const (
+	DefaultContextHandoffThreshold  = 0.8
+	DefaultContextForceHandoffAfter = 15 * time.Minute
+	DefaultContextWindow            = 200_000
+)
+
 // LoadOperationalConfig loads operational config from a town root.
 // Returns a valid (possibly empty) config — never nil, never errors.
 // Callers can use accessor methods that return defaults for nil sub-configs.
@@ -679,3 +693,103 @@ func (wt *WitnessThresholds) DoneIntentRecentGraceD() time.Duration {
 	}
 	return DefaultWitnessDoneIntentRecentGrace
 }
+
+// --- Memory accessors ---
+
+// GetMemoryConfig returns the memory thresholds, never nil.
+func (c *OperationalConfig) GetMemoryConfig() *MemoryThresholds {
+	if c != nil && c.Memory != nil {
+		return c.Memory
+	}
+	return &MemoryThresholds{}
+}
+
+// PrimeTokenBudgetV returns the configured or default prime memory token budget.
+func (m *MemoryThresholds) PrimeTokenBudgetV() int {
+	if m != nil && m.PrimeTokenBudget != nil {
+		return *m.PrimeTokenBudget
+	}
+	return DefaultMemoryPrimeTokenBudget
+}
+
+// StaleAfterD returns the configured or default memory staleness age.
+func (m *MemoryThresholds) StaleAfterD() time.Duration {
+	if m != nil {
+		return ParseDurationOrDefault(m.StaleAfter, DefaultMemoryStaleAfter)
+	}
+	return DefaultMemoryStaleAfter
+}
+
+// --- Context accessors ---
+
+// GetContextConfig returns the context thresholds, never nil.
+func (c *OperationalConfig) GetContextConfig() *ContextThresholds {
+	if c != nil && c.Context != nil {
+		return c.Context
+	}
+	return &ContextThresholds{}
+}
+
+// HandoffThresholdV returns the configured or default handoff threshold.
+func (c *ContextThresholds) HandoffThresholdV() float64 {
+	if c != nil && c.HandoffThreshold != nil {
+		return *c.HandoffThreshold
+	}
+	return DefaultContextHandoffThreshold
+}
+
+// ForceHandoffAfterD returns the configured or default forced handoff delay.
+func (c *ContextThresholds) ForceHandoffAfterD() time.Duration {
+	if c != nil {
+		return ParseDurationOrDefault(c.ForceHandoffAfter, DefaultContextForceHandoffAfter)
+	}
+	return DefaultContextForceHandoffAfter
+}
+
+// builtinContextWindows are the context windows of models whose window
+// isn't DefaultContextWindow, keyed by model name prefix.
+var builtinContextWindows = map[string]int{
+	"gemini-1.5-pro":   2_097_152,
+	"gemini-1.5-flash": 1_048_576,
+	"gemini-2":         1_048_576,
+	"gemini-3":         1_048_576,
+	"gpt-4.1":          1_047_576,
+	"gpt-5":            400_000,
+	"gpt-4o":           128_000,
+	"o3":               200_000,
+	"o4-mini":          200_000,
+}
+
+// oneMillionSuffix marks a Claude model selected with its 1M-token context
+// window, as in Claude Code's "sonnet[1m]".
+const oneMillionSuffix = "[1m]"
+
+// WindowFor returns the context window size for model: the configured
+// override with the longest matching prefix, else the built-in window for
+// the model, else DefaultContextWindow.
+func (c *ContextThresholds) WindowFor(model string) int {
+	if c != nil {
+		if window, ok := longestPrefixWindow(c.Windows, model); ok {
+			return window
+		}
+	}
+	if strings.HasSuffix(model, oneMillionSuffix) {
+		return 1_000_000
+	}
+	if window, ok := longestPrefixWindow(builtinContextWindows, model); ok {
+		return window
+	}
+	return DefaultContextWindow
+}
+
+// longestPrefixWindow returns the window of the longest prefix of model in
+// windows, ignoring non-positive sizes.
+func longestPrefixWindow(windows map[string]int, model string) (int, bool) {
+	window, best := 0, -1
+	for prefix, size := range windows {
+		if size > 0 && len(prefix) > best && strings.HasPrefix(model, prefix) {
+			window, best = size, len(prefix)
+		}
+	}
+	return window, best >= 0
+}
@@ -364,3 +364,94 @@ func TestWitnessThresholds_Overrides(t *testing.T) {
 		t.Errorf("DoneIntentRecentGrace: got %v, want 15s", got)
 	}
 }
+
+func TestMemoryThresholds(t *testing.T) {
+	t.Parallel()
+
+	var op *OperationalConfig
+	mem := op.GetMemoryConfig()
+	if got := mem.PrimeTokenBudgetV(); got != DefaultMemoryPrimeTokenBudget {
+		t.Errorf("PrimeTokenBudget: got %v, want %v", got, DefaultMemoryPrimeTokenBudget)
+	}
+	if got := mem.StaleAfterD(); got != DefaultMemoryStaleAfter {
+		t.Errorf("StaleAfter: got %v, want %v", got, DefaultMemoryStaleAfter)
+	}
+
+	budget := 0
+	op = &OperationalConfig{Memory: &MemoryThresholds{PrimeTokenBudget: &budget, StaleAfter: "720h"}}
+	mem = op.GetMemoryConfig()
+	if got := mem.PrimeTokenBudgetV(); got != 0 {
+		t.Errorf("PrimeTokenBudget: got %v, want 0 (uncapped)", got)
+	}
+	if got := mem.StaleAfterD(); got != 720*time.Hour {
+		t.Errorf("StaleAfter: got %v, want 720h", got)
+	}
+}
+
+func TestContextThresholds(t *testing.T) {
+	t.Parallel()
+
+	var op *OperationalConfig
+	ctx := op.GetContextConfig()
+	if got := ctx.HandoffThresholdV(); got != DefaultContextHandoffThreshold {
+		t.Errorf("HandoffThreshold: got %v, want %v", got, DefaultContextHandoffThreshold)
+	}
+	if got := ctx.ForceHandoffAfterD(); got != DefaultContextForceHandoffAfter {
+		t.Errorf("ForceHandoffAfter: got %v, want %v", got, DefaultContextForceHandoffAfter)
+	}
+	if got := ctx.WindowFor("claude-opus-4-1"); got != DefaultContextWindow {
+		t.Errorf("WindowFor: got %v, want %v", got, DefaultContextWindow)
+	}
+
+	threshold := 0.6
+	op = &OperationalConfig{Context: &ContextThresholds{
+		HandoffThreshold:  &threshold,
+		ForceHandoffAfter: "0",
+		Windows:           map[string]int{"claude-sonnet": 500_000, "claude-sonnet-4": 1_000_000},
+	}}
+	ctx = op.GetContextConfig()
+	if got := ctx.HandoffThresholdV(); got != 0.6 {
+		t.Errorf("HandoffThreshold: got %v, want 0.6", got)
+	}
+	if got := ctx.ForceHandoffAfterD(); got != 0 {
+		t.Errorf("ForceHandoffAfter: got %v, want 0 (disabled)", got)
+	}
+	for model, want := range map[string]int{
+		"claude-sonnet-4-5-20250929": 1_000_000,
+		"claude-sonnet-3-7":          500_000,
+		"claude-opus-4-1":            DefaultContextWindow,
+	} {
+		if got := ctx.WindowFor(model); got != want {
+			t.Errorf("WindowFor(%q): got %v, want %v", model, got, want)
+		}
+	}
+}
+
+func TestContextThresholds_BuiltinWindows(t *testing.T) {
+	t.Parallel()
+
+	var unset *ContextThresholds
+	for model, want := range map[string]int{
+		"claude-sonnet-4-5-20250929":     DefaultContextWindow,
+		"claude-sonnet-4-5-20250929[1m]": 1_000_000,
+		"gemini-2.5-pro":                 1_048_576,
+		"gpt-4.1-mini":                   1_047_576,
+		"gpt-5-codex":                    400_000,
+	} {
+		if got := unset.WindowFor(model); got != want {
+			t.Errorf("WindowFor(%q): got %v, want %v", model, got, want)
+		}
+	}
+
+	// Configured windows override the built-in ones.
+	ctx := &ContextThresholds{Windows: map[string]int{"gemini": 500_000, "claude-sonnet-4-5": 300_000}}
+	for model, want := range map[string]int{
+		"gemini-2.5-pro":                 500_000,
+		"claude-sonnet-4-5-20250929[1m]": 300_000,
+		"gpt-5":                          400_000,
+	} {
+		if got := ctx.WindowFor(model); got != want {
+			t.Errorf("override WindowFor(%q): got %v, want %v", model, got, want)
+		}
+	}
+}
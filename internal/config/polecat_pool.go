@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultPolecatWarmupTimeout bounds a pool warmup command when
+// WarmupTimeout is unset.
+const DefaultPolecatWarmupTimeout = 30 * time.Minute
+
+// PolecatPoolConfig keeps a rig's idle polecats warm. The daemon holds Size
+// idle polecats with their worktree at the latest target commit and Warmup
+// already run in it, so gt sling can hand one out without a checkout,
+// dependency install or build.
+//
+// Example (rig settings/config.json):
+//
+//	"polecat_pool": {
+//	  "size": 3,
+//	  "warmup": "npm ci && npm run build",
+//	  "warmup_timeout": "20m"
+//	}
+type PolecatPoolConfig struct {
+	// Size is the number of warm idle polecats to keep. 0 disables the pool.
+	Size int `json:"size"`
+
+	// Warmup is a shell command run in each pooled worktree after it moves
+	// to the target commit. Build outputs it leaves behind (dependencies,
+	// caches) carry over to the polecat's work branch.
+	Warmup string `json:"warmup,omitempty"`
+
+	// WarmupTimeout bounds the warmup command (default 30m).
+	WarmupTimeout string `json:"warmup_timeout,omitempty"`
+}
+
+// Enabled reports whether the pool keeps any polecats warm.
+func (c *PolecatPoolConfig) Enabled() bool {
+	return c != nil && c.Size > 0
+}
+
+// Validate checks the pool settings.
+func (c *PolecatPoolConfig) Validate() error {
+	if c.Size < 0 {
+		return fmt.Errorf("polecat_pool.size must not be negative, got %d", c.Size)
+	}
+	if c.WarmupTimeout != "" {
+		if d, err := time.ParseDuration(c.WarmupTimeout); err != nil || d <= 0 {
+			return fmt.Errorf("polecat_pool.warmup_timeout %q is not a positive duration", c.WarmupTimeout)
+		}
+	}
+	return nil
+}
+
+// WarmupTimeoutD returns the warmup timeout, defaulting to 30 minutes.
+func (c *PolecatPoolConfig) WarmupTimeoutD() time.Duration {
+	if d, err := time.ParseDuration(c.WarmupTimeout); err == nil && d > 0 {
+		return d
+	}
+	return DefaultPolecatWarmupTimeout
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+)
+
+// Sandbox tools.
+const (
+	SandboxToolAuto    = "auto"    // bwrap if installed, else unshare
+	SandboxToolBwrap   = "bwrap"   // bubblewrap
+	SandboxToolUnshare = "unshare" // gt's own user/mount/network namespaces
+)
+
+// Sandbox network modes.
+const (
+	SandboxNetworkProxy = "proxy" // only the gt-proxy-server endpoint (and Forward)
+	SandboxNetworkNone  = "none"  // no network at all, except Forward
+	SandboxNetworkHost  = "host"  // unrestricted
+)
+
+// DefaultSandboxWritable are home paths agents keep state in. They stay
+// writable inside the sandbox when they exist.
+var DefaultSandboxWritable = []string{"~/.claude", "~/.claude.json", "~/.cache"}
+
+// ErrInvalidSandbox indicates a malformed sandbox setting.
+var ErrInvalidSandbox = errors.New("invalid sandbox config")
+
+// SandboxConfig confines polecat sessions. Inside the sandbox the agent can
+// write only to its worktree, the rig's git directory, the town and rig
+// .runtime directories, DefaultSandboxWritable and Writable; everything
+// else is read-only, as are the git hooks and config and the sandbox's
+// own state directory. Unless Network is "host", the agent has no network
+// except the gt-proxy-server endpoint and any Forward endpoints, which are
+// relayed to the same port on the sandbox's loopback.
+//
+// Example (rig settings/config.json):
+//
+//	"sandbox": {
+//	  "enabled": true,
+//	  "network": "proxy",
+//	  "proxy_addr": "172.17.0.1:9876",
+//	  "forward": ["127.0.0.1:3307"],
+//	  "writable": ["~/go/pkg/mod"],
+//	  "hidden": ["~/.ssh", "~/.aws"]
+//	}
+type SandboxConfig struct {
+	// Enabled turns the sandbox on for new polecat sessions.
+	Enabled bool `json:"enabled"`
+
+	// Tool is "auto" (default), "bwrap" or "unshare".
+	Tool string `json:"tool,omitempty"`
+
+	// Network is "proxy" (default), "none" or "host".
+	Network string `json:"network,omitempty"`
+
+	// ProxyAddr is the gt-proxy-server host:port. Default: the host and
+	// port of GT_PROXY_URL.
+	ProxyAddr string `json:"proxy_addr,omitempty"`
+
+	// Forward lists extra host:port endpoints reachable from the sandbox,
+	// such as the Dolt server or an egress proxy for the model API.
+	Forward []string `json:"forward,omitempty"`
+
+	// Writable lists extra writable paths. "~/" expands to the home
+	// directory; relative paths are relative to the rig.
+	Writable []string `json:"writable,omitempty"`
+
+	// Hidden lists paths replaced by an empty directory or file.
+	Hidden []string `json:"hidden,omitempty"`
+}
+
+// Validate checks the sandbox settings.
+func (c *SandboxConfig) Validate() error {
+	switch c.Tool {
+	case "", SandboxToolAuto, SandboxToolBwrap, SandboxToolUnshare:
+	default:
+		return fmt.Errorf("%w: unknown tool %q", ErrInvalidSandbox, c.Tool)
+	}
+	switch c.Network {
+	case "", SandboxNetworkProxy, SandboxNetworkNone, SandboxNetworkHost:
+	default:
+		return fmt.Errorf("%w: unknown network mode %q", ErrInvalidSandbox, c.Network)
+	}
+	for _, addr := range append([]string{c.ProxyAddr}, c.Forward...) {
+		if addr == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("%w: endpoint %q: %v", ErrInvalidSandbox, addr, err)
+		}
+	}
+	return nil
+}
+
+// ToolV returns the sandbox tool, defaulting to "auto".
+func (c *SandboxConfig) ToolV() string {
+	if c.Tool == "" {
+		return SandboxToolAuto
+	}
+	return c.Tool
+}
+
+// NetworkV returns the network mode, defaulting to "proxy".
+func (c *SandboxConfig) NetworkV() string {
+	if c.Network == "" {
+		return SandboxNetworkProxy
+	}
+	return c.Network
+}
+
+// ProxyEndpoint returns the gt-proxy-server host:port, from ProxyAddr or
+// GT_PROXY_URL. It is empty when neither is set.
+func (c *SandboxConfig) ProxyEndpoint() string {
+	if c.ProxyAddr != "" {
+		return c.ProxyAddr
+	}
+	u, err := url.Parse(os.Getenv("GT_PROXY_URL"))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	if u.Port() == "" {
+		return net.JoinHostPort(u.Hostname(), "443")
+	}
+	return u.Host
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSandboxConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     SandboxConfig
+		wantErr bool
+	}{
+		{name: "defaults", cfg: SandboxConfig{Enabled: true}},
+		{name: "all set", cfg: SandboxConfig{Tool: SandboxToolBwrap, Network: SandboxNetworkNone, ProxyAddr: "10.0.0.1:9876", Forward: []string{"127.0.0.1:3307"}}},
+		{name: "unknown tool", cfg: SandboxConfig{Tool: "docker"}, wantErr: true},
+		{name: "unknown network", cfg: SandboxConfig{Network: "vpn"}, wantErr: true},
+		{name: "bad proxy addr", cfg: SandboxConfig{ProxyAddr: "proxy.local"}, wantErr: true},
+		{name: "bad forward", cfg: SandboxConfig{Forward: []string{"3307"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSandbox) {
+				t.Errorf("error %v should wrap ErrInvalidSandbox", err)
+			}
+		})
+	}
+}
+
+func TestSandboxConfigDefaults(t *testing.T) {
+	var cfg SandboxConfig
+	if got := cfg.ToolV(); got != SandboxToolAuto {
+		t.Errorf("ToolV() = %q, want %q", got, SandboxToolAuto)
+	}
+	if got := cfg.NetworkV(); got != SandboxNetworkProxy {
+		t.Errorf("NetworkV() = %q, want %q", got, SandboxNetworkProxy)
+	}
+}
+
+func TestSandboxConfigProxyEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		addr     string
+		proxyURL string
+		want     string
+	}{
+		{name: "explicit addr wins", addr: "10.0.0.1:9876", proxyURL: "https://gt-proxy:8443", want: "10.0.0.1:9876"},
+		{name: "from GT_PROXY_URL", proxyURL: "https://gt-proxy:8443", want: "gt-proxy:8443"},
+		{name: "default https port", proxyURL: "https://gt-proxy", want: "gt-proxy:443"},
+		{name: "unset", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GT_PROXY_URL", tt.proxyURL)
+			cfg := SandboxConfig{ProxyAddr: tt.addr}
+			if got := cfg.ProxyEndpoint(); got != tt.want {
+				t.Errorf("ProxyEndpoint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
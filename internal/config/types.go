@@ -96,8 +96,27 @@ type TownSettings struct {
 	// These were previously hardcoded as Go constants throughout the codebase.
 	// All values are optional — omitted values use compiled-in defaults.
 	Operational *OperationalConfig `json:"operational,omitempty"`
+
+	// DoctorChecks are user-defined gt doctor checks run from the town root.
+	DoctorChecks []DoctorCheckConfig `json:"doctor_checks,omitempty"`
+
+	// SessionBackend selects what runs agent sessions.
+	// Values: "tmux" (default) or "pty" (gt-owned PTY supervisors).
+	SessionBackend string `json:"session_backend,omitempty"`
+
+	// ExperimentalPTYBackend opts in to session_backend "pty", which is
+	// experimental: many gt commands (attach, crew start, quota rotate's
+	// respawn, ...) still drive tmux only. PTY sessions refuse to start
+	// without it.
+	ExperimentalPTYBackend bool `json:"experimental_pty_backend,omitempty"`
 }
 
+// Session backends.
+const (
+	SessionBackendTmux = "tmux"
+	SessionBackendPTY  = "pty"
+)
+
 // NewTownSettings creates a new TownSettings with defaults.
 func NewTownSettings() *TownSettings {
 	return &TownSettings{
@@ -215,6 +234,12 @@ type OperationalConfig struct {
 
 	// Witness configures witness patrol thresholds.
 	Witness *WitnessThresholds `json:"witness,omitempty"`
+
+	// Memory configures agent memory injection at prime time.
+	Memory *MemoryThresholds `json:"memory,omitempty"`
+
+	// Context configures context-window pressure tracking and proactive handoff.
+	Context *ContextThresholds `json:"context,omitempty"`
 }
 
 // SessionThresholds configures session management timeouts.
@@ -431,6 +456,39 @@ type WitnessThresholds struct {
 	DoneIntentRecentGrace string `json:"done_intent_recent_grace,omitempty"`
 }
 
+// MemoryThresholds configures agent memory injection.
+type MemoryThresholds struct {
+	// PrimeTokenBudget caps the estimated tokens of memories injected by
+	// gt prime (default 1500). Zero or negative disables the cap.
+	PrimeTokenBudget *int `json:"prime_token_budget,omitempty"`
+
+	// StaleAfter is the age after which 'gt memories --stale' reports a
+	// memory for review (default "2160h", 90 days).
+	StaleAfter string `json:"stale_after,omitempty"`
+}
+
+// ContextThresholds configures context-window pressure tracking. A session's
+// pressure is the prompt size of its latest turn (input plus cache tokens)
+// over its model's context window.
+type ContextThresholds struct {
+	// HandoffThreshold is the fraction of the context window at which the
+	// daemon nudges an agent to checkpoint and run gt handoff (default 0.8).
+	// Zero or negative disables context-pressure handoffs.
+	HandoffThreshold *float64 `json:"handoff_threshold,omitempty"`
+
+	// ForceHandoffAfter is how long an agent may stay over the threshold after
+	// being nudged before the daemon saves its state and cycles the session
+	// (default "15m"). "0" disables forced handoffs.
+	ForceHandoffAfter string `json:"force_handoff_after,omitempty"`
+
+	// Windows overrides context window sizes in tokens, keyed by model name
+	// prefix (e.g. {"claude-sonnet-4": 1000000}). The longest matching prefix
+	// wins; unmatched models use gt's built-in window for the model (1M for
+	// "[1m]" Claude models and long-context Gemini and GPT models), else
+	// 200000.
+	Windows map[string]int `json:"windows,omitempty"`
+}
+
 // DefaultOperationalConfig returns an OperationalConfig with all defaults.
 func DefaultOperationalConfig() *OperationalConfig {
 	return &OperationalConfig{}
@@ -621,6 +679,20 @@ type RigSettings struct {
 	// Takes precedence over RoleAgents["crew"] but is overridden by explicit --agent flags.
 	// Example: {"denali": "codex", "glacier": "gemini"}
 	WorkerAgents map[string]string `json:"worker_agents,omitempty"`
+
+	// DoctorChecks are user-defined gt doctor checks run from the rig root.
+	DoctorChecks []DoctorCheckConfig `json:"doctor_checks,omitempty"`
+
+	// Sandbox confines this rig's polecat sessions with Linux namespaces.
+	Sandbox *SandboxConfig `json:"sandbox,omitempty"`
+
+	// PolecatPool keeps idle polecats warm for instant dispatch.
+	PolecatPool *PolecatPoolConfig `json:"polecat_pool,omitempty"`
+
+	// Plugins names the town plugins (<town>/plugins/<name>) this rig
+	// enables. A town plugin still runs once for the whole town; the dog
+	// running it is told which rigs enabled it.
+	Plugins []string `json:"plugins,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -1145,7 +1217,9 @@ type MergeQueueConfig struct {
 	// Nil defaults to true (merged branches are deleted).
 	DeleteMergedBranches *bool `json:"delete_merged_branches,omitempty"`
 
-	// RetryFlakyTests is the number of times to retry flaky tests.
+	// RetryFlakyTests is the total number of attempts per test command or
+	// gate, including the first run. Values below 2 disable retries, and with
+	// them flake detection.
 	RetryFlakyTests int `json:"retry_flaky_tests"`
 
 	// PollInterval is how often to poll for new merge requests (e.g., "30s").
@@ -1340,6 +1414,11 @@ type MessagingConfig struct {
 	// Like mailing lists but for tmux send-keys instead of durable mail.
 	// Example: {"workers": ["gastown/polecats/*", "gastown/crew/*"], "witnesses": ["*/witness"]}
 	NudgeChannels map[string][]string `json:"nudge_channels,omitempty"`
+
+	// Towns are remote towns reachable through their gt-proxy-server mail bridge.
+	// Messages addressed to "town:<name>/<address>" are relayed over mTLS.
+	// Example: {"backend": {"url": "https://10.0.0.5:9876", "cert_file": "...", ...}}
+	Towns map[string]RemoteTownConfig `json:"towns,omitempty"`
 }
 
 // QueueConfig represents a work queue configuration.
@@ -1362,6 +1441,23 @@ type AnnounceConfig struct {
 	RetainCount int `json:"retain_count,omitempty"`
 }
 
+// RemoteTownConfig describes how to reach another town's mail bridge.
+// The client certificate must be issued by the remote town's proxy CA with
+// CN "gt-town-<local town name>" so the remote side can authenticate us.
+type RemoteTownConfig struct {
+	// URL is the remote gt-proxy-server base URL (e.g. "https://10.0.0.5:9876").
+	URL string `json:"url"`
+
+	// CertFile is the PEM client certificate issued by the remote town's CA.
+	CertFile string `json:"cert_file"`
+
+	// KeyFile is the PEM private key for CertFile.
+	KeyFile string `json:"key_file"`
+
+	// CAFile is the remote town's CA certificate, used to verify the server.
+	CAFile string `json:"ca_file"`
+}
+
 // CurrentMessagingVersion is the current schema version for MessagingConfig.
 const CurrentMessagingVersion = 1
 
@@ -1374,6 +1470,7 @@ func NewMessagingConfig() *MessagingConfig {
 		Queues:        make(map[string]QueueConfig),
 		Announces:     make(map[string]AnnounceConfig),
 		NudgeChannels: make(map[string][]string),
+		Towns:         make(map[string]RemoteTownConfig),
 	}
 }
 
@@ -146,6 +146,11 @@ const (
 	// This prevents the handoff loop bug where agents re-run /handoff from context.
 	FileHandoffMarker = "handoff_to_successor"
 
+	// FileHandoffDocument is the structured handoff record in .runtime/.
+	// Written by gt handoff, rendered by gt prime and compared against the
+	// workspace by gt resume --diff.
+	FileHandoffDocument = "handoff.json"
+
 	// FileLastHandoffTS records the timestamp of the last handoff.
 	// Used to enforce MinHandoffCooldown and prevent tight restart loops.
 	// (gt-058d)
@@ -788,11 +788,12 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 	}
 
 	t := tmux.NewTmux()
+	sessions := session.NewTownBackend(t, townRoot)
 	sessionID := m.SessionName(name)
 
 	// Check if session already exists — kill AFTER command is fully built
 	// so validation failures don't destroy the user's running session.
-	running, err := t.HasSession(sessionID)
+	running, err := sessions.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -800,17 +801,17 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		if opts.KillExisting {
 			// Restart/resume mode - kill existing session.
 			// Use KillSessionWithProcesses to ensure all descendant processes are killed.
-			if err := t.KillSessionWithProcesses(sessionID); err != nil {
+			if err := sessions.KillSessionWithProcesses(sessionID); err != nil {
 				return fmt.Errorf("killing existing session: %w", err)
 			}
 		} else {
 			// Normal start - session exists, check if agent is actually running
-			if t.IsAgentAlive(sessionID) {
+			if sessions.IsAgentAlive(sessionID) {
 				return fmt.Errorf("%w: %s", ErrSessionRunning, sessionID)
 			}
 			// Zombie session - kill and recreate.
 			// Use KillSessionWithProcesses to ensure all descendant processes are killed.
-			if err := t.KillSessionWithProcesses(sessionID); err != nil {
+			if err := sessions.KillSessionWithProcesses(sessionID); err != nil {
 				return fmt.Errorf("killing zombie session: %w", err)
 			}
 		}
@@ -826,24 +827,35 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 	// initial shell inherits the correct GT_ROLE (not the parent's).
 	// See: https://github.com/anthropics/gastown/issues/280 (race condition fix)
 	// See: https://github.com/steveyegge/gastown/issues/1289 (env inheritance fix)
-	if err := t.NewSessionWithCommandAndEnv(sessionID, worker.ClonePath, claudeCmd, envVars); err != nil {
-		return fmt.Errorf("creating session: %w", err)
+	// PTY sessions take the env vars once started; the startup command
+	// already exports them to the agent.
+	b := session.NewBackendWith(t, townRoot)
+	if tb, ok := b.(*tmux.Tmux); ok {
+		if err := tb.NewSessionWithCommandAndEnv(sessionID, worker.ClonePath, claudeCmd, envVars); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+		// Set up C-b n/p keybindings for crew session cycling (non-fatal)
+		_ = tb.SetCrewCycleBindings(sessionID)
+	} else {
+		if err := b.NewSessionWithCommand(sessionID, worker.ClonePath, claudeCmd); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+		for k, v := range envVars {
+			_ = b.SetEnvironment(sessionID, k, v)
+		}
 	}
 
 	// Record agent's pane_id for ZFC-compliant liveness checks (gt-qmsx).
-	if paneID, err := t.GetPaneID(sessionID); err == nil {
-		_ = t.SetEnvironment(sessionID, "GT_PANE_ID", paneID)
+	if paneID, err := session.PaneID(b, sessionID); err == nil {
+		_ = b.SetEnvironment(sessionID, "GT_PANE_ID", paneID)
 	}
 
 	// Apply rig-based theming (non-fatal: theming failure doesn't affect operation)
 	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, name, "crew")
-
-	// Set up C-b n/p keybindings for crew session cycling (non-fatal)
-	_ = t.SetCrewCycleBindings(sessionID)
+	_ = session.ApplyTheme(b, sessionID, theme, m.rig.Name, name, "crew")
 
 	// Track PID for defense-in-depth orphan cleanup (non-fatal)
-	_ = session.TrackSessionPID(townRoot, sessionID, t)
+	_ = session.TrackSessionPID(townRoot, sessionID, b)
 
 	// Wait for the agent to start, then accept any startup dialogs that appear.
 	// Workspace trust dialog is independent of bypass permissions and can appear
@@ -859,25 +871,25 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		}
 		preset := config.GetAgentPresetByName(agentName)
 		if preset != nil && preset.EmitsPermissionWarning {
-			if err := t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+			if err := session.WaitForCommand(b, sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 				// Non-fatal — agent might still start
 				style.PrintWarning("timeout waiting for agent to start: %v", err)
 			}
-			_ = t.AcceptStartupDialogs(sessionID)
+			_ = session.AcceptStartupDialogs(b, sessionID)
 		}
 	}
 
 	return nil
 }
 
-// Stop terminates a crew member's tmux session.
+// Stop terminates a crew member's session.
 func (m *Manager) Stop(name string) error {
 	if err := validateCrewName(name); err != nil {
 		return err
 	}
 
-	t := tmux.NewTmux()
 	sessionID := m.SessionName(name)
+	t := session.BackendFor(filepath.Dir(m.rig.Path), sessionID)
 
 	// Check if session exists
 	running, err := t.HasSession(sessionID)
@@ -900,7 +912,6 @@ func (m *Manager) Stop(name string) error {
 
 // IsRunning checks if a crew member's session is active.
 func (m *Manager) IsRunning(name string) (bool, error) {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName(name)
-	return t.HasSession(sessionID)
+	return session.BackendFor(filepath.Dir(m.rig.Path), sessionID).HasSession(sessionID)
 }
@@ -0,0 +1,95 @@
+// Package ctxpressure measures how full an agent session's context window is.
+//
+// A session's pressure is the prompt size of its latest turn (input plus
+// cache read and creation tokens, from the agent's conversation log) over the
+// context window of the model that served it. It is read on demand from the
+// log rather than from the gt agent-log stream, which is opt-in.
+//
+// gt vitals, the tmux status line and the feed TUI show it; the daemon uses
+// it to nudge agents over the configured threshold to hand off, and to force a
+// handoff when they don't.
+package ctxpressure
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// ErrUnsupported is returned by ForSession for agents whose conversation logs
+// gt can't read context usage from (only Claude Code's, so far).
+var ErrUnsupported = agentlog.ErrContextUnsupported
+
+// Reading is one session's context-window pressure.
+type Reading struct {
+	Session string
+	Model   string
+	Tokens  int       // prompt size of the latest turn
+	Window  int       // the model's context window
+	At      time.Time // when the latest turn was logged
+}
+
+// Fraction returns the share of the context window in use, from 0 to 1 (or
+// more if the window is configured too small).
+func (r *Reading) Fraction() float64 {
+	if r == nil || r.Window <= 0 {
+		return 0
+	}
+	return float64(r.Tokens) / float64(r.Window)
+}
+
+// Percent returns Fraction as a whole percentage.
+func (r *Reading) Percent() int {
+	return int(r.Fraction()*100 + 0.5)
+}
+
+// Over reports whether the reading is at or above threshold. A zero or
+// negative threshold disables pressure handoffs, so nothing is over it.
+func (r *Reading) Over(threshold float64) bool {
+	return threshold > 0 && r.Fraction() >= threshold
+}
+
+// Badge returns a compact label such as "🧠 82%".
+func (r *Reading) Badge() string {
+	return fmt.Sprintf("🧠 %d%%", r.Percent())
+}
+
+// New builds a reading from a conversation's latest usage, sizing the window
+// from cfg.
+func New(session string, usage *agentlog.ContextUsage, cfg *config.ContextThresholds) *Reading {
+	return &Reading{
+		Session: session,
+		Model:   usage.Model,
+		Tokens:  usage.Tokens,
+		Window:  cfg.WindowFor(usage.Model),
+		At:      usage.Timestamp,
+	}
+}
+
+// ForSession reads the context pressure of the agent in a session on any
+// backend. The conversation log is found from the session's agent (GT_AGENT,
+// else the default preset) and working directory, ignoring logs older than
+// the session. It returns nil when the agent has not finished a turn yet.
+func ForSession(b session.Backend, name string, cfg *config.ContextThresholds) (*Reading, error) {
+	agent, _ := b.GetEnvironment(name, "GT_AGENT")
+	if agent == "" {
+		agent = string(config.DefaultAgentPreset())
+	}
+	workDir, err := session.WorkDir(b, name)
+	if err != nil {
+		return nil, fmt.Errorf("getting working directory: %w", err)
+	}
+	var since time.Time
+	if created := session.CreatedUnix(b, name); created > 0 {
+		since = time.Unix(created, 0)
+	}
+
+	usage, err := agentlog.LastContextUsage(agentlog.AgentTypeForPreset(agent), workDir, since)
+	if err != nil || usage == nil {
+		return nil, err
+	}
+	return New(name, usage, cfg), nil
+}
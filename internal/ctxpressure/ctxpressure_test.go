@@ -0,0 +1,40 @@
+package ctxpressure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestReading(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.ContextThresholds{Windows: map[string]int{"claude-sonnet-4": 1_000_000}}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	r := New("hq-mayor", &agentlog.ContextUsage{Model: "claude-opus-4-1", Tokens: 165_000, Timestamp: at}, cfg)
+	if r.Window != config.DefaultContextWindow || r.At != at || r.Session != "hq-mayor" {
+		t.Fatalf("New() = %+v", r)
+	}
+	if got := r.Percent(); got != 83 {
+		t.Errorf("Percent() = %d, want 83", got)
+	}
+	if got := r.Badge(); got != "🧠 83%" {
+		t.Errorf("Badge() = %q", got)
+	}
+	if !r.Over(0.8) || r.Over(0.9) || r.Over(0) {
+		t.Errorf("Over() wrong at %v", r.Fraction())
+	}
+
+	wide := New("gt-crew-max", &agentlog.ContextUsage{Model: "claude-sonnet-4-5", Tokens: 165_000}, cfg)
+	if wide.Window != 1_000_000 || wide.Over(0.8) {
+		t.Errorf("configured window not used: %+v", wide)
+	}
+
+	var none *Reading
+	if none.Fraction() != 0 || none.Over(0.5) {
+		t.Error("nil reading should report no pressure")
+	}
+}
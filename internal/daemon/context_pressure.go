@@ -0,0 +1,212 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/ctxpressure"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// contextAction is what the daemon does about a session's context pressure.
+type contextAction int
+
+const (
+	contextNone  contextAction = iota // under threshold, or waiting on a nudge
+	contextNudge                      // newly over threshold: ask the agent to hand off
+	contextForce                      // nudge ignored too long: hand off for it
+)
+
+// decideContextAction picks the action for a session over (or under) the
+// handoff threshold. nudgedAt is when the session was last nudged, zero if
+// it hasn't been; forceAfter <= 0 disables forced handoffs.
+func decideContextAction(over bool, nudgedAt time.Time, forceAfter time.Duration, now time.Time) contextAction {
+	switch {
+	case !over:
+		return contextNone
+	case nudgedAt.IsZero():
+		return contextNudge
+	case forceAfter > 0 && now.Sub(nudgedAt) >= forceAfter:
+		return contextForce
+	default:
+		return contextNone
+	}
+}
+
+// contextSession is one agent session's context-pressure reading for a
+// heartbeat. reading is nil when it could not be read.
+type contextSession struct {
+	name     string
+	identity string
+	reading  *ctxpressure.Reading
+}
+
+// checkContextPressure nudges agents whose context window is over the
+// configured threshold to checkpoint and run gt handoff, and forces a
+// graceful handoff when an agent stays over it for too long after the nudge.
+// A cycled session starts with a fresh conversation, so its reading drops
+// and its nudge state clears on the next heartbeat.
+func (d *Daemon) checkContextPressure() {
+	cfg := d.loadOperationalConfig().GetContextConfig()
+	threshold := cfg.HandoffThresholdV()
+	if threshold <= 0 {
+		d.contextNudged = nil
+		return
+	}
+
+	names, err := session.ListAll(d.tmux, d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("context_pressure: listing sessions: %v", err)
+		return
+	}
+
+	var sessions []contextSession
+	for _, name := range names {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		identity := lifecycleIdentity(id)
+		if identity == "" {
+			continue
+		}
+		reading, _ := ctxpressure.ForSession(d.backendFor(name), name, cfg)
+		sessions = append(sessions, contextSession{name: name, identity: identity, reading: reading})
+	}
+	d.updateContextPressure(sessions, threshold, cfg.ForceHandoffAfterD(), time.Now())
+}
+
+// updateContextPressure acts on one heartbeat's readings. A forced handoff
+// that fails restarts the wait, so it is retried after another forceAfter
+// rather than on every heartbeat.
+func (d *Daemon) updateContextPressure(sessions []contextSession, threshold float64, forceAfter time.Duration, now time.Time) {
+	if d.contextNudged == nil {
+		d.contextNudged = make(map[string]time.Time)
+	}
+	force := d.contextForceFn
+	if force == nil {
+		force = d.forceContextHandoff
+	}
+
+	live := make(map[string]bool)
+	for _, s := range sessions {
+		live[s.name] = true
+		if s.reading == nil {
+			continue
+		}
+		switch decideContextAction(s.reading.Over(threshold), d.contextNudged[s.name], forceAfter, now) {
+		case contextNudge:
+			d.nudgeContextHandoff(s.name, s.reading, forceAfter)
+			d.contextNudged[s.name] = now
+		case contextForce:
+			if err := force(s.name, s.identity, s.reading); err != nil {
+				d.logger.Printf("context_pressure: forced handoff of %s failed, retrying in %s: %v", s.name, forceAfter, err)
+				d.contextNudged[s.name] = now
+				continue
+			}
+			delete(d.contextNudged, s.name)
+		default:
+			if !s.reading.Over(threshold) {
+				delete(d.contextNudged, s.name)
+			}
+		}
+	}
+	for name := range d.contextNudged {
+		if !live[name] {
+			delete(d.contextNudged, name)
+		}
+	}
+}
+
+// nudgeContextHandoff queues an urgent nudge asking the agent to checkpoint
+// and hand off.
+func (d *Daemon) nudgeContextHandoff(sessionName string, reading *ctxpressure.Reading, forceAfter time.Duration) {
+	msg := fmt.Sprintf("Your context window is %d%% full (%dk of %dk tokens). "+
+		"Checkpoint now: commit or record your progress, then run `gt handoff` "+
+		"so a fresh session picks up your hooked work.",
+		reading.Percent(), reading.Tokens/1000, reading.Window/1000)
+	if forceAfter > 0 {
+		msg += fmt.Sprintf(" If you have not handed off within %s, the daemon will save your state and cycle this session.", forceAfter)
+	}
+
+	if err := nudge.Enqueue(d.config.TownRoot, sessionName, nudge.QueuedNudge{
+		Sender:   "daemon",
+		Message:  msg,
+		Priority: nudge.PriorityUrgent,
+	}); err != nil {
+		d.logger.Printf("context_pressure: nudging %s: %v", sessionName, err)
+		return
+	}
+	d.logger.Printf("context_pressure: %s at %d%% of its context window, nudged to hand off", sessionName, reading.Percent())
+}
+
+// forceContextHandoff hands off for an agent that ignored the nudge: its
+// state is saved with gt handoff --auto, run in the agent's directory and
+// environment so the handoff mail reaches its successor, and the session is
+// then cycled into a fresh one.
+func (d *Daemon) forceContextHandoff(sessionName, identity string, reading *ctxpressure.Reading) error {
+	parsed, err := parseIdentity(identity)
+	if err != nil {
+		return err
+	}
+	if parsed.RigName != "" {
+		if operational, reason := d.isRigOperational(parsed.RigName); !operational {
+			return fmt.Errorf("not cycling: %s", reason)
+		}
+	}
+
+	d.logger.Printf("context_pressure: %s still at %d%% of its context window, forcing handoff", sessionName, reading.Percent())
+
+	b := d.backendFor(sessionName)
+	workDir, err := session.WorkDir(b, sessionName)
+	if err != nil {
+		return fmt.Errorf("getting working directory: %w", err)
+	}
+	env := os.Environ()
+	if sessionEnv, err := session.Environment(b, sessionName); err == nil {
+		for k, v := range sessionEnv {
+			env = append(env, k+"="+v)
+		}
+	}
+	subject := fmt.Sprintf("🤝 HANDOFF: context-pressure (%d%% full)", reading.Percent())
+	cmd := exec.Command(d.gtPath, "handoff", "--auto", "--reason", "context-pressure", "--subject", subject) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = workDir
+	cmd.Env = env
+	if out, err := cmd.CombinedOutput(); err != nil {
+		// The cycle still frees the context; the successor just starts
+		// without the handoff mail.
+		d.logger.Printf("context_pressure: saving state for %s: %v: %s", sessionName, err, strings.TrimSpace(string(out)))
+	}
+
+	return d.executeLifecycleAction(&LifecycleRequest{
+		From:      identity,
+		Action:    ActionCycle,
+		Timestamp: time.Now(),
+	})
+}
+
+// lifecycleIdentity returns the lifecycle identity (see parseIdentity) of an
+// agent session, or "" for sessions the daemon doesn't cycle.
+func lifecycleIdentity(id *session.AgentIdentity) string {
+	switch id.Role {
+	case session.RoleMayor:
+		return constants.RoleMayor
+	case session.RoleDeacon:
+		return constants.RoleDeacon
+	case session.RoleWitness:
+		return id.Rig + "-witness"
+	case session.RoleRefinery:
+		return id.Rig + "-refinery"
+	case session.RoleCrew:
+		return id.Rig + "-crew-" + id.Name
+	case session.RolePolecat:
+		return id.Rig + "/polecats/" + id.Name
+	default:
+		return ""
+	}
+}
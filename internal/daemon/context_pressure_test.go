@@ -0,0 +1,163 @@
+package daemon
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/ctxpressure"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestDecideContextAction(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		over       bool
+		nudgedAt   time.Time
+		forceAfter time.Duration
+		want       contextAction
+	}{
+		{"under threshold", false, time.Time{}, 15 * time.Minute, contextNone},
+		{"under after nudge", false, now.Add(-time.Hour), 15 * time.Minute, contextNone},
+		{"newly over", true, time.Time{}, 15 * time.Minute, contextNudge},
+		{"over, waiting on nudge", true, now.Add(-5 * time.Minute), 15 * time.Minute, contextNone},
+		{"over, nudge ignored", true, now.Add(-15 * time.Minute), 15 * time.Minute, contextForce},
+		{"over, force disabled", true, now.Add(-time.Hour), 0, contextNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decideContextAction(tt.over, tt.nudgedAt, tt.forceAfter, now); got != tt.want {
+				t.Errorf("decideContextAction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLifecycleIdentity_RoundTrip(t *testing.T) {
+	tests := []struct {
+		id   session.AgentIdentity
+		want ParsedIdentity
+	}{
+		{session.AgentIdentity{Role: session.RoleMayor}, ParsedIdentity{RoleType: constants.RoleMayor}},
+		{session.AgentIdentity{Role: session.RoleDeacon}, ParsedIdentity{RoleType: constants.RoleDeacon}},
+		{session.AgentIdentity{Role: session.RoleWitness, Rig: "gastown"}, ParsedIdentity{RoleType: constants.RoleWitness, RigName: "gastown"}},
+		{session.AgentIdentity{Role: session.RoleRefinery, Rig: "gastown"}, ParsedIdentity{RoleType: constants.RoleRefinery, RigName: "gastown"}},
+		{session.AgentIdentity{Role: session.RoleCrew, Rig: "gastown", Name: "max"}, ParsedIdentity{RoleType: constants.RoleCrew, RigName: "gastown", AgentName: "max"}},
+		{session.AgentIdentity{Role: session.RolePolecat, Rig: "gastown", Name: "Toast"}, ParsedIdentity{RoleType: constants.RolePolecat, RigName: "gastown", AgentName: "Toast"}},
+	}
+	for _, tt := range tests {
+		identity := lifecycleIdentity(&tt.id)
+		parsed, err := parseIdentity(identity)
+		if err != nil {
+			t.Errorf("parseIdentity(%q) error: %v", identity, err)
+			continue
+		}
+		if *parsed != tt.want {
+			t.Errorf("lifecycleIdentity(%+v) = %q, parsed %+v, want %+v", tt.id, identity, *parsed, tt.want)
+		}
+	}
+
+	if got := lifecycleIdentity(&session.AgentIdentity{Role: session.RoleOverseer}); got != "" {
+		t.Errorf("overseer identity = %q, want empty", got)
+	}
+}
+
+func TestNudgeContextHandoff(t *testing.T) {
+	townRoot := t.TempDir()
+	d := testHandlerDaemon(t, townRoot)
+	reading := &ctxpressure.Reading{Session: "gt-crew-max", Tokens: 170_000, Window: 200_000}
+
+	d.nudgeContextHandoff("gt-crew-max", reading, 15*time.Minute)
+
+	nudges, err := nudge.Drain(townRoot, "gt-crew-max")
+	if err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if len(nudges) != 1 {
+		t.Fatalf("got %d nudges, want 1", len(nudges))
+	}
+	n := nudges[0]
+	if n.Sender != "daemon" || n.Priority != nudge.PriorityUrgent {
+		t.Errorf("nudge = %+v, want urgent from daemon", n)
+	}
+	for _, want := range []string{"85% full", "170k of 200k", "gt handoff", "within 15m0s"} {
+		if !strings.Contains(n.Message, want) {
+			t.Errorf("message %q missing %q", n.Message, want)
+		}
+	}
+}
+
+func TestUpdateContextPressure_ClearsAfterCycle(t *testing.T) {
+	townRoot := t.TempDir()
+	d := testHandlerDaemon(t, townRoot)
+	forced := 0
+	d.contextForceFn = func(string, string, *ctxpressure.Reading) error {
+		forced++
+		return nil
+	}
+	const name, forceAfter = "gt-crew-max", 15 * time.Minute
+	over := []contextSession{{name: name, identity: "gastown-crew-max", reading: &ctxpressure.Reading{Tokens: 190_000, Window: 200_000}}}
+	t0 := time.Now()
+
+	d.updateContextPressure(over, 0.8, forceAfter, t0)
+	if _, ok := d.contextNudged[name]; !ok {
+		t.Fatal("session over threshold was not marked nudged")
+	}
+	d.updateContextPressure(over, 0.8, forceAfter, t0.Add(16*time.Minute))
+	if forced != 1 {
+		t.Fatalf("forced %d times, want 1", forced)
+	}
+	if _, ok := d.contextNudged[name]; ok {
+		t.Error("nudge state kept after a forced cycle")
+	}
+
+	// The fresh session has no reading yet, then a low one.
+	d.updateContextPressure([]contextSession{{name: name, identity: "gastown-crew-max"}}, 0.8, forceAfter, t0.Add(17*time.Minute))
+	d.updateContextPressure([]contextSession{{name: name, identity: "gastown-crew-max", reading: &ctxpressure.Reading{Tokens: 20_000, Window: 200_000}}}, 0.8, forceAfter, t0.Add(18*time.Minute))
+	if len(d.contextNudged) != 0 || forced != 1 {
+		t.Errorf("after cycle: nudged = %v, forced = %d", d.contextNudged, forced)
+	}
+	if nudges, _ := nudge.Drain(townRoot, name); len(nudges) != 1 {
+		t.Errorf("got %d nudges, want only the first", len(nudges))
+	}
+
+	// Going over again starts a new nudge cycle.
+	d.updateContextPressure(over, 0.8, forceAfter, t0.Add(40*time.Minute))
+	if _, ok := d.contextNudged[name]; !ok {
+		t.Error("successor over threshold was not nudged")
+	}
+}
+
+func TestUpdateContextPressure_FailedForceBacksOff(t *testing.T) {
+	d := testHandlerDaemon(t, t.TempDir())
+	forced := 0
+	d.contextForceFn = func(string, string, *ctxpressure.Reading) error {
+		forced++
+		return errors.New("rig not operational")
+	}
+	const forceAfter = 15 * time.Minute
+	over := []contextSession{{name: "gt-crew-max", identity: "gastown-crew-max", reading: &ctxpressure.Reading{Tokens: 190_000, Window: 200_000}}}
+	t0 := time.Now()
+
+	d.updateContextPressure(over, 0.8, forceAfter, t0)
+	for _, at := range []time.Duration{16 * time.Minute, 17 * time.Minute, 20 * time.Minute, 30 * time.Minute} {
+		d.updateContextPressure(over, 0.8, forceAfter, t0.Add(at))
+	}
+	if forced != 1 {
+		t.Fatalf("failed forced handoff attempted %d times within the backoff, want 1", forced)
+	}
+	d.updateContextPressure(over, 0.8, forceAfter, t0.Add(32*time.Minute))
+	if forced != 2 {
+		t.Errorf("forced handoff attempted %d times after the backoff, want 2", forced)
+	}
+
+	// A session that goes away drops its state.
+	d.updateContextPressure(nil, 0.8, forceAfter, t0.Add(33*time.Minute))
+	if len(d.contextNudged) != 0 {
+		t.Errorf("nudge state kept for a dead session: %v", d.contextNudged)
+	}
+}
@@ -3,7 +3,6 @@ package daemon
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -24,6 +23,7 @@ import (
 	"github.com/steveyegge/gastown/internal/boot"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/ctxpressure"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
@@ -100,6 +100,14 @@ type Daemon struct {
 	// lastMaintenanceRun tracks when scheduled maintenance last ran.
 	// Only accessed from heartbeat loop goroutine - no sync needed.
 	lastMaintenanceRun time.Time
+
+	// contextNudged tracks when sessions over the context-pressure threshold
+	// were nudged to hand off, keyed by session name.
+	// Only accessed from heartbeat loop goroutine - no sync needed.
+	contextNudged map[string]time.Time
+
+	// contextForceFn replaces forceContextHandoff in tests; nil = real.
+	contextForceFn func(sessionName, identity string, reading *ctxpressure.Reading) error
 }
 
 // sessionDeath records a detected session death for mass death analysis.
@@ -573,6 +581,7 @@ func (d *Daemon) recoveryHeartbeatInterval() time.Duration {
 // - Dead sessions that need restart
 // - Agents with work-on-hook not progressing (GUPP violation)
 // - Orphaned work (assigned to dead agents)
+// - Agents running out of context window (proactive handoff)
 func (d *Daemon) heartbeat(state *State) {
 	// Skip heartbeat if shutdown is in progress.
 	// This prevents the daemon from fighting shutdown by auto-restarting killed agents.
@@ -660,6 +669,10 @@ func (d *Daemon) heartbeat(state *State) {
 	// This validates tmux sessions are still alive for polecats with work-on-hook
 	d.checkPolecatSessionHealth()
 
+	// 12.5. Nudge agents near the end of their context window to hand off,
+	// and force a graceful handoff when the nudge is ignored too long.
+	d.checkContextPressure()
+
 	// 13. Clean up orphaned claude subagent processes (memory leak prevention)
 	// These are Task tool subagents that didn't clean up after completion.
 	// This is a safety net - Deacon patrol also does this more frequently.
@@ -679,6 +692,10 @@ func (d *Daemon) heartbeat(state *State) {
 	// daemon.log uses lumberjack for automatic rotation; this handles Dolt server logs.
 	d.rotateOversizedLogs()
 
+	// 16. Top up warm polecat pools (rigs with polecat_pool in settings).
+	// Fills run in the background since warmup commands can take minutes.
+	d.fillPolecatPools()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -857,6 +874,12 @@ func (d *Daemon) ensureBootRunning() {
 	d.logger.Println("Boot spawned successfully")
 }
 
+// backendFor returns the backend running sessionName: a PTY supervisor if
+// one answers for it, else the daemon's tmux client.
+func (d *Daemon) backendFor(sessionName string) session.Backend {
+	return session.BackendForWith(d.tmux, d.config.TownRoot, sessionName)
+}
+
 // runDegradedBootTriage performs mechanical Boot logic without AI reasoning.
 // This is for degraded mode when tmux is unavailable.
 func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
@@ -866,7 +889,8 @@ func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
 	}
 
 	// Simple check: is Deacon session alive?
-	hasDeacon, err := d.tmux.HasSession(d.getDeaconSessionName())
+	deaconSession := d.getDeaconSessionName()
+	hasDeacon, err := d.backendFor(deaconSession).HasSession(deaconSession)
 	if err != nil {
 		d.logger.Printf("Error checking Deacon session: %v", err)
 		status.LastAction = "error"
@@ -1018,7 +1042,8 @@ func (d *Daemon) checkDeaconHeartbeat() {
 	d.logger.Printf("Deacon heartbeat is stale (%s old), checking session...", age.Round(time.Minute))
 
 	// Check if session exists
-	hasSession, err := d.tmux.HasSession(sessionName)
+	t := d.backendFor(sessionName)
+	hasSession, err := t.HasSession(sessionName)
 	if err != nil {
 		d.logger.Printf("Error checking Deacon session: %v", err)
 		return
@@ -1038,7 +1063,7 @@ func (d *Daemon) checkDeaconHeartbeat() {
 	} else {
 		// Stuck but not critically - nudge to wake up
 		d.logger.Printf("Deacon stuck for %s - nudging session", age.Round(time.Minute))
-		if err := d.tmux.NudgeSession(sessionName, "HEALTH_CHECK: heartbeat stale, respond to confirm responsiveness"); err != nil {
+		if err := t.NudgeSession(sessionName, "HEALTH_CHECK: heartbeat stale, respond to confirm responsiveness"); err != nil {
 			d.logger.Printf("Error nudging stuck Deacon: %v", err)
 		}
 	}
@@ -1048,10 +1073,11 @@ func (d *Daemon) checkDeaconHeartbeat() {
 // Extracted for reuse by PATCH-005 grace period logic.
 func (d *Daemon) restartStuckDeacon(sessionName string) {
 	// Check if session exists before trying to kill
-	hasSession, _ := d.tmux.HasSession(sessionName)
+	t := d.backendFor(sessionName)
+	hasSession, _ := t.HasSession(sessionName)
 	if hasSession {
 		d.logger.Printf("Killing stuck Deacon session %s", sessionName)
-		if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
+		if err := t.KillSessionWithProcesses(sessionName); err != nil {
 			d.logger.Printf("Error killing stuck Deacon: %v", err)
 		}
 	}
@@ -1109,8 +1135,7 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 	// indicating Claude is stuck. Kill it so Start() can recreate a fresh one.
 	if status := mgr.IsHealthy(hungSessionThreshold); status == tmux.AgentHung {
 		d.logger.Printf("Witness for %s is hung (no activity for %v), killing for restart", rigName, hungSessionThreshold)
-		t := tmux.NewTmux()
-		_ = t.KillSession(mgr.SessionName())
+		_ = session.Kill(d.backendFor(mgr.SessionName()), mgr.SessionName())
 	}
 
 	if err := mgr.Start(false, "", nil); err != nil {
@@ -1178,8 +1203,7 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 	// can recreate a fresh one. See: gt-tr3d
 	if status := mgr.IsHealthy(hungSessionThreshold); status == tmux.AgentHung {
 		d.logger.Printf("Refinery for %s is hung (no activity for %v), killing for restart", rigName, hungSessionThreshold)
-		t := tmux.NewTmux()
-		_ = t.KillSession(mgr.SessionName())
+		_ = session.Kill(d.backendFor(mgr.SessionName()), mgr.SessionName())
 	}
 
 	if err := mgr.Start(false, ""); err != nil {
@@ -1219,10 +1243,11 @@ func (d *Daemon) ensureMayorRunning() {
 // running their own patrol loops and spawning agents. (hq-2mstj)
 func (d *Daemon) killDeaconSessions() {
 	for _, name := range []string{session.DeaconSessionName(), session.BootSessionName()} {
-		exists, _ := d.tmux.HasSession(name)
+		t := d.backendFor(name)
+		exists, _ := t.HasSession(name)
 		if exists {
 			d.logger.Printf("Killing leftover %s session (patrol disabled)", name)
-			if err := d.tmux.KillSessionWithProcesses(name); err != nil {
+			if err := t.KillSessionWithProcesses(name); err != nil {
 				d.logger.Printf("Error killing %s session: %v", name, err)
 			}
 		}
@@ -1234,10 +1259,11 @@ func (d *Daemon) killDeaconSessions() {
 func (d *Daemon) killWitnessSessions() {
 	for _, rigName := range d.getKnownRigs() {
 		name := session.WitnessSessionName(session.PrefixFor(rigName))
-		exists, _ := d.tmux.HasSession(name)
+		t := d.backendFor(name)
+		exists, _ := t.HasSession(name)
 		if exists {
 			d.logger.Printf("Killing leftover %s session (patrol disabled)", name)
-			if err := d.tmux.KillSessionWithProcesses(name); err != nil {
+			if err := t.KillSessionWithProcesses(name); err != nil {
 				d.logger.Printf("Error killing %s session: %v", name, err)
 			}
 		}
@@ -1249,10 +1275,11 @@ func (d *Daemon) killWitnessSessions() {
 func (d *Daemon) killRefinerySessions() {
 	for _, rigName := range d.getKnownRigs() {
 		name := session.RefinerySessionName(session.PrefixFor(rigName))
-		exists, _ := d.tmux.HasSession(name)
+		t := d.backendFor(name)
+		exists, _ := t.HasSession(name)
 		if exists {
 			d.logger.Printf("Killing leftover %s session (patrol disabled)", name)
-			if err := d.tmux.KillSessionWithProcesses(name); err != nil {
+			if err := t.KillSessionWithProcesses(name); err != nil {
 				d.logger.Printf("Error killing %s session: %v", name, err)
 			}
 		}
@@ -1431,6 +1458,7 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("Convoy manager stopped")
 	}
 	d.beadsStores = nil
+	beads.CloseStores() // pooled in-process read clients
 
 	// Stop KRC pruner
 	if d.krcPruner != nil {
@@ -1790,7 +1818,7 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
 
 	// Check if tmux session exists
-	sessionAlive, err := d.tmux.HasSession(sessionName)
+	sessionAlive, err := d.backendFor(sessionName).HasSession(sessionName)
 	if err != nil {
 		d.logger.Printf("Error checking session %s: %v", sessionName, err)
 		return
@@ -1849,7 +1877,7 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	// TOCTOU guard: re-verify session is still dead before restarting.
 	// Between the initial check and now, the session may have been restarted
 	// by another heartbeat cycle, witness, or the polecat itself.
-	sessionRevived, err := d.tmux.HasSession(sessionName)
+	sessionRevived, err := d.backendFor(sessionName).HasSession(sessionName)
 	if err == nil && sessionRevived {
 		return // Session came back - no restart needed
 	}
@@ -1977,12 +2005,20 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	startCmd := config.BuildStartupCommand(envVars, rigPath, "")
 
 	// Create session with command as initial process (replaces EnsureSessionFresh + SendKeys).
-	// EnsureSessionFreshWithCommand kills zombie sessions and creates a new one atomically.
-	if err := d.tmux.EnsureSessionFreshWithCommand(sessionName, workDir, startCmd); err != nil {
-		if errors.Is(err, tmux.ErrSessionRunning) {
+	// A zombie session (alive but agent dead) is killed first; a healthy one
+	// means the polecat was already restarted.
+	existing := d.backendFor(sessionName)
+	if running, _ := existing.HasSession(sessionName); running {
+		if existing.IsAgentAlive(sessionName) {
 			d.logger.Printf("Session %s already running with healthy agent, skipping restart", sessionName)
 			return nil
 		}
+		if err := existing.KillSessionWithProcesses(sessionName); err != nil {
+			return fmt.Errorf("killing zombie session: %w", err)
+		}
+	}
+	t := session.NewBackendWith(d.tmux, d.config.TownRoot)
+	if err := t.NewSessionWithCommand(sessionName, workDir, startCmd); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
@@ -1992,7 +2028,7 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	// Set environment variables in tmux session table (for debugging/monitoring tools).
 	// The process itself gets env vars via 'exec env ...' in the startup command.
 	for k, v := range envVars {
-		_ = d.tmux.SetEnvironment(sessionName, k, v)
+		_ = t.SetEnvironment(sessionName, k, v)
 	}
 
 	// Set GT_AGENT in tmux session env so tools querying tmux environment
@@ -2000,31 +2036,33 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	// BuildStartupCommand sets GT_AGENT in process env via exec env, but that
 	// isn't visible to tmux show-environment.
 	if rc.ResolvedAgent != "" {
-		_ = d.tmux.SetEnvironment(sessionName, "GT_AGENT", rc.ResolvedAgent)
+		_ = t.SetEnvironment(sessionName, "GT_AGENT", rc.ResolvedAgent)
 	}
 
 	// Set GT_PROCESS_NAMES for accurate liveness detection of custom agents.
 	processNames := config.ResolveProcessNames(rc.ResolvedAgent, rc.Command)
-	_ = d.tmux.SetEnvironment(sessionName, "GT_PROCESS_NAMES", strings.Join(processNames, ","))
+	_ = t.SetEnvironment(sessionName, "GT_PROCESS_NAMES", strings.Join(processNames, ","))
 
 	// Record agent's pane_id for ZFC-compliant liveness checks (gt-qmsx).
-	if paneID, err := d.tmux.GetPaneID(sessionName); err == nil {
-		_ = d.tmux.SetEnvironment(sessionName, "GT_PANE_ID", paneID)
+	if paneID, err := session.PaneID(t, sessionName); err == nil {
+		_ = t.SetEnvironment(sessionName, "GT_PANE_ID", paneID)
 	}
 
 	// Apply theme
 	theme := tmux.AssignTheme(rigName)
-	_ = d.tmux.ConfigureGasTownSession(sessionName, theme, rigName, polecatName, "polecat")
+	_ = session.ApplyTheme(t, sessionName, theme, rigName, polecatName, "polecat")
 
 	// Set pane-died hook for future crash detection
 	agentID := fmt.Sprintf("%s/%s", rigName, polecatName)
-	_ = d.tmux.SetPaneDiedHook(sessionName, agentID)
+	if tm, ok := t.(*tmux.Tmux); ok {
+		_ = tm.SetPaneDiedHook(sessionName, agentID)
+	}
 
 	// Wait for Claude to start, then accept startup dialogs if they appear.
-	if err := d.tmux.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+	if err := session.WaitForCommand(t, sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 		// Non-fatal - Claude might still start
 	}
-	_ = d.tmux.AcceptStartupDialogs(sessionName)
+	_ = session.AcceptStartupDialogs(t, sessionName)
 
 	return nil
 }
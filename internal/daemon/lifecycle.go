@@ -173,18 +173,23 @@ func (d *Daemon) executeLifecycleAction(request *LifecycleRequest) error {
 		}
 	}
 
-	// Check if session exists (tmux detection still needed for lifecycle actions)
-	running, err := d.tmux.HasSession(sessionName)
+	// Check if session exists in whichever backend runs it. A session that
+	// isn't running is restarted under the town's configured backend.
+	b := d.backendFor(sessionName)
+	running, err := b.HasSession(sessionName)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
+	if !running {
+		b = session.NewBackendWith(d.tmux, d.config.TownRoot)
+	}
 
 	switch request.Action {
 	case ActionShutdown:
 		if running {
 			// Use KillSessionWithProcesses to ensure all descendant processes are killed.
 			// This prevents orphan bash processes from Claude's Bash tool surviving session termination.
-			if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
+			if err := b.KillSessionWithProcesses(sessionName); err != nil {
 				return fmt.Errorf("killing session: %w", err)
 			}
 			d.logger.Printf("Killed session %s", sessionName)
@@ -194,7 +199,7 @@ func (d *Daemon) executeLifecycleAction(request *LifecycleRequest) error {
 	case ActionCycle, ActionRestart:
 		if running {
 			// Kill the session first - use KillSessionWithProcesses to prevent orphan processes.
-			if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
+			if err := b.KillSessionWithProcesses(sessionName); err != nil {
 				return fmt.Errorf("killing session: %w", err)
 			}
 			d.logger.Printf("Killed session %s for restart", sessionName)
@@ -204,7 +209,7 @@ func (d *Daemon) executeLifecycleAction(request *LifecycleRequest) error {
 		}
 
 		// Restart the session
-		if err := d.restartSession(sessionName, request.From); err != nil {
+		if err := d.restartSession(b, sessionName, request.From); err != nil {
 			return fmt.Errorf("restarting session: %w", err)
 		}
 		d.logger.Printf("Restarted session %s", sessionName)
@@ -334,9 +339,9 @@ func (d *Daemon) identityToSession(identity string) string {
 	}
 }
 
-// restartSession starts a new session for the given agent.
+// restartSession starts a new session for the given agent on backend b.
 // Uses role config if available, falls back to hardcoded defaults.
-func (d *Daemon) restartSession(sessionName, identity string) error {
+func (d *Daemon) restartSession(b session.Backend, sessionName, identity string) error {
 	// Get role config for this identity
 	config, parsed, err := d.getRoleConfigForIdentity(identity)
 	if err != nil {
@@ -375,25 +380,30 @@ func (d *Daemon) restartSession(sessionName, identity string) error {
 
 	// Create session with command as initial process (replaces EnsureSessionFresh + SendKeys).
 	// EnsureSessionFreshWithCommand kills zombie sessions and creates a new one atomically.
-	if err := d.tmux.EnsureSessionFreshWithCommand(sessionName, workDir, startCmd); err != nil {
-		if errors.Is(err, tmux.ErrSessionRunning) {
-			d.logger.Printf("Session %s already running with healthy agent, skipping restart", sessionName)
-			return nil
+	// Other backends have no zombie sessions: the caller already killed it.
+	if t, ok := b.(*tmux.Tmux); ok {
+		if err := t.EnsureSessionFreshWithCommand(sessionName, workDir, startCmd); err != nil {
+			if errors.Is(err, tmux.ErrSessionRunning) {
+				d.logger.Printf("Session %s already running with healthy agent, skipping restart", sessionName)
+				return nil
+			}
+			return fmt.Errorf("creating session: %w", err)
 		}
+	} else if err := b.NewSessionWithCommand(sessionName, workDir, startCmd); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
-	// Set environment variables in tmux session table (for debugging/monitoring tools).
-	d.setSessionEnvironment(sessionName, config, parsed)
+	// Set environment variables in the session table (for debugging/monitoring tools).
+	d.setSessionEnvironment(b, sessionName, config, parsed)
 
 	// Apply theme (non-fatal: theming failure doesn't affect operation)
-	d.applySessionTheme(sessionName, parsed)
+	d.applySessionTheme(b, sessionName, parsed)
 
 	// Wait for Claude to start, then accept startup dialogs if they appear.
-	if err := d.tmux.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+	if err := session.WaitForCommand(b, sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 		// Non-fatal - Claude might still start
 	}
-	_ = d.tmux.AcceptStartupDialogs(sessionName)
+	_ = session.AcceptStartupDialogs(b, sessionName)
 	time.Sleep(constants.ShutdownNotifyDelay)
 
 	return nil
@@ -525,7 +535,7 @@ func (d *Daemon) getStartCommand(roleConfig *beads.RoleConfig, parsed *ParsedIde
 
 // setSessionEnvironment sets environment variables for the tmux session.
 // Uses centralized AgentEnv for consistency, plus custom env vars from role config if available.
-func (d *Daemon) setSessionEnvironment(sessionName string, roleConfig *beads.RoleConfig, parsed *ParsedIdentity) {
+func (d *Daemon) setSessionEnvironment(b session.Backend, sessionName string, roleConfig *beads.RoleConfig, parsed *ParsedIdentity) {
 	// Use centralized AgentEnv for base environment variables
 	envVars := config.AgentEnv(config.AgentEnvConfig{
 		Role:        parsed.RoleType,
@@ -535,31 +545,32 @@ func (d *Daemon) setSessionEnvironment(sessionName string, roleConfig *beads.Rol
 		SessionName: sessionName,
 	})
 	for k, v := range envVars {
-		_ = d.tmux.SetEnvironment(sessionName, k, v)
+		_ = b.SetEnvironment(sessionName, k, v)
 	}
 
 	// Record agent's pane_id for ZFC-compliant liveness checks (gt-qmsx).
-	if paneID, err := d.tmux.GetPaneID(sessionName); err == nil {
-		_ = d.tmux.SetEnvironment(sessionName, "GT_PANE_ID", paneID)
+	if paneID, err := session.PaneID(b, sessionName); err == nil {
+		_ = b.SetEnvironment(sessionName, "GT_PANE_ID", paneID)
 	}
 
 	// Set any custom env vars from role config
 	if roleConfig != nil {
 		for k, v := range roleConfig.EnvVars {
 			expanded := beads.ExpandRolePattern(v, d.config.TownRoot, parsed.RigName, parsed.AgentName, parsed.RoleType, session.PrefixFor(parsed.RigName))
-			_ = d.tmux.SetEnvironment(sessionName, k, expanded)
+			_ = b.SetEnvironment(sessionName, k, expanded)
 		}
 	}
 }
 
-// applySessionTheme applies tmux theming to the session.
-func (d *Daemon) applySessionTheme(sessionName string, parsed *ParsedIdentity) {
+// applySessionTheme applies tmux theming to the session, on backends that
+// support it.
+func (d *Daemon) applySessionTheme(b session.Backend, sessionName string, parsed *ParsedIdentity) {
 	if parsed.RoleType == constants.RoleMayor {
 		theme := tmux.MayorTheme()
-		_ = d.tmux.ConfigureGasTownSession(sessionName, theme, "", "Mayor", "coordinator")
+		_ = session.ApplyTheme(b, sessionName, theme, "", "Mayor", "coordinator")
 	} else if parsed.RigName != "" {
 		theme := tmux.AssignTheme(parsed.RigName)
-		_ = d.tmux.ConfigureGasTownSession(sessionName, theme, parsed.RigName, parsed.RoleType, parsed.RoleType)
+		_ = session.ApplyTheme(b, sessionName, theme, parsed.RigName, parsed.RoleType, parsed.RoleType)
 	}
 }
 
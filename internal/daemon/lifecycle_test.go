@@ -7,8 +7,11 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/steveyegge/gastown/internal/pty"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // testDaemon creates a minimal Daemon for testing.
@@ -464,3 +467,36 @@ func TestSyncWorkspace_DirtyTreeAutoStash(t *testing.T) {
 		t.Errorf("expected 0 sync failures after successful sync, got %d", got)
 	}
 }
+
+func TestExecuteLifecycleAction_ShutdownPTYSession(t *testing.T) {
+	d, cleanup := testDaemonWithTown(t, "ai")
+	defer cleanup()
+	d.tmux = tmux.NewTmuxWithSocket("gt-test-none")
+
+	const name = "hq-mayor"
+	socket := pty.SocketPath(d.config.TownRoot, name)
+	if err := os.MkdirAll(filepath.Dir(socket), 0700); err != nil {
+		t.Fatal(err)
+	}
+	sup := &pty.Supervisor{Name: name, Dir: d.config.TownRoot, Command: "cat", Socket: socket}
+	go func() { _ = sup.Run() }()
+	b := pty.NewBackend(d.config.TownRoot)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if ok, _ := b.HasSession(name); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("PTY session never came up")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Cleanup(func() { _ = b.KillSessionWithProcesses(name) })
+
+	if err := d.executeLifecycleAction(&LifecycleRequest{From: "mayor", Action: ActionShutdown}); err != nil {
+		t.Fatalf("executeLifecycleAction: %v", err)
+	}
+	if ok, _ := b.HasSession(name); ok {
+		t.Error("PTY session still running after shutdown")
+	}
+}
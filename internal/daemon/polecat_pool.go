@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// poolFillTimeout bounds one background pool fill. Fills for a pool of N
+// polecats run up to N warmups, so this is generous; the pool-fill lock
+// keeps a slow fill from piling up behind the next heartbeat.
+const poolFillTimeout = 2 * time.Hour
+
+// fillPolecatPools tops up the warm polecat pool of every operational rig
+// that configures one (polecat_pool in settings/config.json). Warmups can
+// take minutes, so each fill runs in the background via gt polecat
+// pool-fill; a fill still running from an earlier heartbeat makes the new
+// one a no-op.
+func (d *Daemon) fillPolecatPools() {
+	for _, rigName := range d.getKnownRigs() {
+		rigPath := filepath.Join(d.config.TownRoot, rigName)
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+		if err != nil || !settings.PolecatPool.Enabled() {
+			continue
+		}
+		if ok, reason := d.isRigOperational(rigName); !ok {
+			d.logger.Printf("Skipping polecat pool fill for %s: %s", rigName, reason)
+			continue
+		}
+		go d.fillPolecatPool(rigName)
+	}
+}
+
+// fillPolecatPool runs one pool fill for a rig and logs the outcome.
+func (d *Daemon) fillPolecatPool(rigName string) {
+	ctx, cancel := context.WithTimeout(d.ctx, poolFillTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, "polecat", "pool-fill", rigName) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = append(os.Environ(), "GT_DAEMON=1")
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		d.logger.Printf("Polecat pool fill for %s timed out after %v", rigName, poolFillTimeout)
+	case err != nil:
+		d.logger.Printf("Polecat pool fill for %s failed: %v (output: %s)", rigName, err, output)
+	case output != "":
+		d.logger.Printf("Polecat pool fill for %s: %s", rigName, output)
+	}
+}
@@ -20,29 +20,10 @@ var (
 	ErrAlreadyRunning = errors.New("deacon already running")
 )
 
-// tmuxOps abstracts tmux operations for testing.
-type tmuxOps interface {
-	HasSession(name string) (bool, error)
-	IsAgentAlive(session string) bool
-	KillSessionWithProcesses(name string) error
-	NewSessionWithCommand(name, workDir, command string) error
-	SetRemainOnExit(pane string, on bool) error
-	SetEnvironment(session, key, value string) error
-	GetPaneID(session string) (string, error)
-	ConfigureGasTownSession(session string, theme tmux.Theme, rig, worker, role string) error
-	WaitForCommand(session string, excludeCommands []string, timeout time.Duration) error
-	SetAutoRespawnHook(session string) error
-	AcceptStartupDialogs(session string) error
-	AcceptWorkspaceTrustDialog(session string) error
-	AcceptBypassPermissionsWarning(session string) error
-	SendKeysRaw(session, keys string) error
-	GetSessionInfo(name string) (*tmux.SessionInfo, error)
-}
-
 // Manager handles deacon lifecycle operations.
 type Manager struct {
 	townRoot string
-	tmux     tmuxOps
+	tmux     session.Backend
 }
 
 // NewManager creates a new deacon manager for a town.
@@ -64,6 +45,20 @@ func (m *Manager) SessionName() string {
 	return SessionName()
 }
 
+// backend returns the backend running the deacon session, or the town's
+// configured backend when the session doesn't exist yet. A non-tmux
+// m.tmux (a test fake) is used as-is.
+func (m *Manager) backend(existing bool) session.Backend {
+	tm, ok := m.tmux.(*tmux.Tmux)
+	if !ok {
+		return m.tmux
+	}
+	if existing {
+		return session.BackendForWith(tm, m.townRoot, m.SessionName())
+	}
+	return session.NewBackendWith(tm, m.townRoot)
+}
+
 // deaconDir returns the working directory for the deacon.
 func (m *Manager) deaconDir() string {
 	return filepath.Join(m.townRoot, "deacon")
@@ -73,7 +68,7 @@ func (m *Manager) deaconDir() string {
 // agentOverride allows specifying an alternate agent alias (e.g., for testing).
 // Restarts are handled by daemon via ensureDeaconRunning on each heartbeat.
 func (m *Manager) Start(agentOverride string) error {
-	t := m.tmux
+	t := m.backend(true)
 	sessionID := m.SessionName()
 
 	// Check if session already exists
@@ -123,6 +118,7 @@ func (m *Manager) Start(agentOverride string) error {
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
+	t = m.backend(false)
 	if err := t.NewSessionWithCommand(sessionID, deaconDir, startupCmd); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
@@ -130,7 +126,7 @@ func (m *Manager) Start(agentOverride string) error {
 	// PATCH-010: Set remain-on-exit IMMEDIATELY after session creation.
 	// This ensures the pane stays if Claude exits before hooks are fully set.
 	// The pane will show "[Exited]" status but remain available for respawn.
-	_ = t.SetRemainOnExit(sessionID, true)
+	_ = session.SetRemainOnExit(t, sessionID, true)
 
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
@@ -146,38 +142,36 @@ func (m *Manager) Start(agentOverride string) error {
 	}
 
 	// Record agent's pane_id for ZFC-compliant liveness checks (gt-qmsx).
-	if paneID, err := t.GetPaneID(sessionID); err == nil {
+	if paneID, err := session.PaneID(t, sessionID); err == nil {
 		_ = t.SetEnvironment(sessionID, "GT_PANE_ID", paneID)
 	}
 
 	// Apply Deacon theming (non-fatal: theming failure doesn't affect operation)
 	theme := tmux.DeaconTheme()
-	_ = t.ConfigureGasTownSession(sessionID, theme, "", "Deacon", "health-check")
+	_ = session.ApplyTheme(t, sessionID, theme, "", "Deacon", "health-check")
 
 	// Wait for Claude to start - fatal if Claude fails to launch
-	if err := t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+	if err := session.WaitForCommand(t, sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 		// Kill the zombie session before returning error
 		_ = t.KillSessionWithProcesses(sessionID)
 		return fmt.Errorf("waiting for deacon to start: %w", err)
 	}
 
 	// Track PID for defense-in-depth orphan cleanup (non-fatal)
-	if realTmux, ok := t.(*tmux.Tmux); ok {
-		_ = session.TrackSessionPID(m.townRoot, sessionID, realTmux)
-	}
+	_ = session.TrackSessionPID(m.townRoot, sessionID, t)
 
 	// PATCH-010: Set auto-respawn hook for Deacon resilience.
 	// When Claude exits (for any reason), tmux will automatically respawn it.
 	// This prevents the crash loop where daemon repeatedly restarts Deacon.
 	// Note: SetAutoRespawnHook calls SetRemainOnExit again (harmless, already set above).
-	if err := t.SetAutoRespawnHook(sessionID); err != nil {
+	if err := session.SetAutoRespawnHook(t, sessionID); err != nil {
 		// Non-fatal: Deacon still works, just won't auto-respawn on crash
 		// Daemon will still restart it, but with a delay
 		fmt.Printf("warning: failed to set auto-respawn hook for deacon: %v\n", err)
 	}
 
 	// Accept startup dialogs (workspace trust + bypass permissions) if they appear.
-	_ = t.AcceptStartupDialogs(sessionID)
+	_ = session.AcceptStartupDialogs(t, sessionID)
 
 	time.Sleep(constants.ShutdownNotifyDelay)
 
@@ -186,7 +180,7 @@ func (m *Manager) Start(agentOverride string) error {
 
 // Stop stops the deacon session.
 func (m *Manager) Stop() error {
-	t := m.tmux
+	t := m.backend(true)
 	sessionID := m.SessionName()
 
 	// Check if session exists
@@ -214,12 +208,12 @@ func (m *Manager) Stop() error {
 
 // IsRunning checks if the deacon session is active.
 func (m *Manager) IsRunning() (bool, error) {
-	return m.tmux.HasSession(m.SessionName())
+	return m.backend(true).HasSession(m.SessionName())
 }
 
 // Status returns information about the deacon session.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := m.tmux
+	t := m.backend(true)
 	sessionID := m.SessionName()
 
 	running, err := t.HasSession(sessionID)
@@ -230,5 +224,5 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	return session.Info(t, sessionID)
 }
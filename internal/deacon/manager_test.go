@@ -9,7 +9,8 @@ import (
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
-// mockTmux implements tmuxOps for testing.
+// mockTmux implements session.Backend, plus the tmux-only steps Start
+// uses, for testing.
 type mockTmux struct {
 	hasSessionResult bool
 	hasSessionErr    error
@@ -64,6 +65,13 @@ func (m *mockTmux) GetSessionInfo(_ string) (*tmux.SessionInfo, error) {
 	return m.sessionInfo, m.sessionInfoErr
 }
 
+func (m *mockTmux) ListSessions() ([]string, error)            { return nil, nil }
+func (m *mockTmux) GetEnvironment(_, _ string) (string, error) { return "", nil }
+func (m *mockTmux) NudgeSession(_, _ string) error             { return nil }
+func (m *mockTmux) CapturePane(_ string, _ int) (string, error) {
+	return "", nil
+}
+
 func newTestManager(townRoot string, mock *mockTmux) *Manager {
 	return &Manager{
 		townRoot: townRoot,
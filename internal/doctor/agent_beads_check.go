@@ -31,6 +31,7 @@ func NewAgentBeadsCheck() *AgentBeadsCheck {
 				CheckName:        "agent-beads-exist",
 				CheckDescription: "Verify agent beads exist for all agents",
 				CheckCategory:    CategoryRig,
+				CheckDependsOn:   beadsPrerequisites,
 			},
 		},
 	}
@@ -328,6 +328,7 @@ func NewRoleLabelCheck() *RoleLabelCheck {
 				CheckName:        "role-bead-labels",
 				CheckDescription: "Check that role beads have gt:role label",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   beadsPrerequisites,
 			},
 		},
 		labelAdder: &realLabelAdder{},
@@ -448,6 +449,7 @@ func NewDatabasePrefixCheck() *DatabasePrefixCheck {
 				CheckName:        "database-prefix",
 				CheckDescription: "Check rig database issue_prefix matches routes.jsonl",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   beadsPrerequisites,
 			},
 		},
 	}
@@ -617,6 +617,7 @@ func NewCustomTypesCheck() *CustomTypesCheck {
 				CheckName:        "beads-custom-types",
 				CheckDescription: "Check that Gas Town custom types are registered with beads",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   beadsPrerequisites,
 			},
 		},
 	}
@@ -742,6 +743,7 @@ func NewCustomStatusesCheck() *CustomStatusesCheck {
 				CheckName:        "beads-custom-statuses",
 				CheckDescription: "Check that Gas Town custom statuses are registered with beads",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   beadsPrerequisites,
 			},
 		},
 	}
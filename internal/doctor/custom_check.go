@@ -0,0 +1,321 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// CustomCheck runs a user-defined check declared in the doctor_checks list of
+// town or rig settings.
+type CustomCheck struct {
+	FixableCheck
+	cfg     config.DoctorCheckConfig
+	rig     string // empty for town checks
+	root    string // directory cfg.Dir is relative to
+	invalid error  // configuration problem reported instead of running
+
+	mu         sync.Mutex
+	failedDirs []string // directories that failed the last run; guarded by mu
+}
+
+// NewCustomCheck creates a check from its settings entry. root is the town
+// root for town checks and the rig root for rig checks.
+func NewCustomCheck(cfg config.DoctorCheckConfig, rig, root string) *CustomCheck {
+	name := cfg.Name
+	if name == "" {
+		name = "unnamed-check"
+	}
+	if rig != "" {
+		name = rig + "/" + name
+	}
+	desc := cfg.Description
+	if desc == "" {
+		desc = "Custom check: " + cfg.Command
+	}
+	category := cfg.Category
+	if category == "" {
+		category = CategoryCustom
+	}
+	return &CustomCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        name,
+				CheckDescription: desc,
+				CheckCategory:    category,
+				CheckDependsOn:   cfg.DependsOn,
+			},
+		},
+		cfg:     cfg,
+		rig:     rig,
+		root:    root,
+		invalid: cfg.Validate(),
+	}
+}
+
+// CanFix returns true if the check declares a fix command.
+func (c *CustomCheck) CanFix() bool {
+	return c.invalid == nil && c.cfg.Fix != ""
+}
+
+// Run executes the check command in each of its directories.
+func (c *CustomCheck) Run(ctx *CheckContext) *CheckResult {
+	if c.invalid != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: c.invalid.Error(),
+			FixHint: "Edit doctor_checks in " + c.settingsPath(),
+		}
+	}
+
+	c.setFailedDirs(nil)
+	dirs, err := c.dirs()
+	if err != nil {
+		return c.failure(err.Error(), nil)
+	}
+	if len(dirs) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("No directories match %s", c.cfg.Dir),
+		}
+	}
+
+	var failed, details []string
+	for _, dir := range dirs {
+		if problem := c.runIn(ctx, dir); problem != "" {
+			failed = append(failed, dir)
+			details = append(details, c.relDir(dir)+": "+problem)
+		}
+	}
+	c.setFailedDirs(failed)
+
+	if len(failed) == 0 {
+		msg := "Passed"
+		if len(dirs) > 1 {
+			msg = fmt.Sprintf("Passed in %d directories", len(dirs))
+		}
+		return &CheckResult{Name: c.Name(), Status: StatusOK, Message: msg}
+	}
+	if len(dirs) == 1 {
+		return c.failure(strings.TrimPrefix(details[0], c.relDir(dirs[0])+": "), nil)
+	}
+	return c.failure(fmt.Sprintf("Failed in %d of %d directories", len(failed), len(dirs)), details)
+}
+
+// Fix runs the fix command in every directory that failed the last run.
+func (c *CustomCheck) Fix(ctx *CheckContext) error {
+	c.mu.Lock()
+	failed := c.failedDirs
+	c.mu.Unlock()
+
+	var errs []error
+	for _, dir := range failed {
+		out, err := c.shell(ctx, dir, c.cfg.Fix)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v%s", c.relDir(dir), err, lastLine(out)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *CustomCheck) setFailedDirs(dirs []string) {
+	c.mu.Lock()
+	c.failedDirs = dirs
+	c.mu.Unlock()
+}
+
+// runIn runs the check command in dir and describes the failure, if any.
+func (c *CustomCheck) runIn(ctx *CheckContext, dir string) string {
+	out, err := c.shell(ctx, dir, c.cfg.Command)
+	exit := 0
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() < 0 {
+			return err.Error() + lastLine(out)
+		}
+		exit = exitErr.ExitCode()
+	}
+	if want := c.cfg.ExpectExitV(); exit != want {
+		return fmt.Sprintf("exit %d, want %d%s", exit, want, lastLine(out))
+	}
+	if c.cfg.ExpectOutput != "" && !regexp.MustCompile(c.cfg.ExpectOutput).Match(out) {
+		return fmt.Sprintf("output does not match %q%s", c.cfg.ExpectOutput, lastLine(out))
+	}
+	return ""
+}
+
+// shell runs command through sh -c in dir with the check's timeout.
+func (c *CustomCheck) shell(ctx *CheckContext, dir, command string) ([]byte, error) {
+	runCtx, cancel := context.WithTimeout(context.Background(), c.cfg.TimeoutD())
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, "sh", "-c", command)
+	util.SetProcessGroup(cmd) // a timeout kills the whole command tree
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GT_TOWN_ROOT="+ctx.TownRoot)
+	if c.rig != "" {
+		cmd.Env = append(cmd.Env, "GT_RIG="+c.rig)
+	}
+	out, err := cmd.CombinedOutput()
+	if runCtx.Err() == context.DeadlineExceeded {
+		return out, fmt.Errorf("timed out after %s", c.cfg.TimeoutD())
+	}
+	return out, err
+}
+
+// dirs resolves the directories the check runs in. A glob may match nothing;
+// a literal directory must exist.
+func (c *CustomCheck) dirs() ([]string, error) {
+	if c.cfg.Dir == "" {
+		return []string{c.root}, nil
+	}
+	pattern := c.cfg.Dir
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(c.root, pattern)
+	}
+	if !strings.ContainsAny(c.cfg.Dir, "*?[") {
+		if info, err := os.Stat(pattern); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("directory %s not found", c.cfg.Dir)
+		}
+		return []string{pattern}, nil
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dir glob %q: %v", c.cfg.Dir, err)
+	}
+	var dirs []string
+	for _, m := range matches {
+		if info, err := os.Stat(m); err == nil && info.IsDir() {
+			dirs = append(dirs, m)
+		}
+	}
+	return dirs, nil
+}
+
+// failure builds a failing result at the configured severity.
+func (c *CustomCheck) failure(msg string, details []string) *CheckResult {
+	status := StatusError
+	if c.cfg.Severity == config.DoctorSeverityWarning {
+		status = StatusWarning
+	}
+	result := &CheckResult{
+		Name:    c.Name(),
+		Status:  status,
+		Message: msg,
+		Details: details,
+	}
+	if c.CanFix() {
+		result.FixHint = "Run 'gt doctor --fix' to run: " + c.cfg.Fix
+	}
+	return result
+}
+
+func (c *CustomCheck) relDir(dir string) string {
+	if rel, err := filepath.Rel(c.root, dir); err == nil {
+		return rel
+	}
+	return dir
+}
+
+func (c *CustomCheck) settingsPath() string {
+	if c.rig != "" {
+		return config.RigSettingsPath(c.root)
+	}
+	return config.TownSettingsPath(c.root)
+}
+
+// lastLine returns the last non-empty line of command output, formatted as
+// a suffix for a failure message.
+func lastLine(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if line := strings.TrimSpace(lines[len(lines)-1]); line != "" {
+		return ": " + line
+	}
+	return ""
+}
+
+// CustomChecks loads the user-defined checks from town settings and from the
+// settings of rigName, or of every registered rig when rigName is empty.
+// Settings that can't be loaded, and invalid or duplicate entries, are
+// returned as checks that report the problem.
+func CustomChecks(townRoot, rigName string) []Check {
+	var checks []Check
+
+	townPath := config.TownSettingsPath(townRoot)
+	if town, err := config.LoadOrCreateTownSettings(townPath); err != nil {
+		checks = append(checks, settingsErrorCheck("custom-checks", townRoot, "", err))
+	} else {
+		checks = append(checks, customChecksFor(town.DoctorChecks, "", townRoot)...)
+	}
+
+	rigs := []string{rigName}
+	if rigName == "" {
+		rigs, _ = discoverRigs(townRoot)
+		sort.Strings(rigs)
+	}
+	for _, rig := range rigs {
+		rigPath := filepath.Join(townRoot, rig)
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+		if err != nil {
+			if !errors.Is(err, config.ErrNotFound) {
+				checks = append(checks, settingsErrorCheck(rig+"/custom-checks", rigPath, rig, err))
+			}
+			continue
+		}
+		checks = append(checks, customChecksFor(settings.DoctorChecks, rig, rigPath)...)
+	}
+	return checks
+}
+
+// customChecksFor builds the checks of one settings file. Dependencies on
+// checks of the same rig are qualified with the rig name.
+func customChecksFor(cfgs []config.DoctorCheckConfig, rig, root string) []Check {
+	local := make(map[string]bool, len(cfgs))
+	for _, cfg := range cfgs {
+		local[cfg.Name] = true
+	}
+
+	var checks []Check
+	seen := make(map[string]bool, len(cfgs))
+	for _, cfg := range cfgs {
+		if rig != "" && len(cfg.DependsOn) > 0 {
+			deps := make([]string, len(cfg.DependsOn))
+			for i, dep := range cfg.DependsOn {
+				if local[dep] {
+					dep = rig + "/" + dep
+				}
+				deps[i] = dep
+			}
+			cfg.DependsOn = deps
+		}
+		check := NewCustomCheck(cfg, rig, root)
+		if cfg.Name != "" && seen[cfg.Name] && check.invalid == nil {
+			check.invalid = fmt.Errorf("%w %q: duplicate name", config.ErrInvalidDoctorCheck, cfg.Name)
+		}
+		seen[cfg.Name] = true
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// settingsErrorCheck reports settings that could not be loaded.
+func settingsErrorCheck(name, root, rig string, err error) Check {
+	check := NewCustomCheck(config.DoctorCheckConfig{Name: name}, "", root)
+	check.CheckName = name
+	check.CheckDescription = "Load user-defined doctor checks"
+	check.rig = rig
+	check.invalid = fmt.Errorf("loading settings: %w", err)
+	return check
+}
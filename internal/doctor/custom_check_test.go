@@ -0,0 +1,139 @@
+package doctor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestCustomCheck_ExitCodeAndOutput(t *testing.T) {
+	root := t.TempDir()
+	ctx := &CheckContext{TownRoot: root}
+	two := 2
+
+	tests := []struct {
+		name   string
+		cfg    config.DoctorCheckConfig
+		status CheckStatus
+		msg    string
+	}{
+		{"pass", config.DoctorCheckConfig{Command: "true"}, StatusOK, "Passed"},
+		{"fail", config.DoctorCheckConfig{Command: "echo boom; exit 1"}, StatusError, "exit 1, want 0: boom"},
+		{"expected exit", config.DoctorCheckConfig{Command: "exit 2", ExpectExit: &two}, StatusOK, "Passed"},
+		{"warning", config.DoctorCheckConfig{Command: "false", Severity: "warning"}, StatusWarning, "exit 1"},
+		{"output match", config.DoctorCheckConfig{Command: "echo go1.25.6", ExpectOutput: `^go1\.25`}, StatusOK, "Passed"},
+		{"output mismatch", config.DoctorCheckConfig{Command: "echo go1.24.0", ExpectOutput: `^go1\.25`}, StatusError, "output does not match"},
+		{"env", config.DoctorCheckConfig{Command: `test "$GT_TOWN_ROOT" = "` + root + `"`}, StatusOK, "Passed"},
+		{"invalid", config.DoctorCheckConfig{Command: "true", Severity: "fatal"}, StatusError, "severity"},
+		{"missing dir", config.DoctorCheckConfig{Command: "true", Dir: "nope"}, StatusError, "directory nope not found"},
+		{"timeout", config.DoctorCheckConfig{Command: "sleep 5", Timeout: "50ms"}, StatusError, "timed out"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Name = "c"
+			result := NewCustomCheck(tt.cfg, "", root).Run(ctx)
+			if result.Status != tt.status || !strings.Contains(result.Message, tt.msg) {
+				t.Errorf("Run() = %v %q, want %v containing %q", result.Status, result.Message, tt.status, tt.msg)
+			}
+		})
+	}
+}
+
+func TestCustomCheck_GlobDirsAndFix(t *testing.T) {
+	rigPath := t.TempDir()
+	for _, p := range []string{"polecats/a/rig", "polecats/b/rig", "polecats/c/rig"} {
+		if err := os.MkdirAll(filepath.Join(rigPath, p), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(rigPath, "polecats/a/rig/hook"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewCustomCheck(config.DoctorCheckConfig{
+		Name:    "hook-installed",
+		Command: "test -f hook",
+		Dir:     "polecats/*/rig",
+		Fix:     "touch hook",
+	}, "gastown", rigPath)
+	if check.Name() != "gastown/hook-installed" || check.Category() != CategoryCustom || !check.CanFix() {
+		t.Fatalf("check = %s %s fixable=%v", check.Name(), check.Category(), check.CanFix())
+	}
+
+	ctx := &CheckContext{TownRoot: filepath.Dir(rigPath)}
+	result := check.Run(ctx)
+	if result.Status != StatusError || result.Message != "Failed in 2 of 3 directories" || len(result.Details) != 2 {
+		t.Fatalf("Run() = %+v", result)
+	}
+	if !strings.HasPrefix(result.Details[0], filepath.Join("polecats", "b", "rig")+": ") {
+		t.Errorf("Details = %v", result.Details)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix() error: %v", err)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK || result.Message != "Passed in 3 directories" {
+		t.Errorf("after fix Run() = %+v", result)
+	}
+}
+
+func TestCustomChecks_LoadsTownAndRigSettings(t *testing.T) {
+	townRoot := t.TempDir()
+	writeJSON := func(path string, v any) {
+		t.Helper()
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeJSON(filepath.Join(townRoot, "mayor", "rigs.json"), map[string]any{
+		"version": 1,
+		"rigs":    map[string]any{"gastown": map[string]any{}, "beads": map[string]any{}},
+	})
+	writeJSON(config.TownSettingsPath(townRoot), map[string]any{
+		"type":          "town-settings",
+		"doctor_checks": []map[string]any{{"name": "disk", "command": "true", "category": "Ops"}},
+	})
+	writeJSON(config.RigSettingsPath(filepath.Join(townRoot, "gastown")), map[string]any{
+		"type": "rig-settings",
+		"doctor_checks": []map[string]any{
+			{"name": "toolchain", "command": "true"},
+			{"name": "lint", "command": "true", "depends_on": []string{"toolchain", "beads-binary"}},
+			{"name": "lint", "command": "true"},
+		},
+	})
+
+	checks := CustomChecks(townRoot, "")
+	var names []string
+	for _, c := range checks {
+		names = append(names, c.Name())
+	}
+	if got := strings.Join(names, ","); got != "disk,gastown/toolchain,gastown/lint,gastown/lint" {
+		t.Fatalf("checks = %s", got)
+	}
+	if got := checks[0].(*CustomCheck).Category(); got != "Ops" {
+		t.Errorf("category = %q, want Ops", got)
+	}
+	if got := strings.Join(checks[2].(*CustomCheck).DependsOn(), ","); got != "gastown/toolchain,beads-binary" {
+		t.Errorf("depends_on = %s", got)
+	}
+	if result := checks[3].Run(&CheckContext{TownRoot: townRoot}); result.Status != StatusError || !strings.Contains(result.Message, "duplicate") {
+		t.Errorf("duplicate = %+v", result)
+	}
+
+	// --rig limits loading to that rig's settings.
+	if checks := CustomChecks(townRoot, "beads"); len(checks) != 1 {
+		t.Errorf("CustomChecks(beads) = %d checks, want only the town check", len(checks))
+	}
+}
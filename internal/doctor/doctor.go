@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/ui"
@@ -12,12 +13,14 @@ import (
 // Doctor manages and executes health checks.
 type Doctor struct {
 	checks []Check
+	jobs   int
 }
 
 // NewDoctor creates a new Doctor with no registered checks.
 func NewDoctor() *Doctor {
 	return &Doctor{
 		checks: make([]Check, 0),
+		jobs:   DefaultJobs,
 	}
 }
 
@@ -36,6 +39,12 @@ func (d *Doctor) Checks() []Check {
 	return d.checks
 }
 
+// SetJobs sets how many checks may run concurrently.
+// Values below 1 run checks one at a time.
+func (d *Doctor) SetJobs(n int) {
+	d.jobs = n
+}
+
 // categoryGetter interface for checks that provide a category
 type categoryGetter interface {
 	Category() string
@@ -47,62 +56,13 @@ func (d *Doctor) Run(ctx *CheckContext) *Report {
 }
 
 // RunStreaming executes all registered checks with optional real-time output.
-// If w is non-nil, prints each check name as it starts and result when done.
+// Checks run concurrently (see SetJobs), each starting once its prerequisites
+// have finished; a check whose prerequisite failed is skipped.
+// If w is non-nil, results are printed in dependency order (registration
+// order for independent checks) as they become available.
 // If slowThreshold > 0, shows hourglass icon for slow checks.
 func (d *Doctor) RunStreaming(ctx *CheckContext, w io.Writer, slowThreshold time.Duration) *Report {
-	report := NewReport()
-
-	for _, check := range d.checks {
-		// Stream: print check name before running
-		if w != nil {
-			fmt.Fprintf(w, "  %s  %s...", ui.RenderMuted("○"), check.Name())
-		}
-
-		start := time.Now()
-		result := check.Run(ctx)
-		result.Elapsed = time.Since(start)
-
-		// Ensure check name is populated
-		if result.Name == "" {
-			result.Name = check.Name()
-		}
-		// Set category from check if available
-		if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-			result.Category = cg.Category()
-		}
-
-		// Stream: overwrite line with result
-		if w != nil {
-			var statusIcon string
-			switch result.Status {
-			case StatusOK:
-				statusIcon = ui.RenderPassIcon()
-			case StatusWarning:
-				statusIcon = ui.RenderWarnIcon()
-			case StatusError:
-				statusIcon = ui.RenderFailIcon()
-			}
-			// Check if slow (hourglass replaces spaces to maintain alignment)
-			isSlow := slowThreshold > 0 && result.Elapsed >= slowThreshold
-			slowIndicator := "  "
-			if isSlow {
-				report.Summary.Slow++
-				slowIndicator = "⏳"
-			}
-			fmt.Fprintf(w, "\r  %s%s%s", statusIcon, slowIndicator, result.Name)
-			if result.Message != "" {
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
-			}
-			if isSlow {
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" ("+formatDuration(result.Elapsed)+")"))
-			}
-			fmt.Fprintln(w)
-		}
-
-		report.Add(result)
-	}
-
-	return report
+	return d.execute(ctx, w, slowThreshold, false)
 }
 
 // Fix runs all checks with auto-fix enabled where possible.
@@ -124,121 +84,148 @@ func safeFixCheck(check Check, ctx *CheckContext) (retErr error) {
 }
 
 // FixStreaming runs all checks with auto-fix and optional real-time output.
+// Checks run concurrently (up to --jobs) until the first fix is attempted;
+// from then on every later check is re-run inline, one at a time in
+// dependency order, so a check never observes a fix half-applied.
 // If w is non-nil, prints each check name as it starts and result when done.
 // If slowThreshold > 0, shows hourglass icon for slow checks.
 func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time.Duration) *Report {
+	return d.execute(ctx, w, slowThreshold, true)
+}
+
+// execute runs the check graph and collects final results in dependency
+// order, streaming each to w. Every check first runs concurrently in the
+// background and this goroutine waits for results in order. With fix, a fix
+// may change what any later check reads, dependent or not, so once one has
+// been attempted the background pass is stopped and each later check runs
+// again inline. A healthy town therefore fixes as fast as it checks; the
+// serial tail only costs time when something actually needed fixing.
+func (d *Doctor) execute(ctx *CheckContext, w io.Writer, slowThreshold time.Duration, fix bool) *Report {
 	report := NewReport()
+	g := newCheckGraph(d.checks)
+	stop := make(chan struct{})
+	runs := g.runAll(ctx, d.jobs, stop)
 
-	for _, check := range d.checks {
-		// Stream: print check name before running
+	final := make([]*CheckResult, len(d.checks))
+	fixed := false // a fix has been attempted; background results are stale
+	for _, i := range g.order {
+		check := d.checks[i]
+
+		// Stream: print check name while waiting for its result
 		if w != nil {
 			fmt.Fprintf(w, "  %s  %s...", ui.RenderMuted("○"), check.Name())
 		}
 
-		start := time.Now()
-		result := check.Run(ctx)
-		if result.Name == "" {
-			result.Name = check.Name()
-		}
-		// Set category from check if available
-		if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-			result.Category = cg.Category()
-		}
-
-		// Attempt fix if check failed and is fixable
-		if result.Status != StatusOK && check.CanFix() {
-			// Stream: show the problem with fixing indicator (all on same line)
-			if w != nil {
-				var problemIcon string
-				if result.Status == StatusError {
-					problemIcon = ui.RenderFailIcon()
-				} else {
-					problemIcon = ui.RenderWarnIcon()
-				}
-				// Overwrite the "checking" line with problem status + fixing indicator
-				fmt.Fprintf(w, "\r  %s  %s", problemIcon, check.Name())
-				if result.Message != "" {
-					fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
-				}
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" (fixing)..."))
+		<-runs[i].done
+		result := runs[i].result
+		if fixed {
+			if result = skippedResult(check, g.prerequisites(i, final)); result == nil {
+				result = runCheck(check, ctx)
 			}
-
-			err := safeFixCheck(check, ctx)
-			if err == nil {
-				// Re-run check to verify fix worked
-				result = check.Run(ctx)
-				if result.Name == "" {
-					result.Name = check.Name()
-				}
-				// Set category again after re-run
-				if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-					result.Category = cg.Category()
-				}
-				// Update message to indicate fix was applied
-				if result.Status == StatusOK {
-					result.Message = result.Message + " (fixed)"
-					result.Fixed = true
-				}
-			} else if errors.Is(err, ErrSkippedNoStart) {
-				// Fix skipped due to --no-start flag
-				result.Details = append(result.Details, "Skipped: --no-start suppresses startup")
-			} else {
-				// Fix failed, add error to details
-				result.Details = append(result.Details, "Fix failed: "+err.Error())
+		}
+		if fix && result.Status != StatusOK && !result.Skipped && check.CanFix() {
+			if !fixed {
+				fixed = true
+				close(stop)
 			}
+			result = d.fixCheck(ctx, check, result, w)
 		}
 
-		// Record total elapsed time including any fix attempts
-		result.Elapsed = time.Since(start)
-
-		// Stream: overwrite line with final result
+		final[i] = result
 		if w != nil {
-			var statusIcon string
-			if result.Fixed {
-				statusIcon = ui.RenderFixIcon()
-			} else {
-				switch result.Status {
-				case StatusOK:
-					statusIcon = ui.RenderPassIcon()
-				case StatusWarning:
-					statusIcon = ui.RenderWarnIcon()
-				case StatusError:
-					statusIcon = ui.RenderFailIcon()
-				}
-			}
-			// Check if slow (hourglass replaces spaces to maintain alignment)
-			// Fix icon (🔧) is double-width, so use one less padding space
-			isSlow := slowThreshold > 0 && result.Elapsed >= slowThreshold
-			slowIndicator := "  "
-			if result.Fixed {
-				slowIndicator = " "
-			}
-			if isSlow {
-				report.Summary.Slow++
-				slowIndicator = "⏳"
-			}
-			fmt.Fprintf(w, "\r  %s%s%s", statusIcon, slowIndicator, result.Name)
-			if result.Message != "" {
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
-			}
-			if isSlow {
-				fmt.Fprintf(w, "%s", ui.RenderMuted(" ("+formatDuration(result.Elapsed)+")"))
-			}
-			fmt.Fprintln(w)
+			printResultLine(w, report, result, slowThreshold)
 		}
-
 		report.Add(result)
 	}
 
 	return report
 }
 
+// fixCheck attempts to fix a failing check and re-runs it to verify.
+func (d *Doctor) fixCheck(ctx *CheckContext, check Check, result *CheckResult, w io.Writer) *CheckResult {
+	// Stream: show the problem with fixing indicator (all on same line)
+	if w != nil {
+		var problemIcon string
+		if result.Status == StatusError {
+			problemIcon = ui.RenderFailIcon()
+		} else {
+			problemIcon = ui.RenderWarnIcon()
+		}
+		// Overwrite the "checking" line with problem status + fixing indicator
+		fmt.Fprintf(w, "\r  %s  %s", problemIcon, check.Name())
+		if result.Message != "" {
+			fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
+		}
+		fmt.Fprintf(w, "%s", ui.RenderMuted(" (fixing)..."))
+	}
+
+	start := time.Now()
+	elapsed := result.Elapsed
+	err := safeFixCheck(check, ctx)
+	if err == nil {
+		// Re-run check to verify fix worked
+		result = runCheck(check, ctx)
+		// Update message to indicate fix was applied
+		if result.Status == StatusOK {
+			result.Message = result.Message + " (fixed)"
+			result.Fixed = true
+		}
+	} else if errors.Is(err, ErrSkippedNoStart) {
+		// Fix skipped due to --no-start flag
+		result.Details = append(result.Details, "Skipped: --no-start suppresses startup")
+	} else {
+		// Fix failed, add error to details
+		result.Details = append(result.Details, "Fix failed: "+err.Error())
+	}
+
+	// Record total elapsed time including the fix attempt
+	result.Elapsed = elapsed + time.Since(start)
+	return result
+}
+
+// printResultLine overwrites the "checking" line with a check's final result.
+func printResultLine(w io.Writer, report *Report, result *CheckResult, slowThreshold time.Duration) {
+	var statusIcon string
+	if result.Fixed {
+		statusIcon = ui.RenderFixIcon()
+	} else {
+		switch result.Status {
+		case StatusOK:
+			statusIcon = ui.RenderPassIcon()
+		case StatusWarning:
+			statusIcon = ui.RenderWarnIcon()
+		case StatusError:
+			statusIcon = ui.RenderFailIcon()
+		}
+	}
+	// Check if slow (hourglass replaces spaces to maintain alignment)
+	// Fix icon (🔧) is double-width, so use one less padding space
+	isSlow := slowThreshold > 0 && result.Elapsed >= slowThreshold
+	slowIndicator := "  "
+	if result.Fixed {
+		slowIndicator = " "
+	}
+	if isSlow {
+		report.Summary.Slow++
+		slowIndicator = "⏳"
+	}
+	fmt.Fprintf(w, "\r  %s%s%s", statusIcon, slowIndicator, result.Name)
+	if result.Message != "" {
+		fmt.Fprintf(w, "%s", ui.RenderMuted(" "+result.Message))
+	}
+	if isSlow {
+		fmt.Fprintf(w, "%s", ui.RenderMuted(" ("+formatDuration(result.Elapsed)+")"))
+	}
+	fmt.Fprintln(w)
+}
+
 // BaseCheck provides a base implementation for checks that don't support auto-fix.
 // Embed this in custom checks to get default CanFix() and Fix() implementations.
 type BaseCheck struct {
 	CheckName        string
 	CheckDescription string
-	CheckCategory    string // Category for grouping (e.g., CategoryCore)
+	CheckCategory    string   // Category for grouping (e.g., CategoryCore)
+	CheckDependsOn   []string // Names of prerequisite checks (optional)
 }
 
 // Category returns the check's category for grouping in output.
@@ -246,6 +233,28 @@ func (b *BaseCheck) Category() string {
 	return b.CheckCategory
 }
 
+// DependsOn returns the names of checks that must run first. If any of them
+// fails with an error, this check is skipped.
+func (b *BaseCheck) DependsOn() []string {
+	return b.CheckDependsOn
+}
+
+// baseCheck gives package helpers access to the embedded BaseCheck.
+func (b *BaseCheck) baseCheck() *BaseCheck {
+	return b
+}
+
+// requires adds prerequisites to checks built on BaseCheck.
+func requires(prereqs []string, checks ...Check) []Check {
+	for _, c := range checks {
+		if bc, ok := c.(interface{ baseCheck() *BaseCheck }); ok {
+			b := bc.baseCheck()
+			b.CheckDependsOn = append(slices.Clip(b.CheckDependsOn), prereqs...)
+		}
+	}
+	return checks
+}
+
 // Name returns the check name.
 func (b *BaseCheck) Name() string {
 	return b.CheckName
@@ -0,0 +1,296 @@
+package doctor
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Output formats for a report.
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+	FormatSARIF = "sarif"
+)
+
+// Formats lists the supported output formats.
+var Formats = []string{FormatText, FormatJSON, FormatJUnit, FormatSARIF}
+
+// MarshalText encodes the status as "ok", "warning" or "error".
+func (s CheckStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case StatusOK, StatusWarning, StatusError:
+		return []byte(strings.ToLower(s.String())), nil
+	default:
+		return nil, fmt.Errorf("unknown check status %d", int(s))
+	}
+}
+
+// UnmarshalText decodes a status encoded by MarshalText.
+func (s *CheckStatus) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "ok":
+		*s = StatusOK
+	case "warning":
+		*s = StatusWarning
+	case "error":
+		*s = StatusError
+	default:
+		return fmt.Errorf("unknown check status %q", text)
+	}
+	return nil
+}
+
+// Write renders the report in a machine-readable format. FormatText is
+// handled by Print and is not accepted here.
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		return r.WriteJSON(w)
+	case FormatJUnit:
+		return r.WriteJUnit(w)
+	case FormatSARIF:
+		return r.WriteSARIF(w)
+	default:
+		return fmt.Errorf("unsupported report format %q (want %s)", format, strings.Join(Formats[1:], ", "))
+	}
+}
+
+type jsonReport struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Healthy   bool        `json:"healthy"`
+	Summary   jsonSummary `json:"summary"`
+	Checks    []jsonCheck `json:"checks"`
+}
+
+type jsonSummary struct {
+	Total    int `json:"total"`
+	OK       int `json:"ok"`
+	Warnings int `json:"warnings"`
+	Errors   int `json:"errors"`
+	Fixed    int `json:"fixed"`
+}
+
+type jsonCheck struct {
+	Name      string      `json:"name"`
+	Category  string      `json:"category,omitempty"`
+	Status    CheckStatus `json:"status"`
+	Message   string      `json:"message,omitempty"`
+	Details   []string    `json:"details,omitempty"`
+	FixHint   string      `json:"fix_hint,omitempty"`
+	ElapsedMs int64       `json:"elapsed_ms"`
+	Fixed     bool        `json:"fixed,omitempty"`
+	Skipped   bool        `json:"skipped,omitempty"`
+}
+
+// WriteJSON writes the report as an indented JSON document.
+func (r *Report) WriteJSON(w io.Writer) error {
+	out := jsonReport{
+		Timestamp: r.Timestamp.UTC(),
+		Healthy:   r.IsHealthy(),
+		Summary: jsonSummary{
+			Total:    r.Summary.Total,
+			OK:       r.Summary.OK,
+			Warnings: r.Summary.Warnings,
+			Errors:   r.Summary.Errors,
+			Fixed:    r.Summary.Fixed,
+		},
+		Checks: make([]jsonCheck, 0, len(r.Checks)),
+	}
+	for _, c := range r.Checks {
+		out.Checks = append(out.Checks, jsonCheck{
+			Name:      c.Name,
+			Category:  c.Category,
+			Status:    c.Status,
+			Message:   c.Message,
+			Details:   c.Details,
+			FixHint:   c.FixHint,
+			ElapsedMs: c.Elapsed.Milliseconds(),
+			Fixed:     c.Fixed,
+			Skipped:   c.Skipped,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as JUnit XML. Errors are failures, checks
+// skipped for a failed prerequisite are skipped, and warnings pass with the
+// warning in system-out so CI surfaces them without failing the build.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{
+		Name:      "gt doctor",
+		Tests:     len(r.Checks),
+		Timestamp: r.Timestamp.UTC().Format("2006-01-02T15:04:05"),
+	}
+	var total time.Duration
+	for _, c := range r.Checks {
+		total += c.Elapsed
+		classname := "doctor"
+		if c.Category != "" {
+			classname += "." + c.Category
+		}
+		tc := junitTestCase{
+			Name:      c.Name,
+			Classname: classname,
+			Time:      junitSeconds(c.Elapsed),
+		}
+		body := strings.Join(append(append([]string{}, c.Details...), fixHintLine(c)...), "\n")
+		switch {
+		case c.Skipped:
+			suite.Skipped++
+			tc.Skipped = &junitMessage{Message: c.Message}
+		case c.Status == StatusError:
+			suite.Failures++
+			tc.Failure = &junitMessage{Message: c.Message, Type: "error", Body: body}
+		case c.Status == StatusWarning:
+			tc.SystemOut = strings.TrimSpace("warning: " + c.Message + "\n" + body)
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = junitSeconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+func fixHintLine(c *CheckResult) []string {
+	if c.FixHint == "" {
+		return nil
+	}
+	return []string{"Fix: " + c.FixHint}
+}
+
+// SARIF 2.1.0, limited to the fields editors and code-scanning UIs read.
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+	Properties       *sarifProps   `json:"properties,omitempty"`
+}
+
+type sarifProps struct {
+	Category string `json:"category,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string       `json:"ruleId"`
+	RuleIndex int          `json:"ruleIndex"`
+	Level     string       `json:"level"`
+	Message   sarifMessage `json:"message"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// WriteSARIF writes the report as a SARIF 2.1.0 log. Every check is a rule;
+// only warnings and errors produce results.
+func (r *Report) WriteSARIF(w io.Writer) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gt doctor",
+			InformationURI: "https://github.com/steveyegge/gastown",
+			Rules:          make([]sarifRule, 0, len(r.Checks)),
+		}},
+		Results: make([]sarifResult, 0),
+	}
+	for i, c := range r.Checks {
+		rule := sarifRule{ID: c.Name}
+		if c.Category != "" {
+			rule.Properties = &sarifProps{Category: c.Category}
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+
+		if c.Status == StatusOK {
+			continue
+		}
+		level := "error"
+		if c.Status == StatusWarning {
+			level = "warning"
+		}
+		text := strings.Join(append(append([]string{c.Message}, c.Details...), fixHintLine(c)...), "\n")
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    c.Name,
+			RuleIndex: i,
+			Level:     level,
+			Message:   sarifMessage{Text: strings.TrimSpace(text)},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}})
+}
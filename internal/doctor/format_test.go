@@ -0,0 +1,112 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleReport() *Report {
+	r := NewReport()
+	r.Add(&CheckResult{Name: "town-config-valid", Category: CategoryCore, Status: StatusOK, Message: "valid", Elapsed: 12 * time.Millisecond})
+	r.Add(&CheckResult{Name: "daemon", Category: CategoryInfrastructure, Status: StatusWarning, Message: "not running", FixHint: "gt daemon start"})
+	r.Add(&CheckResult{Name: "dolt-server-reachable", Status: StatusError, Message: "connection refused", Details: []string{"port 3307"}})
+	r.Add(&CheckResult{Name: "null-assignee-steps", Status: StatusWarning, Message: "skipped: requires dolt-server-reachable", Skipped: true})
+	return r
+}
+
+func TestCheckStatus_TextRoundTrip(t *testing.T) {
+	for _, s := range []CheckStatus{StatusOK, StatusWarning, StatusError} {
+		text, err := s.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got CheckStatus
+		if err := got.UnmarshalText(text); err != nil || got != s {
+			t.Errorf("round trip %v via %q = %v, %v", s, text, got, err)
+		}
+	}
+	if _, err := CheckStatus(99).MarshalText(); err == nil {
+		t.Error("unknown status should not marshal")
+	}
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().Write(&buf, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Healthy bool
+		Summary struct{ Total, Errors int }
+		Checks  []struct {
+			Name      string
+			Status    string
+			ElapsedMs int64 `json:"elapsed_ms"`
+			Skipped   bool
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if got.Healthy || got.Summary.Total != 4 || got.Summary.Errors != 1 {
+		t.Errorf("summary = %+v healthy=%v", got.Summary, got.Healthy)
+	}
+	if c := got.Checks[0]; c.Status != "ok" || c.ElapsedMs != 12 {
+		t.Errorf("checks[0] = %+v", c)
+	}
+	if c := got.Checks[3]; c.Status != "warning" || !c.Skipped {
+		t.Errorf("checks[3] = %+v", c)
+	}
+}
+
+func TestReport_WriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().Write(&buf, FormatJUnit); err != nil {
+		t.Fatal(err)
+	}
+	var got junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	suite := got.Suites[0]
+	if suite.Tests != 4 || suite.Failures != 1 || suite.Skipped != 1 {
+		t.Errorf("suite = tests %d failures %d skipped %d", suite.Tests, suite.Failures, suite.Skipped)
+	}
+	if c := suite.Cases[0]; c.Classname != "doctor.Core" || c.Time != "0.012" || c.Failure != nil {
+		t.Errorf("case 0 = %+v", c)
+	}
+	if c := suite.Cases[1]; c.Failure != nil || !strings.Contains(c.SystemOut, "Fix: gt daemon start") {
+		t.Errorf("warning case = %+v", c)
+	}
+	if c := suite.Cases[2]; c.Failure == nil || c.Failure.Message != "connection refused" || !strings.Contains(c.Failure.Body, "port 3307") {
+		t.Errorf("error case = %+v", c)
+	}
+}
+
+func TestReport_WriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().Write(&buf, FormatSARIF); err != nil {
+		t.Fatal(err)
+	}
+	var got sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid SARIF: %v", err)
+	}
+	run := got.Runs[0]
+	if got.Version != "2.1.0" || len(run.Tool.Driver.Rules) != 4 || len(run.Results) != 3 {
+		t.Fatalf("log = version %s, %d rules, %d results", got.Version, len(run.Tool.Driver.Rules), len(run.Results))
+	}
+	if r := run.Results[1]; r.RuleID != "dolt-server-reachable" || r.RuleIndex != 2 || r.Level != "error" {
+		t.Errorf("result = %+v", r)
+	}
+}
+
+func TestReport_WriteUnknownFormat(t *testing.T) {
+	if err := sampleReport().Write(&bytes.Buffer{}, FormatText); err == nil {
+		t.Error("Write(text) should fail; text output uses Print")
+	}
+}
@@ -0,0 +1,212 @@
+package doctor
+
+import (
+	"container/heap"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultJobs is the default number of checks run concurrently.
+const DefaultJobs = 8
+
+// beadsPrerequisites are the checks that must pass before checks that shell
+// out to bd or query the beads database can give meaningful results.
+var beadsPrerequisites = []string{"beads-binary", "dolt-server-reachable"}
+
+// dependencyGetter interface for checks that declare prerequisites
+type dependencyGetter interface {
+	DependsOn() []string
+}
+
+// checkGraph orders registered checks by their declared prerequisites.
+type checkGraph struct {
+	checks []Check
+	order  []int   // topological order; registration order among independent checks
+	deps   [][]int // prerequisite indices per check, all earlier in order
+}
+
+// newCheckGraph builds the dependency graph for checks. Prerequisites that
+// aren't registered are ignored. A dependency cycle is broken by running the
+// earliest-registered check of the cycle first without its remaining
+// prerequisites, so a bad declaration never wedges gt doctor.
+func newCheckGraph(checks []Check) *checkGraph {
+	g := &checkGraph{
+		checks: checks,
+		deps:   make([][]int, len(checks)),
+	}
+
+	byName := make(map[string]int, len(checks))
+	for i, c := range checks {
+		if _, dup := byName[c.Name()]; !dup {
+			byName[c.Name()] = i
+		}
+	}
+	declared := make([][]int, len(checks))
+	for i, c := range checks {
+		dg, ok := c.(dependencyGetter)
+		if !ok {
+			continue
+		}
+		for _, name := range dg.DependsOn() {
+			if j, ok := byName[name]; ok && j != i {
+				declared[i] = append(declared[i], j)
+			}
+		}
+	}
+
+	// Kahn's algorithm, always taking the earliest-registered ready check.
+	waiting := make([]int, len(checks))
+	for i := range checks {
+		waiting[i] = len(declared[i])
+	}
+	dependents := make([][]int, len(checks))
+	for i, ds := range declared {
+		for _, j := range ds {
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+	placed := make([]bool, len(checks))
+	ready := &indexHeap{}
+	for i := range checks {
+		if waiting[i] == 0 {
+			heap.Push(ready, i)
+		}
+	}
+	for len(g.order) < len(checks) {
+		if ready.Len() == 0 {
+			// Cycle: release the earliest unplaced check.
+			for i := range checks {
+				if !placed[i] {
+					heap.Push(ready, i)
+					break
+				}
+			}
+		}
+		i := heap.Pop(ready).(int)
+		if placed[i] {
+			continue
+		}
+		placed[i] = true
+		g.order = append(g.order, i)
+		for _, j := range declared[i] {
+			if placed[j] {
+				g.deps[i] = append(g.deps[i], j)
+			}
+		}
+		for _, k := range dependents[i] {
+			waiting[k]--
+			if waiting[k] == 0 && !placed[k] {
+				heap.Push(ready, k)
+			}
+		}
+	}
+	return g
+}
+
+// indexHeap is a min-heap of check indices.
+type indexHeap []int
+
+func (h indexHeap) Len() int           { return len(h) }
+func (h indexHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h indexHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *indexHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *indexHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// checkRun is the first-pass result of one check.
+type checkRun struct {
+	done   chan struct{}
+	result *CheckResult
+}
+
+// runAll starts running every check once its prerequisites have finished,
+// with at most jobs checks running at a time. Each run's done channel closes
+// when its result is available. Once stop is closed, checks that haven't
+// started yet finish without running and leave a nil result.
+func (g *checkGraph) runAll(ctx *CheckContext, jobs int, stop <-chan struct{}) []*checkRun {
+	if jobs < 1 {
+		jobs = 1
+	}
+	runs := make([]*checkRun, len(g.checks))
+	for i := range runs {
+		runs[i] = &checkRun{done: make(chan struct{})}
+	}
+	sem := make(chan struct{}, jobs)
+	for _, i := range g.order {
+		go func(i int) {
+			defer close(runs[i].done)
+			prereqs := make([]*CheckResult, len(g.deps[i]))
+			for n, j := range g.deps[i] {
+				<-runs[j].done
+				prereqs[n] = runs[j].result
+			}
+			if r := skippedResult(g.checks[i], prereqs); r != nil {
+				runs[i].result = r
+				return
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-stop:
+				return
+			}
+			defer func() { <-sem }()
+			runs[i].result = runCheck(g.checks[i], ctx)
+		}(i)
+	}
+	return runs
+}
+
+// prerequisites returns the final results of check i's prerequisites.
+func (g *checkGraph) prerequisites(i int, final []*CheckResult) []*CheckResult {
+	prereqs := make([]*CheckResult, len(g.deps[i]))
+	for n, j := range g.deps[i] {
+		prereqs[n] = final[j]
+	}
+	return prereqs
+}
+
+// runCheck runs a check and fills in its name, category and elapsed time.
+func runCheck(check Check, ctx *CheckContext) *CheckResult {
+	start := time.Now()
+	result := check.Run(ctx)
+	result.Elapsed = time.Since(start)
+	fillResult(check, result)
+	return result
+}
+
+// fillResult ensures the check name and category are populated.
+func fillResult(check Check, result *CheckResult) {
+	if result.Name == "" {
+		result.Name = check.Name()
+	}
+	if cg, ok := check.(categoryGetter); ok && result.Category == "" {
+		result.Category = cg.Category()
+	}
+}
+
+// skippedResult returns the result for a check whose prerequisites failed,
+// or nil if every prerequisite passed or only warned.
+func skippedResult(check Check, prereqs []*CheckResult) *CheckResult {
+	var failed []string
+	for _, p := range prereqs {
+		if p != nil && p.Status == StatusError {
+			failed = append(failed, p.Name)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	result := &CheckResult{
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("skipped: requires %s", strings.Join(failed, ", ")),
+		FixHint: fmt.Sprintf("Fix %s first, then re-run gt doctor", strings.Join(failed, ", ")),
+		Skipped: true,
+	}
+	fillResult(check, result)
+	return result
+}
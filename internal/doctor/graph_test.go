@@ -0,0 +1,310 @@
+package doctor
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// depCheck is a mock check with prerequisites that counts its runs.
+type depCheck struct {
+	mockCheck
+	runs  atomic.Int32
+	delay time.Duration
+}
+
+func newDepCheck(name string, status CheckStatus, deps ...string) *depCheck {
+	c := &depCheck{mockCheck: *newMockCheck(name, status)}
+	c.CheckDependsOn = deps
+	return c
+}
+
+func (c *depCheck) Run(ctx *CheckContext) *CheckResult {
+	c.runs.Add(1)
+	time.Sleep(c.delay)
+	return c.mockCheck.Run(ctx)
+}
+
+func checkNames(results []*CheckResult) string {
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = r.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestNewCheckGraph_Order(t *testing.T) {
+	checks := []Check{
+		newDepCheck("c", StatusOK, "b"),
+		newDepCheck("a", StatusOK),
+		newDepCheck("b", StatusOK, "a", "missing"),
+		newDepCheck("d", StatusOK),
+	}
+	g := newCheckGraph(checks)
+
+	var names []string
+	for _, i := range g.order {
+		names = append(names, checks[i].Name())
+	}
+	if got := strings.Join(names, ","); got != "a,b,c,d" {
+		t.Errorf("order = %s, want a,b,c,d", got)
+	}
+	if len(g.deps[2]) != 1 || g.deps[2][0] != 1 {
+		t.Errorf("deps of b = %v, want [1] (unknown prerequisites ignored)", g.deps[2])
+	}
+}
+
+func TestNewCheckGraph_CycleDoesNotHang(t *testing.T) {
+	d := NewDoctor()
+	d.Register(newDepCheck("x", StatusOK, "y"))
+	d.Register(newDepCheck("y", StatusOK, "x"))
+
+	done := make(chan *Report)
+	go func() { done <- d.Run(&CheckContext{TownRoot: "/test"}) }()
+	select {
+	case report := <-done:
+		if got := checkNames(report.Checks); got != "x,y" {
+			t.Errorf("checks = %s, want x,y", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() hung on a dependency cycle")
+	}
+}
+
+func TestDoctor_PrerequisiteErrorSkipsDependents(t *testing.T) {
+	d := NewDoctor()
+	d.Register(newDepCheck("config", StatusError))
+	d.Register(newDepCheck("rig", StatusOK, "config"))
+	d.Register(newDepCheck("rig-detail", StatusOK, "rig"))
+	d.Register(newDepCheck("unrelated", StatusOK))
+
+	report := d.Run(&CheckContext{TownRoot: "/test"})
+
+	rig := report.Checks[1]
+	if !rig.Skipped || rig.Status != StatusWarning || !strings.Contains(rig.Message, "requires config") {
+		t.Errorf("rig = %+v, want skipped warning requiring config", rig)
+	}
+	// A skipped check is a warning, so it does not cascade further.
+	if report.Checks[2].Skipped {
+		t.Errorf("rig-detail should run when its prerequisite was skipped")
+	}
+	if report.Checks[3].Skipped || report.Checks[3].Status != StatusOK {
+		t.Errorf("unrelated = %+v, want OK", report.Checks[3])
+	}
+	if n := d.Checks()[1].(*depCheck).runs.Load(); n != 0 {
+		t.Errorf("skipped check ran %d times", n)
+	}
+}
+
+func TestDoctor_PrerequisiteWarningDoesNotSkip(t *testing.T) {
+	d := NewDoctor()
+	d.Register(newDepCheck("config", StatusWarning))
+	d.Register(newDepCheck("rig", StatusOK, "config"))
+
+	report := d.Run(&CheckContext{TownRoot: "/test"})
+	if report.Checks[1].Skipped || report.Checks[1].Status != StatusOK {
+		t.Errorf("rig = %+v, want OK", report.Checks[1])
+	}
+}
+
+func TestDoctor_ParallelKeepsOutputOrder(t *testing.T) {
+	d := NewDoctor()
+	d.SetJobs(4)
+	var checks []*depCheck
+	for i, name := range []string{"slow", "medium", "fast", "instant"} {
+		c := newDepCheck(name, StatusOK)
+		c.delay = time.Duration(3-i) * 50 * time.Millisecond
+		checks = append(checks, c)
+		d.Register(c)
+	}
+
+	var buf bytes.Buffer
+	start := time.Now()
+	report := d.RunStreaming(&CheckContext{TownRoot: "/test"}, &buf, 0)
+	elapsed := time.Since(start)
+
+	if got := checkNames(report.Checks); got != "slow,medium,fast,instant" {
+		t.Errorf("report order = %s", got)
+	}
+	out := buf.String()
+	last := -1
+	for _, c := range checks {
+		idx := strings.LastIndex(out, c.Name())
+		if idx < last {
+			t.Errorf("output out of order:\n%s", out)
+			break
+		}
+		last = idx
+	}
+	// Sequential would take 300ms; parallel is bounded by the slowest check.
+	if elapsed >= 280*time.Millisecond {
+		t.Errorf("parallel run took %v, want checks to overlap", elapsed)
+	}
+}
+
+func TestDoctor_SingleJobRunsSequentially(t *testing.T) {
+	d := NewDoctor()
+	d.SetJobs(1)
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	for _, name := range []string{"a", "b", "c"} {
+		d.Register(&trackingCheck{
+			BaseCheck: BaseCheck{CheckName: name},
+			enter: func() {
+				mu.Lock()
+				running++
+				maxRunning = max(maxRunning, running)
+				mu.Unlock()
+			},
+			exit: func() {
+				mu.Lock()
+				running--
+				mu.Unlock()
+			},
+		})
+	}
+	d.Run(&CheckContext{TownRoot: "/test"})
+	if maxRunning != 1 {
+		t.Errorf("max concurrent checks = %d, want 1", maxRunning)
+	}
+}
+
+// trackingCheck calls enter and exit around a short run.
+type trackingCheck struct {
+	BaseCheck
+	enter, exit func()
+}
+
+func (c *trackingCheck) Run(ctx *CheckContext) *CheckResult {
+	c.enter()
+	defer c.exit()
+	time.Sleep(10 * time.Millisecond)
+	return &CheckResult{Name: c.CheckName, Status: StatusOK}
+}
+
+// TestDoctor_FixRerunsLaterChecks verifies that once a fix is applied every
+// later check runs again, and a check skipped for the fixed prerequisite
+// runs for the first time.
+func TestDoctor_FixRerunsLaterChecks(t *testing.T) {
+	d := NewDoctor()
+	config := newDepCheck("config", StatusError)
+	config.fixable = true
+	rig := newDepCheck("rig", StatusOK, "config")
+	unrelated := newDepCheck("unrelated", StatusOK)
+	d.RegisterAll(config, rig, unrelated)
+
+	report := d.Fix(&CheckContext{TownRoot: "/test"})
+
+	if !report.Checks[0].Fixed || report.Checks[0].Status != StatusOK {
+		t.Errorf("config = %+v, want fixed", report.Checks[0])
+	}
+	if report.Checks[1].Skipped || report.Checks[1].Status != StatusOK {
+		t.Errorf("rig = %+v, want re-run after config was fixed", report.Checks[1])
+	}
+	if n := config.runs.Load(); n != 2 {
+		t.Errorf("config ran %d times, want 2 (check + verify)", n)
+	}
+	if n := rig.runs.Load(); n != 1 {
+		t.Errorf("rig ran %d times, want 1 (skipped, then re-run)", n)
+	}
+	if n := unrelated.runs.Load(); n != 2 {
+		t.Errorf("unrelated ran %d times, want 2 (first pass, then after the fix)", n)
+	}
+}
+
+// TestDoctor_FixRunsInParallelUntilFirstFix verifies that --fix on a healthy
+// town runs checks concurrently, each once, like a plain run.
+func TestDoctor_FixRunsInParallelUntilFirstFix(t *testing.T) {
+	d := NewDoctor()
+	d.SetJobs(4)
+	var checks []*depCheck
+	for _, name := range []string{"a", "b", "c", "d"} {
+		c := newDepCheck(name, StatusOK)
+		c.delay = 50 * time.Millisecond
+		checks = append(checks, c)
+		d.Register(c)
+	}
+
+	start := time.Now()
+	report := d.Fix(&CheckContext{TownRoot: "/test"})
+	elapsed := time.Since(start)
+
+	if got := checkNames(report.Checks); got != "a,b,c,d" {
+		t.Errorf("report order = %s", got)
+	}
+	for _, c := range checks {
+		if n := c.runs.Load(); n != 1 {
+			t.Errorf("%s ran %d times, want 1", c.Name(), n)
+		}
+	}
+	// Sequential would take 200ms.
+	if elapsed >= 180*time.Millisecond {
+		t.Errorf("fix run took %v, want checks to overlap", elapsed)
+	}
+}
+
+// stateCheck fails until its fix flips the shared state, which an unrelated
+// reader check also inspects.
+type stateCheck struct {
+	BaseCheck
+	state *atomic.Bool
+	fix   bool
+}
+
+func (c *stateCheck) Run(ctx *CheckContext) *CheckResult {
+	time.Sleep(10 * time.Millisecond)
+	if !c.state.Load() {
+		return &CheckResult{Name: c.CheckName, Status: StatusError}
+	}
+	return &CheckResult{Name: c.CheckName, Status: StatusOK}
+}
+
+func (c *stateCheck) CanFix() bool { return c.fix }
+
+func (c *stateCheck) Fix(ctx *CheckContext) error {
+	time.Sleep(20 * time.Millisecond)
+	c.state.Store(true)
+	return nil
+}
+
+// TestDoctor_FixCompletesBeforeLaterChecks verifies that with --fix a later
+// check sees the fixed state even when it doesn't declare a dependency.
+func TestDoctor_FixCompletesBeforeLaterChecks(t *testing.T) {
+	var state atomic.Bool
+	d := NewDoctor()
+	d.RegisterAll(
+		&stateCheck{BaseCheck: BaseCheck{CheckName: "writer"}, state: &state, fix: true},
+		&stateCheck{BaseCheck: BaseCheck{CheckName: "reader"}, state: &state},
+	)
+
+	report := d.Fix(&CheckContext{TownRoot: "/test"})
+	if !report.Checks[0].Fixed {
+		t.Errorf("writer = %+v, want fixed", report.Checks[0])
+	}
+	if report.Checks[1].Status != StatusOK {
+		t.Errorf("reader = %+v, want OK after the writer's fix", report.Checks[1])
+	}
+}
+
+func TestBeadsChecksRequireBeadsPrerequisites(t *testing.T) {
+	checks := []Check{
+		NewCustomTypesCheck(), NewCustomStatusesCheck(), NewRoleLabelCheck(),
+		NewNullAssigneeCheck(), NewDatabasePrefixCheck(), NewRoutingModeCheck(),
+		NewAgentBeadsCheck(), NewStaleAgentBeadsCheck(), NewRigBeadsCheck(),
+		NewBeadsConfigValidCheck(), NewHookAttachmentValidCheck(),
+		NewHookSingletonCheck(), NewOrphanedAttachmentsCheck(), NewWispGCCheck(),
+		NewCheckMisclassifiedWisps(), NewCheckJSONLBloat(), NewPatrolNotStuckCheck(),
+	}
+	for _, c := range checks {
+		deps := strings.Join(c.(dependencyGetter).DependsOn(), ",")
+		for _, prereq := range beadsPrerequisites {
+			if !strings.Contains(deps, prereq) {
+				t.Errorf("%s depends on %q, want %s", c.Name(), deps, prereq)
+			}
+		}
+	}
+}
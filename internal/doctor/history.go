@@ -0,0 +1,277 @@
+package doctor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultHistoryLimit is the number of runs kept in the doctor history.
+const DefaultHistoryLimit = 100
+
+// HistoryPath returns the path of the doctor run history for a town.
+func HistoryPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "doctor", "history.jsonl")
+}
+
+// HistoryEntry records one gt doctor run.
+type HistoryEntry struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Scope     string         `json:"scope,omitempty"` // e.g. "rig:gastown", "custom"
+	Checks    []HistoryCheck `json:"checks"`
+}
+
+// HistoryCheck records one check's outcome within a run.
+type HistoryCheck struct {
+	Name      string      `json:"name"`
+	Status    CheckStatus `json:"status"`
+	ElapsedMs int64       `json:"elapsed_ms"`
+	Skipped   bool        `json:"skipped,omitempty"`
+}
+
+// NewHistoryEntry summarizes a report for the history.
+func NewHistoryEntry(r *Report, scope string) HistoryEntry {
+	entry := HistoryEntry{
+		Timestamp: r.Timestamp.UTC(),
+		Scope:     scope,
+		Checks:    make([]HistoryCheck, 0, len(r.Checks)),
+	}
+	for _, c := range r.Checks {
+		entry.Checks = append(entry.Checks, HistoryCheck{
+			Name:      c.Name,
+			Status:    c.Status,
+			ElapsedMs: c.Elapsed.Milliseconds(),
+			Skipped:   c.Skipped,
+		})
+	}
+	return entry
+}
+
+// LoadHistory reads the recorded runs, oldest first. A missing history is
+// empty; malformed lines are skipped.
+func LoadHistory(townRoot string) ([]HistoryEntry, error) {
+	data, err := os.ReadFile(HistoryPath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading doctor history: %w", err)
+	}
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// AppendHistory records entry, keeping only the most recent limit runs
+// (DefaultHistoryLimit if limit <= 0). The read-trim-write is serialized
+// across processes with a flock on the history file, so concurrent gt doctor
+// runs never drop each other's entries.
+func AppendHistory(townRoot string, entry HistoryEntry, limit int) error {
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	path := HistoryPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating doctor history dir: %w", err)
+	}
+
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring doctor history lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	entries, err := LoadHistory(townRoot)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, entry)
+	if len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	var buf bytes.Buffer
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return util.AtomicWriteFile(path, buf.Bytes(), 0644)
+}
+
+// Trend compares the latest recorded run with the runs before it.
+type Trend struct {
+	Runs     int       `json:"runs"`
+	Latest   time.Time `json:"latest"`
+	Previous time.Time `json:"previous,omitzero"`
+
+	// Regressions are checks whose status got worse than the last run that
+	// included them, or that fail on their first recorded run.
+	Regressions []TrendChange `json:"regressions"`
+
+	// Recoveries are checks that passed after previously failing.
+	Recoveries []TrendChange `json:"recoveries"`
+
+	// Failing lists every check failing in the latest run with how long it
+	// has been failing.
+	Failing []FailingCheck `json:"failing"`
+
+	// Durations lists checks whose latest run took notably longer or
+	// shorter than their median over earlier runs.
+	Durations []DurationTrend `json:"durations"`
+}
+
+// TrendChange is a status change of one check.
+type TrendChange struct {
+	Name   string       `json:"name"`
+	Status CheckStatus  `json:"status"`
+	Was    *CheckStatus `json:"was,omitempty"` // nil when the check is new
+}
+
+// FailingCheck is a check failing in the latest run.
+type FailingCheck struct {
+	Name   string      `json:"name"`
+	Status CheckStatus `json:"status"`
+	Runs   int         `json:"runs"`  // consecutive failing runs, including the latest
+	Since  time.Time   `json:"since"` // first run of the failing streak
+}
+
+// DurationTrend compares a check's latest duration with its history.
+type DurationTrend struct {
+	Name     string `json:"name"`
+	LatestMs int64  `json:"latest_ms"`
+	MedianMs int64  `json:"median_ms"`
+	Samples  int    `json:"samples"` // earlier runs the median is taken over
+}
+
+// Duration changes are reported only when both thresholds are exceeded.
+const (
+	trendDurationMinDelta = 100 * time.Millisecond
+	trendDurationMinRatio = 1.5
+)
+
+// ComputeTrend analyzes history (oldest first). Each check is compared with
+// the most recent earlier run that included it, so runs limited to one rig
+// or to custom checks don't read as regressions of the checks they skipped.
+// It returns nil for an empty history.
+func ComputeTrend(history []HistoryEntry) *Trend {
+	if len(history) == 0 {
+		return nil
+	}
+	latest := history[len(history)-1]
+	earlier := history[:len(history)-1]
+	t := &Trend{
+		Runs:        len(history),
+		Latest:      latest.Timestamp,
+		Regressions: []TrendChange{},
+		Recoveries:  []TrendChange{},
+		Failing:     []FailingCheck{},
+		Durations:   []DurationTrend{},
+	}
+	if len(earlier) > 0 {
+		t.Previous = earlier[len(earlier)-1].Timestamp
+	}
+
+	for _, c := range latest.Checks {
+		var prev []HistoryCheck // earlier outcomes of c, newest first
+		var prevTimes []time.Time
+		for i := len(earlier) - 1; i >= 0; i-- {
+			if pc, ok := findHistoryCheck(earlier[i], c.Name); ok {
+				prev = append(prev, pc)
+				prevTimes = append(prevTimes, earlier[i].Timestamp)
+			}
+		}
+
+		switch {
+		case len(prev) == 0:
+			if c.Status != StatusOK {
+				t.Regressions = append(t.Regressions, TrendChange{Name: c.Name, Status: c.Status})
+			}
+		case c.Status > prev[0].Status:
+			was := prev[0].Status
+			t.Regressions = append(t.Regressions, TrendChange{Name: c.Name, Status: c.Status, Was: &was})
+		case c.Status == StatusOK && prev[0].Status != StatusOK:
+			was := prev[0].Status
+			t.Recoveries = append(t.Recoveries, TrendChange{Name: c.Name, Status: c.Status, Was: &was})
+		}
+
+		if c.Status != StatusOK {
+			f := FailingCheck{Name: c.Name, Status: c.Status, Runs: 1, Since: latest.Timestamp}
+			for i, pc := range prev {
+				if pc.Status == StatusOK {
+					break
+				}
+				f.Runs++
+				f.Since = prevTimes[i]
+			}
+			t.Failing = append(t.Failing, f)
+		}
+
+		if c.Skipped {
+			continue
+		}
+		var samples []int64
+		for _, pc := range prev {
+			if !pc.Skipped {
+				samples = append(samples, pc.ElapsedMs)
+			}
+		}
+		if len(samples) == 0 {
+			continue
+		}
+		median := medianMs(samples)
+		lo, hi := min(c.ElapsedMs, median), max(c.ElapsedMs, median)
+		if time.Duration(hi-lo)*time.Millisecond >= trendDurationMinDelta &&
+			float64(hi) >= trendDurationMinRatio*float64(max(lo, 1)) {
+			t.Durations = append(t.Durations, DurationTrend{
+				Name: c.Name, LatestMs: c.ElapsedMs, MedianMs: median, Samples: len(samples),
+			})
+		}
+	}
+
+	sort.SliceStable(t.Failing, func(i, j int) bool { return t.Failing[i].Since.Before(t.Failing[j].Since) })
+	sort.SliceStable(t.Durations, func(i, j int) bool {
+		di := t.Durations[i].LatestMs - t.Durations[i].MedianMs
+		dj := t.Durations[j].LatestMs - t.Durations[j].MedianMs
+		return di > dj
+	})
+	return t
+}
+
+func findHistoryCheck(e HistoryEntry, name string) (HistoryCheck, bool) {
+	i := slices.IndexFunc(e.Checks, func(c HistoryCheck) bool { return c.Name == name })
+	if i < 0 {
+		return HistoryCheck{}, false
+	}
+	return e.Checks[i], true
+}
+
+func medianMs(samples []int64) int64 {
+	s := slices.Clone(samples)
+	slices.Sort(s)
+	n := len(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
@@ -0,0 +1,125 @@
+package doctor
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func historyEntry(at time.Time, checks ...HistoryCheck) HistoryEntry {
+	return HistoryEntry{Timestamp: at, Checks: checks}
+}
+
+func TestAppendHistory_RollsOver(t *testing.T) {
+	townRoot := t.TempDir()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		e := historyEntry(start.Add(time.Duration(i)*time.Hour), HistoryCheck{Name: "a", Status: StatusOK})
+		if err := AppendHistory(townRoot, e, 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := LoadHistory(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || !got[0].Timestamp.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("history = %d entries starting %v, want last 3", len(got), got[0].Timestamp)
+	}
+}
+
+func TestAppendHistory_ConcurrentAppendsKeepEveryEntry(t *testing.T) {
+	townRoot := t.TempDir()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	const n = 20
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e := historyEntry(start.Add(time.Duration(i)*time.Minute), HistoryCheck{Name: "a", Status: StatusOK})
+			if err := AppendHistory(townRoot, e, 0); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries, err := LoadHistory(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != n {
+		t.Errorf("got %d entries, want %d", len(entries), n)
+	}
+}
+
+func TestNewHistoryEntry(t *testing.T) {
+	e := NewHistoryEntry(sampleReport(), "rig:gastown")
+	if e.Scope != "rig:gastown" || len(e.Checks) != 4 {
+		t.Fatalf("entry = %+v", e)
+	}
+	if c := e.Checks[2]; c.Name != "dolt-server-reachable" || c.Status != StatusError {
+		t.Errorf("checks[2] = %+v", c)
+	}
+}
+
+func TestComputeTrend(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	hour := time.Hour
+	history := []HistoryEntry{
+		historyEntry(t0,
+			HistoryCheck{Name: "stuck", Status: StatusOK},
+			HistoryCheck{Name: "slow", Status: StatusOK, ElapsedMs: 100},
+			HistoryCheck{Name: "flappy", Status: StatusError}),
+		historyEntry(t0.Add(hour),
+			HistoryCheck{Name: "stuck", Status: StatusError},
+			HistoryCheck{Name: "slow", Status: StatusOK, ElapsedMs: 120},
+			HistoryCheck{Name: "worse", Status: StatusWarning}),
+		// A custom-only run doesn't include the built-ins.
+		historyEntry(t0.Add(2*hour), HistoryCheck{Name: "custom", Status: StatusOK}),
+		historyEntry(t0.Add(3*hour),
+			HistoryCheck{Name: "stuck", Status: StatusError},
+			HistoryCheck{Name: "slow", Status: StatusOK, ElapsedMs: 900},
+			HistoryCheck{Name: "flappy", Status: StatusOK},
+			HistoryCheck{Name: "worse", Status: StatusError},
+			HistoryCheck{Name: "brand-new", Status: StatusWarning}),
+	}
+
+	trend := ComputeTrend(history)
+	if trend.Runs != 4 || !trend.Previous.Equal(t0.Add(2*hour)) {
+		t.Errorf("runs = %d previous = %v", trend.Runs, trend.Previous)
+	}
+
+	if len(trend.Regressions) != 2 {
+		t.Fatalf("regressions = %+v", trend.Regressions)
+	}
+	if r := trend.Regressions[0]; r.Name != "worse" || r.Was == nil || *r.Was != StatusWarning {
+		t.Errorf("regressions[0] = %+v", r)
+	}
+	if r := trend.Regressions[1]; r.Name != "brand-new" || r.Was != nil {
+		t.Errorf("regressions[1] = %+v", r)
+	}
+	if len(trend.Recoveries) != 1 || trend.Recoveries[0].Name != "flappy" {
+		t.Errorf("recoveries = %+v", trend.Recoveries)
+	}
+
+	// "stuck" has failed since the second run; "custom" isn't compared.
+	if len(trend.Failing) != 3 {
+		t.Fatalf("failing = %+v", trend.Failing)
+	}
+	if f := trend.Failing[0]; f.Name != "stuck" || f.Runs != 2 || !f.Since.Equal(t0.Add(hour)) {
+		t.Errorf("failing[0] = %+v", f)
+	}
+	if f := trend.Failing[1]; f.Name != "worse" || f.Runs != 2 {
+		t.Errorf("failing[1] = %+v", f)
+	}
+
+	if len(trend.Durations) != 1 || trend.Durations[0].Name != "slow" || trend.Durations[0].MedianMs != 110 {
+		t.Errorf("durations = %+v", trend.Durations)
+	}
+
+	if ComputeTrend(nil) != nil {
+		t.Error("empty history should have no trend")
+	}
+}
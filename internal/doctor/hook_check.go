@@ -33,6 +33,7 @@ func NewHookAttachmentValidCheck() *HookAttachmentValidCheck {
 				CheckName:        "hook-attachment-valid",
 				CheckDescription: "Verify attached molecules exist and are not closed",
 				CheckCategory:    CategoryHooks,
+				CheckDependsOn:   beadsPrerequisites,
 			},
 		},
 	}
@@ -209,6 +210,7 @@ func NewHookSingletonCheck() *HookSingletonCheck {
 				CheckName:        "hook-singleton",
 				CheckDescription: "Ensure each agent has at most one handoff bead",
 				CheckCategory:    CategoryHooks,
+				CheckDependsOn:   beadsPrerequisites,
 			},
 		},
 	}
@@ -349,6 +351,7 @@ func NewOrphanedAttachmentsCheck() *OrphanedAttachmentsCheck {
 			CheckName:        "orphaned-attachments",
 			CheckDescription: "Detect handoff beads for non-existent agents",
 			CheckCategory:    CategoryHooks,
+			CheckDependsOn:   beadsPrerequisites,
 		},
 	}
 }
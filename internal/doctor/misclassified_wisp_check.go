@@ -41,6 +41,7 @@ func NewCheckMisclassifiedWisps() *CheckMisclassifiedWisps {
 				CheckName:        "misclassified-wisps",
 				CheckDescription: "Detect issues that should be wisps but aren't marked as ephemeral",
 				CheckCategory:    CategoryCleanup,
+				CheckDependsOn:   beadsPrerequisites,
 			},
 		},
 		misclassifiedRigs: make(map[string]int),
@@ -242,6 +242,7 @@ func NewPatrolNotStuckCheck() *PatrolNotStuckCheck {
 			CheckName:        "patrol-not-stuck",
 			CheckDescription: "Check for stuck patrol wisps (>1h in_progress)",
 			CheckCategory:    CategoryPatrol,
+			CheckDependsOn:   beadsPrerequisites,
 		},
 		stuckThreshold: DefaultStuckThreshold,
 	}
@@ -812,6 +812,7 @@ func NewBeadsConfigValidCheck() *BeadsConfigValidCheck {
 				CheckName:        "beads-config-valid",
 				CheckDescription: "Verify beads configuration if .beads/ exists",
 				CheckCategory:    CategoryRig,
+				CheckDependsOn:   beadsPrerequisites,
 			},
 		},
 	}
@@ -1816,7 +1817,9 @@ func (c *BareRepoExistsCheck) findWorktreeDirs(rigPath, rigName string) []string
 
 // RigChecks returns all rig-level health checks.
 func RigChecks() []Check {
-	return []Check{
+	// Rig checks read paths derived from the town config; skip them when
+	// it's unusable rather than reporting a cascade of bogus failures.
+	return requires([]string{"town-config-valid"},
 		NewRigIsGitRepoCheck(),
 		NewGitExcludeConfiguredCheck(),
 		NewHooksPathConfiguredCheck(),
@@ -1830,5 +1833,5 @@ func RigChecks() []Check {
 		NewBeadsConfigValidCheck(),
 		NewBeadsRedirectCheck(),
 		NewTestutilSymlinkCheck(),
-	}
+	)
 }
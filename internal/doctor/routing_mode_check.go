@@ -26,6 +26,7 @@ func NewRoutingModeCheck() *RoutingModeCheck {
 				CheckName:        "routing-mode",
 				CheckDescription: "Check beads routing.mode is explicit (prevents .beads-planning routing)",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   beadsPrerequisites,
 			},
 		},
 	}
@@ -0,0 +1,133 @@
+package doctor
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/sandbox"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// SandboxCheck verifies that rigs with the polecat sandbox enabled can run
+// it, and that their running polecats are actually confined: each must have
+// a sandbox status file whose process is the session's inner sandbox stage
+// and lives in its own mount (and network) namespace.
+type SandboxCheck struct {
+	BaseCheck
+	sessionListerForTest SessionLister // Injectable for testing; nil uses the town's session backend
+	registryForTest      *session.PrefixRegistry
+	confinedForTest      func(pid int, network bool) error
+	innerStageForTest    func(pid int, policyPath string) error
+}
+
+// NewSandboxCheck creates a new polecat sandbox check.
+func NewSandboxCheck() *SandboxCheck {
+	return &SandboxCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "polecat-sandbox",
+			CheckDescription: "Verify sandboxed rigs run their polecats confined",
+			CheckCategory:    CategoryRig,
+		},
+	}
+}
+
+// Run checks every rig whose settings enable the sandbox.
+func (c *SandboxCheck) Run(ctx *CheckContext) *CheckResult {
+	rigs, err := discoverRigs(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{Name: c.Name(), Status: StatusWarning, Message: "Could not list rigs", Details: []string{err.Error()}}
+	}
+	sort.Strings(rigs)
+
+	sandboxed := make(map[string]*config.SandboxConfig)
+	var problems []string
+	for _, rigName := range rigs {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(ctx.TownRoot, rigName)))
+		if err != nil || settings.Sandbox == nil || !settings.Sandbox.Enabled {
+			continue
+		}
+		cfg := settings.Sandbox
+		if err := cfg.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", rigName, err))
+			continue
+		}
+		if err := sandbox.Supported(); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", rigName, err))
+			continue
+		}
+		if cfg.ToolV() == config.SandboxToolBwrap {
+			if _, err := exec.LookPath("bwrap"); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: sandbox tool is bwrap but bubblewrap is not installed", rigName))
+				continue
+			}
+		}
+		sandboxed[rigName] = cfg
+	}
+	if len(sandboxed) == 0 && len(problems) == 0 {
+		return &CheckResult{Name: c.Name(), Status: StatusOK, Message: "No rigs have the sandbox enabled"}
+	}
+
+	lister := c.sessionListerForTest
+	if lister == nil {
+		lister = session.NewBackend(ctx.TownRoot)
+	}
+	reg := c.registryForTest
+	if reg == nil {
+		reg = session.DefaultRegistry()
+	}
+	confined := c.confinedForTest
+	if confined == nil {
+		confined = sandbox.Confined
+	}
+	innerStage := c.innerStageForTest
+	if innerStage == nil {
+		innerStage = sandbox.IsInnerStage
+	}
+
+	sessions, err := lister.ListSessions()
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("could not list sessions: %v", err))
+	}
+	checked := 0
+	for _, sess := range sessions {
+		id, err := session.ParseSessionNameWithRegistry(sess, reg)
+		if err != nil || id.Role != session.RolePolecat {
+			continue
+		}
+		cfg, ok := sandboxed[id.Rig]
+		if !ok {
+			continue
+		}
+		checked++
+		st, err := sandbox.ReadStatus(ctx.TownRoot, sess)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: running unsandboxed (no sandbox status)", sess))
+			continue
+		}
+		if err := innerStage(st.PID, sandbox.PolicyPath(ctx.TownRoot, sess)); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: sandbox status does not match a running sandbox: %v", sess, err))
+			continue
+		}
+		if err := confined(st.PID, cfg.NetworkV() != config.SandboxNetworkHost); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: not confined: %v", sess, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("%d sandbox problem(s)", len(problems)),
+			Details: problems,
+			FixHint: "Restart affected polecats after fixing the rig's sandbox settings; run 'gt sandbox check <rig>' to test a rig",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: fmt.Sprintf("%d sandboxed rig(s), %d polecat(s) confined", len(sandboxed), checked),
+	}
+}
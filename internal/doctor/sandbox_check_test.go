@@ -0,0 +1,142 @@
+package doctor
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/sandbox"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// setupSandboxTown creates a town with one rig "gastown" (prefix gt) whose
+// settings carry the given sandbox block (none if empty).
+func setupSandboxTown(t *testing.T, sandboxJSON string) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	for _, dir := range []string{"mayor", "gastown/settings"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rigs := `{"version": 1, "rigs": {"gastown": {"beads": {"prefix": "gt"}}}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(rigs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{"type": "rig-settings", "version": 1}`
+	if sandboxJSON != "" {
+		settings = `{"type": "rig-settings", "version": 1, "sandbox": ` + sandboxJSON + `}`
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "gastown", "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+func writeSandboxStatus(t *testing.T, townRoot, sess string, pid int) {
+	t.Helper()
+	if err := os.MkdirAll(sandbox.StateDir(townRoot), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(sandbox.Status{Session: sess, PID: pid, Tool: "unshare", Network: "none"})
+	if err := os.WriteFile(sandbox.StatusPath(townRoot, sess), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestSandboxCheck(sessions []string, confined func(int, bool) error) *SandboxCheck {
+	reg := session.NewPrefixRegistry()
+	reg.Register("gt", "gastown")
+	check := NewSandboxCheck()
+	check.sessionListerForTest = &mockSessionLister{sessions: sessions}
+	check.registryForTest = reg
+	check.confinedForTest = confined
+	check.innerStageForTest = func(pid int, _ string) error {
+		if pid == 303 {
+			return errors.New("not the sandbox")
+		}
+		return nil
+	}
+	return check
+}
+
+func TestSandboxCheck_NoSandboxedRigs(t *testing.T) {
+	townRoot := setupSandboxTown(t, "")
+	check := newTestSandboxCheck([]string{"gt-furiosa"}, nil)
+
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusOK {
+		t.Errorf("expected OK, got %v: %s %v", result.Status, result.Message, result.Details)
+	}
+}
+
+func TestSandboxCheck_InvalidConfig(t *testing.T) {
+	townRoot := setupSandboxTown(t, `{"enabled": true, "tool": "docker"}`)
+	check := newTestSandboxCheck(nil, nil)
+
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusError {
+		t.Fatalf("expected error for unknown tool, got %v: %s", result.Status, result.Message)
+	}
+	if !strings.Contains(strings.Join(result.Details, "\n"), "docker") {
+		t.Errorf("details should name the bad tool: %v", result.Details)
+	}
+}
+
+func TestSandboxCheck_Polecats(t *testing.T) {
+	if err := sandbox.Supported(); err != nil {
+		t.Skip(err)
+	}
+	confinedPIDs := map[int]bool{101: true}
+	confined := func(pid int, network bool) error {
+		if !network {
+			t.Errorf("network namespace should be checked for network none")
+		}
+		if !confinedPIDs[pid] {
+			return errors.New("shares gt's mnt namespace")
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name     string
+		status   map[string]int // session -> pid in its status file
+		wantOK   bool
+		wantText string
+	}{
+		{name: "confined", status: map[string]int{"gt-furiosa": 101}, wantOK: true},
+		{name: "no status", status: nil, wantText: "gt-furiosa: running unsandboxed"},
+		{name: "escaped", status: map[string]int{"gt-furiosa": 202}, wantText: "gt-furiosa: not confined"},
+		{name: "forged pid", status: map[string]int{"gt-furiosa": 303}, wantText: "gt-furiosa: sandbox status does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			townRoot := setupSandboxTown(t, `{"enabled": true, "tool": "unshare", "network": "none"}`)
+			for sess, pid := range tt.status {
+				writeSandboxStatus(t, townRoot, sess, pid)
+			}
+			// The witness is not a polecat and isn't required to be sandboxed.
+			check := newTestSandboxCheck([]string{"gt-witness", "gt-furiosa"}, confined)
+
+			result := check.Run(&CheckContext{TownRoot: townRoot})
+			if tt.wantOK {
+				if result.Status != StatusOK {
+					t.Fatalf("expected OK, got %v: %s %v", result.Status, result.Message, result.Details)
+				}
+				if !strings.Contains(result.Message, "1 polecat(s) confined") {
+					t.Errorf("unexpected message: %s", result.Message)
+				}
+				return
+			}
+			if result.Status != StatusError {
+				t.Fatalf("expected error, got %v: %s", result.Status, result.Message)
+			}
+			if !strings.Contains(strings.Join(result.Details, "\n"), tt.wantText) {
+				t.Errorf("details %v should contain %q", result.Details, tt.wantText)
+			}
+		})
+	}
+}
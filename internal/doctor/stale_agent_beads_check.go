@@ -34,6 +34,7 @@ func NewStaleAgentBeadsCheck() *StaleAgentBeadsCheck {
 				CheckName:        "stale-agent-beads",
 				CheckDescription: "Detect agent beads for removed workers (crew and polecats)",
 				CheckCategory:    CategoryRig,
+				CheckDependsOn:   beadsPrerequisites,
 			},
 		},
 	}
@@ -4,6 +4,7 @@ package doctor
 import (
 	"fmt"
 	"io"
+	"slices"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/ui"
@@ -18,6 +19,7 @@ const (
 	CategoryConfig        = "Configuration"
 	CategoryCleanup       = "Cleanup"
 	CategoryHooks         = "Hooks"
+	CategoryCustom        = "Custom"
 )
 
 // CategoryOrder defines the display order for categories
@@ -29,6 +31,7 @@ var CategoryOrder = []string{
 	CategoryConfig,
 	CategoryCleanup,
 	CategoryHooks,
+	CategoryCustom,
 }
 
 // CheckStatus represents the result status of a health check.
@@ -88,6 +91,7 @@ type CheckResult struct {
 	Category string        // Category for grouping (e.g., CategoryCore)
 	Elapsed  time.Duration // How long the check took to run
 	Fixed    bool          // True if this check was auto-fixed
+	Skipped  bool          // True if not run because a prerequisite failed
 }
 
 // Check defines the interface for a health check.
@@ -218,19 +222,23 @@ func (r *Report) Print(w io.Writer, verbose bool, slowThreshold time.Duration) {
 
 	// Group checks by category
 	checksByCategory := make(map[string][]*CheckResult)
+	var extraCategories []string // user-defined categories, in first-seen order
 	for _, check := range r.Checks {
 		cat := check.Category
 		if cat == "" {
 			cat = "Other"
 		}
+		if _, seen := checksByCategory[cat]; !seen && cat != "Other" && !slices.Contains(CategoryOrder, cat) {
+			extraCategories = append(extraCategories, cat)
+		}
 		checksByCategory[cat] = append(checksByCategory[cat], check)
 	}
 
 	// Track warnings/errors for summary section
 	var warnings []*CheckResult
 
-	// Print checks by category in defined order
-	for _, category := range CategoryOrder {
+	// Print checks by category in defined order, then user-defined categories
+	for _, category := range slices.Concat(CategoryOrder, extraCategories) {
 		checks, exists := checksByCategory[category]
 		if !exists || len(checks) == 0 {
 			continue
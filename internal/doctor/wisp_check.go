@@ -25,6 +25,7 @@ func NewWispGCCheck() *WispGCCheck {
 				CheckName:        "wisp-gc",
 				CheckDescription: "Detect and clean orphaned wisps (>1h old)",
 				CheckCategory:    CategoryCleanup,
+				CheckDependsOn:   beadsPrerequisites,
 			},
 		},
 		threshold:     1 * time.Hour,
@@ -55,6 +55,7 @@ func NewTownConfigValidCheck() *TownConfigValidCheck {
 			CheckName:        "town-config-valid",
 			CheckDescription: "Check that mayor/town.json is valid with required fields",
 			CheckCategory:    CategoryCore,
+			CheckDependsOn:   []string{"town-config-exists"},
 		},
 	}
 }
@@ -192,6 +193,7 @@ func NewRigsRegistryValidCheck() *RigsRegistryValidCheck {
 				CheckName:        "rigs-registry-valid",
 				CheckDescription: "Check that registered rigs exist on disk",
 				CheckCategory:    CategoryCore,
+				CheckDependsOn:   []string{"rigs-registry-exists"},
 			},
 		},
 	}
@@ -66,6 +66,11 @@ type SessionInfo struct {
 	Created time.Time `json:"created,omitempty"`
 }
 
+// backendFor returns the backend running an existing dog session.
+func (m *SessionManager) backendFor(sessionID string) session.Backend {
+	return session.BackendForWith(m.tmux, m.townRoot, sessionID)
+}
+
 // SessionName generates the tmux session name for a dog.
 // Pattern: hq-dog-{name}
 // Dogs are town-level (managed by deacon), so they use the hq- prefix.
@@ -91,7 +96,7 @@ func (m *SessionManager) Start(dogName string, opts SessionStartOptions) error {
 	sessionID := m.SessionName(dogName)
 
 	// Kill any existing zombie session (tmux alive but agent dead).
-	_, err := session.KillExistingSession(m.tmux, sessionID, true)
+	_, err := session.KillExistingSession(m.backendFor(sessionID), sessionID, true)
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrSessionRunning, sessionID)
 	}
@@ -114,7 +119,7 @@ func (m *SessionManager) Start(dogName string, opts SessionStartOptions) error {
 
 	// Use unified session lifecycle.
 	theme := tmux.DogTheme()
-	_, err = session.StartSession(m.tmux, session.SessionConfig{
+	_, err = session.StartSession(session.NewBackendWith(m.tmux, m.townRoot), session.SessionConfig{
 		SessionID: sessionID,
 		WorkDir:   kennelDir,
 		Role:      "dog",
@@ -154,7 +159,8 @@ func (m *SessionManager) Start(dogName string, opts SessionStartOptions) error {
 func (m *SessionManager) Stop(dogName string, force bool) error {
 	sessionID := m.SessionName(dogName)
 
-	running, err := m.tmux.HasSession(sessionID)
+	t := m.backendFor(sessionID)
+	running, err := t.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -164,11 +170,11 @@ func (m *SessionManager) Stop(dogName string, force bool) error {
 
 	// Try graceful shutdown first
 	if !force {
-		_ = m.tmux.SendKeysRaw(sessionID, "C-c")
-		session.WaitForSessionExit(m.tmux, sessionID, constants.GracefulShutdownTimeout)
+		_ = t.SendKeysRaw(sessionID, "C-c")
+		session.WaitForSessionExit(t, sessionID, constants.GracefulShutdownTimeout)
 	}
 
-	if err := m.tmux.KillSessionWithProcesses(sessionID); err != nil {
+	if err := t.KillSessionWithProcesses(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
 
@@ -185,14 +191,15 @@ func (m *SessionManager) Stop(dogName string, force bool) error {
 // IsRunning checks if a dog session is active.
 func (m *SessionManager) IsRunning(dogName string) (bool, error) {
 	sessionID := m.SessionName(dogName)
-	return m.tmux.HasSession(sessionID)
+	return m.backendFor(sessionID).HasSession(sessionID)
 }
 
 // Status returns detailed status for a dog session.
 func (m *SessionManager) Status(dogName string) (*SessionInfo, error) {
 	sessionID := m.SessionName(dogName)
 
-	running, err := m.tmux.HasSession(sessionID)
+	t := m.backendFor(sessionID)
+	running, err := t.HasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -207,7 +214,7 @@ func (m *SessionManager) Status(dogName string) (*SessionInfo, error) {
 		return info, nil
 	}
 
-	tmuxInfo, err := m.tmux.GetSessionInfo(sessionID)
+	tmuxInfo, err := session.Info(t, sessionID)
 	if err != nil {
 		return info, nil
 	}
@@ -221,7 +228,8 @@ func (m *SessionManager) Status(dogName string) (*SessionInfo, error) {
 func (m *SessionManager) GetPane(dogName string) (string, error) {
 	sessionID := m.SessionName(dogName)
 
-	running, err := m.tmux.HasSession(sessionID)
+	t := m.backendFor(sessionID)
+	running, err := t.HasSession(sessionID)
 	if err != nil {
 		return "", fmt.Errorf("checking session: %w", err)
 	}
@@ -229,8 +237,12 @@ func (m *SessionManager) GetPane(dogName string) (string, error) {
 		return "", ErrSessionNotFound
 	}
 
-	// Get pane ID from session
-	pane, err := m.tmux.GetPaneID(sessionID)
+	// Get pane ID from session. Backends without panes address the
+	// session by name.
+	pane, err := session.PaneID(t, sessionID)
+	if errors.Is(err, errors.ErrUnsupported) {
+		return sessionID, nil
+	}
 	if err != nil {
 		return "", fmt.Errorf("getting pane: %w", err)
 	}
@@ -2,17 +2,26 @@
 //
 // The wl-commons database is the shared wanted board for the Wasteland federation.
 // Phase 1 (wild-west mode): direct writes to main branch via the local Dolt server.
+//
+// Wanted items come from other towns, so every statement that touches
+// commons data is prepared with the values passed as arguments, and rows
+// are scanned into typed fields.
 package doltserver
 
 import (
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
-	"strings"
+	"strconv"
+	"sync"
 	"time"
 )
 
@@ -27,25 +36,125 @@ type WLCommonsStore interface {
 	ClaimWanted(wantedID, rigHandle string) error
 	SubmitCompletion(completionID, wantedID, rigHandle, evidence string) error
 	QueryWanted(wantedID string) (*WantedItem, error)
+	QueryCompletion(wantedID string) (*Completion, error)
+	ReviewCompletion(stampID, wantedID, reviewer, verdict, notes string) (string, error)
+	QueryReputation(handle string) (*Reputation, error)
 }
 
-// WLCommons implements WLCommonsStore using the real Dolt server.
-type WLCommons struct{ townRoot string }
+// WLCommons implements WLCommonsStore using the real Dolt server. It talks
+// to the server over the MySQL protocol with prepared statements, opening a
+// connection pool on first use; call Close when done.
+type WLCommons struct {
+	townRoot string
+
+	mu sync.Mutex
+	db *sql.DB
+}
 
 // NewWLCommons creates a WLCommonsStore backed by the real Dolt server.
 func NewWLCommons(townRoot string) *WLCommons { return &WLCommons{townRoot: townRoot} }
 
-func (w *WLCommons) EnsureDB() error           { return EnsureWLCommons(w.townRoot) }
+func (w *WLCommons) EnsureDB() error               { return EnsureWLCommons(w.townRoot) }
 func (w *WLCommons) DatabaseExists(db string) bool { return DatabaseExists(w.townRoot, db) }
-func (w *WLCommons) InsertWanted(item *WantedItem) error { return InsertWanted(w.townRoot, item) }
-func (w *WLCommons) ClaimWanted(wantedID, rigHandle string) error {
-	return ClaimWanted(w.townRoot, wantedID, rigHandle)
+
+// Close releases the connection pool, if one was opened.
+func (w *WLCommons) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.db == nil {
+		return nil
+	}
+	err := w.db.Close()
+	w.db = nil
+	return err
 }
-func (w *WLCommons) SubmitCompletion(completionID, wantedID, rigHandle, evidence string) error {
-	return SubmitCompletion(w.townRoot, completionID, wantedID, rigHandle, evidence)
+
+// conn returns the connection pool, opening it on first use.
+func (w *WLCommons) conn() (*sql.DB, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.db != nil {
+		return w.db, nil
+	}
+	db, err := sql.Open("mysql", wlCommonsDSN(DefaultConfig(w.townRoot)))
+	if err != nil {
+		return nil, fmt.Errorf("opening wl-commons connection: %w", err)
+	}
+	db.SetMaxOpenConns(4)
+	db.SetConnMaxLifetime(time.Minute)
+	w.db = db
+	return db, nil
 }
-func (w *WLCommons) QueryWanted(wantedID string) (*WantedItem, error) {
-	return QueryWanted(w.townRoot, wantedID)
+
+// wlCommonsDSN returns the MySQL DSN for the wl-commons database.
+func wlCommonsDSN(config *Config) string {
+	return fmt.Sprintf("%s@tcp(%s)/%s?parseTime=true&timeout=5s&readTimeout=30s&writeTimeout=30s",
+		config.userDSN(), config.HostPort(), WLCommonsDB)
+}
+
+// errPrecondition marks a transaction that found the commons in the wrong
+// state; it is returned as is rather than retried.
+type errPrecondition struct{ msg string }
+
+func (e *errPrecondition) Error() string { return e.msg }
+
+func preconditionf(format string, args ...any) error {
+	return &errPrecondition{msg: fmt.Sprintf(format, args...)}
+}
+
+// inTx runs fn in a SQL transaction and records the result as a Dolt commit
+// with message. Dolt transactions are optimistic: when two rigs race (two
+// claims on one item), the loser's commit fails with a serialization error
+// and the whole transaction is retried, at which point its preconditions
+// no longer hold.
+func (w *WLCommons) inTx(message string, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	db, err := w.conn()
+	if err != nil {
+		return err
+	}
+
+	const maxRetries = 3
+	var lastErr error
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		lastErr = w.runTx(db, message, fn)
+		var pre *errPrecondition
+		if lastErr == nil || errors.As(lastErr, &pre) || !isDoltRetryableError(lastErr) {
+			return lastErr
+		}
+		time.Sleep(time.Duration(attempt) * 250 * time.Millisecond)
+	}
+	return fmt.Errorf("after %d retries: %w", maxRetries, lastErr)
+}
+
+func (w *WLCommons) runTx(db *sql.DB, message string, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+	// DOLT_COMMIT also commits the SQL transaction.
+	if _, err := tx.ExecContext(ctx, "CALL DOLT_COMMIT('-Am', ?)", message); err != nil {
+		return fmt.Errorf("dolt commit: %w", err)
+	}
+	return tx.Commit()
+}
+
+// query runs a read query with the standard timeout.
+func (w *WLCommons) query(fn func(ctx context.Context, db *sql.DB) error) error {
+	db, err := w.conn()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	return fn(ctx, db)
 }
 
 // WantedItem represents a row in the wanted table.
@@ -64,19 +173,60 @@ type WantedItem struct {
 	SandboxRequired bool
 }
 
-// isNothingToCommit returns true if the error indicates DOLT_COMMIT found no
-// changes to commit. This happens when a conditional UPDATE matched 0 rows,
-// leaving the working set unchanged.
-func isNothingToCommit(err error) bool {
-	return err != nil && strings.Contains(strings.ToLower(err.Error()), "nothing to commit")
-}
+// OpenClone serves the Dolt databases under dataDir on a private, local
+// sql-server and opens database db on it, so a throwaway clone of the
+// commons (gt wl browse) can be queried with prepared statements like the
+// store. stop closes the pool and shuts the server down.
+func OpenClone(doltPath, dataDir, db string) (conn *sql.DB, stop func(), err error) {
+	port := FindFreePort(23306)
+	if port == 0 {
+		return nil, nil, fmt.Errorf("no free port for the clone's sql-server")
+	}
+	cmd := exec.Command(doltPath, "sql-server", "--host", "127.0.0.1", "--port", strconv.Itoa(port), "--data-dir", dataDir)
+	cmd.Dir = dataDir
+	if err := cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("starting sql-server for clone: %w", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	stopServer := func() {
+		_ = cmd.Process.Kill()
+		<-exited
+	}
 
-// EscapeSQL escapes backslashes and single quotes for SQL string literals.
-// Dolt (MySQL-compatible) treats \ as an escape character, so a trailing
-// backslash in user input would escape the closing quote and break the query.
-func EscapeSQL(s string) string {
-	s = strings.ReplaceAll(s, `\`, `\\`)
-	return strings.ReplaceAll(s, "'", "''")
+	conn, err = sql.Open("mysql", fmt.Sprintf("root@tcp(127.0.0.1:%d)/%s?parseTime=true&timeout=5s&readTimeout=30s", port, db))
+	if err != nil {
+		stopServer()
+		return nil, nil, fmt.Errorf("opening clone connection: %w", err)
+	}
+	stop = func() {
+		_ = conn.Close()
+		stopServer()
+	}
+
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err = conn.PingContext(ctx)
+		cancel()
+		if err == nil {
+			return conn, stop, nil
+		}
+		select {
+		case <-exited:
+			stop()
+			return nil, nil, fmt.Errorf("sql-server for clone exited: %w", err)
+		default:
+		}
+		if time.Now().After(deadline) {
+			stop()
+			return nil, nil, fmt.Errorf("sql-server for clone not ready: %w", err)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
 }
 
 // GenerateWantedID generates a unique wanted item ID in the format w-<10-char-hash>.
@@ -222,224 +372,182 @@ func backtickKey() string {
 	return "`key`"
 }
 
-// InsertWanted inserts a new wanted item into the wl-commons database.
-func InsertWanted(townRoot string, item *WantedItem) error {
+// Prepared statements for the wanted board. User input is only ever
+// passed as arguments.
+const (
+	insertWantedSQL = `INSERT INTO wanted (id, title, description, project, type, priority, tags, posted_by, status, effort_level, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	claimWantedSQL = `UPDATE wanted SET claimed_by = ?, status = 'claimed', updated_at = NOW()
+WHERE id = ? AND status = 'open'`
+	submitWantedSQL = `UPDATE wanted SET status = 'in_review', evidence_url = ?, updated_at = NOW()
+WHERE id = ? AND status = 'claimed' AND claimed_by = ?
+AND NOT EXISTS (SELECT 1 FROM completions WHERE wanted_id = ?)`
+	insertCompletionSQL = `INSERT INTO completions (id, wanted_id, completed_by, evidence, completed_at)
+VALUES (?, ?, ?, ?, NOW())`
+	queryWantedSQL = `SELECT id, title, description, project, type, priority, tags, posted_by, claimed_by, status, effort_level, sandbox_required
+FROM wanted WHERE id = ?`
+)
+
+// insertWantedArgs validates item and returns the insertWantedSQL arguments.
+func insertWantedArgs(item *WantedItem, now time.Time) ([]any, error) {
 	if item.ID == "" {
-		return fmt.Errorf("wanted item ID cannot be empty")
+		return nil, fmt.Errorf("wanted item ID cannot be empty")
 	}
 	if item.Title == "" {
-		return fmt.Errorf("wanted item title cannot be empty")
+		return nil, fmt.Errorf("wanted item title cannot be empty")
 	}
 
-	now := time.Now().UTC().Format("2006-01-02 15:04:05")
-
-	tagsJSON := "NULL"
+	var tags any
 	if len(item.Tags) > 0 {
-		escaped := make([]string, len(item.Tags))
-		for i, t := range item.Tags {
-			t = strings.ReplaceAll(t, `\`, `\\`)
-			t = strings.ReplaceAll(t, `"`, `\"`)
-			t = strings.ReplaceAll(t, "'", "''")
-			escaped[i] = t
+		data, err := json.Marshal(item.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("encoding tags: %w", err)
 		}
-		tagsJSON = fmt.Sprintf("'[\"%s\"]'", strings.Join(escaped, `","`))
-	}
-
-	descField := "NULL"
-	if item.Description != "" {
-		descField = fmt.Sprintf("'%s'", EscapeSQL(item.Description))
-	}
-	projectField := "NULL"
-	if item.Project != "" {
-		projectField = fmt.Sprintf("'%s'", EscapeSQL(item.Project))
-	}
-	typeField := "NULL"
-	if item.Type != "" {
-		typeField = fmt.Sprintf("'%s'", EscapeSQL(item.Type))
+		tags = string(data)
 	}
-	postedByField := "NULL"
-	if item.PostedBy != "" {
-		postedByField = fmt.Sprintf("'%s'", EscapeSQL(item.PostedBy))
+	effort := item.EffortLevel
+	if effort == "" {
+		effort = "medium"
 	}
-	effortField := "'medium'"
-	if item.EffortLevel != "" {
-		effortField = fmt.Sprintf("'%s'", EscapeSQL(item.EffortLevel))
-	}
-	status := "'open'"
-	if item.Status != "" {
-		status = fmt.Sprintf("'%s'", EscapeSQL(item.Status))
+	status := item.Status
+	if status == "" {
+		status = "open"
 	}
+	ts := now.UTC().Format("2006-01-02 15:04:05")
 
-	script := fmt.Sprintf(`USE %s;
+	return []any{
+		item.ID, item.Title, nullString(item.Description), nullString(item.Project), nullString(item.Type),
+		item.Priority, tags, nullString(item.PostedBy), status, effort, ts, ts,
+	}, nil
+}
 
-INSERT INTO wanted (id, title, description, project, type, priority, tags, posted_by, status, effort_level, created_at, updated_at)
-VALUES ('%s', '%s', %s, %s, %s, %d, %s, %s, %s, %s, '%s', '%s');
+// nullString maps "" to SQL NULL.
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
 
-CALL DOLT_ADD('-A');
-CALL DOLT_COMMIT('-m', 'wl post: %s');
-`,
-		WLCommonsDB,
-		EscapeSQL(item.ID), EscapeSQL(item.Title), descField, projectField, typeField,
-		item.Priority, tagsJSON, postedByField, status, effortField,
-		now, now,
-		EscapeSQL(item.Title))
-
-	return doltSQLScriptWithRetry(townRoot, script)
+// InsertWanted inserts a new wanted item into the wl-commons database.
+func InsertWanted(townRoot string, item *WantedItem) error {
+	w := NewWLCommons(townRoot)
+	defer w.Close()
+	return w.InsertWanted(item)
+}
+
+// InsertWanted inserts a new wanted item into the wl-commons database.
+func (w *WLCommons) InsertWanted(item *WantedItem) error {
+	args, err := insertWantedArgs(item, time.Now())
+	if err != nil {
+		return err
+	}
+	return w.inTx("wl post: "+item.Title, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, insertWantedSQL, args...); err != nil {
+			return fmt.Errorf("inserting wanted item: %w", err)
+		}
+		return nil
+	})
 }
 
 // ClaimWanted updates a wanted item's status to claimed.
 // Returns an error if the item does not exist or is not open.
-//
-// Uses a single-script approach: UPDATE + DOLT_ADD + DOLT_COMMIT in one
-// invocation. If the UPDATE matches 0 rows (item not open), the working set
-// is unchanged and DOLT_COMMIT fails with "nothing to commit" — which we
-// map to a precondition error. This avoids splitting into separate sessions
-// and eliminates the need for DOLT_RESET on failure.
 func ClaimWanted(townRoot, wantedID, rigHandle string) error {
-	script := fmt.Sprintf(`USE %s;
-UPDATE wanted SET claimed_by='%s', status='claimed', updated_at=NOW()
-  WHERE id='%s' AND status='open';
-CALL DOLT_ADD('-A');
-CALL DOLT_COMMIT('-m', 'wl claim: %s');
-`, WLCommonsDB, EscapeSQL(rigHandle), EscapeSQL(wantedID), EscapeSQL(wantedID))
+	w := NewWLCommons(townRoot)
+	defer w.Close()
+	return w.ClaimWanted(wantedID, rigHandle)
+}
 
-	err := doltSQLScriptWithRetry(townRoot, script)
-	if err == nil {
+// ClaimWanted updates a wanted item's status to claimed.
+//
+// The conditional UPDATE runs in a transaction: if another rig claimed the
+// item first, either the UPDATE matches no rows or the racing commit fails
+// and the retry does, and the caller gets a precondition error.
+func (w *WLCommons) ClaimWanted(wantedID, rigHandle string) error {
+	err := w.inTx("wl claim: "+wantedID, func(ctx context.Context, tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, claimWantedSQL, rigHandle, wantedID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return preconditionf("wanted item %q is not open or does not exist", wantedID)
+		}
 		return nil
-	}
-	if isNothingToCommit(err) {
-		return fmt.Errorf("wanted item %q is not open or does not exist", wantedID)
+	})
+	var pre *errPrecondition
+	if err == nil || errors.As(err, &pre) {
+		return err
 	}
 	return fmt.Errorf("claim failed: %w", err)
 }
 
 // SubmitCompletion inserts a completion record and updates the wanted status.
-// The item must have status='claimed' AND claimed_by=rigHandle to prevent
-// completing an item claimed by another rig.
-//
-// Uses a single-script approach like ClaimWanted. The INSERT uses INSERT IGNORE
-// with a SELECT conditional on status='in_review' AND claimed_by AND NOT EXISTS
-// (prior completion). INSERT IGNORE makes the script idempotent on retry since
-// completions.id is a PRIMARY KEY. NOT EXISTS prevents multiple completions per
-// wanted item, ensuring the lifecycle is strictly post→claim→done.
 func SubmitCompletion(townRoot, completionID, wantedID, rigHandle, evidence string) error {
-	script := fmt.Sprintf(`USE %s;
-UPDATE wanted SET status='in_review', evidence_url='%s', updated_at=NOW()
-  WHERE id='%s' AND status='claimed' AND claimed_by='%s';
-INSERT IGNORE INTO completions (id, wanted_id, completed_by, evidence, completed_at)
-  SELECT '%s', '%s', '%s', '%s', NOW()
-  FROM wanted WHERE id='%s' AND status='in_review' AND claimed_by='%s'
-  AND NOT EXISTS (SELECT 1 FROM completions WHERE wanted_id='%s');
-CALL DOLT_ADD('-A');
-CALL DOLT_COMMIT('-m', 'wl done: %s');
-`,
-		WLCommonsDB,
-		EscapeSQL(evidence), EscapeSQL(wantedID), EscapeSQL(rigHandle),
-		EscapeSQL(completionID), EscapeSQL(wantedID), EscapeSQL(rigHandle), EscapeSQL(evidence),
-		EscapeSQL(wantedID), EscapeSQL(rigHandle), EscapeSQL(wantedID),
-		EscapeSQL(wantedID))
-
-	err := doltSQLScriptWithRetry(townRoot, script)
-	if err == nil {
-		return nil
-	}
-	if isNothingToCommit(err) {
-		return fmt.Errorf("wanted item %q is not claimed by %q or does not exist", wantedID, rigHandle)
-	}
-	return fmt.Errorf("completion failed: %w", err)
+	w := NewWLCommons(townRoot)
+	defer w.Close()
+	return w.SubmitCompletion(completionID, wantedID, rigHandle, evidence)
 }
 
-// QueryWanted fetches a wanted item by ID. Returns nil if not found.
-func QueryWanted(townRoot, wantedID string) (*WantedItem, error) {
-	query := fmt.Sprintf(`USE %s; SELECT id, title, status, COALESCE(claimed_by, '') as claimed_by FROM wanted WHERE id='%s';`,
-		WLCommonsDB, EscapeSQL(wantedID))
-
-	output, err := doltSQLQuery(townRoot, query)
-	if err != nil {
-		return nil, err
-	}
-
-	rows := parseSimpleCSV(output)
-	if len(rows) == 0 {
-		return nil, fmt.Errorf("wanted item %q not found", wantedID)
-	}
-
-	row := rows[0]
-	item := &WantedItem{
-		ID:        row["id"],
-		Title:     row["title"],
-		Status:    row["status"],
-		ClaimedBy: row["claimed_by"],
+// SubmitCompletion inserts a completion record and updates the wanted status.
+// The item must have status='claimed' AND claimed_by=rigHandle, and no
+// completion may already be pending for it, so the lifecycle is strictly
+// post→claim→done. Both writes happen in one transaction.
+func (w *WLCommons) SubmitCompletion(completionID, wantedID, rigHandle, evidence string) error {
+	err := w.inTx("wl done: "+wantedID, func(ctx context.Context, tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, submitWantedSQL, evidence, wantedID, rigHandle, wantedID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return preconditionf("wanted item %q is not claimed by %q or does not exist", wantedID, rigHandle)
+		}
+		_, err = tx.ExecContext(ctx, insertCompletionSQL, completionID, wantedID, rigHandle, evidence)
+		return err
+	})
+	var pre *errPrecondition
+	if err == nil || errors.As(err, &pre) {
+		return err
 	}
-	return item, nil
+	return fmt.Errorf("completion failed: %w", err)
 }
 
-// doltSQLQuery executes a SQL query and returns the raw CSV output.
-func doltSQLQuery(townRoot, query string) (string, error) {
-	config := DefaultConfig(townRoot)
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
-	cmd := buildDoltSQLCmd(ctx, config, "-r", "csv", "-q", query)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return "", fmt.Errorf("dolt sql query failed: %w (%s)", err, strings.TrimSpace(string(output)))
-	}
-	return string(output), nil
+// QueryWanted fetches a wanted item by ID.
+func QueryWanted(townRoot, wantedID string) (*WantedItem, error) {
+	w := NewWLCommons(townRoot)
+	defer w.Close()
+	return w.QueryWanted(wantedID)
 }
 
-// parseSimpleCSV parses CSV output from dolt sql into a slice of maps.
-// Handles quoted fields containing commas and escaped quotes.
-func parseSimpleCSV(data string) []map[string]string {
-	lines := strings.Split(strings.TrimSpace(data), "\n")
-	if len(lines) < 2 {
-		return nil
-	}
-
-	headers := parseCSVLine(lines[0])
-	var result []map[string]string
-
-	for _, line := range lines[1:] {
-		if line == "" {
-			continue
+// QueryWanted fetches a wanted item by ID.
+func (w *WLCommons) QueryWanted(wantedID string) (*WantedItem, error) {
+	var item *WantedItem
+	err := w.query(func(ctx context.Context, db *sql.DB) error {
+		var (
+			description, project, typ, tags, postedBy, claimedBy, effort sql.NullString
+			priority                                                     sql.NullInt64
+			sandbox                                                      sql.NullBool
+			it                                                           WantedItem
+		)
+		err := db.QueryRowContext(ctx, queryWantedSQL, wantedID).Scan(
+			&it.ID, &it.Title, &description, &project, &typ, &priority, &tags,
+			&postedBy, &claimedBy, &it.Status, &effort, &sandbox)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("wanted item %q not found", wantedID)
 		}
-		fields := parseCSVLine(line)
-		row := make(map[string]string)
-		for i, h := range headers {
-			if i < len(fields) {
-				row[strings.TrimSpace(h)] = strings.TrimSpace(fields[i])
-			}
+		if err != nil {
+			return fmt.Errorf("querying wanted item: %w", err)
 		}
-		result = append(result, row)
-	}
-	return result
-}
-
-// parseCSVLine parses a single CSV line, handling quoted fields.
-func parseCSVLine(line string) []string {
-	var fields []string
-	var field strings.Builder
-	inQuote := false
-
-	for i := 0; i < len(line); i++ {
-		ch := line[i]
-		switch {
-		case ch == '"' && !inQuote:
-			inQuote = true
-		case ch == '"' && inQuote:
-			if i+1 < len(line) && line[i+1] == '"' {
-				field.WriteByte('"')
-				i++
-			} else {
-				inQuote = false
+		it.Description, it.Project, it.Type = description.String, project.String, typ.String
+		it.PostedBy, it.ClaimedBy, it.EffortLevel = postedBy.String, claimedBy.String, effort.String
+		it.Priority = int(priority.Int64)
+		it.SandboxRequired = sandbox.Bool
+		if tags.Valid && tags.String != "" {
+			if err := json.Unmarshal([]byte(tags.String), &it.Tags); err != nil {
+				return fmt.Errorf("decoding tags of %s: %w", wantedID, err)
 			}
-		case ch == ',' && !inQuote:
-			fields = append(fields, field.String())
-			field.Reset()
-		default:
-			field.WriteByte(ch)
 		}
-	}
-	fields = append(fields, field.String())
-	return fields
+		item = &it
+		return nil
+	})
+	return item, err
 }
@@ -329,6 +329,121 @@ func wlCommonsConformance(t *testing.T, newStore func(t *testing.T) WLCommonsSto
 			t.Errorf("ClaimedBy = %q, want to contain %q", got.ClaimedBy, "specific-rig")
 		}
 	})
+
+	// inReview posts, claims and completes a wanted item for review tests.
+	inReview := func(t *testing.T, store WLCommonsStore, wantedID, poster, worker, effort string) {
+		t.Helper()
+		if err := store.InsertWanted(&WantedItem{ID: wantedID, Title: "Reviewable", PostedBy: poster, EffortLevel: effort}); err != nil {
+			t.Fatalf("InsertWanted() error: %v", err)
+		}
+		if err := store.ClaimWanted(wantedID, worker); err != nil {
+			t.Fatalf("ClaimWanted() error: %v", err)
+		}
+		if err := store.SubmitCompletion("c-"+wantedID, wantedID, worker, "commit abc123"); err != nil {
+			t.Fatalf("SubmitCompletion() error: %v", err)
+		}
+	}
+
+	t.Run("PosterApprovalValidates", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+		inReview(t, store, "w-rev01", "poster-rig", "worker-rig", "large")
+
+		outcome, err := store.ReviewCompletion("s-rev01", "w-rev01", "poster-rig", VerdictApprove, "tests pass")
+		if err != nil {
+			t.Fatalf("ReviewCompletion() error: %v", err)
+		}
+		if outcome != ReviewValidated {
+			t.Errorf("outcome = %q, want %q", outcome, ReviewValidated)
+		}
+		got, _ := store.QueryWanted("w-rev01")
+		if got.Status != "completed" {
+			t.Errorf("Status = %q, want completed", got.Status)
+		}
+		c, err := store.QueryCompletion("w-rev01")
+		if err != nil {
+			t.Fatalf("QueryCompletion() error: %v", err)
+		}
+		if c.ValidatedBy != "poster-rig" || len(c.Reviews) != 1 || c.Reviews[0].Notes != "tests pass" {
+			t.Errorf("completion = %+v", c)
+		}
+
+		rep, err := store.QueryReputation("worker-rig")
+		if err != nil {
+			t.Fatalf("QueryReputation() error: %v", err)
+		}
+		if rep.Score != EffortPoints("large") || len(rep.Validated) != 1 || rep.Pending != 0 {
+			t.Errorf("reputation = %+v", rep)
+		}
+	})
+
+	t.Run("QuorumOfIndependentRigs", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+		inReview(t, store, "w-rev02", "poster-rig", "worker-rig", "small")
+
+		outcome, err := store.ReviewCompletion("s-rev02a", "w-rev02", "peer-a", VerdictApprove, "")
+		if err != nil || outcome != ReviewPending {
+			t.Fatalf("first review = %q, %v; want pending", outcome, err)
+		}
+		if rep, _ := store.QueryReputation("worker-rig"); rep.Pending != 1 || rep.Score != 0 {
+			t.Errorf("reputation while pending = %+v", rep)
+		}
+		outcome, err = store.ReviewCompletion("s-rev02b", "w-rev02", "peer-b", VerdictApprove, "")
+		if err != nil || outcome != ReviewValidated {
+			t.Fatalf("second review = %q, %v; want validated", outcome, err)
+		}
+		c, _ := store.QueryCompletion("w-rev02")
+		if c.ValidatedBy != "peer-a,peer-b" {
+			t.Errorf("ValidatedBy = %q", c.ValidatedBy)
+		}
+	})
+
+	t.Run("RejectionReturnsToClaimant", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+		inReview(t, store, "w-rev03", "poster-rig", "worker-rig", "medium")
+
+		outcome, err := store.ReviewCompletion("s-rev03", "w-rev03", "poster-rig", VerdictReject, "tests fail")
+		if err != nil || outcome != ReviewRejected {
+			t.Fatalf("review = %q, %v; want rejected", outcome, err)
+		}
+		got, _ := store.QueryWanted("w-rev03")
+		if got.Status != "claimed" || got.ClaimedBy != "worker-rig" {
+			t.Errorf("after rejection: status %q claimed by %q", got.Status, got.ClaimedBy)
+		}
+		rep, _ := store.QueryReputation("worker-rig")
+		if rep.Rejected != 1 || rep.Score != 0 {
+			t.Errorf("reputation = %+v", rep)
+		}
+
+		// The claimant can fix the work and resubmit.
+		if err := store.SubmitCompletion("c-rev03b", "w-rev03", "worker-rig", "commit def456"); err != nil {
+			t.Errorf("resubmit after rejection: %v", err)
+		}
+	})
+
+	t.Run("InvalidReviews", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+		inReview(t, store, "w-rev04", "poster-rig", "worker-rig", "medium")
+
+		if _, err := store.ReviewCompletion("s-rev04a", "w-rev04", "worker-rig", VerdictApprove, ""); err == nil {
+			t.Error("self-review should fail")
+		}
+		if _, err := store.ReviewCompletion("s-rev04b", "w-rev04", "peer-a", "maybe", ""); err == nil {
+			t.Error("unknown verdict should fail")
+		}
+		if _, err := store.ReviewCompletion("s-rev04c", "w-rev04", "peer-a", VerdictApprove, ""); err != nil {
+			t.Fatalf("ReviewCompletion() error: %v", err)
+		}
+		if _, err := store.ReviewCompletion("s-rev04d", "w-rev04", "peer-a", VerdictReject, ""); err == nil {
+			t.Error("second review by the same rig should fail")
+		}
+		if _, err := store.ReviewCompletion("s-rev04e", "w-missing", "peer-a", VerdictApprove, ""); err == nil {
+			t.Error("review of an item with no completion should fail")
+		}
+	})
 }
 
 // TestFakeWLCommonsStore_Conformance runs the conformance suite against the fake.
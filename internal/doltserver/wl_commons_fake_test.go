@@ -2,6 +2,7 @@ package doltserver
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 )
 
@@ -12,18 +13,28 @@ type fakeWLCommonsStore struct {
 	items map[string]*WantedItem
 	dbOK  bool
 
+	// completions is keyed by wanted ID; rejected counts rejected
+	// completions per completer.
+	completions map[string]*Completion
+	rejected    map[string]int
+	quorum      int
+
 	// Error injection fields
 	EnsureDBErr         error
 	InsertWantedErr     error
 	ClaimWantedErr      error
 	SubmitCompletionErr error
 	QueryWantedErr      error
+	ReviewErr           error
 }
 
 func newFakeWLCommonsStore() *fakeWLCommonsStore {
 	return &fakeWLCommonsStore{
-		items: make(map[string]*WantedItem),
-		dbOK:  true,
+		items:       make(map[string]*WantedItem),
+		dbOK:        true,
+		completions: make(map[string]*Completion),
+		rejected:    make(map[string]int),
+		quorum:      DefaultValidationQuorum,
 	}
 }
 
@@ -104,6 +115,15 @@ func (f *fakeWLCommonsStore) SubmitCompletion(completionID, wantedID, rigHandle,
 		return fmt.Errorf("wanted item %q is not claimed by %q (claimed by %q)", wantedID, rigHandle, item.ClaimedBy)
 	}
 	item.Status = "in_review"
+	f.completions[wantedID] = &Completion{
+		ID:          completionID,
+		WantedID:    wantedID,
+		Title:       item.Title,
+		PostedBy:    item.PostedBy,
+		Effort:      item.EffortLevel,
+		CompletedBy: rigHandle,
+		Evidence:    evidence,
+	}
 	return nil
 }
 
@@ -122,3 +142,74 @@ func (f *fakeWLCommonsStore) QueryWanted(wantedID string) (*WantedItem, error) {
 	cp := *item
 	return &cp, nil
 }
+
+func (f *fakeWLCommonsStore) QueryCompletion(wantedID string) (*Completion, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.completionLocked(wantedID)
+}
+
+// completionLocked returns a copy of the completion for wantedID with the
+// item's current status. Callers hold f.mu.
+func (f *fakeWLCommonsStore) completionLocked(wantedID string) (*Completion, error) {
+	c, ok := f.completions[wantedID]
+	if !ok {
+		return nil, fmt.Errorf("no completion submitted for wanted item %q", wantedID)
+	}
+	cp := *c
+	cp.Reviews = append([]CompletionReview(nil), c.Reviews...)
+	cp.Status = f.items[wantedID].Status
+	return &cp, nil
+}
+
+func (f *fakeWLCommonsStore) ReviewCompletion(stampID, wantedID, reviewer, verdict, notes string) (string, error) {
+	if f.ReviewErr != nil {
+		return "", f.ReviewErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, err := f.completionLocked(wantedID)
+	if err != nil {
+		return "", err
+	}
+	if err := CheckReview(c, reviewer, verdict); err != nil {
+		return "", err
+	}
+	stored := f.completions[wantedID]
+	stored.Reviews = append(stored.Reviews, CompletionReview{StampID: stampID, Reviewer: reviewer, Verdict: verdict, Notes: notes})
+
+	outcome, deciders := ResolveReviews(stored, f.quorum)
+	item := f.items[wantedID]
+	switch outcome {
+	case ReviewValidated:
+		stored.ValidatedBy = strings.Join(deciders, ",")
+		item.Status = "completed"
+	case ReviewRejected:
+		delete(f.completions, wantedID)
+		f.rejected[stored.CompletedBy]++
+		item.Status = "claimed"
+	}
+	return outcome, nil
+}
+
+func (f *fakeWLCommonsStore) QueryReputation(handle string) (*Reputation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rep := &Reputation{Handle: handle, Rejected: f.rejected[handle]}
+	for wantedID, c := range f.completions {
+		if c.CompletedBy != handle {
+			continue
+		}
+		if c.ValidatedBy == "" {
+			rep.Pending++
+			continue
+		}
+		cp, _ := f.completionLocked(wantedID)
+		rep.Validated = append(rep.Validated, cp)
+		rep.Score += EffortPoints(c.Effort)
+	}
+	return rep, nil
+}
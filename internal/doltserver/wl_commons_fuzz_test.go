@@ -0,0 +1,208 @@
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// wlHostileSeeds are titles and descriptions another town might post to
+// break a commons that builds SQL from strings.
+var wlHostileSeeds = []string{
+	"Fix auth bug",
+	"'; DROP TABLE wanted; --",
+	`it\'s`,
+	`trailing backslash \`,
+	"multi\nline\r\ndescription",
+	`"quoted", with commas`,
+	"tab\tand \x1a control",
+	"USE other_db; CALL DOLT_RESET('--hard')",
+	"emoji 🚧 and ünïcödé",
+	"?",
+}
+
+func FuzzInsertWantedArgs(f *testing.F) {
+	for _, seed := range wlHostileSeeds {
+		f.Add(seed, seed, seed)
+	}
+	f.Fuzz(func(t *testing.T, title, description, tag string) {
+		item := &WantedItem{ID: "w-fuzz", Title: title, Description: description, Tags: []string{tag, title}}
+		args, err := insertWantedArgs(item, time.Now())
+		if title == "" {
+			if err == nil {
+				t.Fatal("insertWantedArgs accepted an empty title")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("insertWantedArgs(%q) error: %v", title, err)
+		}
+
+		// Every value travels as an argument, one per placeholder, unchanged.
+		if got, want := len(args), strings.Count(insertWantedSQL, "?"); got != want {
+			t.Fatalf("%d args for %d placeholders", got, want)
+		}
+		if args[1] != title {
+			t.Errorf("title arg = %q, want %q", args[1], title)
+		}
+		if description == "" && args[2] != nil || description != "" && args[2] != description {
+			t.Errorf("description arg = %#v, want %q", args[2], description)
+		}
+		var tags []string
+		if err := json.Unmarshal([]byte(args[6].(string)), &tags); err != nil {
+			t.Fatalf("tags arg %q is not JSON: %v", args[6], err)
+		}
+		// encoding/json replaces invalid UTF-8 with U+FFFD, so only valid
+		// strings are expected to round-trip exactly.
+		if !reflect.DeepEqual(tags, item.Tags) && utf8.ValidString(tag) && utf8.ValidString(title) {
+			t.Errorf("tags round trip = %q, want %q", tags, item.Tags)
+		}
+	})
+}
+
+func TestInsertWantedArgs_Defaults(t *testing.T) {
+	t.Parallel()
+	args, err := insertWantedArgs(&WantedItem{ID: "w-1", Title: "T"}, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// description, project, type, tags and posted_by are NULL; status and
+	// effort take their defaults.
+	for _, i := range []int{2, 3, 4, 6, 7} {
+		if args[i] != nil {
+			t.Errorf("args[%d] = %#v, want nil", i, args[i])
+		}
+	}
+	if args[8] != "open" || args[9] != "medium" || args[10] != "2026-03-01 12:00:00" {
+		t.Errorf("args = %#v", args)
+	}
+	if _, err := insertWantedArgs(&WantedItem{Title: "no id"}, time.Now()); err == nil {
+		t.Error("insertWantedArgs accepted an empty ID")
+	}
+}
+
+// recorder is a database/sql connector standing in for Dolt on the commons
+// query path. It records every statement with its arguments; each Exec
+// affects one row and each query returns no rows.
+type recorder struct {
+	mu    sync.Mutex
+	stmts []recordedStmt
+}
+
+type recordedStmt struct {
+	query string
+	args  []any
+}
+
+func (r *recorder) Connect(context.Context) (driver.Conn, error) { return &recorderConn{r}, nil }
+func (r *recorder) Driver() driver.Driver                        { return recorderDriver{} }
+
+func (r *recorder) record(query string, args []driver.NamedValue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st := recordedStmt{query: query}
+	for _, a := range args {
+		st.args = append(st.args, a.Value)
+	}
+	r.stmts = append(r.stmts, st)
+}
+
+type recorderDriver struct{}
+
+func (recorderDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("recorder: use sql.OpenDB")
+}
+
+type recorderConn struct{ r *recorder }
+
+func (c *recorderConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("recorder: Prepare not supported")
+}
+func (c *recorderConn) Close() error              { return nil }
+func (c *recorderConn) Begin() (driver.Tx, error) { return recorderTx{}, nil }
+
+func (c *recorderConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.r.record(query, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *recorderConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.r.record(query, args)
+	return recorderRows{}, nil
+}
+
+type recorderTx struct{}
+
+func (recorderTx) Commit() error   { return nil }
+func (recorderTx) Rollback() error { return nil }
+
+type recorderRows struct{}
+
+func (recorderRows) Columns() []string         { return nil }
+func (recorderRows) Close() error              { return nil }
+func (recorderRows) Next([]driver.Value) error { return io.EOF }
+
+// recordCommonsOps runs the commons operations with the given inputs against
+// a recorder and returns the statements issued.
+func recordCommonsOps(title, handle, text string) []recordedStmt {
+	rec := &recorder{}
+	w := &WLCommons{db: sql.OpenDB(rec)}
+	defer w.Close()
+
+	_ = w.InsertWanted(&WantedItem{ID: "w-fuzz", Title: title, Description: text, Project: text, Tags: []string{handle}, PostedBy: handle})
+	_ = w.ClaimWanted("w-fuzz", handle)
+	_ = w.SubmitCompletion("c-fuzz", "w-fuzz", handle, text)
+	_, _ = w.QueryWanted(title)
+	_, _ = w.QueryCompletion(title)
+	_, _ = w.ReviewCompletion("s-fuzz", "w-fuzz", handle, "accept", text)
+	_, _ = w.QueryReputation(handle)
+	return rec.stmts
+}
+
+// FuzzCommonsQueries drives hostile input through the store's statements
+// and checks it never reaches the SQL text: each statement is the one
+// issued for harmless input, and the values travel as arguments.
+func FuzzCommonsQueries(f *testing.F) {
+	for _, seed := range wlHostileSeeds {
+		f.Add(seed, seed, seed)
+	}
+	f.Fuzz(func(t *testing.T, title, handle, text string) {
+		stand := func(v string) string {
+			if v == "" {
+				return ""
+			}
+			return "x"
+		}
+		got := recordCommonsOps(title, handle, text)
+		want := recordCommonsOps(stand(title), stand(handle), stand(text))
+		if len(got) != len(want) {
+			t.Fatalf("%d statements for hostile input, %d for harmless input", len(got), len(want))
+		}
+		seen := make(map[any]bool)
+		for i := range got {
+			if got[i].query != want[i].query {
+				t.Fatalf("statement %d depends on input:\n%s\nvs\n%s", i, got[i].query, want[i].query)
+			}
+			if n := strings.Count(got[i].query, "?"); n != len(got[i].args) {
+				t.Fatalf("statement %d has %d args for %d placeholders:\n%s", i, len(got[i].args), n, got[i].query)
+			}
+			for _, a := range got[i].args {
+				seen[a] = true
+			}
+		}
+		for _, v := range []string{title, handle, text} {
+			if v != "" && !seen[v] {
+				t.Errorf("%q was not passed as an argument", v)
+			}
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"reflect"
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/testutil"
@@ -45,35 +46,129 @@ func TestRealWLCommonsStore_Conformance(t *testing.T) {
 	})
 }
 
-// TestIsNothingToCommit_RealDolt verifies that isNothingToCommit correctly detects
-// the error produced by DOLT_COMMIT when no changes exist. This pins the detection
-// logic against the actual Dolt error text so that Dolt upgrades that change the
-// message wording are caught immediately.
-func TestIsNothingToCommit_RealDolt(t *testing.T) {
+// TestRealWLCommons_HostileText writes hostile wanted items through the
+// real store and checks they read back byte for byte.
+func TestRealWLCommons_HostileText(t *testing.T) {
 	townRoot := startIsolatedDoltContainer(t)
+	store := NewWLCommons(townRoot)
+	defer store.Close()
+	if err := store.EnsureDB(); err != nil {
+		t.Fatalf("EnsureDB() error: %v", err)
+	}
 
-	// Create a database and table so we have a valid context for DOLT_COMMIT.
-	initScript := fmt.Sprintf(`CREATE DATABASE IF NOT EXISTS %s;
-USE %s;
-CREATE TABLE IF NOT EXISTS _ping (id INT PRIMARY KEY);
-CALL DOLT_ADD('-A');
-CALL DOLT_COMMIT('-m', 'init ping table');
-`, WLCommonsDB, WLCommonsDB)
-	if err := doltSQLScript(townRoot, initScript); err != nil {
-		t.Fatalf("init script error: %v", err)
+	for i, text := range wlHostileSeeds {
+		id := fmt.Sprintf("w-hostile%d", i)
+		item := &WantedItem{ID: id, Title: text, Description: text, PostedBy: "poster", Tags: []string{text, "sql"}}
+		if err := store.InsertWanted(item); err != nil {
+			t.Fatalf("InsertWanted(%q) error: %v", text, err)
+		}
+		if err := store.ClaimWanted(id, "claimer"); err != nil {
+			t.Fatalf("ClaimWanted(%q) error: %v", id, err)
+		}
+		got, err := store.QueryWanted(id)
+		if err != nil {
+			t.Fatalf("QueryWanted(%q) error: %v", id, err)
+		}
+		if got.Title != text || got.Description != text || got.ClaimedBy != "claimer" || !reflect.DeepEqual(got.Tags, item.Tags) {
+			t.Errorf("round trip of %q = %+v", text, got)
+		}
+	}
+}
+
+// TestRealWLCommons_ReputationLedger seeds completions and reviews and
+// checks the scores the ledger query computes from them.
+func TestRealWLCommons_ReputationLedger(t *testing.T) {
+	townRoot := startIsolatedDoltContainer(t)
+	store := NewWLCommons(townRoot)
+	defer store.Close()
+	if err := store.EnsureDB(); err != nil {
+		t.Fatalf("EnsureDB() error: %v", err)
 	}
 
-	// Now try to commit with no changes — this should produce the "nothing to commit" error.
-	noopScript := fmt.Sprintf(`USE %s;
-CALL DOLT_ADD('-A');
-CALL DOLT_COMMIT('-m', 'noop');
-`, WLCommonsDB)
-	err := doltSQLScript(townRoot, noopScript)
-	if err == nil {
-		t.Fatal("expected error from DOLT_COMMIT with no changes, got nil")
+	submit := func(wantedID, worker, effort string) {
+		t.Helper()
+		if err := store.InsertWanted(&WantedItem{ID: wantedID, Title: "Ledger " + wantedID, PostedBy: "poster", EffortLevel: effort}); err != nil {
+			t.Fatalf("InsertWanted(%s) error: %v", wantedID, err)
+		}
+		if err := store.ClaimWanted(wantedID, worker); err != nil {
+			t.Fatalf("ClaimWanted(%s) error: %v", wantedID, err)
+		}
+		if err := store.SubmitCompletion("c-"+wantedID, wantedID, worker, "commit abc123"); err != nil {
+			t.Fatalf("SubmitCompletion(%s) error: %v", wantedID, err)
+		}
+	}
+	review := func(stampID, wantedID, reviewer, verdict, want string) {
+		t.Helper()
+		outcome, err := store.ReviewCompletion(stampID, wantedID, reviewer, verdict, "")
+		if err != nil || outcome != want {
+			t.Fatalf("ReviewCompletion(%s by %s) = %q, %v; want %q", wantedID, reviewer, outcome, err, want)
+		}
+	}
+
+	// alice: an epic validated by the poster, a small one by quorum, and
+	// an unknown effort level (counted as medium) validated by the poster.
+	submit("w-led1", "alice", "epic")
+	review("s-led1", "w-led1", "poster", VerdictApprove, ReviewValidated)
+	submit("w-led2", "alice", "small")
+	review("s-led2a", "w-led2", "peer-a", VerdictApprove, ReviewPending)
+	review("s-led2b", "w-led2", "peer-b", VerdictApprove, ReviewValidated)
+	submit("w-led3", "alice", "mystery")
+	review("s-led3", "w-led3", "poster", VerdictApprove, ReviewValidated)
+
+	// bob: a completion nobody has reviewed yet.
+	submit("w-led4", "bob", "large")
+
+	// carol: one completion rejected by the poster, one with a single
+	// negative peer review (pending), and one validated.
+	submit("w-led5", "carol", "large")
+	review("s-led5", "w-led5", "poster", VerdictReject, ReviewRejected)
+	submit("w-led6", "carol", "epic")
+	review("s-led6", "w-led6", "peer-a", VerdictReject, ReviewPending)
+	submit("w-led7", "carol", "trivial")
+	review("s-led7", "w-led7", "poster", VerdictApprove, ReviewValidated)
+
+	db, err := store.conn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := db.Query(ReputationScoresSQL)
+	if err != nil {
+		t.Fatalf("ledger query: %v", err)
+	}
+	defer rows.Close()
+	scores := make(map[string]int)
+	for rows.Next() {
+		var handle string
+		var score int
+		if err := rows.Scan(&handle, &score); err != nil {
+			t.Fatal(err)
+		}
+		scores[handle] = score
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"alice": 8 + 2 + 3, "carol": 1}
+	if !reflect.DeepEqual(scores, want) {
+		t.Errorf("ledger scores = %v, want %v", scores, want)
 	}
 
-	if !isNothingToCommit(err) {
-		t.Errorf("isNothingToCommit(%q) = false, want true — Dolt error text may have changed", err)
+	for _, tt := range []struct {
+		handle                           string
+		score, validated, pending, rejct int
+	}{
+		{"alice", 13, 3, 0, 0},
+		{"bob", 0, 0, 1, 0},
+		{"carol", 1, 1, 1, 1},
+		{"nobody", 0, 0, 0, 0},
+	} {
+		rep, err := store.QueryReputation(tt.handle)
+		if err != nil {
+			t.Fatalf("QueryReputation(%s) error: %v", tt.handle, err)
+		}
+		if rep.Score != tt.score || len(rep.Validated) != tt.validated || rep.Pending != tt.pending || rep.Rejected != tt.rejct {
+			t.Errorf("QueryReputation(%s) = score %d, validated %d, pending %d, rejected %d; want %d, %d, %d, %d",
+				tt.handle, rep.Score, len(rep.Validated), rep.Pending, rep.Rejected, tt.score, tt.validated, tt.pending, tt.rejct)
+		}
 	}
 }
@@ -5,135 +5,6 @@ import (
 	"testing"
 )
 
-func TestParseSimpleCSV_Empty(t *testing.T) {
-	t.Parallel()
-	got := parseSimpleCSV("")
-	if got != nil {
-		t.Errorf("parseSimpleCSV(\"\") = %v, want nil", got)
-	}
-}
-
-func TestParseSimpleCSV_HeaderOnly(t *testing.T) {
-	t.Parallel()
-	got := parseSimpleCSV("id,title,status\n")
-	if got != nil {
-		t.Errorf("parseSimpleCSV(header-only) = %v, want nil", got)
-	}
-}
-
-func TestParseSimpleCSV_SingleRow(t *testing.T) {
-	t.Parallel()
-	data := "id,title,status\nw-abc,Fix bug,open"
-	got := parseSimpleCSV(data)
-	if len(got) != 1 {
-		t.Fatalf("got %d rows, want 1", len(got))
-	}
-	if got[0]["id"] != "w-abc" {
-		t.Errorf("id = %q, want %q", got[0]["id"], "w-abc")
-	}
-	if got[0]["title"] != "Fix bug" {
-		t.Errorf("title = %q, want %q", got[0]["title"], "Fix bug")
-	}
-	if got[0]["status"] != "open" {
-		t.Errorf("status = %q, want %q", got[0]["status"], "open")
-	}
-}
-
-func TestParseSimpleCSV_MultiRow(t *testing.T) {
-	t.Parallel()
-	data := "id,title\nw-1,First\nw-2,Second\nw-3,Third"
-	got := parseSimpleCSV(data)
-	if len(got) != 3 {
-		t.Fatalf("got %d rows, want 3", len(got))
-	}
-	for i, wantID := range []string{"w-1", "w-2", "w-3"} {
-		if got[i]["id"] != wantID {
-			t.Errorf("row %d id = %q, want %q", i, got[i]["id"], wantID)
-		}
-	}
-}
-
-func TestParseSimpleCSV_MissingFields(t *testing.T) {
-	t.Parallel()
-	data := "id,title,status\nw-abc,Fix bug"
-	got := parseSimpleCSV(data)
-	if len(got) != 1 {
-		t.Fatalf("got %d rows, want 1", len(got))
-	}
-	// Missing 'status' field should not be present in map
-	if _, ok := got[0]["status"]; ok {
-		t.Error("expected missing 'status' field to not be present")
-	}
-}
-
-func TestParseSimpleCSV_SkipsBlankLines(t *testing.T) {
-	t.Parallel()
-	data := "id,title\nw-1,First\n\nw-2,Second\n"
-	got := parseSimpleCSV(data)
-	if len(got) != 2 {
-		t.Fatalf("got %d rows, want 2", len(got))
-	}
-}
-
-func TestParseSimpleCSV_TrimsWhitespace(t *testing.T) {
-	t.Parallel()
-	data := " id , title \n w-abc , Fix bug "
-	got := parseSimpleCSV(data)
-	if len(got) != 1 {
-		t.Fatalf("got %d rows, want 1", len(got))
-	}
-	if got[0]["id"] != "w-abc" {
-		t.Errorf("id = %q, want %q", got[0]["id"], "w-abc")
-	}
-	if got[0]["title"] != "Fix bug" {
-		t.Errorf("title = %q, want %q", got[0]["title"], "Fix bug")
-	}
-}
-
-func TestEscapeSQL_SingleQuotes(t *testing.T) {
-	t.Parallel()
-	tests := []struct {
-		input string
-		want  string
-	}{
-		{"hello", "hello"},
-		{"it's", "it''s"},
-		{"", ""},
-		{"'; DROP TABLE wanted;--", "''; DROP TABLE wanted;--"},
-	}
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			t.Parallel()
-			got := EscapeSQL(tt.input)
-			if got != tt.want {
-				t.Errorf("EscapeSQL(%q) = %q, want %q", tt.input, got, tt.want)
-			}
-		})
-	}
-}
-
-func TestEscapeSQL_Backslashes(t *testing.T) {
-	t.Parallel()
-	tests := []struct {
-		input string
-		want  string
-	}{
-		{`path\to\file`, `path\\to\\file`},
-		{`trailing\`, `trailing\\`},
-		{`it\'s`, `it\\''s`},
-		{`no special`, `no special`},
-	}
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			t.Parallel()
-			got := EscapeSQL(tt.input)
-			if got != tt.want {
-				t.Errorf("EscapeSQL(%q) = %q, want %q", tt.input, got, tt.want)
-			}
-		})
-	}
-}
-
 func TestGenerateWantedID_Format(t *testing.T) {
 	t.Parallel()
 	id := GenerateWantedID("Test Title")
@@ -164,3 +35,31 @@ func TestGenerateWantedID_Uniqueness(t *testing.T) {
 		seen[id] = true
 	}
 }
+
+func TestResolveReviews(t *testing.T) {
+	t.Parallel()
+	review := func(reviewer, verdict string) CompletionReview {
+		return CompletionReview{Reviewer: reviewer, Verdict: verdict}
+	}
+	tests := []struct {
+		name     string
+		postedBy string
+		reviews  []CompletionReview
+		want     string
+		deciders string
+	}{
+		{"no reviews", "poster", nil, ReviewPending, ""},
+		{"one peer", "poster", []CompletionReview{review("a", VerdictApprove)}, ReviewPending, ""},
+		{"poster approves", "poster", []CompletionReview{review("a", VerdictReject), review("poster", VerdictApprove)}, ReviewValidated, "poster"},
+		{"poster rejects", "poster", []CompletionReview{review("a", VerdictApprove), review("poster", VerdictReject)}, ReviewRejected, "poster"},
+		{"peer quorum", "poster", []CompletionReview{review("a", VerdictApprove), review("b", VerdictReject), review("c", VerdictApprove)}, ReviewValidated, "a,c"},
+		{"peer rejection quorum", "", []CompletionReview{review("a", VerdictReject), review("b", VerdictReject)}, ReviewRejected, "a,b"},
+	}
+	for _, tt := range tests {
+		c := &Completion{PostedBy: tt.postedBy, CompletedBy: "worker", Reviews: tt.reviews}
+		got, deciders := ResolveReviews(c, 2)
+		if got != tt.want || strings.Join(deciders, ",") != tt.deciders {
+			t.Errorf("%s: ResolveReviews = %q %v, want %q %q", tt.name, got, deciders, tt.want, tt.deciders)
+		}
+	}
+}
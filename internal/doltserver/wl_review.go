@@ -0,0 +1,376 @@
+// Package doltserver - wl_review.go provides completion review and the
+// reputation ledger for the wl-commons (Wasteland) database.
+//
+// A completion submitted by gt wl done sits in review until it is decided:
+// the posting rig's verdict decides it outright, otherwise a quorum of
+// independent rigs must agree. Each review is a stamp (author = reviewer,
+// subject = completer, context = the completion), so the commons keeps the
+// full review history. A validated completion earns the completer the
+// effort points of the wanted item; a rejected one is deleted and the item
+// goes back to claimed so the completer can fix it and resubmit.
+package doltserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Review verdicts.
+const (
+	VerdictApprove = "approve"
+	VerdictReject  = "reject"
+)
+
+// Review outcomes returned by ReviewCompletion.
+const (
+	ReviewPending   = "pending"
+	ReviewValidated = "validated"
+	ReviewRejected  = "rejected"
+)
+
+// DefaultValidationQuorum is the number of independent approvals (or
+// rejections) that decide a completion when the posting rig has not
+// reviewed it. A commons overrides it with the validation_quorum _meta key.
+const DefaultValidationQuorum = 2
+
+// reviewContextType is the stamps.context_type of completion reviews.
+const reviewContextType = "completion"
+
+// effortPoints is the reputation earned per validated completion, by the
+// wanted item's effort level.
+var effortPoints = map[string]int{
+	"trivial": 1,
+	"small":   2,
+	"medium":  3,
+	"large":   5,
+	"epic":    8,
+}
+
+// EffortPoints returns the reputation a validated completion of the given
+// effort level is worth. Unknown levels count as medium.
+func EffortPoints(effort string) int {
+	if p, ok := effortPoints[effort]; ok {
+		return p
+	}
+	return effortPoints["medium"]
+}
+
+// Completion is a completion record joined with its wanted item and reviews.
+type Completion struct {
+	ID          string
+	WantedID    string
+	Title       string
+	PostedBy    string
+	Status      string // the wanted item's status
+	Effort      string
+	CompletedBy string
+	Evidence    string
+	ValidatedBy string
+	Reviews     []CompletionReview
+}
+
+// CompletionReview is one rig's verdict on a completion.
+type CompletionReview struct {
+	StampID  string
+	Reviewer string
+	Verdict  string
+	Notes    string
+}
+
+// Reputation summarizes a rig's standing from its completions.
+type Reputation struct {
+	Handle string
+	// Score is the sum of EffortPoints over validated completions.
+	Score     int
+	Pending   int
+	Rejected  int
+	Validated []*Completion
+}
+
+// CheckReview reports why reviewer may not review c with verdict, or nil.
+func CheckReview(c *Completion, reviewer, verdict string) error {
+	if verdict != VerdictApprove && verdict != VerdictReject {
+		return fmt.Errorf("invalid verdict %q: want %s or %s", verdict, VerdictApprove, VerdictReject)
+	}
+	if c.Status != "in_review" {
+		return fmt.Errorf("wanted item %q is not in review (status: %s)", c.WantedID, c.Status)
+	}
+	if reviewer == c.CompletedBy {
+		return fmt.Errorf("%s cannot review its own completion", reviewer)
+	}
+	for _, r := range c.Reviews {
+		if r.Reviewer == reviewer {
+			return fmt.Errorf("%s has already reviewed completion %s (%s)", reviewer, c.ID, r.Verdict)
+		}
+	}
+	return nil
+}
+
+// ResolveReviews decides a completion from its reviews. The posting rig's
+// verdict is final; otherwise quorum distinct reviewers must agree. It
+// returns the outcome and, for a decided completion, the deciding rigs.
+func ResolveReviews(c *Completion, quorum int) (string, []string) {
+	if quorum < 1 {
+		quorum = 1
+	}
+	var approvers, rejecters []string
+	for _, r := range c.Reviews {
+		if c.PostedBy != "" && r.Reviewer == c.PostedBy {
+			if r.Verdict == VerdictApprove {
+				return ReviewValidated, []string{r.Reviewer}
+			}
+			return ReviewRejected, []string{r.Reviewer}
+		}
+		if r.Verdict == VerdictApprove {
+			approvers = append(approvers, r.Reviewer)
+		} else {
+			rejecters = append(rejecters, r.Reviewer)
+		}
+	}
+	switch {
+	case len(approvers) >= quorum:
+		return ReviewValidated, approvers
+	case len(rejecters) >= quorum:
+		return ReviewRejected, rejecters
+	}
+	return ReviewPending, nil
+}
+
+// ReputationScoresSQL selects (handle, score) for every rig with validated
+// completions. It runs against any wl-commons clone, without a USE.
+var ReputationScoresSQL = func() string {
+	levels := make([]string, 0, len(effortPoints))
+	for level := range effortPoints {
+		levels = append(levels, level)
+	}
+	sort.Strings(levels)
+	var cases strings.Builder
+	for _, level := range levels {
+		fmt.Fprintf(&cases, " WHEN '%s' THEN %d", level, effortPoints[level])
+	}
+	return fmt.Sprintf(`SELECT c.completed_by AS handle, SUM(CASE w.effort_level%s ELSE %d END) AS score `+
+		`FROM completions c JOIN wanted w ON w.id = c.wanted_id `+
+		`WHERE c.validated_at IS NOT NULL GROUP BY c.completed_by`,
+		cases.String(), EffortPoints(""))
+}()
+
+// Prepared statements for completion review.
+const (
+	queryCompletionSQL = `SELECT c.id, c.wanted_id, w.title, w.posted_by, w.status, w.effort_level, c.completed_by, c.evidence, c.validated_by
+FROM completions c JOIN wanted w ON w.id = c.wanted_id`
+	queryReviewsSQL = `SELECT id, author, JSON_UNQUOTE(JSON_EXTRACT(valence, '$.verdict')), message
+FROM stamps WHERE context_type = ? AND context_id = ? ORDER BY created_at, id`
+	queryQuorumSQL  = "SELECT value FROM _meta WHERE `key` = 'validation_quorum'"
+	insertReviewSQL = `INSERT INTO stamps (id, author, subject, valence, confidence, severity, context_id, context_type, message, created_at)
+VALUES (?, ?, ?, JSON_OBJECT('verdict', ?), 1, 'leaf', ?, ?, ?, NOW())`
+	validateCompletionSQL = `UPDATE completions SET validated_by = ?, validated_at = NOW() WHERE id = ?`
+	completeWantedSQL     = `UPDATE wanted SET status = 'completed', updated_at = NOW() WHERE id = ? AND status = 'in_review'`
+	deleteCompletionSQL   = `DELETE FROM completions WHERE id = ?`
+	reopenWantedSQL       = `UPDATE wanted SET status = 'claimed', evidence_url = NULL, updated_at = NOW() WHERE id = ? AND status = 'in_review'`
+	queryPendingSQL       = `SELECT COUNT(*) FROM completions WHERE completed_by = ? AND validated_at IS NULL`
+	// Rejected completions are deleted, so they are the reviewed
+	// completions that no longer exist.
+	queryRejectedSQL = `SELECT COUNT(DISTINCT s.context_id) FROM stamps s
+WHERE s.subject = ? AND s.context_type = ? AND NOT EXISTS (SELECT 1 FROM completions c WHERE c.id = s.context_id)`
+)
+
+// rowScanner is the Scan method shared by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanCompletion scans a queryCompletionSQL row.
+func scanCompletion(row rowScanner) (*Completion, error) {
+	var (
+		c                                                      Completion
+		title, postedBy, status, effort, evidence, validatedBy sql.NullString
+		completedBy                                            sql.NullString
+	)
+	if err := row.Scan(&c.ID, &c.WantedID, &title, &postedBy, &status, &effort, &completedBy, &evidence, &validatedBy); err != nil {
+		return nil, err
+	}
+	c.Title, c.PostedBy, c.Status, c.Effort = title.String, postedBy.String, status.String, effort.String
+	c.CompletedBy, c.Evidence, c.ValidatedBy = completedBy.String, evidence.String, validatedBy.String
+	return &c, nil
+}
+
+// queryer is the QueryContext method shared by *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// queryReviews reads the reviews of a completion in the order they were made.
+func queryReviews(ctx context.Context, q queryer, completionID string) ([]CompletionReview, error) {
+	rows, err := q.QueryContext(ctx, queryReviewsSQL, reviewContextType, completionID)
+	if err != nil {
+		return nil, fmt.Errorf("querying reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []CompletionReview
+	for rows.Next() {
+		var r CompletionReview
+		var verdict, notes sql.NullString
+		if err := rows.Scan(&r.StampID, &r.Reviewer, &verdict, &notes); err != nil {
+			return nil, fmt.Errorf("reading review: %w", err)
+		}
+		r.Verdict, r.Notes = verdict.String, notes.String
+		reviews = append(reviews, r)
+	}
+	return reviews, rows.Err()
+}
+
+// QueryCompletion fetches the completion submitted for a wanted item,
+// with its reviews.
+func QueryCompletion(townRoot, wantedID string) (*Completion, error) {
+	w := NewWLCommons(townRoot)
+	defer w.Close()
+	return w.QueryCompletion(wantedID)
+}
+
+// QueryCompletion fetches the completion submitted for a wanted item,
+// with its reviews.
+func (w *WLCommons) QueryCompletion(wantedID string) (*Completion, error) {
+	var c *Completion
+	err := w.query(func(ctx context.Context, db *sql.DB) error {
+		var err error
+		c, err = scanCompletion(db.QueryRowContext(ctx, queryCompletionSQL+" WHERE c.wanted_id = ?", wantedID))
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("no completion submitted for wanted item %q", wantedID)
+		}
+		if err != nil {
+			return fmt.Errorf("querying completion: %w", err)
+		}
+
+		c.Reviews, err = queryReviews(ctx, db, c.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// validationQuorum reads the commons' validation_quorum, falling back to
+// DefaultValidationQuorum.
+func (w *WLCommons) validationQuorum() int {
+	var value sql.NullString
+	if err := w.query(func(ctx context.Context, db *sql.DB) error {
+		return db.QueryRowContext(ctx, queryQuorumSQL).Scan(&value)
+	}); err != nil {
+		return DefaultValidationQuorum
+	}
+	n, err := strconv.Atoi(value.String)
+	if err != nil || n < 1 {
+		return DefaultValidationQuorum
+	}
+	return n
+}
+
+// ReviewCompletion records reviewer's verdict on the completion of a wanted
+// item and applies the outcome.
+func ReviewCompletion(townRoot, stampID, wantedID, reviewer, verdict, notes string) (string, error) {
+	w := NewWLCommons(townRoot)
+	defer w.Close()
+	return w.ReviewCompletion(stampID, wantedID, reviewer, verdict, notes)
+}
+
+// ReviewCompletion records reviewer's verdict on the completion of a wanted
+// item and applies the outcome: a validated completion is stamped with its
+// validators and the item marked completed; a rejected one is deleted and
+// the item returned to its claimant. The reviews are re-read inside the
+// transaction so two reviewers racing to decide one completion cannot both
+// apply an outcome.
+func (w *WLCommons) ReviewCompletion(stampID, wantedID, reviewer, verdict, notes string) (string, error) {
+	quorum := w.validationQuorum()
+	var outcome string
+	err := w.inTx(fmt.Sprintf("wl review: %s %s by %s", wantedID, verdict, reviewer), func(ctx context.Context, tx *sql.Tx) error {
+		c, err := scanCompletion(tx.QueryRowContext(ctx, queryCompletionSQL+" WHERE c.wanted_id = ?", wantedID))
+		if errors.Is(err, sql.ErrNoRows) {
+			return preconditionf("no completion submitted for wanted item %q", wantedID)
+		}
+		if err != nil {
+			return fmt.Errorf("querying completion: %w", err)
+		}
+		if c.Reviews, err = queryReviews(ctx, tx, c.ID); err != nil {
+			return err
+		}
+
+		if err := CheckReview(c, reviewer, verdict); err != nil {
+			return preconditionf("%s", err)
+		}
+		c.Reviews = append(c.Reviews, CompletionReview{StampID: stampID, Reviewer: reviewer, Verdict: verdict, Notes: notes})
+		var deciders []string
+		outcome, deciders = ResolveReviews(c, quorum)
+
+		if _, err := tx.ExecContext(ctx, insertReviewSQL, stampID, reviewer, c.CompletedBy, verdict, c.ID, reviewContextType, nullString(notes)); err != nil {
+			return fmt.Errorf("recording review: %w", err)
+		}
+		switch outcome {
+		case ReviewValidated:
+			if _, err := tx.ExecContext(ctx, validateCompletionSQL, strings.Join(deciders, ","), c.ID); err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, completeWantedSQL, wantedID)
+		case ReviewRejected:
+			if _, err := tx.ExecContext(ctx, deleteCompletionSQL, c.ID); err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, reopenWantedSQL, wantedID)
+		}
+		return err
+	})
+	var pre *errPrecondition
+	if err != nil && !errors.As(err, &pre) {
+		return "", fmt.Errorf("review failed: %w", err)
+	}
+	return outcome, err
+}
+
+// QueryReputation computes a rig's reputation from the commons.
+func QueryReputation(townRoot, handle string) (*Reputation, error) {
+	w := NewWLCommons(townRoot)
+	defer w.Close()
+	return w.QueryReputation(handle)
+}
+
+// QueryReputation computes a rig's reputation from the commons.
+func (w *WLCommons) QueryReputation(handle string) (*Reputation, error) {
+	rep := &Reputation{Handle: handle}
+	err := w.query(func(ctx context.Context, db *sql.DB) error {
+		rows, err := db.QueryContext(ctx, queryCompletionSQL+
+			" WHERE c.completed_by = ? AND c.validated_at IS NOT NULL ORDER BY c.validated_at DESC", handle)
+		if err != nil {
+			return fmt.Errorf("querying completions: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			c, err := scanCompletion(rows)
+			if err != nil {
+				return fmt.Errorf("reading completion: %w", err)
+			}
+			rep.Validated = append(rep.Validated, c)
+			rep.Score += EffortPoints(c.Effort)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if err := db.QueryRowContext(ctx, queryPendingSQL, handle).Scan(&rep.Pending); err != nil {
+			return fmt.Errorf("counting pending completions: %w", err)
+		}
+		if err := db.QueryRowContext(ctx, queryRejectedSQL, handle, reviewContextType).Scan(&rep.Rejected); err != nil {
+			return fmt.Errorf("counting rejected completions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
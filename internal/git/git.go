@@ -83,6 +83,62 @@ func (g *Git) IsRepo() bool {
 	return err == nil
 }
 
+// TopLevel returns the root of the work tree containing workDir.
+func (g *Git) TopLevel() (string, error) {
+	return g.run("rev-parse", "--show-toplevel")
+}
+
+// Exclude adds paths, relative to the work tree root, to the repository's
+// info/exclude. Unlike .gitignore it is not part of the project, and all
+// worktrees of a repository share it. Paths already listed are skipped.
+func (g *Git) Exclude(paths ...string) error {
+	common, err := g.run("rev-parse", "--git-common-dir")
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(g.workDir, common)
+	}
+	excludePath := filepath.Join(common, "info", "exclude")
+
+	content, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	listed := make(map[string]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		listed[strings.TrimSpace(line)] = true
+	}
+	var add strings.Builder
+	for _, p := range paths {
+		// Anchor at the work tree root.
+		entry := "/" + filepath.ToSlash(p)
+		if !listed[entry] {
+			listed[entry] = true
+			add.WriteString(entry + "\n")
+		}
+	}
+	if add.Len() == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		if _, err := f.WriteString("\n"); err != nil {
+			return err
+		}
+	}
+	_, err = f.WriteString(add.String())
+	return err
+}
+
 // run executes a git command and returns stdout.
 func (g *Git) run(args ...string) (string, error) {
 	// If gitDir is set (bare repo), prepend --git-dir flag
@@ -375,6 +431,13 @@ func (g *Git) CheckoutNewBranch(branch, startPoint string) error {
 	return err
 }
 
+// CheckoutReset creates or resets branch to startPoint and checks it out.
+// Equivalent to: git checkout -B <branch> <startPoint>
+func (g *Git) CheckoutReset(branch, startPoint string) error {
+	_, err := g.run("checkout", "-B", branch, startPoint)
+	return err
+}
+
 // Fetch fetches from the remote.
 func (g *Git) Fetch(remote string) error {
 	_, err := g.run("fetch", remote)
@@ -869,6 +932,23 @@ func (g *Git) GetConflictingFiles() ([]string, error) {
 	return result, nil
 }
 
+// ChangedFiles returns the files changed on head since it diverged from base
+// (git diff --name-only base...head).
+func (g *Git) ChangedFiles(base, head string) ([]string, error) {
+	out, err := g.run("diff", "--name-only", base+"..."+head)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, f := range strings.Split(out, "\n") {
+		if f != "" {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
 // AbortRebase aborts a rebase in progress.
 func (g *Git) AbortRebase() error {
 	_, err := g.run("rebase", "--abort")
@@ -61,6 +61,46 @@ func TestIsRepo(t *testing.T) {
 	}
 }
 
+func TestExclude(t *testing.T) {
+	dir := initTestRepo(t)
+	hookFile := filepath.Join(dir, ".github", "hooks", "gastown.json")
+	if err := os.MkdirAll(filepath.Dir(hookFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hookFile, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A linked worktree shares the main repository's info/exclude.
+	wt := filepath.Join(t.TempDir(), "wt")
+	cmd := exec.Command("git", "worktree", "add", "-b", "wt", wt)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add: %v\n%s", err, out)
+	}
+	g := NewGit(wt)
+	for i := 0; i < 2; i++ {
+		if err := g.Exclude(".github/hooks/gastown.json"); err != nil {
+			t.Fatalf("Exclude: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".git", "info", "exclude"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "/.github/hooks/gastown.json\n"); n != 1 {
+		t.Errorf("exclude lists the path %d times:\n%s", n, data)
+	}
+	status, err := NewGit(dir).run("status", "--porcelain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(status, ".github") {
+		t.Errorf("hook file is not ignored: %q", status)
+	}
+}
+
 func TestCloneWithReferenceCreatesAlternates(t *testing.T) {
 	tmp := t.TempDir()
 	src := filepath.Join(tmp, "src")
@@ -298,6 +338,47 @@ func TestCheckoutNewBranch(t *testing.T) {
 	}
 }
 
+func TestChangedFiles(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	base, err := g.CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch: %v", err)
+	}
+	if err := g.CheckoutNewBranch("feature", "HEAD"); err != nil {
+		t.Fatalf("CheckoutNewBranch: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "api"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "api", "handler.go"), []byte("package api\n"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := g.Add("."); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("add handler"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	files, err := g.ChangedFiles(base, "feature")
+	if err != nil {
+		t.Fatalf("ChangedFiles: %v", err)
+	}
+	if len(files) != 1 || files[0] != "api/handler.go" {
+		t.Errorf("ChangedFiles = %v, want [api/handler.go]", files)
+	}
+
+	files, err = g.ChangedFiles("feature", base)
+	if err != nil {
+		t.Fatalf("ChangedFiles reversed: %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("ChangedFiles reversed = %v, want none", files)
+	}
+}
+
 func TestNotARepo(t *testing.T) {
 	dir := t.TempDir() // Empty dir, not a git repo
 	g := NewGit(dir)
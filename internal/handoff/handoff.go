@@ -0,0 +1,386 @@
+// Package handoff provides structured handoff documents.
+//
+// When a session hands off, it writes a typed record of where things stand
+// (hooked work, molecule step, git state, commands and files in flight, open
+// questions and next actions) instead of relying on the prose of a mail to
+// itself. Most fields are captured automatically from the checkpoint
+// capture, git and the agent's conversation log; the rest come from the
+// handing-off agent. The successor's gt prime renders the document, and
+// gt resume --diff compares it with the workspace as it is now.
+package handoff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/checkpoint"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// maxRecentCommands bounds the commands recorded from the conversation log.
+const maxRecentCommands = 5
+
+// recentToolCalls is how many of the last tool calls are scanned for
+// commands and edited files.
+const recentToolCalls = 50
+
+// Document is a structured handoff record.
+type Document struct {
+	// WrittenAt is when the handoff was written.
+	WrittenAt time.Time `json:"written_at"`
+
+	// Agent is the identity that wrote the handoff (e.g. gastown/crew/max).
+	Agent string `json:"agent,omitempty"`
+
+	// Session is the tmux session that handed off.
+	Session string `json:"session,omitempty"`
+
+	// SessionID is the agent runtime's session ID, for gt seance.
+	SessionID string `json:"session_id,omitempty"`
+
+	// Reason is why the session handed off (e.g. compaction, idle).
+	Reason string `json:"reason,omitempty"`
+
+	// HookedBead is the bead on the agent's hook.
+	HookedBead string `json:"hooked_bead,omitempty"`
+
+	// Molecule, Step and StepTitle locate the molecule step in progress.
+	Molecule  string `json:"molecule,omitempty"`
+	Step      string `json:"step,omitempty"`
+	StepTitle string `json:"step_title,omitempty"`
+
+	// Branch and Commit are the git state at handoff.
+	Branch string `json:"branch,omitempty"`
+	Commit string `json:"commit,omitempty"`
+
+	// FilesInFlight are files with uncommitted changes or edited recently.
+	FilesInFlight []string `json:"files_in_flight,omitempty"`
+
+	// Commands are the most recent commands the agent ran, newest last.
+	Commands []string `json:"commands,omitempty"`
+
+	// OpenQuestions are unresolved questions for the successor.
+	OpenQuestions []string `json:"open_questions,omitempty"`
+
+	// NextActions are what the successor should do next, in order.
+	NextActions []string `json:"next_actions,omitempty"`
+
+	// Notes is the free-form message that accompanied the handoff.
+	Notes string `json:"notes,omitempty"`
+
+	// ConsumedAt is when a successor's gt prime first rendered the handoff;
+	// zero until then.
+	ConsumedAt time.Time `json:"consumed_at,omitempty"`
+
+	// ConsumedBy is the runtime session ID of that successor, if known.
+	ConsumedBy string `json:"consumed_by,omitempty"`
+}
+
+// Path returns the handoff document path for a working directory.
+func Path(workDir string) string {
+	return filepath.Join(workDir, constants.DirRuntime, constants.FileHandoffDocument)
+}
+
+// Read loads the handoff document from a working directory.
+// Returns nil, nil if there is none.
+func Read(workDir string) (*Document, error) {
+	data, err := os.ReadFile(Path(workDir)) //nolint:gosec // G304: path is constructed from trusted workDir
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading handoff document: %w", err)
+	}
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing handoff document: %w", err)
+	}
+	return &doc, nil
+}
+
+// Write saves the handoff document to a working directory, replacing any
+// earlier one.
+func Write(workDir string, doc *Document) error {
+	if doc.WrittenAt.IsZero() {
+		doc.WrittenAt = time.Now()
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling handoff document: %w", err)
+	}
+	path := Path(workDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing handoff document: %w", err)
+	}
+	return nil
+}
+
+// Capture starts a handoff document from the git state of workDir.
+// Hooked work, molecule context and the agent-supplied fields are left to
+// the caller.
+func Capture(workDir string) *Document {
+	doc := &Document{WrittenAt: time.Now()}
+	if cp, err := checkpoint.Capture(workDir); err == nil {
+		doc.Branch = cp.Branch
+		doc.Commit = cp.LastCommit
+		doc.FilesInFlight = cp.ModifiedFiles
+	}
+	return doc
+}
+
+// AddToolActivity records the commands and edited files found in the last
+// tool calls of a conversation log. Files edited by the agent join
+// FilesInFlight even when they have since been committed, since they are
+// what the session was touching.
+func (d *Document) AddToolActivity(workDir string, events []agentlog.AgentEvent) {
+	var calls []agentlog.AgentEvent
+	for _, ev := range events {
+		if ev.EventType == "tool_use" {
+			calls = append(calls, ev)
+		}
+	}
+	if len(calls) > recentToolCalls {
+		calls = calls[len(calls)-recentToolCalls:]
+	}
+
+	var commands []string
+	for _, ev := range calls {
+		name, input, ok := strings.Cut(ev.Content, ": ")
+		if !ok {
+			continue
+		}
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(input), &args); err != nil {
+			continue
+		}
+		if command, _ := args["command"].(string); command != "" {
+			commands = appendUnique(commands, strings.TrimSpace(command))
+			continue
+		}
+		if !isEditTool(name) {
+			continue
+		}
+		for _, key := range []string{"file_path", "filePath", "path"} {
+			if file, _ := args[key].(string); file != "" {
+				d.FilesInFlight = appendUnique(d.FilesInFlight, relativeTo(workDir, file))
+				break
+			}
+		}
+	}
+	if len(commands) > maxRecentCommands {
+		commands = commands[len(commands)-maxRecentCommands:]
+	}
+	d.Commands = commands
+}
+
+// isEditTool reports whether a tool name is one that writes files, across
+// the agents with conversation log adapters.
+func isEditTool(name string) bool {
+	switch strings.ToLower(name) {
+	case "edit", "write", "multiedit", "notebookedit", "patch":
+		return true
+	}
+	return false
+}
+
+// appendUnique appends s unless present, moving an existing entry to the
+// end so the slice stays ordered by most recent use.
+func appendUnique(list []string, s string) []string {
+	for i, existing := range list {
+		if existing == s {
+			return append(append(list[:i:i], list[i+1:]...), s)
+		}
+	}
+	return append(list, s)
+}
+
+func relativeTo(workDir, file string) string {
+	if !filepath.IsAbs(file) {
+		return file
+	}
+	if rel, err := filepath.Rel(workDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return file
+}
+
+// Age returns how long ago the handoff was written.
+func (d *Document) Age() time.Duration {
+	return time.Since(d.WrittenAt)
+}
+
+// IsStale returns true if the handoff is at or older than the threshold.
+func (d *Document) IsStale(threshold time.Duration) bool {
+	return d.Age() >= threshold
+}
+
+// Consume marks the handoff as taken up by the successor session sessionID.
+// It returns false if the handoff was already consumed by another session,
+// in which case it should not be shown again. The successor itself may see
+// it again (e.g. after re-priming); an unknown session ID consumes it for
+// good.
+func (d *Document) Consume(sessionID string) bool {
+	if !d.ConsumedAt.IsZero() {
+		return sessionID != "" && d.ConsumedBy == sessionID
+	}
+	d.ConsumedAt = time.Now()
+	d.ConsumedBy = sessionID
+	return true
+}
+
+// Markdown renders the document for mail bodies and gt prime output.
+func (d *Document) Markdown() string {
+	var b strings.Builder
+	field := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "**%s:** %s\n", label, value)
+		}
+	}
+	list := func(title string, items []string, numbered bool) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n### %s\n", title)
+		for i, item := range items {
+			if numbered {
+				fmt.Fprintf(&b, "%d. %s\n", i+1, item)
+			} else {
+				fmt.Fprintf(&b, "- %s\n", item)
+			}
+		}
+	}
+
+	field("Hooked bead", d.HookedBead)
+	field("Molecule", d.Molecule)
+	step := d.Step
+	if d.StepTitle != "" {
+		step = strings.TrimSpace(step + " (" + d.StepTitle + ")")
+	}
+	field("Step", step)
+	if d.Branch != "" || d.Commit != "" {
+		field("Branch", strings.TrimSpace(d.Branch+" @ "+shortSHA(d.Commit)))
+	}
+	field("Reason", d.Reason)
+	list("Next actions", d.NextActions, true)
+	list("Open questions", d.OpenQuestions, false)
+	list("Files in flight", d.FilesInFlight, false)
+	if len(d.Commands) > 0 {
+		b.WriteString("\n### Recent commands\n")
+		for _, c := range d.Commands {
+			fmt.Fprintf(&b, "    $ %s\n", firstLine(c))
+		}
+	}
+	if d.Notes != "" {
+		fmt.Fprintf(&b, "\n### Notes\n%s\n", d.Notes)
+	}
+	if b.Len() == 0 {
+		return "No structured state recorded."
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+func firstLine(s string) string {
+	if line, _, found := strings.Cut(s, "\n"); found {
+		return line + " …"
+	}
+	return s
+}
+
+// Diff describes how a workspace moved on since a handoff.
+type Diff struct {
+	// Commits made since the handoff commit, newest first (oneline format).
+	Commits []string
+
+	// BranchChanged is set when the current branch differs from the
+	// handoff's; Branch is the current branch.
+	BranchChanged bool
+	Branch        string
+
+	// HookChanged is set when the hooked bead differs; HookedBead is the
+	// current one.
+	HookChanged bool
+	HookedBead  string
+
+	// StepChanged is set when the molecule step differs; Step is the
+	// current one.
+	StepChanged bool
+	Step        string
+
+	// Settled are files in flight at handoff that are now clean.
+	Settled []string
+
+	// NewInFlight are files with changes now that were not in flight.
+	NewInFlight []string
+}
+
+// Empty reports whether nothing changed.
+func (d *Diff) Empty() bool {
+	return len(d.Commits) == 0 && !d.BranchChanged && !d.HookChanged && !d.StepChanged &&
+		len(d.Settled) == 0 && len(d.NewInFlight) == 0
+}
+
+// Compare diffs a handoff document against a document captured now.
+// Commits are left to CommitsSince, which needs the repository.
+func Compare(prev, cur *Document) *Diff {
+	d := &Diff{
+		Branch:     cur.Branch,
+		HookedBead: cur.HookedBead,
+		Step:       cur.Step,
+	}
+	d.BranchChanged = prev.Branch != cur.Branch
+	d.HookChanged = prev.HookedBead != cur.HookedBead
+	d.StepChanged = prev.Molecule != "" && prev.Step != cur.Step
+
+	now := make(map[string]bool, len(cur.FilesInFlight))
+	for _, f := range cur.FilesInFlight {
+		now[f] = true
+	}
+	then := make(map[string]bool, len(prev.FilesInFlight))
+	for _, f := range prev.FilesInFlight {
+		then[f] = true
+		if !now[f] {
+			d.Settled = append(d.Settled, f)
+		}
+	}
+	for _, f := range cur.FilesInFlight {
+		if !then[f] {
+			d.NewInFlight = append(d.NewInFlight, f)
+		}
+	}
+	return d
+}
+
+// CommitsSince lists commits reachable from HEAD but not from commit, in
+// oneline format, newest first.
+func CommitsSince(workDir, commit string) ([]string, error) {
+	if commit == "" {
+		return nil, nil
+	}
+	cmd := exec.Command("git", "log", "--oneline", commit+"..HEAD")
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log %s..HEAD: %w", shortSHA(commit), err)
+	}
+	trimmed := strings.TrimSpace(string(out))
+	if trimmed == "" {
+		return nil, nil
+	}
+	return strings.Split(trimmed, "\n"), nil
+}
@@ -0,0 +1,191 @@
+package handoff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+)
+
+func TestReadWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	doc, err := Read(dir)
+	if err != nil || doc != nil {
+		t.Fatalf("Read with no document = %+v, %v; want nil, nil", doc, err)
+	}
+
+	original := &Document{
+		Agent:         "gastown/crew/max",
+		HookedBead:    "gt-abc",
+		Molecule:      "mol-1",
+		Step:          "gt-abc.3",
+		Branch:        "feature/x",
+		Commit:        "0123456789abcdef",
+		FilesInFlight: []string{"a.go"},
+		OpenQuestions: []string{"Is the cache safe to drop?"},
+		NextActions:   []string{"Run the migration", "Open the MR"},
+	}
+	if err := Write(dir, original); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if original.WrittenAt.IsZero() {
+		t.Error("Write should stamp WrittenAt")
+	}
+
+	doc, err = Read(dir)
+	if err != nil || doc == nil {
+		t.Fatalf("Read: %+v, %v", doc, err)
+	}
+	if doc.HookedBead != "gt-abc" || len(doc.NextActions) != 2 || doc.OpenQuestions[0] != "Is the cache safe to drop?" {
+		t.Errorf("round trip lost fields: %+v", doc)
+	}
+}
+
+func TestAddToolActivity(t *testing.T) {
+	workDir := "/work/crew/max"
+	var events []agentlog.AgentEvent
+	tool := func(content string) {
+		events = append(events, agentlog.AgentEvent{EventType: "tool_use", Content: content})
+	}
+	events = append(events, agentlog.AgentEvent{EventType: "text", Content: "Bash: {\"command\":\"ignored\"}"})
+	tool(`Bash: {"command":"go test ./..."}`)
+	tool(`Edit: {"file_path":"/work/crew/max/internal/a.go","old_string":"x"}`)
+	tool(`Read: {"file_path":"/work/crew/max/README.md"}`)
+	tool(`write: {"filePath":"/elsewhere/b.go"}`)
+	tool(`Bash: {"command":"make lint"}`)
+	tool(`Bash: {"command":"go test ./..."}`)
+	tool("not a tool call")
+
+	doc := &Document{FilesInFlight: []string{"internal/a.go", "c.go"}}
+	doc.AddToolActivity(workDir, events)
+
+	if got := strings.Join(doc.Commands, " | "); got != "make lint | go test ./..." {
+		t.Errorf("Commands = %q", got)
+	}
+	if got := strings.Join(doc.FilesInFlight, " "); got != "c.go internal/a.go /elsewhere/b.go" {
+		t.Errorf("FilesInFlight = %q", got)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	doc := &Document{
+		HookedBead:  "gt-abc",
+		Molecule:    "mol-1",
+		Step:        "gt-abc.3",
+		StepTitle:   "Write tests",
+		Branch:      "feature/x",
+		Commit:      "0123456789abcdef",
+		Commands:    []string{"go test ./...\nwith a second line"},
+		NextActions: []string{"Run the migration"},
+		Notes:       "Cache is warm.",
+	}
+	md := doc.Markdown()
+	for _, want := range []string{
+		"**Hooked bead:** gt-abc",
+		"**Step:** gt-abc.3 (Write tests)",
+		"**Branch:** feature/x @ 01234567",
+		"### Next actions\n1. Run the migration",
+		"$ go test ./... …",
+		"### Notes\nCache is warm.",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown missing %q:\n%s", want, md)
+		}
+	}
+	if got := (&Document{}).Markdown(); got != "No structured state recorded." {
+		t.Errorf("empty Markdown = %q", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	prev := &Document{
+		Branch:        "feature/x",
+		HookedBead:    "gt-abc",
+		Molecule:      "mol-1",
+		Step:          "gt-abc.3",
+		FilesInFlight: []string{"a.go", "b.go"},
+	}
+	same := Compare(prev, &Document{Branch: "feature/x", HookedBead: "gt-abc", Step: "gt-abc.3", FilesInFlight: []string{"b.go", "a.go"}})
+	if !same.Empty() {
+		t.Errorf("expected no changes, got %+v", same)
+	}
+
+	d := Compare(prev, &Document{Branch: "feature/y", HookedBead: "gt-def", Step: "gt-abc.4", FilesInFlight: []string{"b.go", "c.go"}})
+	if !d.BranchChanged || !d.HookChanged || !d.StepChanged {
+		t.Errorf("expected branch, hook and step changes, got %+v", d)
+	}
+	if strings.Join(d.Settled, ",") != "a.go" || strings.Join(d.NewInFlight, ",") != "c.go" {
+		t.Errorf("Settled = %v, NewInFlight = %v", d.Settled, d.NewInFlight)
+	}
+}
+
+func TestCaptureAndCommitsSince(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-b", "main")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-m", "first")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := Capture(dir)
+	if doc.Branch != "main" || doc.Commit == "" || strings.Join(doc.FilesInFlight, ",") != "a.txt" {
+		t.Fatalf("Capture = %+v", doc)
+	}
+
+	commits, err := CommitsSince(dir, doc.Commit)
+	if err != nil || len(commits) != 0 {
+		t.Fatalf("CommitsSince before new work = %v, %v", commits, err)
+	}
+	run("commit", "-am", "second")
+	commits, err = CommitsSince(dir, doc.Commit)
+	if err != nil || len(commits) != 1 || !strings.HasSuffix(commits[0], "second") {
+		t.Errorf("CommitsSince = %v, %v", commits, err)
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	doc := &Document{WrittenAt: time.Now().Add(-2 * time.Hour)}
+	if doc.IsStale(3 * time.Hour) {
+		t.Error("2h-old handoff should not be stale at 3h")
+	}
+	if !doc.IsStale(time.Hour) {
+		t.Error("2h-old handoff should be stale at 1h")
+	}
+}
+
+func TestConsume(t *testing.T) {
+	doc := &Document{WrittenAt: time.Now()}
+	if !doc.Consume("succ") {
+		t.Fatal("first Consume should succeed")
+	}
+	if !doc.Consume("succ") {
+		t.Error("the consuming successor should still see the handoff")
+	}
+	if doc.Consume("other") {
+		t.Error("another session should not see a consumed handoff")
+	}
+
+	anon := &Document{WrittenAt: time.Now()}
+	if !anon.Consume("") || anon.Consume("") {
+		t.Error("an unknown session should consume the handoff once")
+	}
+}
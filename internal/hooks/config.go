@@ -468,6 +468,63 @@ func DiscoverTargets(townRoot string) ([]Target, error) {
 	return targets, nil
 }
 
+// SettingsDir returns the directory whose .claude holds the target's settings.
+func (t Target) SettingsDir() string {
+	return filepath.Dir(filepath.Dir(t.Path))
+}
+
+// WorkDirs returns the working directories of the agents the target covers.
+// Providers without a --settings flag read their hooks from there rather
+// than from the shared settings directory.
+func (t Target) WorkDirs() []string {
+	dir := t.SettingsDir()
+	switch t.Role {
+	case "crew":
+		return subdirs(dir)
+	case "polecat":
+		// New polecats work in polecats/<name>/<rig>; older ones in polecats/<name>.
+		var dirs []string
+		for _, p := range subdirs(dir) {
+			if clone := filepath.Join(p, t.Rig); isDir(clone) {
+				p = clone
+			}
+			dirs = append(dirs, p)
+		}
+		return dirs
+	case "witness", "refinery":
+		dirs := []string{dir}
+		if rigDir := filepath.Join(dir, "rig"); isDir(rigDir) {
+			dirs = []string{rigDir}
+		}
+		if t.Role == "refinery" {
+			dirs = append(dirs, subdirs(filepath.Join(dir, "lanes"))...)
+		}
+		return dirs
+	default:
+		return []string{dir}
+	}
+}
+
+// subdirs lists the non-hidden subdirectories of dir.
+func subdirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			dirs = append(dirs, filepath.Join(dir, e.Name()))
+		}
+	}
+	return dirs
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
 // isRig checks if a directory looks like a rig (has crew/, witness/, or polecats/ subdirectory).
 func isRig(path string) bool {
 	for _, sub := range []string{"crew", "witness", "polecats", "refinery"} {
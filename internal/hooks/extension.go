@@ -0,0 +1,84 @@
+package hooks
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+//go:embed extensions/*.js
+var extensionFS embed.FS
+
+// hooksLine is the line of a generated extension that carries its hooks,
+// as the JSON of a HooksConfig. Parse reads it back.
+var hooksLine = []byte("const HOOKS = ")
+
+// extensionEvents are the events the generated extensions dispatch.
+var extensionEvents = map[string]string{
+	"PreToolUse":       "tool call",
+	"PostToolUse":      "tool result",
+	"SessionStart":     "session start",
+	"Stop":             "session end",
+	"PreCompact":       "compaction",
+	"UserPromptSubmit": "prompt",
+}
+
+// newExtensionTranslator returns a translator generating a JavaScript
+// extension from an embedded template. The whole file is generated; hand
+// edits are replaced on sync.
+func newExtensionTranslator(template string) *Translator {
+	return &Translator{
+		Events:   extensionEvents,
+		Matchers: true,
+		path:     func(dir, file string) string { return filepath.Join(dir, file) },
+		render: func(cfg *HooksConfig, _ []byte) ([]byte, error) {
+			return renderExtension(template, cfg)
+		},
+		parse: parseExtension,
+	}
+}
+
+var (
+	// OpenCode plugins in .opencode/plugins.
+	opencodeTranslator = newExtensionTranslator("extensions/opencode.js")
+	// pi extensions (-e) and oh-my-pi hooks (--hook) share an event API.
+	piTranslator  = newExtensionTranslator("extensions/pi.js")
+	ompTranslator = newExtensionTranslator("extensions/pi.js")
+)
+
+func renderExtension(template string, cfg *HooksConfig) ([]byte, error) {
+	tmpl, err := extensionFS.ReadFile(template)
+	if err != nil {
+		return nil, fmt.Errorf("reading extension template: %w", err)
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	placeholder := append(append([]byte{}, hooksLine...), "{};"...)
+	if !bytes.Contains(tmpl, placeholder) {
+		return nil, fmt.Errorf("extension template %s has no HOOKS placeholder", template)
+	}
+	line := append(append(append([]byte{}, hooksLine...), data...), ';')
+	return bytes.Replace(tmpl, placeholder, line, 1), nil
+}
+
+// parseExtension reads the hooks of a generated extension. Extensions not
+// generated by gt (the hand-written ones installed before sync translated
+// hooks) have none.
+func parseExtension(data []byte) (*HooksConfig, error) {
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if !bytes.HasPrefix(line, hooksLine) {
+			continue
+		}
+		raw := bytes.TrimSuffix(bytes.TrimSpace(bytes.TrimPrefix(line, hooksLine)), []byte(";"))
+		var cfg HooksConfig
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return nil, fmt.Errorf("parsing extension hooks: %w", err)
+		}
+		return &cfg, nil
+	}
+	return &HooksConfig{}, nil
+}
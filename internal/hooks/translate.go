@@ -0,0 +1,396 @@
+package hooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownProvider indicates a hooks provider with no translator.
+var ErrUnknownProvider = errors.New("no hook translator for provider")
+
+// Unsupported is a hook a provider can't express natively. Sync skips it
+// and reports it, so a guard that silently doesn't run is never mistaken
+// for one that does.
+type Unsupported struct {
+	Event   string
+	Matcher string
+	Reason  string
+}
+
+func (u Unsupported) String() string {
+	if u.Matcher == "" {
+		return fmt.Sprintf("%s: %s", u.Event, u.Reason)
+	}
+	return fmt.Sprintf("%s[%s]: %s", u.Event, u.Matcher, u.Reason)
+}
+
+// Translator renders a HooksConfig in one provider's native hook format.
+// Event names and matchers in a HooksConfig are Claude Code's; they are the
+// abstract vocabulary the base config, overrides and registry are written in.
+type Translator struct {
+	// Events maps each event type the provider supports to its native name.
+	Events map[string]string
+
+	// Matchers reports whether hooks can be scoped to tools. Without it,
+	// only entries with an empty matcher translate.
+	Matchers bool
+
+	// path returns the hook file relative to an agent's working directory,
+	// given the preset's hooks dir and settings file.
+	path func(hooksDir, hooksFile string) string
+
+	// render writes cfg (already reduced to what the provider supports)
+	// into the native file, preserving unmanaged content of current.
+	render func(cfg *HooksConfig, current []byte) ([]byte, error)
+
+	// parse reads the managed hooks back out of a native file.
+	parse func(data []byte) (*HooksConfig, error)
+}
+
+// translators maps hooks provider names (RuntimeHooksConfig.Provider) to
+// their translators. Claude is absent: its settings.json is the native form.
+var translators = map[string]*Translator{
+	"gemini":   geminiTranslator,
+	"cursor":   cursorTranslator,
+	"copilot":  copilotTranslator,
+	"opencode": opencodeTranslator,
+	"pi":       piTranslator,
+	"omp":      ompTranslator,
+}
+
+// TranslatorFor returns the translator for a hooks provider.
+func TranslatorFor(provider string) (*Translator, error) {
+	t, ok := translators[provider]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownProvider, provider)
+	}
+	return t, nil
+}
+
+// TranslatedProviders returns the providers gt hooks sync can translate to.
+func TranslatedProviders() []string {
+	names := make([]string, 0, len(translators))
+	for name := range translators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Path returns the provider's hook file for an agent working in workDir.
+func (t *Translator) Path(workDir, hooksDir, hooksFile string) string {
+	return filepath.Join(workDir, t.path(hooksDir, hooksFile))
+}
+
+// Supported splits cfg into the part the provider can express and the
+// hooks it can't.
+func (t *Translator) Supported(cfg *HooksConfig) (*HooksConfig, []Unsupported) {
+	out := &HooksConfig{}
+	var skipped []Unsupported
+	for _, event := range EventTypes {
+		var kept []HookEntry
+		for _, entry := range cfg.GetEntries(event) {
+			switch {
+			case t.Events[event] == "":
+				skipped = append(skipped, Unsupported{Event: event, Matcher: entry.Matcher, Reason: "no equivalent event"})
+			case entry.Matcher != "" && !t.Matchers:
+				skipped = append(skipped, Unsupported{Event: event, Matcher: entry.Matcher, Reason: "hooks can't be scoped to tools"})
+			default:
+				kept = append(kept, entry)
+			}
+		}
+		out.SetEntries(event, kept)
+	}
+	return out, skipped
+}
+
+// Render translates cfg into the provider's native file, merging into
+// current (nil if the file doesn't exist). It returns the hooks that were
+// left out.
+func (t *Translator) Render(cfg *HooksConfig, current []byte) ([]byte, []Unsupported, error) {
+	supported, skipped := t.Supported(cfg)
+	data, err := t.render(supported, current)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, skipped, nil
+}
+
+// Parse reads the managed hooks of a native file back into a HooksConfig,
+// so native files can be compared against the expected config.
+func (t *Translator) Parse(data []byte) (*HooksConfig, error) {
+	if len(data) == 0 {
+		return &HooksConfig{}, nil
+	}
+	return t.parse(data)
+}
+
+// splitMatcher splits a "Tool(pattern)" matcher into its tool and argument
+// pattern. A bare "Tool" has no pattern.
+func splitMatcher(m string) (tool, pattern string, ok bool) {
+	open := strings.IndexByte(m, '(')
+	if open < 0 || !strings.HasSuffix(m, ")") {
+		return m, "", false
+	}
+	return m[:open], m[open+1 : len(m)-1], true
+}
+
+// globToRegexp turns a matcher glob ("*" wildcard) into a regular expression.
+func globToRegexp(glob string) string {
+	return strings.ReplaceAll(regexp.QuoteMeta(glob), `\*`, ".*")
+}
+
+// regexpToGlob reverses globToRegexp.
+func regexpToGlob(re string) string {
+	var b strings.Builder
+	for i := 0; i < len(re); i++ {
+		switch {
+		case re[i] == '\\' && i+1 < len(re):
+			i++
+			b.WriteByte(re[i])
+		case re[i] == '.' && i+1 < len(re) && re[i+1] == '*':
+			i++
+			b.WriteByte('*')
+		default:
+			b.WriteByte(re[i])
+		}
+	}
+	return b.String()
+}
+
+// nativeEvents returns the inverse of an Events map.
+func nativeEvents(events map[string]string) map[string]string {
+	out := make(map[string]string, len(events))
+	for canonical, native := range events {
+		out[native] = canonical
+	}
+	return out
+}
+
+// mergeJSONKey sets key in a JSON object document, preserving its other
+// fields. An empty current starts a new object.
+func mergeJSONKey(current []byte, key string, value any, defaults map[string]any) ([]byte, error) {
+	doc := make(map[string]json.RawMessage)
+	if len(current) > 0 {
+		if err := json.Unmarshal(current, &doc); err != nil {
+			return nil, fmt.Errorf("parsing existing file: %w", err)
+		}
+	}
+	for k, v := range defaults {
+		if _, ok := doc[k]; !ok {
+			raw, _ := json.Marshal(v)
+			doc[k] = raw
+		}
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	doc[key] = raw
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// jsonKey reads key from a JSON object document into v. A missing key
+// leaves v untouched.
+func jsonKey(data []byte, key string, v any) error {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	raw, ok := doc[key]
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// Gemini CLI: .gemini/settings.json, Claude-shaped entries with regular
+// expression matchers and its own event names.
+var geminiTranslator = &Translator{
+	Events: map[string]string{
+		"PreToolUse":       "BeforeTool",
+		"PostToolUse":      "AfterTool",
+		"SessionStart":     "SessionStart",
+		"Stop":             "SessionEnd",
+		"PreCompact":       "PreCompress",
+		"UserPromptSubmit": "BeforeAgent",
+	},
+	Matchers: true,
+	path:     func(dir, file string) string { return filepath.Join(dir, file) },
+}
+
+func init() {
+	geminiTranslator.render = func(cfg *HooksConfig, current []byte) ([]byte, error) {
+		native := make(map[string][]HookEntry)
+		for _, event := range EventTypes {
+			for _, entry := range cfg.GetEntries(event) {
+				entry.Matcher = geminiMatcher(entry.Matcher)
+				native[geminiTranslator.Events[event]] = append(native[geminiTranslator.Events[event]], entry)
+			}
+		}
+		return mergeJSONKey(current, "hooks", native, nil)
+	}
+	geminiTranslator.parse = func(data []byte) (*HooksConfig, error) {
+		var native map[string][]HookEntry
+		if err := jsonKey(data, "hooks", &native); err != nil {
+			return nil, err
+		}
+		cfg := &HooksConfig{}
+		canonical := nativeEvents(geminiTranslator.Events)
+		for name, entries := range native {
+			event, ok := canonical[name]
+			if !ok {
+				continue
+			}
+			for i := range entries {
+				entries[i].Matcher = regexpToGlob(entries[i].Matcher)
+			}
+			cfg.SetEntries(event, entries)
+		}
+		return cfg, nil
+	}
+}
+
+// geminiMatcher turns "Bash(gh pr create*)" into "Bash\(gh pr create.*\)".
+func geminiMatcher(m string) string {
+	if m == "" {
+		return ""
+	}
+	return globToRegexp(m)
+}
+
+// cursorHook is one entry of Cursor's hooks.json.
+type cursorHook struct {
+	Command string `json:"command"`
+	Matcher string `json:"matcher,omitempty"`
+}
+
+// Cursor: .cursor/hooks.json, a flat command list per camel-cased event,
+// with the shell tool called Shell.
+var cursorTranslator = &Translator{
+	Events: map[string]string{
+		"PreToolUse":       "preToolUse",
+		"PostToolUse":      "postToolUse",
+		"SessionStart":     "sessionStart",
+		"Stop":             "stop",
+		"PreCompact":       "preCompact",
+		"UserPromptSubmit": "beforeSubmitPrompt",
+	},
+	Matchers: true,
+	path:     func(dir, file string) string { return filepath.Join(dir, file) },
+}
+
+func init() {
+	cursorTranslator.render = func(cfg *HooksConfig, current []byte) ([]byte, error) {
+		native := make(map[string][]cursorHook)
+		for _, event := range EventTypes {
+			name := cursorTranslator.Events[event]
+			for _, entry := range cfg.GetEntries(event) {
+				for _, h := range entry.Hooks {
+					native[name] = append(native[name], cursorHook{Command: h.Command, Matcher: renameTool(entry.Matcher, "Bash", "Shell")})
+				}
+			}
+		}
+		return mergeJSONKey(current, "hooks", native, map[string]any{"version": 1})
+	}
+	cursorTranslator.parse = func(data []byte) (*HooksConfig, error) {
+		var native map[string][]cursorHook
+		if err := jsonKey(data, "hooks", &native); err != nil {
+			return nil, err
+		}
+		cfg := &HooksConfig{}
+		canonical := nativeEvents(cursorTranslator.Events)
+		for name, hooks := range native {
+			event, ok := canonical[name]
+			if !ok {
+				continue
+			}
+			var entries []HookEntry
+			for _, h := range hooks {
+				matcher := renameTool(h.Matcher, "Shell", "Bash")
+				if n := len(entries); n > 0 && entries[n-1].Matcher == matcher {
+					entries[n-1].Hooks = append(entries[n-1].Hooks, Hook{Type: "command", Command: h.Command})
+					continue
+				}
+				entries = append(entries, HookEntry{Matcher: matcher, Hooks: []Hook{{Type: "command", Command: h.Command}}})
+			}
+			cfg.SetEntries(event, entries)
+		}
+		return cfg, nil
+	}
+}
+
+// renameTool renames the tool of a "Tool(pattern)" or bare "Tool" matcher.
+func renameTool(m, from, to string) string {
+	tool, pattern, hasPattern := splitMatcher(m)
+	if tool != from {
+		return m
+	}
+	if !hasPattern {
+		return to
+	}
+	return to + "(" + pattern + ")"
+}
+
+// copilotHook is one entry of a Copilot CLI hooks file.
+type copilotHook struct {
+	Type string `json:"type"`
+	Bash string `json:"bash"`
+}
+
+// Copilot CLI: .github/hooks/gastown.json. Hooks see every tool call, so
+// tool-scoped guards can't be expressed.
+var copilotTranslator = &Translator{
+	Events: map[string]string{
+		"PreToolUse":       "preToolUse",
+		"PostToolUse":      "postToolUse",
+		"SessionStart":     "sessionStart",
+		"Stop":             "sessionEnd",
+		"UserPromptSubmit": "userPromptSubmitted",
+	},
+	path: func(string, string) string { return filepath.Join(".github", "hooks", "gastown.json") },
+}
+
+func init() {
+	copilotTranslator.render = func(cfg *HooksConfig, current []byte) ([]byte, error) {
+		native := make(map[string][]copilotHook)
+		for _, event := range EventTypes {
+			for _, entry := range cfg.GetEntries(event) {
+				for _, h := range entry.Hooks {
+					name := copilotTranslator.Events[event]
+					native[name] = append(native[name], copilotHook{Type: "command", Bash: h.Command})
+				}
+			}
+		}
+		return mergeJSONKey(current, "hooks", native, map[string]any{"version": 1})
+	}
+	copilotTranslator.parse = func(data []byte) (*HooksConfig, error) {
+		var native map[string][]copilotHook
+		if err := jsonKey(data, "hooks", &native); err != nil {
+			return nil, err
+		}
+		cfg := &HooksConfig{}
+		canonical := nativeEvents(copilotTranslator.Events)
+		for name, hooks := range native {
+			event, ok := canonical[name]
+			if !ok || len(hooks) == 0 {
+				continue
+			}
+			entry := HookEntry{}
+			for _, h := range hooks {
+				entry.Hooks = append(entry.Hooks, Hook{Type: "command", Command: h.Bash})
+			}
+			cfg.SetEntries(event, []HookEntry{entry})
+		}
+		return cfg, nil
+	}
+}
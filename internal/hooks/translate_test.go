@@ -0,0 +1,198 @@
+package hooks
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testTranslateConfig is a witness-like config: tool-scoped guards plus
+// lifecycle hooks, and a worktree hook no other provider has.
+func testTranslateConfig() *HooksConfig {
+	cfg := Merge(DefaultBase(), DefaultOverrides()["witness"])
+	cfg.WorktreeCreate = []HookEntry{{Matcher: "", Hooks: []Hook{{Type: "command", Command: "gt worktree setup"}}}}
+	return cfg
+}
+
+func TestTranslatorsRoundTrip(t *testing.T) {
+	cfg := testTranslateConfig()
+	for _, provider := range TranslatedProviders() {
+		t.Run(provider, func(t *testing.T) {
+			tr, err := TranslatorFor(provider)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, skipped, err := tr.Render(cfg, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := tr.Parse(data)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			want, _ := tr.Supported(cfg)
+			if !HooksEqual(got, want) {
+				gj, _ := json.Marshal(got)
+				wj, _ := json.Marshal(want)
+				t.Errorf("round trip mismatch:\n got  %s\n want %s", gj, wj)
+			}
+
+			var worktree bool
+			for _, u := range skipped {
+				if u.Event == "WorktreeCreate" {
+					worktree = true
+				}
+			}
+			if !worktree {
+				t.Errorf("WorktreeCreate should be reported as unsupported, got %v", skipped)
+			}
+
+			// Rendering is stable, so an unchanged config leaves the file alone.
+			again, _, err := tr.Render(cfg, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(again) != string(data) {
+				t.Error("re-rendering over the rendered file changed it")
+			}
+		})
+	}
+}
+
+func TestTranslatorForUnknown(t *testing.T) {
+	if _, err := TranslatorFor("emacs"); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("expected ErrUnknownProvider, got %v", err)
+	}
+}
+
+func TestGeminiTranslation(t *testing.T) {
+	current := []byte(`{"model": {"name": "gemini-2.5-pro"}, "hooks": {"BeforeTool": []}}`)
+	data, _, err := geminiTranslator.Render(DefaultBase(), current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Model map[string]string      `json:"model"`
+		Hooks map[string][]HookEntry `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Model["name"] != "gemini-2.5-pro" {
+		t.Errorf("unmanaged settings not preserved: %s", data)
+	}
+	// Same shape as the template gemini agents were installed with.
+	if got := doc.Hooks["BeforeTool"][0].Matcher; got != `Bash\(gh pr create.*\)` {
+		t.Errorf("BeforeTool matcher = %q", got)
+	}
+	for _, event := range []string{"SessionStart", "PreCompress", "BeforeAgent", "SessionEnd"} {
+		if len(doc.Hooks[event]) == 0 {
+			t.Errorf("missing %s hooks in %s", event, data)
+		}
+	}
+}
+
+func TestCursorTranslation(t *testing.T) {
+	data, _, err := cursorTranslator.Render(DefaultBase(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Version int                     `json:"version"`
+		Hooks   map[string][]cursorHook `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Version != 1 {
+		t.Errorf("version = %d, want 1", doc.Version)
+	}
+	if got := doc.Hooks["preToolUse"][0].Matcher; got != "Shell(gh pr create*)" {
+		t.Errorf("preToolUse matcher = %q", got)
+	}
+	if len(doc.Hooks["beforeSubmitPrompt"]) != 1 || doc.Hooks["beforeSubmitPrompt"][0].Matcher != "" {
+		t.Errorf("beforeSubmitPrompt = %+v", doc.Hooks["beforeSubmitPrompt"])
+	}
+}
+
+func TestCopilotTranslationReportsToolGuards(t *testing.T) {
+	data, skipped, err := copilotTranslator.Render(DefaultBase(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "gt tap guard") {
+		t.Errorf("tool-scoped guards can't be expressed for copilot:\n%s", data)
+	}
+	reasons := make(map[string]int)
+	for _, u := range skipped {
+		reasons[u.Event+": "+u.Reason]++
+	}
+	if reasons["PreToolUse: hooks can't be scoped to tools"] != len(DefaultBase().PreToolUse) {
+		t.Errorf("every tool guard should be reported, got %v", skipped)
+	}
+	if reasons["PreCompact: no equivalent event"] != 1 {
+		t.Errorf("PreCompact should be reported, got %v", skipped)
+	}
+	if p := copilotTranslator.Path("/w", ".copilot", "copilot-instructions.md"); p != filepath.Join("/w", ".github", "hooks", "gastown.json") {
+		t.Errorf("Path = %s", p)
+	}
+}
+
+func TestExtensionParseHandwritten(t *testing.T) {
+	cfg, err := piTranslator.Parse([]byte("export default (pi) => {};\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !HooksEqual(cfg, &HooksConfig{}) {
+		t.Errorf("hand-written extension should have no managed hooks, got %+v", cfg)
+	}
+}
+
+func TestGlobRegexp(t *testing.T) {
+	for _, glob := range []string{"Bash(gh pr create*)", "Bash(rm -rf /*)", "Edit|Write", "Bash(a.b+c?)"} {
+		if got := regexpToGlob(globToRegexp(glob)); got != glob {
+			t.Errorf("round trip %q -> %q -> %q", glob, globToRegexp(glob), got)
+		}
+	}
+}
+
+func TestTargetWorkDirs(t *testing.T) {
+	town := t.TempDir()
+	mk := func(parts ...string) string {
+		p := filepath.Join(append([]string{town}, parts...)...)
+		if err := os.MkdirAll(p, 0755); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	crewMax := mk("gastown", "crew", "max")
+	mk("gastown", "crew", ".claude")
+	furiosa := mk("gastown", "polecats", "furiosa", "gastown")
+	legacy := mk("gastown", "polecats", "nux")
+	refinery := mk("gastown", "refinery", "rig")
+	lane := mk("gastown", "refinery", "lanes", "api")
+	witness := mk("gastown", "witness")
+
+	target := func(role, parent string) Target {
+		return Target{Path: filepath.Join(town, "gastown", parent, ".claude", "settings.json"), Rig: "gastown", Role: role}
+	}
+	tests := []struct {
+		target Target
+		want   []string
+	}{
+		{target("crew", "crew"), []string{crewMax}},
+		{target("polecat", "polecats"), []string{furiosa, legacy}},
+		{target("refinery", "refinery"), []string{refinery, lane}},
+		{target("witness", "witness"), []string{witness}},
+		{Target{Path: filepath.Join(town, "mayor", ".claude", "settings.json"), Role: "mayor"}, []string{filepath.Join(town, "mayor")}},
+	}
+	for _, tt := range tests {
+		if got := tt.target.WorkDirs(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s WorkDirs() = %v, want %v", tt.target.Role, got, tt.want)
+		}
+	}
+}
@@ -54,9 +54,18 @@ func (e *bdError) ContainsError(substr string) bool {
 // beadsDir is the BEADS_DIR environment variable value.
 // extraEnv contains additional environment variables to set (e.g., "BD_IDENTITY=...").
 // Returns stdout bytes on success, or a *bdError on failure.
+// Read-only list/show queries are served in-process when possible (see
+// beads.QueryJSON); extraEnv only applies to the subprocess, so queries that
+// carry it always spawn bd.
 func runBdCommand(ctx context.Context, args []string, workDir, beadsDir string, extraEnv ...string) (_ []byte, retErr error) {
 	defer func() { telemetry.RecordMail(ctx, "bd."+firstArg(args), retErr) }()
 
+	if len(extraEnv) == 0 {
+		if out, ok := beads.QueryJSON(ctx, beadsDir, args); ok {
+			return out, nil
+		}
+	}
+
 	// Remove stale dolt-server.pid before spawning bd. A stale PID file causes
 	// bd to connect to port 3307 which may be occupied by a different Dolt server
 	// serving different databases, resulting in hangs until the read timeout kills it.
@@ -0,0 +1,507 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Cross-town mail bridge.
+//
+// Addresses of the form "town:<name>/<address>" target an agent in another
+// town. The sending router records an outbound copy in local beads, relays a
+// RelayEnvelope to the remote town's gt-proxy-server over mTLS, and marks the
+// outbound copy acked (DeliveryAckLabelSequence) once the remote town returns a
+// RelayReceipt. The receiving side (BridgeHandler) authenticates the peer town
+// from its client certificate, drops loops and duplicates, and delivers the
+// message through its own Router.
+
+const (
+	// TownAddressPrefix marks a federated address: town:<name>/<address>.
+	TownAddressPrefix = "town:"
+
+	// BridgeRelayPath is the gt-proxy-server endpoint that accepts relayed mail.
+	BridgeRelayPath = "/v1/mail/relay"
+
+	// MaxRelayHops bounds how many towns a single message may traverse.
+	// Direct town-to-town delivery uses one hop; forwarding (e.g. a mailing
+	// list in the remote town that points at a third town) adds one per town.
+	MaxRelayHops = 4
+
+	// bridgeCNPrefix is the client certificate CN prefix for peer towns.
+	// Certificates are issued by the remote proxy's admin API with rig "town"
+	// and name <town>, which yields CN "gt-town-<town>".
+	bridgeCNPrefix = "gt-town-"
+
+	// relayLabelPrefix tags the local outbound copy of a relayed message.
+	relayLabelPrefix = "relay:"
+
+	// bridgeRelayTimeout bounds one relay round trip, including remote delivery.
+	bridgeRelayTimeout = 90 * time.Second
+
+	// bridgeSeenLimit caps the receiver's duplicate-suppression memory.
+	bridgeSeenLimit = 1024
+)
+
+// ErrUnknownTown indicates a town:<name> address names a town that is not
+// configured in messaging.json "towns".
+var ErrUnknownTown = errors.New("unknown remote town")
+
+// ErrRelayLoop indicates a relayed message would revisit a town it already
+// passed through, or exceeded MaxRelayHops.
+var ErrRelayLoop = errors.New("mail relay loop")
+
+// isTownAddress returns true if the address uses town:<name>/<address> syntax.
+func isTownAddress(address string) bool {
+	return strings.HasPrefix(address, TownAddressPrefix)
+}
+
+// ParseTownAddress splits a town:<name>/<address> address into the town name
+// and the address local to that town (e.g. "town:backend/mayor/" → "backend", "mayor/").
+func ParseTownAddress(address string) (town, local string, err error) {
+	if !isTownAddress(address) {
+		return "", "", fmt.Errorf("not a town address: %s", address)
+	}
+	town, local, ok := strings.Cut(strings.TrimPrefix(address, TownAddressPrefix), "/")
+	if !ok || town == "" || local == "" {
+		return "", "", fmt.Errorf("invalid town address %q (want town:<name>/<address>)", address)
+	}
+	return town, local, nil
+}
+
+// TownAddress builds a federated address for an agent in the named town.
+func TownAddress(town, local string) string {
+	return TownAddressPrefix + town + "/" + local
+}
+
+// BridgeClientCN returns the client certificate CN a town must present to
+// a peer town's mail bridge.
+func BridgeClientCN(town string) string {
+	return bridgeCNPrefix + town
+}
+
+// RelayEnvelope is the wire format for a message relayed between towns.
+type RelayEnvelope struct {
+	// RelayID identifies the relay attempt; receivers use it to drop retries.
+	RelayID string `json:"relay_id"`
+	// OriginTown is the town where the message was first sent.
+	OriginTown string `json:"origin_town"`
+	// Hops lists every town the message has passed through, origin first.
+	// The last entry must match the town presenting the client certificate.
+	Hops []string `json:"hops"`
+
+	// From is the fully qualified sender address (town:<origin>/<address>).
+	From string `json:"from"`
+	// To is the recipient address local to the receiving town.
+	To       string      `json:"to"`
+	Subject  string      `json:"subject"`
+	Body     string      `json:"body"`
+	Priority Priority    `json:"priority,omitempty"`
+	Type     MessageType `json:"type,omitempty"`
+	ThreadID string      `json:"thread_id,omitempty"`
+	ReplyTo  string      `json:"reply_to,omitempty"`
+	SentAt   time.Time   `json:"sent_at"`
+}
+
+// RelayReceipt is returned by a town once it has durably accepted a relayed message.
+type RelayReceipt struct {
+	RelayID string `json:"relay_id"`
+	// Town is the name of the town that accepted the message.
+	Town string `json:"town"`
+	// AckedBy is the federated identity the message was delivered to.
+	AckedBy string    `json:"acked_by"`
+	AckedAt time.Time `json:"acked_at"`
+	// Duplicate is true when the relay ID had already been delivered.
+	Duplicate bool `json:"duplicate,omitempty"`
+}
+
+// AckLabels returns the delivery ack labels the sender writes on its outbound
+// copy when this receipt arrives.
+func (rc *RelayReceipt) AckLabels() []string {
+	return DeliveryAckLabelSequence(rc.AckedBy, rc.AckedAt)
+}
+
+// newRelayEnvelope builds the envelope for relaying msg from localTown to
+// remoteLocal (an address inside the destination town).
+func newRelayEnvelope(msg *Message, localTown, remoteLocal string) *RelayEnvelope {
+	from := msg.From
+	if !isTownAddress(from) {
+		from = TownAddress(localTown, from)
+	}
+	hops := append(slices.Clone(msg.relayHops), localTown)
+	return &RelayEnvelope{
+		RelayID:    GenerateID(),
+		OriginTown: hops[0],
+		Hops:       hops,
+		From:       from,
+		To:         remoteLocal,
+		Subject:    msg.Subject,
+		Body:       msg.Body,
+		Priority:   msg.Priority,
+		Type:       msg.Type,
+		ThreadID:   msg.ThreadID,
+		ReplyTo:    msg.ReplyTo,
+		SentAt:     timeNow().UTC(),
+	}
+}
+
+// checkHops returns ErrRelayLoop if delivering to town would revisit a town
+// already in the envelope's path or exceed MaxRelayHops.
+func (e *RelayEnvelope) checkHops(town string) error {
+	if slices.Contains(e.Hops, town) {
+		return fmt.Errorf("%w: %s already in path %s", ErrRelayLoop, town, strings.Join(e.Hops, " → "))
+	}
+	if len(e.Hops) > MaxRelayHops {
+		return fmt.Errorf("%w: %d hops exceeds limit of %d", ErrRelayLoop, len(e.Hops), MaxRelayHops)
+	}
+	return nil
+}
+
+// toMessage converts a received envelope into a local message. The relay
+// path is carried along so that any onward relay keeps loop detection.
+func (e *RelayEnvelope) toMessage() *Message {
+	msg := &Message{
+		ID:        GenerateID(),
+		From:      e.From,
+		To:        e.To,
+		Subject:   e.Subject,
+		Body:      e.Body,
+		Timestamp: e.SentAt,
+		Priority:  e.Priority,
+		Type:      e.Type,
+		ThreadID:  e.ThreadID,
+		ReplyTo:   e.ReplyTo,
+		relayHops: slices.Clone(e.Hops),
+	}
+	if msg.Priority == "" {
+		msg.Priority = PriorityNormal
+	}
+	if msg.Type == "" {
+		msg.Type = TypeNotification
+	}
+	return msg
+}
+
+// BridgeClient relays envelopes to one remote town's mail bridge over mTLS.
+type BridgeClient struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewBridgeClient creates a client for the remote town described by cfg.
+// Certificate paths may use a leading ~/.
+func NewBridgeClient(cfg config.RemoteTownConfig) (*BridgeClient, error) {
+	cert, err := tls.LoadX509KeyPair(util.ExpandHome(cfg.CertFile), util.ExpandHome(cfg.KeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("loading bridge client cert: %w", err)
+	}
+	caPEM, err := os.ReadFile(util.ExpandHome(cfg.CAFile)) //nolint:gosec // G304: path comes from town messaging config
+	if err != nil {
+		return nil, fmt.Errorf("reading bridge CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("invalid bridge CA PEM in %s", cfg.CAFile)
+	}
+
+	return &BridgeClient{
+		baseURL: strings.TrimSuffix(cfg.URL, "/"),
+		http: &http.Client{
+			Timeout: bridgeRelayTimeout,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{
+				Certificates: []tls.Certificate{cert},
+				RootCAs:      pool,
+				MinVersion:   tls.VersionTLS13,
+			}},
+		},
+	}, nil
+}
+
+// Relay sends env to the remote town and returns its delivery receipt.
+// A 508 response from the remote town is reported as ErrRelayLoop.
+func (c *BridgeClient) Relay(ctx context.Context, env *RelayEnvelope) (*RelayReceipt, error) {
+	body, err := json.Marshal(env)
+	if err != nil {
+		return nil, fmt.Errorf("encoding relay envelope: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+BridgeRelayPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("bridge request failed: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // best-effort close on response body
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		detail := strings.TrimSpace(string(msg))
+		if resp.StatusCode == http.StatusLoopDetected {
+			return nil, fmt.Errorf("%w: %s", ErrRelayLoop, detail)
+		}
+		return nil, fmt.Errorf("bridge returned %d: %s", resp.StatusCode, detail)
+	}
+
+	var receipt RelayReceipt
+	if err := json.NewDecoder(resp.Body).Decode(&receipt); err != nil {
+		return nil, fmt.Errorf("decoding relay receipt: %w", err)
+	}
+	if receipt.RelayID != env.RelayID {
+		return nil, fmt.Errorf("relay receipt mismatch: got %q, want %q", receipt.RelayID, env.RelayID)
+	}
+	return &receipt, nil
+}
+
+// BridgeHandler accepts relayed mail from peer towns. It is mounted on the
+// gt-proxy-server mTLS listener, so every request carries a client certificate
+// signed by this town's proxy CA.
+type BridgeHandler struct {
+	town    string
+	allowed map[string]bool
+	deliver func(*Message) error
+
+	mu       sync.Mutex
+	seen     map[string]*RelayReceipt
+	seenList []string // insertion order, for eviction
+}
+
+// NewBridgeHandler creates a handler for the named local town. Only peers in
+// allowedTowns are accepted. deliver is called for each new message, typically
+// with Router.Send.
+func NewBridgeHandler(town string, allowedTowns []string, deliver func(*Message) error) *BridgeHandler {
+	allowed := make(map[string]bool, len(allowedTowns))
+	for _, t := range allowedTowns {
+		allowed[t] = true
+	}
+	return &BridgeHandler{
+		town:    town,
+		allowed: allowed,
+		deliver: deliver,
+		seen:    make(map[string]*RelayReceipt),
+	}
+}
+
+// ServeHTTP handles POST /v1/mail/relay.
+func (h *BridgeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != BridgeRelayPath {
+		http.Error(w, "unknown mail endpoint", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	peer := peerTown(r)
+	if peer == "" {
+		http.Error(w, "client certificate is not a town bridge certificate", http.StatusForbidden)
+		return
+	}
+	if !h.allowed[peer] {
+		http.Error(w, fmt.Sprintf("town %q is not a configured peer", peer), http.StatusForbidden)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MiB
+	var env RelayEnvelope
+	if err := json.NewDecoder(r.Body).Decode(&env); err != nil {
+		http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if env.RelayID == "" || env.From == "" || env.To == "" || env.Subject == "" || len(env.Hops) == 0 {
+		http.Error(w, "bad request: relay_id, from, to, subject and hops are required", http.StatusBadRequest)
+		return
+	}
+	if last := env.Hops[len(env.Hops)-1]; last != peer {
+		http.Error(w, fmt.Sprintf("last hop %q does not match certificate town %q", last, peer), http.StatusForbidden)
+		return
+	}
+	if err := env.checkHops(h.town); err != nil {
+		http.Error(w, err.Error(), http.StatusLoopDetected)
+		return
+	}
+
+	// Hold the lock across delivery so a retry racing the original cannot
+	// deliver twice. Relays are low volume; serializing them is fine.
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if prior, ok := h.seen[env.RelayID]; ok {
+		dup := *prior
+		dup.Duplicate = true
+		writeReceipt(w, &dup)
+		return
+	}
+
+	if err := h.deliver(env.toMessage()); err != nil {
+		status := http.StatusBadGateway
+		if errors.Is(err, ErrRelayLoop) {
+			status = http.StatusLoopDetected
+		}
+		http.Error(w, "delivery failed: "+err.Error(), status)
+		return
+	}
+
+	receipt := &RelayReceipt{
+		RelayID: env.RelayID,
+		Town:    h.town,
+		AckedBy: TownAddress(h.town, AddressToIdentity(env.To)),
+		AckedAt: timeNow().UTC(),
+	}
+	h.remember(receipt)
+	writeReceipt(w, receipt)
+}
+
+// remember records a receipt for duplicate suppression. Caller holds h.mu.
+func (h *BridgeHandler) remember(receipt *RelayReceipt) {
+	h.seen[receipt.RelayID] = receipt
+	h.seenList = append(h.seenList, receipt.RelayID)
+	if len(h.seenList) > bridgeSeenLimit {
+		delete(h.seen, h.seenList[0])
+		h.seenList = h.seenList[1:]
+	}
+}
+
+func writeReceipt(w http.ResponseWriter, receipt *RelayReceipt) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(receipt)
+}
+
+// peerTown returns the town name from a "gt-town-<name>" client certificate,
+// or "" if the request has no such certificate.
+func peerTown(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return ""
+	}
+	cn := r.TLS.PeerCertificates[0].Subject.CommonName
+	if !strings.HasPrefix(cn, bridgeCNPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(cn, bridgeCNPrefix)
+}
+
+// sendToRemoteTown relays a message addressed to town:<name>/<address>.
+// A local outbound copy is written first with delivery:pending; it is acked
+// with the remote receipt once the remote town accepts the message, so an
+// unreachable town leaves a visible pending record instead of a silent drop.
+func (r *Router) sendToRemoteTown(msg *Message) error {
+	town, local, err := ParseTownAddress(msg.To)
+	if err != nil {
+		return err
+	}
+	if r.townRoot == "" {
+		return fmt.Errorf("town root not set, cannot relay to town %s", town)
+	}
+	localTown, err := workspace.GetTownName(r.townRoot)
+	if err != nil {
+		return fmt.Errorf("determining local town name: %w", err)
+	}
+
+	// Addressed to ourselves: deliver locally.
+	if town == localTown {
+		localCopy := *msg
+		localCopy.To = local
+		return r.Send(&localCopy)
+	}
+
+	remote, err := expandFromConfig(r, town, func(cfg *config.MessagingConfig) (config.RemoteTownConfig, bool) {
+		rt, ok := cfg.Towns[town]
+		return rt, ok
+	}, ErrUnknownTown)
+	if err != nil {
+		return err
+	}
+
+	if msg.ID == "" {
+		msg.ID = GenerateID()
+	}
+	if err := msg.Validate(); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+
+	env := newRelayEnvelope(msg, localTown, local)
+	if err := env.checkHops(town); err != nil {
+		return err
+	}
+
+	client, err := NewBridgeClient(remote)
+	if err != nil {
+		return fmt.Errorf("town %s: %w", town, err)
+	}
+
+	beadsDir := r.resolveBeadsDir()
+	outboundID, err := r.recordOutbound(msg, env, TownAddress(town, AddressToIdentity(local)), beadsDir)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), bridgeRelayTimeout)
+	defer cancel()
+	receipt, err := client.Relay(ctx, env)
+	if err != nil {
+		return fmt.Errorf("relaying to town %s (outbound %s left pending): %w", town, outboundID, err)
+	}
+
+	if err := writeDeliveryAckLabels(filepath.Dir(beadsDir), beadsDir, outboundID, receipt.AckLabels()); err != nil {
+		return fmt.Errorf("town %s accepted message but recording receipt on %s failed: %w", town, outboundID, err)
+	}
+	return nil
+}
+
+// recordOutbound writes the local copy of a relayed message and returns its bead ID.
+// The copy is assigned to the federated identity so it never lands in a local inbox.
+func (r *Router) recordOutbound(msg *Message, env *RelayEnvelope, assignee, beadsDir string) (string, error) {
+	labels := []string{"gt:message", "from:" + msg.From, relayLabelPrefix + env.RelayID}
+	labels = append(labels, DeliverySendLabels()...)
+	if msg.ThreadID != "" {
+		labels = append(labels, "thread:"+msg.ThreadID)
+	}
+	if msg.ReplyTo != "" {
+		labels = append(labels, "reply-to:"+msg.ReplyTo)
+	}
+
+	args := []string{"create", "--json",
+		"--assignee", assignee,
+		"-d", msg.Body,
+		"--priority", fmt.Sprintf("%d", PriorityToBeads(msg.Priority)),
+		"--labels", strings.Join(labels, ","),
+		"--actor", msg.From,
+		"--", msg.Subject,
+	}
+
+	if err := r.ensureCustomTypes(beadsDir); err != nil {
+		return "", err
+	}
+	ctx, cancel := bdWriteCtx()
+	defer cancel()
+	out, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	if err != nil {
+		return "", fmt.Errorf("recording outbound message: %w", err)
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(out, &created); err != nil || created.ID == "" {
+		return "", fmt.Errorf("parsing outbound message ID from bd create output: %q", strings.TrimSpace(string(out)))
+	}
+	return created.ID, nil
+}
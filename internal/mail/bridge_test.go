@@ -0,0 +1,333 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/proxy"
+)
+
+func TestParseTownAddress(t *testing.T) {
+	tests := []struct {
+		address   string
+		wantTown  string
+		wantLocal string
+		wantErr   bool
+	}{
+		{"town:backend/mayor/", "backend", "mayor/", false},
+		{"town:backend/gastown/crew/max", "backend", "gastown/crew/max", false},
+		{"town:backend", "", "", true},
+		{"town:/mayor/", "", "", true},
+		{"town:backend/", "", "", true},
+		{"mayor/", "", "", true},
+	}
+	for _, tt := range tests {
+		town, local, err := ParseTownAddress(tt.address)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTownAddress(%q) error = %v, wantErr %v", tt.address, err, tt.wantErr)
+			continue
+		}
+		if town != tt.wantTown || local != tt.wantLocal {
+			t.Errorf("ParseTownAddress(%q) = (%q, %q), want (%q, %q)", tt.address, town, local, tt.wantTown, tt.wantLocal)
+		}
+	}
+}
+
+func TestNewRelayEnvelope(t *testing.T) {
+	msg := NewMessage("mayor/", "town:backend/mayor/", "Schema change", "Heads up")
+	env := newRelayEnvelope(msg, "frontend", "mayor/")
+
+	if env.From != "town:frontend/mayor/" {
+		t.Errorf("From = %q, want town:frontend/mayor/", env.From)
+	}
+	if env.OriginTown != "frontend" || len(env.Hops) != 1 || env.Hops[0] != "frontend" {
+		t.Errorf("origin/hops = %q/%v, want frontend/[frontend]", env.OriginTown, env.Hops)
+	}
+
+	// A message that arrived over the bridge keeps its path and qualified sender.
+	fwd := env.toMessage()
+	fwd.To = "town:ops/mayor/"
+	next := newRelayEnvelope(fwd, "backend", "mayor/")
+	if next.From != "town:frontend/mayor/" {
+		t.Errorf("forwarded From = %q, want town:frontend/mayor/", next.From)
+	}
+	if got := strings.Join(next.Hops, ","); got != "frontend,backend" {
+		t.Errorf("forwarded Hops = %q, want frontend,backend", got)
+	}
+	if err := next.checkHops("frontend"); !errors.Is(err, ErrRelayLoop) {
+		t.Errorf("checkHops(frontend) = %v, want ErrRelayLoop", err)
+	}
+	if err := next.checkHops("ops"); err != nil {
+		t.Errorf("checkHops(ops) = %v, want nil", err)
+	}
+}
+
+func TestRelayReceiptAckLabels(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	rc := &RelayReceipt{RelayID: "msg-1", Town: "backend", AckedBy: "town:backend/mayor", AckedAt: at}
+
+	state, by, ackedAt := ParseDeliveryLabels(append(DeliverySendLabels(), rc.AckLabels()...))
+	if state != DeliveryStateAcked {
+		t.Fatalf("state = %q, want %q", state, DeliveryStateAcked)
+	}
+	if by != "town:backend/mayor" {
+		t.Errorf("ackedBy = %q, want town:backend/mayor", by)
+	}
+	if ackedAt == nil || !ackedAt.Equal(at) {
+		t.Errorf("ackedAt = %v, want %v", ackedAt, at)
+	}
+}
+
+// bridgeRequest builds a relay request carrying a fake peer certificate CN.
+func bridgeRequest(t *testing.T, env *RelayEnvelope, cn string) *http.Request {
+	t.Helper()
+	body, err := json.Marshal(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, BridgeRelayPath, strings.NewReader(string(body)))
+	if cn != "" {
+		req.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: cn}}},
+		}
+	}
+	return req
+}
+
+func TestBridgeHandlerRejections(t *testing.T) {
+	delivered := 0
+	h := NewBridgeHandler("backend", []string{"frontend"}, func(*Message) error {
+		delivered++
+		return nil
+	})
+	msg := NewMessage("mayor/", "town:backend/mayor/", "hello", "body")
+
+	tests := []struct {
+		name   string
+		hops   []string
+		cn     string
+		status int
+	}{
+		{"no client certificate", []string{"frontend"}, "", http.StatusForbidden},
+		{"polecat certificate", []string{"frontend"}, "gt-gastown-rust", http.StatusForbidden},
+		{"unconfigured peer", []string{"ops"}, BridgeClientCN("ops"), http.StatusForbidden},
+		{"hop does not match certificate", []string{"ops", "backend"}, BridgeClientCN("frontend"), http.StatusForbidden},
+		{"loop back to receiver", []string{"backend", "frontend"}, BridgeClientCN("frontend"), http.StatusLoopDetected},
+		{"too many hops", []string{"a", "b", "c", "d", "e", "frontend"}, BridgeClientCN("frontend"), http.StatusLoopDetected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newRelayEnvelope(msg, "frontend", "mayor/")
+			env.Hops = tt.hops
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, bridgeRequest(t, env, tt.cn))
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d (body: %s)", rec.Code, tt.status, rec.Body.String())
+			}
+		})
+	}
+	if delivered != 0 {
+		t.Errorf("rejected relays delivered %d messages", delivered)
+	}
+}
+
+// testTown is one side of a two-town bridge test: a proxy server with its
+// own CA and a recording mail bridge.
+type testTown struct {
+	name string
+	ca   *proxy.CA
+	addr string
+
+	mu        sync.Mutex
+	delivered []*Message
+}
+
+func (tt *testTown) messages() []*Message {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	return append([]*Message(nil), tt.delivered...)
+}
+
+// startTestTown starts a gt-proxy-server for the named town that accepts
+// relays from peers. forward, if non-nil, handles messages addressed to
+// another town (simulating Router.Send's onward relay).
+func startTestTown(t *testing.T, name string, peers []string, forward func(*Message) error) *testTown {
+	t.Helper()
+	ca, err := proxy.GenerateCA(t.TempDir())
+	if err != nil {
+		t.Fatalf("GenerateCA: %v", err)
+	}
+	town := &testTown{name: name, ca: ca}
+
+	bridge := NewBridgeHandler(name, peers, func(msg *Message) error {
+		if isTownAddress(msg.To) && forward != nil {
+			return forward(msg)
+		}
+		town.mu.Lock()
+		defer town.mu.Unlock()
+		town.delivered = append(town.delivered, msg)
+		return nil
+	})
+
+	srv, err := proxy.New(proxy.Config{
+		ListenAddr: "127.0.0.1:0",
+		TownRoot:   t.TempDir(),
+		Logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+		MailBridge: bridge,
+	}, ca)
+	if err != nil {
+		t.Fatalf("proxy.New: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = srv.Start(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for srv.Addr() == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("proxy for town %s did not start", name)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	town.addr = srv.Addr().String()
+	return town
+}
+
+// remoteConfig issues a client certificate from remote's CA for the local
+// town and returns the messaging config entry the local town would use.
+func remoteConfig(t *testing.T, local string, remote *testTown) config.RemoteTownConfig {
+	t.Helper()
+	certPEM, keyPEM, err := remote.ca.IssuePolecat(BridgeClientCN(local), time.Hour)
+	if err != nil {
+		t.Fatalf("IssuePolecat: %v", err)
+	}
+	dir := t.TempDir()
+	cfg := config.RemoteTownConfig{
+		URL:      "https://" + remote.addr,
+		CertFile: filepath.Join(dir, "bridge.crt"),
+		KeyFile:  filepath.Join(dir, "bridge.key"),
+		CAFile:   filepath.Join(dir, "ca.crt"),
+	}
+	for path, data := range map[string][]byte{cfg.CertFile: certPEM, cfg.KeyFile: keyPEM, cfg.CAFile: remote.ca.CertPEM} {
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cfg
+}
+
+// TestBridgeTwoTowns relays mail between two local gt-proxy-server instances.
+func TestBridgeTwoTowns(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping two-proxy bridge test in short mode")
+	}
+
+	var backendToFrontend *BridgeClient
+	frontend := startTestTown(t, "frontend", []string{"backend"}, nil)
+	backend := startTestTown(t, "backend", []string{"frontend"}, func(msg *Message) error {
+		// Onward relay as Router.sendToRemoteTown would do it.
+		town, local, err := ParseTownAddress(msg.To)
+		if err != nil {
+			return err
+		}
+		env := newRelayEnvelope(msg, "backend", local)
+		if err := env.checkHops(town); err != nil {
+			return err
+		}
+		_, err = backendToFrontend.Relay(context.Background(), env)
+		return err
+	})
+
+	frontendToBackend, err := NewBridgeClient(remoteConfig(t, "frontend", backend))
+	if err != nil {
+		t.Fatalf("NewBridgeClient: %v", err)
+	}
+	backendToFrontend, err = NewBridgeClient(remoteConfig(t, "backend", frontend))
+	if err != nil {
+		t.Fatalf("NewBridgeClient: %v", err)
+	}
+
+	msg := NewMessage("mayor/", "town:backend/mayor/", "API v2 ready", "Please switch clients.")
+	env := newRelayEnvelope(msg, "frontend", "mayor/")
+
+	t.Run("delivers with receipt", func(t *testing.T) {
+		receipt, err := frontendToBackend.Relay(context.Background(), env)
+		if err != nil {
+			t.Fatalf("Relay: %v", err)
+		}
+		if receipt.Town != "backend" || receipt.AckedBy != "town:backend/mayor/" || receipt.Duplicate {
+			t.Errorf("receipt = %+v", receipt)
+		}
+		got := backend.messages()
+		if len(got) != 1 {
+			t.Fatalf("backend received %d messages, want 1", len(got))
+		}
+		if got[0].From != "town:frontend/mayor/" || got[0].To != "mayor/" || got[0].Subject != "API v2 ready" {
+			t.Errorf("delivered message = %+v", got[0])
+		}
+	})
+
+	t.Run("retry is deduplicated", func(t *testing.T) {
+		receipt, err := frontendToBackend.Relay(context.Background(), env)
+		if err != nil {
+			t.Fatalf("Relay: %v", err)
+		}
+		if !receipt.Duplicate {
+			t.Error("expected duplicate receipt on retry")
+		}
+		if n := len(backend.messages()); n != 1 {
+			t.Errorf("backend received %d messages after retry, want 1", n)
+		}
+	})
+
+	t.Run("reply travels back", func(t *testing.T) {
+		reply := NewMessage("mayor/", "town:frontend/mayor/", "Re: API v2 ready", "Done.")
+		if _, err := backendToFrontend.Relay(context.Background(), newRelayEnvelope(reply, "backend", "mayor/")); err != nil {
+			t.Fatalf("Relay: %v", err)
+		}
+		got := frontend.messages()
+		if len(got) != 1 || got[0].From != "town:backend/mayor/" {
+			t.Fatalf("frontend received %+v", got)
+		}
+	})
+
+	t.Run("forward back to origin is a loop", func(t *testing.T) {
+		bounce := NewMessage("mayor/", "town:backend/mayor/", "bounce", "")
+		bounceEnv := newRelayEnvelope(bounce, "frontend", "town:frontend/mayor/")
+		_, err := frontendToBackend.Relay(context.Background(), bounceEnv)
+		if !errors.Is(err, ErrRelayLoop) {
+			t.Fatalf("Relay error = %v, want ErrRelayLoop", err)
+		}
+		if n := len(frontend.messages()); n != 1 {
+			t.Errorf("frontend received %d messages, want 1 (bounce must not arrive)", n)
+		}
+	})
+
+	t.Run("certificate from wrong CA is rejected", func(t *testing.T) {
+		wrong := remoteConfig(t, "frontend", frontend) // signed by frontend's CA
+		wrong.URL = "https://" + backend.addr
+		wrong.CAFile = remoteConfig(t, "frontend", backend).CAFile
+		client, err := NewBridgeClient(wrong)
+		if err != nil {
+			t.Fatalf("NewBridgeClient: %v", err)
+		}
+		if _, err := client.Relay(context.Background(), newRelayEnvelope(msg, "frontend", "mayor/")); err == nil {
+			t.Fatal("expected TLS rejection for certificate from another CA")
+		}
+	})
+}
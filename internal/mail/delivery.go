@@ -86,7 +86,15 @@ func AcknowledgeDeliveryBead(workDir, beadsDir, beadID, recipientIdentity string
 		fmt.Fprintf(os.Stderr, "delivery ack: could not read labels for %s: %v (proceeding with fresh timestamp)\n", beadID, readErr)
 	}
 
-	for _, label := range DeliveryAckLabelSequenceIdempotent(recipientIdentity, timeNow().UTC(), existingLabels) {
+	return writeDeliveryAckLabels(workDir, beadsDir, beadID,
+		DeliveryAckLabelSequenceIdempotent(recipientIdentity, timeNow().UTC(), existingLabels))
+}
+
+// writeDeliveryAckLabels writes an ack label sequence to a bead in order,
+// stopping at the first failure so the bead stays pending until the final
+// delivery:acked label lands.
+func writeDeliveryAckLabels(workDir, beadsDir, beadID string, labels []string) error {
+	for _, label := range labels {
 		args := []string{"label", "add", beadID, label}
 		ctx, cancel := bdWriteCtx()
 		_, err := runBdCommand(ctx, args, workDir, beadsDir)
@@ -81,8 +81,9 @@ func (r *Resolver) resolveWithVisited(address string, visited map[string]bool) (
 		return r.resolveChannel(name)
 	}
 
-	// Legacy prefixes (list:, announce:) - pass through
-	if strings.HasPrefix(address, "list:") || strings.HasPrefix(address, "announce:") {
+	// Legacy prefixes (list:, announce:) and remote towns (town:) - pass through.
+	// Remote addresses are validated by the receiving town's bridge.
+	if strings.HasPrefix(address, "list:") || strings.HasPrefix(address, "announce:") || isTownAddress(address) {
 		// These are handled by existing router logic
 		return []Recipient{{Address: address, Type: RecipientAgent}}, nil
 	}
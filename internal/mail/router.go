@@ -821,7 +821,13 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 // Supports single-copy delivery for:
 // - Queues (queue:name) - stores single message for worker claiming
 // - Announces (announce:name) - bulletin board, no claiming, retention-limited
+// - Remote towns (town:name/address) - relayed over the mTLS mail bridge
 func (r *Router) Send(msg *Message) error {
+	// Check for federated address - relay to another town's mail bridge
+	if isTownAddress(msg.To) {
+		return r.sendToRemoteTown(msg)
+	}
+
 	// Check for mailing list address
 	if isListAddress(msg.To) {
 		return r.sendToList(msg)
@@ -1580,7 +1586,9 @@ func (r *Router) notifyRecipient(msg *Message) error {
 	// This handles the ambiguity where canonical addresses (rig/name) don't
 	// distinguish between crew workers (gt-rig-crew-name) and polecats (gt-rig-name).
 	for _, sessionID := range sessionIDs {
-		hasSession, err := r.tmux.HasSession(sessionID)
+		// The session may run in tmux or under a PTY supervisor.
+		b := session.BackendForWith(r.tmux, r.townRoot, sessionID)
+		hasSession, err := b.HasSession(sessionID)
 		if err != nil || !hasSession {
 			continue
 		}
@@ -1588,7 +1596,10 @@ func (r *Router) notifyRecipient(msg *Message) error {
 		// Overseer is a human operator - use a visible banner instead of NudgeSession
 		// (which types into Claude's input and would disrupt the human's terminal).
 		if msg.To == "overseer" {
-			return r.tmux.SendNotificationBanner(sessionID, msg.From, msg.Subject)
+			if tb, ok := b.(*tmux.Tmux); ok {
+				return tb.SendNotificationBanner(sessionID, msg.From, msg.Subject)
+			}
+			return nil // No banner outside tmux; the overseer checks mail
 		}
 
 		notification := fmt.Sprintf("📬 You have new mail from %s. Subject: %s. Run 'gt mail inbox' to read.", msg.From, msg.Subject)
@@ -1598,10 +1609,13 @@ func (r *Router) notifyRecipient(msg *Message) error {
 		// polling (~15 polls) distinguishes a genuine idle prompt (persists
 		// indefinitely) from brief inter-tool-call gaps (~500ms).
 		// See: https://github.com/steveyegge/gastown/issues/2032
-		waitErr := r.tmux.WaitForIdle(sessionID, timeout)
+		waitErr := errors.ErrUnsupported
+		if w, ok := b.(session.IdleWaiter); ok {
+			waitErr = w.WaitForIdle(sessionID, timeout)
+		}
 		if waitErr == nil {
 			// Agent is idle — deliver directly for immediate wakeup.
-			if err := r.tmux.NudgeSession(sessionID, notification); err == nil {
+			if err := b.NudgeSession(sessionID, notification); err == nil {
 				return nil
 			} else if errors.Is(err, tmux.ErrSessionNotFound) {
 				continue
@@ -1621,7 +1635,7 @@ func (r *Router) notifyRecipient(msg *Message) error {
 			})
 		}
 		// No town root available — last resort direct delivery.
-		return r.tmux.NudgeSession(sessionID, notification)
+		return b.NudgeSession(sessionID, notification)
 	}
 
 	return nil // No active session found
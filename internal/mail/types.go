@@ -134,6 +134,10 @@ type Message struct {
 	// (no nudge, no banner). Set by the CLI when --no-notify is passed.
 	// In-memory only — not serialized.
 	SuppressNotify bool `json:"-"`
+
+	// relayHops is the town path of a message received over the mail bridge.
+	// Fan-out copies inherit it so any onward relay keeps loop detection.
+	relayHops []string
 }
 
 // NewMessage creates a new message with a generated ID and thread ID.
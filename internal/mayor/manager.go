@@ -48,12 +48,11 @@ func (m *Manager) mayorDir() string {
 // Start starts the mayor session.
 // agentOverride optionally specifies a different agent alias to use.
 func (m *Manager) Start(agentOverride string) error {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
 
 	// Kill any existing zombie session (tmux alive but agent dead).
 	// Returns error if session is healthy and already running.
-	_, err := session.KillExistingSession(t, sessionID, true)
+	_, err := session.KillExistingSession(session.BackendFor(m.townRoot, sessionID), sessionID, true)
 	if err != nil {
 		return ErrAlreadyRunning
 	}
@@ -66,7 +65,7 @@ func (m *Manager) Start(agentOverride string) error {
 
 	// Use unified session lifecycle for config → settings → command → create → env → theme → wait.
 	theme := tmux.MayorTheme()
-	_, err = session.StartSession(t, session.SessionConfig{
+	_, err = session.StartSession(session.NewBackend(m.townRoot), session.SessionConfig{
 		SessionID: sessionID,
 		WorkDir:   mayorDir,
 		Role:      "mayor",
@@ -95,8 +94,8 @@ func (m *Manager) Start(agentOverride string) error {
 
 // Stop stops the mayor session.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
+	t := session.BackendFor(m.townRoot, sessionID)
 
 	// Check if session exists
 	running, err := t.HasSession(sessionID)
@@ -121,14 +120,13 @@ func (m *Manager) Stop() error {
 
 // IsRunning checks if the mayor session is active.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
-	return t.HasSession(m.SessionName())
+	return session.BackendFor(m.townRoot, m.SessionName()).HasSession(m.SessionName())
 }
 
 // Status returns information about the mayor session.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
+	t := session.BackendFor(m.townRoot, sessionID)
 
 	running, err := t.HasSession(sessionID)
 	if err != nil {
@@ -138,5 +136,9 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	if tm, ok := t.(*tmux.Tmux); ok {
+		return tm.GetSessionInfo(sessionID)
+	}
+	// PTY sessions have no tmux window metadata.
+	return &tmux.SessionInfo{Name: sessionID}, nil
 }
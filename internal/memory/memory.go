@@ -0,0 +1,414 @@
+// Package memory models agent memories stored in the beads key-value store
+// and selects the ones worth injecting into an agent's prime output.
+//
+// A memory value is either legacy plain text (town-scoped, no metadata) or a
+// JSON envelope carrying the content together with its scope, author,
+// creation time and optional expiry. Prime selects the memories whose scope
+// matches the current role, rig and hooked bead, ranks them by relevance and
+// recency, and caps the result to a token budget.
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// KeyPrefix is the beads kv prefix shared by all memories.
+const KeyPrefix = "memory."
+
+// Scope kinds accepted by ParseScope.
+const (
+	ScopeTown = "town"
+	ScopeRig  = "rig"
+	ScopeRole = "role"
+	ScopePath = "path"
+)
+
+// Scope restricts where a memory is injected. Within a kind, any listed value
+// matches; across kinds, every non-empty kind must match. The zero Scope is
+// town-wide.
+type Scope struct {
+	Rigs  []string `json:"rigs,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+	Paths []string `json:"paths,omitempty"` // globs; "**" spans directories
+}
+
+// IsTown reports whether the scope applies everywhere.
+func (s Scope) IsTown() bool {
+	return len(s.Rigs) == 0 && len(s.Roles) == 0 && len(s.Paths) == 0
+}
+
+// String renders the scope in ParseScope syntax, e.g. "rig:gastown,role:refinery".
+func (s Scope) String() string {
+	if s.IsTown() {
+		return ScopeTown
+	}
+	var parts []string
+	for _, r := range s.Rigs {
+		parts = append(parts, ScopeRig+":"+r)
+	}
+	for _, r := range s.Roles {
+		parts = append(parts, ScopeRole+":"+r)
+	}
+	for _, p := range s.Paths {
+		parts = append(parts, ScopePath+":"+p)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseScope parses scope specs of the form "town", "rig:<name>",
+// "role:<role>" or "path:<glob>". Each spec may itself be a comma-separated
+// list. No specs (or only "town") yields the town scope.
+func ParseScope(specs []string) (Scope, error) {
+	var s Scope
+	for _, spec := range specs {
+		for _, part := range strings.Split(spec, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" || part == ScopeTown {
+				continue
+			}
+			kind, value, ok := strings.Cut(part, ":")
+			value = strings.TrimSpace(value)
+			if !ok || value == "" {
+				return Scope{}, fmt.Errorf("invalid scope %q (use town, rig:<name>, role:<role> or path:<glob>)", part)
+			}
+			switch kind {
+			case ScopeRig:
+				s.Rigs = appendUnique(s.Rigs, value)
+			case ScopeRole:
+				s.Roles = appendUnique(s.Roles, value)
+			case ScopePath:
+				s.Paths = appendUnique(s.Paths, value)
+			default:
+				return Scope{}, fmt.Errorf("unknown scope kind %q (use town, rig, role or path)", kind)
+			}
+		}
+	}
+	return s, nil
+}
+
+func appendUnique(list []string, v string) []string {
+	for _, existing := range list {
+		if existing == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
+
+// Memory is a single stored memory.
+type Memory struct {
+	// Key is the short key, without KeyPrefix. Not part of the stored value.
+	Key string `json:"-"`
+
+	Content   string    `json:"content"`
+	Scope     Scope     `json:"scope,omitzero"`
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// Parse decodes a stored kv value. Values that are not a JSON envelope are
+// legacy plain-text memories and parse as town-scoped content.
+func Parse(key, value string) Memory {
+	key = strings.TrimPrefix(key, KeyPrefix)
+	if strings.HasPrefix(strings.TrimSpace(value), "{") {
+		var m Memory
+		if err := json.Unmarshal([]byte(value), &m); err == nil && m.Content != "" {
+			m.Key = key
+			return m
+		}
+	}
+	return Memory{Key: key, Content: value}
+}
+
+// Encode returns the kv value for m.
+func (m Memory) Encode() (string, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Expired reports whether m has an expiry at or before now.
+func (m Memory) Expired(now time.Time) bool {
+	return !m.ExpiresAt.IsZero() && !now.Before(m.ExpiresAt)
+}
+
+// Stale reports whether m is expired, or older than staleAfter (when
+// positive). Legacy memories without a creation time are never stale by age.
+func (m Memory) Stale(now time.Time, staleAfter time.Duration) bool {
+	if m.Expired(now) {
+		return true
+	}
+	return staleAfter > 0 && !m.CreatedAt.IsZero() && now.Sub(m.CreatedAt) > staleAfter
+}
+
+// Tokens estimates the prompt tokens m costs when injected.
+func (m Memory) Tokens() int {
+	return EstimateTokens(m.Key) + EstimateTokens(m.Content) + 4 // list markup
+}
+
+// EstimateTokens approximates the token count of s (about four bytes per token).
+func EstimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// FromKV parses every memory.* entry of a kv listing, sorted by key.
+func FromKV(kvs map[string]string) []Memory {
+	var memories []Memory
+	for k, v := range kvs {
+		if !strings.HasPrefix(k, KeyPrefix) {
+			continue
+		}
+		memories = append(memories, Parse(k, v))
+	}
+	sort.Slice(memories, func(i, j int) bool { return memories[i].Key < memories[j].Key })
+	return memories
+}
+
+// Context describes the agent a selection is made for.
+type Context struct {
+	Role string
+	Rig  string
+
+	// Paths are repo-relative paths the agent is working on, matched
+	// against path-scoped memories.
+	Paths []string
+
+	// Text is free text describing the current work (the hooked bead's
+	// title and description), used to rank memories by keyword overlap.
+	Text string
+
+	Now time.Time
+}
+
+// Match reports whether m's scope applies to ctx. The returned specificity
+// counts the scope kinds that matched; town-wide memories match with 0.
+func (m Memory) Match(ctx Context) (specificity int, ok bool) {
+	s := m.Scope
+	if len(s.Rigs) > 0 {
+		if !containsFold(s.Rigs, ctx.Rig) {
+			return 0, false
+		}
+		specificity++
+	}
+	if len(s.Roles) > 0 {
+		if !containsFold(s.Roles, ctx.Role) {
+			return 0, false
+		}
+		specificity++
+	}
+	if len(s.Paths) > 0 {
+		if !anyGlobMatch(s.Paths, ctx.Paths) {
+			return 0, false
+		}
+		specificity++
+	}
+	return specificity, true
+}
+
+func containsFold(list []string, v string) bool {
+	if v == "" {
+		return false
+	}
+	for _, item := range list {
+		if strings.EqualFold(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyGlobMatch(globs, paths []string) bool {
+	for _, g := range globs {
+		re := globRegexp(g)
+		for _, p := range paths {
+			if re.MatchString(strings.TrimPrefix(p, "./")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// globRegexp compiles a path glob: "*" and "?" stay within a path segment,
+// "**" spans segments. A glob without a slash matches a base name anywhere.
+func globRegexp(glob string) *regexp.Regexp {
+	glob = strings.TrimPrefix(glob, "./")
+	var b strings.Builder
+	b.WriteString("^")
+	if !strings.Contains(glob, "/") {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("(?:/.*)?$")
+	return regexp.MustCompile(b.String())
+}
+
+// pathPattern finds path-like tokens (containing a slash or a file
+// extension) in free text.
+var pathPattern = regexp.MustCompile(`[A-Za-z0-9_.-]+(?:/[A-Za-z0-9_.*-]+)+|[A-Za-z0-9_-]+\.[A-Za-z0-9]{1,5}\b`)
+
+// PathsInText extracts path-like tokens from text, such as file names
+// mentioned in a bead description.
+func PathsInText(text string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, p := range pathPattern.FindAllString(text, -1) {
+		p = strings.Trim(p, ".")
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// Ranking weights. Scope specificity dominates, keyword overlap with the
+// current work comes next, and recency breaks the remaining ties.
+const (
+	specificityWeight = 6.0
+	keywordWeight     = 1.0
+	maxKeywordScore   = 5
+	recencyHalfLife   = 30 * 24 * time.Hour
+)
+
+// Scored is a selected memory with its ranking score.
+type Scored struct {
+	Memory
+	Score float64
+}
+
+// Selection is the outcome of Select.
+type Selection struct {
+	// Selected memories, best first.
+	Selected []Scored
+
+	// Tokens is the estimated token cost of Selected.
+	Tokens int
+
+	// Dropped counts matching memories left out by the budget.
+	Dropped int
+}
+
+// Select returns the memories that apply to ctx, ranked by relevance and
+// recency and capped to budget tokens (no cap when budget <= 0). Expired
+// memories are never selected. A memory that does not fit is skipped so
+// smaller, lower-ranked memories can still use the remaining budget.
+func Select(memories []Memory, ctx Context, budget int) Selection {
+	if ctx.Now.IsZero() {
+		ctx.Now = time.Now()
+	}
+	words := keywords(ctx.Text)
+
+	var candidates []Scored
+	for _, m := range memories {
+		if m.Expired(ctx.Now) {
+			continue
+		}
+		specificity, ok := m.Match(ctx)
+		if !ok {
+			continue
+		}
+		score := specificityWeight*float64(specificity) +
+			keywordWeight*float64(keywordOverlap(words, m.Key+" "+m.Content)) +
+			recency(m.CreatedAt, ctx.Now)
+		candidates = append(candidates, Scored{Memory: m, Score: score})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Key < candidates[j].Key
+	})
+
+	var sel Selection
+	for _, c := range candidates {
+		cost := c.Tokens()
+		if budget > 0 && sel.Tokens+cost > budget {
+			sel.Dropped++
+			continue
+		}
+		sel.Selected = append(sel.Selected, c)
+		sel.Tokens += cost
+	}
+	return sel
+}
+
+// recency scores a creation time in (0, 1], halving every recencyHalfLife.
+// Legacy memories without a creation time score as if a half-life old.
+func recency(created, now time.Time) float64 {
+	if created.IsZero() {
+		return 0.5
+	}
+	age := now.Sub(created)
+	if age < 0 {
+		age = 0
+	}
+	return math.Pow(0.5, float64(age)/float64(recencyHalfLife))
+}
+
+// stopWords are ignored when ranking by keyword overlap.
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true, "this": true,
+	"from": true, "into": true, "when": true, "not": true, "use": true, "are": true,
+	"was": true, "but": true, "you": true, "all": true, "can": true, "has": true,
+}
+
+// keywords returns the distinct significant lowercase words of text.
+func keywords(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	}) {
+		if len(w) >= 3 && !stopWords[w] {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+// keywordOverlap counts distinct words of text present in words, capped at
+// maxKeywordScore so one verbose memory can't outrank a scoped one.
+func keywordOverlap(words map[string]bool, text string) int {
+	if len(words) == 0 {
+		return 0
+	}
+	n := 0
+	for w := range keywords(text) {
+		if words[w] {
+			n++
+			if n == maxKeywordScore {
+				break
+			}
+		}
+	}
+	return n
+}
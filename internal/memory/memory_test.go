@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse_LegacyAndEnvelope(t *testing.T) {
+	legacy := Parse("memory.refinery-worktree", "Refinery uses worktree")
+	if legacy.Key != "refinery-worktree" || legacy.Content != "Refinery uses worktree" || !legacy.Scope.IsTown() {
+		t.Errorf("legacy = %+v", legacy)
+	}
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	m := Memory{
+		Key:       "tmux",
+		Content:   "tmux tests need a live server",
+		Scope:     Scope{Rigs: []string{"gastown"}, Paths: []string{"internal/tmux/**"}},
+		Author:    "gastown/crew/joe",
+		CreatedAt: created,
+	}
+	value, err := m.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(value, "expires_at") || strings.Contains(value, `"Key"`) {
+		t.Errorf("unexpected fields in %s", value)
+	}
+	got := Parse(KeyPrefix+"tmux", value)
+	if got.Content != m.Content || got.Author != m.Author || !got.CreatedAt.Equal(created) || got.Scope.String() != "rig:gastown,path:internal/tmux/**" {
+		t.Errorf("round trip = %+v", got)
+	}
+
+	// JSON that isn't an envelope is still plain content.
+	if got := Parse("k", `{"foo": 1}`); got.Content != `{"foo": 1}` {
+		t.Errorf("non-envelope JSON = %+v", got)
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	s, err := ParseScope([]string{"rig:gastown,role:refinery", "role:witness", "town"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.String(); got != "rig:gastown,role:refinery,role:witness" {
+		t.Errorf("scope = %q", got)
+	}
+	if s, _ := ParseScope(nil); !s.IsTown() || s.String() != "town" {
+		t.Errorf("empty scope = %+v", s)
+	}
+	for _, bad := range []string{"rig", "rig:", "team:core"} {
+		if _, err := ParseScope([]string{bad}); err == nil {
+			t.Errorf("ParseScope(%q) should fail", bad)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	ctx := Context{Role: "polecat", Rig: "gastown", Paths: []string{"internal/tmux/tmux.go", "docs/README.md"}}
+	tests := []struct {
+		scope Scope
+		want  int
+		ok    bool
+	}{
+		{Scope{}, 0, true},
+		{Scope{Rigs: []string{"beads", "GasTown"}}, 1, true},
+		{Scope{Rigs: []string{"beads"}}, 0, false},
+		{Scope{Rigs: []string{"gastown"}, Roles: []string{"refinery"}}, 0, false},
+		{Scope{Rigs: []string{"gastown"}, Roles: []string{"polecat"}}, 2, true},
+		{Scope{Paths: []string{"internal/tmux/**"}}, 1, true},
+		{Scope{Paths: []string{"internal/*.go"}}, 0, false},
+		{Scope{Paths: []string{"internal/**/*.go"}}, 1, true},
+		{Scope{Paths: []string{"*.md"}}, 1, true},
+		{Scope{Paths: []string{"internal"}}, 1, true},
+	}
+	for _, tt := range tests {
+		got, ok := Memory{Scope: tt.scope}.Match(ctx)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Match(%s) = %d, %v; want %d, %v", tt.scope, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	// Path-scoped memories need a path to match against.
+	if _, ok := (Memory{Scope: Scope{Paths: []string{"**"}}}).Match(Context{}); ok {
+		t.Error("path scope should not match without paths")
+	}
+}
+
+func TestPathsInText(t *testing.T) {
+	got := PathsInText("Fix race in internal/tmux/tmux.go (see go.mod and docs/guide).")
+	want := "internal/tmux/tmux.go,go.mod,docs/guide"
+	if strings.Join(got, ",") != want {
+		t.Errorf("PathsInText = %v, want %s", got, want)
+	}
+}
+
+func TestSelect_RanksFiltersAndBudgets(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	memories := []Memory{
+		{Key: "town-old", Content: "General advice", CreatedAt: now.Add(-300 * 24 * time.Hour)},
+		{Key: "town-new", Content: "General advice", CreatedAt: now.Add(-time.Hour)},
+		{Key: "merge-slot", Content: "Merge slot contention shows up as push retries"},
+		{Key: "refinery", Content: "Refinery uses a worktree", Scope: Scope{Roles: []string{"refinery"}}},
+		{Key: "rig", Content: "Gastown tests are slow", Scope: Scope{Rigs: []string{"gastown"}}},
+		{Key: "expired", Content: "Main is frozen", ExpiresAt: now.Add(-time.Minute)},
+		{Key: "big", Content: strings.Repeat("x", 4000)},
+	}
+	ctx := Context{Role: "polecat", Rig: "gastown", Text: "Reduce merge slot contention", Now: now}
+
+	sel := Select(memories, ctx, 0)
+	var keys []string
+	for _, s := range sel.Selected {
+		keys = append(keys, s.Key)
+	}
+	want := "rig,merge-slot,town-new,big,town-old"
+	if strings.Join(keys, ",") != want {
+		t.Fatalf("selected = %v, want %s", keys, want)
+	}
+
+	// A budget skips memories that don't fit but keeps smaller later ones.
+	sel = Select(memories, ctx, 100)
+	keys = keys[:0]
+	for _, s := range sel.Selected {
+		keys = append(keys, s.Key)
+	}
+	if strings.Join(keys, ",") != "rig,merge-slot,town-new,town-old" || sel.Dropped != 1 {
+		t.Errorf("budgeted = %v (dropped %d), want big dropped", keys, sel.Dropped)
+	}
+	if sel.Tokens > 100 {
+		t.Errorf("Tokens = %d, over budget", sel.Tokens)
+	}
+}
+
+func TestRecency_HalvesEachHalfLife(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		age  time.Duration
+		want float64
+	}{
+		{0, 1},
+		{recencyHalfLife, 0.5},
+		{2 * recencyHalfLife, 0.25},
+		{3 * recencyHalfLife, 0.125},
+	}
+	for _, tt := range tests {
+		if got := recency(now.Add(-tt.age), now); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("recency(age=%v) = %v, want %v", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestStale(t *testing.T) {
+	now := time.Now()
+	fresh := Memory{CreatedAt: now.Add(-time.Hour)}
+	old := Memory{CreatedAt: now.Add(-100 * 24 * time.Hour)}
+	expired := Memory{CreatedAt: now, ExpiresAt: now.Add(-time.Second)}
+	legacy := Memory{}
+
+	for name, tt := range map[string]struct {
+		m    Memory
+		want bool
+	}{
+		"fresh":   {fresh, false},
+		"old":     {old, true},
+		"expired": {expired, true},
+		"legacy":  {legacy, false},
+	} {
+		if got := tt.m.Stale(now, 90*24*time.Hour); got != tt.want {
+			t.Errorf("%s: Stale = %v, want %v", name, got, tt.want)
+		}
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 // Scanner discovers plugins in town and rig directories.
@@ -54,12 +57,31 @@ func (s *Scanner) DiscoverAll() ([]*Plugin, error) {
 	// Convert map to slice
 	plugins := make([]*Plugin, 0, len(pluginMap))
 	for _, p := range pluginMap {
+		s.setEnabledRigs(p)
 		plugins = append(plugins, p)
 	}
 
 	return plugins, nil
 }
 
+// setEnabledRigs records which rigs enable a town-level plugin in their
+// settings.
+func (s *Scanner) setEnabledRigs(p *Plugin) {
+	if p.Location != LocationTown {
+		return
+	}
+	for _, rigName := range s.rigNames {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(s.townRoot, rigName)))
+		if err != nil {
+			continue
+		}
+		if slices.Contains(settings.Plugins, p.Name) {
+			p.EnabledRigs = append(p.EnabledRigs, rigName)
+		}
+	}
+	sort.Strings(p.EnabledRigs)
+}
+
 // scanTownPlugins scans the town-level plugins directory.
 func (s *Scanner) scanTownPlugins() ([]*Plugin, error) {
 	pluginsDir := filepath.Join(s.townRoot, "plugins")
@@ -214,6 +236,7 @@ func (s *Scanner) GetPlugin(name string) (*Plugin, error) {
 	if plugin == nil {
 		return nil, fmt.Errorf("plugin not found: %s", name)
 	}
+	s.setEnabledRigs(plugin)
 
 	return plugin, nil
 }
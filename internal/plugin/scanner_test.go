@@ -3,7 +3,10 @@ package plugin
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 func TestParsePluginMD(t *testing.T) {
@@ -354,6 +357,38 @@ version = 1
 	}
 }
 
+func TestScanner_EnabledRigs(t *testing.T) {
+	townRoot := t.TempDir()
+	pluginDir := filepath.Join(townRoot, "plugins", "lint-sweep")
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.md"), []byte("+++\nname = \"lint-sweep\"\n+++\nSweep lint.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.NewRigSettings()
+	settings.Plugins = []string{"lint-sweep"}
+	if err := config.SaveRigSettings(config.RigSettingsPath(filepath.Join(townRoot, "api")), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := NewScanner(townRoot, []string{"web", "api"})
+	plugins, err := scanner.DiscoverAll()
+	if err != nil {
+		t.Fatalf("DiscoverAll failed: %v", err)
+	}
+	if len(plugins) != 1 || plugins[0].Location != LocationTown || len(plugins[0].EnabledRigs) != 1 || plugins[0].EnabledRigs[0] != "api" {
+		t.Fatalf("plugins = %+v, want town-level lint-sweep enabled by api", plugins)
+	}
+	if body := plugins[0].FormatMailBody(); !strings.Contains(body, "**Enabled by rigs**: api") {
+		t.Errorf("mail body missing enabled rigs:\n%s", body)
+	}
+	p, err := scanner.GetPlugin("lint-sweep")
+	if err != nil || len(p.EnabledRigs) != 1 {
+		t.Errorf("GetPlugin = %+v, %v; want enabled rigs", p, err)
+	}
+}
+
 func TestParsePluginMD_GitHubSheriff(t *testing.T) {
 	// Verify the actual github-sheriff plugin.md parses correctly.
 	// This catches frontmatter regressions in the shipped plugin.
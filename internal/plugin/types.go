@@ -33,6 +33,10 @@ type Plugin struct {
 	// RigName is set for rig-level plugins (empty for town-level).
 	RigName string `json:"rig_name,omitempty"`
 
+	// EnabledRigs lists the rigs whose settings enable this town-level
+	// plugin (the rig settings "plugins" list).
+	EnabledRigs []string `json:"enabled_rigs,omitempty"`
+
 	// Gate defines when the plugin should run.
 	Gate *Gate `json:"gate,omitempty"`
 
@@ -132,6 +136,7 @@ type PluginSummary struct {
 	Description string   `json:"description"`
 	Location    Location `json:"location"`
 	RigName     string   `json:"rig_name,omitempty"`
+	EnabledRigs []string `json:"enabled_rigs,omitempty"`
 	GateType    GateType `json:"gate_type,omitempty"`
 	Path        string   `json:"path"`
 }
@@ -150,6 +155,7 @@ func (p *Plugin) Summary() PluginSummary {
 		Description: p.Description,
 		Location:    p.Location,
 		RigName:     p.RigName,
+		EnabledRigs: p.EnabledRigs,
 		GateType:    gateType,
 		Path:        p.Path,
 	}
@@ -167,6 +173,9 @@ func (p *Plugin) FormatMailBody() string {
 	if p.RigName != "" {
 		sb.WriteString(fmt.Sprintf("**Rig**: %s\n", p.RigName))
 	}
+	if len(p.EnabledRigs) > 0 {
+		sb.WriteString(fmt.Sprintf("**Enabled by rigs**: %s\n", strings.Join(p.EnabledRigs, ", ")))
+	}
 	if p.Execution != nil && p.Execution.Timeout != "" {
 		sb.WriteString(fmt.Sprintf("**Timeout**: %s\n", p.Execution.Timeout))
 	}
@@ -681,6 +681,10 @@ func (m *Manager) AllocateAndAdd(opts AddOptions) (string, *Polecat, error) {
 	if err != nil {
 		return "", nil, err
 	}
+	// Creating a polecat from cold on a pooled rig means the pool ran dry.
+	if m.PoolConfig() != nil {
+		telemetry.RecordPolecatPool(context.Background(), m.rig.Name, name, false)
+	}
 	return name, p, nil
 }
 
@@ -1492,7 +1496,7 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 //
 // Steps:
 //  1. Verify polecat exists and worktree is accessible
-//  2. Fetch latest from origin
+//  2. Fetch latest from origin (skipped for a warm pooled polecat, see pool.go)
 //  3. Create fresh branch: git checkout -b <branch> <startPoint>
 //  4. Reset agent bead and set hook_bead atomically
 //  5. Return polecat in working state
@@ -1516,38 +1520,53 @@ func (m *Manager) ReuseIdlePolecat(name string, opts AddOptions) (*Polecat, erro
 
 	polecatGit := git.NewGit(clonePath)
 
-	// Fetch latest from origin (non-fatal: may be offline)
-	repoGit, err := m.repoBase()
-	if err == nil {
-		_ = repoGit.Fetch("origin")
+	// Determine the start point for the new branch
+	startPoint := opts.BaseBranch
+	if startPoint == "" {
+		startPoint = m.defaultStartPoint()
 	}
-	// Also fetch in the worktree itself so it has the latest refs
-	_ = polecatGit.Fetch("origin")
 
-	// Determine the start point for the new branch
-	var startPoint string
-	if opts.BaseBranch != "" {
-		startPoint = opts.BaseBranch
+	// Take the polecat from any warmup still running in it; WarmPolecat
+	// doesn't hold the lock while warming, so this doesn't wait for it.
+	m.stopWarmup(name)
+
+	// A warm pooled polecat is already at the latest start point with its
+	// warmup run (kept fresh by FillPool), so it skips the fetch entirely.
+	pool := m.PoolConfig()
+	warm := m.WarmState(name)
+	if warm != nil && warm.Target != startPoint {
+		warm = nil
+	}
+	branchFrom := startPoint
+	if warm != nil {
+		branchFrom = warm.Commit
 	} else {
-		defaultBranch := "main"
-		if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
-			defaultBranch = rigCfg.DefaultBranch
+		// Fetch latest from origin (non-fatal: may be offline)
+		repoGit, err := m.repoBase()
+		if err == nil {
+			_ = repoGit.Fetch("origin")
 		}
-		startPoint = fmt.Sprintf("origin/%s", defaultBranch)
-	}
+		// Also fetch in the worktree itself so it has the latest refs
+		_ = polecatGit.Fetch("origin")
 
-	// Validate that startPoint ref exists
-	if exists, err := polecatGit.RefExists(startPoint); err != nil {
-		return nil, fmt.Errorf("checking ref %s: %w", startPoint, err)
-	} else if !exists {
-		return nil, fmt.Errorf("start point %s not found — fall back to full repair", startPoint)
+		// Validate that startPoint ref exists
+		if exists, err := polecatGit.RefExists(startPoint); err != nil {
+			return nil, fmt.Errorf("checking ref %s: %w", startPoint, err)
+		} else if !exists {
+			return nil, fmt.Errorf("start point %s not found — fall back to full repair", startPoint)
+		}
 	}
 
 	// Create fresh branch from start point (branch-only, no worktree add/remove)
 	branchName := m.buildBranchName(name, opts.HookBead)
-	if err := polecatGit.CheckoutNewBranch(branchName, startPoint); err != nil {
+	if err := polecatGit.CheckoutNewBranch(branchName, branchFrom); err != nil {
 		return nil, fmt.Errorf("creating branch %s from %s: %w", branchName, startPoint, err)
 	}
+	// The polecat leaves the pool whether or not it was warm.
+	m.clearWarmState(name)
+	if pool != nil {
+		telemetry.RecordPolecatPool(context.Background(), m.rig.Name, name, warm != nil)
+	}
 
 	// Reset agent bead for reuse
 	agentID := m.agentBeadID(name)
@@ -1696,7 +1715,7 @@ func (m *Manager) ReconcilePoolWith(namesWithDirs, namesWithSessions []string) {
 //
 // Returns true only when we can confirm the process is dead, not on transient
 // failures (gt-kncti: permission denied false positives).
-func isSessionProcessDead(t *tmux.Tmux, sessionName string, townRoot string) bool {
+func isSessionProcessDead(t session.Backend, sessionName string, townRoot string) bool {
 	// Primary: heartbeat-based liveness check (gt-qjtq ZFC fix).
 	if townRoot != "" {
 		stale, exists := IsSessionHeartbeatStale(townRoot, sessionName)
@@ -1707,7 +1726,11 @@ func isSessionProcessDead(t *tmux.Tmux, sessionName string, townRoot string) boo
 	}
 
 	// Fallback: PID signal probing (legacy, for sessions without heartbeat support).
-	pidStr, err := t.GetPanePID(sessionName)
+	pg, ok := t.(session.PanePIDGetter)
+	if !ok {
+		return false
+	}
+	pidStr, err := pg.GetPanePID(sessionName)
 	if err != nil {
 		// Tmux query failed — could be permission denied, server busy, etc.
 		// Don't assume dead; let a future cycle retry.
@@ -1833,17 +1856,35 @@ func (m *Manager) List() ([]*Polecat, error) {
 // Idle polecats have completed their work and have a preserved sandbox (worktree)
 // that can be reused by gt sling without creating a new worktree.
 // Persistent polecat model (gt-4ac).
+// Warm pooled polecats are preferred over other idle ones, and polecats
+// mid-warmup are only picked when nothing else is idle.
 func (m *Manager) FindIdlePolecat() (*Polecat, error) {
 	polecats, err := m.List()
 	if err != nil {
 		return nil, err
 	}
+	var idle, warming *Polecat
 	for _, p := range polecats {
-		if p.State == StateIdle {
+		if p.State != StateIdle {
+			continue
+		}
+		if m.WarmState(p.Name) != nil {
 			return p, nil
 		}
+		if m.warmingCommit(p.Name) != "" {
+			if warming == nil {
+				warming = p
+			}
+			continue
+		}
+		if idle == nil {
+			idle = p
+		}
+	}
+	if idle == nil {
+		return warming, nil
 	}
-	return nil, nil
+	return idle, nil
 }
 
 // Get returns a specific polecat by name.
@@ -0,0 +1,347 @@
+package polecat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Warm polecat pool. With polecat_pool set in the rig settings, FillPool
+// keeps Size idle polecats warm: each worktree sits detached at the latest
+// target commit with the rig's warmup command already run, and a warm-state
+// file in the polecat's home dir records that commit. ReuseIdlePolecat
+// branches a warm polecat straight from that commit, skipping the fetch.
+
+// warmStateFile lives in polecats/<name>/, outside the worktree, so it is
+// never committed and goes away with the polecat. warmingFile sits beside
+// it while a warmup runs and holds the commit being warmed.
+const (
+	warmStateFile = ".warm.json"
+	warmingFile   = ".warming"
+)
+
+// warmupPollInterval is how often a running warmup checks whether its
+// polecat has been handed out.
+var warmupPollInterval = time.Second
+
+// ErrPoolFillInProgress is returned by FillPool when another process is
+// already filling the rig's pool.
+var ErrPoolFillInProgress = errors.New("polecat pool fill already in progress")
+
+// ErrWarmupInterrupted is returned by WarmPolecat when the polecat was
+// handed out while its warmup ran.
+var ErrWarmupInterrupted = errors.New("polecat handed out during warmup")
+
+// WarmState records what a warm polecat was prepared for.
+type WarmState struct {
+	Target   string    `json:"target"` // start point, e.g. origin/main
+	Commit   string    `json:"commit"` // commit the worktree is detached at
+	Warmup   string    `json:"warmup,omitempty"`
+	WarmedAt time.Time `json:"warmed_at"`
+}
+
+// PoolFillResult describes one FillPool pass.
+type PoolFillResult struct {
+	Size    int      // configured pool size
+	Warm    []string // polecats warm after the pass
+	Warmed  []string // polecats (re)warmed during the pass
+	Created []string // polecats created to reach Size
+	Errors  []error  // per-polecat failures; the pass continues past them
+}
+
+// PoolConfig returns the rig's warm pool settings, or nil if the rig has
+// no pool.
+func (m *Manager) PoolConfig() *config.PolecatPoolConfig {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(m.rig.Path))
+	if err != nil || !settings.PolecatPool.Enabled() {
+		return nil
+	}
+	return settings.PolecatPool
+}
+
+// defaultStartPoint returns origin/<default branch> for the rig.
+func (m *Manager) defaultStartPoint() string {
+	defaultBranch := "main"
+	if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
+		defaultBranch = rigCfg.DefaultBranch
+	}
+	return "origin/" + defaultBranch
+}
+
+func (m *Manager) warmStatePath(name string) string {
+	return filepath.Join(m.polecatDir(name), warmStateFile)
+}
+
+// WarmState returns the warm state of a polecat, or nil if it isn't warm.
+func (m *Manager) WarmState(name string) *WarmState {
+	data, err := os.ReadFile(m.warmStatePath(name))
+	if err != nil {
+		return nil
+	}
+	var state WarmState
+	if err := json.Unmarshal(data, &state); err != nil || state.Commit == "" {
+		return nil
+	}
+	return &state
+}
+
+func (m *Manager) clearWarmState(name string) {
+	_ = os.Remove(m.warmStatePath(name))
+}
+
+func (m *Manager) warmingPath(name string) string {
+	return filepath.Join(m.polecatDir(name), warmingFile)
+}
+
+// warmingCommit returns the commit a running warmup is preparing, or ""
+// if none is running.
+func (m *Manager) warmingCommit(name string) string {
+	data, err := os.ReadFile(m.warmingPath(name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// stopWarmup claims a polecat from a running warmup: the warmup sees its
+// marker gone, stops, and leaves the polecat cold.
+func (m *Manager) stopWarmup(name string) {
+	_ = os.Remove(m.warmingPath(name))
+}
+
+// WarmPolecat moves an idle polecat's worktree to the latest commit of the
+// rig's default branch and runs the pool's warmup command in it. A polecat
+// already warm at that commit is left alone. Like ReuseIdlePolecat, this
+// only checks out: local changes are carried over, never reset.
+//
+// The polecat lock is held for the checkout but not the warmup, so a sling
+// can still take the polecat while it warms. The warmup is then stopped and
+// WarmPolecat returns ErrWarmupInterrupted, leaving the polecat cold.
+func (m *Manager) WarmPolecat(name string, pool *config.PolecatPoolConfig) error {
+	target, commit, err := m.startWarmup(name, pool)
+	if err != nil || commit == "" {
+		return err
+	}
+
+	var warmupErr error
+	if pool.Warmup != "" {
+		warmupErr = m.runWarmup(name, m.clonePath(name), commit, pool)
+	}
+
+	fl, err := m.lockPolecat(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	if m.warmingCommit(name) != commit {
+		return ErrWarmupInterrupted
+	}
+	m.stopWarmup(name)
+	if warmupErr != nil {
+		return warmupErr
+	}
+
+	data, err := json.MarshalIndent(WarmState{
+		Target:   target,
+		Commit:   commit,
+		Warmup:   pool.Warmup,
+		WarmedAt: time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(m.warmStatePath(name), data, 0644)
+}
+
+// startWarmup checks out the latest target commit in an idle polecat under
+// its lock and marks the warmup as running. It returns an empty commit when
+// the polecat is already warm at the target.
+func (m *Manager) startWarmup(name string, pool *config.PolecatPoolConfig) (target, commit string, err error) {
+	fl, err := m.lockPolecat(name)
+	if err != nil {
+		return "", "", err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	// The polecat may have been handed out since the caller saw it idle.
+	p, err := m.Get(name)
+	if err != nil {
+		return "", "", err
+	}
+	if p.State != StateIdle {
+		return "", "", fmt.Errorf("polecat %s is %s, not idle", name, p.State)
+	}
+	clonePath := m.clonePath(name)
+	if _, err := os.Stat(clonePath); err != nil {
+		return "", "", fmt.Errorf("worktree not found at %s: %w", clonePath, err)
+	}
+	polecatGit := git.NewGit(clonePath)
+
+	if repoGit, err := m.repoBase(); err == nil {
+		_ = repoGit.Fetch("origin")
+	}
+	_ = polecatGit.Fetch("origin")
+
+	target = m.defaultStartPoint()
+	commit, err = polecatGit.Rev(target)
+	if err != nil {
+		return "", "", fmt.Errorf("resolving %s: %w", target, err)
+	}
+	if state := m.WarmState(name); state != nil && state.Target == target && state.Commit == commit && state.Warmup == pool.Warmup {
+		return target, "", nil
+	}
+
+	// Cold until the warmup succeeds.
+	m.clearWarmState(name)
+	if err := polecatGit.Checkout(commit); err != nil {
+		return "", "", fmt.Errorf("checking out %s: %w", target, err)
+	}
+	if err := os.WriteFile(m.warmingPath(name), []byte(commit+"\n"), 0644); err != nil {
+		return "", "", fmt.Errorf("marking warmup: %w", err)
+	}
+	return target, commit, nil
+}
+
+// runWarmup runs the pool's warmup command in a worktree. It is cancelled
+// early if the polecat is handed out (its warming marker no longer names
+// commit).
+func (m *Manager) runWarmup(name, clonePath, commit string, pool *config.PolecatPoolConfig) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pool.WarmupTimeoutD())
+	defer cancel()
+	go func() {
+		ticker := time.NewTicker(warmupPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if m.warmingCommit(name) != commit {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", pool.Warmup) //nolint:gosec // G204: warmup is rig-configured
+	cmd.Dir = clonePath
+	cmd.Env = append(os.Environ(), "GT_RIG="+m.rig.Name, "GT_POLECAT="+name, "GT_POLECAT_WARMUP=1")
+	// Children of the shell can hold the output pipe open after it is killed.
+	cmd.WaitDelay = 5 * time.Second
+	util.SetProcessGroup(cmd)
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("warmup for %s timed out after %v", name, pool.WarmupTimeoutD())
+	}
+	if err != nil {
+		return fmt.Errorf("warmup for %s failed: %w\n%s", name, err, tail(out, 20))
+	}
+	return nil
+}
+
+// tail returns the last n lines of command output.
+func tail(out []byte, n int) string {
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FillPool brings the rig's warm pool up to its configured size: idle
+// polecats are (re)warmed, warm ones first, and new polecats are created
+// when there are too few idle ones. Only one fill runs per rig at a time;
+// a concurrent call returns ErrPoolFillInProgress.
+func (m *Manager) FillPool(pool *config.PolecatPoolConfig) (*PoolFillResult, error) {
+	if err := pool.Validate(); err != nil {
+		return nil, err
+	}
+	result := &PoolFillResult{Size: pool.Size}
+	if !pool.Enabled() {
+		return result, nil
+	}
+
+	lockDir := filepath.Join(m.rig.Path, ".runtime", "locks")
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating lock dir: %w", err)
+	}
+	fl := flock.New(filepath.Join(lockDir, "polecat-pool-fill.lock"))
+	locked, err := fl.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("acquiring pool fill lock: %w", err)
+	}
+	if !locked {
+		return nil, ErrPoolFillInProgress
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	polecats, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	var idle []string
+	for _, p := range polecats {
+		if p.State == StateIdle {
+			idle = append(idle, p.Name)
+		}
+	}
+	// Keep the polecats that are already warm; only they are cheap to refresh.
+	sort.SliceStable(idle, func(i, j int) bool {
+		return m.WarmState(idle[i]) != nil && m.WarmState(idle[j]) == nil
+	})
+	if len(idle) > pool.Size {
+		idle = idle[:pool.Size]
+	}
+
+	warm := func(name string) {
+		before := m.WarmState(name)
+		if err := m.WarmPolecat(name, pool); err != nil {
+			if errors.Is(err, ErrWarmupInterrupted) {
+				return // slung while warming; the next fill replaces it
+			}
+			result.Errors = append(result.Errors, fmt.Errorf("%s: %w", name, err))
+			return
+		}
+		if after := m.WarmState(name); before == nil || after == nil || before.WarmedAt != after.WarmedAt {
+			result.Warmed = append(result.Warmed, name)
+		}
+		result.Warm = append(result.Warm, name)
+	}
+
+	for _, name := range idle {
+		warm(name)
+	}
+	for i := len(idle); i < pool.Size; i++ {
+		name, err := m.AllocateName()
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("allocating polecat name: %w", err))
+			break
+		}
+		if _, err := m.AddWithOptions(name, AddOptions{}); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		if err := m.SetAgentState(name, "idle"); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("%s: setting idle state: %w", name, err))
+		}
+		result.Created = append(result.Created, name)
+		warm(name)
+	}
+	return result, nil
+}
@@ -0,0 +1,308 @@
+package polecat
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// installIdleMockBd places a fake bd in PATH whose agent beads are all
+// idle, so polecats read back as idle pool members.
+func installIdleMockBd(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("mock bd script requires a POSIX shell")
+	}
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+cmd=""
+for arg in "$@"; do
+  case "$arg" in
+    --*) ;;
+    *) cmd="$arg"; break ;;
+  esac
+done
+case "$cmd" in
+  show)
+    printf '%s\n' '[{"id":"mock-1","title":"agent","status":"open","labels":["gt:agent"],"description":"role_type: polecat\nagent_state: idle"}]'
+    ;;
+  create)
+    echo '{"id":"mock-1","status":"open","created_at":"2025-01-01T00:00:00Z"}'
+    ;;
+esac
+exit 0
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatalf("write mock bd: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// setupPoolRig creates a rig whose mayor/rig repo is its own origin, with
+// origin/main at the initial commit.
+func setupPoolRig(t *testing.T) (*Manager, string) {
+	t.Helper()
+	installIdleMockBd(t)
+	root := t.TempDir()
+	mayorRig := filepath.Join(root, "mayor", "rig")
+	if err := os.MkdirAll(mayorRig, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = mayorRig
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-b", "main")
+	if err := os.WriteFile(filepath.Join(mayorRig, "README.md"), []byte("# Test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-m", "Initial commit")
+	run("remote", "add", "origin", mayorRig)
+	run("update-ref", "refs/remotes/origin/main", "HEAD")
+
+	// Shared beads: rig/.beads redirects to mayor/rig/.beads.
+	if err := os.MkdirAll(filepath.Join(mayorRig, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".beads", "redirect"), []byte("mayor/rig/.beads\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return NewManager(&rig.Rig{Name: "rig", Path: root}, git.NewGit(root), nil), mayorRig
+}
+
+// advanceOrigin commits in the origin repo and moves origin/main to it.
+func advanceOrigin(t *testing.T, mayorRig string) string {
+	t.Helper()
+	g := git.NewGit(mayorRig)
+	if err := os.WriteFile(filepath.Join(mayorRig, "NEXT.md"), []byte("next\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("NEXT.md"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Commit("Next"); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("git", "update-ref", "refs/remotes/origin/main", "HEAD")
+	cmd.Dir = mayorRig
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("update-ref: %v\n%s", err, out)
+	}
+	head, err := g.Rev("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return head
+}
+
+func TestWarmPolecat(t *testing.T) {
+	m, mayorRig := setupPoolRig(t)
+	p, err := m.AddWithOptions("furiosa", AddOptions{})
+	if err != nil {
+		t.Fatalf("AddWithOptions: %v", err)
+	}
+	pool := &config.PolecatPoolConfig{Size: 1, Warmup: "echo x >> warmups.log"}
+
+	if err := m.WarmPolecat("furiosa", pool); err != nil {
+		t.Fatalf("WarmPolecat: %v", err)
+	}
+	state := m.WarmState("furiosa")
+	if state == nil || state.Target != "origin/main" {
+		t.Fatalf("WarmState = %+v", state)
+	}
+	warmups := func() int {
+		data, _ := os.ReadFile(filepath.Join(p.ClonePath, "warmups.log"))
+		return strings.Count(string(data), "x")
+	}
+	if warmups() != 1 {
+		t.Fatalf("warmup ran %d times, want 1", warmups())
+	}
+
+	// Still at the target: nothing to do.
+	if err := m.WarmPolecat("furiosa", pool); err != nil {
+		t.Fatal(err)
+	}
+	if warmups() != 1 {
+		t.Errorf("warm polecat was re-warmed")
+	}
+
+	// The target moved: check out the new commit and warm again.
+	head := advanceOrigin(t, mayorRig)
+	if err := m.WarmPolecat("furiosa", pool); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.WarmState("furiosa"); got == nil || got.Commit != head {
+		t.Errorf("WarmState after advance = %+v, want commit %s", got, head)
+	}
+	if warmups() != 2 {
+		t.Errorf("warmup ran %d times, want 2", warmups())
+	}
+	if _, err := os.Stat(filepath.Join(p.ClonePath, "NEXT.md")); err != nil {
+		t.Errorf("worktree not at new target: %v", err)
+	}
+}
+
+func TestWarmPolecatFailedWarmupLeavesCold(t *testing.T) {
+	m, _ := setupPoolRig(t)
+	if _, err := m.AddWithOptions("nux", AddOptions{}); err != nil {
+		t.Fatalf("AddWithOptions: %v", err)
+	}
+	err := m.WarmPolecat("nux", &config.PolecatPoolConfig{Size: 1, Warmup: "echo boom; exit 3"})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected warmup failure with output, got %v", err)
+	}
+	if m.WarmState("nux") != nil {
+		t.Error("failed warmup should leave the polecat cold")
+	}
+}
+
+func TestReuseIdlePolecatUsesWarmCommit(t *testing.T) {
+	m, mayorRig := setupPoolRig(t)
+	p, err := m.AddWithOptions("slit", AddOptions{})
+	if err != nil {
+		t.Fatalf("AddWithOptions: %v", err)
+	}
+	if err := m.WarmPolecat("slit", &config.PolecatPoolConfig{Size: 1, Warmup: "touch .built"}); err != nil {
+		t.Fatal(err)
+	}
+	warm := m.WarmState("slit")
+
+	// origin/main moves after warming; a warm hand-out branches from the
+	// warmed commit instead of fetching and building again.
+	advanceOrigin(t, mayorRig)
+
+	reused, err := m.ReuseIdlePolecat("slit", AddOptions{})
+	if err != nil {
+		t.Fatalf("ReuseIdlePolecat: %v", err)
+	}
+	head, err := git.NewGit(p.ClonePath).Rev("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head != warm.Commit {
+		t.Errorf("reused HEAD = %s, want warm commit %s", head, warm.Commit)
+	}
+	if !strings.HasPrefix(reused.Branch, "polecat/slit") {
+		t.Errorf("Branch = %q", reused.Branch)
+	}
+	if _, err := os.Stat(filepath.Join(p.ClonePath, ".built")); err != nil {
+		t.Errorf("warmup output not carried over: %v", err)
+	}
+	if m.WarmState("slit") != nil {
+		t.Error("handed-out polecat should leave the pool")
+	}
+}
+
+func TestReuseIdlePolecatDuringWarmup(t *testing.T) {
+	m, _ := setupPoolRig(t)
+	p, err := m.AddWithOptions("toast", AddOptions{})
+	if err != nil {
+		t.Fatalf("AddWithOptions: %v", err)
+	}
+	old := warmupPollInterval
+	warmupPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { warmupPollInterval = old })
+
+	started := filepath.Join(p.ClonePath, "warmup.started")
+	warmed := make(chan error, 1)
+	go func() {
+		warmed <- m.WarmPolecat("toast", &config.PolecatPoolConfig{Size: 1, Warmup: "touch warmup.started; sleep 30"})
+	}()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, err := os.Stat(started); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("warmup never started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if found, err := m.FindIdlePolecat(); err != nil || found == nil || found.Name != "toast" {
+		t.Fatalf("FindIdlePolecat() = %v, %v; want toast even mid-warmup", found, err)
+	}
+
+	// The hand-out must not wait out the 30s warmup.
+	begin := time.Now()
+	if _, err := m.ReuseIdlePolecat("toast", AddOptions{}); err != nil {
+		t.Fatalf("ReuseIdlePolecat: %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 10*time.Second {
+		t.Errorf("reuse took %v, blocked on the warmup", elapsed)
+	}
+
+	select {
+	case err := <-warmed:
+		if !errors.Is(err, ErrWarmupInterrupted) {
+			t.Errorf("WarmPolecat() error = %v, want ErrWarmupInterrupted", err)
+		}
+	case <-time.After(15 * time.Second):
+		t.Fatal("interrupted warmup kept running")
+	}
+	if m.WarmState("toast") != nil {
+		t.Error("polecat handed out mid-warmup should not be marked warm")
+	}
+}
+
+func TestFillPoolCreatesAndWarms(t *testing.T) {
+	m, _ := setupPoolRig(t)
+	pool := &config.PolecatPoolConfig{Size: 2, Warmup: "true"}
+
+	result, err := m.FillPool(pool)
+	if err != nil {
+		t.Fatalf("FillPool: %v", err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("FillPool errors: %v", result.Errors)
+	}
+	if len(result.Created) != 2 || len(result.Warm) != 2 {
+		t.Fatalf("FillPool = %+v, want 2 created and warm", result)
+	}
+	for _, name := range result.Warm {
+		if m.WarmState(name) == nil {
+			t.Errorf("%s has no warm state", name)
+		}
+	}
+	idle, err := m.FindIdlePolecat()
+	if err != nil || idle == nil || m.WarmState(idle.Name) == nil {
+		t.Errorf("FindIdlePolecat should return a warm polecat, got %+v (%v)", idle, err)
+	}
+
+	// A full pool needs no new polecats and no new warmups.
+	again, err := m.FillPool(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Created) != 0 || len(again.Warmed) != 0 || len(again.Warm) != 2 {
+		t.Errorf("second FillPool = %+v, want nothing to do", again)
+	}
+}
+
+func TestPolecatPoolConfigValidate(t *testing.T) {
+	if err := (&config.PolecatPoolConfig{Size: 2, WarmupTimeout: "10m"}).Validate(); err != nil {
+		t.Errorf("valid config: %v", err)
+	}
+	if err := (&config.PolecatPoolConfig{Size: -1}).Validate(); err == nil {
+		t.Error("negative size should be rejected")
+	}
+	if err := (&config.PolecatPoolConfig{Size: 1, WarmupTimeout: "soon"}).Validate(); err == nil {
+		t.Error("bad warmup_timeout should be rejected")
+	}
+}
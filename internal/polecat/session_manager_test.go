@@ -480,7 +480,7 @@ func TestVerifyStartupNudgeDelivery_IdleAgent(t *testing.T) {
 	// Use a goroutine with timeout to prevent test hanging.
 	done := make(chan struct{})
 	go func() {
-		m.verifyStartupNudgeDelivery(sessionName, rc)
+		m.verifyStartupNudgeDelivery(tm, sessionName, rc)
 		close(done)
 	}()
 
@@ -501,7 +501,7 @@ func TestVerifyStartupNudgeDelivery_NilConfig(t *testing.T) {
 	m := NewSessionManager(tmux.NewTmux(), r)
 
 	// Should return immediately without error for nil config
-	m.verifyStartupNudgeDelivery("nonexistent-session", nil)
+	m.verifyStartupNudgeDelivery(m.tmux, "nonexistent-session", nil)
 
 	// And for config without prompt prefix
 	rc := &config.RuntimeConfig{
@@ -510,7 +510,7 @@ func TestVerifyStartupNudgeDelivery_NilConfig(t *testing.T) {
 			ReadyDelayMs:      1000,
 		},
 	}
-	m.verifyStartupNudgeDelivery("nonexistent-session", rc)
+	m.verifyStartupNudgeDelivery(m.tmux, "nonexistent-session", rc)
 }
 
 func TestValidateSessionName(t *testing.T) {
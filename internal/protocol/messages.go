@@ -2,6 +2,7 @@ package protocol
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -49,7 +50,8 @@ func formatMergeReadyBody(p MergeReadyPayload) string {
 
 // NewMergedMessage creates a MERGED protocol message.
 // Sent by Refinery to Witness when a branch is successfully merged.
-func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit string) *mail.Message {
+// outputs carries merge action results (preview URLs, artifacts) and may be nil.
+func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit string, outputs map[string]string) *mail.Message {
 	payload := MergedPayload{
 		Branch:       branch,
 		Issue:        issue,
@@ -58,6 +60,7 @@ func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit str
 		MergedAt:     time.Now(),
 		MergeCommit:  mergeCommit,
 		TargetBranch: targetBranch,
+		Outputs:      outputs,
 	}
 
 	body := formatMergedBody(payload)
@@ -86,12 +89,44 @@ func formatMergedBody(p MergedPayload) string {
 	if p.MergeCommit != "" {
 		sb.WriteString(fmt.Sprintf("Merge-Commit: %s\n", p.MergeCommit))
 	}
+	keys := make([]string, 0, len(p.Outputs))
+	for k := range p.Outputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sb.WriteString(fmt.Sprintf("%s%s: %s\n", outputFieldPrefix, k, p.Outputs[k]))
+	}
 	return sb.String()
 }
 
+// outputFieldPrefix marks merge action output lines in a MERGED body.
+const outputFieldPrefix = "Output-"
+
+// parseOutputFields collects "Output-<key>: value" lines from a message body.
+func parseOutputFields(body string) map[string]string {
+	var outputs map[string]string
+	for _, line := range strings.Split(body, "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), outputFieldPrefix)
+		if !ok {
+			continue
+		}
+		key, value, ok := strings.Cut(rest, ": ")
+		if !ok || key == "" {
+			continue
+		}
+		if outputs == nil {
+			outputs = make(map[string]string)
+		}
+		outputs[key] = value
+	}
+	return outputs
+}
+
 // NewMergeFailedMessage creates a MERGE_FAILED protocol message.
 // Sent by Refinery to Witness when merge fails (tests, build, etc.).
-func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureType, errorMsg string) *mail.Message {
+// flakyTests lists failing tests known to be flaky, if any.
+func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureType, errorMsg string, flakyTests ...string) *mail.Message {
 	payload := MergeFailedPayload{
 		Branch:       branch,
 		Issue:        issue,
@@ -101,6 +136,7 @@ func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureTyp
 		FailureType:  failureType,
 		Error:        errorMsg,
 		TargetBranch: targetBranch,
+		FlakyTests:   flakyTests,
 	}
 
 	body := formatMergeFailedBody(payload)
@@ -128,6 +164,9 @@ func formatMergeFailedBody(p MergeFailedPayload) string {
 	sb.WriteString(fmt.Sprintf("Failed-At: %s\n", p.FailedAt.Format(time.RFC3339)))
 	sb.WriteString(fmt.Sprintf("Failure-Type: %s\n", p.FailureType))
 	sb.WriteString(fmt.Sprintf("Error: %s\n", p.Error))
+	if len(p.FlakyTests) > 0 {
+		sb.WriteString(fmt.Sprintf("Flaky-Tests: %s\n", strings.Join(p.FlakyTests, ", ")))
+	}
 	return sb.String()
 }
 
@@ -294,6 +333,7 @@ func ParseMergedPayload(body string) (*MergedPayload, error) {
 		Rig:          parseField(body, "Rig"),
 		TargetBranch: parseField(body, "Target"),
 		MergeCommit:  parseField(body, "Merge-Commit"),
+		Outputs:      parseOutputFields(body),
 	}
 
 	// Parse timestamp
@@ -340,6 +380,11 @@ func ParseMergeFailedPayload(body string) (*MergeFailedPayload, error) {
 		}
 	}
 
+	// Parse known-flaky tests
+	if tests := parseField(body, "Flaky-Tests"); tests != "" {
+		payload.FlakyTests = strings.Split(tests, ", ")
+	}
+
 	var errs []string
 	if payload.Branch == "" {
 		errs = append(errs, "Branch")
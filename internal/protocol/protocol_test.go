@@ -112,7 +112,7 @@ func TestNewMergeReadyMessage(t *testing.T) {
 }
 
 func TestNewMergedMessage(t *testing.T) {
-	msg := NewMergedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "abc123")
+	msg := NewMergedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "abc123", nil)
 
 	if msg.Subject != "MERGED nux" {
 		t.Errorf("Subject = %q, want %q", msg.Subject, "MERGED nux")
@@ -371,6 +371,44 @@ Error: Test failed`
 	}
 }
 
+func TestMergedPayload_OutputsRoundTrip(t *testing.T) {
+	outputs := map[string]string{"preview.url": "https://pr-12.preview.example.com", "tag.name": "v1.2.3"}
+	msg := NewMergedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "abc123", outputs)
+
+	if !strings.Contains(msg.Body, "Output-preview.url: https://pr-12.preview.example.com") {
+		t.Errorf("body missing preview output:\n%s", msg.Body)
+	}
+	payload, err := ParseMergedPayload(msg.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(payload.Outputs) != 2 || payload.Outputs["tag.name"] != "v1.2.3" {
+		t.Errorf("Outputs = %v, want %v", payload.Outputs, outputs)
+	}
+}
+
+func TestMergeFailedPayload_FlakyTestsRoundTrip(t *testing.T) {
+	msg := NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main",
+		FailureTypeFlaky, "quality gates failed", "pkg.TestRace", "pkg.TestNet")
+
+	payload, err := ParseMergeFailedPayload(msg.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(payload.FlakyTests, ","); got != "pkg.TestRace,pkg.TestNet" {
+		t.Errorf("FlakyTests = %v, want [pkg.TestRace pkg.TestNet]", payload.FlakyTests)
+	}
+	if payload.FailureType != FailureTypeFlaky {
+		t.Errorf("FailureType = %q, want %q", payload.FailureType, FailureTypeFlaky)
+	}
+
+	// Without flaky tests the field is omitted entirely.
+	msg = NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "tests", "Test failed")
+	if strings.Contains(msg.Body, "Flaky-Tests") {
+		t.Errorf("unexpected Flaky-Tests line in body:\n%s", msg.Body)
+	}
+}
+
 func TestParseMergeFailedPayload_InvalidInput(t *testing.T) {
 	payload, err := ParseMergeFailedPayload("")
 	if err == nil {
@@ -627,6 +665,17 @@ func TestDefaultWitnessHandler(t *testing.T) {
 		t.Errorf("Output missing expected text: %s", buf.String())
 	}
 
+	// Known-flaky failures don't request rework
+	buf.Reset()
+	failedPayload.FailureType = FailureTypeFlaky
+	failedPayload.FlakyTests = []string{"pkg.TestRace"}
+	if err := handler.HandleMergeFailed(failedPayload); err != nil {
+		t.Errorf("HandleMergeFailed error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "no rework requested") || strings.Contains(buf.String(), "rework needed") {
+		t.Errorf("flaky-only failure should not request rework: %s", buf.String())
+	}
+
 	// Test HandleReworkRequest
 	buf.Reset()
 	reworkPayload := &ReworkRequestPayload{
@@ -72,15 +72,15 @@ func (h *DefaultRefineryHandler) HandleMergeReady(payload *MergeReadyPayload) er
 
 // SendMerged sends a MERGED message to the Witness.
 // Called by the Refinery after successfully merging a branch.
-func (h *DefaultRefineryHandler) SendMerged(polecat, branch, issue, targetBranch, mergeCommit string) error {
-	msg := NewMergedMessage(h.Rig, polecat, branch, issue, targetBranch, mergeCommit)
+func (h *DefaultRefineryHandler) SendMerged(polecat, branch, issue, targetBranch, mergeCommit string, outputs map[string]string) error {
+	msg := NewMergedMessage(h.Rig, polecat, branch, issue, targetBranch, mergeCommit, outputs)
 	return h.Router.Send(msg)
 }
 
 // SendMergeFailed sends a MERGE_FAILED message to the Witness.
 // Called by the Refinery when a merge fails.
-func (h *DefaultRefineryHandler) SendMergeFailed(polecat, branch, issue, targetBranch, failureType, errorMsg string) error {
-	msg := NewMergeFailedMessage(h.Rig, polecat, branch, issue, targetBranch, failureType, errorMsg)
+func (h *DefaultRefineryHandler) SendMergeFailed(polecat, branch, issue, targetBranch, failureType, errorMsg string, flakyTests ...string) error {
+	msg := NewMergeFailedMessage(h.Rig, polecat, branch, issue, targetBranch, failureType, errorMsg, flakyTests...)
 	return h.Router.Send(msg)
 }
 
@@ -109,21 +109,27 @@ type MergeOutcome struct {
 	// MergeCommit is the SHA of the merge commit on success.
 	MergeCommit string
 
+	// Outputs holds merge action results (preview URLs, artifacts) on success.
+	Outputs map[string]string
+
 	// ConflictFiles lists files with conflicts (if Conflict is true).
 	ConflictFiles []string
+
+	// FlakyTests lists failing tests known to be flaky.
+	FlakyTests []string
 }
 
 // NotifyMergeOutcome sends the appropriate protocol message based on the outcome.
 func (h *DefaultRefineryHandler) NotifyMergeOutcome(polecat, branch, issue, targetBranch string, outcome MergeOutcome) error {
 	if outcome.Success {
-		return h.SendMerged(polecat, branch, issue, targetBranch, outcome.MergeCommit)
+		return h.SendMerged(polecat, branch, issue, targetBranch, outcome.MergeCommit, outcome.Outputs)
 	}
 
 	if outcome.Conflict {
 		return h.SendReworkRequest(polecat, branch, issue, targetBranch, outcome.ConflictFiles)
 	}
 
-	return h.SendMergeFailed(polecat, branch, issue, targetBranch, outcome.FailureType, outcome.Error)
+	return h.SendMergeFailed(polecat, branch, issue, targetBranch, outcome.FailureType, outcome.Error, outcome.FlakyTests...)
 }
 
 // Ensure DefaultRefineryHandler implements RefineryHandler.
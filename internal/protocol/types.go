@@ -13,6 +13,8 @@ package protocol
 import (
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/witness"
 )
 
 // MessageType identifies the protocol message type.
@@ -115,6 +117,10 @@ type MergedPayload struct {
 
 	// TargetBranch is the branch merged into (e.g., "main").
 	TargetBranch string `json:"target_branch"`
+
+	// Outputs holds values reported by pre/post-merge actions, such as
+	// preview URLs or artifact paths, keyed "<action>.<key>".
+	Outputs map[string]string `json:"outputs,omitempty"`
 }
 
 // MergeFailedPayload contains the data for a MERGE_FAILED message.
@@ -136,6 +142,7 @@ type MergeFailedPayload struct {
 	FailedAt time.Time `json:"failed_at"`
 
 	// FailureType categorizes the failure (tests, build, push, etc.).
+	// FailureTypeFlaky means every failing test is a known-flaky test.
 	FailureType string `json:"failure_type"`
 
 	// Error is the error message.
@@ -143,8 +150,16 @@ type MergeFailedPayload struct {
 
 	// TargetBranch is the branch we tried to merge into.
 	TargetBranch string `json:"target_branch"`
+
+	// FlakyTests lists failing tests the refinery already knows to be flaky.
+	// They are reported separately so the polecat isn't asked to fix them.
+	FlakyTests []string `json:"flaky_tests,omitempty"`
 }
 
+// FailureTypeFlaky marks a merge failure caused entirely by known-flaky tests.
+// No rework is requested; the MR stays in the queue for retry.
+const FailureTypeFlaky = witness.FailureTypeFlaky
+
 // ReworkRequestPayload contains the data for a REWORK_REQUEST message.
 // Sent by Refinery when a polecat's branch has conflicts requiring rebase.
 type ReworkRequestPayload struct {
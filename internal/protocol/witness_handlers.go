@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/witness"
@@ -53,6 +55,9 @@ func (h *DefaultWitnessHandler) HandleMerged(payload *MergedPayload) error {
 	if payload.MergeCommit != "" {
 		_, _ = fmt.Fprintf(h.Output, "  Commit: %s\n", payload.MergeCommit)
 	}
+	for _, line := range formatMergeOutputs(payload.Outputs) {
+		_, _ = fmt.Fprintf(h.Output, "  %s\n", line)
+	}
 
 	// Notify the polecat about successful merge
 	if err := h.notifyPolecatMerged(payload); err != nil {
@@ -82,12 +87,23 @@ func (h *DefaultWitnessHandler) HandleMerged(payload *MergedPayload) error {
 // 1. Logs the failure
 // 2. Notifies the polecat about the failure and required fixes
 // 3. Updates the polecat's state to indicate rework needed
+//
+// Failures caused entirely by known-flaky tests are logged but do not
+// request rework: there is nothing for the polecat to fix.
 func (h *DefaultWitnessHandler) HandleMergeFailed(payload *MergeFailedPayload) error {
 	fmt.Fprintf(h.Output, "[Witness] MERGE_FAILED received for polecat %s\n", payload.Polecat)
 	fmt.Fprintf(h.Output, "  Branch: %s\n", payload.Branch)
 	fmt.Fprintf(h.Output, "  Issue: %s\n", payload.Issue)
 	fmt.Fprintf(h.Output, "  Failure type: %s\n", payload.FailureType)
 	fmt.Fprintf(h.Output, "  Error: %s\n", payload.Error)
+	if len(payload.FlakyTests) > 0 {
+		fmt.Fprintf(h.Output, "  Known-flaky tests: %s\n", strings.Join(payload.FlakyTests, ", "))
+	}
+
+	if payload.FailureType == FailureTypeFlaky {
+		fmt.Fprintf(h.Output, "[Witness] Only known-flaky tests failed for %s, no rework requested\n", payload.Polecat)
+		return nil
+	}
 
 	// Notify the polecat about the failure
 	if err := h.notifyPolecatFailed(payload); err != nil {
@@ -179,12 +195,13 @@ func (h *DefaultWitnessHandler) notifyPolecatMerged(payload *MergedPayload) erro
 Branch: %s
 Issue: %s
 Commit: %s
-
+%s
 Thank you for your contribution! Your worktree will be cleaned up shortly.`,
 			payload.TargetBranch,
 			payload.Branch,
 			payload.Issue,
 			payload.MergeCommit,
+			formatMergeOutputsNote(payload.Outputs),
 		),
 	)
 	msg.Priority = mail.PriorityNormal
@@ -204,12 +221,13 @@ Branch: %s
 Issue: %s
 Failure: %s
 Error: %s
-
+%s
 Please fix the issue and resubmit your work with 'gt done'.`,
 			payload.Branch,
 			payload.Issue,
 			payload.FailureType,
 			payload.Error,
+			formatFlakyTestsNote(payload.FlakyTests),
 		),
 	)
 	msg.Priority = mail.PriorityHigh
@@ -218,6 +236,36 @@ Please fix the issue and resubmit your work with 'gt done'.`,
 	return h.Router.Send(msg)
 }
 
+// formatMergeOutputs renders merge action outputs as sorted "key: value" lines.
+func formatMergeOutputs(outputs map[string]string) []string {
+	keys := make([]string, 0, len(outputs))
+	for k := range outputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	lines := make([]string, len(keys))
+	for i, k := range keys {
+		lines[i] = fmt.Sprintf("%s: %s", k, outputs[k])
+	}
+	return lines
+}
+
+// formatMergeOutputsNote lists merge action outputs for the polecat.
+func formatMergeOutputsNote(outputs map[string]string) string {
+	if len(outputs) == 0 {
+		return ""
+	}
+	return "\nMerge outputs:\n  " + strings.Join(formatMergeOutputs(outputs), "\n  ") + "\n"
+}
+
+// formatFlakyTestsNote tells the polecat which failures it can ignore.
+func formatFlakyTestsNote(tests []string) string {
+	if len(tests) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Known-flaky (not caused by your change, ignore): %s\n", strings.Join(tests, ", "))
+}
+
 // notifyPolecatRebase sends a rebase request notification to a polecat.
 func (h *DefaultWitnessHandler) notifyPolecatRebase(payload *ReworkRequestPayload) error {
 	conflictInfo := ""
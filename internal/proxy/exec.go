@@ -32,6 +32,11 @@ func (s *Server) handleExec(w http.ResponseWriter, r *http.Request) {
 	// Limit request body to prevent a misbehaving client from exhausting memory.
 	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MiB
 
+	if isTownBridgeCN(clientCN(r)) {
+		http.Error(w, "town bridge certificates may not exec", http.StatusForbidden)
+		return
+	}
+
 	// Extract identity from client cert CN (format: gt-<rig>-<name>).
 	identity := extractIdentity(r)
 
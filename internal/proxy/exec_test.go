@@ -616,3 +616,44 @@ func TestBinaryResolution(t *testing.T) {
 		"non-existent binary should be removed from allowlist")
 	assert.True(t, lc.hasLevel(slog.LevelError), "expected error log for missing binary")
 }
+
+func TestTownBridgeCertRestrictions(t *testing.T) {
+	bridged := 0
+	srv := newExecTestServer(t, Config{
+		AllowedCommands: []string{"echo"},
+		MailBridge: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			bridged++
+			w.WriteHeader(http.StatusOK)
+		}),
+	})
+
+	t.Run("town cert cannot exec", func(t *testing.T) {
+		req := makeFakeRequest("POST", "/v1/exec", `{"argv":["echo","hi"]}`, "gt-town-frontend")
+		rec := httptest.NewRecorder()
+		srv.handleExec(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("town cert cannot use git", func(t *testing.T) {
+		req := makeFakeRequest("GET", "/v1/git/gastown/info/refs?service=git-upload-pack", "", "gt-town-frontend")
+		rec := httptest.NewRecorder()
+		srv.handleGit(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("polecat cert cannot use mail bridge", func(t *testing.T) {
+		req := makeFakeRequest("POST", "/v1/mail/relay", "{}", "gt-gastown-rust")
+		rec := httptest.NewRecorder()
+		srv.handleMail(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, 0, bridged)
+	})
+
+	t.Run("town cert reaches mail bridge", func(t *testing.T) {
+		req := makeFakeRequest("POST", "/v1/mail/relay", "{}", "gt-town-frontend")
+		rec := httptest.NewRecorder()
+		srv.handleMail(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 1, bridged)
+	})
+}
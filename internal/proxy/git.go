@@ -73,6 +73,11 @@ var rigNameRe = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 //	GET  /v1/git/<rig>/info/refs?service=git-receive-pack
 //	POST /v1/git/<rig>/git-receive-pack
 func (s *Server) handleGit(w http.ResponseWriter, r *http.Request) {
+	if isTownBridgeCN(clientCN(r)) {
+		http.Error(w, "town bridge certificates may not access git", http.StatusForbidden)
+		return
+	}
+
 	// Path: /v1/git/<rig>/...
 	path := strings.TrimPrefix(r.URL.Path, "/v1/git/")
 	parts := strings.SplitN(path, "/", 2)
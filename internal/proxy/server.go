@@ -58,6 +58,9 @@ type Config struct {
 	// ExecTimeout is the maximum duration a single exec subprocess may run.
 	// 0 uses the default (60s). Use a negative value to disable the timeout.
 	ExecTimeout time.Duration
+	// MailBridge handles /v1/mail/ requests relayed from peer towns (see
+	// mail.BridgeHandler). nil disables the cross-town mail bridge.
+	MailBridge http.Handler
 }
 
 // Server is an mTLS HTTP proxy server.
@@ -229,6 +232,9 @@ func (s *Server) Start(ctx context.Context) error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/v1/exec", s.handleExec)
 	mux.HandleFunc("/v1/git/", s.handleGit)
+	if s.cfg.MailBridge != nil {
+		mux.HandleFunc("/v1/mail/", s.handleMail)
+	}
 
 	srv := &http.Server{
 		Addr:        s.cfg.ListenAddr,
@@ -386,6 +392,33 @@ func serverListenIPs(listenAddr string) []net.IP {
 	return []net.IP{ip, loopback4, loopback6}
 }
 
+// townBridgeCNPrefix is the CN prefix of certificates issued to peer towns
+// for the mail bridge (admin issue-cert with rig "town"). Such certificates
+// may only reach /v1/mail/; exec and git stay reserved for local polecats.
+const townBridgeCNPrefix = "gt-town-"
+
+// isTownBridgeCN reports whether cn belongs to a peer town's bridge certificate.
+func isTownBridgeCN(cn string) bool {
+	return strings.HasPrefix(cn, townBridgeCNPrefix)
+}
+
+// handleMail forwards /v1/mail/ requests to the configured mail bridge,
+// applying the same per-client rate limit as exec.
+func (s *Server) handleMail(w http.ResponseWriter, r *http.Request) {
+	cn := clientCN(r)
+	if !isTownBridgeCN(cn) {
+		http.Error(w, "mail bridge requires a town certificate", http.StatusForbidden)
+		return
+	}
+	if !s.limiterFor(cn).Allow() {
+		s.log.Warn("mail bridge rate limit exceeded", "cn", cn)
+		http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+		return
+	}
+	s.log.Info("mail relay", "cn", cn, "path", r.URL.Path)
+	s.cfg.MailBridge.ServeHTTP(w, r)
+}
+
 // issueCertRequest is the JSON body for POST /v1/admin/issue-cert.
 type issueCertRequest struct {
 	// Rig is the rig name (e.g. "MyRig").
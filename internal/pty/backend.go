@@ -0,0 +1,419 @@
+package pty
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"golang.org/x/term"
+)
+
+// supervisorStartTimeout bounds waiting for a new supervisor's socket.
+const supervisorStartTimeout = 5 * time.Second
+
+// pollInterval is how often readiness and idle checks capture scrollback.
+const pollInterval = 200 * time.Millisecond
+
+// Backend manages a town's PTY sessions. It provides the session methods
+// gt uses from tmux.Tmux, talking to each session's supervisor socket.
+type Backend struct {
+	townRoot string
+}
+
+// NewBackend returns the PTY backend for a town.
+func NewBackend(townRoot string) *Backend {
+	return &Backend{townRoot: townRoot}
+}
+
+// Enabled reports whether a town has opted in to starting PTY sessions.
+func Enabled(townRoot string) bool {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	return err == nil && settings.ExperimentalPTYBackend
+}
+
+// call sends a request to a session, mapping a missing supervisor to
+// tmux.ErrSessionNotFound so callers can treat both backends alike.
+func (b *Backend) call(session string, req Request) (*Response, error) {
+	resp, err := call(SocketPath(b.townRoot, session), req)
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return nil, fmt.Errorf("%w: %s", tmux.ErrSessionNotFound, session)
+	}
+	return resp, err
+}
+
+// ErrExperimental is returned when a town starts a PTY session without
+// opting in to the experimental backend.
+var ErrExperimental = errors.New(`the "pty" session backend is experimental: set "experimental_pty_backend": true in settings/config.json to use it`)
+
+// NewSessionWithCommand starts a detached supervisor ("gt pty supervise")
+// running command in workDir and waits for its socket to come up.
+func (b *Backend) NewSessionWithCommand(name, workDir, command string) error {
+	if !Enabled(b.townRoot) {
+		return ErrExperimental
+	}
+	if ok, _ := b.HasSession(name); ok {
+		return fmt.Errorf("session %s already exists", name)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding gt executable: %w", err)
+	}
+	dir := SocketDir(b.townRoot)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("creating socket dir: %w", err)
+	}
+	logFile, err := os.OpenFile(filepath.Join(dir, name+".log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("opening supervisor log: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(exe, "pty", "supervise", //nolint:gosec // G204: re-executes gt itself
+		"--town", b.townRoot, "--name", name, "--dir", workDir, "--", command)
+	cmd.Dir = workDir
+	cmd.Stdout, cmd.Stderr = logFile, logFile
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting supervisor: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	deadline := time.Now().Add(supervisorStartTimeout)
+	for time.Now().Before(deadline) {
+		if ok, _ := b.HasSession(name); ok {
+			return nil
+		}
+		select {
+		case err := <-exited:
+			return fmt.Errorf("supervisor for %s exited during startup (see %s): %v", name, logFile.Name(), err)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	return fmt.Errorf("timeout waiting for supervisor socket for %s", name)
+}
+
+// HasSession reports whether a supervisor for the session is answering.
+func (b *Backend) HasSession(name string) (bool, error) {
+	if _, err := b.call(name, Request{Op: OpStatus}); err != nil {
+		if errors.Is(err, tmux.ErrSessionNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ListSessions returns the names of live sessions, sorted.
+func (b *Backend) ListSessions() ([]string, error) {
+	socks, err := filepath.Glob(filepath.Join(SocketDir(b.townRoot), "*.sock"))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, sock := range socks {
+		name := strings.TrimSuffix(filepath.Base(sock), ".sock")
+		if ok, _ := b.HasSession(name); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Status returns a session's supervisor status.
+func (b *Backend) Status(name string) (*Response, error) {
+	return b.call(name, Request{Op: OpStatus})
+}
+
+// KillSessionWithProcesses kills the session's process group and ends the
+// supervisor.
+func (b *Backend) KillSessionWithProcesses(name string) error {
+	_, err := b.call(name, Request{Op: OpKill})
+	return err
+}
+
+// SetEnvironment sets a session variable; respawned commands inherit it.
+func (b *Backend) SetEnvironment(session, key, value string) error {
+	_, err := b.call(session, Request{Op: OpSetEnv, Key: key, Value: value})
+	return err
+}
+
+// GetEnvironment reads a session variable.
+func (b *Backend) GetEnvironment(session, key string) (string, error) {
+	resp, err := b.call(session, Request{Op: OpGetEnv, Key: key})
+	if err != nil {
+		return "", err
+	}
+	return resp.Output, nil
+}
+
+// SendKeysRaw sends a tmux-style key ("C-c", "Enter") or literal text
+// without adding Enter.
+func (b *Backend) SendKeysRaw(session, keys string) error {
+	return b.send(session, KeyBytes(keys))
+}
+
+// SendKeys sends text literally, then Enter after a debounce delay.
+func (b *Backend) SendKeys(session, keys string) error {
+	if err := b.send(session, keys); err != nil {
+		return err
+	}
+	time.Sleep(constants.DefaultDebounceMs * time.Millisecond)
+	return b.send(session, "\r")
+}
+
+// NudgeSession injects a message into the agent's input and submits it.
+func (b *Backend) NudgeSession(session, message string) error {
+	return b.SendKeys(session, message)
+}
+
+func (b *Backend) send(session, data string) error {
+	_, err := b.call(session, Request{Op: OpSend, Data: data})
+	return err
+}
+
+// CapturePane returns the last lines of the session's rendered scrollback.
+func (b *Backend) CapturePane(session string, lines int) (string, error) {
+	resp, err := b.call(session, Request{Op: OpCapture, Lines: lines})
+	if err != nil {
+		return "", err
+	}
+	return resp.Output, nil
+}
+
+// IsAgentAlive reports whether the session's command is running. The
+// supervisor runs the agent directly, so there's no shell to look past.
+func (b *Backend) IsAgentAlive(session string) bool {
+	resp, err := b.call(session, Request{Op: OpStatus})
+	return err == nil && resp.Alive
+}
+
+// CheckSessionHealth reports the session's liveness like
+// tmux.CheckSessionHealth: dead supervisor, dead command, or no output for
+// longer than maxInactivity (0 skips the activity check).
+func (b *Backend) CheckSessionHealth(session string, maxInactivity time.Duration) tmux.ZombieStatus {
+	resp, err := b.call(session, Request{Op: OpStatus})
+	if err != nil {
+		return tmux.SessionDead
+	}
+	if !resp.Alive {
+		return tmux.AgentDead
+	}
+	if maxInactivity > 0 && !resp.Activity.IsZero() && time.Since(resp.Activity) > maxInactivity {
+		return tmux.AgentHung
+	}
+	return tmux.SessionHealthy
+}
+
+// GetSessionInfo returns the session's status in tmux.SessionInfo form.
+// A PTY session always has exactly one window.
+func (b *Backend) GetSessionInfo(name string) (*tmux.SessionInfo, error) {
+	resp, err := b.call(name, Request{Op: OpStatus})
+	if err != nil {
+		return nil, err
+	}
+	info := &tmux.SessionInfo{
+		Name:     name,
+		Windows:  1,
+		Created:  resp.Created.Format("2006-01-02 15:04:05"),
+		Attached: resp.Attached,
+	}
+	if !resp.Activity.IsZero() {
+		info.Activity = fmt.Sprintf("%d", resp.Activity.Unix())
+	}
+	return info, nil
+}
+
+// GetSessionCreatedUnix returns when the session's supervisor started, so
+// callers can tell a session apart from one that replaced it.
+func (b *Backend) GetSessionCreatedUnix(session string) (int64, error) {
+	resp, err := b.call(session, Request{Op: OpStatus})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Created.Unix(), nil
+}
+
+// GetPaneWorkDir returns the working directory the session's command runs in.
+func (b *Backend) GetPaneWorkDir(session string) (string, error) {
+	resp, err := b.call(session, Request{Op: OpStatus})
+	if err != nil {
+		return "", err
+	}
+	if resp.Dir == "" {
+		return "", fmt.Errorf("session %s has no working directory", session)
+	}
+	return resp.Dir, nil
+}
+
+// GetAllEnvironment returns the session's env vars.
+func (b *Backend) GetAllEnvironment(session string) (map[string]string, error) {
+	resp, err := b.call(session, Request{Op: OpEnv})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Env, nil
+}
+
+// GetPanePID returns the PID of the session's command, or "" if it isn't
+// running.
+func (b *Backend) GetPanePID(session string) (string, error) {
+	resp, err := b.call(session, Request{Op: OpStatus})
+	if err != nil {
+		return "", err
+	}
+	if !resp.Alive || resp.PID == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("%d", resp.PID), nil
+}
+
+// SetAutoRespawnHook makes the supervisor restart the command whenever it
+// exits, the equivalent of the tmux pane-died hook.
+func (b *Backend) SetAutoRespawnHook(session string) error {
+	_, err := b.call(session, Request{Op: OpRespawn})
+	return err
+}
+
+// WaitForRuntimeReady waits for the agent's ready prompt, or its fixed
+// ready delay when it has no prompt to detect.
+func (b *Backend) WaitForRuntimeReady(session string, rc *config.RuntimeConfig, timeout time.Duration) error {
+	if rc == nil || rc.Tmux == nil {
+		return nil
+	}
+	if rc.Tmux.ReadyPromptPrefix == "" {
+		if rc.Tmux.ReadyDelayMs > 0 {
+			time.Sleep(min(time.Duration(rc.Tmux.ReadyDelayMs)*time.Millisecond, timeout))
+		}
+		return nil
+	}
+	if !b.waitForPrompt(session, rc.Tmux.ReadyPromptPrefix, 10, timeout) {
+		return fmt.Errorf("timeout waiting for runtime prompt")
+	}
+	return nil
+}
+
+// WaitForIdle waits until the agent shows its idle prompt.
+func (b *Backend) WaitForIdle(session string, timeout time.Duration) error {
+	if !b.waitForPrompt(session, tmux.DefaultReadyPromptPrefix, 5, timeout) {
+		return tmux.ErrIdleTimeout
+	}
+	return nil
+}
+
+// IsAtPrompt reports whether the agent is sitting at its ready prompt right
+// now, without waiting.
+func (b *Backend) IsAtPrompt(session string, rc *config.RuntimeConfig) bool {
+	prefix := tmux.DefaultReadyPromptPrefix
+	if rc != nil && rc.Tmux != nil && rc.Tmux.ReadyPromptPrefix != "" {
+		prefix = rc.Tmux.ReadyPromptPrefix
+	}
+	found, _ := b.atPrompt(session, prefix, 10)
+	return found
+}
+
+// waitForPrompt polls the last lines of scrollback for a line starting
+// with prefix. It gives up early if the session is gone.
+func (b *Backend) waitForPrompt(session, prefix string, lines int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		found, gone := b.atPrompt(session, prefix, lines)
+		if found || gone {
+			return found
+		}
+		time.Sleep(pollInterval)
+	}
+	return false
+}
+
+// atPrompt checks the last lines of scrollback once for a line starting
+// with prefix, treating NBSP as a space. gone is true if the session no
+// longer exists.
+func (b *Backend) atPrompt(session, prefix string, lines int) (found, gone bool) {
+	prefix = strings.ReplaceAll(prefix, "\u00a0", " ")
+	bare := strings.TrimSpace(prefix)
+	out, err := b.CapturePane(session, lines)
+	if errors.Is(err, tmux.ErrSessionNotFound) {
+		return false, true
+	}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(strings.ReplaceAll(line, "\u00a0", " "))
+		if strings.HasPrefix(line, prefix) || (bare != "" && line == bare) {
+			return true, false
+		}
+	}
+	return false, false
+}
+
+// Attach connects the current terminal to a session until the session ends
+// or the user presses the detach key (Ctrl-]).
+func (b *Backend) Attach(session string) error {
+	socket := SocketPath(b.townRoot, session)
+	conn, err := net.DialTimeout("unix", socket, dialTimeout)
+	if err != nil {
+		return fmt.Errorf("%w: %s", tmux.ErrSessionNotFound, session)
+	}
+	defer conn.Close()
+	_, r, err := roundTrip(conn, Request{Op: OpAttach})
+	if err != nil {
+		return err
+	}
+
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("setting raw mode: %w", err)
+		}
+		defer func() { _ = term.Restore(fd, state) }()
+
+		resize := func() {
+			if cols, rows, err := term.GetSize(fd); err == nil {
+				_, _ = call(socket, Request{Op: OpResize, Rows: uint16(rows), Cols: uint16(cols)}) //nolint:gosec // G115: terminal sizes fit
+			}
+		}
+		resize()
+		stop := notifyResize(resize)
+		defer stop()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(os.Stdout, r)
+	}()
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if n > 0 {
+				chunk := buf[:n]
+				if i := strings.IndexByte(string(chunk), DetachKey); i >= 0 {
+					_, _ = conn.Write(chunk[:i])
+					_ = conn.Close()
+					return
+				}
+				if _, err := conn.Write(chunk); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	<-done
+	return nil
+}
@@ -0,0 +1,116 @@
+package pty
+
+import (
+	"strings"
+)
+
+// namedKeys maps tmux key names to the bytes a terminal sends for them.
+var namedKeys = map[string]string{
+	"Enter":  "\r",
+	"C-m":    "\r",
+	"Tab":    "\t",
+	"C-i":    "\t",
+	"Escape": "\x1b",
+	"BSpace": "\x7f",
+	"Space":  " ",
+	"Up":     "\x1b[A",
+	"Down":   "\x1b[B",
+	"Right":  "\x1b[C",
+	"Left":   "\x1b[D",
+	"Home":   "\x1b[H",
+	"End":    "\x1b[F",
+	"DC":     "\x1b[3~",
+	"PPage":  "\x1b[5~",
+	"NPage":  "\x1b[6~",
+}
+
+// KeyBytes translates a tmux send-keys key name ("C-c", "Enter", "Escape",
+// ...) into terminal input. Like tmux, a string that isn't a key name is
+// sent literally.
+func KeyBytes(key string) string {
+	if seq, ok := namedKeys[key]; ok {
+		return seq
+	}
+	if len(key) == 3 && (key[0] == 'C' || key[0] == 'c') && key[1] == '-' {
+		if c := key[2] | 0x20; c >= 'a' && c <= 'z' {
+			return string(rune(c - 'a' + 1))
+		}
+	}
+	return key
+}
+
+// Render turns raw terminal output into plain text lines, roughly what a
+// terminal would show in its scrollback: escape sequences are dropped, a
+// carriage return rewrites the current line and backspace erases a rune.
+func Render(raw []byte) string {
+	var lines []string
+	var line []rune
+	s := []rune(string(raw))
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\x1b':
+			i = skipEscape(s, i)
+		case c == '\n':
+			lines = append(lines, strings.TrimRight(string(line), " "))
+			line = line[:0]
+		case c == '\r':
+			if i+1 < len(s) && s[i+1] == '\n' {
+				continue
+			}
+			line = line[:0]
+		case c == '\b':
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+			}
+		case c == '\t' || c >= ' ':
+			line = append(line, c)
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, strings.TrimRight(string(line), " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// skipEscape returns the index of the last rune of the escape sequence
+// starting at s[i].
+func skipEscape(s []rune, i int) int {
+	if i+1 >= len(s) {
+		return i
+	}
+	switch s[i+1] {
+	case '[': // CSI: parameters, then a final byte in @..~
+		for j := i + 2; j < len(s); j++ {
+			if s[j] >= '@' && s[j] <= '~' {
+				return j
+			}
+		}
+		return len(s) - 1
+	case ']', 'P', '_', '^': // OSC/DCS/APC/PM: until BEL or ST
+		for j := i + 2; j < len(s); j++ {
+			if s[j] == '\a' {
+				return j
+			}
+			if s[j] == '\x1b' && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 1
+			}
+		}
+		return len(s) - 1
+	case '(', ')', '*', '+': // charset designation takes one more byte
+		return min(i+2, len(s)-1)
+	default:
+		return i + 1
+	}
+}
+
+// LastLines returns the last n lines of text (all of it when n <= 0).
+func LastLines(text string, n int) string {
+	if n <= 0 {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
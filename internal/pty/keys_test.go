@@ -0,0 +1,40 @@
+package pty
+
+import "testing"
+
+func TestKeyBytes(t *testing.T) {
+	tests := map[string]string{
+		"C-c":    "\x03",
+		"C-u":    "\x15",
+		"Enter":  "\r",
+		"Escape": "\x1b",
+		"Up":     "\x1b[A",
+		"hello":  "hello",
+		"C-":     "C-",
+	}
+	for key, want := range tests {
+		if got := KeyBytes(key); got != want {
+			t.Errorf("KeyBytes(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	raw := "\x1b[1;32mok\x1b[0m line\r\n" +
+		"progress 10%\rprogress 100%\n" +
+		"\x1b]0;title\x07typo\b\bo!   \n" +
+		"\x1b(Bprompt> "
+	want := "ok line\nprogress 100%\ntyo!\nprompt>"
+	if got := Render([]byte(raw)); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestLastLines(t *testing.T) {
+	if got := LastLines("a\nb\nc", 2); got != "b\nc" {
+		t.Errorf("LastLines(2) = %q", got)
+	}
+	if got := LastLines("a\nb", 0); got != "a\nb" {
+		t.Errorf("LastLines(0) = %q", got)
+	}
+}
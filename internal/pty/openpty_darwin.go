@@ -0,0 +1,44 @@
+//go:build darwin
+
+package pty
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY allocates a pseudo-terminal pair.
+func openPTY() (ptmx, tty *os.File, err error) {
+	p, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	fd := p.Fd()
+	if err := unix.IoctlSetInt(int(fd), unix.TIOCPTYGRANT, 0); err != nil {
+		p.Close()
+		return nil, nil, fmt.Errorf("granting pty: %w", err)
+	}
+	if err := unix.IoctlSetInt(int(fd), unix.TIOCPTYUNLK, 0); err != nil {
+		p.Close()
+		return nil, nil, fmt.Errorf("unlocking pty: %w", err)
+	}
+	name := make([]byte, 128)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(unix.TIOCPTYGNAME), uintptr(unsafe.Pointer(&name[0]))); errno != 0 {
+		p.Close()
+		return nil, nil, fmt.Errorf("getting pty name: %w", errno)
+	}
+	if i := bytes.IndexByte(name, 0); i >= 0 {
+		name = name[:i]
+	}
+	t, err := os.OpenFile(string(name), os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		p.Close()
+		return nil, nil, err
+	}
+	return p, t, nil
+}
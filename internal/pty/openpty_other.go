@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package pty
+
+import (
+	"errors"
+	"os"
+)
+
+// openPTY is not implemented on this platform.
+func openPTY() (ptmx, tty *os.File, err error) {
+	return nil, nil, errors.ErrUnsupported
+}
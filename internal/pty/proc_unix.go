@@ -0,0 +1,55 @@
+//go:build !windows
+
+package pty
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// setControllingTerminal runs cmd in a new session with its stdin terminal
+// as the controlling terminal, so job control and SIGINT work as in tmux.
+func setControllingTerminal(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+}
+
+// signalGroup sends SIGTERM (or SIGKILL if force) to the process group led
+// by pid.
+func signalGroup(pid int, force bool) error {
+	sig := syscall.SIGTERM
+	if force {
+		sig = syscall.SIGKILL
+	}
+	return syscall.Kill(-pid, sig)
+}
+
+// setSize sets the terminal window size.
+func setSize(ptmx *os.File, rows, cols uint16) error {
+	return unix.IoctlSetWinsize(int(ptmx.Fd()), unix.TIOCSWINSZ, &unix.Winsize{Row: rows, Col: cols})
+}
+
+// detach starts cmd in its own session so the supervisor outlives the
+// gt command that launched it.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// notifyResize calls fn whenever the terminal is resized, until the
+// returned stop function is called.
+func notifyResize(fn func()) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGWINCH)
+	go func() {
+		for range ch {
+			fn()
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package pty
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+func setControllingTerminal(cmd *exec.Cmd) {}
+
+func signalGroup(pid int, force bool) error {
+	return errors.ErrUnsupported
+}
+
+func setSize(ptmx *os.File, rows, cols uint16) error {
+	return errors.ErrUnsupported
+}
+
+func detach(cmd *exec.Cmd) {}
+
+func notifyResize(fn func()) (stop func()) { return func() {} }
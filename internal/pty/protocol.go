@@ -0,0 +1,135 @@
+// Package pty runs agent sessions under gt-owned PTY supervisor processes,
+// as an alternative to tmux.
+//
+// Each session is one supervisor process ("gt pty supervise") that owns a
+// pseudo-terminal running the agent command, keeps a scrollback ring buffer
+// of its output and serves a Unix socket. Clients use the socket to inject
+// input, capture scrollback, set the respawn environment, kill the session
+// or attach interactively. Sessions don't share a server, so one crashing
+// supervisor takes down only its own agent.
+package pty
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Socket operations.
+const (
+	OpStatus  = "status"  // report pid and liveness
+	OpCapture = "capture" // return rendered scrollback
+	OpSend    = "send"    // write input to the terminal
+	OpSetEnv  = "setenv"  // set an env var for respawned commands
+	OpGetEnv  = "getenv"  // read a session env var
+	OpEnv     = "env"     // read all session env vars
+	OpRespawn = "respawn" // restart the command whenever it exits
+	OpResize  = "resize"  // set the terminal size
+	OpKill    = "kill"    // kill the command and end the session
+	OpAttach  = "attach"  // switch the connection to a raw terminal stream
+)
+
+// DetachKey detaches an interactive attach (Ctrl-]).
+const DetachKey = 0x1d
+
+// maxSocketPath keeps socket paths under the sun_path limit (104 on macOS).
+const maxSocketPath = 100
+
+// dialTimeout bounds connecting to and talking with a supervisor.
+const dialTimeout = 5 * time.Second
+
+// Request is one client request, sent as a JSON line.
+type Request struct {
+	Op    string `json:"op"`
+	Data  string `json:"data,omitempty"`
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value,omitempty"`
+	Lines int    `json:"lines,omitempty"`
+	Rows  uint16 `json:"rows,omitempty"`
+	Cols  uint16 `json:"cols,omitempty"`
+}
+
+// Response is the supervisor's reply, sent as a JSON line.
+type Response struct {
+	OK      bool      `json:"ok"`
+	Error   string    `json:"error,omitempty"`
+	Output  string    `json:"output,omitempty"`
+	PID     int       `json:"pid,omitempty"`
+	Alive   bool      `json:"alive,omitempty"`
+	Started time.Time `json:"started,omitzero"`
+	Restart int       `json:"restarts,omitempty"`
+
+	// Created is when the supervisor started; Started resets on respawn.
+	Created time.Time `json:"created,omitzero"`
+	// Activity is when the command last produced output.
+	Activity time.Time `json:"activity,omitzero"`
+	Attached bool      `json:"attached,omitempty"`
+
+	// Dir is the command's working directory.
+	Dir string `json:"dir,omitempty"`
+	// Env holds the session env vars (OpEnv).
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// SocketDir returns the directory holding a town's session sockets. Towns
+// whose path would push socket names past the sun_path limit use a
+// per-town directory under the system temp dir instead.
+func SocketDir(townRoot string) string {
+	dir := filepath.Join(townRoot, constants.DirRuntime, "pty")
+	if len(dir)+len("/gt-xxxxxxxxxxxxxxxxxxxxxxxxxxxx.sock") <= maxSocketPath {
+		return dir
+	}
+	sum := sha256.Sum256([]byte(townRoot))
+	return filepath.Join(os.TempDir(), "gt-pty-"+hex.EncodeToString(sum[:6]))
+}
+
+// SocketPath returns the socket of a named session.
+func SocketPath(townRoot, name string) string {
+	return filepath.Join(SocketDir(townRoot), name+".sock")
+}
+
+// call sends one request to the supervisor at socket and reads the reply.
+func call(socket string, req Request) (*Response, error) {
+	conn, err := net.DialTimeout("unix", socket, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(dialTimeout))
+
+	resp, _, err := roundTrip(conn, req)
+	return resp, err
+}
+
+// roundTrip writes req on conn and reads one response. The returned reader
+// holds any bytes buffered past the response (for attach streams).
+func roundTrip(conn net.Conn, req Request) (*Response, *bufio.Reader, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return nil, nil, err
+	}
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading %s response: %w", req.Op, err)
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return nil, nil, fmt.Errorf("parsing %s response: %w", req.Op, err)
+	}
+	if !resp.OK {
+		return &resp, r, fmt.Errorf("%s: %s", req.Op, resp.Error)
+	}
+	return &resp, r, nil
+}
@@ -0,0 +1,60 @@
+package pty
+
+import "sync"
+
+// DefaultScrollback is the default scrollback kept per session, in bytes.
+const DefaultScrollback = 1 << 20
+
+// Ring is a fixed-size byte ring buffer holding the most recent output of a
+// session. It is safe for concurrent use.
+type Ring struct {
+	mu   sync.Mutex
+	buf  []byte
+	next int  // write position
+	full bool // buf has wrapped at least once
+}
+
+// NewRing creates a ring buffer keeping the last size bytes.
+func NewRing(size int) *Ring {
+	if size <= 0 {
+		size = DefaultScrollback
+	}
+	return &Ring{buf: make([]byte, size)}
+}
+
+// Write appends p, overwriting the oldest bytes once the buffer is full.
+func (r *Ring) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := len(p)
+	if n >= len(r.buf) {
+		copy(r.buf, p[n-len(r.buf):])
+		r.next = 0
+		r.full = true
+		return n, nil
+	}
+	c := copy(r.buf[r.next:], p)
+	if c < n {
+		copy(r.buf, p[c:])
+		r.full = true
+	}
+	r.next = (r.next + n) % len(r.buf)
+	if r.next == 0 && n > 0 {
+		r.full = true
+	}
+	return n, nil
+}
+
+// Bytes returns a copy of the buffered output, oldest first.
+func (r *Ring) Bytes() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]byte(nil), r.buf[:r.next]...)
+	}
+	out := make([]byte, 0, len(r.buf))
+	out = append(out, r.buf[r.next:]...)
+	return append(out, r.buf[:r.next]...)
+}
@@ -0,0 +1,23 @@
+package pty
+
+import "testing"
+
+func TestRing(t *testing.T) {
+	r := NewRing(8)
+	_, _ = r.Write([]byte("abc"))
+	if got := string(r.Bytes()); got != "abc" {
+		t.Errorf("Bytes() = %q, want %q", got, "abc")
+	}
+	_, _ = r.Write([]byte("defgh"))
+	if got := string(r.Bytes()); got != "abcdefgh" {
+		t.Errorf("Bytes() = %q, want %q", got, "abcdefgh")
+	}
+	_, _ = r.Write([]byte("ij"))
+	if got := string(r.Bytes()); got != "cdefghij" {
+		t.Errorf("after wrap Bytes() = %q, want %q", got, "cdefghij")
+	}
+	_, _ = r.Write([]byte("0123456789"))
+	if got := string(r.Bytes()); got != "23456789" {
+		t.Errorf("oversized write Bytes() = %q, want %q", got, "23456789")
+	}
+}
@@ -0,0 +1,453 @@
+package pty
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Supervisor timings.
+const (
+	respawnDelay     = time.Second
+	killGracePeriod  = 2 * time.Second
+	outputDrainLimit = 500 * time.Millisecond
+	clientWriteLimit = time.Second
+)
+
+// clientQueue is how many output chunks may wait for an attached client
+// before it is treated as stalled and disconnected.
+const clientQueue = 256
+
+// Default terminal size, wide enough that agent TUIs rarely wrap.
+const (
+	defaultRows = 50
+	defaultCols = 200
+)
+
+// Supervisor owns one session: the PTY running its command, the scrollback
+// ring buffer and the control socket. Run blocks until the session ends.
+type Supervisor struct {
+	Name    string
+	Dir     string
+	Command string
+	Socket  string
+
+	// Scrollback is the ring buffer size in bytes (DefaultScrollback if 0).
+	Scrollback int
+
+	// Respawn restarts the command whenever it exits, until killed.
+	Respawn bool
+
+	// Env is set for the command on top of the supervisor's environment.
+	Env map[string]string
+
+	ring     *Ring
+	mu       sync.Mutex
+	ptmx     *os.File
+	cmd      *exec.Cmd
+	created  time.Time
+	started  time.Time
+	activity time.Time
+	alive    bool
+	killed   bool
+	restart  int
+	rows     uint16
+	cols     uint16
+	clients  map[net.Conn]*client
+
+	// requests tracks in-flight requests so a kill is answered before Run
+	// returns.
+	requests sync.WaitGroup
+}
+
+// Run starts the command and serves the control socket until the command
+// exits (and isn't respawned) or the session is killed.
+func (s *Supervisor) Run() error {
+	if err := os.MkdirAll(filepath.Dir(s.Socket), 0700); err != nil {
+		return fmt.Errorf("creating socket dir: %w", err)
+	}
+	if _, err := call(s.Socket, Request{Op: OpStatus}); err == nil {
+		return fmt.Errorf("session %s already running", s.Name)
+	}
+	_ = os.Remove(s.Socket)
+	ln, err := net.Listen("unix", s.Socket)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.Socket, err)
+	}
+	defer os.Remove(s.Socket)
+	defer ln.Close()
+
+	s.created = time.Now()
+	s.ring = NewRing(s.Scrollback)
+	s.clients = make(map[net.Conn]*client)
+	s.rows, s.cols = defaultRows, defaultCols
+	if s.Env == nil {
+		s.Env = make(map[string]string)
+	}
+
+	pumpDone, err := s.start()
+	if err != nil {
+		return err
+	}
+	go s.serve(ln)
+
+	for {
+		s.wait(pumpDone)
+
+		s.mu.Lock()
+		again := s.Respawn && !s.killed
+		s.mu.Unlock()
+		if !again {
+			break
+		}
+		time.Sleep(respawnDelay)
+		if pumpDone, err = s.start(); err != nil {
+			_, _ = fmt.Fprintf(s.ring, "\r\n[gt pty: respawn failed: %v]\r\n", err)
+			break
+		}
+		s.mu.Lock()
+		s.restart++
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	for conn := range s.clients {
+		s.dropClient(conn)
+	}
+	s.mu.Unlock()
+	_ = ln.Close()
+	s.requests.Wait()
+	return nil
+}
+
+// start launches the command on a fresh PTY and pumps its output.
+func (s *Supervisor) start() (<-chan struct{}, error) {
+	ptmx, tty, err := openPTY()
+	if err != nil {
+		return nil, fmt.Errorf("opening pty: %w", err)
+	}
+	s.mu.Lock()
+	_ = setSize(ptmx, s.rows, s.cols)
+	env := os.Environ()
+	if _, ok := s.Env["TERM"]; !ok && os.Getenv("TERM") == "" {
+		env = append(env, "TERM=xterm-256color")
+	}
+	keys := make([]string, 0, len(s.Env))
+	for k := range s.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		env = append(env, k+"="+s.Env[k])
+	}
+	s.mu.Unlock()
+
+	cmd := exec.Command("sh", "-c", s.Command) //nolint:gosec // G204: the session command is built by gt
+	cmd.Dir = s.Dir
+	cmd.Env = env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+	setControllingTerminal(cmd)
+	if err := cmd.Start(); err != nil {
+		_ = ptmx.Close()
+		_ = tty.Close()
+		return nil, fmt.Errorf("starting command: %w", err)
+	}
+	_ = tty.Close()
+
+	s.mu.Lock()
+	s.ptmx, s.cmd = ptmx, cmd
+	s.started = time.Now()
+	s.alive = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.pump(ptmx)
+	}()
+	return done, nil
+}
+
+// wait blocks until the current command exits and its output is drained.
+func (s *Supervisor) wait(pumpDone <-chan struct{}) {
+	s.mu.Lock()
+	cmd, ptmx := s.cmd, s.ptmx
+	s.mu.Unlock()
+
+	_ = cmd.Wait()
+	// Background children may still hold the terminal open; don't wait on them.
+	select {
+	case <-pumpDone:
+	case <-time.After(outputDrainLimit):
+	}
+	_ = ptmx.Close()
+
+	s.mu.Lock()
+	s.alive = false
+	s.mu.Unlock()
+}
+
+// pump copies terminal output to the scrollback and queues it for attached
+// clients. Writes to clients happen on their own goroutines, so a slow
+// client never holds s.mu; one whose queue fills up is disconnected.
+func (s *Supervisor) pump(ptmx *os.File) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := ptmx.Read(buf)
+		if n > 0 {
+			chunk := append([]byte(nil), buf[:n]...)
+			// Scrollback and client queues are updated together so an
+			// attach never misses or repeats a chunk.
+			s.mu.Lock()
+			_, _ = s.ring.Write(chunk)
+			s.activity = time.Now()
+			for conn, c := range s.clients {
+				select {
+				case c.out <- chunk:
+				default:
+					s.dropClient(conn)
+				}
+			}
+			s.mu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// client is an attached terminal fed from its own output queue.
+type client struct {
+	out chan []byte
+}
+
+// writeLoop delivers queued output to conn until the queue is closed or a
+// write fails.
+func (c *client) writeLoop(conn net.Conn) {
+	for chunk := range c.out {
+		_ = conn.SetWriteDeadline(time.Now().Add(clientWriteLimit))
+		if _, err := conn.Write(chunk); err != nil {
+			_ = conn.Close()
+			return
+		}
+	}
+}
+
+// dropClient disconnects an attached client. Callers hold s.mu.
+func (s *Supervisor) dropClient(conn net.Conn) {
+	c, ok := s.clients[conn]
+	if !ok {
+		return
+	}
+	delete(s.clients, conn)
+	close(c.out)
+	_ = conn.Close()
+}
+
+func (s *Supervisor) serve(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle serves one request. Attach requests keep the connection open as
+// a raw terminal stream.
+func (s *Supervisor) handle(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	_ = conn.SetReadDeadline(time.Now().Add(dialTimeout))
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	var req Request
+	if err := json.Unmarshal(line, &req); err != nil {
+		reply(conn, &Response{Error: "invalid request: " + err.Error()})
+		_ = conn.Close()
+		return
+	}
+	if req.Op == OpAttach {
+		s.attach(conn, r)
+		return
+	}
+	s.requests.Add(1)
+	defer s.requests.Done()
+	defer conn.Close()
+	reply(conn, s.do(req))
+}
+
+// do executes a non-streaming request.
+func (s *Supervisor) do(req Request) *Response {
+	switch req.Op {
+	case OpStatus:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		resp := &Response{
+			OK:       true,
+			Alive:    s.alive,
+			Started:  s.started,
+			Restart:  s.restart,
+			Created:  s.created,
+			Activity: s.activity,
+			Attached: len(s.clients) > 0,
+			Dir:      s.Dir,
+		}
+		if s.alive && s.cmd.Process != nil {
+			resp.PID = s.cmd.Process.Pid
+		}
+		return resp
+
+	case OpCapture:
+		return &Response{OK: true, Output: LastLines(Render(s.ring.Bytes()), req.Lines)}
+
+	case OpSend:
+		s.mu.Lock()
+		ptmx, alive := s.ptmx, s.alive
+		s.mu.Unlock()
+		if !alive {
+			return &Response{Error: "session command is not running"}
+		}
+		if _, err := io.WriteString(ptmx, req.Data); err != nil {
+			return &Response{Error: err.Error()}
+		}
+		return &Response{OK: true}
+
+	case OpSetEnv:
+		if req.Key == "" {
+			return &Response{Error: "setenv requires a key"}
+		}
+		s.mu.Lock()
+		s.Env[req.Key] = req.Value
+		s.mu.Unlock()
+		return &Response{OK: true}
+
+	case OpGetEnv:
+		s.mu.Lock()
+		v, ok := s.Env[req.Key]
+		s.mu.Unlock()
+		if !ok {
+			return &Response{Error: "unknown variable " + req.Key}
+		}
+		return &Response{OK: true, Output: v}
+
+	case OpEnv:
+		s.mu.Lock()
+		env := make(map[string]string, len(s.Env))
+		for k, v := range s.Env {
+			env[k] = v
+		}
+		s.mu.Unlock()
+		return &Response{OK: true, Env: env}
+
+	case OpRespawn:
+		s.mu.Lock()
+		s.Respawn = true
+		s.mu.Unlock()
+		return &Response{OK: true}
+
+	case OpResize:
+		if req.Rows == 0 || req.Cols == 0 {
+			return &Response{Error: "resize requires rows and cols"}
+		}
+		s.mu.Lock()
+		s.rows, s.cols = req.Rows, req.Cols
+		ptmx, alive := s.ptmx, s.alive
+		s.mu.Unlock()
+		if alive {
+			if err := setSize(ptmx, req.Rows, req.Cols); err != nil {
+				return &Response{Error: err.Error()}
+			}
+		}
+		return &Response{OK: true}
+
+	case OpKill:
+		s.kill()
+		return &Response{OK: true}
+
+	default:
+		return &Response{Error: fmt.Sprintf("unknown op %q", req.Op)}
+	}
+}
+
+// kill ends the session: SIGTERM to the command's process group, then
+// SIGKILL if it hasn't exited after killGracePeriod.
+func (s *Supervisor) kill() {
+	s.mu.Lock()
+	s.killed = true
+	cmd, alive := s.cmd, s.alive
+	s.mu.Unlock()
+	if !alive || cmd.Process == nil {
+		return
+	}
+	_ = signalGroup(cmd.Process.Pid, false)
+	deadline := time.Now().Add(killGracePeriod)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		alive = s.alive
+		s.mu.Unlock()
+		if !alive {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	_ = signalGroup(cmd.Process.Pid, true)
+}
+
+// attach replays the scrollback to conn, then streams output to it and
+// its input to the terminal until the client disconnects.
+func (s *Supervisor) attach(conn net.Conn, r *bufio.Reader) {
+	defer conn.Close()
+	reply(conn, &Response{OK: true})
+
+	c := &client{out: make(chan []byte, clientQueue)}
+	s.mu.Lock()
+	c.out <- s.ring.Bytes()
+	s.clients[conn] = c
+	s.mu.Unlock()
+	go c.writeLoop(conn)
+	defer func() {
+		s.mu.Lock()
+		s.dropClient(conn)
+		s.mu.Unlock()
+	}()
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			s.mu.Lock()
+			ptmx, alive := s.ptmx, s.alive
+			s.mu.Unlock()
+			if alive {
+				_, _ = ptmx.Write(buf[:n])
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func reply(conn net.Conn, resp *Response) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	_ = conn.SetWriteDeadline(time.Now().Add(dialTimeout))
+	_, _ = conn.Write(append(data, '\n'))
+	_ = conn.SetWriteDeadline(time.Time{})
+}
@@ -0,0 +1,171 @@
+//go:build linux || darwin
+
+package pty
+
+import (
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startSupervisor runs a supervisor for command in the background and
+// waits for its socket.
+func startSupervisor(t *testing.T, s *Supervisor) <-chan error {
+	t.Helper()
+	if s.Socket == "" {
+		s.Socket = filepath.Join(t.TempDir(), "s.sock")
+	}
+	if s.Dir == "" {
+		s.Dir = t.TempDir()
+	}
+	done := make(chan error, 1)
+	go func() { done <- s.Run() }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := call(s.Socket, Request{Op: OpStatus}); err == nil {
+			t.Cleanup(func() { _, _ = call(s.Socket, Request{Op: OpKill}) })
+			return done
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("supervisor socket never came up")
+	return nil
+}
+
+// waitForOutput polls capture until it contains want.
+func waitForOutput(t *testing.T, socket, want string) string {
+	t.Helper()
+	var out string
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		resp, err := call(socket, Request{Op: OpCapture})
+		if err == nil {
+			out = resp.Output
+			if strings.Contains(out, want) {
+				return out
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("capture never contained %q; last output:\n%s", want, out)
+	return ""
+}
+
+func TestSupervisor_SendCaptureKill(t *testing.T) {
+	s := &Supervisor{Name: "test", Command: "printf 'started %s\\n' \"$GT_TEST\"; cat", Env: map[string]string{"GT_TEST": "yes"}}
+	done := startSupervisor(t, s)
+
+	waitForOutput(t, s.Socket, "started yes")
+
+	st, err := call(s.Socket, Request{Op: OpStatus})
+	if err != nil || !st.Alive || st.PID == 0 {
+		t.Fatalf("status = %+v, %v", st, err)
+	}
+
+	if _, err := call(s.Socket, Request{Op: OpSend, Data: "hello pty" + KeyBytes("Enter")}); err != nil {
+		t.Fatal(err)
+	}
+	waitForOutput(t, s.Socket, "hello pty")
+
+	if _, err := call(s.Socket, Request{Op: OpSetEnv, Key: "GT_ROLE", Value: "mayor"}); err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := call(s.Socket, Request{Op: OpGetEnv, Key: "GT_ROLE"}); err != nil || resp.Output != "mayor" {
+		t.Errorf("getenv = %+v, %v", resp, err)
+	}
+	if _, err := call(s.Socket, Request{Op: OpGetEnv, Key: "MISSING"}); err == nil {
+		t.Error("getenv of unset variable should fail")
+	}
+	if resp, err := call(s.Socket, Request{Op: OpEnv}); err != nil || resp.Env["GT_ROLE"] != "mayor" || resp.Env["GT_TEST"] != "yes" {
+		t.Errorf("env = %+v, %v", resp, err)
+	}
+	if st, err := call(s.Socket, Request{Op: OpStatus}); err != nil || st.Dir != s.Dir {
+		t.Errorf("status dir = %+v, %v; want %s", st, err, s.Dir)
+	}
+
+	if _, err := call(s.Socket, Request{Op: OpKill}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor did not exit after kill")
+	}
+	if _, err := call(s.Socket, Request{Op: OpStatus}); err == nil {
+		t.Error("socket still answering after kill")
+	}
+}
+
+func TestSupervisor_Respawn(t *testing.T) {
+	s := &Supervisor{Name: "respawn", Command: "echo tick; sleep 0.1", Respawn: true}
+	startSupervisor(t, s)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		resp, err := call(s.Socket, Request{Op: OpCapture})
+		if err == nil && strings.Count(resp.Output, "tick") >= 2 {
+			if st, err := call(s.Socket, Request{Op: OpStatus}); err != nil || st.Restart < 1 {
+				t.Errorf("status = %+v, %v; want a restart", st, err)
+			}
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("command was never respawned")
+}
+
+func TestSupervisor_RefusesDuplicate(t *testing.T) {
+	s := &Supervisor{Name: "dup", Command: "cat"}
+	startSupervisor(t, s)
+
+	dup := &Supervisor{Name: "dup", Command: "cat", Socket: s.Socket, Dir: s.Dir}
+	if err := dup.Run(); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("duplicate Run() = %v, want already running", err)
+	}
+}
+
+// TestSupervisor_StalledClientDoesNotBlock attaches a client that never
+// reads while the command floods output: requests must stay responsive and
+// the stalled client is eventually disconnected.
+func TestSupervisor_StalledClientDoesNotBlock(t *testing.T) {
+	s := &Supervisor{Name: "flood", Command: "yes flood"}
+	startSupervisor(t, s)
+
+	conn, err := net.Dial("unix", s.Socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(`{"op":"attach"}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		start := time.Now()
+		if _, err := call(s.Socket, Request{Op: OpStatus}); err != nil {
+			t.Fatal(err)
+		}
+		if d := time.Since(start); d > clientWriteLimit/2 {
+			t.Fatalf("status took %v with a stalled client attached", d)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		n := len(s.clients)
+		s.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Error("stalled client was never disconnected")
+}
@@ -0,0 +1,269 @@
+package refinery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Merge action phases.
+const (
+	PhasePreMerge  = "pre-merge"
+	PhasePostMerge = "post-merge"
+)
+
+// Merge action severities.
+const (
+	// SeverityBlocking failures stop the phase. A blocking pre-merge
+	// failure rejects the merge; a blocking post-merge failure skips the
+	// remaining post-merge actions (the merge itself has already landed).
+	SeverityBlocking = "blocking"
+
+	// SeverityWarning failures are logged and recorded on the MR bead but
+	// do not stop the phase.
+	SeverityWarning = "warning"
+)
+
+// MergeActionConfig defines a command run before or after a merge, such as
+// building artifacts, deploying a preview or tagging a release.
+//
+// Actions run sequentially in name order with the MR described by GT_MR_ID,
+// GT_BRANCH, GT_TARGET, GT_SOURCE_ISSUE, GT_WORKER, GT_RIG and
+// GT_MERGE_COMMIT. An action reports outputs (URLs, artifact paths) by
+// writing "key=value" lines to the file named by GT_OUTPUT; they are
+// attached to the MR bead as "<action>.<key>".
+type MergeActionConfig struct {
+	// Cmd is the shell command to execute.
+	Cmd string `json:"cmd"`
+
+	// Timeout is the maximum time the action may run. Zero means no timeout.
+	Timeout time.Duration `json:"timeout"`
+
+	// Severity is SeverityBlocking or SeverityWarning. Defaults to blocking
+	// for pre-merge actions and warning for post-merge actions.
+	Severity string `json:"severity"`
+}
+
+// mergeActionConfigRaw is the JSON-friendly representation of a merge
+// action with timeout as a string duration.
+type mergeActionConfigRaw struct {
+	Cmd      string `json:"cmd"`
+	Timeout  string `json:"timeout"`
+	Severity string `json:"severity"`
+}
+
+// parseMergeActions converts raw action configs, validating timeouts and
+// severities.
+func parseMergeActions(phase string, raw map[string]*mergeActionConfigRaw) (map[string]*MergeActionConfig, error) {
+	actions := make(map[string]*MergeActionConfig, len(raw))
+	for name, r := range raw {
+		if r == nil || strings.TrimSpace(r.Cmd) == "" {
+			return nil, fmt.Errorf("%s action %q: cmd is required", phase, name)
+		}
+		if strings.ContainsAny(name, ".=") {
+			return nil, fmt.Errorf("%s action %q: name must not contain '.' or '='", phase, name)
+		}
+		ac := &MergeActionConfig{Cmd: r.Cmd, Severity: r.Severity}
+		switch r.Severity {
+		case "", SeverityBlocking, SeverityWarning:
+		default:
+			return nil, fmt.Errorf("%s action %q: invalid severity %q (use %q or %q)", phase, name, r.Severity, SeverityBlocking, SeverityWarning)
+		}
+		if r.Timeout != "" {
+			dur, err := time.ParseDuration(r.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout for %s action %q: %w", phase, name, err)
+			}
+			if dur <= 0 {
+				return nil, fmt.Errorf("%s action %q timeout must be positive, got %v", phase, name, dur)
+			}
+			ac.Timeout = dur
+		}
+		actions[name] = ac
+	}
+	return actions, nil
+}
+
+// severity returns the action's effective severity in phase.
+func (a *MergeActionConfig) severity(phase string) string {
+	if a.Severity != "" {
+		return a.Severity
+	}
+	if phase == PhasePreMerge {
+		return SeverityBlocking
+	}
+	return SeverityWarning
+}
+
+// MergeActionEnv describes the MR a merge action runs for.
+type MergeActionEnv struct {
+	MRID        string
+	Branch      string
+	Target      string
+	SourceIssue string
+	Worker      string
+	MergeCommit string
+}
+
+// MergeActionsResult is the outcome of one phase of merge actions.
+type MergeActionsResult struct {
+	// Outputs holds "<action>.<key>" values reported by the actions, plus
+	// "<action>.error" for each failed action.
+	Outputs map[string]string
+
+	// Warnings lists failures of warning-severity actions.
+	Warnings []string
+
+	// Blocked is the error of the blocking action that stopped the phase,
+	// or "" if no blocking action failed.
+	Blocked string
+}
+
+// runMergeActions runs the actions of one phase sequentially in name order.
+func (e *Engineer) runMergeActions(ctx context.Context, phase string, actions map[string]*MergeActionConfig, env MergeActionEnv) MergeActionsResult {
+	result := MergeActionsResult{Outputs: make(map[string]string)}
+	if len(actions) == 0 {
+		return result
+	}
+
+	names := make([]string, 0, len(actions))
+	for name := range actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Running %d %s action(s)\n", len(names), phase)
+	for _, name := range names {
+		action := actions[name]
+		severity := action.severity(phase)
+		_, _ = fmt.Fprintf(e.output, "[Engineer] %s action %q: starting (%s)\n", phase, name, action.Cmd)
+
+		start := time.Now()
+		outputs, err := e.runMergeAction(ctx, phase, action, env)
+		elapsed := time.Since(start).Truncate(time.Millisecond)
+		for key, value := range outputs {
+			result.Outputs[name+"."+key] = value
+		}
+		if err == nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] %s action %q: passed (%v)\n", phase, name, elapsed)
+			continue
+		}
+
+		msg := fmt.Sprintf("%s: %v", name, err)
+		result.Outputs[name+".error"] = err.Error()
+		if severity == SeverityWarning {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] %s action %q: WARNING (%v) - %v\n", phase, name, elapsed, err)
+			result.Warnings = append(result.Warnings, msg)
+			continue
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] %s action %q: FAILED (%v) - %v\n", phase, name, elapsed, err)
+		result.Blocked = msg
+		break
+	}
+	return result
+}
+
+// runMergeAction runs a single action and returns the outputs it reported.
+func (e *Engineer) runMergeAction(ctx context.Context, phase string, action *MergeActionConfig, env MergeActionEnv) (map[string]string, error) {
+	actionCtx := ctx
+	if action.Timeout > 0 {
+		var cancel context.CancelFunc
+		actionCtx, cancel = context.WithTimeout(ctx, action.Timeout)
+		defer cancel()
+	}
+
+	outFile, err := os.CreateTemp("", "gt-merge-action-*.out")
+	if err != nil {
+		return nil, fmt.Errorf("creating output file: %w", err)
+	}
+	outPath := outFile.Name()
+	_ = outFile.Close()
+	defer func() { _ = os.Remove(outPath) }()
+
+	rigName := ""
+	if e.rig != nil {
+		rigName = e.rig.Name
+	}
+
+	// Trust boundary: action commands come from rig config (operator-controlled).
+	cmd := exec.CommandContext(actionCtx, "sh", "-c", action.Cmd) //nolint:gosec // G204: merge actions are from trusted rig config
+	cmd.Dir = e.workDir
+	cmd.Env = append(os.Environ(),
+		"GT_OUTPUT="+outPath,
+		"GT_MERGE_PHASE="+phase,
+		"GT_MR_ID="+env.MRID,
+		"GT_BRANCH="+env.Branch,
+		"GT_TARGET="+env.Target,
+		"GT_SOURCE_ISSUE="+env.SourceIssue,
+		"GT_WORKER="+env.Worker,
+		"GT_MERGE_COMMIT="+env.MergeCommit,
+		"GT_RIG="+rigName,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	outputs := parseActionOutputs(outPath)
+	if runErr == nil {
+		return outputs, nil
+	}
+
+	if actionCtx.Err() == context.DeadlineExceeded {
+		return outputs, fmt.Errorf("timed out after %v", action.Timeout)
+	}
+	if stderrStr := strings.TrimSpace(stderr.String()); stderrStr != "" {
+		// Cap stderr to avoid huge error messages
+		if len(stderrStr) > 500 {
+			stderrStr = stderrStr[:500] + "..."
+		}
+		return outputs, fmt.Errorf("%v: %s", runErr, stderrStr)
+	}
+	return outputs, runErr
+}
+
+// parseActionOutputs reads "key=value" lines from an action's GT_OUTPUT
+// file. Keys are lowercased; blank lines and lines without '=' are ignored.
+func parseActionOutputs(path string) map[string]string {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a temp file we created
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	outputs := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if !ok || key == "" || value == "" || strings.ContainsAny(key, " :") {
+			continue
+		}
+		outputs[key] = value
+	}
+	return outputs
+}
+
+// RunPostMergeActions runs the configured post-merge actions for a merged MR.
+// Used by the formula-driven refinery via 'gt mq post-merge'.
+func (e *Engineer) RunPostMergeActions(ctx context.Context, env MergeActionEnv) MergeActionsResult {
+	return e.runMergeActions(ctx, PhasePostMerge, e.config.PostMerge, env)
+}
+
+// mergeOutputs combines the outputs of several phases.
+func mergeOutputs(sets ...map[string]string) map[string]string {
+	var merged map[string]string
+	for _, set := range sets {
+		for k, v := range set {
+			if merged == nil {
+				merged = make(map[string]string)
+			}
+			merged[k] = v
+		}
+	}
+	return merged
+}
@@ -0,0 +1,150 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestRunMergeActions_OutputsAndSeverity(t *testing.T) {
+	e := newTestEngineer(t, t.TempDir(), nil)
+	actions := map[string]*MergeActionConfig{
+		"a-preview": {Cmd: `printf 'URL=http://preview/%s\nnot a pair\n' "$GT_MR_ID" > "$GT_OUTPUT"`},
+		"b-lint":    {Cmd: "echo lint broke >&2; exit 1"},
+		"c-tag":     {Cmd: `echo "tag=$GT_MERGE_PHASE" > "$GT_OUTPUT"`},
+	}
+	env := MergeActionEnv{MRID: "gt-mr1", MergeCommit: "abc123"}
+
+	// Post-merge failures default to warnings: the phase continues.
+	result := e.runMergeActions(context.Background(), PhasePostMerge, actions, env)
+	if result.Blocked != "" {
+		t.Fatalf("post-merge should not block: %s", result.Blocked)
+	}
+	if got := result.Outputs["a-preview.url"]; got != "http://preview/gt-mr1" {
+		t.Errorf("a-preview.url = %q, want http://preview/gt-mr1", got)
+	}
+	if got := result.Outputs["c-tag.tag"]; got != PhasePostMerge {
+		t.Errorf("c-tag.tag = %q, want %s", got, PhasePostMerge)
+	}
+	if !strings.Contains(result.Outputs["b-lint.error"], "lint broke") {
+		t.Errorf("b-lint.error = %q, want stderr", result.Outputs["b-lint.error"])
+	}
+	if len(result.Warnings) != 1 || !strings.HasPrefix(result.Warnings[0], "b-lint:") {
+		t.Errorf("Warnings = %v, want one b-lint warning", result.Warnings)
+	}
+
+	// Pre-merge failures default to blocking: later actions are skipped.
+	result = e.runMergeActions(context.Background(), PhasePreMerge, actions, env)
+	if !strings.HasPrefix(result.Blocked, "b-lint:") {
+		t.Fatalf("Blocked = %q, want b-lint failure", result.Blocked)
+	}
+	if _, ok := result.Outputs["c-tag.tag"]; ok {
+		t.Error("actions after a blocking failure should not run")
+	}
+}
+
+func TestDoMerge_PreMergeActionBlocks(t *testing.T) {
+	workDir, g, cleanup := testGitRepo(t)
+	defer cleanup()
+	createFeatureBranch(t, workDir, "polecat/feat", "feat.txt", "feature\n")
+	before := run(t, workDir, "git", "rev-parse", "origin/main")
+
+	e := newTestEngineer(t, workDir, g)
+	e.config.RunTests = false
+	e.config.PreMerge = map[string]*MergeActionConfig{
+		"artifacts": {Cmd: `test -f feat.txt && echo "path=dist/feat.tar" > "$GT_OUTPUT" && exit 3`},
+	}
+
+	result := e.doMerge(context.Background(), "polecat/feat", "main", "gt-abc")
+	if result.Success {
+		t.Fatal("blocking pre-merge failure should reject the merge")
+	}
+	if !strings.Contains(result.Error, "pre-merge action failed: artifacts") {
+		t.Errorf("Error = %q, want pre-merge failure", result.Error)
+	}
+	if got := result.Outputs["artifacts.path"]; got != "dist/feat.tar" {
+		t.Errorf("artifacts.path = %q, want outputs from the failed action", got)
+	}
+	run(t, workDir, "git", "fetch", "origin")
+	if after := run(t, workDir, "git", "rev-parse", "origin/main"); after != before {
+		t.Error("target branch should not move when a pre-merge action blocks")
+	}
+
+	e.config.PreMerge["artifacts"].Severity = SeverityWarning
+	result = e.doMerge(context.Background(), "polecat/feat", "main", "gt-abc")
+	if !result.Success {
+		t.Fatalf("warning pre-merge failure should not block: %s", result.Error)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("Warnings = %v, want one", result.Warnings)
+	}
+}
+
+func TestGate_RunsPreMergeAgainstHead(t *testing.T) {
+	workDir, g, cleanup := testGitRepo(t)
+	defer cleanup()
+	createFeatureBranch(t, workDir, "polecat/feat", "feat.txt", "feature\n")
+	run(t, workDir, "git", "checkout", "-b", "temp", "polecat/feat")
+	head := run(t, workDir, "git", "rev-parse", "HEAD")
+
+	e := newTestEngineer(t, workDir, g)
+	e.config.RunTests = false
+	e.config.PreMerge = map[string]*MergeActionConfig{
+		"artifacts": {Cmd: `test -f feat.txt && echo "commit=$GT_MERGE_COMMIT" > "$GT_OUTPUT"`},
+	}
+
+	result := e.Gate(context.Background(), MergeActionEnv{MRID: "gt-mr1", Target: "main"})
+	if !result.Success {
+		t.Fatalf("Gate failed: %s", result.Error)
+	}
+	if got := result.Outputs["artifacts.commit"]; got != head {
+		t.Errorf("artifacts.commit = %q, want HEAD %s", got, head)
+	}
+
+	e.config.Gates = map[string]*GateConfig{"fail": {Cmd: "exit 1"}}
+	if result := e.Gate(context.Background(), MergeActionEnv{MRID: "gt-mr1"}); result.Success {
+		t.Error("Gate succeeded with a failing gate")
+	}
+}
+
+func TestEngineer_LoadConfig_MergeActions(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(mq map[string]interface{}) {
+		data, _ := json.Marshal(map[string]interface{}{"merge_queue": mq})
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(map[string]interface{}{
+		"pre_merge":  map[string]interface{}{"build": map[string]interface{}{"cmd": "make dist", "timeout": "5m"}},
+		"post_merge": map[string]interface{}{"preview": map[string]interface{}{"cmd": "./deploy.sh", "severity": "blocking"}},
+	})
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if build := e.Config().PreMerge["build"]; build == nil || build.Timeout.Minutes() != 5 || build.severity(PhasePreMerge) != SeverityBlocking {
+		t.Errorf("pre_merge build = %+v", build)
+	}
+	if preview := e.Config().PostMerge["preview"]; preview == nil || preview.severity(PhasePostMerge) != SeverityBlocking {
+		t.Errorf("post_merge preview = %+v", preview)
+	}
+
+	for name, mq := range map[string]map[string]interface{}{
+		"missing cmd":  {"post_merge": map[string]interface{}{"x": map[string]interface{}{}}},
+		"bad severity": {"post_merge": map[string]interface{}{"x": map[string]interface{}{"cmd": "true", "severity": "fatal"}}},
+		"dotted name":  {"pre_merge": map[string]interface{}{"a.b": map[string]interface{}{"cmd": "true"}}},
+		"bad timeout":  {"pre_merge": map[string]interface{}{"x": map[string]interface{}{"cmd": "true", "timeout": "-1s"}}},
+	} {
+		write(mq)
+		if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -205,6 +205,8 @@ func (e *Engineer) ProcessBatch(ctx context.Context, batch []*MRInfo, target str
 		return result
 	}
 
+	ctx = withGateMRs(ctx, mrIDs(batch)...)
+
 	// Single MR: use existing doMerge path (no batch overhead)
 	if len(batch) == 1 {
 		return e.processSingleMR(ctx, batch[0], target)
@@ -314,6 +316,8 @@ func (e *Engineer) runBatchGates(ctx context.Context) ProcessResult {
 				Success:     false,
 				TestsFailed: true,
 				Error:       result.Error,
+				FlakyTests:  result.FlakyTests,
+				FlakyOnly:   result.FlakyOnly,
 			}
 		}
 		return ProcessResult{Success: true}
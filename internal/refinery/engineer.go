@@ -22,6 +22,7 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
 )
 
@@ -54,6 +55,11 @@ type GateConfig struct {
 	// Timeout is the maximum time the gate command may run.
 	// Zero means no timeout (inherits context deadline).
 	Timeout time.Duration `json:"timeout"`
+
+	// Report is an optional path, relative to the worktree, of a JUnit XML
+	// or `go test -json` report written by the gate. When empty, the gate's
+	// stdout is parsed instead. Per-test outcomes feed flaky detection.
+	Report string `json:"report,omitempty"`
 }
 
 // GateResult holds the outcome of a single gate execution.
@@ -62,6 +68,12 @@ type GateResult struct {
 	Success bool
 	Error   string
 	Elapsed time.Duration
+
+	// Tests classifies the gate's test outcomes across retries.
+	// Empty when the gate emits no parseable test report.
+	Tests TestRunSummary
+
+	outcomes []TestOutcome
 }
 
 // MergeQueueConfig holds configuration for the merge queue processor.
@@ -86,9 +98,14 @@ type MergeQueueConfig struct {
 	// DeleteMergedBranches controls whether to delete branches after merge.
 	DeleteMergedBranches bool `json:"delete_merged_branches"`
 
-	// RetryFlakyTests is the number of times to retry flaky tests.
+	// RetryFlakyTests is the total number of attempts per run, including the
+	// first. Applies to the legacy test command and to each quality gate.
+	// Values below 2 disable retries, and with them flake detection.
 	RetryFlakyTests int `json:"retry_flaky_tests"`
 
+	// Flaky controls flaky test detection from gate test reports.
+	Flaky *FlakyConfig `json:"flaky,omitempty"`
+
 	// PollInterval is how often to check for new MRs.
 	PollInterval time.Duration `json:"poll_interval"`
 
@@ -99,8 +116,9 @@ type MergeQueueConfig struct {
 	// being considered abandoned and eligible for re-claim. This handles the
 	// case where a refinery crashes mid-merge, leaving an MR permanently claimed.
 	// Set conservatively to avoid re-claiming MRs with long-running test suites.
-	// NOTE: Only one refinery instance runs per rig (enforced by ErrAlreadyRunning
-	// in manager.go), so concurrent re-claim is not a concern in practice.
+	// NOTE: Only one refinery instance runs per rig or lane (enforced by
+	// ErrAlreadyRunning in manager.go), and each MR routes to exactly one lane,
+	// so concurrent re-claim is not a concern in practice.
 	StaleClaimTimeout time.Duration `json:"stale_claim_timeout"`
 
 	// Gates defines named quality gate commands to run before merging.
@@ -112,6 +130,15 @@ type MergeQueueConfig struct {
 	// When true, all gates start simultaneously; any failure = overall failure.
 	GatesParallel bool `json:"gates_parallel"`
 
+	// PreMerge defines named actions run on the squashed merge commit before
+	// it is pushed (e.g., building release artifacts). Blocking failures
+	// reject the merge.
+	PreMerge map[string]*MergeActionConfig `json:"pre_merge,omitempty"`
+
+	// PostMerge defines named actions run after a successful merge
+	// (e.g., deploying a preview environment or tagging a release).
+	PostMerge map[string]*MergeActionConfig `json:"post_merge,omitempty"`
+
 	// StaleClaimWarningAfter is how long a claimed MR can sit without updates
 	// before it triggers a "warning" severity anomaly.
 	StaleClaimWarningAfter time.Duration `json:"stale_claim_warning_after"`
@@ -127,6 +154,15 @@ type MergeQueueConfig struct {
 	// Batch holds configuration for the batch-then-bisect merge queue.
 	// When nil or MaxBatchSize <= 1, batching is disabled and MRs process sequentially.
 	Batch *BatchConfig `json:"batch,omitempty"`
+
+	// Speculative holds configuration for speculative gate pipelines.
+	// When nil or Depth <= 1, MRs are gated one at a time (or batched).
+	Speculative *SpeculativeConfig `json:"speculative,omitempty"`
+
+	// Lanes partitions the queue into parallel merge lanes, each served by
+	// its own refinery instance. MRs not matching any lane go to DefaultLane.
+	// When empty, a single refinery processes the whole queue.
+	Lanes []*LaneConfig `json:"lanes,omitempty"`
 }
 
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
@@ -139,6 +175,7 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 		DeleteMergedBranches:    true,
 		GatesParallel:           true, // gt-8b2i: run gates concurrently (~2x speedup)
 		RetryFlakyTests:         1,
+		Flaky:                   DefaultFlakyConfig(),
 		PollInterval:            30 * time.Second,
 		MaxConcurrent:           1,
 		StaleClaimTimeout:       DefaultStaleClaimTimeout,
@@ -165,6 +202,7 @@ type MRInfo struct {
 	ConvoyCreatedAt *time.Time // Convoy creation time
 	CreatedAt       time.Time  // MR creation time
 	BlockedBy       string     // Task ID blocking this MR
+	Lane            string     // Merge lane (empty when lanes are not configured)
 
 	// Pre-verification fields (Phase 3: polecat-owned rebasing)
 	// When set, the refinery can skip gates if VerifiedBase matches target HEAD.
@@ -184,12 +222,12 @@ type MRAnomaly struct {
 	ID       string        `json:"id"`
 	Branch   string        `json:"branch"`
 	Type     string        `json:"type"` // stale-claim | orphaned-branch
+	Lane     string        `json:"lane,omitempty"`
 	Assignee string        `json:"assignee,omitempty"`
 	Age      time.Duration `json:"age,omitempty"`
 	Detail   string        `json:"detail"`
 }
 
-
 // errMergeSlotTimeout is returned by acquireMainPushSlot when retries are
 // exhausted due to slot contention. Infrastructure errors (beads down,
 // permission errors) return a different error so callers can distinguish
@@ -209,8 +247,10 @@ type Engineer struct {
 	git                   *git.Git
 	config                *MergeQueueConfig
 	workDir               string
-	output                io.Writer    // Output destination for user-facing messages
-	router                *mail.Router // Mail router for sending protocol messages
+	output                io.Writer        // Output destination for user-facing messages
+	router                *mail.Router     // Mail router for sending protocol messages
+	lane                  string           // Merge lane this engineer serves ("" = whole queue)
+	gateCache             *gateResultCache // Passing gate results by tree (speculative mode)
 	mergeSlotEnsureExists func() (string, error)
 	mergeSlotAcquire      func(holder string, addWaiter bool) (*beads.MergeSlotStatus, error)
 	mergeSlotRelease      func(holder string) error
@@ -287,17 +327,22 @@ func (e *Engineer) LoadConfig() error {
 	// Parse merge_queue section into our config struct
 	// We need special handling for poll_interval (string -> Duration)
 	var mqRaw struct {
-		Enabled              *bool                      `json:"enabled"`
-		OnConflict           *string                    `json:"on_conflict"`
-		RunTests             *bool                      `json:"run_tests"`
-		TestCommand          *string                    `json:"test_command"`
-		DeleteMergedBranches *bool                      `json:"delete_merged_branches"`
-		RetryFlakyTests      *int                       `json:"retry_flaky_tests"`
-		PollInterval         *string                    `json:"poll_interval"`
-		MaxConcurrent        *int                       `json:"max_concurrent"`
-		StaleClaimTimeout    *string                    `json:"stale_claim_timeout"`
-		Gates                map[string]*gateConfigRaw  `json:"gates"`
-		GatesParallel        *bool                      `json:"gates_parallel"`
+		Enabled              *bool                            `json:"enabled"`
+		OnConflict           *string                          `json:"on_conflict"`
+		RunTests             *bool                            `json:"run_tests"`
+		TestCommand          *string                          `json:"test_command"`
+		DeleteMergedBranches *bool                            `json:"delete_merged_branches"`
+		RetryFlakyTests      *int                             `json:"retry_flaky_tests"`
+		PollInterval         *string                          `json:"poll_interval"`
+		MaxConcurrent        *int                             `json:"max_concurrent"`
+		StaleClaimTimeout    *string                          `json:"stale_claim_timeout"`
+		Gates                map[string]*gateConfigRaw        `json:"gates"`
+		GatesParallel        *bool                            `json:"gates_parallel"`
+		PreMerge             map[string]*mergeActionConfigRaw `json:"pre_merge"`
+		PostMerge            map[string]*mergeActionConfigRaw `json:"post_merge"`
+		Lanes                []*LaneConfig                    `json:"lanes"`
+		Speculative          json.RawMessage                  `json:"speculative"`
+		Flaky                json.RawMessage                  `json:"flaky"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -348,7 +393,7 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.Gates != nil {
 		e.config.Gates = make(map[string]*GateConfig, len(mqRaw.Gates))
 		for name, raw := range mqRaw.Gates {
-			gc := &GateConfig{Cmd: raw.Cmd}
+			gc := &GateConfig{Cmd: raw.Cmd, Report: raw.Report}
 			if raw.Timeout != "" {
 				dur, err := time.ParseDuration(raw.Timeout)
 				if err != nil {
@@ -365,6 +410,53 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.GatesParallel != nil {
 		e.config.GatesParallel = *mqRaw.GatesParallel
 	}
+	if mqRaw.PreMerge != nil {
+		actions, err := parseMergeActions(PhasePreMerge, mqRaw.PreMerge)
+		if err != nil {
+			return err
+		}
+		e.config.PreMerge = actions
+	}
+	if mqRaw.PostMerge != nil {
+		actions, err := parseMergeActions(PhasePostMerge, mqRaw.PostMerge)
+		if err != nil {
+			return err
+		}
+		e.config.PostMerge = actions
+	}
+	if mqRaw.Lanes != nil {
+		if err := validateLanes(mqRaw.Lanes); err != nil {
+			return fmt.Errorf("invalid merge_queue lanes: %w", err)
+		}
+		e.config.Lanes = mqRaw.Lanes
+	}
+	if mqRaw.Speculative != nil {
+		// Start from defaults so omitted fields (e.g. cache_results) keep them.
+		spec := DefaultSpeculativeConfig()
+		if err := json.Unmarshal(mqRaw.Speculative, spec); err != nil {
+			return fmt.Errorf("parsing merge_queue speculative config: %w", err)
+		}
+		if spec.Depth < 0 {
+			return fmt.Errorf("speculative depth must not be negative, got %d", spec.Depth)
+		}
+		e.config.Speculative = spec
+	}
+	if mqRaw.Flaky != nil {
+		flaky := DefaultFlakyConfig()
+		if err := json.Unmarshal(mqRaw.Flaky, flaky); err != nil {
+			return fmt.Errorf("parsing merge_queue flaky config: %w", err)
+		}
+		if flaky.MinFlakes < 1 {
+			return fmt.Errorf("flaky min_flakes must be at least 1, got %d", flaky.MinFlakes)
+		}
+		if flaky.Threshold < 0 || flaky.Threshold > 1 {
+			return fmt.Errorf("flaky threshold must be between 0 and 1, got %v", flaky.Threshold)
+		}
+		if flaky.MaxRequeues < 0 {
+			return fmt.Errorf("flaky max_requeues must not be negative, got %d", flaky.MaxRequeues)
+		}
+		e.config.Flaky = flaky
+	}
 
 	return nil
 }
@@ -374,6 +466,7 @@ func (e *Engineer) LoadConfig() error {
 type gateConfigRaw struct {
 	Cmd     string `json:"cmd"`
 	Timeout string `json:"timeout"`
+	Report  string `json:"report"`
 }
 
 // Config returns the current merge queue configuration.
@@ -389,6 +482,18 @@ type ProcessResult struct {
 	Conflict    bool
 	TestsFailed bool
 	SlotTimeout bool // Merge slot contention timeout (distinct from build/test failure)
+
+	// FlakyTests lists failing tests with a known-flaky history.
+	FlakyTests []string
+	// FlakyOnly is set when every failing test is known-flaky. Such
+	// failures are not the polecat's to fix.
+	FlakyOnly bool
+
+	// Outputs holds values reported by pre-merge actions, keyed
+	// "<action>.<key>".
+	Outputs map[string]string
+	// Warnings lists failed warning-severity pre-merge actions.
+	Warnings []string
 }
 
 // doMerge performs the actual git merge operation.
@@ -409,24 +514,42 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
-	// Step 2: Checkout the target branch
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking out target branch %s...\n", target)
-	if err := e.git.Checkout(target); err != nil {
-		return ProcessResult{
-			Success: false,
-			Error:   fmt.Sprintf("failed to checkout target %s: %v", target, err),
+	// Step 2: Checkout the target branch.
+	// Lane engineers cannot check out the target itself (it is checked out in
+	// the shared refinery worktree), so they stage on a private lane branch
+	// reset to origin's target and push it by refspec in Step 8.
+	stageBranch := target
+	if e.lane != "" {
+		stageBranch = laneBranch(e.lane, target)
+		if err := e.git.FetchBranch("origin", target); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: fetch origin/%s: %v (continuing)\n", target, err)
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Staging on lane branch %s (from origin/%s)...\n", stageBranch, target)
+		if err := e.git.CheckoutReset(stageBranch, "origin/"+target); err != nil {
+			return ProcessResult{
+				Success: false,
+				Error:   fmt.Sprintf("failed to stage lane branch %s: %v", stageBranch, err),
+			}
+		}
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Checking out target branch %s...\n", target)
+		if err := e.git.Checkout(target); err != nil {
+			return ProcessResult{
+				Success: false,
+				Error:   fmt.Sprintf("failed to checkout target %s: %v", target, err),
+			}
 		}
-	}
 
-	// Make sure target is up to date with origin
-	if err := e.git.Pull("origin", target); err != nil {
-		// Pull might fail if nothing to pull, that's ok
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
+		// Make sure target is up to date with origin
+		if err := e.git.Pull("origin", target); err != nil {
+			// Pull might fail if nothing to pull, that's ok
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
+		}
 	}
 
 	// Step 3: Check for merge conflicts (using local branch)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking for conflicts...\n")
-	conflicts, err := e.git.CheckConflicts(branch, target)
+	conflicts, err := e.git.CheckConflicts(branch, stageBranch)
 	if err != nil {
 		return ProcessResult{
 			Success:  false,
@@ -478,24 +601,8 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	shouldSkipGates := len(skipGates) > 0 && skipGates[0]
 	if shouldSkipGates {
 		_, _ = fmt.Fprintln(e.output, "[Engineer] Skipping gates (pre-verified by polecat)")
-	} else if len(e.config.Gates) > 0 {
-		// New gates system: run configured quality gates
-		gateResult := e.runGates(ctx)
-		if !gateResult.Success {
-			return gateResult
-		}
-	} else if e.config.RunTests && e.config.TestCommand != "" {
-		// Legacy test command path (backward compatible)
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
-		result := e.runTests(ctx)
-		if !result.Success {
-			return ProcessResult{
-				Success:     false,
-				TestsFailed: true,
-				Error:       result.Error,
-			}
-		}
-		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
+	} else if result := e.runBatchGates(ctx); !result.Success {
+		return result
 	}
 
 	// Step 5: Perform the actual merge using squash merge
@@ -540,6 +647,27 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
+	// Step 6.5: Run pre-merge actions against the squashed commit before it
+	// is published. Blocking failures undo the local squash commit.
+	preMerge := e.runMergeActions(ctx, PhasePreMerge, e.config.PreMerge, MergeActionEnv{
+		MRID:        strings.Join(gateMRs(ctx), ","),
+		Branch:      branch,
+		Target:      target,
+		SourceIssue: sourceIssue,
+		MergeCommit: mergeCommit,
+	})
+	if preMerge.Blocked != "" {
+		if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after pre-merge failure: %v\n", stageBranch, resetErr)
+		}
+		return ProcessResult{
+			Success:  false,
+			Error:    fmt.Sprintf("pre-merge action failed: %s", preMerge.Blocked),
+			Outputs:  preMerge.Outputs,
+			Warnings: preMerge.Warnings,
+		}
+	}
+
 	// Step 7: Acquire merge slot before push to serialize writes to the default branch.
 	// Only serialize pushes to the rig's default branch (typically main).
 	// Integration-branch and feature-branch pushes don't need serialization.
@@ -573,9 +701,35 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}()
 	}
 
+	// Step 7.5: Lanes run gates in parallel, so another lane may have landed
+	// since this one staged. While holding the slot, replay the squash commit
+	// onto the latest target so the push below is a fast-forward and history
+	// stays linear.
+	pushRef := target
+	if e.lane != "" {
+		rebased, result := e.refreshOntoTarget(ctx, mergeCommit, MergeActionEnv{
+			MRID:        strings.Join(gateMRs(ctx), ","),
+			Branch:      branch,
+			Target:      target,
+			SourceIssue: sourceIssue,
+		})
+		if !result.Success {
+			if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after lane refresh failure: %v\n", stageBranch, resetErr)
+			}
+			return result
+		}
+		if rebased != mergeCommit {
+			// Pre-merge actions re-ran against the rebased commit.
+			preMerge.Outputs, preMerge.Warnings = result.Outputs, result.Warnings
+		}
+		mergeCommit = rebased
+		pushRef = stageBranch + ":" + target
+	}
+
 	// Step 8: Push to origin
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing to origin/%s...\n", target)
-	if err := e.git.Push("origin", target, false); err != nil {
+	if err := e.git.Push("origin", pushRef, false); err != nil {
 		// Reset the checked-out target branch to undo the local squash commit.
 		// Without this, the next retry could see stale local state from the failed push.
 		if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
@@ -591,6 +745,8 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	return ProcessResult{
 		Success:     true,
 		MergeCommit: mergeCommit,
+		Outputs:     preMerge.Outputs,
+		Warnings:    preMerge.Warnings,
 	}
 }
 
@@ -603,11 +759,12 @@ func (e *Engineer) acquireMainPushSlot(ctx context.Context) (string, error) {
 	seq := atomic.AddUint64(&mergeSlotSeq, 1)
 	holder := fmt.Sprintf("%s/refinery/push/%d-%d", e.rig.Name, time.Now().UnixNano(), seq)
 
-	// The conflict-resolution path holds the slot with holder "rigName/refinery".
+	// The conflict-resolution path holds the slot with holder "rigName/refinery"
+	// (or "rigName/refinery/<lane>" for lane engineers).
 	// Both push and conflict-resolution run in the same single-threaded refinery
 	// agent, so if our own rig holds the slot for conflict resolution, we can
 	// safely proceed without re-acquiring — no concurrent push is possible.
-	selfConflictHolder := e.rig.Name + "/refinery"
+	selfConflictHolder := e.slotHolder()
 
 	backoff := e.mergeSlotRetryBackoff
 	if backoff == 0 {
@@ -672,6 +829,7 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 	}
 
 	var lastErr error
+	var attempts [][]TestOutcome
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Retrying tests (attempt %d/%d)...\n", attempt, maxRetries)
@@ -688,7 +846,10 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		cmd.Stderr = &stderr
 
 		err := cmd.Run()
+		outcomes, _ := ParseTestReport(stdout.Bytes())
+		attempts = append(attempts, outcomes)
 		if err == nil {
+			e.recordTestRun(ctx, "test", attempts)
 			return ProcessResult{Success: true}
 		}
 		lastErr = err
@@ -702,10 +863,16 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		}
 	}
 
+	summary := e.recordTestRun(ctx, "test", attempts)
+	if e.quarantined("test", summary) {
+		return ProcessResult{Success: true}
+	}
 	return ProcessResult{
 		Success:     false,
 		TestsFailed: true,
-		Error:       fmt.Sprintf("tests failed after %d attempts: %v", maxRetries, lastErr),
+		Error:       fmt.Sprintf("tests failed after %d attempts: %v%s", maxRetries, lastErr, formatKnownFlaky(summary.KnownFlaky)),
+		FlakyTests:  summary.KnownFlaky,
+		FlakyOnly:   summary.FlakyOnly(),
 	}
 }
 
@@ -730,6 +897,14 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 		defer cancel()
 	}
 
+	// Remove a stale report so a gate that dies early isn't judged by the
+	// previous run's results.
+	reportPath := ""
+	if gate.Report != "" {
+		reportPath = filepath.Join(e.workDir, gate.Report)
+		_ = os.Remove(reportPath)
+	}
+
 	cmd := exec.CommandContext(gateCtx, "sh", "-c", gate.Cmd) //nolint:gosec // G204: Gate commands are from trusted rig config
 	cmd.Dir = e.workDir
 	var stdout, stderr bytes.Buffer
@@ -739,11 +914,21 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 	err := cmd.Run()
 	elapsed := time.Since(start)
 
+	report := stdout.Bytes()
+	if reportPath != "" {
+		report, _ = os.ReadFile(reportPath) //nolint:gosec // G304: report path is from trusted rig config
+	}
+	outcomes, parseErr := ParseTestReport(report)
+	if parseErr != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: ignoring unreadable test report: %v\n", name, parseErr)
+	}
+
 	if err == nil {
 		return GateResult{
-			Name:    name,
-			Success: true,
-			Elapsed: elapsed,
+			Name:     name,
+			Success:  true,
+			Elapsed:  elapsed,
+			outcomes: outcomes,
 		}
 	}
 
@@ -760,13 +945,65 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 	}
 
 	return GateResult{
-		Name:    name,
-		Success: false,
-		Error:   errMsg,
-		Elapsed: elapsed,
+		Name:     name,
+		Success:  false,
+		Error:    errMsg,
+		Elapsed:  elapsed,
+		outcomes: outcomes,
 	}
 }
 
+// runGateWithRetries runs a gate, retrying failures up to RetryFlakyTests
+// attempts, and classifies its test outcomes across the attempts. A gate
+// whose only failures are known-flaky tests passes when quarantine is on.
+func (e *Engineer) runGateWithRetries(ctx context.Context, name string, gate *GateConfig) GateResult {
+	maxAttempts := e.config.RetryFlakyTests
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	start := time.Now()
+	var result GateResult
+	var attempts [][]TestOutcome
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: retrying (attempt %d/%d)...\n", name, attempt, maxAttempts)
+		}
+		result = e.runGate(ctx, name, gate)
+		attempts = append(attempts, result.outcomes)
+		if result.Success || ctx.Err() != nil {
+			break
+		}
+	}
+	result.Elapsed = time.Since(start)
+	result.Tests = e.recordTestRun(ctx, name, attempts)
+
+	if !result.Success && e.quarantined(name, result.Tests) {
+		result.Success = true
+		result.Error = ""
+	}
+	return result
+}
+
+// quarantined reports whether a failed run may pass because every failing
+// test is known-flaky and quarantine is enabled.
+func (e *Engineer) quarantined(gate string, summary TestRunSummary) bool {
+	if !summary.FlakyOnly() || !e.flakyConfig().Quarantine {
+		return false
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: only known-flaky tests failed (%s), quarantined\n",
+		gate, strings.Join(summary.KnownFlaky, ", "))
+	return true
+}
+
+// formatKnownFlaky renders known-flaky failures for an error message.
+func formatKnownFlaky(tests []string) string {
+	if len(tests) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (known-flaky: %s)", strings.Join(tests, ", "))
+}
+
 // runGates executes all configured quality gates and returns a ProcessResult.
 // Gates run in parallel if GatesParallel is true; otherwise sequentially.
 // Any single gate failure means overall failure.
@@ -795,14 +1032,14 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 			go func(idx int, gateName string) {
 				defer wg.Done()
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", gateName, gates[gateName].Cmd)
-				results[idx] = e.runGate(ctx, gateName, gates[gateName])
+				results[idx] = e.runGateWithRetries(ctx, gateName, gates[gateName])
 			}(i, name)
 		}
 		wg.Wait()
 	} else {
 		for _, name := range names {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: starting (%s)\n", name, gates[name].Cmd)
-			result := e.runGate(ctx, name, gates[name])
+			result := e.runGateWithRetries(ctx, name, gates[name])
 			results = append(results, result)
 			if !result.Success {
 				// Sequential mode: stop on first failure
@@ -812,13 +1049,16 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 	}
 
 	// Report results
-	var failures []string
+	var failures, flakyTests []string
+	flakyOnly := true
 	for _, r := range results {
 		if r.Success {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: passed (%v)\n", r.Name, r.Elapsed.Truncate(time.Millisecond))
 		} else {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: FAILED (%v) - %s\n", r.Name, r.Elapsed.Truncate(time.Millisecond), r.Error)
-			failures = append(failures, fmt.Sprintf("%s: %s", r.Name, r.Error))
+			failures = append(failures, fmt.Sprintf("%s: %s%s", r.Name, r.Error, formatKnownFlaky(r.Tests.KnownFlaky)))
+			flakyTests = append(flakyTests, r.Tests.KnownFlaky...)
+			flakyOnly = flakyOnly && r.Tests.FlakyOnly()
 		}
 	}
 
@@ -827,6 +1067,8 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 			Success:     false,
 			TestsFailed: true,
 			Error:       fmt.Sprintf("quality gates failed: %s", strings.Join(failures, "; ")),
+			FlakyTests:  flakyTests,
+			FlakyOnly:   flakyOnly,
 		}
 	}
 
@@ -895,14 +1137,14 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	}
 
 	// Use the shared merge logic
-	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue, skipGates)
+	return e.doMerge(withGateMRs(ctx, mr.ID), mr.Branch, mr.Target, mr.SourceIssue, skipGates)
 }
 
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
 func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 	// Release merge slot if this was a conflict resolution
 	// The slot is held while conflict resolution is in progress
-	holder := e.rig.Name + "/refinery"
+	holder := e.slotHolder()
 	if err := e.mergeSlotRelease(holder); err != nil {
 		// Best-effort: slot release failures are always non-fatal.
 		// Slot may not have been held (optional acquisition) or may have expired.
@@ -911,6 +1153,21 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Released merge slot\n")
 	}
 
+	// Run post-merge actions (preview deploys, release tags). The merge has
+	// landed, so failures are recorded on the MR bead but never undo it.
+	postMerge := e.runMergeActions(context.Background(), PhasePostMerge, e.config.PostMerge, MergeActionEnv{
+		MRID:        mr.ID,
+		Branch:      mr.Branch,
+		Target:      mr.Target,
+		SourceIssue: mr.SourceIssue,
+		Worker:      mr.Worker,
+		MergeCommit: result.MergeCommit,
+	})
+	if postMerge.Blocked != "" {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: post-merge actions stopped: %s\n", postMerge.Blocked)
+	}
+	outputs := mergeOutputs(result.Outputs, postMerge.Outputs)
+
 	// Update and close the MR bead
 	if mr.ID != "" {
 		// Fetch the MR bead to update its fields
@@ -925,6 +1182,7 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 			}
 			mrFields.MergeCommit = result.MergeCommit
 			mrFields.CloseReason = "merged"
+			mrFields.Outputs = mergeOutputs(mrFields.Outputs, outputs)
 			newDesc := beads.SetMRFields(mrBead, mrFields)
 			if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s with merge commit: %v\n", mr.ID, err)
@@ -993,27 +1251,29 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		return
 	}
 
+	// Failures caused entirely by known-flaky tests are not the polecat's to
+	// fix. Leave the MR queued for retry instead of requesting rework, up to
+	// the flaky requeue cap; past it the failure escalates and is handled as
+	// an ordinary test failure below.
+	failType := failureType(result)
+	if result.FlakyOnly {
+		count, requeue := e.requeueFlaky(mr.ID, result)
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Known-flaky failure: %s - %s\n", mr.ID, strings.Join(result.FlakyTests, ", "))
+		if requeue {
+			e.nudgeWorker(mr, protocol.FailureTypeFlaky, fmt.Sprintf(
+				"MERGE_FAILED: branch=%s issue=%s type=%s tests=%s — known-flaky tests only, no rework needed (requeue %d/%d)",
+				mr.Branch, mr.SourceIssue, protocol.FailureTypeFlaky, strings.Join(result.FlakyTests, ", "), count, e.flakyConfig().MaxRequeues))
+			_, _ = fmt.Fprintln(e.output, "[Engineer] MR remains in queue for automatic retry (no rework requested)")
+			return
+		}
+	}
+
 	// Nudge polecat directly about the merge failure.
 	// Previously sent MERGE_FAILED mail to witness (which relayed to polecat),
 	// but that created permanent Dolt commits for routine protocol signals.
 	// The witness discovers merge failures from MR bead status during patrol.
-	failureType := "build"
-	if result.Conflict {
-		failureType = "conflict"
-	} else if result.TestsFailed {
-		failureType = "tests"
-	}
-	polecatName := strings.TrimPrefix(mr.Worker, "polecats/")
-	nudgeTarget := fmt.Sprintf("%s/%s", e.rig.Name, polecatName)
-	nudgeMsg := fmt.Sprintf("MERGE_FAILED: branch=%s issue=%s type=%s error=%s — fix and resubmit with 'gt done'",
-		mr.Branch, mr.SourceIssue, failureType, result.Error)
-	nudgeCmd := exec.Command("gt", "nudge", nudgeTarget, nudgeMsg)
-	nudgeCmd.Dir = e.workDir
-	if err := nudgeCmd.Run(); err != nil {
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to nudge %s about merge failure: %v\n", polecatName, err)
-	} else {
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Nudged %s about merge failure (%s)\n", polecatName, failureType)
-	}
+	e.nudgeWorker(mr, failType, fmt.Sprintf("MERGE_FAILED: branch=%s issue=%s type=%s error=%s — fix and resubmit with 'gt done'",
+		mr.Branch, mr.SourceIssue, failType, result.Error))
 
 	// If this was a conflict, create a conflict-resolution task for dispatch
 	// and block the MR until the task is resolved (non-blocking delegation)
@@ -1041,6 +1301,31 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	}
 }
 
+// failureType names a failed ProcessResult's failure for MERGE_FAILED.
+func failureType(result ProcessResult) string {
+	switch {
+	case result.Conflict:
+		return "conflict"
+	case result.TestsFailed:
+		return "tests"
+	default:
+		return "build"
+	}
+}
+
+// nudgeWorker nudges the polecat that submitted mr about a merge failure.
+func (e *Engineer) nudgeWorker(mr *MRInfo, kind, msg string) {
+	polecatName := strings.TrimPrefix(mr.Worker, "polecats/")
+	nudgeTarget := fmt.Sprintf("%s/%s", e.rig.Name, polecatName)
+	nudgeCmd := exec.Command("gt", "nudge", nudgeTarget, msg)
+	nudgeCmd.Dir = e.workDir
+	if err := nudgeCmd.Run(); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to nudge %s about merge failure: %v\n", polecatName, err)
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Nudged %s about merge failure (%s)\n", polecatName, kind)
+	}
+}
+
 // createConflictResolutionTaskForMR creates a dispatchable task for resolving merge conflicts.
 // This task will be picked up by bd ready and can be slung to a fresh polecat (spawned on demand).
 // Returns the created task's ID for blocking the MR until resolution.
@@ -1068,7 +1353,7 @@ func (e *Engineer) createConflictResolutionTaskForMR(mr *MRInfo, _ ProcessResult
 		// Continue anyway - slot is optional for now
 	} else {
 		// Try to acquire the merge slot
-		holder := e.rig.Name + "/refinery"
+		holder := e.slotHolder()
 		status, err := e.mergeSlotAcquire(holder, false)
 		if err != nil {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not acquire merge slot: %v\n", err)
@@ -1245,6 +1530,7 @@ func (e *Engineer) firstOpenBlocker(issue *beads.Issue) string {
 // ListReadyMRs returns MRs that are ready for processing:
 // - Not claimed by another worker (checked via assignee field)
 // - Not blocked by an open task (checked via firstOpenBlocker)
+// - Routed to this engineer's lane (lane engineers only)
 // Sorted by priority (highest first).
 //
 // Uses bd list instead of bd ready because MRs are ephemeral beads and
@@ -1289,9 +1575,15 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 			continue // Skip issues without MR fields
 		}
 
+		// Each lane only sees its own claim set.
+		lane := e.routeLane(issue, fields)
+		if !e.inLane(lane) {
+			continue
+		}
+
 		// Skip if already assigned, unless claim is stale (allows re-claim after crash).
-		// NOTE: Only one refinery runs per rig (enforced by ErrAlreadyRunning in
-		// manager.go), so concurrent re-claim race conditions are not a concern.
+		// NOTE: Only one refinery runs per rig or lane (enforced by ErrAlreadyRunning
+		// in manager.go), so concurrent re-claim race conditions are not a concern.
 		if issue.Assignee != "" {
 			stale, parseErr := isClaimStale(issue.UpdatedAt, e.config.StaleClaimTimeout)
 			if parseErr != nil {
@@ -1305,7 +1597,9 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 				issue.ID, issue.Assignee, issue.UpdatedAt)
 		}
 
-		mrs = append(mrs, issueToMRInfo(issue, fields))
+		mr := issueToMRInfo(issue, fields)
+		mr.Lane = lane
+		mrs = append(mrs, mr)
 	}
 
 	return mrs, nil
@@ -1380,6 +1674,10 @@ func (e *Engineer) ListAllOpenMRs() ([]*MRInfo, error) {
 		}
 
 		mr := issueToMRInfo(issue, fields)
+		mr.Lane = e.routeLane(issue, fields)
+		if !e.inLane(mr.Lane) {
+			continue
+		}
 
 		// Check branch existence (local + remote tracking refs)
 		mr.BranchExistsLocal, _ = e.git.BranchExists(fields.Branch)
@@ -1394,6 +1692,8 @@ func (e *Engineer) ListAllOpenMRs() ([]*MRInfo, error) {
 
 // ListQueueAnomalies finds stale claims and orphaned branches in open MRs.
 // This gives Witness/Refinery patrols deterministic signals for deadlock risk.
+// When lanes are configured, each anomaly is tagged with its MR's lane and
+// lane engineers only report anomalies in their own lane.
 func (e *Engineer) ListQueueAnomalies(now time.Time) ([]*MRAnomaly, error) {
 	issues, err := e.beads.List(beads.ListOptions{
 		Status:   "open",
@@ -1404,17 +1704,45 @@ func (e *Engineer) ListQueueAnomalies(now time.Time) ([]*MRAnomaly, error) {
 		return nil, fmt.Errorf("querying beads for merge-requests: %w", err)
 	}
 
-	return detectQueueAnomalies(issues, now, e.config.StaleClaimWarningAfter, func(branch string) (bool, bool, error) {
-		localExists, err := e.git.BranchExists(branch)
-		if err != nil {
-			return false, false, err
+	if len(e.config.Lanes) > 0 {
+		lanes := make(map[string]string, len(issues))
+		var visible []*beads.Issue
+		for _, issue := range issues {
+			if issue == nil {
+				continue
+			}
+			fields := beads.ParseMRFields(issue)
+			if fields == nil {
+				continue
+			}
+			lane := e.routeLane(issue, fields)
+			if !e.inLane(lane) {
+				continue
+			}
+			lanes[issue.ID] = lane
+			visible = append(visible, issue)
 		}
-		remoteTrackingExists, err := e.git.RemoteTrackingBranchExists("origin", branch)
-		if err != nil {
-			return false, false, err
+		anomalies := detectQueueAnomalies(visible, now, e.config.StaleClaimWarningAfter, e.branchExists)
+		for _, a := range anomalies {
+			a.Lane = lanes[a.ID]
 		}
-		return localExists, remoteTrackingExists, nil
-	}), nil
+		return anomalies, nil
+	}
+
+	return detectQueueAnomalies(issues, now, e.config.StaleClaimWarningAfter, e.branchExists), nil
+}
+
+// branchExists reports whether branch exists locally and in origin/* tracking refs.
+func (e *Engineer) branchExists(branch string) (bool, bool, error) {
+	localExists, err := e.git.BranchExists(branch)
+	if err != nil {
+		return false, false, err
+	}
+	remoteTrackingExists, err := e.git.RemoteTrackingBranchExists("origin", branch)
+	if err != nil {
+		return false, false, err
+	}
+	return localExists, remoteTrackingExists, nil
 }
 
 func detectQueueAnomalies(
@@ -1471,7 +1799,25 @@ func detectQueueAnomalies(
 // ClaimMR claims an MR for processing by setting the assignee field.
 // This replaces mrqueue.Claim() for beads-based MRs.
 // The workerID is typically the refinery's identifier (e.g., "gastown/refinery").
+// Lane engineers refuse MRs routed to other lanes (ErrWrongLane).
 func (e *Engineer) ClaimMR(mrID, workerID string) error {
+	if e.lane != "" && len(e.config.Lanes) > 0 {
+		issue, err := e.beads.Show(mrID)
+		if err != nil {
+			return fmt.Errorf("fetching MR %s: %w", mrID, err)
+		}
+		fields := beads.ParseMRFields(issue)
+		if fields == nil {
+			return fmt.Errorf("%s is not a merge request", mrID)
+		}
+		lane, err := e.laneForIssue(issue, fields)
+		if err != nil {
+			return err
+		}
+		if lane != e.lane {
+			return fmt.Errorf("%s routes to lane %q, not %q: %w", mrID, lane, e.lane, ErrWrongLane)
+		}
+	}
 	return e.beads.Update(mrID, beads.UpdateOptions{
 		Assignee: &workerID,
 	})
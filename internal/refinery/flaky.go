@@ -0,0 +1,514 @@
+package refinery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/protocol"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// maxFlakyMRs caps the failing MR IDs remembered per test.
+const maxFlakyMRs = 5
+
+// FlakyConfig controls flaky test detection for quality gates.
+//
+// Gates that emit JUnit XML or `go test -json` output have their per-test
+// outcomes recorded in the rig's flaky history. A test that fails and then
+// passes when the gate is retried (see RetryFlakyTests) counts as a flake.
+type FlakyConfig struct {
+	// MinFlakes is how many pass-after-retry flakes a test needs before it
+	// is treated as known-flaky.
+	MinFlakes int `json:"min_flakes"`
+
+	// Threshold is the minimum flake rate (flakes / recorded runs) for a
+	// test to be treated as known-flaky.
+	Threshold float64 `json:"threshold"`
+
+	// Quarantine lets a gate pass when every failing test is known-flaky.
+	// When false, such failures still block the merge but the MR stays in
+	// the queue for retry instead of going back to the polecat for rework.
+	Quarantine bool `json:"quarantine"`
+
+	// MaxRequeues caps how many times an MR is left in the queue after a
+	// failure caused only by known-flaky tests. The next such failure is
+	// escalated to the mayor and handled as an ordinary test failure.
+	MaxRequeues int `json:"max_requeues"`
+}
+
+// DefaultFlakyConfig returns the default flaky detection configuration.
+func DefaultFlakyConfig() *FlakyConfig {
+	return &FlakyConfig{
+		MinFlakes:   2,
+		Threshold:   0.1,
+		MaxRequeues: 3,
+	}
+}
+
+// TestOutcome is the result of one test case in a single gate attempt.
+type TestOutcome struct {
+	Name   string
+	Passed bool
+}
+
+// ParseTestReport extracts per-test outcomes from gate output. JUnit XML
+// and `go test -json` streams are detected automatically; anything else
+// yields no outcomes.
+func ParseTestReport(data []byte) ([]TestOutcome, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+	if trimmed[0] == '<' {
+		return parseJUnitXML(trimmed)
+	}
+	return parseGoTestJSON(trimmed), nil
+}
+
+// goTestEvent is the subset of a `go test -json` event we need.
+type goTestEvent struct {
+	Action  string `json:"Action"`
+	Package string `json:"Package"`
+	Test    string `json:"Test"`
+}
+
+// parseGoTestJSON reads `go test -json` events. Lines that are not JSON
+// (e.g., build output interleaved by a wrapper script) are skipped.
+// A package that fails without any failing test (a build failure or a
+// panic in TestMain) is reported as a failed outcome named after the
+// package, so it is never mistaken for a flaky-only failure.
+func parseGoTestJSON(data []byte) []TestOutcome {
+	final := make(map[string]bool)
+	var order []string
+	pkgFailed := make(map[string]bool)
+	var pkgOrder []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var ev goTestEvent
+		if err := json.Unmarshal(line, &ev); err != nil {
+			continue
+		}
+		if ev.Action != "pass" && ev.Action != "fail" {
+			continue
+		}
+		if ev.Test == "" {
+			if ev.Action == "fail" && !pkgFailed[ev.Package] {
+				pkgFailed[ev.Package] = true
+				pkgOrder = append(pkgOrder, ev.Package)
+			}
+			continue
+		}
+		name := ev.Package + "." + ev.Test
+		if _, ok := final[name]; !ok {
+			order = append(order, name)
+		}
+		final[name] = ev.Action == "pass"
+	}
+	outcomes := make([]TestOutcome, 0, len(order))
+	testFailedIn := make(map[string]bool)
+	for _, name := range order {
+		outcomes = append(outcomes, TestOutcome{Name: name, Passed: final[name]})
+		if !final[name] {
+			testFailedIn[name[:strings.LastIndex(name, ".")]] = true
+		}
+	}
+	for _, pkg := range pkgOrder {
+		if !testFailedIn[pkg] {
+			outcomes = append(outcomes, TestOutcome{Name: pkg, Passed: false})
+		}
+	}
+	return outcomes
+}
+
+// junitSuite matches both <testsuites> and <testsuite> elements.
+type junitSuite struct {
+	Name   string          `xml:"name,attr"`
+	Suites []junitSuite    `xml:"testsuite"`
+	Cases  []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string    `xml:"name,attr"`
+	ClassName string    `xml:"classname,attr"`
+	Failure   *struct{} `xml:"failure"`
+	Error     *struct{} `xml:"error"`
+	Skipped   *struct{} `xml:"skipped"`
+}
+
+// parseJUnitXML reads a JUnit XML report. Skipped cases are ignored.
+func parseJUnitXML(data []byte) ([]TestOutcome, error) {
+	var root junitSuite
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing JUnit XML: %w", err)
+	}
+	var outcomes []TestOutcome
+	var walk func(s junitSuite)
+	walk = func(s junitSuite) {
+		for _, tc := range s.Cases {
+			if tc.Skipped != nil {
+				continue
+			}
+			prefix := tc.ClassName
+			if prefix == "" {
+				prefix = s.Name
+			}
+			name := tc.Name
+			if prefix != "" {
+				name = prefix + "." + tc.Name
+			}
+			outcomes = append(outcomes, TestOutcome{Name: name, Passed: tc.Failure == nil && tc.Error == nil})
+		}
+		for _, child := range s.Suites {
+			walk(child)
+		}
+	}
+	walk(root)
+	return outcomes, nil
+}
+
+// TestRunSummary classifies the tests of one gate run across its attempts.
+type TestRunSummary struct {
+	// Flaked lists tests that failed in an earlier attempt and passed later.
+	Flaked []string
+
+	// Failed lists tests that failed in the final attempt.
+	Failed []string
+
+	// KnownFlaky is the subset of Failed with a flaky history.
+	KnownFlaky []string
+}
+
+// FlakyOnly reports whether the run failed only on known-flaky tests.
+func (s TestRunSummary) FlakyOnly() bool {
+	return len(s.Failed) > 0 && len(s.KnownFlaky) == len(s.Failed)
+}
+
+// summarizeAttempts compares the attempts of one gate run. attempts holds
+// the parsed outcomes of each attempt in order; the last one is final.
+func summarizeAttempts(attempts [][]TestOutcome) TestRunSummary {
+	var s TestRunSummary
+	if len(attempts) == 0 {
+		return s
+	}
+	failedEarlier := make(map[string]bool)
+	for _, attempt := range attempts[:len(attempts)-1] {
+		for _, o := range attempt {
+			if !o.Passed {
+				failedEarlier[o.Name] = true
+			}
+		}
+	}
+	for _, o := range attempts[len(attempts)-1] {
+		switch {
+		case !o.Passed:
+			s.Failed = append(s.Failed, o.Name)
+		case failedEarlier[o.Name]:
+			s.Flaked = append(s.Flaked, o.Name)
+		}
+	}
+	return s
+}
+
+// TestHistory is the recorded flakiness of a single test in a single gate.
+// Only tests that have failed at least once are tracked, so Runs counts
+// gate runs since the test first failed.
+type TestHistory struct {
+	Gate        string    `json:"gate"`
+	Test        string    `json:"test"`
+	Runs        int       `json:"runs"`
+	Failures    int       `json:"failures"`
+	Flakes      int       `json:"flakes"`
+	LastFlakeAt time.Time `json:"last_flake_at,omitempty"`
+	LastFailAt  time.Time `json:"last_fail_at,omitempty"`
+
+	// MRs lists the most recent MRs whose gate runs saw this test fail.
+	MRs []string `json:"mrs,omitempty"`
+}
+
+// FlakeRate is the fraction of recorded runs that passed only after a retry.
+func (h *TestHistory) FlakeRate() float64 {
+	if h.Runs == 0 {
+		return 0
+	}
+	return float64(h.Flakes) / float64(h.Runs)
+}
+
+// IsFlaky reports whether the test meets the known-flaky criteria in cfg.
+func (h *TestHistory) IsFlaky(cfg *FlakyConfig) bool {
+	if cfg == nil {
+		cfg = DefaultFlakyConfig()
+	}
+	return h.Flakes >= cfg.MinFlakes && h.FlakeRate() >= cfg.Threshold
+}
+
+func (h *TestHistory) addMRs(ids []string) {
+	for _, id := range ids {
+		for i, existing := range h.MRs {
+			if existing == id {
+				h.MRs = append(h.MRs[:i], h.MRs[i+1:]...)
+				break
+			}
+		}
+		h.MRs = append(h.MRs, id)
+	}
+	if len(h.MRs) > maxFlakyMRs {
+		h.MRs = h.MRs[len(h.MRs)-maxFlakyMRs:]
+	}
+}
+
+// FlakyHistory is the per-rig record of test flakiness across gate runs.
+// Stored at <rig>/.runtime/refinery-flaky.json.
+type FlakyHistory struct {
+	Tests map[string]*TestHistory `json:"tests"`
+}
+
+// FlakyHistoryPath returns the path of the rig's flaky test history.
+func FlakyHistoryPath(r *rig.Rig) string {
+	return filepath.Join(r.Path, ".runtime", "refinery-flaky.json")
+}
+
+// LoadFlakyHistory reads the flaky history at path. A missing file yields
+// an empty history.
+func LoadFlakyHistory(path string) (*FlakyHistory, error) {
+	h := &FlakyHistory{Tests: make(map[string]*TestHistory)}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("parsing flaky history: %w", err)
+	}
+	if h.Tests == nil {
+		h.Tests = make(map[string]*TestHistory)
+	}
+	return h, nil
+}
+
+func flakyKey(gate, test string) string {
+	return gate + "\x00" + test
+}
+
+// Lookup returns the history for a test, or nil if it has never failed.
+func (h *FlakyHistory) Lookup(gate, test string) *TestHistory {
+	return h.Tests[flakyKey(gate, test)]
+}
+
+// Record folds one gate run into the history. outcomes are the final
+// attempt's results; summary classifies them across attempts.
+func (h *FlakyHistory) Record(gate string, outcomes []TestOutcome, summary TestRunSummary, mrIDs []string, now time.Time) {
+	failed := make(map[string]bool, len(summary.Failed))
+	for _, name := range summary.Failed {
+		failed[name] = true
+	}
+	flaked := make(map[string]bool, len(summary.Flaked))
+	for _, name := range summary.Flaked {
+		flaked[name] = true
+	}
+
+	for _, o := range outcomes {
+		key := flakyKey(gate, o.Name)
+		th := h.Tests[key]
+		if th == nil {
+			if !failed[o.Name] && !flaked[o.Name] {
+				continue // never failed: not worth tracking
+			}
+			th = &TestHistory{Gate: gate, Test: o.Name}
+			h.Tests[key] = th
+		}
+		th.Runs++
+		switch {
+		case failed[o.Name]:
+			th.Failures++
+			th.LastFailAt = now
+			th.addMRs(mrIDs)
+		case flaked[o.Name]:
+			th.Flakes++
+			th.LastFlakeAt = now
+			th.addMRs(mrIDs)
+		}
+	}
+}
+
+// Worst returns up to limit flaky tests ordered by flake count, then rate.
+// Tests that never flaked are omitted. limit <= 0 means no limit.
+func (h *FlakyHistory) Worst(limit int) []*TestHistory {
+	var out []*TestHistory
+	for _, th := range h.Tests {
+		if th.Flakes > 0 {
+			out = append(out, th)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Flakes != out[j].Flakes {
+			return out[i].Flakes > out[j].Flakes
+		}
+		if ri, rj := out[i].FlakeRate(), out[j].FlakeRate(); ri != rj {
+			return ri > rj
+		}
+		if out[i].Gate != out[j].Gate {
+			return out[i].Gate < out[j].Gate
+		}
+		return out[i].Test < out[j].Test
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// updateFlakyHistory applies fn to the history at path under a file lock,
+// so lanes and speculative pipelines recording concurrently don't lose runs.
+func updateFlakyHistory(path string, fn func(*FlakyHistory)) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking flaky history: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	h, err := LoadFlakyHistory(path)
+	if err != nil {
+		return err
+	}
+	fn(h)
+	return util.AtomicWriteJSON(path, h)
+}
+
+// gateMRsKey carries the IDs of the MRs under test through gate runs.
+type gateMRsKey struct{}
+
+// withGateMRs annotates ctx with the MRs whose changes the gates verify,
+// so flaky history can point back at the MRs that hit each failure.
+func withGateMRs(ctx context.Context, ids ...string) context.Context {
+	return context.WithValue(ctx, gateMRsKey{}, ids)
+}
+
+func gateMRs(ctx context.Context) []string {
+	ids, _ := ctx.Value(gateMRsKey{}).([]string)
+	return ids
+}
+
+// flakyConfig returns the flaky detection config, falling back to defaults.
+func (e *Engineer) flakyConfig() *FlakyConfig {
+	if e.config.Flaky != nil {
+		return e.config.Flaky
+	}
+	return DefaultFlakyConfig()
+}
+
+// recordTestRun summarizes a gate run's attempts, records the result in
+// the rig's flaky history and marks which final failures are known-flaky.
+// Cancelled runs are summarized but not recorded.
+func (e *Engineer) recordTestRun(ctx context.Context, gate string, attempts [][]TestOutcome) TestRunSummary {
+	summary := summarizeAttempts(attempts)
+	if len(attempts) == 0 || len(attempts[len(attempts)-1]) == 0 {
+		return summary
+	}
+	for _, name := range summary.Flaked {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Gate %q: %s passed on retry (flaky)\n", gate, name)
+	}
+	if e.rig == nil || ctx.Err() != nil {
+		return summary
+	}
+
+	cfg := e.flakyConfig()
+	final := attempts[len(attempts)-1]
+	err := updateFlakyHistory(FlakyHistoryPath(e.rig), func(h *FlakyHistory) {
+		h.Record(gate, final, summary, gateMRs(ctx), time.Now())
+		for _, name := range summary.Failed {
+			if th := h.Lookup(gate, name); th != nil && th.IsFlaky(cfg) {
+				summary.KnownFlaky = append(summary.KnownFlaky, name)
+			}
+		}
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record flaky test history: %v\n", err)
+	}
+	return summary
+}
+
+// flakyRequeueAction decides what to do with an MR that has now failed
+// count times on known-flaky tests alone: keep it queued while count is
+// within limit, escalate on the first failure past it, and after that
+// treat the failure as an ordinary test failure.
+func flakyRequeueAction(count, limit int) (requeue, escalate bool) {
+	if count <= limit {
+		return true, false
+	}
+	return false, count == limit+1
+}
+
+// requeueFlaky records a known-flaky-only failure on the MR bead and reports
+// whether the MR should stay queued for retry. Past Flaky.MaxRequeues the
+// refinery escalates to the mayor and returns false, so the caller handles
+// the failure as an ordinary test failure: a flake that blocks the same MR
+// that often needs a human, not another retry. Returns the updated count.
+func (e *Engineer) requeueFlaky(mrID string, result ProcessResult) (int, bool) {
+	limit := e.flakyConfig().MaxRequeues
+	issue, err := e.beads.Show(mrID)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not count flaky requeues for %s: %v\n", mrID, err)
+		return 0, true
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+	fields.FlakyRetries++
+	desc := beads.SetMRFields(issue, fields)
+	if err := e.beads.Update(mrID, beads.UpdateOptions{Description: &desc}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not record flaky requeue for %s: %v\n", mrID, err)
+	}
+
+	requeue, escalate := flakyRequeueAction(fields.FlakyRetries, limit)
+	if escalate {
+		msg := fmt.Sprintf("refinery %s: MR %s failed %d times on known-flaky tests only (%s); no longer retrying",
+			e.rig.Name, mrID, fields.FlakyRetries, strings.Join(result.FlakyTests, ", "))
+		cmd := exec.Command("gt", "escalate", "-s", "HIGH", msg)
+		cmd.Dir = e.workDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: escalation failed: %v (%s)\n", err, strings.TrimSpace(string(output)))
+		} else {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Escalated %s after %d known-flaky failures\n", mrID, fields.FlakyRetries)
+		}
+	}
+	return fields.FlakyRetries, requeue
+}
+
+// GateFailureType classifies a failed gate run for the MERGE_FAILED
+// notification sent by the refinery patrol (gt mq gate). Failures caused
+// only by known-flaky tests are requeued under the Flaky.MaxRequeues cap
+// and reported as protocol.FailureTypeFlaky; past the cap they escalate and
+// are reported as ordinary test failures.
+func (e *Engineer) GateFailureType(mrID string, result ProcessResult) string {
+	if result.FlakyOnly {
+		if _, requeue := e.requeueFlaky(mrID, result); requeue {
+			return protocol.FailureTypeFlaky
+		}
+		return "tests"
+	}
+	return failureType(result)
+}
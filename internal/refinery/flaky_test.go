@@ -0,0 +1,280 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestParseTestReport_GoTestJSON(t *testing.T) {
+	data := `# building...
+{"Action":"run","Package":"example/a","Test":"TestOK"}
+{"Action":"pass","Package":"example/a","Test":"TestOK"}
+{"Action":"fail","Package":"example/a","Test":"TestRace/sub"}
+{"Action":"fail","Package":"example/a","Test":"TestRace"}
+{"Action":"fail","Package":"example/a"}
+{"Action":"fail","Package":"example/b"}
+`
+	outcomes, err := ParseTestReport([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseTestReport: %v", err)
+	}
+	want := []TestOutcome{
+		{Name: "example/a.TestOK", Passed: true},
+		{Name: "example/a.TestRace/sub", Passed: false},
+		{Name: "example/a.TestRace", Passed: false},
+		// example/b failed without a failing test (build failure).
+		{Name: "example/b", Passed: false},
+	}
+	if fmt.Sprint(outcomes) != fmt.Sprint(want) {
+		t.Errorf("outcomes = %v, want %v", outcomes, want)
+	}
+}
+
+func TestParseTestReport_JUnit(t *testing.T) {
+	data := `<?xml version="1.0"?>
+<testsuites>
+  <testsuite name="suite">
+    <testcase classname="pkg.Foo" name="passes"/>
+    <testcase classname="pkg.Foo" name="fails"><failure message="boom"/></testcase>
+    <testcase name="errors"><error/></testcase>
+    <testcase classname="pkg.Foo" name="skipped"><skipped/></testcase>
+  </testsuite>
+</testsuites>`
+	outcomes, err := ParseTestReport([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseTestReport: %v", err)
+	}
+	want := []TestOutcome{
+		{Name: "pkg.Foo.passes", Passed: true},
+		{Name: "pkg.Foo.fails", Passed: false},
+		{Name: "suite.errors", Passed: false},
+	}
+	if fmt.Sprint(outcomes) != fmt.Sprint(want) {
+		t.Errorf("outcomes = %v, want %v", outcomes, want)
+	}
+
+	if _, err := ParseTestReport([]byte("<testsuite><testcase")); err == nil {
+		t.Error("expected error for malformed XML")
+	}
+	if outcomes, _ := ParseTestReport([]byte("ok  \texample/a\t0.1s\n")); len(outcomes) != 0 {
+		t.Errorf("plain output should yield no outcomes, got %v", outcomes)
+	}
+}
+
+func TestSummarizeAttempts(t *testing.T) {
+	attempts := [][]TestOutcome{
+		{{"A", false}, {"B", false}, {"C", true}},
+		{{"A", true}, {"B", false}, {"C", true}},
+	}
+	s := summarizeAttempts(attempts)
+	if strings.Join(s.Flaked, ",") != "A" {
+		t.Errorf("Flaked = %v, want [A]", s.Flaked)
+	}
+	if strings.Join(s.Failed, ",") != "B" {
+		t.Errorf("Failed = %v, want [B]", s.Failed)
+	}
+	if s.FlakyOnly() {
+		t.Error("FlakyOnly should be false without known-flaky failures")
+	}
+}
+
+func TestFlakyHistory_RecordAndWorst(t *testing.T) {
+	h := &FlakyHistory{Tests: make(map[string]*TestHistory)}
+	now := time.Now()
+	outcomes := []TestOutcome{{"A", true}, {"B", true}, {"C", true}}
+
+	// A flakes twice, B fails once, C never fails.
+	h.Record("test", outcomes, TestRunSummary{Flaked: []string{"A"}}, []string{"gt-1"}, now)
+	h.Record("test", outcomes, TestRunSummary{Flaked: []string{"A"}, Failed: []string{"B"}}, []string{"gt-2"}, now)
+	h.Record("test", outcomes, TestRunSummary{}, []string{"gt-3"}, now)
+
+	if h.Lookup("test", "C") != nil {
+		t.Error("never-failing tests should not be tracked")
+	}
+	a := h.Lookup("test", "A")
+	if a == nil || a.Runs != 3 || a.Flakes != 2 {
+		t.Fatalf("A history = %+v, want 3 runs / 2 flakes", a)
+	}
+	if strings.Join(a.MRs, ",") != "gt-1,gt-2" {
+		t.Errorf("A MRs = %v, want [gt-1 gt-2]", a.MRs)
+	}
+	if !a.IsFlaky(DefaultFlakyConfig()) {
+		t.Error("A should be known-flaky")
+	}
+	if b := h.Lookup("test", "B"); b == nil || b.Failures != 1 || b.IsFlaky(nil) {
+		t.Errorf("B history = %+v, want 1 failure and not flaky", b)
+	}
+
+	worst := h.Worst(10)
+	if len(worst) != 1 || worst[0].Test != "A" {
+		t.Errorf("Worst = %v, want only A", worst)
+	}
+}
+
+// flakyGateCmd fails TestRace on its first run and passes it afterwards.
+func flakyGateCmd(marker string) string {
+	return fmt.Sprintf(`if [ -f %[1]s ]; then echo '{"Action":"pass","Package":"p","Test":"TestRace"}'; `+
+		`else touch %[1]s; echo '{"Action":"fail","Package":"p","Test":"TestRace"}'; exit 1; fi`, marker)
+}
+
+func newFlakyTestEngineer(t *testing.T, gateCmd string) *Engineer {
+	t.Helper()
+	workDir := t.TempDir()
+	e := newTestEngineer(t, workDir, nil)
+	e.rig = &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e.config.Gates = map[string]*GateConfig{"test": {Cmd: gateCmd}}
+	e.config.RetryFlakyTests = 2
+	return e
+}
+
+func TestRunGates_RecordsPassAfterRetry(t *testing.T) {
+	e := newFlakyTestEngineer(t, flakyGateCmd(filepath.Join(t.TempDir(), "ran")))
+
+	result := e.runGates(withGateMRs(context.Background(), "gt-mr1"))
+	if !result.Success {
+		t.Fatalf("gate should pass on retry: %s", result.Error)
+	}
+
+	h, err := LoadFlakyHistory(FlakyHistoryPath(e.rig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	th := h.Lookup("test", "p.TestRace")
+	if th == nil || th.Flakes != 1 || th.Runs != 1 {
+		t.Fatalf("history = %+v, want 1 flake in 1 run", th)
+	}
+	if strings.Join(th.MRs, ",") != "gt-mr1" {
+		t.Errorf("MRs = %v, want [gt-mr1]", th.MRs)
+	}
+}
+
+func TestRunGates_KnownFlakyFailure(t *testing.T) {
+	failRace := `echo '{"Action":"fail","Package":"p","Test":"TestRace"}'; exit 1`
+	failBoth := `echo '{"Action":"fail","Package":"p","Test":"TestRace"}'; echo '{"Action":"fail","Package":"p","Test":"TestReal"}'; exit 1`
+
+	seed := func(t *testing.T, e *Engineer) {
+		t.Helper()
+		h := FlakyHistory{Tests: map[string]*TestHistory{
+			flakyKey("test", "p.TestRace"): {Gate: "test", Test: "p.TestRace", Runs: 4, Flakes: 3},
+		}}
+		data, _ := json.Marshal(h)
+		path := FlakyHistoryPath(e.rig)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("flaky only", func(t *testing.T) {
+		e := newFlakyTestEngineer(t, failRace)
+		seed(t, e)
+		result := e.runGates(context.Background())
+		if result.Success || !result.FlakyOnly {
+			t.Fatalf("result = %+v, want flaky-only failure", result)
+		}
+		if strings.Join(result.FlakyTests, ",") != "p.TestRace" {
+			t.Errorf("FlakyTests = %v, want [p.TestRace]", result.FlakyTests)
+		}
+	})
+
+	t.Run("real failure alongside flaky", func(t *testing.T) {
+		e := newFlakyTestEngineer(t, failBoth)
+		seed(t, e)
+		result := e.runGates(context.Background())
+		if result.Success || result.FlakyOnly {
+			t.Fatalf("result = %+v, want a real failure", result)
+		}
+		if strings.Join(result.FlakyTests, ",") != "p.TestRace" {
+			t.Errorf("FlakyTests = %v, want [p.TestRace]", result.FlakyTests)
+		}
+	})
+
+	t.Run("quarantine", func(t *testing.T) {
+		e := newFlakyTestEngineer(t, failRace)
+		e.config.Flaky = &FlakyConfig{MinFlakes: 2, Threshold: 0.1, Quarantine: true}
+		seed(t, e)
+		if result := e.runGates(context.Background()); !result.Success {
+			t.Fatalf("quarantined gate should pass: %s", result.Error)
+		}
+	})
+}
+
+func TestRunGate_ReadsReportFile(t *testing.T) {
+	e := newFlakyTestEngineer(t, `echo '<testsuite><testcase classname="x" name="y"><failure/></testcase></testsuite>' > report.xml; exit 1`)
+	e.config.Gates["test"].Report = "report.xml"
+	e.config.RetryFlakyTests = 1
+
+	result := e.runGates(context.Background())
+	if result.Success {
+		t.Fatal("expected failure")
+	}
+	h, err := LoadFlakyHistory(FlakyHistoryPath(e.rig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th := h.Lookup("test", "x.y"); th == nil || th.Failures != 1 {
+		t.Errorf("history = %+v, want 1 failure for x.y", th)
+	}
+}
+
+func TestEngineer_LoadConfig_Flaky(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(flaky map[string]interface{}) {
+		data, _ := json.Marshal(map[string]interface{}{
+			"merge_queue": map[string]interface{}{
+				"flaky": flaky,
+				"gates": map[string]interface{}{"test": map[string]interface{}{"cmd": "go test -json ./...", "report": "out.xml"}},
+			},
+		})
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(map[string]interface{}{"quarantine": true})
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if f := e.Config().Flaky; !f.Quarantine || f.MinFlakes != 2 {
+		t.Errorf("Flaky = %+v, want quarantine with default min_flakes", f)
+	}
+	if got := e.Config().Gates["test"].Report; got != "out.xml" {
+		t.Errorf("gate Report = %q, want out.xml", got)
+	}
+
+	write(map[string]interface{}{"threshold": 1.5})
+	if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+		t.Error("expected error for threshold > 1")
+	}
+}
+
+func TestFlakyRequeueAction(t *testing.T) {
+	tests := []struct {
+		count, limit      int
+		requeue, escalate bool
+	}{
+		{count: 1, limit: 3, requeue: true},
+		{count: 3, limit: 3, requeue: true},
+		{count: 4, limit: 3, escalate: true},
+		{count: 5, limit: 3},
+		{count: 1, limit: 0, escalate: true},
+	}
+	for _, tt := range tests {
+		requeue, escalate := flakyRequeueAction(tt.count, tt.limit)
+		if requeue != tt.requeue || escalate != tt.escalate {
+			t.Errorf("flakyRequeueAction(%d, %d) = (%v, %v), want (%v, %v)",
+				tt.count, tt.limit, requeue, escalate, tt.requeue, tt.escalate)
+		}
+	}
+}
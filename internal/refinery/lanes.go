@@ -0,0 +1,317 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// DefaultLane is the catch-all merge lane. MRs that carry no lane label and
+// whose changes do not fall entirely inside a single configured lane land here.
+const DefaultLane = "default"
+
+// LaneLabelPrefix marks an explicit lane assignment on an MR bead
+// (e.g., "gt:lane:frontend"). Explicit labels win over path-based routing.
+const LaneLabelPrefix = "gt:lane:"
+
+// ErrWrongLane is returned when a lane-scoped refinery tries to claim an MR
+// that belongs to a different lane.
+var ErrWrongLane = errors.New("MR belongs to a different merge lane")
+
+// LaneConfig defines a single merge lane.
+//
+// Lanes let a rig run several refinery instances in parallel, each with its
+// own worktree and claim set. Gates run concurrently across lanes; pushes to
+// the target branch are still serialized through the merge slot, with each
+// lane rebasing its squash commit onto the latest target before pushing so
+// history stays linear.
+type LaneConfig struct {
+	// Name identifies the lane. Used in session names, worktree paths and
+	// claim assignees, so it is restricted to [a-z0-9-].
+	Name string `json:"name"`
+
+	// Paths lists repository path prefixes owned by this lane
+	// (e.g., "services/api"). An MR is routed here when every file it
+	// touches falls under one of these prefixes.
+	Paths []string `json:"paths,omitempty"`
+
+	// Labels lists MR bead labels that route an MR to this lane regardless
+	// of the paths it touches.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// validateLanes checks lane names for uniqueness and safe characters.
+func validateLanes(lanes []*LaneConfig) error {
+	seen := make(map[string]bool, len(lanes))
+	for i, lane := range lanes {
+		if lane == nil || lane.Name == "" {
+			return fmt.Errorf("lanes[%d]: name is required", i)
+		}
+		if lane.Name == DefaultLane {
+			return fmt.Errorf("lanes[%d]: %q is reserved for the catch-all lane", i, DefaultLane)
+		}
+		for _, r := range lane.Name {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return fmt.Errorf("lanes[%d]: invalid name %q (use lowercase letters, digits and '-')", i, lane.Name)
+			}
+		}
+		if seen[lane.Name] {
+			return fmt.Errorf("lanes[%d]: duplicate lane %q", i, lane.Name)
+		}
+		seen[lane.Name] = true
+		if len(lane.Paths) == 0 && len(lane.Labels) == 0 {
+			return fmt.Errorf("lane %q: needs at least one path or label", lane.Name)
+		}
+	}
+	return nil
+}
+
+// HasLane reports whether name is a lane MRs can be routed to under this config.
+func (c *MergeQueueConfig) HasLane(name string) bool {
+	if name == DefaultLane {
+		return len(c.Lanes) > 0
+	}
+	for _, lane := range c.Lanes {
+		if lane.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// LaneNames returns all lanes in routing order, ending with DefaultLane.
+// Returns nil when lanes are not configured.
+func (c *MergeQueueConfig) LaneNames() []string {
+	if len(c.Lanes) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(c.Lanes)+1)
+	for _, lane := range c.Lanes {
+		names = append(names, lane.Name)
+	}
+	return append(names, DefaultLane)
+}
+
+// classifyLane picks the lane for an MR from its labels and touched paths.
+//
+// Routing order:
+//  1. An explicit "gt:lane:<name>" label naming a configured lane
+//  2. The first lane listing one of the MR's labels
+//  3. The single lane whose path prefixes cover every touched file
+//  4. DefaultLane (no lanes match, or changes span several lanes)
+func classifyLane(lanes []*LaneConfig, labels, touched []string) string {
+	for _, label := range labels {
+		if name, ok := strings.CutPrefix(label, LaneLabelPrefix); ok {
+			for _, lane := range lanes {
+				if lane.Name == name {
+					return name
+				}
+			}
+		}
+	}
+
+	for _, lane := range lanes {
+		for _, want := range lane.Labels {
+			for _, label := range labels {
+				if label == want {
+					return lane.Name
+				}
+			}
+		}
+	}
+
+	if len(touched) == 0 {
+		return DefaultLane
+	}
+	match := ""
+	for _, file := range touched {
+		owner := ""
+		for _, lane := range lanes {
+			if laneOwnsPath(lane, file) {
+				owner = lane.Name
+				break
+			}
+		}
+		if owner == "" || (match != "" && owner != match) {
+			return DefaultLane
+		}
+		match = owner
+	}
+	return match
+}
+
+// laneOwnsPath reports whether file falls under one of the lane's prefixes.
+// Prefixes match on path-segment boundaries: "api" owns "api/x.go" but not
+// "apiary/x.go".
+func laneOwnsPath(lane *LaneConfig, file string) bool {
+	for _, prefix := range lane.Paths {
+		prefix = strings.Trim(filepath.ToSlash(prefix), "/")
+		if prefix == "" {
+			continue
+		}
+		if file == prefix || strings.HasPrefix(file, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// LaneWorkerID returns the claim assignee used by a lane's refinery instance.
+func LaneWorkerID(lane string) string {
+	return "refinery-" + lane
+}
+
+// LaneWorkDir returns the git worktree used by a lane's refinery instance.
+// Lanes need their own worktree because a branch can only be checked out in
+// one worktree at a time.
+func LaneWorkDir(r *rig.Rig, lane string) string {
+	return filepath.Join(r.Path, "refinery", "lanes", lane)
+}
+
+// laneBranch returns the local branch a lane stages merges on. The shared
+// refinery/rig worktree keeps the real target branch checked out, so lanes
+// work on a private copy and push it to the target by refspec.
+func laneBranch(lane, target string) string {
+	return "refinery/" + lane + "/" + target
+}
+
+// EnsureLaneWorktree creates the lane's worktree from the main refinery clone
+// if it does not exist yet. Returns the worktree path.
+func EnsureLaneWorktree(r *rig.Rig, lane string) (string, error) {
+	dir := LaneWorkDir(r, lane)
+	if _, err := os.Stat(dir); err == nil {
+		return dir, nil
+	}
+
+	srcDir := filepath.Join(r.Path, "refinery", "rig")
+	if _, err := os.Stat(srcDir); os.IsNotExist(err) {
+		srcDir = filepath.Join(r.Path, "mayor", "rig")
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return "", fmt.Errorf("creating lanes directory: %w", err)
+	}
+	g := git.NewGit(srcDir)
+	if err := g.WorktreeAddDetached(dir, "HEAD"); err != nil {
+		return "", fmt.Errorf("creating worktree for lane %s: %w", lane, err)
+	}
+	return dir, nil
+}
+
+// NewLaneEngineer creates an Engineer scoped to a single merge lane.
+// It operates in the lane's worktree and only sees MRs routed to its lane.
+// The lane worktree must already exist (see EnsureLaneWorktree).
+func NewLaneEngineer(r *rig.Rig, lane string) *Engineer {
+	e := NewEngineer(r)
+	e.lane = lane
+	dir := LaneWorkDir(r, lane)
+	if _, err := os.Stat(dir); err == nil {
+		e.git = git.NewGit(dir)
+		e.workDir = dir
+	}
+	return e
+}
+
+// Lane returns the merge lane this engineer serves, or "" when unsharded.
+func (e *Engineer) Lane() string {
+	return e.lane
+}
+
+// slotHolder is the merge slot holder ID for this engineer's conflict path.
+// Each lane gets its own so lanes never mistake another lane's hold for
+// their own.
+func (e *Engineer) slotHolder() string {
+	if e.lane != "" {
+		return e.rig.Name + "/refinery/" + e.lane
+	}
+	return e.rig.Name + "/refinery"
+}
+
+// laneForIssue routes an MR bead to a lane. Returns "" when lanes are not
+// configured. Path routing diffs the MR branch against its target; if the
+// diff cannot be computed the MR is routed by its labels alone and the diff
+// error is returned alongside that lane.
+func (e *Engineer) laneForIssue(issue *beads.Issue, fields *beads.MRFields) (string, error) {
+	if len(e.config.Lanes) == 0 {
+		return "", nil
+	}
+	var touched []string
+	var diffErr error
+	if fields.Branch != "" && fields.Target != "" {
+		files, err := e.git.ChangedFiles("origin/"+fields.Target, fields.Branch)
+		if err != nil {
+			diffErr = fmt.Errorf("routing %s: diffing %s against origin/%s: %w", issue.ID, fields.Branch, fields.Target, err)
+		} else {
+			touched = files
+		}
+	}
+	return classifyLane(e.config.Lanes, issue.Labels, touched), diffErr
+}
+
+// routeLane is laneForIssue for the queue listings, which keep going with
+// the label-only lane and log why path routing was skipped.
+func (e *Engineer) routeLane(issue *beads.Issue, fields *beads.MRFields) string {
+	lane, err := e.laneForIssue(issue, fields)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v (using lane %q)\n", err, lane)
+	}
+	return lane
+}
+
+// LaneForIssue returns the merge lane an MR bead routes to, or "" when lanes
+// are not configured. A non-nil error means path routing failed and the lane
+// was chosen from labels only.
+func (e *Engineer) LaneForIssue(issue *beads.Issue) (string, error) {
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		fields = &beads.MRFields{} // no branch to diff: route by labels
+	}
+	return e.laneForIssue(issue, fields)
+}
+
+// inLane reports whether an MR routed to lane is visible to this engineer.
+// Unsharded engineers see every lane.
+func (e *Engineer) inLane(lane string) bool {
+	return e.lane == "" || lane == e.lane
+}
+
+// refreshOntoTarget replays the commit checked out in the work tree onto
+// the latest origin target (env.Target) and returns the new HEAD. Callers
+// hold the merge slot, so the push that follows is a fast-forward even when
+// other lanes landed commits while this one was running gates. If the rebase
+// produced a commit the gates have never seen (tested is the one they
+// passed), the gates and pre-merge actions are re-run against it rather than
+// publishing an unverified combination; the result then carries the
+// pre-merge outputs of the re-run.
+func (e *Engineer) refreshOntoTarget(ctx context.Context, tested string, env MergeActionEnv) (string, ProcessResult) {
+	target := env.Target
+	if err := e.git.FetchBranch("origin", target); err != nil {
+		return "", ProcessResult{Error: fmt.Sprintf("fetching origin/%s: %v", target, err)}
+	}
+	if err := e.git.Rebase("origin/" + target); err != nil {
+		_ = e.git.AbortRebase()
+		return "", ProcessResult{
+			Conflict: true,
+			Error:    fmt.Sprintf("rebase onto origin/%s failed: %v", target, err),
+		}
+	}
+	head, err := e.git.Rev("HEAD")
+	if err != nil {
+		return "", ProcessResult{Error: fmt.Sprintf("failed to get rebased HEAD: %v", err)}
+	}
+	if head == tested {
+		return head, ProcessResult{Success: true}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Rebased onto new origin/%s, re-running gates and pre-merge actions...\n", target)
+	result := e.verifyCommit(ctx, env)
+	if !result.Success {
+		return "", result
+	}
+	return head, result
+}
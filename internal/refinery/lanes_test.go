@@ -0,0 +1,323 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func testLanes() []*LaneConfig {
+	return []*LaneConfig{
+		{Name: "api", Paths: []string{"services/api", "proto/"}},
+		{Name: "web", Paths: []string{"web"}, Labels: []string{"area:frontend"}},
+	}
+}
+
+func TestClassifyLane(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  []string
+		touched []string
+		want    string
+	}{
+		{"all files in one lane", nil, []string{"services/api/main.go", "proto/api.proto"}, "api"},
+		{"single file", nil, []string{"web/index.html"}, "web"},
+		{"spans two lanes", nil, []string{"services/api/main.go", "web/index.html"}, DefaultLane},
+		{"file outside every lane", nil, []string{"services/api/main.go", "go.mod"}, DefaultLane},
+		{"prefix matches on segment boundary", nil, []string{"website/index.html"}, DefaultLane},
+		{"no touched files", nil, nil, DefaultLane},
+		{"explicit lane label wins", []string{"gt:lane:web"}, []string{"services/api/main.go"}, "web"},
+		{"explicit label for unknown lane ignored", []string{"gt:lane:docs"}, []string{"services/api/main.go"}, "api"},
+		{"lane label routing", []string{"area:frontend"}, []string{"go.mod"}, "web"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyLane(testLanes(), tt.labels, tt.touched); got != tt.want {
+				t.Errorf("classifyLane(%v, %v) = %q, want %q", tt.labels, tt.touched, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateLanes(t *testing.T) {
+	tests := []struct {
+		name    string
+		lanes   []*LaneConfig
+		wantErr string
+	}{
+		{"valid", testLanes(), ""},
+		{"missing name", []*LaneConfig{{Paths: []string{"a"}}}, "name is required"},
+		{"reserved name", []*LaneConfig{{Name: DefaultLane, Paths: []string{"a"}}}, "reserved"},
+		{"unsafe name", []*LaneConfig{{Name: "API/v2", Paths: []string{"a"}}}, "invalid name"},
+		{"duplicate", []*LaneConfig{{Name: "a", Paths: []string{"a"}}, {Name: "a", Paths: []string{"b"}}}, "duplicate"},
+		{"no routing", []*LaneConfig{{Name: "a"}}, "at least one path or label"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLanes(tt.lanes)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEngineer_LoadConfig_WithLanes(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := map[string]interface{}{
+		"merge_queue": map[string]interface{}{
+			"lanes": []map[string]interface{}{
+				{"name": "api", "paths": []string{"services/api"}},
+				{"name": "web", "labels": []string{"area:frontend"}},
+			},
+		},
+	}
+	data, _ := json.Marshal(config)
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if got := strings.Join(e.Config().LaneNames(), ","); got != "api,web,default" {
+		t.Errorf("LaneNames() = %s, want api,web,default", got)
+	}
+	if !e.Config().HasLane(DefaultLane) || e.Config().HasLane("docs") {
+		t.Error("HasLane reported wrong membership")
+	}
+
+	// Invalid lanes are rejected.
+	config["merge_queue"] = map[string]interface{}{
+		"lanes": []map[string]interface{}{{"name": "api"}},
+	}
+	data, _ = json.Marshal(config)
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+		t.Error("expected error for lane without paths or labels")
+	}
+}
+
+func TestLaneManager_SessionName(t *testing.T) {
+	setupTestRegistry(t)
+	mgr := NewLaneManager(&rig.Rig{Name: "testrig", Path: t.TempDir()}, "api")
+
+	if got, want := mgr.SessionName(), "xut-refinery-api"; got != want {
+		t.Errorf("SessionName() = %s, want %s", got, want)
+	}
+	if got := session.RefinerySessionName("xut"); got == mgr.SessionName() {
+		t.Error("lane session name must differ from the rig refinery session")
+	}
+}
+
+func TestAcquireMainPushSlot_OtherLaneHolderNotBypassed(t *testing.T) {
+	// A conflict-resolution hold by another lane must not be mistaken for our own.
+	e := &Engineer{
+		rig:    &rig.Rig{Name: "testrig"},
+		lane:   "api",
+		output: io.Discard,
+		mergeSlotEnsureExists: func() (string, error) {
+			return "merge-slot", nil
+		},
+		mergeSlotAcquire: func(_ string, _ bool) (*beads.MergeSlotStatus, error) {
+			return &beads.MergeSlotStatus{ID: "merge-slot", Available: false, Holder: "testrig/refinery/web"}, nil
+		},
+		mergeSlotRelease: func(_ string) error { return nil },
+	}
+
+	_, err := e.acquireMainPushSlot(context.Background())
+	if !errors.Is(err, errMergeSlotTimeout) {
+		t.Fatalf("expected slot timeout, got %v", err)
+	}
+}
+
+// setupConcurrentLaneMerge builds an origin with a polecat/api branch and an
+// "api" lane engineer whose merge-slot acquire lands a web lane commit on
+// origin/main first, simulating a lane that finished while this one ran gates.
+func setupConcurrentLaneMerge(t *testing.T) (e *Engineer, refineryDir, otherDir string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	bareDir := filepath.Join(tmpDir, "origin.git")
+	rigPath := filepath.Join(tmpDir, "testrig")
+	refineryDir = filepath.Join(rigPath, "refinery", "rig")
+	otherDir = filepath.Join(tmpDir, "other")
+
+	run(t, tmpDir, "git", "init", "--bare", "--initial-branch=main", bareDir)
+	run(t, tmpDir, "git", "clone", bareDir, refineryDir)
+	run(t, refineryDir, "git", "config", "user.email", "test@test.com")
+	run(t, refineryDir, "git", "config", "user.name", "Test")
+	run(t, refineryDir, "git", "checkout", "-b", "main")
+	writeFile(t, refineryDir, "README.md", "# Test\n")
+	run(t, refineryDir, "git", "add", ".")
+	run(t, refineryDir, "git", "commit", "-m", "initial commit")
+	run(t, refineryDir, "git", "push", "-u", "origin", "main")
+
+	if err := os.MkdirAll(filepath.Join(refineryDir, "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	createFeatureBranch(t, refineryDir, "polecat/api", "api/handler.go", "package api\n")
+
+	r := &rig.Rig{Name: "testrig", Path: rigPath}
+	if _, err := EnsureLaneWorktree(r, "api"); err != nil {
+		t.Fatalf("EnsureLaneWorktree: %v", err)
+	}
+
+	// Another lane lands on main while this lane holds its staged commit.
+	run(t, tmpDir, "git", "clone", bareDir, otherDir)
+	run(t, otherDir, "git", "config", "user.email", "test@test.com")
+	run(t, otherDir, "git", "config", "user.name", "Test")
+
+	e = NewLaneEngineer(r, "api")
+	e.config.Lanes = []*LaneConfig{{Name: "api", Paths: []string{"api"}}}
+	e.output = io.Discard
+	landed := false
+	e.mergeSlotEnsureExists = func() (string, error) { return "test-slot", nil }
+	e.mergeSlotAcquire = func(holder string, _ bool) (*beads.MergeSlotStatus, error) {
+		if !landed {
+			landed = true
+			writeFile(t, otherDir, "web.html", "<html></html>\n")
+			run(t, otherDir, "git", "add", ".")
+			run(t, otherDir, "git", "commit", "-m", "feat: web lane change")
+			run(t, otherDir, "git", "push", "origin", "main")
+		}
+		return &beads.MergeSlotStatus{Available: true, Holder: holder}, nil
+	}
+	e.mergeSlotRelease = func(string) error { return nil }
+
+	return e, refineryDir, otherDir
+}
+
+// TestLaneMerge_RebasesOntoConcurrentLanding verifies the final serialized
+// fast-forward: when another lane lands while this lane is running gates,
+// the lane rebases its squash commit onto the new target under the merge
+// slot and the target stays linear.
+func TestLaneMerge_RebasesOntoConcurrentLanding(t *testing.T) {
+	e, refineryDir, otherDir := setupConcurrentLaneMerge(t)
+
+	result := e.doMerge(context.Background(), "polecat/api", "main", "")
+	if !result.Success {
+		t.Fatalf("doMerge failed: %s", result.Error)
+	}
+
+	run(t, otherDir, "git", "pull", "origin", "main")
+	log := run(t, otherDir, "git", "log", "--format=%s", "main")
+	lines := strings.Split(log, "\n")
+	if len(lines) != 3 || lines[0] != "feat: add api/handler.go" || lines[1] != "feat: web lane change" {
+		t.Fatalf("unexpected history on main:\n%s", log)
+	}
+	if merges := run(t, otherDir, "git", "rev-list", "--merges", "main"); merges != "" {
+		t.Errorf("expected linear history, found merge commits: %s", merges)
+	}
+	if head := run(t, otherDir, "git", "rev-parse", "HEAD"); head != result.MergeCommit {
+		t.Errorf("MergeCommit = %s, want pushed HEAD %s", result.MergeCommit, head)
+	}
+
+	// The shared refinery worktree keeps main checked out untouched.
+	if branch := run(t, refineryDir, "git", "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+		t.Errorf("refinery worktree branch = %s, want main", branch)
+	}
+}
+
+// TestLaneMerge_RerunsGatesAfterRebase verifies that a rebased lane commit is
+// gated again before it is pushed: the gate passes on the staged commit but
+// fails once the concurrently landed web change is underneath it.
+func TestLaneMerge_RerunsGatesAfterRebase(t *testing.T) {
+	e, _, otherDir := setupConcurrentLaneMerge(t)
+	e.config.Gates = map[string]*GateConfig{
+		"no-web": {Cmd: "test ! -e web.html"},
+	}
+
+	result := e.doMerge(context.Background(), "polecat/api", "main", "")
+	if result.Success {
+		t.Fatal("doMerge succeeded, want gate failure on the rebased commit")
+	}
+
+	run(t, otherDir, "git", "fetch", "origin")
+	if log := run(t, otherDir, "git", "log", "--format=%s", "origin/main"); strings.Contains(log, "api/handler.go") {
+		t.Errorf("ungated rebased commit was pushed:\n%s", log)
+	}
+}
+
+// TestLaneMerge_RerunsPreMergeAfterRebase verifies that pre-merge actions,
+// like the gates, see the rebased lane commit before it is pushed.
+func TestLaneMerge_RerunsPreMergeAfterRebase(t *testing.T) {
+	e, _, otherDir := setupConcurrentLaneMerge(t)
+	e.config.PreMerge = map[string]*MergeActionConfig{
+		"no-web": {Cmd: "test ! -e web.html"},
+	}
+
+	result := e.doMerge(context.Background(), "polecat/api", "main", "")
+	if result.Success {
+		t.Fatal("doMerge succeeded, want pre-merge failure on the rebased commit")
+	}
+	if !strings.Contains(result.Error, "pre-merge") {
+		t.Errorf("Error = %q, want a pre-merge failure", result.Error)
+	}
+
+	run(t, otherDir, "git", "fetch", "origin")
+	if log := run(t, otherDir, "git", "log", "--format=%s", "origin/main"); strings.Contains(log, "api/handler.go") {
+		t.Errorf("rebased commit was pushed without passing pre-merge:\n%s", log)
+	}
+}
+
+// TestLand_PushesByRefspecFromLaneWorktree verifies the patrol's land path:
+// the lane worktree keeps its work branch checked out, Land rebases it onto
+// the concurrently landed web change and pushes it by refspec.
+func TestLand_PushesByRefspecFromLaneWorktree(t *testing.T) {
+	e, _, otherDir := setupConcurrentLaneMerge(t)
+	laneDir := e.git.WorkDir()
+	run(t, laneDir, "git", "checkout", "-b", "temp-api", "polecat/api")
+
+	result := e.Land(context.Background(), MergeActionEnv{MRID: "gt-mr-api", Target: "main"})
+	if !result.Success {
+		t.Fatalf("Land failed: %s", result.Error)
+	}
+
+	run(t, otherDir, "git", "pull", "origin", "main")
+	log := run(t, otherDir, "git", "log", "--format=%s", "main")
+	lines := strings.Split(log, "\n")
+	if len(lines) != 3 || lines[0] != "feat: add api/handler.go" || lines[1] != "feat: web lane change" {
+		t.Fatalf("unexpected history on main:\n%s", log)
+	}
+	if head := run(t, otherDir, "git", "rev-parse", "HEAD"); head != result.MergeCommit {
+		t.Errorf("MergeCommit = %s, want pushed HEAD %s", result.MergeCommit, head)
+	}
+	if branch := run(t, laneDir, "git", "rev-parse", "--abbrev-ref", "HEAD"); branch != "temp-api" {
+		t.Errorf("lane worktree branch = %s, want temp-api", branch)
+	}
+}
+
+// TestLaneForIssue_ReportsDiffError verifies that a failed path diff is
+// surfaced instead of silently routing the MR, while labels still apply.
+func TestLaneForIssue_ReportsDiffError(t *testing.T) {
+	e := NewEngineer(&rig.Rig{Name: "testrig", Path: t.TempDir()})
+	e.config.Lanes = []*LaneConfig{{Name: "api", Paths: []string{"api"}}}
+	issue := &beads.Issue{ID: "gt-mr-1", Labels: []string{LaneLabelPrefix + "api"}}
+	fields := &beads.MRFields{Branch: "polecat/missing", Target: "main"}
+
+	lane, err := e.laneForIssue(issue, fields)
+	if err == nil {
+		t.Fatal("laneForIssue returned no error for an undiffable branch")
+	}
+	if lane != "api" {
+		t.Errorf("lane = %q, want label lane %q", lane, "api")
+	}
+}
@@ -36,6 +36,7 @@ type Manager struct {
 	rig     *rig.Rig
 	workDir string
 	output  io.Writer // Output destination for user-facing messages
+	lane    string    // Merge lane served by this instance ("" = whole queue)
 }
 
 type scoredIssue struct {
@@ -52,6 +53,15 @@ func NewManager(r *rig.Rig) *Manager {
 	}
 }
 
+// NewLaneManager creates a refinery manager for one merge lane of a rig.
+// Each lane runs in its own tmux session and worktree, so lanes can be
+// started and stopped independently.
+func NewLaneManager(r *rig.Rig, lane string) *Manager {
+	m := NewManager(r)
+	m.lane = lane
+	return m
+}
+
 // SetOutput sets the output writer for user-facing messages.
 // This is useful for testing or redirecting output.
 func (m *Manager) SetOutput(w io.Writer) {
@@ -59,8 +69,13 @@ func (m *Manager) SetOutput(w io.Writer) {
 }
 
 // SessionName returns the tmux session name for this refinery.
+// Lane instances append the lane name (e.g., "gt-refinery-frontend").
 func (m *Manager) SessionName() string {
-	return session.RefinerySessionName(session.PrefixFor(m.rig.Name))
+	name := session.RefinerySessionName(session.PrefixFor(m.rig.Name))
+	if m.lane != "" {
+		name += "-" + m.lane
+	}
+	return name
 }
 
 // IsRunning checks if the refinery session is active and healthy.
@@ -69,9 +84,8 @@ func (m *Manager) SessionName() string {
 // ZFC: tmux session existence is the source of truth for session state,
 // but agent liveness determines if the session is actually functional.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
 	sessionName := m.SessionName()
-	status := t.CheckSessionHealth(sessionName, 0)
+	status := session.Health(session.BackendFor(filepath.Dir(m.rig.Path), sessionName), sessionName, 0)
 	return status == tmux.SessionHealthy, nil
 }
 
@@ -81,15 +95,15 @@ func (m *Manager) IsRunning() (bool, error) {
 // Returns the detailed ZombieStatus for callers that need to distinguish
 // between different failure modes.
 func (m *Manager) IsHealthy(maxInactivity time.Duration) tmux.ZombieStatus {
-	t := tmux.NewTmux()
-	return t.CheckSessionHealth(m.SessionName(), maxInactivity)
+	sessionName := m.SessionName()
+	return session.Health(session.BackendFor(filepath.Dir(m.rig.Path), sessionName), sessionName, maxInactivity)
 }
 
 // Status returns information about the refinery session.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
+	t := session.BackendFor(filepath.Dir(m.rig.Path), sessionID)
 
 	running, err := t.HasSession(sessionID)
 	if err != nil {
@@ -99,7 +113,7 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	return session.Info(t, sessionID)
 }
 
 // Start starts the refinery.
@@ -108,8 +122,8 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 // The agentOverride parameter allows specifying an agent alias to use instead of the town default.
 // ZFC-compliant: no state file, tmux session is source of truth.
 func (m *Manager) Start(foreground bool, agentOverride string) error {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
+	t := session.BackendFor(filepath.Dir(m.rig.Path), sessionID)
 
 	if foreground {
 		// Foreground mode is deprecated - the Refinery agent handles merge processing
@@ -125,7 +139,7 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 		}
 		// Zombie - tmux alive but agent dead. Kill and recreate.
 		_, _ = fmt.Fprintln(m.output, "⚠ Detected zombie session (tmux alive, agent dead). Recreating...")
-		if err := t.KillSession(sessionID); err != nil {
+		if err := session.Kill(t, sessionID); err != nil {
 			return fmt.Errorf("killing zombie session: %w", err)
 		}
 	}
@@ -142,6 +156,13 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 		// Using rig.Path directly would find town's .git with rig-named remotes instead of "origin".
 		refineryRigDir = filepath.Join(m.rig.Path, "mayor", "rig")
 	}
+	if m.lane != "" {
+		laneDir, err := EnsureLaneWorktree(m.rig, m.lane)
+		if err != nil {
+			return err
+		}
+		refineryRigDir = laneDir
+	}
 
 	// Ensure runtime settings exist in the shared refinery parent directory.
 	// Settings are passed to Claude Code via --settings flag.
@@ -180,6 +201,7 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
+	t = session.NewBackend(townRoot)
 	if err := t.NewSessionWithCommand(sessionID, refineryRigDir, command); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
@@ -197,6 +219,11 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 
 	// Add refinery-specific flag
 	envVars["GT_REFINERY"] = "1"
+	if m.lane != "" {
+		// Scope claims and queue listings to this lane (see gt refinery ready/claim).
+		envVars["GT_REFINERY_LANE"] = m.lane
+		envVars["GT_REFINERY_WORKER"] = LaneWorkerID(m.lane)
+	}
 
 	// Set all env vars in tmux session (for debugging) and they'll also be exported to Claude
 	for k, v := range envVars {
@@ -206,15 +233,15 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 
 	// Apply theme (non-fatal: theming failure doesn't affect operation)
 	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "refinery", "refinery")
+	_ = session.ApplyTheme(t, sessionID, theme, m.rig.Name, "refinery", "refinery")
 
 	// Accept startup dialogs (workspace trust + bypass permissions) if they appear.
 	// Must be before WaitForRuntimeReady to avoid race where dialog blocks prompt detection.
-	_ = t.AcceptStartupDialogs(sessionID)
+	_ = session.AcceptStartupDialogs(t, sessionID)
 
 	// Wait for Claude to start and show its prompt - fatal if Claude fails to launch
 	// WaitForRuntimeReady waits for the runtime to be ready
-	if err := t.WaitForRuntimeReady(sessionID, runtimeConfig, constants.ClaudeStartTimeout); err != nil {
+	if err := session.WaitForRuntimeReady(t, sessionID, runtimeConfig, constants.ClaudeStartTimeout); err != nil {
 		// Kill the zombie session before returning error
 		_ = t.KillSessionWithProcesses(sessionID)
 		return fmt.Errorf("waiting for refinery to start: %w", err)
@@ -239,8 +266,8 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 // Stop stops the refinery.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
+	t := session.BackendFor(filepath.Dir(m.rig.Path), sessionID)
 
 	// Check if tmux session exists
 	running, _ := t.HasSession(sessionID)
@@ -249,7 +276,7 @@ func (m *Manager) Stop() error {
 	}
 
 	// Kill the tmux session
-	return t.KillSession(sessionID)
+	return session.Kill(t, sessionID)
 }
 
 // Queue returns the current merge queue.
@@ -0,0 +1,97 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Gate verifies the commit checked out in the refinery work tree before it
+// is landed: it runs the merge queue gates (or the legacy test command),
+// then the pre-merge actions against HEAD. It is the formula-driven
+// refinery's equivalent of doMerge's gate and pre-merge steps (gt mq gate).
+func (e *Engineer) Gate(ctx context.Context, env MergeActionEnv) ProcessResult {
+	return e.verifyCommit(withGateMRs(ctx, env.MRID), env)
+}
+
+// verifyCommit runs the gates, then the pre-merge actions against HEAD.
+func (e *Engineer) verifyCommit(ctx context.Context, env MergeActionEnv) ProcessResult {
+	if result := e.runBatchGates(ctx); !result.Success {
+		return result
+	}
+	return e.runPreMerge(ctx, env)
+}
+
+// runPreMerge runs the pre-merge actions against HEAD, with env.MergeCommit
+// set to it. Blocking failures fail the result.
+func (e *Engineer) runPreMerge(ctx context.Context, env MergeActionEnv) ProcessResult {
+	head, err := e.git.Rev("HEAD")
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("failed to get HEAD: %v", err)}
+	}
+	env.MergeCommit = head
+	preMerge := e.runMergeActions(ctx, PhasePreMerge, e.config.PreMerge, env)
+	if preMerge.Blocked != "" {
+		return ProcessResult{
+			Error:    fmt.Sprintf("pre-merge action failed: %s", preMerge.Blocked),
+			Outputs:  preMerge.Outputs,
+			Warnings: preMerge.Warnings,
+		}
+	}
+	return ProcessResult{
+		Success:     true,
+		MergeCommit: head,
+		Outputs:     preMerge.Outputs,
+		Warnings:    preMerge.Warnings,
+	}
+}
+
+// Land publishes the commit checked out in the refinery work tree to target.
+// It is the push half of the patrol formula's merge-push step (gt mq land):
+// the patrol has already rebased and gated HEAD, and Land keeps that true
+// while it holds the merge slot by rebasing onto any target commits that
+// landed in the meantime and re-running the gates and pre-merge actions if
+// HEAD moved. The result carries the outputs of that re-run, if any.
+//
+// The push is by refspec (HEAD:<target>), so lane worktrees never need the
+// target branch checked out. The work tree is left on the landed commit.
+func (e *Engineer) Land(ctx context.Context, env MergeActionEnv) ProcessResult {
+	ctx = withGateMRs(ctx, env.MRID)
+	target := env.Target
+
+	tested, err := e.git.Rev("HEAD")
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("failed to get HEAD: %v", err)}
+	}
+
+	// Serialize writes to the default branch, as doMerge does.
+	if target == e.rig.DefaultBranch() {
+		pushHolder, slotErr := e.acquireMainPushSlot(ctx)
+		if slotErr != nil {
+			return ProcessResult{
+				SlotTimeout: errors.Is(slotErr, errMergeSlotTimeout),
+				Error:       fmt.Sprintf("failed to acquire merge slot before push: %v", slotErr),
+			}
+		}
+		defer func() {
+			if pushHolder != "" {
+				if releaseErr := e.mergeSlotRelease(pushHolder); releaseErr != nil {
+					_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to release merge slot for push (%s): %v\n", pushHolder, releaseErr)
+				}
+			}
+		}()
+	}
+
+	landed, result := e.refreshOntoTarget(ctx, tested, env)
+	if !result.Success {
+		return result
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing %s to origin/%s...\n", landed[:8], target)
+	if err := e.git.Push("origin", "HEAD:"+target, false); err != nil {
+		return ProcessResult{Error: fmt.Sprintf("failed to push to origin: %v", err)}
+	}
+
+	result.MergeCommit = landed
+	return result
+}
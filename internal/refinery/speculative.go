@@ -0,0 +1,431 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// SpeculativeConfig holds configuration for speculative (pipelined) gate execution.
+//
+// Instead of waiting for MR N to pass before testing MR N+1, the refinery
+// tests target+N, target+N+N+1, ... concurrently in separate worktrees,
+// assuming every earlier MR will pass (Zuul-style). If an earlier MR fails,
+// the pipelines stacked on top of it are cancelled and restarted without it.
+// The patrol runs it through 'gt mq speculate' when Depth > 1, then handles
+// the MRs it could not stack one at a time.
+type SpeculativeConfig struct {
+	// Depth is the number of pipelines run at once (MRs tested ahead).
+	// Values <= 1 disable speculation. Default: 3.
+	Depth int `json:"depth"`
+
+	// CacheResults reuses passing gate results for a tree that was already
+	// tested with the same gate configuration (e.g., after a push failure
+	// or a restart that rebuilt an identical stack). Default: true.
+	CacheResults bool `json:"cache_results"`
+}
+
+// DefaultSpeculativeConfig returns sensible defaults for speculative execution.
+func DefaultSpeculativeConfig() *SpeculativeConfig {
+	return &SpeculativeConfig{
+		Depth:        3,
+		CacheResults: true,
+	}
+}
+
+// SpeculativeResult holds the outcome of a speculative processing run.
+type SpeculativeResult struct {
+	// Merged is the set of MRs pushed to the target, in merge order.
+	Merged []*MRInfo
+
+	// Culprits is the set of MRs whose pipeline failed gates.
+	Culprits []*MRInfo
+
+	// Conflicts is the set of MRs that could not be stacked onto the MRs ahead of them.
+	Conflicts []*MRInfo
+
+	// MergeCommit is the last SHA pushed to the target branch (empty if nothing merged).
+	MergeCommit string
+
+	// Results holds the gate result for each merged MR and culprit, keyed
+	// by MR ID. Merged results carry the landed commit and the outputs of
+	// the pre-merge actions run against it.
+	Results map[string]ProcessResult
+
+	// Restarts counts how many times later pipelines were cancelled and
+	// rebuilt because an earlier MR failed.
+	Restarts int
+
+	// CacheHits counts pipelines whose gates were skipped via the result cache.
+	CacheHits int
+
+	// Error is set if processing stopped on an infrastructure error.
+	// MRs not listed above were not processed and remain in the queue.
+	Error error
+}
+
+// gateResultCache remembers passing gate runs keyed by tree SHA and gate
+// configuration. Only passes are cached: a failing tree is never retried as-is
+// (its MR is removed from the stack), and caching failures would pin flakes.
+type gateResultCache struct {
+	mu     sync.Mutex
+	passed map[string]bool
+}
+
+func newGateResultCache() *gateResultCache {
+	return &gateResultCache{passed: make(map[string]bool)}
+}
+
+func (c *gateResultCache) hit(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.passed[key]
+}
+
+func (c *gateResultCache) recordPass(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.passed[key] = true
+}
+
+// gateFingerprint identifies the gate configuration so cached results are
+// invalidated when gates change.
+func (e *Engineer) gateFingerprint() string {
+	if len(e.config.Gates) > 0 {
+		names := make([]string, 0, len(e.config.Gates))
+		for name := range e.config.Gates {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		parts := make([]string, len(names))
+		for i, name := range names {
+			parts[i] = name + "=" + e.config.Gates[name].Cmd
+		}
+		return strings.Join(parts, "\x00")
+	}
+	if e.config.RunTests {
+		return "test=" + e.config.TestCommand
+	}
+	return ""
+}
+
+// syncWriter serializes writes from concurrent pipelines to a shared output.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// speculativeWorkDir returns the worktree for pipeline idx.
+// Lanes get their own set so lane refineries never share pipelines.
+func (e *Engineer) speculativeWorkDir(idx int) string {
+	name := "main"
+	if e.lane != "" {
+		name = e.lane
+	}
+	return filepath.Join(e.rig.Path, "refinery", "speculative", fmt.Sprintf("%s-%d", name, idx))
+}
+
+// ensureSpeculativeWorktrees creates detached worktrees for each pipeline.
+func (e *Engineer) ensureSpeculativeWorktrees(depth int) ([]string, error) {
+	dirs := make([]string, depth)
+	for i := range dirs {
+		dir := e.speculativeWorkDir(i)
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+				return nil, fmt.Errorf("creating speculative directory: %w", err)
+			}
+			if err := e.git.WorktreeAddDetached(dir, "HEAD"); err != nil {
+				return nil, fmt.Errorf("creating pipeline worktree %d: %w", i, err)
+			}
+		}
+		dirs[i] = dir
+	}
+	return dirs, nil
+}
+
+// speculativeStep is one MR in the speculative stack and the commit that
+// represents target + every MR up to and including it.
+type speculativeStep struct {
+	mr     *MRInfo
+	commit string
+}
+
+// buildSpeculativeStack squash-merges mrs onto base one at a time and records
+// the commit after each. Each pipeline tests one of these commits, and since
+// they form a single chain, pushing them in order is always a fast-forward.
+// MRs that conflict with the MRs ahead of them are dropped from the chain.
+func (e *Engineer) buildSpeculativeStack(g *git.Git, base string, mrs []*MRInfo) (steps []speculativeStep, conflicts []*MRInfo, err error) {
+	if err := g.Checkout(base); err != nil {
+		return nil, nil, fmt.Errorf("checkout base %s: %w", base, err)
+	}
+	if err := g.ResetHard(base); err != nil {
+		return nil, nil, fmt.Errorf("reset to base %s: %w", base, err)
+	}
+
+	head := base
+	for _, mr := range mrs {
+		if exists, brErr := g.BranchExists(mr.Branch); brErr != nil || !exists {
+			_, _ = fmt.Fprintf(e.output, "[Speculative] MR %s: branch %s not found, skipping\n", mr.ID, mr.Branch)
+			conflicts = append(conflicts, mr)
+			continue
+		}
+		if mergeErr := g.MergeSquash(mr.Branch, e.getMergeMessage(mr)); mergeErr != nil {
+			_, _ = fmt.Fprintf(e.output, "[Speculative] MR %s: does not stack cleanly: %v\n", mr.ID, mergeErr)
+			conflicts = append(conflicts, mr)
+			if resetErr := g.ResetHard(head); resetErr != nil {
+				return nil, nil, fmt.Errorf("reset after conflict in %s: %w", mr.ID, resetErr)
+			}
+			continue
+		}
+		commit, revErr := g.Rev("HEAD")
+		if revErr != nil {
+			return nil, nil, fmt.Errorf("resolve stack head after %s: %w", mr.ID, revErr)
+		}
+		head = commit
+		steps = append(steps, speculativeStep{mr: mr, commit: commit})
+	}
+	return steps, conflicts, nil
+}
+
+// runPipeline checks out commit in dir and verifies it as 'gt mq gate' would:
+// gates, then pre-merge actions against commit. Cached gate passes skip the
+// gates but not the actions. Returns the result, whether the gates came from
+// the cache, and any error that kept the pipeline from running at all.
+func (e *Engineer) runPipeline(ctx context.Context, dir, commit string, env MergeActionEnv, cache *gateResultCache) (ProcessResult, bool, error) {
+	g := git.NewGit(dir)
+	if err := g.Checkout(commit); err != nil {
+		return ProcessResult{}, false, fmt.Errorf("pipeline checkout %s: %w", commit, err)
+	}
+
+	key := ""
+	if cache != nil {
+		if tree, err := g.Rev(commit + "^{tree}"); err == nil {
+			key = tree + "\x00" + e.gateFingerprint()
+		}
+	}
+	cached := key != "" && cache.hit(key)
+
+	pe := *e
+	pe.git = g
+	pe.workDir = dir
+	if !cached {
+		if result := pe.runBatchGates(ctx); !result.Success {
+			if ctx.Err() != nil {
+				return result, false, fmt.Errorf("pipeline cancelled")
+			}
+			return result, false, nil
+		}
+		if key != "" {
+			cache.recordPass(key)
+		}
+	}
+	result := pe.runPreMerge(ctx, env)
+	if !result.Success && ctx.Err() != nil {
+		return result, cached, fmt.Errorf("pipeline cancelled")
+	}
+	return result, cached, nil
+}
+
+// pushSpeculativeCommit fast-forwards the target to commit.
+func (e *Engineer) pushSpeculativeCommit(ctx context.Context, commit, target string) error {
+	if target == e.rig.DefaultBranch() {
+		holder, err := e.acquireMainPushSlot(ctx)
+		if err != nil {
+			return fmt.Errorf("acquire merge slot: %w", err)
+		}
+		defer func() {
+			if holder != "" {
+				if releaseErr := e.mergeSlotRelease(holder); releaseErr != nil {
+					_, _ = fmt.Fprintf(e.output, "[Speculative] Warning: failed to release merge slot: %v\n", releaseErr)
+				}
+			}
+		}()
+	}
+	if err := e.git.Push("origin", commit+":refs/heads/"+target, false); err != nil {
+		return fmt.Errorf("push %s to origin/%s: %w", shortSHA(commit), target, err)
+	}
+	return nil
+}
+
+// ProcessSpeculative processes queued MRs with speculative gate pipelines.
+//
+// Algorithm:
+//  1. Take the next Depth MRs and stack them on origin/target as a chain
+//     of squash commits (target+1, target+1+2, ...)
+//  2. Run gates and pre-merge actions on every commit in the chain
+//     concurrently, one worktree each
+//  3. Walk results in queue order, pushing each passing commit as a fast-forward
+//  4. On the first failure, cancel the pipelines stacked on top of the failing
+//     MR, drop it, and restart from step 1 with the remaining MRs
+//
+// MRs must be pre-sorted by score (highest first). ProcessSpeculative only
+// pushes: the caller runs post-merge bookkeeping for Merged and reports
+// Culprits, and Conflicts stay queued for the one-at-a-time path.
+func (e *Engineer) ProcessSpeculative(ctx context.Context, queue []*MRInfo, target string, cfg *SpeculativeConfig) *SpeculativeResult {
+	if cfg == nil {
+		cfg = DefaultSpeculativeConfig()
+	}
+	result := &SpeculativeResult{Results: make(map[string]ProcessResult)}
+	if len(queue) == 0 {
+		return result
+	}
+
+	depth := cfg.Depth
+	if depth < 1 {
+		depth = 1
+	}
+	var cache *gateResultCache
+	if cfg.CacheResults {
+		if e.gateCache == nil {
+			e.gateCache = newGateResultCache()
+		}
+		cache = e.gateCache
+	}
+
+	// Pipelines log concurrently.
+	origOutput := e.output
+	e.output = &syncWriter{w: origOutput}
+	defer func() { e.output = origOutput }()
+
+	dirs, err := e.ensureSpeculativeWorktrees(depth)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	buildGit := git.NewGit(dirs[0])
+
+	pending := append([]*MRInfo{}, queue...)
+	for len(pending) > 0 {
+		if ctx.Err() != nil {
+			result.Error = ctx.Err()
+			return result
+		}
+
+		if err := e.git.FetchBranch("origin", target); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Speculative] Warning: fetch origin/%s: %v (continuing)\n", target, err)
+		}
+		base, err := e.git.Rev("origin/" + target)
+		if err != nil {
+			result.Error = fmt.Errorf("resolve origin/%s: %w", target, err)
+			return result
+		}
+
+		window := pending[:min(depth, len(pending))]
+		pending = pending[len(window):]
+
+		steps, conflicts, err := e.buildSpeculativeStack(buildGit, base, window)
+		if err != nil {
+			result.Error = fmt.Errorf("build speculative stack: %w", err)
+			return result
+		}
+		result.Conflicts = append(result.Conflicts, conflicts...)
+		if len(steps) == 0 {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(e.output, "[Speculative] Starting %d pipeline(s) on %s: %v\n",
+			len(steps), shortSHA(base), mrIDs(stepMRs(steps)))
+
+		type outcome struct {
+			result ProcessResult
+			cached bool
+			err    error
+		}
+		pctx, cancel := context.WithCancel(ctx)
+		outcomes := make([]chan outcome, len(steps))
+		for i, step := range steps {
+			ch := make(chan outcome, 1)
+			outcomes[i] = ch
+			env := MergeActionEnv{
+				MRID:        step.mr.ID,
+				Branch:      step.mr.Branch,
+				Target:      target,
+				SourceIssue: step.mr.SourceIssue,
+				Worker:      step.mr.Worker,
+			}
+			go func(dir, commit string, env MergeActionEnv) {
+				r, cached, err := e.runPipeline(withGateMRs(pctx, env.MRID), dir, commit, env, cache)
+				ch <- outcome{result: r, cached: cached, err: err}
+			}(dirs[i], step.commit, env)
+		}
+
+		failedAt := -1
+		for i, step := range steps {
+			out := <-outcomes[i]
+			if out.cached {
+				result.CacheHits++
+				_, _ = fmt.Fprintf(e.output, "[Speculative] MR %s: gates cached for this tree\n", step.mr.ID)
+			}
+			if out.err != nil {
+				failedAt = i
+				result.Error = fmt.Errorf("pipeline for %s: %w", step.mr.ID, out.err)
+				break
+			}
+			if !out.result.Success {
+				failedAt = i
+				_, _ = fmt.Fprintf(e.output, "[Speculative] MR %s: gates failed: %s\n", step.mr.ID, out.result.Error)
+				result.Culprits = append(result.Culprits, step.mr)
+				result.Results[step.mr.ID] = out.result
+				break
+			}
+			if pushErr := e.pushSpeculativeCommit(ctx, step.commit, target); pushErr != nil {
+				failedAt = i
+				result.Error = pushErr
+				break
+			}
+			_, _ = fmt.Fprintf(e.output, "[Speculative] Merged %s (commit %s)\n", step.mr.ID, shortSHA(step.commit))
+			out.result.MergeCommit = step.commit
+			result.Merged = append(result.Merged, step.mr)
+			result.Results[step.mr.ID] = out.result
+			result.MergeCommit = step.commit
+		}
+
+		// Cancel anything still running and wait for it so the worktrees
+		// are idle before the stack is rebuilt.
+		cancel()
+		for i := failedAt + 1; failedAt >= 0 && i < len(steps); i++ {
+			<-outcomes[i]
+		}
+
+		if failedAt < 0 {
+			continue
+		}
+		if result.Error != nil {
+			return result
+		}
+		if rest := steps[failedAt+1:]; len(rest) > 0 {
+			result.Restarts++
+			_, _ = fmt.Fprintf(e.output, "[Speculative] Restarting %d pipeline(s) without %s\n", len(rest), steps[failedAt].mr.ID)
+			pending = append(stepMRs(rest), pending...)
+		}
+	}
+
+	return result
+}
+
+// stepMRs returns the MRs of a speculative chain in order.
+func stepMRs(steps []speculativeStep) []*MRInfo {
+	mrs := make([]*MRInfo, len(steps))
+	for i, s := range steps {
+		mrs[i] = s.mr
+	}
+	return mrs
+}
+
+// shortSHA abbreviates a commit SHA for logging.
+func shortSHA(sha string) string {
+	return sha[:min(8, len(sha))]
+}
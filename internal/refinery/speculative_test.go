@@ -0,0 +1,224 @@
+package refinery
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gitpkg "github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// newSpeculativeTestEngineer returns an engineer whose rig directory sits
+// outside the git working tree, so pipeline worktrees don't nest inside it.
+func newSpeculativeTestEngineer(t *testing.T, workDir string, g *gitpkg.Git, gateCmd string) *Engineer {
+	t.Helper()
+	e := newTestEngineer(t, workDir, g)
+	e.rig = &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e.config.Gates = map[string]*GateConfig{"check": {Cmd: gateCmd}}
+	return e
+}
+
+func originLog(t *testing.T, workDir string) []string {
+	t.Helper()
+	run(t, workDir, "git", "fetch", "origin")
+	return strings.Split(run(t, workDir, "git", "log", "--format=%s", "origin/main"), "\n")
+}
+
+func TestProcessSpeculative_AllPass(t *testing.T) {
+	workDir, g, _ := testGitRepo(t)
+	createFeatureBranch(t, workDir, "polecat/a", "a.txt", "a\n")
+	createFeatureBranch(t, workDir, "polecat/b", "b.txt", "b\n")
+	createFeatureBranch(t, workDir, "polecat/c", "c.txt", "c\n")
+
+	e := newSpeculativeTestEngineer(t, workDir, g, "true")
+	queue := []*MRInfo{
+		makeMR("gt-a", "polecat/a", "main"),
+		makeMR("gt-b", "polecat/b", "main"),
+		makeMR("gt-c", "polecat/c", "main"),
+	}
+
+	result := e.ProcessSpeculative(context.Background(), queue, "main", &SpeculativeConfig{Depth: 3})
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if got := mrIDs(result.Merged); strings.Join(got, ",") != "gt-a,gt-b,gt-c" {
+		t.Fatalf("Merged = %v, want [gt-a gt-b gt-c]", got)
+	}
+	if result.Restarts != 0 {
+		t.Errorf("Restarts = %d, want 0", result.Restarts)
+	}
+
+	log := originLog(t, workDir)
+	want := []string{"feat: add c.txt", "feat: add b.txt", "feat: add a.txt", "initial commit"}
+	if strings.Join(log, "|") != strings.Join(want, "|") {
+		t.Errorf("origin/main history = %v, want %v", log, want)
+	}
+	if head := run(t, workDir, "git", "rev-parse", "origin/main"); head != result.MergeCommit {
+		t.Errorf("MergeCommit = %s, want origin/main %s", result.MergeCommit, head)
+	}
+}
+
+func TestProcessSpeculative_FailureRestartsLaterPipelines(t *testing.T) {
+	workDir, g, _ := testGitRepo(t)
+	createFeatureBranch(t, workDir, "polecat/a", "a.txt", "a\n")
+	createFeatureBranch(t, workDir, "polecat/bad", "bad.txt", "bad\n")
+	createFeatureBranch(t, workDir, "polecat/c", "c.txt", "c\n")
+
+	e := newSpeculativeTestEngineer(t, workDir, g, "test ! -f bad.txt")
+	queue := []*MRInfo{
+		makeMR("gt-a", "polecat/a", "main"),
+		makeMR("gt-bad", "polecat/bad", "main"),
+		makeMR("gt-c", "polecat/c", "main"),
+	}
+
+	result := e.ProcessSpeculative(context.Background(), queue, "main", &SpeculativeConfig{Depth: 3})
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if got := mrIDs(result.Merged); strings.Join(got, ",") != "gt-a,gt-c" {
+		t.Errorf("Merged = %v, want [gt-a gt-c]", got)
+	}
+	if got := mrIDs(result.Culprits); strings.Join(got, ",") != "gt-bad" {
+		t.Errorf("Culprits = %v, want [gt-bad]", got)
+	}
+	if result.Restarts != 1 {
+		t.Errorf("Restarts = %d, want 1", result.Restarts)
+	}
+
+	log := originLog(t, workDir)
+	for _, subject := range log {
+		if strings.Contains(subject, "bad.txt") {
+			t.Fatalf("culprit landed on main: %v", log)
+		}
+	}
+	if len(log) != 3 {
+		t.Errorf("origin/main history = %v, want 3 commits", log)
+	}
+}
+
+func TestProcessSpeculative_Conflict(t *testing.T) {
+	workDir, g, _ := testGitRepo(t)
+	createFeatureBranch(t, workDir, "polecat/one", "shared.txt", "one\n")
+	createConflictingBranch(t, workDir, "polecat/two", "shared.txt", "two\n")
+
+	e := newSpeculativeTestEngineer(t, workDir, g, "true")
+	queue := []*MRInfo{
+		makeMR("gt-one", "polecat/one", "main"),
+		makeMR("gt-two", "polecat/two", "main"),
+	}
+
+	result := e.ProcessSpeculative(context.Background(), queue, "main", &SpeculativeConfig{Depth: 2})
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if got := mrIDs(result.Merged); strings.Join(got, ",") != "gt-one" {
+		t.Errorf("Merged = %v, want [gt-one]", got)
+	}
+	if got := mrIDs(result.Conflicts); strings.Join(got, ",") != "gt-two" {
+		t.Errorf("Conflicts = %v, want [gt-two]", got)
+	}
+}
+
+func TestProcessSpeculative_PreMergeActions(t *testing.T) {
+	workDir, g, _ := testGitRepo(t)
+	createFeatureBranch(t, workDir, "polecat/a", "a.txt", "a\n")
+	createFeatureBranch(t, workDir, "polecat/b", "b.txt", "b\n")
+
+	e := newSpeculativeTestEngineer(t, workDir, g, "true")
+	e.config.PreMerge = map[string]*MergeActionConfig{
+		"artifacts": {Cmd: `test "$GT_MR_ID" != gt-b || exit 1; echo "commit=$GT_MERGE_COMMIT" > "$GT_OUTPUT"`},
+	}
+	queue := []*MRInfo{
+		makeMR("gt-a", "polecat/a", "main"),
+		makeMR("gt-b", "polecat/b", "main"),
+	}
+
+	result := e.ProcessSpeculative(context.Background(), queue, "main", &SpeculativeConfig{Depth: 2})
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if got := mrIDs(result.Merged); strings.Join(got, ",") != "gt-a" {
+		t.Fatalf("Merged = %v, want [gt-a]", got)
+	}
+	if got := mrIDs(result.Culprits); strings.Join(got, ",") != "gt-b" {
+		t.Errorf("Culprits = %v, want [gt-b] (blocking pre-merge action)", got)
+	}
+	merged := result.Results["gt-a"]
+	if merged.MergeCommit != result.MergeCommit {
+		t.Errorf("Results[gt-a].MergeCommit = %q, want %q", merged.MergeCommit, result.MergeCommit)
+	}
+	if got := merged.Outputs["artifacts.commit"]; got != merged.MergeCommit {
+		t.Errorf("artifacts.commit = %q, want the landed commit %s", got, merged.MergeCommit)
+	}
+	if culprit, ok := result.Results["gt-b"]; !ok || culprit.Success {
+		t.Errorf("Results[gt-b] = %+v, want a failed result", culprit)
+	}
+}
+
+func TestRunPipeline_CachesPassingTree(t *testing.T) {
+	workDir, g, _ := testGitRepo(t)
+	counter := filepath.Join(t.TempDir(), "runs")
+	e := newSpeculativeTestEngineer(t, workDir, g, "echo run >> "+counter)
+
+	dirs, err := e.ensureSpeculativeWorktrees(1)
+	if err != nil {
+		t.Fatalf("ensureSpeculativeWorktrees: %v", err)
+	}
+	head := run(t, workDir, "git", "rev-parse", "HEAD")
+	cache := newGateResultCache()
+
+	for i, wantCached := range []bool{false, true} {
+		result, cached, err := e.runPipeline(context.Background(), dirs[0], head, MergeActionEnv{}, cache)
+		if err != nil {
+			t.Fatalf("run %d: %v", i, err)
+		}
+		if !result.Success {
+			t.Fatalf("run %d failed: %s", i, result.Error)
+		}
+		if cached != wantCached {
+			t.Errorf("run %d cached = %v, want %v", i, cached, wantCached)
+		}
+	}
+
+	data, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs := strings.Count(string(data), "run"); runs != 1 {
+		t.Errorf("gate ran %d times, want 1", runs)
+	}
+
+	// Changing the gate configuration invalidates the cache.
+	e.config.Gates["check"].Cmd = "echo rerun >> " + counter
+	if _, cached, _ := e.runPipeline(context.Background(), dirs[0], head, MergeActionEnv{}, cache); cached {
+		t.Error("expected cache miss after gate config change")
+	}
+}
+
+func TestEngineer_LoadConfig_Speculative(t *testing.T) {
+	tmpDir := t.TempDir()
+	data, _ := json.Marshal(map[string]interface{}{
+		"merge_queue": map[string]interface{}{
+			"speculative": map[string]interface{}{"depth": 5},
+		},
+	})
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	spec := e.Config().Speculative
+	if spec == nil || spec.Depth != 5 {
+		t.Fatalf("Speculative = %+v, want depth 5", spec)
+	}
+	if !spec.CacheResults {
+		t.Error("expected cache_results to keep its default (true)")
+	}
+}
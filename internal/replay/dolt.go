@@ -0,0 +1,255 @@
+package replay
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql" // MySQL driver for the Dolt server
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+)
+
+// Snapshot is bead state read from Dolt history as of one instant.
+type Snapshot struct {
+	// Hooks maps agent addresses to the bead on their hook.
+	Hooks       map[string]string
+	Convoys     []ConvoyState
+	MergeQueue  []MergeRequest
+	Escalations []Escalation
+}
+
+// History answers point-in-time questions about beads.
+type History interface {
+	SnapshotAt(ctx context.Context, at time.Time) (*Snapshot, error)
+}
+
+// DoltHistory reads bead history from the town's Dolt server with AS OF
+// queries across every town and rig database.
+type DoltHistory struct {
+	Config    *doltserver.Config
+	Databases []string
+}
+
+// NewDoltHistory returns a History over the town's Dolt databases.
+func NewDoltHistory(townRoot string) (*DoltHistory, error) {
+	dbs, err := doltserver.ListDatabases(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing databases: %w", err)
+	}
+	h := &DoltHistory{Config: doltserver.DefaultConfig(townRoot)}
+	for _, db := range dbs {
+		if !doltserver.IsSystemDatabase(db) {
+			h.Databases = append(h.Databases, db)
+		}
+	}
+	return h, nil
+}
+
+// SnapshotAt reads hooks, open convoys, the merge queue and open
+// escalations as they were at the given instant. Databases that did not
+// exist yet (or cannot be read) are skipped; it fails only when none can
+// be read.
+func (h *DoltHistory) SnapshotAt(ctx context.Context, at time.Time) (*Snapshot, error) {
+	snap := &Snapshot{
+		Hooks:       make(map[string]string),
+		Convoys:     []ConvoyState{},
+		MergeQueue:  []MergeRequest{},
+		Escalations: []Escalation{},
+	}
+	asOf := asOfClause(at)
+
+	statuses := make(map[string]string)
+	tracked := make(map[string][]string)
+	var firstErr error
+	read := 0
+	for _, name := range h.Databases {
+		db, err := h.open(name)
+		if err != nil {
+			return nil, err
+		}
+		err = readDatabase(ctx, db, asOf, snap, tracked, statuses)
+		_ = db.Close()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", name, err)
+			}
+			continue
+		}
+		read++
+	}
+	if read == 0 {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("no databases")
+		}
+		return nil, fmt.Errorf("reading Dolt history: %w", firstErr)
+	}
+
+	for i := range snap.Convoys {
+		c := &snap.Convoys[i]
+		for _, id := range tracked[c.ID] {
+			c.Tracked++
+			if statuses[id] == "closed" {
+				c.Closed++
+			}
+		}
+	}
+	sort.Slice(snap.Convoys, func(i, j int) bool { return snap.Convoys[i].ID < snap.Convoys[j].ID })
+	sort.Slice(snap.MergeQueue, func(i, j int) bool { return snap.MergeQueue[i].Branch < snap.MergeQueue[j].Branch })
+	sort.Slice(snap.Escalations, func(i, j int) bool { return snap.Escalations[i].Since.Before(snap.Escalations[j].Since) })
+	return snap, nil
+}
+
+func (h *DoltHistory) open(dbName string) (*sql.DB, error) {
+	user := h.Config.User
+	if h.Config.Password != "" {
+		user += ":" + h.Config.Password
+	}
+	dsn := fmt.Sprintf("%s@tcp(%s)/%s?parseTime=true&timeout=5s&readTimeout=30s",
+		user, h.Config.HostPort(), dbName)
+	return sql.Open("mysql", dsn)
+}
+
+// asOfClause renders the AS OF expression for an instant. Dolt commit
+// dates are UTC.
+func asOfClause(at time.Time) string {
+	return fmt.Sprintf("CONVERT('%s', DATETIME)", at.UTC().Format("2006-01-02 15:04:05"))
+}
+
+// readDatabase adds one database's point-in-time bead state to snap.
+// tracked collects convoy → tracked issue IDs and statuses the status of
+// every issue, so convoy progress can span databases.
+func readDatabase(ctx context.Context, db *sql.DB, asOf string, snap *Snapshot, tracked map[string][]string, statuses map[string]string) error {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT id, status, COALESCE(assignee, ''), COALESCE(issue_type, '') FROM issues AS OF %s", asOf))
+	if err != nil {
+		return err
+	}
+	var convoyIDs []string
+	for rows.Next() {
+		var id, status, assignee, issueType string
+		if err := rows.Scan(&id, &status, &assignee, &issueType); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		statuses[id] = status
+		if status == "hooked" && assignee != "" {
+			snap.Hooks[strings.TrimSuffix(assignee, "/")] = id
+		}
+		if issueType == "convoy" && status != "closed" {
+			convoyIDs = append(convoyIDs, id)
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(convoyIDs) > 0 {
+		if err := readConvoys(ctx, db, asOf, convoyIDs, snap, tracked); err != nil {
+			return err
+		}
+	}
+	if err := readMergeRequests(ctx, db, asOf, snap); err != nil {
+		return err
+	}
+	return readEscalations(ctx, db, asOf, snap)
+}
+
+func readConvoys(ctx context.Context, db *sql.DB, asOf string, ids []string, snap *Snapshot, tracked map[string][]string) error {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT id, COALESCE(title, '') FROM issues AS OF %s WHERE id IN (%s)", asOf, placeholders), args...)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var c ConvoyState
+		if err := rows.Scan(&c.ID, &c.Title); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		snap.Convoys = append(snap.Convoys, c)
+	}
+	_ = rows.Close()
+
+	rows, err = db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT issue_id, depends_on_id FROM dependencies AS OF %s WHERE type = 'tracks' AND issue_id IN (%s)", asOf, placeholders), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var convoy, dep string
+		if err := rows.Scan(&convoy, &dep); err != nil {
+			return err
+		}
+		// Cross-rig references are stored as external:<prefix>:<id>.
+		if i := strings.LastIndex(dep, ":"); i >= 0 {
+			dep = dep[i+1:]
+		}
+		tracked[convoy] = append(tracked[convoy], dep)
+	}
+	return rows.Err()
+}
+
+// labeledOpenIssues selects open issues carrying a label, as of an instant.
+func labeledOpenIssues(ctx context.Context, db *sql.DB, asOf, label string) (*sql.Rows, error) {
+	return db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT i.id, i.status, COALESCE(i.title, ''), COALESCE(i.description, ''), i.created_at "+
+			"FROM issues AS OF %[1]s i JOIN labels AS OF %[1]s l ON l.issue_id = i.id "+
+			"WHERE l.label = ? AND i.status != 'closed'", asOf), label)
+}
+
+func readMergeRequests(ctx context.Context, db *sql.DB, asOf string, snap *Snapshot) error {
+	rows, err := labeledOpenIssues(ctx, db, asOf, "gt:merge-request")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, status, title, desc string
+		var created time.Time
+		if err := rows.Scan(&id, &status, &title, &desc, &created); err != nil {
+			return err
+		}
+		mr := MergeRequest{ID: id, Status: MRQueued}
+		if status == "in_progress" {
+			mr.Status = MRMerging
+		}
+		if f := beads.ParseMRFields(&beads.Issue{Description: desc}); f != nil {
+			mr.Branch, mr.Worker = f.Branch, f.Worker
+		}
+		snap.MergeQueue = append(snap.MergeQueue, mr)
+	}
+	return rows.Err()
+}
+
+func readEscalations(ctx context.Context, db *sql.DB, asOf string, snap *Snapshot) error {
+	rows, err := labeledOpenIssues(ctx, db, asOf, "gt:escalation")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, status, title, desc string
+		var created time.Time
+		if err := rows.Scan(&id, &status, &title, &desc, &created); err != nil {
+			return err
+		}
+		esc := Escalation{ID: id, Reason: title, Since: created}
+		if f := beads.ParseEscalationFields(desc); f != nil {
+			esc.From, esc.Severity, esc.Acked = f.EscalatedBy, f.Severity, f.AckedBy != ""
+		}
+		snap.Escalations = append(snap.Escalations, esc)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,190 @@
+// Package replay reconstructs what the town looked like at a past moment,
+// for postmortems. It merges the raw events log (.events.jsonl) and the
+// town log (logs/town.log) into one timeline, folds that timeline into the
+// state of agents, hooks, the merge queue and escalations at any instant,
+// and overlays bead state read from Dolt history with AS OF queries.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/townlog"
+)
+
+// Timeline sources.
+const (
+	SourceEvents  = "events"
+	SourceTownlog = "townlog"
+	SourceDolt    = "dolt"
+)
+
+// Entry is one moment on the replay timeline.
+type Entry struct {
+	Time    time.Time              `json:"time"`
+	Source  string                 `json:"source"`
+	Type    string                 `json:"type"`
+	Actor   string                 `json:"actor"`
+	Payload map[string]interface{} `json:"payload,omitempty"`
+}
+
+// Load reads the events log and the town log and returns their entries in
+// chronological order. Missing files are not an error: a young town may
+// have neither.
+func Load(townRoot string) ([]Entry, error) {
+	entries, err := loadEvents(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		return nil, err
+	}
+
+	logged, err := townlog.ReadEvents(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range logged {
+		entries = append(entries, Entry{
+			Time:   localTime(e.Timestamp),
+			Source: SourceTownlog,
+			Type:   string(e.Type),
+			Actor:  e.Agent,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	return entries, nil
+}
+
+// loadEvents parses .events.jsonl, skipping malformed lines.
+func loadEvents(path string) ([]Entry, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading events: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, Entry{
+			Time:    ts,
+			Source:  SourceEvents,
+			Type:    e.Type,
+			Actor:   e.Actor,
+			Payload: e.Payload,
+		})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading events: %w", err)
+	}
+	return entries, nil
+}
+
+// localTime reinterprets a town log timestamp in local time. The town log
+// writes wall-clock times without a zone, which townlog parses as UTC.
+func localTime(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+}
+
+// Window returns the entries in [from, to]. A zero bound is open.
+func Window(entries []Entry, from, to time.Time) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if !from.IsZero() && e.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && e.Time.After(to) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// Summary renders an entry as a one-line description.
+func (e Entry) Summary() string {
+	p := func(key string) string { return payloadString(e.Payload, key) }
+	switch e.Type {
+	case events.TypeSling:
+		return fmt.Sprintf("slung %s to %s", p("bead"), p("target"))
+	case events.TypeHook:
+		return "hooked " + p("bead")
+	case events.TypeUnhook:
+		return "unhooked " + p("bead")
+	case events.TypeDone:
+		if b := p("branch"); b != "" {
+			return fmt.Sprintf("done with %s (branch %s)", p("bead"), b)
+		}
+		return "done"
+	case events.TypeSpawn:
+		if pc := p("polecat"); pc != "" {
+			return fmt.Sprintf("spawned %s/polecats/%s", p("rig"), pc)
+		}
+		return "spawned"
+	case events.TypeSessionDeath:
+		return fmt.Sprintf("session %s died: %s", orDash(p("session")), orDash(p("reason")))
+	case events.TypeMassDeath:
+		return fmt.Sprintf("%v sessions died within %s", e.Payload["count"], p("window"))
+	case events.TypeKill:
+		return fmt.Sprintf("killed %s: %s", p("target"), p("reason"))
+	case events.TypeMergeStarted:
+		return fmt.Sprintf("merging %s (%s)", p("branch"), p("mr"))
+	case events.TypeMerged:
+		return fmt.Sprintf("merged %s", p("branch"))
+	case events.TypeMergeFailed, events.TypeMergeSkipped:
+		return fmt.Sprintf("%s %s: %s", strings.ReplaceAll(e.Type, "_", " "), p("branch"), p("reason"))
+	case events.TypeEscalationSent:
+		if p("escalation_id") != "" {
+			return fmt.Sprintf("re-escalated %s to %s", p("escalation_id"), p("new_severity"))
+		}
+		return fmt.Sprintf("escalated %s [%s]: %s", p("rig"), p("severity"), p("reason"))
+	case events.TypeEscalationAcked:
+		return "acknowledged " + p("escalation_id")
+	case events.TypeEscalationClosed:
+		return "closed " + p("escalation_id")
+	case events.TypeMail:
+		return fmt.Sprintf("mail to %s: %s", p("to"), p("subject"))
+	case events.TypeNudge:
+		return fmt.Sprintf("nudged %s", p("target"))
+	case events.TypeHandoff:
+		if s := p("subject"); s != "" {
+			return "handed off: " + s
+		}
+		return "handed off"
+	}
+	return strings.ReplaceAll(e.Type, "_", " ")
+}
+
+func payloadString(p map[string]interface{}, key string) string {
+	if v, ok := p[key]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,222 @@
+package replay
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+var t0 = time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)
+
+// at returns t0 plus m minutes.
+func at(m int) time.Time { return t0.Add(time.Duration(m) * time.Minute) }
+
+func ev(m int, typ, actor string, payload map[string]interface{}) Entry {
+	return Entry{Time: at(m), Source: SourceEvents, Type: typ, Actor: actor, Payload: payload}
+}
+
+// overnight is a night in the town: Toast is slung work, finishes and is
+// merged; Nux is slung work and crashes; an escalation is raised and acked.
+func overnight() []Entry {
+	return []Entry{
+		ev(0, events.TypeSpawn, "gt", events.SpawnPayload("gastown", "Toast")),
+		ev(1, events.TypeSling, "mayor", events.SlingPayload("gt-a", "gastown/polecats/Toast")),
+		ev(2, events.TypeSpawn, "gt", events.SpawnPayload("gastown", "Nux")),
+		ev(3, events.TypeHook, "gastown/polecats/Nux", events.HookPayload("gt-b")),
+		ev(10, events.TypeDone, "gastown/polecats/Toast", events.DonePayload("gt-a", "polecat/Toast/gt-a")),
+		ev(12, events.TypeMergeStarted, "gastown/refinery", events.MergePayload("gt-mr1", "Toast", "polecat/Toast/gt-a", "")),
+		{Time: at(15), Source: SourceTownlog, Type: "crash", Actor: "gastown/polecats/Nux"},
+		ev(16, events.TypeEscalationSent, "gastown/witness", func() map[string]interface{} {
+			p := events.EscalationPayload("hq-esc1", "gastown/witness", "mayor", "Nux crashed twice")
+			p["severity"] = "high"
+			return p
+		}()),
+		ev(20, events.TypeMerged, "gastown/refinery", events.MergePayload("gt-mr1", "Toast", "polecat/Toast/gt-a", "")),
+		ev(25, events.TypeEscalationAcked, "mayor", map[string]interface{}{"escalation_id": "hq-esc1"}),
+		ev(30, events.TypeEscalationClosed, "mayor", map[string]interface{}{"escalation_id": "hq-esc1"}),
+	}
+}
+
+func agentByAddress(s *State, address string) *AgentState {
+	for _, a := range s.Agents {
+		if a.Address == address {
+			return a
+		}
+	}
+	return nil
+}
+
+func TestRebuild(t *testing.T) {
+	entries := overnight()
+
+	s := Rebuild(entries, at(5))
+	toast, nux := agentByAddress(s, "gastown/polecats/Toast"), agentByAddress(s, "gastown/polecats/Nux")
+	if toast == nil || toast.Status != AgentRunning || toast.Hook != "gt-a" {
+		t.Errorf("Toast at +5m = %+v, want running with gt-a hooked", toast)
+	}
+	if nux == nil || nux.Status != AgentRunning || nux.Hook != "gt-b" {
+		t.Errorf("Nux at +5m = %+v, want running with gt-b hooked", nux)
+	}
+	if len(s.MergeQueue) != 0 || len(s.Escalations) != 0 {
+		t.Errorf("at +5m: merge queue %+v, escalations %+v, want both empty", s.MergeQueue, s.Escalations)
+	}
+
+	s = Rebuild(entries, at(17))
+	toast, nux = agentByAddress(s, "gastown/polecats/Toast"), agentByAddress(s, "gastown/polecats/Nux")
+	if toast.Status != AgentDone || toast.Hook != "" || !toast.Since.Equal(at(10)) {
+		t.Errorf("Toast at +17m = %+v, want done since +10m with empty hook", toast)
+	}
+	if nux.Status != AgentCrashed || !nux.Since.Equal(at(15)) {
+		t.Errorf("Nux at +17m = %+v, want crashed since +15m", nux)
+	}
+	if len(s.MergeQueue) != 1 || s.MergeQueue[0].Status != MRMerging || s.MergeQueue[0].ID != "gt-mr1" {
+		t.Errorf("merge queue at +17m = %+v, want gt-mr1 merging", s.MergeQueue)
+	}
+	if len(s.Escalations) != 1 || s.Escalations[0].ID != "hq-esc1" || s.Escalations[0].Severity != "high" || s.Escalations[0].Acked {
+		t.Errorf("escalations at +17m = %+v, want unacked high hq-esc1", s.Escalations)
+	}
+	if strings.Join(s.Sources, ",") != "events,townlog" {
+		t.Errorf("sources = %v", s.Sources)
+	}
+
+	s = Rebuild(entries, at(26))
+	if len(s.MergeQueue) != 0 {
+		t.Errorf("merge queue at +26m = %+v, want empty after merge", s.MergeQueue)
+	}
+	if len(s.Escalations) != 1 || !s.Escalations[0].Acked {
+		t.Errorf("escalations at +26m = %+v, want acked", s.Escalations)
+	}
+
+	if s = Rebuild(entries, at(31)); len(s.Escalations) != 0 {
+		t.Errorf("escalations at +31m = %+v, want none after close", s.Escalations)
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	s := Rebuild(overnight(), at(5))
+	s.Overlay(&Snapshot{
+		Hooks:      map[string]string{"gastown/polecats/Toast": "gt-a", "gastown/crew/max": "gt-c"},
+		Convoys:    []ConvoyState{{ID: "hq-cv1", Title: "Night work", Tracked: 2, Closed: 1}},
+		MergeQueue: []MergeRequest{{ID: "gt-mr0", Branch: "polecat/Slit/gt-z", Status: MRQueued}},
+	})
+
+	if a := agentByAddress(s, "gastown/polecats/Nux"); a.Hook != "" {
+		t.Errorf("Nux hook = %q, want cleared: Dolt shows nothing hooked", a.Hook)
+	}
+	if a := agentByAddress(s, "gastown/crew/max"); a == nil || a.Hook != "gt-c" {
+		t.Errorf("max = %+v, want added with gt-c hooked", a)
+	}
+	if len(s.Convoys) != 1 || s.Convoys[0].Closed != 1 {
+		t.Errorf("convoys = %+v", s.Convoys)
+	}
+	if len(s.MergeQueue) != 1 || s.MergeQueue[0].ID != "gt-mr0" {
+		t.Errorf("merge queue = %+v, want Dolt's", s.MergeQueue)
+	}
+	if s.Sources[len(s.Sources)-1] != SourceDolt {
+		t.Errorf("sources = %v, want dolt last", s.Sources)
+	}
+}
+
+func TestOverlay_KeepsEphemeralMRs(t *testing.T) {
+	// gt-mr1 is a wisp: the timeline has it merging at +17m, but Dolt history
+	// never saw it.
+	s := Rebuild(overnight(), at(17))
+	if len(s.MergeQueue) != 1 || s.MergeQueue[0].ID != "gt-mr1" {
+		t.Fatalf("merge queue before overlay = %+v, want gt-mr1", s.MergeQueue)
+	}
+	branch := s.MergeQueue[0].Branch
+	s.Overlay(&Snapshot{MergeQueue: []MergeRequest{{ID: "gt-mr0", Branch: "polecat/Slit/gt-z", Status: MRQueued}}})
+	if len(s.MergeQueue) != 2 || s.MergeQueue[0].ID != "gt-mr0" || s.MergeQueue[1].ID != "gt-mr1" || s.MergeQueue[1].Status != MRMerging {
+		t.Errorf("merge queue = %+v, want ephemeral gt-mr1 kept alongside Dolt's gt-mr0", s.MergeQueue)
+	}
+
+	// When Dolt does know an MR, its view wins.
+	s = Rebuild(overnight(), at(17))
+	s.Overlay(&Snapshot{MergeQueue: []MergeRequest{{ID: "gt-mr1", Branch: branch, Status: MRQueued}}})
+	if len(s.MergeQueue) != 1 || s.MergeQueue[0].Status != MRQueued {
+		t.Errorf("merge queue = %+v, want Dolt's gt-mr1", s.MergeQueue)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	townRoot := t.TempDir()
+
+	var lines []string
+	for _, e := range []events.Event{
+		{Timestamp: at(5).Format(time.RFC3339), Source: "gt", Type: events.TypeHook, Actor: "gastown/polecats/Nux", Payload: events.HookPayload("gt-b")},
+		{Timestamp: at(1).Format(time.RFC3339), Source: "gt", Type: events.TypeSling, Actor: "mayor", Payload: events.SlingPayload("gt-b", "gastown")},
+	} {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, string(data))
+	}
+	lines = append(lines, "not json", `{"ts":"yesterday","type":"hook"}`)
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	crash := at(3).In(time.Local)
+	if err := os.MkdirAll(filepath.Join(townRoot, "logs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	logLine := crash.Format("2006-01-02 15:04:05") + " [crash] gastown/polecats/Nux crashed: exit 1\n"
+	if err := os.WriteFile(filepath.Join(townRoot, "logs", "town.log"), []byte(logLine), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Load(townRoot)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Source+":"+e.Type)
+	}
+	if want := "events:sling,townlog:crash,events:hook"; strings.Join(got, ",") != want {
+		t.Errorf("entries = %v, want %s", got, want)
+	}
+	if !entries[1].Time.Equal(at(3)) {
+		t.Errorf("town log time = %v, want %v", entries[1].Time, at(3))
+	}
+
+	if w := Window(entries, at(2), at(4)); len(w) != 1 || w[0].Type != "crash" {
+		t.Errorf("Window = %+v, want the crash alone", w)
+	}
+
+	empty, err := Load(t.TempDir())
+	if err != nil || len(empty) != 0 {
+		t.Errorf("Load(empty town) = %v, %v", empty, err)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		entry Entry
+		want  string
+	}{
+		{ev(0, events.TypeSling, "mayor", events.SlingPayload("gt-a", "gastown")), "slung gt-a to gastown"},
+		{ev(0, events.TypeMerged, "gastown/refinery", events.MergePayload("gt-mr1", "Toast", "polecat/Toast/gt-a", "")), "merged polecat/Toast/gt-a"},
+		{ev(0, events.TypeMergeFailed, "gastown/refinery", events.MergePayload("gt-mr1", "Toast", "b", "conflict")), "merge failed b: conflict"},
+		{ev(0, events.TypeSessionDeath, "gt-Toast", events.SessionDeathPayload("gt-Toast", "gastown/polecats/Toast", "zombie cleanup", "daemon")), "session gt-Toast died: zombie cleanup"},
+		{Entry{Type: "patrol_complete"}, "patrol complete"},
+	}
+	for _, tt := range tests {
+		if got := tt.entry.Summary(); got != tt.want {
+			t.Errorf("Summary(%s) = %q, want %q", tt.entry.Type, got, tt.want)
+		}
+	}
+}
+
+func TestAsOfClause(t *testing.T) {
+	local := time.Date(2026, 3, 1, 4, 5, 6, 0, time.FixedZone("UTC+2", 2*3600))
+	if got, want := asOfClause(local), "CONVERT('2026-03-01 02:05:06', DATETIME)"; got != want {
+		t.Errorf("asOfClause = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,290 @@
+package replay
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/townlog"
+)
+
+// Agent statuses reconstructed from lifecycle events.
+const (
+	AgentRunning = "running"
+	AgentDone    = "done"
+	AgentCrashed = "crashed"
+	AgentStopped = "stopped"
+)
+
+// Merge request statuses reconstructed from refinery events.
+const (
+	MRQueued  = "queued"
+	MRMerging = "merging"
+	MRFailed  = "failed"
+)
+
+// AgentState is an agent as it stood at the replay instant.
+type AgentState struct {
+	Address   string    `json:"address"`
+	Status    string    `json:"status,omitempty"`
+	Hook      string    `json:"hook,omitempty"`
+	Since     time.Time `json:"since,omitempty"`
+	LastEvent string    `json:"last_event,omitempty"`
+	LastSeen  time.Time `json:"last_seen,omitempty"`
+}
+
+// ConvoyState is a convoy's progress at the replay instant.
+type ConvoyState struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Tracked int    `json:"tracked"`
+	Closed  int    `json:"closed"`
+}
+
+// MergeRequest is a merge queue entry at the replay instant.
+type MergeRequest struct {
+	ID     string `json:"id,omitempty"`
+	Branch string `json:"branch"`
+	Worker string `json:"worker,omitempty"`
+	Status string `json:"status"`
+}
+
+// Escalation is an open escalation at the replay instant.
+type Escalation struct {
+	ID       string    `json:"id"`
+	From     string    `json:"from,omitempty"`
+	Severity string    `json:"severity,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+	Acked    bool      `json:"acked,omitempty"`
+	Since    time.Time `json:"since,omitempty"`
+}
+
+// State is the town at one instant.
+type State struct {
+	At          time.Time      `json:"at"`
+	Agents      []*AgentState  `json:"agents"`
+	Convoys     []ConvoyState  `json:"convoys,omitempty"`
+	MergeQueue  []MergeRequest `json:"merge_queue"`
+	Escalations []Escalation   `json:"escalations"`
+	// Sources lists where the state came from ("events", "townlog", "dolt").
+	Sources []string `json:"sources"`
+	// HistoryError explains why Dolt history was not used, if it wasn't.
+	HistoryError string `json:"history_error,omitempty"`
+}
+
+// builder folds timeline entries into a State.
+type builder struct {
+	agents      map[string]*AgentState
+	mq          map[string]*MergeRequest // keyed by branch
+	escalations map[string]*Escalation
+	sources     map[string]bool
+}
+
+// Rebuild folds every entry at or before at into the town's state.
+// Entries must be in chronological order, as Load returns them.
+func Rebuild(entries []Entry, at time.Time) *State {
+	b := &builder{
+		agents:      make(map[string]*AgentState),
+		mq:          make(map[string]*MergeRequest),
+		escalations: make(map[string]*Escalation),
+		sources:     make(map[string]bool),
+	}
+	for _, e := range entries {
+		if e.Time.After(at) {
+			break
+		}
+		b.sources[e.Source] = true
+		b.apply(e)
+	}
+	return b.state(at)
+}
+
+func (b *builder) agent(address string, e Entry) *AgentState {
+	a := b.agents[address]
+	if a == nil {
+		a = &AgentState{Address: address}
+		b.agents[address] = a
+	}
+	a.LastEvent = e.Type
+	a.LastSeen = e.Time
+	return a
+}
+
+func (b *builder) setStatus(address, status string, e Entry) *AgentState {
+	a := b.agent(address, e)
+	if a.Status != status {
+		a.Status = status
+		a.Since = e.Time
+	}
+	return a
+}
+
+func (b *builder) apply(e Entry) {
+	p := func(key string) string { return payloadString(e.Payload, key) }
+
+	if e.Source == SourceTownlog {
+		switch townlog.EventType(e.Type) {
+		case townlog.EventSpawn, townlog.EventWake, townlog.EventHandoff:
+			b.setStatus(e.Actor, AgentRunning, e)
+		case townlog.EventDone:
+			b.setStatus(e.Actor, AgentDone, e)
+		case townlog.EventCrash:
+			b.setStatus(e.Actor, AgentCrashed, e)
+		case townlog.EventKill, townlog.EventSessionDeath:
+			b.setStatus(e.Actor, AgentStopped, e)
+		default:
+			b.agent(e.Actor, e)
+		}
+		return
+	}
+
+	switch e.Type {
+	case events.TypeSpawn:
+		if rig, pc := p("rig"), p("polecat"); rig != "" && pc != "" {
+			b.setStatus(rig+"/polecats/"+pc, AgentRunning, e)
+		}
+	case events.TypeSessionStart, events.TypeHandoff:
+		b.setStatus(e.Actor, AgentRunning, e)
+	case events.TypeHook:
+		b.setStatus(e.Actor, AgentRunning, e).Hook = p("bead")
+	case events.TypeSling:
+		if target := p("target"); strings.Contains(target, "/") {
+			b.agent(strings.TrimSuffix(target, "/"), e).Hook = p("bead")
+		}
+	case events.TypeUnhook:
+		b.agent(e.Actor, e).Hook = ""
+	case events.TypeDone:
+		b.setStatus(e.Actor, AgentDone, e).Hook = ""
+		if branch := p("branch"); branch != "" {
+			b.mq[branch] = &MergeRequest{Branch: branch, Worker: e.Actor, Status: MRQueued}
+		}
+	case events.TypeSessionDeath:
+		agent := p("agent")
+		if agent == "" {
+			agent = e.Actor
+		}
+		b.setStatus(agent, AgentStopped, e)
+	case events.TypeKill:
+		if target := p("target"); target != "" {
+			b.setStatus(target, AgentStopped, e)
+		}
+	case events.TypeMergeStarted:
+		mr := b.mergeRequest(p("branch"))
+		mr.ID, mr.Worker, mr.Status = p("mr"), p("worker"), MRMerging
+	case events.TypeMergeFailed:
+		b.mergeRequest(p("branch")).Status = MRFailed
+	case events.TypeMerged, events.TypeMergeSkipped:
+		delete(b.mq, p("branch"))
+	case events.TypeEscalationSent:
+		if id := p("escalation_id"); id != "" {
+			// Re-escalation: bump the severity of an existing escalation.
+			if esc := b.escalations[id]; esc != nil {
+				esc.Severity = p("new_severity")
+			}
+			return
+		}
+		// EscalationPayload carries the escalation bead ID in "rig".
+		id := p("rig")
+		b.escalations[id] = &Escalation{
+			ID:       id,
+			From:     e.Actor,
+			Severity: p("severity"),
+			Reason:   p("reason"),
+			Since:    e.Time,
+		}
+	case events.TypeEscalationAcked:
+		if esc := b.escalations[p("escalation_id")]; esc != nil {
+			esc.Acked = true
+		}
+	case events.TypeEscalationClosed:
+		delete(b.escalations, p("escalation_id"))
+	default:
+		if e.Actor != "" && e.Actor != "gt" {
+			b.agent(e.Actor, e)
+		}
+	}
+}
+
+func (b *builder) mergeRequest(branch string) *MergeRequest {
+	mr := b.mq[branch]
+	if mr == nil {
+		mr = &MergeRequest{Branch: branch}
+		b.mq[branch] = mr
+	}
+	return mr
+}
+
+func (b *builder) state(at time.Time) *State {
+	s := &State{At: at, MergeQueue: []MergeRequest{}, Escalations: []Escalation{}, Agents: []*AgentState{}}
+	for _, a := range b.agents {
+		s.Agents = append(s.Agents, a)
+	}
+	sort.Slice(s.Agents, func(i, j int) bool { return s.Agents[i].Address < s.Agents[j].Address })
+	for _, mr := range b.mq {
+		s.MergeQueue = append(s.MergeQueue, *mr)
+	}
+	sort.Slice(s.MergeQueue, func(i, j int) bool { return s.MergeQueue[i].Branch < s.MergeQueue[j].Branch })
+	for _, esc := range b.escalations {
+		s.Escalations = append(s.Escalations, *esc)
+	}
+	sort.Slice(s.Escalations, func(i, j int) bool { return s.Escalations[i].Since.Before(s.Escalations[j].Since) })
+	for _, src := range []string{SourceEvents, SourceTownlog} {
+		if b.sources[src] {
+			s.Sources = append(s.Sources, src)
+		}
+	}
+	return s
+}
+
+// Overlay replaces the parts of the state that Dolt history knows exactly
+// (hooks, convoys, escalations) with a point-in-time snapshot. Agent liveness
+// still comes from the timeline: beads do not record it. The merge queue is
+// merged rather than replaced: MR beads are usually ephemeral wisps, which
+// Dolt history never sees, so MRs known only from the timeline are kept.
+func (s *State) Overlay(snap *Snapshot) {
+	byAddress := make(map[string]*AgentState, len(s.Agents))
+	for _, a := range s.Agents {
+		a.Hook = ""
+		byAddress[a.Address] = a
+	}
+	for agent, bead := range snap.Hooks {
+		a := byAddress[agent]
+		if a == nil {
+			a = &AgentState{Address: agent}
+			byAddress[agent] = a
+			s.Agents = append(s.Agents, a)
+		}
+		a.Hook = bead
+	}
+	sort.Slice(s.Agents, func(i, j int) bool { return s.Agents[i].Address < s.Agents[j].Address })
+
+	s.Convoys = snap.Convoys
+	s.MergeQueue = mergeQueues(snap.MergeQueue, s.MergeQueue)
+	s.Escalations = snap.Escalations
+	s.Sources = append(s.Sources, SourceDolt)
+}
+
+// mergeQueues combines Dolt's merge queue with the timeline's. Dolt's entry
+// wins for an MR both know (matched by ID, else branch).
+func mergeQueues(dolt, timeline []MergeRequest) []MergeRequest {
+	out := append([]MergeRequest{}, dolt...)
+	ids := make(map[string]bool, len(dolt))
+	branches := make(map[string]bool, len(dolt))
+	for _, mr := range dolt {
+		if mr.ID != "" {
+			ids[mr.ID] = true
+		}
+		if mr.Branch != "" {
+			branches[mr.Branch] = true
+		}
+	}
+	for _, mr := range timeline {
+		if (mr.ID != "" && ids[mr.ID]) || branches[mr.Branch] {
+			continue
+		}
+		out = append(out, mr)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Branch < out[j].Branch })
+	return out
+}
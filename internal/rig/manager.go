@@ -30,8 +30,10 @@ var (
 
 // reservedRigNames are names that cannot be used for rigs because they
 // collide with town-level infrastructure. "hq" is special-cased by
-// EnsureMetadata and dolt routing as the town-level beads alias.
-var reservedRigNames = []string{"hq"}
+// EnsureMetadata and dolt routing as the town-level beads alias. "town" is
+// the rig component of mail-bridge certificates (CN "gt-town-<name>"), so a
+// rig with that name would mint polecat certs the proxy reads as peer towns.
+var reservedRigNames = []string{"hq", "town"}
 
 // wrapCloneError wraps clone errors with helpful suggestions.
 // Detects common auth failures and suggests SSH as an alternative.
@@ -235,6 +235,8 @@ func TestAddRig_RejectsInvalidNames(t *testing.T) {
 		{"op-baby-test", `rig name "op-baby-test" contains invalid characters`},
 		{"hq", `rig name "hq" is reserved for town-level infrastructure`},
 		{"HQ", `rig name "HQ" is reserved for town-level infrastructure`},
+		{"town", `rig name "town" is reserved for town-level infrastructure`},
+		{"Town", `rig name "Town" is reserved for town-level infrastructure`},
 	}
 
 	for _, tt := range tests {
@@ -990,6 +992,8 @@ func TestRegisterRig_RejectsReservedNames(t *testing.T) {
 	}{
 		{"hq", `rig name "hq" is reserved for town-level infrastructure`},
 		{"HQ", `rig name "HQ" is reserved for town-level infrastructure`},
+		{"town", `rig name "town" is reserved for town-level infrastructure`},
+		{"Town", `rig name "Town" is reserved for town-level infrastructure`},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,384 @@
+package rigtemplate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/hooks"
+)
+
+// ChangeKind is the part of a rig a change touches.
+type ChangeKind string
+
+const (
+	KindSetting ChangeKind = "setting"
+	KindHook    ChangeKind = "hook"
+	KindPlugin  ChangeKind = "plugin"
+)
+
+// Change is one difference between a rig and a template.
+type Change struct {
+	Kind ChangeKind
+	// Key names what changes: a settings path (merge_queue.test_command),
+	// a hook (<rig>/<role> <event> <matcher>) or a plugin name.
+	Key string
+	// Old is the rig's current value; empty when the rig has none.
+	Old string
+	// New is the template's value.
+	New string
+	// Conflict is set when the rig has customized Key to something else.
+	Conflict bool
+
+	path   []string // settings path
+	value  any      // settings value
+	target string   // hooks override target
+	event  string
+	entry  hooks.HookEntry
+	src    string // plugin source dir
+}
+
+// Plan is the set of changes applying a template to a rig would make.
+type Plan struct {
+	Template *Template
+	RigName  string
+	RigPath  string
+	Changes  []Change
+}
+
+// Conflicts returns the planned changes that would overwrite rig customizations.
+func (p *Plan) Conflicts() []Change {
+	var out []Change
+	for _, c := range p.Changes {
+		if c.Conflict {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// NewPlan compares a template against a rig. Settings the rig leaves at their
+// defaults (or unset) are plain changes; settings it has set to a different
+// value are conflicts. Hook entries conflict when the rig's override already
+// has the same matcher with different hooks, and plugins when the rig has its
+// own plugin of that name, which overrides the town plugin.
+func NewPlan(townRoot, rigName, rigPath string, t *Template) (*Plan, error) {
+	p := &Plan{Template: t, RigName: rigName, RigPath: rigPath}
+	if err := p.planSettings(); err != nil {
+		return nil, err
+	}
+	if err := p.planHooks(); err != nil {
+		return nil, err
+	}
+	if err := p.planPlugins(townRoot); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// currentSettings returns the rig's settings file as a generic map, or the
+// default scaffold when the rig has no settings file yet. The map is read
+// from the raw JSON so keys RigSettings doesn't know about survive a rewrite.
+func currentSettings(rigPath string) (map[string]any, error) {
+	path := config.RigSettingsPath(rigPath)
+	if _, err := config.LoadRigSettings(path); err != nil {
+		if !errors.Is(err, config.ErrNotFound) {
+			return nil, fmt.Errorf("loading rig settings: %w", err)
+		}
+		return toMap(config.NewRigSettings())
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the rig's settings file
+	if err != nil {
+		return nil, fmt.Errorf("reading rig settings: %w", err)
+	}
+	m := make(map[string]any)
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing rig settings: %w", err)
+	}
+	return m, nil
+}
+
+func (p *Plan) planSettings() error {
+	current, err := currentSettings(p.RigPath)
+	if err != nil {
+		return err
+	}
+	defaults, err := toMap(config.NewRigSettings())
+	if err != nil {
+		return err
+	}
+
+	paths, values := p.Template.settingChanges()
+	for i, path := range paths {
+		want := values[i]
+		have, ok := lookup(current, path)
+		if ok && reflect.DeepEqual(have, want) {
+			continue
+		}
+		c := Change{Kind: KindSetting, Key: strings.Join(path, "."), New: displayJSON(want), path: path, value: want}
+		if ok {
+			c.Old = displayJSON(have)
+			def, isDefault := lookup(defaults, path)
+			c.Conflict = !isDefault || !reflect.DeepEqual(have, def)
+		}
+		p.Changes = append(p.Changes, c)
+	}
+	return nil
+}
+
+func (p *Plan) planHooks() error {
+	for _, role := range sortedKeys(p.Template.Hooks) {
+		cfg := p.Template.Hooks[role]
+		if cfg == nil {
+			continue
+		}
+		target := p.RigName + "/" + role
+		existing, err := hooks.LoadOverride(target)
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("loading hooks override %s: %w", target, err)
+			}
+			existing = &hooks.HooksConfig{}
+		}
+
+		for _, event := range hooks.EventTypes {
+			for _, entry := range cfg.GetEntries(event) {
+				have, ok := findEntry(existing.GetEntries(event), entry.Matcher)
+				if ok && reflect.DeepEqual(have, entry) {
+					continue
+				}
+				if !ok && len(entry.Hooks) == 0 {
+					continue // disabling a matcher the rig doesn't have
+				}
+				c := Change{
+					Kind:   KindHook,
+					Key:    fmt.Sprintf("%s %s %q", target, event, entry.Matcher),
+					New:    hookCommands(entry),
+					target: target,
+					event:  event,
+					entry:  entry,
+				}
+				if ok {
+					c.Old = hookCommands(have)
+					c.Conflict = true
+				}
+				p.Changes = append(p.Changes, c)
+			}
+		}
+	}
+	return nil
+}
+
+// planPlugins enables town plugins in the rig's settings. The plugin is not
+// copied into the rig: a rig-level plugin of the same name would replace the
+// town plugin for every rig.
+func (p *Plan) planPlugins(townRoot string) error {
+	current, err := currentSettings(p.RigPath)
+	if err != nil {
+		return err
+	}
+	enabled := make(map[string]bool)
+	if list, ok := current["plugins"].([]any); ok {
+		for _, v := range list {
+			if name, ok := v.(string); ok {
+				enabled[name] = true
+			}
+		}
+	}
+
+	for _, name := range p.Template.Plugins {
+		src := filepath.Join(townRoot, "plugins", name)
+		if _, err := os.Stat(filepath.Join(src, "plugin.md")); err != nil {
+			return fmt.Errorf("plugin %q not found in %s", name, filepath.Join(townRoot, "plugins"))
+		}
+		if enabled[name] {
+			continue
+		}
+		c := Change{Kind: KindPlugin, Key: name, New: "enable plugins/" + name, src: src}
+		if _, err := os.Stat(filepath.Join(p.RigPath, "plugins", name)); err == nil {
+			// Enabling still happens when forced; the rig's copy is left alone.
+			c.Old = "rig has its own plugins/" + name
+			c.Conflict = true
+		}
+		p.Changes = append(p.Changes, c)
+	}
+	return nil
+}
+
+// Apply makes the planned changes. Conflicting changes are skipped unless
+// force is set. Every change is prepared and validated before anything is
+// written, so a bad setting, hook or plugin leaves the rig untouched.
+func (p *Plan) Apply(force bool) error {
+	var settings, hookChanges, plugins []Change
+	for _, c := range p.Changes {
+		if c.Conflict && !force {
+			continue
+		}
+		switch c.Kind {
+		case KindSetting:
+			settings = append(settings, c)
+		case KindHook:
+			hookChanges = append(hookChanges, c)
+		case KindPlugin:
+			plugins = append(plugins, c)
+		}
+	}
+
+	if err := preparePlugins(plugins); err != nil {
+		return err
+	}
+	settingsData, err := prepareSettings(p.RigPath, settings, plugins)
+	if err != nil {
+		return err
+	}
+	overrides, targets, err := prepareHooks(hookChanges)
+	if err != nil {
+		return err
+	}
+
+	if settingsData != nil {
+		path := config.RigSettingsPath(p.RigPath)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("creating settings directory: %w", err)
+		}
+		if err := os.WriteFile(path, settingsData, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
+			return fmt.Errorf("saving rig settings: %w", err)
+		}
+	}
+	for _, target := range targets {
+		if err := hooks.SaveOverride(target, overrides[target]); err != nil {
+			return fmt.Errorf("saving hooks override %s: %w", target, err)
+		}
+	}
+	return nil
+}
+
+// prepareSettings returns the rig's settings file with setting changes
+// applied and plugins enabled, or nil when there are none. The result is
+// checked by decoding it into RigSettings, but written from the generic map so
+// unknown keys are kept.
+func prepareSettings(rigPath string, changes, plugins []Change) ([]byte, error) {
+	if len(changes) == 0 && len(plugins) == 0 {
+		return nil, nil
+	}
+	current, err := currentSettings(rigPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range changes {
+		setPath(current, c.path, c.value)
+	}
+	if len(plugins) > 0 {
+		enabled, _ := current["plugins"].([]any)
+		for _, c := range plugins {
+			if !slices.Contains(enabled, any(c.Key)) {
+				enabled = append(enabled, c.Key)
+			}
+		}
+		current["plugins"] = enabled
+	}
+
+	data, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding settings: %w", err)
+	}
+	var settings config.RigSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("decoding settings: %w", err)
+	}
+	if err := config.ValidateRigSettings(&settings); err != nil {
+		return nil, fmt.Errorf("invalid rig settings: %w", err)
+	}
+	return data, nil
+}
+
+// prepareHooks merges hook changes into each target's existing override and
+// returns the merged overrides in the order their targets were first seen.
+func prepareHooks(changes []Change) (map[string]*hooks.HooksConfig, []string, error) {
+	additions := make(map[string]*hooks.HooksConfig)
+	var targets []string
+	for _, c := range changes {
+		ov, ok := additions[c.target]
+		if !ok {
+			if !hooks.ValidTarget(c.target) {
+				return nil, nil, fmt.Errorf("invalid hooks target %q", c.target)
+			}
+			ov = &hooks.HooksConfig{}
+			additions[c.target] = ov
+			targets = append(targets, c.target)
+		}
+		ov.AddEntry(c.event, c.entry)
+	}
+
+	merged := make(map[string]*hooks.HooksConfig, len(targets))
+	for _, target := range targets {
+		existing, err := hooks.LoadOverride(target)
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				return nil, nil, fmt.Errorf("loading hooks override %s: %w", target, err)
+			}
+			existing = &hooks.HooksConfig{}
+		}
+		cfg := hooks.Merge(existing, additions[target])
+		if _, err := hooks.MarshalConfig(cfg); err != nil {
+			return nil, nil, fmt.Errorf("encoding hooks override %s: %w", target, err)
+		}
+		merged[target] = cfg
+	}
+	return merged, targets, nil
+}
+
+// preparePlugins checks that every plugin being enabled still exists in the
+// town.
+func preparePlugins(changes []Change) error {
+	for _, c := range changes {
+		if _, err := os.Stat(filepath.Join(c.src, "plugin.md")); err != nil {
+			return fmt.Errorf("plugin %s: %w", c.Key, err)
+		}
+	}
+	return nil
+}
+
+func findEntry(entries []hooks.HookEntry, matcher string) (hooks.HookEntry, bool) {
+	for _, e := range entries {
+		if e.Matcher == matcher {
+			return e, true
+		}
+	}
+	return hooks.HookEntry{}, false
+}
+
+func hookCommands(e hooks.HookEntry) string {
+	if len(e.Hooks) == 0 {
+		return "(disabled)"
+	}
+	cmds := make([]string, len(e.Hooks))
+	for i, h := range e.Hooks {
+		cmds[i] = h.Command
+	}
+	return strings.Join(cmds, "; ")
+}
+
+func displayJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,287 @@
+// Package rigtemplate provides named rig templates: town-level bundles of rig
+// settings, hook overrides and plugins that are applied when a rig is added
+// (gt rig add --template) or later (gt rig apply-template).
+//
+// Templates live in <town>/settings/rig-templates/<name>.json:
+//
+//	{
+//	  "type": "rig-template",
+//	  "version": 1,
+//	  "description": "Go service with race-enabled tests",
+//	  "settings": {
+//	    "merge_queue": {"test_command": "go test -race ./...", "lint_command": "golangci-lint run"},
+//	    "namepool": {"style": "minerals"},
+//	    "role_agents": {"witness": "claude-haiku"}
+//	  },
+//	  "default_formula": "shiny",
+//	  "hooks": {"polecats": {"PreToolUse": [...]}},
+//	  "plugins": ["github-sheriff"]
+//	}
+//
+// settings is a partial rig settings/config.json: only the keys it names are
+// applied. hooks maps a rig role to a hooks override written for <rig>/<role>.
+// plugins names town plugins (<town>/plugins/<name>) that are enabled in the
+// rig settings' plugins list. They are not copied into the rig: a rig-level
+// plugin of the same name would replace the town plugin for every rig.
+//
+// Applying a template is a two-step Plan/Apply. A change conflicts with the
+// rig when the rig has already customized the same setting, hook matcher or
+// plugin to something else; conflicts are only overwritten when forced.
+package rigtemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/hooks"
+)
+
+// TemplateType is the type field of a rig template file.
+const TemplateType = "rig-template"
+
+// CurrentVersion is the current rig template schema version.
+const CurrentVersion = 1
+
+// ErrNotFound indicates the named template does not exist.
+var ErrNotFound = errors.New("rig template not found")
+
+// rigRoles are the roles a template may carry hook overrides for.
+var rigRoles = map[string]bool{"crew": true, "witness": true, "refinery": true, "polecats": true}
+
+// Template is a named bundle of rig configuration.
+type Template struct {
+	Type        string `json:"type"`
+	Version     int    `json:"version"`
+	Name        string `json:"name,omitempty"` // always the file name
+	Description string `json:"description,omitempty"`
+
+	// Settings is a partial rig settings/config.json.
+	Settings map[string]any `json:"settings,omitempty"`
+
+	// DefaultFormula sets workflow.default_formula.
+	DefaultFormula string `json:"default_formula,omitempty"`
+
+	// Hooks maps a rig role (crew, witness, refinery, polecats) to a hooks
+	// override for <rig>/<role>.
+	Hooks map[string]*hooks.HooksConfig `json:"hooks,omitempty"`
+
+	// Plugins names town plugins to enable for the rig.
+	Plugins []string `json:"plugins,omitempty"`
+}
+
+// Dir returns the directory holding a town's rig templates.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "rig-templates")
+}
+
+// Path returns the path of the named template.
+func Path(townRoot, name string) string {
+	return filepath.Join(Dir(townRoot), name+".json")
+}
+
+// Load reads and validates the named template.
+func Load(townRoot, name string) (*Template, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+	path := Path(townRoot, name)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the town settings dir
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s (looked in %s)", ErrNotFound, name, Dir(townRoot))
+		}
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+	t, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	t.Name = name
+	return t, nil
+}
+
+// List returns the town's valid templates sorted by name. Invalid template
+// files are reported on stderr and skipped.
+func List(townRoot string) ([]*Template, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading templates: %w", err)
+	}
+
+	var templates []*Template
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		t, err := Load(townRoot, name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping invalid rig template %s: %v\n", entry.Name(), err)
+			continue
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// Parse decodes and validates a template file.
+func Parse(data []byte) (*Template, error) {
+	var t Template
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&t); err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (t *Template) validate() error {
+	if t.Type != TemplateType {
+		return fmt.Errorf("%w: expected type %q, got %q", config.ErrInvalidType, TemplateType, t.Type)
+	}
+	if t.Version > CurrentVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", config.ErrInvalidVersion, t.Version, CurrentVersion)
+	}
+
+	if _, ok := t.Settings["type"]; ok {
+		return fmt.Errorf("settings: type cannot be set by a template")
+	}
+	if _, ok := t.Settings["version"]; ok {
+		return fmt.Errorf("settings: version cannot be set by a template")
+	}
+	// Decode strictly so a misspelled key fails here rather than being
+	// silently dropped from the rig's settings.
+	raw, err := json.Marshal(t.Settings)
+	if err != nil {
+		return fmt.Errorf("settings: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&config.RigSettings{}); err != nil {
+		return fmt.Errorf("settings: %w", err)
+	}
+	if t.DefaultFormula != "" {
+		if v, ok := lookup(t.Settings, settingPath("workflow.default_formula")); ok && v != t.DefaultFormula {
+			return fmt.Errorf("default_formula %q conflicts with settings.workflow.default_formula %v", t.DefaultFormula, v)
+		}
+	}
+
+	for role, cfg := range t.Hooks {
+		if !rigRoles[role] {
+			return fmt.Errorf("hooks: %q is not a rig role (want crew, witness, refinery or polecats)", role)
+		}
+		if cfg == nil {
+			continue
+		}
+		for _, event := range hooks.EventTypes {
+			seen := make(map[string]bool)
+			for _, entry := range cfg.GetEntries(event) {
+				if seen[entry.Matcher] {
+					return fmt.Errorf("hooks: duplicate matcher %q in %s.%s", entry.Matcher, role, event)
+				}
+				seen[entry.Matcher] = true
+			}
+		}
+	}
+	for _, name := range t.Plugins {
+		if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+			return fmt.Errorf("plugins: invalid plugin name %q", name)
+		}
+	}
+	return nil
+}
+
+// settingChanges returns the template's settings as leaf paths and values,
+// including default_formula, in a stable order.
+func (t *Template) settingChanges() ([][]string, []any) {
+	leaves := make(map[string]any)
+	var paths [][]string
+	flatten(t.Settings, nil, func(path []string, v any) {
+		paths = append(paths, path)
+		leaves[strings.Join(path, "\x00")] = v
+	})
+	if t.DefaultFormula != "" {
+		path := settingPath("workflow.default_formula")
+		if _, ok := leaves[strings.Join(path, "\x00")]; !ok {
+			paths = append(paths, path)
+			leaves[strings.Join(path, "\x00")] = t.DefaultFormula
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool { return strings.Join(paths[i], ".") < strings.Join(paths[j], ".") })
+
+	values := make([]any, len(paths))
+	for i, p := range paths {
+		values[i] = leaves[strings.Join(p, "\x00")]
+	}
+	return paths, values
+}
+
+func settingPath(dotted string) []string {
+	return strings.Split(dotted, ".")
+}
+
+// flatten calls fn for every non-object value in m. Arrays are leaves; empty
+// objects set nothing, so they never wipe a rig's existing map.
+func flatten(m map[string]any, prefix []string, fn func(path []string, v any)) {
+	for k, v := range m {
+		path := append(append([]string(nil), prefix...), k)
+		if sub, ok := v.(map[string]any); ok {
+			flatten(sub, path, fn)
+			continue
+		}
+		fn(path, v)
+	}
+}
+
+func lookup(m map[string]any, path []string) (any, bool) {
+	var cur any = m
+	for _, k := range path {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = obj[k]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func setPath(m map[string]any, path []string, v any) {
+	for _, k := range path[:len(path)-1] {
+		sub, ok := m[k].(map[string]any)
+		if !ok {
+			sub = make(map[string]any)
+			m[k] = sub
+		}
+		m = sub
+	}
+	m[path[len(path)-1]] = v
+}
+
+// toMap round-trips v through JSON into a generic map.
+func toMap(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]any)
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
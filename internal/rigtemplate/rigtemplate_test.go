@@ -0,0 +1,332 @@
+package rigtemplate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/hooks"
+)
+
+const goServiceTemplate = `{
+  "type": "rig-template",
+  "version": 1,
+  "description": "Go service",
+  "settings": {
+    "merge_queue": {"test_command": "go test -race ./...", "lint_command": "golangci-lint run"},
+    "namepool": {"style": "minerals"}
+  },
+  "default_formula": "shiny",
+  "hooks": {
+    "polecats": {
+      "PreToolUse": [{"matcher": "Bash(git push*)", "hooks": [{"type": "command", "command": "gt guard push"}]}]
+    }
+  },
+  "plugins": ["lint-sweep"]
+}`
+
+// setupTown creates a town with the go-service template, a lint-sweep town
+// plugin and an empty rig, and points hook overrides at a temp GT_HOME.
+func setupTown(t *testing.T) (townRoot, rigPath string) {
+	t.Helper()
+	t.Setenv("GT_HOME", t.TempDir())
+	townRoot = t.TempDir()
+	writeFile(t, Path(townRoot, "go-service"), goServiceTemplate)
+	writeFile(t, filepath.Join(townRoot, "plugins", "lint-sweep", "plugin.md"), "+++\nname = \"lint-sweep\"\n+++\nSweep lint.\n")
+	rigPath = filepath.Join(townRoot, "api")
+	if err := os.MkdirAll(rigPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot, rigPath
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func changeKeys(changes []Change) []string {
+	keys := make([]string, len(changes))
+	for i, c := range changes {
+		keys[i] = string(c.Kind) + " " + c.Key
+	}
+	return keys
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name, data, want string
+	}{
+		{"wrong type", `{"type": "rig"}`, "invalid config type"},
+		{"unknown field", `{"type": "rig-template", "plugin": ["x"]}`, "unknown field"},
+		{"misspelled setting", `{"type": "rig-template", "settings": {"merge_queu": {}}}`, "unknown field"},
+		{"settings type", `{"type": "rig-template", "settings": {"type": "x"}}`, "type cannot be set"},
+		{"non-rig hook role", `{"type": "rig-template", "hooks": {"mayor": {}}}`, "not a rig role"},
+		{"plugin path", `{"type": "rig-template", "plugins": ["../x"]}`, "invalid plugin name"},
+		{"formula clash", `{"type": "rig-template", "default_formula": "a", "settings": {"workflow": {"default_formula": "b"}}}`, "conflicts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAndList(t *testing.T) {
+	townRoot, _ := setupTown(t)
+	writeFile(t, Path(townRoot, "broken"), `{"type": "nope"}`)
+
+	tmpl, err := Load(townRoot, "go-service")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if tmpl.Name != "go-service" || tmpl.DefaultFormula != "shiny" {
+		t.Errorf("Load() = %+v", tmpl)
+	}
+	if _, err := Load(townRoot, "missing"); err == nil || !strings.Contains(err.Error(), ErrNotFound.Error()) {
+		t.Errorf("Load(missing) error = %v, want ErrNotFound", err)
+	}
+
+	templates, err := List(townRoot)
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(templates) != 1 || templates[0].Name != "go-service" {
+		t.Errorf("List() = %v, want only go-service", templates)
+	}
+}
+
+func TestPlanAndApply_FreshRig(t *testing.T) {
+	townRoot, rigPath := setupTown(t)
+	tmpl, err := Load(townRoot, "go-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := NewPlan(townRoot, "api", rigPath, tmpl)
+	if err != nil {
+		t.Fatalf("NewPlan() error: %v", err)
+	}
+	want := []string{
+		"setting merge_queue.lint_command",
+		"setting merge_queue.test_command",
+		"setting namepool.style",
+		"setting workflow.default_formula",
+		`hook api/polecats PreToolUse "Bash(git push*)"`,
+		"plugin lint-sweep",
+	}
+	if got := changeKeys(plan.Changes); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("changes =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if len(plan.Conflicts()) != 0 {
+		t.Errorf("fresh rig has conflicts: %v", changeKeys(plan.Conflicts()))
+	}
+
+	if err := plan.Apply(false); err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.MergeQueue.TestCommand != "go test -race ./..." || settings.MergeQueue.LintCommand != "golangci-lint run" {
+		t.Errorf("merge_queue = %+v", settings.MergeQueue)
+	}
+	if !settings.MergeQueue.Enabled || settings.MergeQueue.PollInterval != "30s" {
+		t.Errorf("merge_queue defaults lost: %+v", settings.MergeQueue)
+	}
+	if settings.Namepool.Style != "minerals" || settings.Workflow.DefaultFormula != "shiny" {
+		t.Errorf("settings = %+v", settings)
+	}
+
+	ov, err := hooks.LoadOverride("api/polecats")
+	if err != nil {
+		t.Fatalf("LoadOverride() error: %v", err)
+	}
+	if len(ov.PreToolUse) != 1 || ov.PreToolUse[0].Hooks[0].Command != "gt guard push" {
+		t.Errorf("override = %+v", ov)
+	}
+	if len(settings.Plugins) != 1 || settings.Plugins[0] != "lint-sweep" {
+		t.Errorf("plugins = %v, want lint-sweep enabled", settings.Plugins)
+	}
+	// Town plugins are enabled, not copied: a rig copy would replace the
+	// town plugin for every rig.
+	if _, err := os.Stat(filepath.Join(rigPath, "plugins")); !os.IsNotExist(err) {
+		t.Errorf("plugin copied into the rig: %v", err)
+	}
+
+	// Reapplying is a no-op.
+	again, err := NewPlan(townRoot, "api", rigPath, tmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Changes) != 0 {
+		t.Errorf("second plan = %v, want no changes", changeKeys(again.Changes))
+	}
+}
+
+func TestPlanAndApply_Conflicts(t *testing.T) {
+	townRoot, rigPath := setupTown(t)
+	tmpl, err := Load(townRoot, "go-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The rig has customized its test command and namepool, and already has
+	// its own push guard and lint-sweep plugin. Its lint command is unset.
+	settings := config.NewRigSettings()
+	settings.MergeQueue.TestCommand = "make test"
+	settings.Namepool.Style = "wasteland"
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := hooks.SaveOverride("api/polecats", &hooks.HooksConfig{
+		PreToolUse: []hooks.HookEntry{{Matcher: "Bash(git push*)", Hooks: []hooks.Hook{{Type: "command", Command: "./guard.sh"}}}},
+		Stop:       []hooks.HookEntry{{Matcher: "", Hooks: []hooks.Hook{{Type: "command", Command: "gt costs record"}}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(rigPath, "plugins", "lint-sweep", "plugin.md"), "local edit\n")
+
+	plan, err := NewPlan(townRoot, "api", rigPath, tmpl)
+	if err != nil {
+		t.Fatalf("NewPlan() error: %v", err)
+	}
+	wantConflicts := []string{
+		"setting merge_queue.test_command",
+		"setting namepool.style",
+		`hook api/polecats PreToolUse "Bash(git push*)"`,
+		"plugin lint-sweep",
+	}
+	if got := changeKeys(plan.Conflicts()); strings.Join(got, "\n") != strings.Join(wantConflicts, "\n") {
+		t.Errorf("conflicts =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(wantConflicts, "\n"))
+	}
+
+	if err := plan.Apply(false); err != nil {
+		t.Fatalf("Apply(false) error: %v", err)
+	}
+	got, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.MergeQueue.TestCommand != "make test" || got.Namepool.Style != "wasteland" {
+		t.Errorf("customizations overwritten without force: %+v %+v", got.MergeQueue, got.Namepool)
+	}
+	if got.MergeQueue.LintCommand != "golangci-lint run" {
+		t.Errorf("lint_command = %q, want template value", got.MergeQueue.LintCommand)
+	}
+	if len(got.Plugins) != 0 {
+		t.Errorf("plugin enabled without force over the rig's own copy: %v", got.Plugins)
+	}
+	if ov, _ := hooks.LoadOverride("api/polecats"); ov.PreToolUse[0].Hooks[0].Command != "./guard.sh" {
+		t.Errorf("hook overwritten without force: %+v", ov.PreToolUse)
+	}
+
+	if err := plan.Apply(true); err != nil {
+		t.Fatalf("Apply(true) error: %v", err)
+	}
+	got, err = config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.MergeQueue.TestCommand != "go test -race ./..." || got.Namepool.Style != "minerals" {
+		t.Errorf("force did not overwrite: %+v %+v", got.MergeQueue, got.Namepool)
+	}
+	ov, err := hooks.LoadOverride("api/polecats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ov.PreToolUse[0].Hooks[0].Command != "gt guard push" || len(ov.Stop) != 1 {
+		t.Errorf("override after force = %+v", ov)
+	}
+	if len(got.Plugins) != 1 || got.Plugins[0] != "lint-sweep" {
+		t.Errorf("plugins after force = %v, want lint-sweep enabled", got.Plugins)
+	}
+	data, err := os.ReadFile(filepath.Join(rigPath, "plugins", "lint-sweep", "plugin.md"))
+	if err != nil || string(data) != "local edit\n" {
+		t.Errorf("rig's own plugin changed by force: %q, %v", data, err)
+	}
+}
+
+func TestNewPlan_MissingPlugin(t *testing.T) {
+	townRoot, rigPath := setupTown(t)
+	tmpl := &Template{Type: TemplateType, Name: "x", Plugins: []string{"nope"}}
+	if _, err := NewPlan(townRoot, "api", rigPath, tmpl); err == nil || !strings.Contains(err.Error(), `plugin "nope" not found`) {
+		t.Errorf("NewPlan() error = %v, want missing plugin", err)
+	}
+}
+
+func TestApply_KeepsUnknownSettings(t *testing.T) {
+	townRoot, rigPath := setupTown(t)
+	tmpl, err := Load(townRoot, "go-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, config.RigSettingsPath(rigPath), `{"type": "rig-settings", "version": 1, "future_feature": {"on": true}, "merge_queue": {"enabled": true, "shiny_new_knob": 3}}`)
+
+	plan, err := NewPlan(townRoot, "api", rigPath, tmpl)
+	if err != nil {
+		t.Fatalf("NewPlan() error: %v", err)
+	}
+	if err := plan.Apply(false); err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+
+	data, err := os.ReadFile(config.RigSettingsPath(rigPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"future_feature"`, `"shiny_new_knob": 3`, `"test_command": "go test -race ./..."`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("settings missing %s:\n%s", want, data)
+		}
+	}
+}
+
+func TestApply_InvalidChangeWritesNothing(t *testing.T) {
+	townRoot, rigPath := setupTown(t)
+	tmpl, err := Load(townRoot, "go-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := NewPlan(townRoot, "api", rigPath, tmpl)
+	if err != nil {
+		t.Fatalf("NewPlan() error: %v", err)
+	}
+	path := []string{"merge_queue", "on_conflict"}
+	plan.Changes = append(plan.Changes, Change{Kind: KindSetting, Key: "merge_queue.on_conflict", path: path, value: "shrug"})
+
+	if err := plan.Apply(false); err == nil || !strings.Contains(err.Error(), "invalid rig settings") {
+		t.Fatalf("Apply() error = %v, want invalid rig settings", err)
+	}
+	if _, err := os.Stat(config.RigSettingsPath(rigPath)); !os.IsNotExist(err) {
+		t.Errorf("settings written: %v", err)
+	}
+	if _, err := hooks.LoadOverride("api/polecats"); !os.IsNotExist(err) {
+		t.Errorf("hooks override written: %v", err)
+	}
+	// A plugin that vanished after planning also stops the apply up front.
+	plan.Changes = plan.Changes[:len(plan.Changes)-1]
+	if err := os.RemoveAll(filepath.Join(townRoot, "plugins", "lint-sweep")); err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.Apply(false); err == nil || !strings.Contains(err.Error(), "plugin lint-sweep") {
+		t.Fatalf("Apply() error = %v, want missing plugin", err)
+	}
+	if _, err := os.Stat(config.RigSettingsPath(rigPath)); !os.IsNotExist(err) {
+		t.Errorf("settings written: %v", err)
+	}
+}
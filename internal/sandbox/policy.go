@@ -0,0 +1,324 @@
+// Package sandbox confines agent sessions with Linux namespaces.
+//
+// A sandboxed session command runs as "gt sandbox run", which relays the
+// policy's network endpoints over Unix sockets and starts "gt sandbox
+// inner" in new user, mount and network namespaces, either through
+// bubblewrap or through gt's own namespace setup. Inside, the filesystem
+// is read-only except for the policy's writable paths, and the only
+// network is the loopback, where the inner process listens on each
+// forwarded endpoint's port and relays back out over its socket.
+package sandbox
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/pty"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// EnvSandbox is set to the sandbox tool inside a sandboxed session.
+const EnvSandbox = "GT_SANDBOX"
+
+// maxSocketPath keeps relay socket paths under the sun_path limit.
+const maxSocketPath = 100
+
+// Policy is a resolved sandbox policy for one session, saved as JSON and
+// read by both sandbox stages.
+type Policy struct {
+	Session  string    `json:"session"`
+	TownRoot string    `json:"town_root"`
+	Tool     string    `json:"tool"`    // bwrap or unshare
+	Network  string    `json:"network"` // proxy, none or host
+	Writable []string  `json:"writable"`
+	ReadOnly []string  `json:"read_only,omitempty"` // under writable paths, kept read-only
+	Hidden   []string  `json:"hidden,omitempty"`
+	Forward  []Forward `json:"forward,omitempty"`
+}
+
+// Forward is a host:port endpoint reachable from inside the sandbox.
+type Forward struct {
+	Addr   string `json:"addr"`            // endpoint outside the sandbox
+	Port   string `json:"port"`            // loopback port inside the sandbox
+	Socket string `json:"socket"`          // Unix socket relaying between them
+	Proxy  bool   `json:"proxy,omitempty"` // the gt-proxy-server endpoint
+}
+
+// Status records a running sandbox, for gt doctor. The outer stage writes
+// it; inside the sandbox it is read-only.
+type Status struct {
+	Session string    `json:"session"`
+	PID     int       `json:"pid"` // the inner process, inside the namespaces
+	Tool    string    `json:"tool"`
+	Network string    `json:"network"`
+	Started time.Time `json:"started"`
+}
+
+// Options locate the session a policy is built for.
+type Options struct {
+	TownRoot string
+	RigPath  string
+	WorkDir  string
+	Session  string
+
+	// RuntimeConfigDir is the agent's config dir override, kept writable.
+	RuntimeConfigDir string
+}
+
+// StateDir returns the directory holding a town's sandbox policies and
+// status files.
+func StateDir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "sandbox")
+}
+
+// PolicyPath returns where a session's policy is saved.
+func PolicyPath(townRoot, session string) string {
+	return filepath.Join(StateDir(townRoot), session+".policy.json")
+}
+
+// StatusPath returns a session's sandbox status file.
+func StatusPath(townRoot, session string) string {
+	return filepath.Join(StateDir(townRoot), session+".json")
+}
+
+// socketDir returns the directory for relay sockets. Long town paths use
+// the user cache dir, which (unlike /tmp) is visible inside the sandbox.
+func socketDir(townRoot string) string {
+	dir := StateDir(townRoot)
+	if len(dir)+len("/gt-xxxxxxxxxxxxxxxxxxxxxxxx-9.sock") <= maxSocketPath {
+		return dir
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = filepath.Join(os.Getenv("HOME"), ".cache")
+	}
+	sum := sha256.Sum256([]byte(townRoot))
+	return filepath.Join(base, "gt-sandbox-"+hex.EncodeToString(sum[:6]))
+}
+
+// NewPolicy resolves a rig's sandbox settings into a policy for a session.
+func NewPolicy(cfg *config.SandboxConfig, opts Options) (*Policy, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	p := &Policy{Session: opts.Session, TownRoot: opts.TownRoot, Tool: cfg.ToolV(), Network: cfg.NetworkV()}
+	if p.Tool == config.SandboxToolAuto {
+		p.Tool = config.SandboxToolUnshare
+		if _, err := exec.LookPath("bwrap"); err == nil {
+			p.Tool = config.SandboxToolBwrap
+		}
+	}
+
+	if err := os.MkdirAll(StateDir(opts.TownRoot), 0755); err != nil {
+		return nil, fmt.Errorf("creating sandbox state dir: %w", err)
+	}
+	writable := []string{opts.WorkDir,
+		filepath.Join(opts.TownRoot, constants.DirRuntime),
+		filepath.Join(opts.RigPath, constants.DirRuntime)}
+	// The agent must not rewrite its own policy or status, nor plant git
+	// hooks or config that run outside the sandbox the next time anything
+	// uses the shared repository.
+	readOnly := []string{StateDir(opts.TownRoot)}
+	if common := gitCommonDir(opts.WorkDir); common != "" {
+		writable = append(writable, common)
+		hooks := filepath.Join(common, "hooks")
+		if err := os.MkdirAll(hooks, 0755); err != nil {
+			return nil, fmt.Errorf("creating git hooks dir: %w", err)
+		}
+		readOnly = append(readOnly, hooks, filepath.Join(common, "config"))
+	}
+	if opts.RuntimeConfigDir != "" {
+		writable = append(writable, opts.RuntimeConfigDir)
+	}
+	for _, w := range append(config.DefaultSandboxWritable, cfg.Writable...) {
+		writable = append(writable, expandPath(w, opts.RigPath))
+	}
+	p.Writable = existingPaths(writable)
+	p.ReadOnly = existingPaths(readOnly)
+	var hidden []string
+	for _, h := range cfg.Hidden {
+		hidden = append(hidden, expandPath(h, opts.RigPath))
+	}
+	// Session sockets drive sessions outside the sandbox: a nudge is
+	// keystrokes into the mayor's terminal. Connecting to a socket needs no
+	// write access to its mount, so they are hidden rather than read-only,
+	// and created now so sockets made later are hidden too.
+	for _, dir := range []string{pty.SocketDir(opts.TownRoot), tmux.SocketDir()} {
+		if p.privateTmp() && underTmp(dir) {
+			continue // gone with the host /tmp
+		}
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, fmt.Errorf("creating session socket dir: %w", err)
+		}
+		hidden = append(hidden, dir)
+	}
+	p.Hidden = existingPaths(hidden)
+
+	if p.Network != config.SandboxNetworkHost {
+		endpoints := cfg.Forward
+		proxy := ""
+		if p.Network == config.SandboxNetworkProxy {
+			proxy = cfg.ProxyEndpoint()
+			if proxy != "" {
+				endpoints = append([]string{proxy}, endpoints...)
+			}
+		}
+		dir := socketDir(opts.TownRoot)
+		for i, addr := range endpoints {
+			_, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, fmt.Errorf("%w: endpoint %q: %v", config.ErrInvalidSandbox, addr, err)
+			}
+			p.Forward = append(p.Forward, Forward{
+				Addr:   addr,
+				Port:   port,
+				Socket: filepath.Join(dir, opts.Session+"-"+strconv.Itoa(i)+".sock"),
+				Proxy:  i == 0 && proxy != "",
+			})
+		}
+	}
+	return p, nil
+}
+
+// privateTmp reports whether the sandbox gets an empty /tmp of its own.
+// When a writable path lives under /tmp, the host /tmp stays visible (and
+// writable) instead, since a fresh tmpfs would hide it.
+func (p *Policy) privateTmp() bool {
+	for _, w := range p.Writable {
+		if underTmp(w) {
+			return false
+		}
+	}
+	return true
+}
+
+// underTmp reports whether path is /tmp or inside it.
+func underTmp(path string) bool {
+	return path == "/tmp" || strings.HasPrefix(path, "/tmp/")
+}
+
+// Save writes the policy to path.
+func (p *Policy) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.AtomicWriteFile(path, append(data, '\n'), 0644)
+}
+
+// LoadPolicy reads a saved policy.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is passed by gt itself
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing sandbox policy %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// Wrap builds and saves a session's policy and returns command wrapped to
+// run under it via "gt sandbox run".
+func Wrap(cfg *config.SandboxConfig, opts Options, command string) (string, error) {
+	if err := Supported(); err != nil {
+		return "", err
+	}
+	p, err := NewPolicy(cfg, opts)
+	if err != nil {
+		return "", err
+	}
+	path := PolicyPath(opts.TownRoot, opts.Session)
+	if err := p.Save(path); err != nil {
+		return "", fmt.Errorf("saving sandbox policy: %w", err)
+	}
+	gt, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("finding gt executable: %w", err)
+	}
+	return WrapCommand(gt, path, command), nil
+}
+
+// WrapCommand returns the shell command running command under the saved
+// policy at policyPath.
+func WrapCommand(gt, policyPath, command string) string {
+	return fmt.Sprintf("exec %s sandbox run --policy %s -- sh -c %s",
+		config.ShellQuote(gt), config.ShellQuote(policyPath), config.ShellQuote(command))
+}
+
+// ReadStatus reads a session's sandbox status file.
+func ReadStatus(townRoot, session string) (*Status, error) {
+	data, err := os.ReadFile(StatusPath(townRoot, session))
+	if err != nil {
+		return nil, err
+	}
+	var st Status
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func writeStatus(townRoot string, st *Status) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return util.AtomicWriteFile(StatusPath(townRoot, st.Session), append(data, '\n'), 0644)
+}
+
+// gitCommonDir returns the shared git directory of a worktree, which
+// commits write to, or "" if dir isn't in a git repository.
+func gitCommonDir(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--path-format=absolute", "--git-common-dir").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// expandPath expands "~/" and resolves relative paths against base.
+func expandPath(p, base string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(p, "~"))
+		}
+	}
+	if !filepath.IsAbs(p) {
+		return filepath.Join(base, p)
+	}
+	return filepath.Clean(p)
+}
+
+// existingPaths drops duplicates and paths that don't exist; those can't
+// be bind-mounted.
+func existingPaths(paths []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		if _, err := os.Stat(p); err == nil {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,195 @@
+package sandbox
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/pty"
+)
+
+// setupRig creates a town with a rig and a polecat worktree directory.
+func setupRig(t *testing.T) (townRoot, rigPath, workDir string) {
+	t.Helper()
+	townRoot = t.TempDir()
+	rigPath = filepath.Join(townRoot, "gastown")
+	workDir = filepath.Join(rigPath, "polecats", "furiosa")
+	for _, dir := range []string{workDir, filepath.Join(rigPath, ".runtime"), filepath.Join(townRoot, ".runtime"), filepath.Join(rigPath, "cache")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return townRoot, rigPath, workDir
+}
+
+func TestNewPolicyWritable(t *testing.T) {
+	townRoot, rigPath, workDir := setupRig(t)
+	cfg := &config.SandboxConfig{
+		Tool:     config.SandboxToolUnshare,
+		Network:  config.SandboxNetworkHost,
+		Writable: []string{"cache", "missing"},
+		Hidden:   []string{"polecats"},
+	}
+	p, err := NewPolicy(cfg, Options{TownRoot: townRoot, RigPath: rigPath, WorkDir: workDir, Session: "gt-furiosa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{workDir, filepath.Join(townRoot, ".runtime"), filepath.Join(rigPath, ".runtime"), filepath.Join(rigPath, "cache")} {
+		if !slices.Contains(p.Writable, want) {
+			t.Errorf("Writable %v missing %s", p.Writable, want)
+		}
+	}
+	if slices.Contains(p.Writable, filepath.Join(rigPath, "missing")) {
+		t.Errorf("Writable should skip paths that don't exist: %v", p.Writable)
+	}
+	for _, want := range []string{filepath.Join(rigPath, "polecats"), pty.SocketDir(townRoot)} {
+		if !slices.Contains(p.Hidden, want) {
+			t.Errorf("Hidden %v missing %s", p.Hidden, want)
+		}
+	}
+	if len(p.Forward) != 0 {
+		t.Errorf("host network should forward nothing, got %v", p.Forward)
+	}
+	if _, err := os.Stat(StateDir(townRoot)); err != nil {
+		t.Errorf("state dir not created: %v", err)
+	}
+}
+
+func TestNewPolicyGitCommonDir(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	townRoot, rigPath, workDir := setupRig(t)
+	if out, err := exec.Command("git", "init", "-q", workDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	p, err := NewPolicy(&config.SandboxConfig{}, Options{TownRoot: townRoot, RigPath: rigPath, WorkDir: workDir, Session: "gt-furiosa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(workDir, ".git")
+	if resolved, err := filepath.EvalSymlinks(want); err == nil {
+		want = resolved
+	}
+	found := false
+	for _, w := range p.Writable {
+		if resolved, err := filepath.EvalSymlinks(w); err == nil && resolved == want {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Writable %v missing git dir %s", p.Writable, want)
+	}
+	for _, name := range []string{"hooks", "config"} {
+		found := false
+		for _, r := range p.ReadOnly {
+			if resolved, err := filepath.EvalSymlinks(r); err == nil && resolved == filepath.Join(want, name) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("ReadOnly %v missing git %s", p.ReadOnly, name)
+		}
+	}
+	if !slices.Contains(p.ReadOnly, StateDir(townRoot)) {
+		t.Errorf("ReadOnly %v missing the sandbox state dir", p.ReadOnly)
+	}
+}
+
+func TestNewPolicyForwards(t *testing.T) {
+	townRoot, rigPath, workDir := setupRig(t)
+	opts := Options{TownRoot: townRoot, RigPath: rigPath, WorkDir: workDir, Session: "gt-furiosa"}
+
+	t.Run("proxy", func(t *testing.T) {
+		t.Setenv("GT_PROXY_URL", "https://10.0.0.1:9876")
+		p, err := NewPolicy(&config.SandboxConfig{Forward: []string{"127.0.0.1:3307"}}, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(p.Forward) != 2 {
+			t.Fatalf("Forward = %v, want proxy and one endpoint", p.Forward)
+		}
+		if f := p.Forward[0]; f.Addr != "10.0.0.1:9876" || f.Port != "9876" || !f.Proxy {
+			t.Errorf("proxy forward = %+v", f)
+		}
+		if f := p.Forward[1]; f.Addr != "127.0.0.1:3307" || f.Port != "3307" || f.Proxy {
+			t.Errorf("endpoint forward = %+v", f)
+		}
+		if p.Forward[0].Socket == p.Forward[1].Socket {
+			t.Errorf("forwards share a socket: %s", p.Forward[0].Socket)
+		}
+	})
+
+	t.Run("none drops the proxy", func(t *testing.T) {
+		t.Setenv("GT_PROXY_URL", "https://10.0.0.1:9876")
+		p, err := NewPolicy(&config.SandboxConfig{Network: config.SandboxNetworkNone, Forward: []string{"127.0.0.1:3307"}}, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(p.Forward) != 1 || p.Forward[0].Proxy {
+			t.Errorf("Forward = %v, want only the explicit endpoint", p.Forward)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := NewPolicy(&config.SandboxConfig{Tool: "docker"}, opts); err == nil {
+			t.Error("expected error for unknown tool")
+		}
+	})
+}
+
+func TestSocketDirStaysShort(t *testing.T) {
+	long := "/" + strings.Repeat("very-long-town-name/", 8)
+	dir := socketDir(long)
+	if strings.HasPrefix(dir, long) {
+		t.Errorf("socketDir(%q) = %q, should move out of a long town root", long, dir)
+	}
+	if short := "/gt"; socketDir(short) != StateDir(short) {
+		t.Errorf("short town roots should keep sockets in the state dir")
+	}
+}
+
+func TestPrivateTmp(t *testing.T) {
+	if !(&Policy{Writable: []string{"/home/me/town"}}).privateTmp() {
+		t.Error("expected a private /tmp")
+	}
+	if (&Policy{Writable: []string{"/tmp/town/rig"}}).privateTmp() {
+		t.Error("writable path under /tmp should keep the host /tmp")
+	}
+	if !(&Policy{Writable: []string{"/tmpfoo"}}).privateTmp() {
+		t.Error("/tmpfoo is not under /tmp")
+	}
+}
+
+func TestPolicySaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "p.policy.json")
+	want := &Policy{
+		Session: "gt-furiosa", TownRoot: "/town", Tool: config.SandboxToolBwrap, Network: config.SandboxNetworkProxy,
+		Writable: []string{"/town/gastown/polecats/furiosa"},
+		Forward:  []Forward{{Addr: "10.0.0.1:9876", Port: "9876", Socket: "/town/.runtime/sandbox/gt-furiosa-0.sock", Proxy: true}},
+	}
+	if err := want.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadPolicy() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWrapCommand(t *testing.T) {
+	got := WrapCommand("/usr/local/bin/gt", "/town/.runtime/sandbox/gt-furiosa.policy.json", "export A='x y' && claude")
+	want := `exec /usr/local/bin/gt sandbox run --policy /town/.runtime/sandbox/gt-furiosa.policy.json -- sh -c 'export A='\''x y'\'' && claude'`
+	if got != want {
+		t.Errorf("WrapCommand() =\n  %s\nwant\n  %s", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package sandbox
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// probeAddr is an outside address a sandbox without network must not reach.
+const probeAddr = "1.1.1.1:53"
+
+// ProbeResult is one sandbox self-test.
+type ProbeResult struct {
+	Name string `json:"name"`
+	OK   bool   `json:"ok"`
+	Info string `json:"info,omitempty"`
+}
+
+// Probe tests, from inside a sandbox, that the policy is in force: the
+// first writable path accepts writes, readOnly rejects them and, unless
+// the network is "host", outside addresses are unreachable.
+func Probe(p *Policy, readOnly string) []ProbeResult {
+	var results []ProbeResult
+	add := func(name string, ok bool, format string, args ...any) {
+		results = append(results, ProbeResult{Name: name, OK: ok, Info: fmt.Sprintf(format, args...)})
+	}
+
+	add("in-sandbox", os.Getenv(EnvSandbox) != "", "%s=%q", EnvSandbox, os.Getenv(EnvSandbox))
+
+	if len(p.Writable) > 0 {
+		err := tryWrite(p.Writable[0])
+		add("writable", err == nil, "%s: %v", p.Writable[0], errOrOK(err))
+	}
+	err := tryWrite(readOnly)
+	add("read-only", err != nil, "%s: %v", readOnly, errOrOK(err))
+
+	if p.Network != config.SandboxNetworkHost {
+		conn, err := net.DialTimeout("tcp", probeAddr, 2*time.Second)
+		if conn != nil {
+			_ = conn.Close()
+		}
+		add("network", err != nil, "%s: %v", probeAddr, errOrOK(err))
+	}
+	return results
+}
+
+func tryWrite(dir string) error {
+	path := filepath.Join(dir, fmt.Sprintf(".gt-sandbox-probe-%d", os.Getpid()))
+	if err := os.WriteFile(path, []byte("probe\n"), 0600); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func errOrOK(err error) any {
+	if err == nil {
+		return "allowed"
+	}
+	return err
+}
@@ -0,0 +1,43 @@
+package sandbox
+
+import (
+	"io"
+	"net"
+	"sync"
+)
+
+// relay accepts connections on ln and pipes each to a fresh connection
+// from dial, until ln is closed.
+func relay(ln net.Listener, dial func() (net.Conn, error)) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			upstream, err := dial()
+			if err != nil {
+				return
+			}
+			defer upstream.Close()
+			pipe(conn, upstream)
+		}()
+	}
+}
+
+// pipe copies in both directions until both sides are done.
+func pipe(a, b net.Conn) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	copyHalf := func(dst, src net.Conn) {
+		defer wg.Done()
+		_, _ = io.Copy(dst, src)
+		if cw, ok := dst.(interface{ CloseWrite() error }); ok {
+			_ = cw.CloseWrite()
+		}
+	}
+	go copyHalf(a, b)
+	go copyHalf(b, a)
+	wg.Wait()
+}
@@ -0,0 +1,348 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Supported reports whether sandboxing is available on this platform.
+func Supported() error {
+	return nil
+}
+
+// Run is the outer sandbox stage ("gt sandbox run"). It relays the
+// policy's endpoints, starts the inner stage inside the namespaces and
+// returns the command's exit code.
+func Run(policyPath string, args []string) (int, error) {
+	p, err := LoadPolicy(policyPath)
+	if err != nil {
+		return 1, err
+	}
+	for _, f := range p.Forward {
+		if err := os.MkdirAll(filepath.Dir(f.Socket), 0700); err != nil {
+			return 1, err
+		}
+		_ = os.Remove(f.Socket)
+		ln, err := net.Listen("unix", f.Socket)
+		if err != nil {
+			return 1, fmt.Errorf("relaying %s: %w", f.Addr, err)
+		}
+		defer os.Remove(f.Socket)
+		defer ln.Close()
+		addr := f.Addr
+		go relay(ln, func() (net.Conn, error) { return net.DialTimeout("tcp", addr, 10*time.Second) })
+	}
+
+	gt, err := os.Executable()
+	if err != nil {
+		return 1, err
+	}
+	inner := append([]string{"sandbox", "inner", "--policy", policyPath, "--"}, args...)
+
+	var cmd *exec.Cmd
+	var info *os.File // bwrap reports its sandboxed child's pid here
+	switch p.Tool {
+	case config.SandboxToolBwrap:
+		r, w, err := os.Pipe()
+		if err != nil {
+			return 1, err
+		}
+		defer r.Close()
+		info = r
+		bwrap := append(bwrapArgs(p), "--info-fd", "3", "--", gt)
+		cmd = exec.Command("bwrap", append(bwrap, inner...)...) //nolint:gosec // G204: arguments come from the saved policy
+		cmd.ExtraFiles = []*os.File{w}
+	case config.SandboxToolUnshare:
+		cmd = exec.Command(gt, append([]string{"sandbox", "inner", "--setup"}, inner[2:]...)...) //nolint:gosec // G204: re-executes gt itself
+		cmd.SysProcAttr = unshareAttr(p)
+	default:
+		return 1, fmt.Errorf("%w: unknown tool %q", config.ErrInvalidSandbox, p.Tool)
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	err = cmd.Start()
+	if info != nil {
+		_ = cmd.ExtraFiles[0].Close()
+	}
+	if err != nil {
+		return 1, fmt.Errorf("starting sandbox (%s): %w", p.Tool, err)
+	}
+
+	// Record the inner stage's pid, which gt doctor checks is really in the
+	// namespaces. It is written from out here because the state dir is
+	// read-only inside.
+	pid := cmd.Process.Pid
+	if info != nil {
+		var child struct {
+			PID int `json:"child-pid"`
+		}
+		if err := json.NewDecoder(info).Decode(&child); err == nil {
+			pid = child.PID
+		}
+	}
+	_ = writeStatus(p.TownRoot, &Status{Session: p.Session, PID: pid, Tool: p.Tool, Network: p.Network, Started: time.Now()})
+	defer os.Remove(StatusPath(p.TownRoot, p.Session))
+	return waitForwardingSignals(cmd), nil
+}
+
+// bwrapArgs translates a policy into bubblewrap arguments.
+func bwrapArgs(p *Policy) []string {
+	args := []string{"--die-with-parent", "--ro-bind", "/", "/", "--dev", "/dev", "--proc", "/proc"}
+	if p.privateTmp() {
+		args = append(args, "--tmpfs", "/tmp")
+	} else {
+		args = append(args, "--bind", "/tmp", "/tmp")
+	}
+	for _, w := range p.Writable {
+		args = append(args, "--bind", w, w)
+	}
+	for _, r := range p.ReadOnly {
+		args = append(args, "--ro-bind", r, r)
+	}
+	for _, h := range p.Hidden {
+		if fi, err := os.Stat(h); err == nil && !fi.IsDir() {
+			args = append(args, "--ro-bind", "/dev/null", h)
+		} else {
+			args = append(args, "--tmpfs", h)
+		}
+	}
+	if p.Network != config.SandboxNetworkHost {
+		args = append(args, "--unshare-net")
+	}
+	return args
+}
+
+// unshareAttr puts the inner stage in new user and mount (and network)
+// namespaces with just enough capability to set them up. The caller's uid
+// is mapped to itself, except root, which is mapped to an unprivileged uid
+// so the agent can't regain capabilities inside the namespace.
+func unshareAttr(p *Policy) *syscall.SysProcAttr {
+	uid, gid := os.Getuid(), os.Getgid()
+	innerUID, innerGID := uid, gid
+	if uid == 0 {
+		innerUID, innerGID = 65534, 65534
+	}
+	flags := uintptr(syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS)
+	if p.Network != config.SandboxNetworkHost {
+		flags |= syscall.CLONE_NEWNET
+	}
+	return &syscall.SysProcAttr{
+		Cloneflags:  flags,
+		UidMappings: []syscall.SysProcIDMap{{ContainerID: innerUID, HostID: uid, Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{{ContainerID: innerGID, HostID: gid, Size: 1}},
+		AmbientCaps: []uintptr{unix.CAP_SYS_ADMIN, unix.CAP_NET_ADMIN},
+		Pdeathsig:   syscall.SIGKILL,
+	}
+}
+
+// Inner is the inner sandbox stage ("gt sandbox inner"), running inside
+// the namespaces. With setup, it first makes the filesystem read-only
+// (bubblewrap has already done so otherwise). It serves the forwarded
+// endpoints on the loopback and runs the command.
+func Inner(policyPath string, setup bool, args []string) (int, error) {
+	p, err := LoadPolicy(policyPath)
+	if err != nil {
+		return 1, err
+	}
+	if setup {
+		if err := setupMounts(p); err != nil {
+			return 1, fmt.Errorf("sandbox mounts: %w", err)
+		}
+		if p.Network != config.SandboxNetworkHost {
+			if err := loopbackUp(); err != nil {
+				return 1, fmt.Errorf("sandbox loopback: %w", err)
+			}
+		}
+	}
+
+	env := append(os.Environ(), EnvSandbox+"="+p.Tool)
+	for _, f := range p.Forward {
+		ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", f.Port))
+		if err != nil {
+			return 1, fmt.Errorf("forwarding %s: %w", f.Addr, err)
+		}
+		defer ln.Close()
+		socket := f.Socket
+		go relay(ln, func() (net.Conn, error) { return net.Dial("unix", socket) })
+		if f.Proxy {
+			env = append(env, "GT_PROXY_URL=https://"+net.JoinHostPort("127.0.0.1", f.Port))
+		}
+	}
+
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // G204: the session command
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Env = env
+
+	// Capabilities are per thread: drop ours on the thread that forks the
+	// command so it starts with none and can't gain any.
+	runtime.LockOSThread()
+	if setup {
+		if err := unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_CLEAR_ALL, 0, 0, 0); err != nil {
+			return 1, fmt.Errorf("dropping capabilities: %w", err)
+		}
+	}
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return 1, fmt.Errorf("setting no_new_privs: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return 127, err
+	}
+	return waitForwardingSignals(cmd), nil
+}
+
+// setupMounts makes every mount read-only except the writable paths and
+// /tmp, covers the hidden paths, and then makes the read-only paths under
+// writable ones read-only again.
+func setupMounts(p *Policy) error {
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("making mounts private: %w", err)
+	}
+	for _, w := range p.Writable {
+		if err := unix.Mount(w, w, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+			return fmt.Errorf("binding %s: %w", w, err)
+		}
+	}
+	if p.privateTmp() {
+		if err := unix.Mount("tmpfs", "/tmp", "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, "mode=1777"); err != nil {
+			return fmt.Errorf("mounting /tmp: %w", err)
+		}
+	} else if err := unix.Mount("/tmp", "/tmp", "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+		// A mount of its own, so it can be made writable below.
+		return fmt.Errorf("binding /tmp: %w", err)
+	}
+	for _, h := range p.Hidden {
+		var err error
+		if fi, statErr := os.Stat(h); statErr == nil && !fi.IsDir() {
+			err = unix.Mount("/dev/null", h, "", unix.MS_BIND, "")
+		} else {
+			err = unix.Mount("tmpfs", h, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, "mode=0700")
+		}
+		if err != nil {
+			return fmt.Errorf("hiding %s: %w", h, err)
+		}
+	}
+
+	if err := unix.MountSetattr(-1, "/", unix.AT_RECURSIVE, &unix.MountAttr{Attr_set: unix.MOUNT_ATTR_RDONLY}); err != nil {
+		return fmt.Errorf("making / read-only: %w", err)
+	}
+	for _, w := range append([]string{"/tmp", "/dev/shm"}, p.Writable...) {
+		err := unix.MountSetattr(-1, w, unix.AT_RECURSIVE, &unix.MountAttr{Attr_clr: unix.MOUNT_ATTR_RDONLY})
+		if err != nil && w != "/dev/shm" {
+			return fmt.Errorf("making %s writable: %w", w, err)
+		}
+	}
+	for _, r := range p.ReadOnly {
+		if err := unix.Mount(r, r, "", unix.MS_BIND, ""); err != nil {
+			return fmt.Errorf("binding %s: %w", r, err)
+		}
+		if err := unix.MountSetattr(-1, r, 0, &unix.MountAttr{Attr_set: unix.MOUNT_ATTR_RDONLY}); err != nil {
+			return fmt.Errorf("making %s read-only: %w", r, err)
+		}
+	}
+	return nil
+}
+
+// loopbackUp brings up lo in a new network namespace.
+func loopbackUp() error {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+	ifr, err := unix.NewIfreq("lo")
+	if err != nil {
+		return err
+	}
+	if err := unix.IoctlIfreq(fd, unix.SIOCGIFFLAGS, ifr); err != nil {
+		return err
+	}
+	ifr.SetUint16(ifr.Uint16() | unix.IFF_UP)
+	return unix.IoctlIfreq(fd, unix.SIOCSIFFLAGS, ifr)
+}
+
+// waitForwardingSignals waits for cmd, passing termination signals on to
+// it, and returns its exit code.
+func waitForwardingSignals(cmd *exec.Cmd) int {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
+	defer signal.Stop(sigs)
+	go func() {
+		for sig := range sigs {
+			_ = cmd.Process.Signal(sig)
+		}
+	}()
+
+	err := cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			return 128 + int(ws.Signal())
+		}
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		return 1
+	}
+	return 0
+}
+
+// IsInnerStage reports whether pid is the inner stage ("gt sandbox inner")
+// running the saved policy at policyPath.
+func IsInnerStage(pid int, policyPath string) error {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return fmt.Errorf("reading command line of pid %d: %w", pid, err)
+	}
+	args := strings.Split(strings.TrimSuffix(string(data), "\x00"), "\x00")
+	inner := false
+	for i, a := range args {
+		if a == "--" {
+			break
+		}
+		if i > 0 && a == "inner" && args[i-1] == "sandbox" {
+			inner = true
+		}
+		if inner && a == "--policy" && i+1 < len(args) && args[i+1] == policyPath {
+			return nil
+		}
+	}
+	return fmt.Errorf("pid %d is not the sandbox for %s", pid, filepath.Base(policyPath))
+}
+
+// Confined reports whether pid runs in different mount (and, if network
+// is true, network) namespaces than the calling process.
+func Confined(pid int, network bool) error {
+	kinds := []string{"mnt"}
+	if network {
+		kinds = append(kinds, "net")
+	}
+	for _, ns := range kinds {
+		theirs, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/%s", pid, ns))
+		if err != nil {
+			return fmt.Errorf("reading %s namespace of pid %d: %w", ns, pid, err)
+		}
+		ours, err := os.Readlink("/proc/self/ns/" + ns)
+		if err != nil {
+			return err
+		}
+		if theirs == ours {
+			return fmt.Errorf("pid %d shares gt's %s namespace", pid, ns)
+		}
+	}
+	return nil
+}
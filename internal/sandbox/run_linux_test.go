@@ -0,0 +1,214 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/pty"
+)
+
+func TestBwrapArgs(t *testing.T) {
+	dir := t.TempDir()
+	hiddenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(hiddenFile, []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	p := &Policy{
+		Tool:     config.SandboxToolBwrap,
+		Network:  config.SandboxNetworkProxy,
+		Writable: []string{"/home/me/town/rig/polecats/furiosa", "/home/me/town/.runtime"},
+		ReadOnly: []string{"/home/me/town/.runtime/sandbox"},
+		Hidden:   []string{dir + "/ssh", hiddenFile},
+	}
+	args := strings.Join(bwrapArgs(p), " ")
+	for _, want := range []string{
+		"--ro-bind / /",
+		"--tmpfs /tmp",
+		"--bind /home/me/town/rig/polecats/furiosa /home/me/town/rig/polecats/furiosa",
+		"--tmpfs " + dir + "/ssh",
+		"--ro-bind /dev/null " + hiddenFile,
+		"--unshare-net",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("bwrap args %q missing %q", args, want)
+		}
+	}
+
+	if rw, ro := strings.Index(args, "--bind /home/me/town/.runtime "), strings.Index(args, "--ro-bind /home/me/town/.runtime/sandbox "); ro < rw {
+		t.Errorf("read-only paths must be bound after the writable ones: %q", args)
+	}
+
+	p.Network = config.SandboxNetworkHost
+	p.Writable = []string{"/tmp/town"}
+	got := bwrapArgs(p)
+	if slices.Contains(got, "--unshare-net") {
+		t.Error("host network should not unshare the network")
+	}
+	if !strings.Contains(strings.Join(got, " "), "--bind /tmp /tmp") {
+		t.Errorf("writable path under /tmp should keep the host /tmp: %v", got)
+	}
+}
+
+// TestMain lets the test binary stand in for gt: Run re-executes
+// os.Executable as "sandbox inner". As "dial <socket>" it exits 0 only if
+// it can connect to the Unix socket.
+func TestMain(m *testing.M) {
+	if len(os.Args) == 3 && os.Args[1] == "dial" {
+		conn, err := net.Dial("unix", os.Args[2])
+		if err != nil {
+			os.Exit(1)
+		}
+		_ = conn.Close()
+		os.Exit(0)
+	}
+	if len(os.Args) > 2 && os.Args[1] == "sandbox" && os.Args[2] == "inner" {
+		var policy string
+		setup := false
+		args := os.Args[3:]
+		for len(args) > 0 && args[0] != "--" {
+			switch args[0] {
+			case "--setup":
+				setup = true
+			case "--policy":
+				args = args[1:]
+				policy = args[0]
+			}
+			args = args[1:]
+		}
+		code, err := Inner(policy, setup, args[1:])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(code)
+	}
+	os.Exit(m.Run())
+}
+
+func TestRun_StateAndGitHooksReadOnly(t *testing.T) {
+	if err := exec.Command("unshare", "-Urm", "true").Run(); err != nil {
+		t.Skip("user namespaces unavailable")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	townRoot, rigPath, workDir := setupRig(t)
+	if out, err := exec.Command("git", "init", "-q", workDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	cfg := &config.SandboxConfig{Tool: config.SandboxToolUnshare, Network: config.SandboxNetworkHost}
+	p, err := NewPolicy(cfg, Options{TownRoot: townRoot, RigPath: rigPath, WorkDir: workDir, Session: "gt-furiosa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyPath := PolicyPath(townRoot, "gt-furiosa")
+	if err := p.Save(policyPath); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(policyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := config.ShellQuote
+	script := strings.Join([]string{
+		"touch " + q(filepath.Join(workDir, "ok")),
+		"! echo '{}' > " + q(policyPath),
+		"! touch " + q(StatusPath(townRoot, "forged")),
+		"! touch " + q(filepath.Join(workDir, ".git", "hooks", "pre-commit")),
+		"! git -C " + q(workDir) + " config core.hooksPath /tmp",
+	}, " && ")
+	code, err := Run(policyPath, []string{"sh", "-c", script + " 2>/dev/null"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != 0 {
+		t.Errorf("sandboxed script exited %d; want writes to the worktree allowed and to policy, status and git hooks/config refused", code)
+	}
+	after, err := os.ReadFile(policyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Errorf("policy was rewritten from inside the sandbox:\n%s", after)
+	}
+}
+
+func TestRun_SessionSocketsHidden(t *testing.T) {
+	if err := exec.Command("unshare", "-Urm", "true").Run(); err != nil {
+		t.Skip("user namespaces unavailable")
+	}
+	townRoot, rigPath, workDir := setupRig(t)
+	cfg := &config.SandboxConfig{Tool: config.SandboxToolUnshare, Network: config.SandboxNetworkHost}
+	p, err := NewPolicy(cfg, Options{TownRoot: townRoot, RigPath: rigPath, WorkDir: workDir, Session: "gt-furiosa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyPath := PolicyPath(townRoot, "gt-furiosa")
+	if err := p.Save(policyPath); err != nil {
+		t.Fatal(err)
+	}
+
+	// A session started after the sandbox policy was built, such as the
+	// mayor's, must still be out of reach.
+	socket := pty.SocketPath(townRoot, "hq-mayor")
+	if err := os.MkdirAll(filepath.Dir(socket), 0700); err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := exec.Command(self, "dial", socket).Run(); err != nil {
+		t.Fatalf("dialing %s outside the sandbox: %v", socket, err)
+	}
+
+	code, err := Run(policyPath, []string{self, "dial", socket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code == 0 {
+		t.Errorf("sandboxed process connected to session socket %s", socket)
+	}
+}
+
+func TestIsInnerStage(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "sleep 30; :", "sandbox", "inner", "--policy", "/town/.runtime/sandbox/gt-furiosa.policy.json")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = cmd.Process.Kill(); _ = cmd.Wait() }()
+
+	if err := IsInnerStage(cmd.Process.Pid, "/town/.runtime/sandbox/gt-furiosa.policy.json"); err != nil {
+		t.Errorf("IsInnerStage: %v", err)
+	}
+	if err := IsInnerStage(cmd.Process.Pid, "/town/.runtime/sandbox/gt-nux.policy.json"); err == nil {
+		t.Error("IsInnerStage should reject another session's policy")
+	}
+	if err := IsInnerStage(os.Getpid(), "/town/.runtime/sandbox/gt-furiosa.policy.json"); err == nil {
+		t.Error("IsInnerStage should reject a process that isn't a sandbox")
+	}
+}
@@ -0,0 +1,34 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// Supported reports whether sandboxing is available on this platform.
+func Supported() error {
+	return fmt.Errorf("polecat sandbox needs Linux namespaces (running on %s): %w", runtime.GOOS, errors.ErrUnsupported)
+}
+
+// Run is the outer sandbox stage; it is Linux-only.
+func Run(policyPath string, args []string) (int, error) {
+	return 1, Supported()
+}
+
+// Inner is the inner sandbox stage; it is Linux-only.
+func Inner(policyPath string, setup bool, args []string) (int, error) {
+	return 1, Supported()
+}
+
+// Confined is Linux-only.
+func Confined(pid int, network bool) error {
+	return Supported()
+}
+
+// IsInnerStage is Linux-only.
+func IsInnerStage(pid int, policyPath string) error {
+	return Supported()
+}
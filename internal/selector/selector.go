@@ -0,0 +1,308 @@
+// Package selector implements the agent selector language shared by
+// commands that target groups of agents (broadcast, nudge, mail, session
+// and warrant). A selector is a comma-separated list of terms, all of
+// which must match:
+//
+//	role=polecat,rig=web,state=working,idle>30m,hook~gt-abc
+//
+// Each term is <key><op><value>. Keys:
+//
+//	role     mayor, deacon, witness, refinery, crew, polecat, dog
+//	rig      rig name
+//	name     crew/polecat/dog name
+//	address  full agent address (gastown/polecats/Toast)
+//	state    agent bead state (working, idle, stuck, done, ...)
+//	hook     bead on the agent's hook ("" when nothing is hooked)
+//	session  running or stopped
+//	idle     time since the agent's session last saw activity
+//
+// Operators are = and != (exact, or a glob with * and ?; alternatives
+// separated by |), ~ and !~ (substring), and >, >=, <, <= for idle, whose
+// values are durations such as 90s, 30m, 2h or 1d.
+package selector
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keys a term may test.
+const (
+	KeyRole    = "role"
+	KeyRig     = "rig"
+	KeyName    = "name"
+	KeyAddress = "address"
+	KeyState   = "state"
+	KeyHook    = "hook"
+	KeySession = "session"
+	KeyIdle    = "idle"
+)
+
+// Session values.
+const (
+	SessionRunning = "running"
+	SessionStopped = "stopped"
+)
+
+// Op is a term's comparison operator.
+type Op string
+
+// Operators, longest first so parsing finds "!=" before "=".
+const (
+	OpNotEqual    Op = "!="
+	OpNotContains Op = "!~"
+	OpGreaterEq   Op = ">="
+	OpLessEq      Op = "<="
+	OpEqual       Op = "="
+	OpContains    Op = "~"
+	OpGreater     Op = ">"
+	OpLess        Op = "<"
+)
+
+var ops = []Op{OpNotEqual, OpNotContains, OpGreaterEq, OpLessEq, OpEqual, OpContains, OpGreater, OpLess}
+
+// roleAliases maps plural and group spellings to role names.
+var roleAliases = map[string]string{
+	"polecats":   "polecat",
+	"witnesses":  "witness",
+	"refineries": "refinery",
+	"deacons":    "deacon",
+	"dogs":       "dog",
+}
+
+// Agent is what a selector is evaluated against.
+type Agent struct {
+	Address string `json:"address"`
+	Role    string `json:"role"`
+	Rig     string `json:"rig,omitempty"`
+	Name    string `json:"name,omitempty"`
+	// Session is the tmux session name; empty when the agent has none.
+	Session string `json:"session,omitempty"`
+	State   string `json:"state,omitempty"`
+	Hook    string `json:"hook,omitempty"`
+	// Idle is the time since the session's last activity; IdleKnown is
+	// false when there is no session or activity could not be read.
+	Idle      time.Duration `json:"idle,omitempty"`
+	IdleKnown bool          `json:"-"`
+}
+
+// Running reports whether the agent has a live session.
+func (a *Agent) Running() bool { return a.Session != "" }
+
+// Term is one key/operator/value test.
+type Term struct {
+	Key   string
+	Op    Op
+	Value string
+
+	alts []string      // = and != alternatives
+	dur  time.Duration // idle comparisons
+}
+
+// Selector is a parsed selector: a conjunction of terms.
+type Selector struct {
+	Terms []Term
+}
+
+// Parse parses a selector expression.
+func Parse(s string) (*Selector, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("empty selector")
+	}
+	sel := &Selector{}
+	for _, raw := range strings.Split(s, ",") {
+		t, err := parseTerm(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, err
+		}
+		sel.Terms = append(sel.Terms, t)
+	}
+	return sel, nil
+}
+
+func parseTerm(raw string) (Term, error) {
+	if raw == "" {
+		return Term{}, fmt.Errorf("empty term in selector")
+	}
+	i := strings.IndexAny(raw, "=!~<>")
+	if i <= 0 {
+		return Term{}, fmt.Errorf("invalid term %q: expected <key><op><value>", raw)
+	}
+	key := strings.TrimSpace(raw[:i])
+	rest := raw[i:]
+	var t Term
+	for _, op := range ops {
+		if strings.HasPrefix(rest, string(op)) {
+			t = Term{Key: key, Op: op, Value: strings.TrimSpace(rest[len(op):])}
+			break
+		}
+	}
+	if t.Op == "" {
+		return Term{}, fmt.Errorf("invalid operator in %q", raw)
+	}
+
+	switch key {
+	case KeyIdle:
+		if t.Op != OpGreater && t.Op != OpGreaterEq && t.Op != OpLess && t.Op != OpLessEq {
+			return Term{}, fmt.Errorf("idle takes >, >=, < or <= (got %q)", raw)
+		}
+		d, err := parseDuration(t.Value)
+		if err != nil {
+			return Term{}, fmt.Errorf("invalid duration in %q: %w", raw, err)
+		}
+		t.dur = d
+		return t, nil
+	case KeyRole, KeyRig, KeyName, KeyAddress, KeyState, KeyHook, KeySession:
+	default:
+		return Term{}, fmt.Errorf("unknown selector key %q (want role, rig, name, address, state, hook, session or idle)", key)
+	}
+
+	switch t.Op {
+	case OpEqual, OpNotEqual:
+		for _, alt := range strings.Split(t.Value, "|") {
+			alt = strings.TrimSpace(alt)
+			if key == KeyRole {
+				if r, ok := roleAliases[alt]; ok {
+					alt = r
+				}
+			}
+			if _, err := path.Match(alt, ""); err != nil {
+				return Term{}, fmt.Errorf("invalid pattern in %q: %w", raw, err)
+			}
+			t.alts = append(t.alts, alt)
+		}
+	case OpContains, OpNotContains:
+	default:
+		return Term{}, fmt.Errorf("%s takes =, !=, ~ or !~ (got %q)", key, raw)
+	}
+	if key == KeySession {
+		for _, alt := range t.alts {
+			if alt != SessionRunning && alt != SessionStopped {
+				return Term{}, fmt.Errorf("session is %q or %q (got %q)", SessionRunning, SessionStopped, alt)
+			}
+		}
+	}
+	return t, nil
+}
+
+// parseDuration parses a Go duration, also accepting a "d" (days) suffix.
+func parseDuration(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(s, "d"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// Uses reports whether any term tests one of the given keys. Callers use
+// it to skip gathering data (bead lookups, session activity) that the
+// selector never looks at.
+func (s *Selector) Uses(keys ...string) bool {
+	for _, t := range s.Terms {
+		for _, k := range keys {
+			if t.Key == k {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Match reports whether every term matches the agent.
+func (s *Selector) Match(a *Agent) bool {
+	for _, t := range s.Terms {
+		if !t.match(a) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the agents the selector matches, in order.
+func (s *Selector) Filter(agents []*Agent) []*Agent {
+	var out []*Agent
+	for _, a := range agents {
+		if s.Match(a) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// String renders the selector in canonical form.
+func (s *Selector) String() string {
+	parts := make([]string, len(s.Terms))
+	for i, t := range s.Terms {
+		parts[i] = t.Key + string(t.Op) + t.Value
+	}
+	return strings.Join(parts, ",")
+}
+
+func (t Term) match(a *Agent) bool {
+	if t.Key == KeyIdle {
+		if !a.IdleKnown {
+			return false
+		}
+		switch t.Op {
+		case OpGreater:
+			return a.Idle > t.dur
+		case OpGreaterEq:
+			return a.Idle >= t.dur
+		case OpLess:
+			return a.Idle < t.dur
+		default:
+			return a.Idle <= t.dur
+		}
+	}
+
+	var v string
+	switch t.Key {
+	case KeyRole:
+		v = a.Role
+	case KeyRig:
+		v = a.Rig
+	case KeyName:
+		v = a.Name
+	case KeyAddress:
+		v = a.Address
+	case KeyState:
+		v = a.State
+	case KeyHook:
+		v = a.Hook
+	case KeySession:
+		v = SessionStopped
+		if a.Running() {
+			v = SessionRunning
+		}
+	}
+
+	switch t.Op {
+	case OpContains:
+		return strings.Contains(v, t.Value)
+	case OpNotContains:
+		return !strings.Contains(v, t.Value)
+	case OpNotEqual:
+		return !matchAny(t.alts, v)
+	default:
+		return matchAny(t.alts, v)
+	}
+}
+
+func matchAny(patterns []string, v string) bool {
+	for _, p := range patterns {
+		if p == v {
+			return true
+		}
+		if ok, _ := path.Match(p, v); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package selector
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func town() []*Agent {
+	return []*Agent{
+		{Address: "mayor", Role: "mayor", Session: "hq-mayor", IdleKnown: true, Idle: time.Minute},
+		{Address: "web/witness", Role: "witness", Rig: "web", Session: "web-witness", IdleKnown: true, Idle: 2 * time.Hour},
+		{Address: "web/polecats/Toast", Role: "polecat", Rig: "web", Name: "Toast", Session: "web-Toast", State: "working", Hook: "gt-abc12", IdleKnown: true, Idle: 45 * time.Minute},
+		{Address: "web/polecats/Nux", Role: "polecat", Rig: "web", Name: "Nux", Session: "web-Nux", State: "working", Hook: "gt-xyz", IdleKnown: true, Idle: 5 * time.Minute},
+		{Address: "web/crew/max", Role: "crew", Rig: "web", Name: "max", State: "idle"},
+		{Address: "api/polecats/Slit", Role: "polecat", Rig: "api", Name: "Slit", Session: "api-Slit", State: "stuck", IdleKnown: true, Idle: 3 * time.Hour},
+	}
+}
+
+func addresses(agents []*Agent) string {
+	var out []string
+	for _, a := range agents {
+		out = append(out, a.Address)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		sel  string
+		want string
+	}{
+		{"role=polecat,rig=web,state=working,idle>30m,hook~gt-abc", "web/polecats/Toast"},
+		{"role=polecats", "web/polecats/Toast web/polecats/Nux api/polecats/Slit"},
+		{"role=crew|polecat,rig!=api", "web/polecats/Toast web/polecats/Nux web/crew/max"},
+		{"address=web/polecats/*", "web/polecats/Toast web/polecats/Nux"},
+		{"idle>=2h", "web/witness api/polecats/Slit"},
+		{"idle<10m", "mayor web/polecats/Nux"},
+		{"session=stopped", "web/crew/max"},
+		{"hook=,role=polecat", "api/polecats/Slit"},
+		{"hook!=", "web/polecats/Toast web/polecats/Nux"},
+		{"name!~a", "mayor web/witness web/polecats/Nux api/polecats/Slit"},
+		{"state=st*", "api/polecats/Slit"},
+		{" rig = api ", "api/polecats/Slit"},
+	}
+	for _, tt := range tests {
+		sel, err := Parse(tt.sel)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.sel, err)
+			continue
+		}
+		if got := addresses(sel.Filter(town())); got != tt.want {
+			t.Errorf("%q matched %q, want %q", tt.sel, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"role",
+		"=polecat",
+		"colour=red",
+		"role>polecat",
+		"idle=30m",
+		"idle>soon",
+		"session=asleep",
+		"role=polecat,,rig=web",
+		"name=[",
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func TestUsesAndString(t *testing.T) {
+	sel, err := Parse("role=polecat, idle>1d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sel.Uses(KeyIdle) || sel.Uses(KeyState, KeyHook) {
+		t.Errorf("Uses wrong for %s", sel)
+	}
+	if got := sel.String(); got != "role=polecat,idle>1d" {
+		t.Errorf("String = %q", got)
+	}
+	if sel.Terms[1].dur != 24*time.Hour {
+		t.Errorf("1d parsed as %v", sel.Terms[1].dur)
+	}
+}
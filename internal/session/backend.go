@@ -0,0 +1,359 @@
+package session
+
+import (
+	"errors"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/pty"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Backend is what runs agent sessions: the subset of tmux.Tmux that the
+// session lifecycle, nudging and liveness checks need. A town picks its
+// backend with the session_backend town setting (see NewBackend).
+type Backend interface {
+	NewSessionWithCommand(name, workDir, command string) error
+	HasSession(name string) (bool, error)
+	ListSessions() ([]string, error)
+	KillSessionWithProcesses(name string) error
+	SetEnvironment(session, key, value string) error
+	GetEnvironment(session, key string) (string, error)
+	SendKeysRaw(session, keys string) error
+	NudgeSession(session, message string) error
+	CapturePane(session string, lines int) (string, error)
+	IsAgentAlive(session string) bool
+}
+
+var (
+	_ Backend = (*tmux.Tmux)(nil)
+	_ Backend = (*pty.Backend)(nil)
+)
+
+// Optional backend capabilities. StartSession skips a step when the
+// backend doesn't provide it.
+type (
+	remainOnExitSetter interface {
+		SetRemainOnExit(pane string, on bool) error
+	}
+	sessionThemer interface {
+		ConfigureGasTownSession(session string, theme tmux.Theme, rig, worker, role string) error
+	}
+	commandWaiter interface {
+		WaitForCommand(session string, excludeCommands []string, timeout time.Duration) error
+	}
+	autoRespawner interface {
+		SetAutoRespawnHook(session string) error
+	}
+	dialogAccepter interface {
+		AcceptStartupDialogs(session string) error
+	}
+	readyWaiter interface {
+		WaitForRuntimeReady(session string, rc *config.RuntimeConfig, timeout time.Duration) error
+	}
+	paneIDGetter interface {
+		GetPaneID(session string) (string, error)
+	}
+	healthChecker interface {
+		CheckSessionHealth(session string, maxInactivity time.Duration) tmux.ZombieStatus
+	}
+	infoGetter interface {
+		GetSessionInfo(name string) (*tmux.SessionInfo, error)
+	}
+	createdGetter interface {
+		GetSessionCreatedUnix(session string) (int64, error)
+	}
+	sessionKiller interface {
+		KillSession(name string) error
+	}
+	workDirGetter interface {
+		GetPaneWorkDir(session string) (string, error)
+	}
+	envLister interface {
+		GetAllEnvironment(session string) (map[string]string, error)
+	}
+)
+
+// Health reports a session's liveness on any backend. maxInactivity > 0 also
+// flags sessions with no output for that long as hung, where the backend
+// tracks activity.
+func Health(t Backend, name string, maxInactivity time.Duration) tmux.ZombieStatus {
+	if hc, ok := t.(healthChecker); ok {
+		return hc.CheckSessionHealth(name, maxInactivity)
+	}
+	if ok, err := t.HasSession(name); err != nil || !ok {
+		return tmux.SessionDead
+	}
+	if !t.IsAgentAlive(name) {
+		return tmux.AgentDead
+	}
+	return tmux.SessionHealthy
+}
+
+// Info returns display information about a running session. Backends
+// without window metadata report just the name.
+func Info(t Backend, name string) (*tmux.SessionInfo, error) {
+	if ig, ok := t.(infoGetter); ok {
+		return ig.GetSessionInfo(name)
+	}
+	return &tmux.SessionInfo{Name: name}, nil
+}
+
+// CreatedUnix returns when a session was created, or 0 if the backend
+// can't tell. Callers compare it across checks to detect a replaced session.
+func CreatedUnix(t Backend, name string) int64 {
+	if cg, ok := t.(createdGetter); ok {
+		if ts, err := cg.GetSessionCreatedUnix(name); err == nil {
+			return ts
+		}
+	}
+	return 0
+}
+
+// WorkDir returns the working directory of a session's agent, or
+// errors.ErrUnsupported if the backend can't tell.
+func WorkDir(t Backend, name string) (string, error) {
+	if w, ok := t.(workDirGetter); ok {
+		return w.GetPaneWorkDir(name)
+	}
+	return "", errors.ErrUnsupported
+}
+
+// Environment returns a session's env vars, or errors.ErrUnsupported if the
+// backend can't list them.
+func Environment(t Backend, name string) (map[string]string, error) {
+	if e, ok := t.(envLister); ok {
+		return e.GetAllEnvironment(name)
+	}
+	return nil, errors.ErrUnsupported
+}
+
+// Kill ends a session without the process-tree sweep of
+// KillSessionWithProcesses where the backend distinguishes the two.
+func Kill(t Backend, name string) error {
+	if k, ok := t.(sessionKiller); ok {
+		return k.KillSession(name)
+	}
+	return t.KillSessionWithProcesses(name)
+}
+
+// ApplyTheme applies Gas Town theming on backends that have a status bar.
+func ApplyTheme(t Backend, name string, theme tmux.Theme, rig, worker, role string) error {
+	if th, ok := t.(sessionThemer); ok {
+		return th.ConfigureGasTownSession(name, theme, rig, worker, role)
+	}
+	return nil
+}
+
+// WaitForCommand waits for the agent to replace the startup shell on
+// backends that can see the pane's foreground command.
+func WaitForCommand(t Backend, name string, excludeCommands []string, timeout time.Duration) error {
+	if w, ok := t.(commandWaiter); ok {
+		return w.WaitForCommand(name, excludeCommands, timeout)
+	}
+	return nil
+}
+
+// AcceptStartupDialogs dismisses the agent's startup dialogs on backends
+// that support it.
+func AcceptStartupDialogs(t Backend, name string) error {
+	if d, ok := t.(dialogAccepter); ok {
+		return d.AcceptStartupDialogs(name)
+	}
+	return nil
+}
+
+// WaitForRuntimeReady waits for the agent's ready prompt on backends that
+// can detect it.
+func WaitForRuntimeReady(t Backend, name string, rc *config.RuntimeConfig, timeout time.Duration) error {
+	if w, ok := t.(readyWaiter); ok {
+		return w.WaitForRuntimeReady(name, rc, timeout)
+	}
+	return nil
+}
+
+// SetRemainOnExit keeps a session's pane after its command exits, on
+// backends with panes.
+func SetRemainOnExit(t Backend, name string, on bool) error {
+	if r, ok := t.(remainOnExitSetter); ok {
+		return r.SetRemainOnExit(name, on)
+	}
+	return nil
+}
+
+// SetAutoRespawnHook makes the backend restart the agent when it exits,
+// where supported.
+func SetAutoRespawnHook(t Backend, name string) error {
+	if r, ok := t.(autoRespawner); ok {
+		return r.SetAutoRespawnHook(name)
+	}
+	return nil
+}
+
+// PaneID returns the session's pane ID, or errors.ErrUnsupported on
+// backends without panes.
+func PaneID(t Backend, name string) (string, error) {
+	if p, ok := t.(paneIDGetter); ok {
+		return p.GetPaneID(name)
+	}
+	return "", errors.ErrUnsupported
+}
+
+// PanePIDGetter is implemented by backends that can report the PID of the
+// process running in a session.
+type PanePIDGetter interface {
+	GetPanePID(session string) (string, error)
+}
+
+// IdleWaiter is implemented by backends that can tell when an agent is
+// sitting at its idle prompt.
+type IdleWaiter interface {
+	WaitForIdle(session string, timeout time.Duration) error
+}
+
+// NewBackend returns the session backend configured for a town: tmux
+// unless settings/config.json sets session_backend to "pty".
+func NewBackend(townRoot string) Backend {
+	if BackendName(townRoot) == config.SessionBackendPTY {
+		return pty.NewBackend(townRoot)
+	}
+	return tmux.NewTmux()
+}
+
+// BackendName returns the town's configured session backend name.
+func BackendName(townRoot string) string {
+	if townRoot == "" {
+		return config.SessionBackendTmux
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.SessionBackend == "" {
+		return config.SessionBackendTmux
+	}
+	return settings.SessionBackend
+}
+
+// BackendFor returns the backend running an existing session: a PTY
+// supervisor if one answers for it, tmux otherwise. Towns can mix the two
+// while sessions started under an earlier setting are still running.
+func BackendFor(townRoot, sessionID string) Backend {
+	if townRoot != "" {
+		b := pty.NewBackend(townRoot)
+		if ok, _ := b.HasSession(sessionID); ok {
+			return b
+		}
+	}
+	return tmux.NewTmux()
+}
+
+// NewBackendWith is NewBackend for callers that hold a configured tmux
+// client: t is returned when the town runs tmux.
+func NewBackendWith(t *tmux.Tmux, townRoot string) Backend {
+	if BackendName(townRoot) == config.SessionBackendPTY {
+		return pty.NewBackend(townRoot)
+	}
+	return orDefault(t)
+}
+
+// BackendForWith is BackendFor with t as the tmux fallback.
+func BackendForWith(t *tmux.Tmux, townRoot, sessionID string) Backend {
+	if townRoot != "" {
+		b := pty.NewBackend(townRoot)
+		if ok, _ := b.HasSession(sessionID); ok {
+			return b
+		}
+	}
+	return orDefault(t)
+}
+
+// ListAll returns the sessions running on tmux and on the town's PTY
+// supervisors. A tmux error is returned only when there are no PTY
+// sessions to report either.
+func ListAll(t *tmux.Tmux, townRoot string) ([]string, error) {
+	names, err := orDefault(t).ListSessions()
+	if townRoot == "" {
+		return names, err
+	}
+	ptyNames, _ := pty.NewBackend(townRoot).ListSessions()
+	if err != nil && len(ptyNames) == 0 {
+		return nil, err
+	}
+	return append(names, ptyNames...), nil
+}
+
+// TownBackend is a Backend for callers that handle many sessions: each call
+// goes to the backend running that session (see BackendForWith), listing
+// covers every backend, and new sessions start on the town's configured one.
+type TownBackend struct {
+	tmux     *tmux.Tmux
+	townRoot string
+}
+
+var _ Backend = (*TownBackend)(nil)
+
+// NewTownBackend returns a TownBackend with t as the tmux client.
+func NewTownBackend(t *tmux.Tmux, townRoot string) *TownBackend {
+	return &TownBackend{tmux: orDefault(t), townRoot: townRoot}
+}
+
+// For returns the backend running session: a PTY supervisor if one
+// answers for it, tmux otherwise.
+func (b *TownBackend) For(name string) Backend {
+	return BackendForWith(b.tmux, b.townRoot, name)
+}
+
+func (b *TownBackend) NewSessionWithCommand(name, workDir, command string) error {
+	return NewBackendWith(b.tmux, b.townRoot).NewSessionWithCommand(name, workDir, command)
+}
+
+func (b *TownBackend) HasSession(name string) (bool, error) { return b.For(name).HasSession(name) }
+
+func (b *TownBackend) ListSessions() ([]string, error) { return ListAll(b.tmux, b.townRoot) }
+
+func (b *TownBackend) KillSessionWithProcesses(name string) error {
+	return b.For(name).KillSessionWithProcesses(name)
+}
+
+func (b *TownBackend) SetEnvironment(session, key, value string) error {
+	return b.For(session).SetEnvironment(session, key, value)
+}
+
+func (b *TownBackend) GetEnvironment(session, key string) (string, error) {
+	return b.For(session).GetEnvironment(session, key)
+}
+
+func (b *TownBackend) SendKeysRaw(session, keys string) error {
+	return b.For(session).SendKeysRaw(session, keys)
+}
+
+func (b *TownBackend) NudgeSession(session, message string) error {
+	return b.For(session).NudgeSession(session, message)
+}
+
+func (b *TownBackend) CapturePane(session string, lines int) (string, error) {
+	return b.For(session).CapturePane(session, lines)
+}
+
+func (b *TownBackend) IsAgentAlive(session string) bool { return b.For(session).IsAgentAlive(session) }
+
+// WaitForIdle waits for the agent's idle prompt, returning
+// errors.ErrUnsupported on backends that can't detect it.
+func (b *TownBackend) WaitForIdle(session string, timeout time.Duration) error {
+	if w, ok := b.For(session).(IdleWaiter); ok {
+		return w.WaitForIdle(session, timeout)
+	}
+	return errors.ErrUnsupported
+}
+
+// IsTmux reports whether session runs in tmux (or doesn't exist), for
+// tmux-only steps such as pane respawns and notification banners.
+func (b *TownBackend) IsTmux(session string) bool {
+	_, ok := b.For(session).(*tmux.Tmux)
+	return ok
+}
+
+func orDefault(t *tmux.Tmux) *tmux.Tmux {
+	if t == nil {
+		return tmux.NewTmux()
+	}
+	return t
+}
@@ -0,0 +1,129 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/pty"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func TestNewBackend(t *testing.T) {
+	townRoot := t.TempDir()
+	if _, ok := NewBackend(townRoot).(*tmux.Tmux); !ok {
+		t.Error("town without settings should use tmux")
+	}
+
+	writeTownSettings(t, townRoot, `{"type":"town-settings","version":1,"session_backend":"pty"}`)
+	if _, ok := NewBackend(townRoot).(*pty.Backend); !ok {
+		t.Error(`session_backend "pty" should use the pty backend`)
+	}
+}
+
+func TestPTYBackend_RequiresOptIn(t *testing.T) {
+	townRoot := t.TempDir()
+	writeTownSettings(t, townRoot, `{"type":"town-settings","version":1,"session_backend":"pty"}`)
+	err := NewBackend(townRoot).NewSessionWithCommand("gt-test", townRoot, "true")
+	if !errors.Is(err, pty.ErrExperimental) {
+		t.Errorf("starting a PTY session without experimental_pty_backend: err = %v, want ErrExperimental", err)
+	}
+}
+
+func TestTownBackend_RoutesToPTYSessions(t *testing.T) {
+	townRoot := t.TempDir()
+	name := "gt-routed"
+	socket := pty.SocketPath(townRoot, name)
+	if err := os.MkdirAll(filepath.Dir(socket), 0700); err != nil {
+		t.Fatal(err)
+	}
+	sup := &pty.Supervisor{Name: name, Dir: townRoot, Command: "echo routed; cat", Socket: socket}
+	go func() { _ = sup.Run() }()
+
+	b := NewTownBackend(tmux.NewTmuxWithSocket("gt-test-none"), townRoot)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if ok, _ := b.HasSession(name); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("PTY session never showed up through the town backend")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Cleanup(func() { _ = b.KillSessionWithProcesses(name) })
+
+	if b.IsTmux(name) {
+		t.Error("IsTmux = true for a PTY session")
+	}
+	if !b.IsTmux("gt-missing") {
+		t.Error("IsTmux = false for a session no PTY supervisor answers for")
+	}
+	names, _ := b.ListSessions()
+	if !slices.Contains(names, name) {
+		t.Errorf("ListSessions = %v, want it to include %s", names, name)
+	}
+	if err := b.SetEnvironment(name, "GT_ROLE", "crew"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := b.GetEnvironment(name, "GT_ROLE"); err != nil || got != "crew" {
+		t.Errorf("GetEnvironment = %q, %v, want crew", got, err)
+	}
+}
+
+func writeTownSettings(t *testing.T, townRoot, data string) {
+	t.Helper()
+	settings := filepath.Join(townRoot, "settings", "config.json")
+	if err := os.MkdirAll(filepath.Dir(settings), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settings, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBackendWith_UsesGivenTmux(t *testing.T) {
+	townRoot := t.TempDir()
+	tm := tmux.NewTmuxWithSocket("gt-test")
+	if got := NewBackendWith(tm, townRoot); got != Backend(tm) {
+		t.Errorf("NewBackendWith = %T, want the given tmux client", got)
+	}
+	if got := BackendForWith(tm, townRoot, "gt-none"); got != Backend(tm) {
+		t.Errorf("BackendForWith with no PTY session = %T, want the given tmux client", got)
+	}
+}
+
+// aliveOnlyBackend has no health or info capabilities, so the helpers fall
+// back to the core Backend methods.
+type aliveOnlyBackend struct {
+	Backend
+	has, alive bool
+}
+
+func (b aliveOnlyBackend) HasSession(string) (bool, error) { return b.has, nil }
+func (b aliveOnlyBackend) IsAgentAlive(string) bool        { return b.alive }
+
+func TestHealth_Fallback(t *testing.T) {
+	tests := []struct {
+		has, alive bool
+		want       tmux.ZombieStatus
+	}{
+		{false, false, tmux.SessionDead},
+		{true, false, tmux.AgentDead},
+		{true, true, tmux.SessionHealthy},
+	}
+	for _, tt := range tests {
+		if got := Health(aliveOnlyBackend{has: tt.has, alive: tt.alive}, "s", 0); got != tt.want {
+			t.Errorf("Health(has=%v, alive=%v) = %v, want %v", tt.has, tt.alive, got, tt.want)
+		}
+	}
+	if got := CreatedUnix(aliveOnlyBackend{}, "s"); got != 0 {
+		t.Errorf("CreatedUnix without support = %d, want 0", got)
+	}
+	if _, err := PaneID(aliveOnlyBackend{}, "s"); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("PaneID without support: err = %v, want ErrUnsupported", err)
+	}
+}
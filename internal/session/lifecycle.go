@@ -18,7 +18,7 @@ import (
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
-// SessionConfig describes how to create and start a tmux session.
+// SessionConfig describes how to create and start an agent session.
 // This unifies the common startup pattern that was previously duplicated
 // across polecat, mayor, boot, deacon, witness, refinery, crew, and dog
 // session managers. Each of those managers previously had to coordinate
@@ -141,7 +141,7 @@ type StartResult struct {
 // Role-specific concerns (issue validation, fallback nudges, pane-died hooks,
 // crew cycle bindings, etc.) should be handled by the caller before/after
 // calling StartSession.
-func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error) {
+func StartSession(t Backend, cfg SessionConfig) (_ *StartResult, retErr error) {
 	// Generate the GASTA run ID — the root identifier for all telemetry emitted
 	// by this agent session and its subprocesses (bd, mail, …).
 	runID := uuid.New().String()
@@ -197,14 +197,14 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 	extraWithRun["GT_RUN"] = runID
 	command = config.PrependEnv(command, extraWithRun)
 
-	// 4. Create tmux session with command.
+	// 4. Create session with command.
 	if err := t.NewSessionWithCommand(cfg.SessionID, cfg.WorkDir, command); err != nil {
 		return nil, fmt.Errorf("creating session: %w", err)
 	}
 
 	// 5. Set remain-on-exit immediately if requested (before anything else can fail).
-	if cfg.RemainOnExit {
-		_ = t.SetRemainOnExit(cfg.SessionID, true)
+	if r, ok := t.(remainOnExitSetter); ok && cfg.RemainOnExit {
+		_ = r.SetRemainOnExit(cfg.SessionID, true)
 	}
 
 	// 6. Set environment variables.
@@ -228,13 +228,13 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 	}
 
 	// 7. Apply theme.
-	if cfg.Theme != nil {
-		_ = t.ConfigureGasTownSession(cfg.SessionID, *cfg.Theme, cfg.RigName, cfg.AgentName, cfg.Role)
+	if th, ok := t.(sessionThemer); ok && cfg.Theme != nil {
+		_ = th.ConfigureGasTownSession(cfg.SessionID, *cfg.Theme, cfg.RigName, cfg.AgentName, cfg.Role)
 	}
 
 	// 8. Wait for agent to start.
-	if cfg.WaitForAgent {
-		if err := t.WaitForCommand(cfg.SessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+	if w, ok := t.(commandWaiter); ok && cfg.WaitForAgent {
+		if err := w.WaitForCommand(cfg.SessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 			if cfg.WaitFatal {
 				_ = t.KillSessionWithProcesses(cfg.SessionID)
 				return nil, fmt.Errorf("waiting for %s to start: %w", cfg.Role, err)
@@ -243,22 +243,22 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 	}
 
 	// 9. Auto-respawn hook.
-	if cfg.AutoRespawn {
-		if err := t.SetAutoRespawnHook(cfg.SessionID); err != nil {
+	if r, ok := t.(autoRespawner); ok && cfg.AutoRespawn {
+		if err := r.SetAutoRespawnHook(cfg.SessionID); err != nil {
 			fmt.Printf("warning: failed to set auto-respawn hook for %s: %v\n", cfg.Role, err)
 		}
 	}
 
 	// 10. Accept startup dialogs (workspace trust + bypass permissions).
-	if cfg.AcceptBypass {
-		_ = t.AcceptStartupDialogs(cfg.SessionID)
+	if d, ok := t.(dialogAccepter); ok && cfg.AcceptBypass {
+		_ = d.AcceptStartupDialogs(cfg.SessionID)
 	}
 
 	// 11. Ready delay: wait for agent to be fully ready at the prompt.
 	// Uses prompt-based polling for agents with ReadyPromptPrefix,
 	// falling back to ReadyDelayMs sleep for agents without prompt detection.
-	if cfg.ReadyDelay {
-		if err := t.WaitForRuntimeReady(cfg.SessionID, runtimeConfig, constants.ClaudeStartTimeout); err != nil {
+	if w, ok := t.(readyWaiter); ok && cfg.ReadyDelay {
+		if err := w.WaitForRuntimeReady(cfg.SessionID, runtimeConfig, constants.ClaudeStartTimeout); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: agent readiness detection timed out for %s: %v\n", cfg.SessionID, err)
 		}
 	}
@@ -279,8 +279,10 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 	// 13. Record agent's pane_id for ZFC-compliant liveness checks (gt-qmsx).
 	// Declared pane identity replaces process-tree inference in IsRuntimeRunning
 	// and FindAgentPane. Legacy sessions without GT_PANE_ID fall back to scanning.
-	if paneID, err := t.GetPaneID(cfg.SessionID); err == nil {
-		_ = t.SetEnvironment(cfg.SessionID, "GT_PANE_ID", paneID)
+	if p, ok := t.(paneIDGetter); ok {
+		if paneID, err := p.GetPaneID(cfg.SessionID); err == nil {
+			_ = t.SetEnvironment(cfg.SessionID, "GT_PANE_ID", paneID)
+		}
 	}
 
 	// 14. Track PID for defense-in-depth orphan cleanup.
@@ -337,11 +339,11 @@ func RecordAgentInstantiateFromDir(ctx context.Context, runID, resolvedAgent, ro
 	})
 }
 
-// StopSession stops a tmux session with optional graceful shutdown.
+// StopSession stops a session with optional graceful shutdown.
 //
 // If graceful is true, sends Ctrl-C first and waits for the session to exit
 // before force-killing. This allows the agent to clean up.
-func StopSession(t *tmux.Tmux, sessionID string, graceful bool) error {
+func StopSession(t Backend, sessionID string, graceful bool) error {
 	running, err := t.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
@@ -424,7 +426,7 @@ func MergeRuntimeLivenessEnv(envVars map[string]string, runtimeConfig *config.Ru
 // If checkAlive is true, only kills zombie sessions (tmux alive but agent dead).
 // If the session exists and the agent is alive, returns ErrAlreadyRunning.
 // If checkAlive is false, kills any existing session unconditionally.
-func KillExistingSession(t *tmux.Tmux, sessionID string, checkAlive bool) (bool, error) {
+func KillExistingSession(t Backend, sessionID string, checkAlive bool) (bool, error) {
 	running, err := t.HasSession(sessionID)
 	if err != nil {
 		return false, fmt.Errorf("checking session: %w", err)
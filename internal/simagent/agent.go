@@ -0,0 +1,286 @@
+package simagent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// ReadyPrompt is printed whenever the sim agent is waiting for input. The
+// sim preset uses it as its ready prompt prefix.
+const ReadyPrompt = "sim> "
+
+// Messages printed by the stall behaviors. They match what real agents
+// print so pane scanners (rate-limit detection, witness) see the same thing.
+const (
+	ContextExceededText = "Prompt is too long"
+	RateLimitText       = "You've hit your limit · resets 7pm (UTC)"
+)
+
+// ExitError is returned by Run when the script crashes the agent.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("sim agent crashed (exit %d)", e.Code)
+}
+
+// Agent plays back a script in a workspace.
+type Agent struct {
+	// Actions is the script to play.
+	Actions []Action
+	// WorkDir is the agent's workspace.
+	WorkDir string
+	// Prompt is the startup prompt (beacon) the session was launched with.
+	Prompt string
+	// Out is the agent's terminal; In delivers nudges.
+	Out io.Writer
+	In  io.Reader
+	// Gt runs a gt command and returns its combined output. Defaults to
+	// running "gt" from PATH in WorkDir.
+	Gt func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+// Run plays the script. When the script ends without done, handoff, crash
+// or a stall, the agent idles so the session stays up like a real agent's.
+func (a *Agent) Run(ctx context.Context) error {
+	if a.Gt == nil {
+		a.Gt = a.execGt
+	}
+	if a.Prompt != "" {
+		fmt.Fprintf(a.Out, "> %s\n\n", a.Prompt)
+	}
+
+	for _, act := range a.Actions {
+		fmt.Fprintf(a.Out, "● %s\n", describe(act))
+		stop, err := a.do(ctx, act)
+		if err != nil || stop {
+			return err
+		}
+	}
+	return a.idle(ctx)
+}
+
+// do performs one action. stop reports that the script is over.
+func (a *Agent) do(ctx context.Context, act Action) (stop bool, err error) {
+	switch act.Kind {
+	case KindPrime:
+		a.gt(ctx, "prime")
+	case KindHook:
+		a.gt(ctx, "hook")
+	case KindCompleteStep:
+		a.completeSteps(ctx, act.Count)
+	case KindCommit:
+		if err := a.commit(act); err != nil {
+			fmt.Fprintf(a.Out, "  commit failed: %v\n", err)
+		}
+	case KindRun:
+		a.gt(ctx, act.Args...)
+	case KindSleep:
+		if !sleep(ctx, act.Duration) {
+			return true, nil
+		}
+	case KindCrash:
+		if !sleep(ctx, act.Duration) {
+			return true, nil
+		}
+		code := act.Code
+		if code == 0 {
+			code = 1
+		}
+		return true, &ExitError{Code: code}
+	case KindHang:
+		<-ctx.Done()
+		return true, nil
+	case KindExceedContext:
+		fmt.Fprintf(a.Out, "  %s\n", orDefault(act.Text, ContextExceededText))
+		<-ctx.Done()
+		return true, nil
+	case KindRateLimit:
+		fmt.Fprintf(a.Out, "  %s\n", orDefault(act.Text, RateLimitText))
+		<-ctx.Done()
+		return true, nil
+	case KindDone:
+		a.gt(ctx, "done")
+	case KindHandoff:
+		a.gt(ctx, "handoff")
+	case KindIdle:
+		return true, a.idle(ctx)
+	}
+	return false, nil
+}
+
+// gt runs a gt command and echoes its output to the terminal. Failures are
+// printed, not fatal: a real agent reads the error and carries on.
+func (a *Agent) gt(ctx context.Context, args ...string) {
+	out, err := a.Gt(ctx, args...)
+	if len(out) > 0 {
+		fmt.Fprint(a.Out, indent(string(out)))
+	}
+	if err != nil {
+		fmt.Fprintf(a.Out, "  gt %s: %v\n", strings.Join(args, " "), err)
+	}
+}
+
+// completeSteps closes the current molecule step, up to count steps (0 for
+// all). gt mol step done may respawn the session between steps; the script
+// then restarts and picks up at the next step.
+func (a *Agent) completeSteps(ctx context.Context, count int) {
+	var last string
+	for n := 0; count == 0 || n < count; n++ {
+		out, err := a.Gt(ctx, "mol", "current", "--json")
+		if err != nil {
+			fmt.Fprintf(a.Out, "  gt mol current: %v\n", err)
+			return
+		}
+		var cur struct {
+			CurrentStepID string `json:"current_step_id"`
+			Status        string `json:"status"`
+		}
+		if err := json.Unmarshal(out, &cur); err != nil {
+			fmt.Fprintf(a.Out, "  gt mol current: %v\n", err)
+			return
+		}
+		if cur.CurrentStepID == "" || cur.CurrentStepID == last {
+			fmt.Fprintf(a.Out, "  no step to complete (%s)\n", orDefault(cur.Status, "unknown"))
+			return
+		}
+		last = cur.CurrentStepID
+		a.gt(ctx, "mol", "step", "done", cur.CurrentStepID)
+	}
+}
+
+// commit writes the action's file and commits it.
+func (a *Agent) commit(act Action) error {
+	path := filepath.Join(a.WorkDir, act.File)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	content := orDefault(act.Content, "written by the sim agent\n")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil { //nolint:gosec // G306: workspace file
+		return err
+	}
+	g := git.NewGit(a.WorkDir)
+	if err := g.Add(act.File); err != nil {
+		return err
+	}
+	return g.Commit(orDefault(act.Message, "sim: update "+act.File))
+}
+
+// idle waits for nudges. Each line is echoed; "gt ..." commands in it
+// (joined with && the way startup nudges are) are run.
+func (a *Agent) idle(ctx context.Context) error {
+	if a.In == nil {
+		<-ctx.Done()
+		return nil
+	}
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(a.In)
+		for sc.Scan() {
+			select {
+			case lines <- sc.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		fmt.Fprint(a.Out, ReadyPrompt)
+		select {
+		case <-ctx.Done():
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				fmt.Fprintln(a.Out)
+				return nil
+			}
+			a.handleNudge(ctx, line)
+		}
+	}
+}
+
+// handleNudge runs the gt commands in a nudge line.
+func (a *Agent) handleNudge(ctx context.Context, line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+	fmt.Fprintf(a.Out, "\n> %s\n", line)
+	for _, part := range strings.Split(line, "&&") {
+		fields := strings.Fields(part)
+		if len(fields) < 2 || fields[0] != "gt" {
+			continue
+		}
+		fmt.Fprintf(a.Out, "● gt %s\n", strings.Join(fields[1:], " "))
+		a.gt(ctx, fields[1:]...)
+	}
+}
+
+// execGt runs gt from PATH in the workspace.
+func (a *Agent) execGt(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "gt", args...)
+	cmd.Dir = a.WorkDir
+	return cmd.CombinedOutput()
+}
+
+// describe renders an action for the terminal.
+func describe(act Action) string {
+	switch act.Kind {
+	case KindCompleteStep:
+		if act.Count > 0 {
+			return fmt.Sprintf("complete_step (%d)", act.Count)
+		}
+		return "complete_step (all)"
+	case KindCommit:
+		return "commit " + act.File
+	case KindRun:
+		return "gt " + strings.Join(act.Args, " ")
+	case KindSleep, KindCrash:
+		if act.Duration > 0 {
+			return fmt.Sprintf("%s %s", act.Kind, act.Duration)
+		}
+	case KindPrime, KindHook, KindDone, KindHandoff:
+		return "gt " + string(act.Kind)
+	}
+	return string(act.Kind)
+}
+
+// sleep waits for d, returning false if ctx ends first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func indent(s string) string {
+	s = strings.TrimRight(s, "\n")
+	return "  " + strings.ReplaceAll(s, "\n", "\n  ") + "\n"
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
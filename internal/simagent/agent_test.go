@@ -0,0 +1,202 @@
+package simagent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeGt records gt invocations and answers gt mol current from steps.
+type fakeGt struct {
+	calls []string
+	steps []string
+}
+
+func (f *fakeGt) run(_ context.Context, args ...string) ([]byte, error) {
+	call := strings.Join(args, " ")
+	f.calls = append(f.calls, call)
+	switch call {
+	case "mol current --json":
+		if len(f.steps) == 0 {
+			return []byte(`{"status":"complete"}`), nil
+		}
+		return []byte(`{"status":"working","current_step_id":"` + f.steps[0] + `"}`), nil
+	case "hook":
+		return []byte("nothing on hook\n"), nil
+	}
+	if strings.HasPrefix(call, "mol step done ") && len(f.steps) > 0 {
+		f.steps = f.steps[1:]
+	}
+	if call == "fail" {
+		return []byte("boom\n"), errors.New("exit status 1")
+	}
+	return nil, nil
+}
+
+func TestRunScript(t *testing.T) {
+	gt := &fakeGt{steps: []string{"gt-s1", "gt-s2", "gt-s3"}}
+	var out bytes.Buffer
+	a := &Agent{
+		Actions: []Action{
+			{Kind: KindPrime},
+			{Kind: KindHook},
+			{Kind: KindCompleteStep, Count: 1},
+			{Kind: KindCompleteStep},
+			{Kind: KindRun, Args: []string{"fail"}},
+			{Kind: KindDone},
+		},
+		Prompt: "[GAS TOWN] beacon",
+		Out:    &out,
+		In:     strings.NewReader(""),
+		Gt:     gt.run,
+	}
+	if err := a.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := []string{
+		"prime",
+		"hook",
+		"mol current --json", "mol step done gt-s1",
+		"mol current --json", "mol step done gt-s2",
+		"mol current --json", "mol step done gt-s3",
+		"mol current --json",
+		"fail",
+		"done",
+	}
+	if !reflect.DeepEqual(gt.calls, want) {
+		t.Errorf("gt calls =\n%q\nwant\n%q", gt.calls, want)
+	}
+	for _, s := range []string{"> [GAS TOWN] beacon", "  nothing on hook", "no step to complete (complete)", "gt fail: exit status 1", ReadyPrompt} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("output missing %q:\n%s", s, out.String())
+		}
+	}
+}
+
+func TestRunCrash(t *testing.T) {
+	gt := &fakeGt{}
+	a := &Agent{
+		Actions: []Action{{Kind: KindCrash, Duration: time.Millisecond, Code: 2}, {Kind: KindDone}},
+		Out:     &bytes.Buffer{},
+		Gt:      gt.run,
+	}
+	var exitErr *ExitError
+	if err := a.Run(context.Background()); !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Fatalf("Run = %v, want exit 2", err)
+	}
+	if len(gt.calls) != 0 {
+		t.Errorf("crashed agent ran %q", gt.calls)
+	}
+
+	a.Actions = []Action{{Kind: KindCrash}}
+	if err := a.Run(context.Background()); !errors.As(err, &exitErr) || exitErr.Code != 1 {
+		t.Errorf("Run = %v, want default exit 1", err)
+	}
+}
+
+func TestRunStalls(t *testing.T) {
+	tests := []struct {
+		kind Kind
+		want string
+	}{
+		{KindHang, ""},
+		{KindExceedContext, ContextExceededText},
+		{KindRateLimit, RateLimitText},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			gt := &fakeGt{}
+			var out bytes.Buffer
+			a := &Agent{
+				Actions: []Action{{Kind: tt.kind}, {Kind: KindDone}},
+				Out:     &out,
+				In:      strings.NewReader("gt prime\n"),
+				Gt:      gt.run,
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			if err := a.Run(ctx); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if len(gt.calls) != 0 {
+				t.Errorf("stalled agent ran %q", gt.calls)
+			}
+			if tt.want != "" && !strings.Contains(out.String(), tt.want) {
+				t.Errorf("output missing %q:\n%s", tt.want, out.String())
+			}
+			if strings.Contains(out.String(), ReadyPrompt) {
+				t.Errorf("stalled agent showed the ready prompt:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestIdleRunsNudges(t *testing.T) {
+	gt := &fakeGt{}
+	var out bytes.Buffer
+	a := &Agent{
+		Out: &out,
+		In:  strings.NewReader("gt prime && gt mail check --inject\nCheck your hook with `gt hook`.\n\n"),
+		Gt:  gt.run,
+	}
+	if err := a.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := []string{"prime", "mail check --inject"}
+	if !reflect.DeepEqual(gt.calls, want) {
+		t.Errorf("gt calls = %q, want %q", gt.calls, want)
+	}
+	if !strings.Contains(out.String(), "> Check your hook") {
+		t.Errorf("nudge not echoed:\n%s", out.String())
+	}
+}
+
+func TestCommit(t *testing.T) {
+	dir := t.TempDir()
+	for _, args := range [][]string{{"init", "-q"}, {"commit", "-q", "--allow-empty", "-m", "init"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "t")
+	t.Setenv("GIT_AUTHOR_EMAIL", "t@t")
+	t.Setenv("GIT_COMMITTER_NAME", "t")
+	t.Setenv("GIT_COMMITTER_EMAIL", "t@t")
+
+	var out bytes.Buffer
+	a := &Agent{
+		Actions: []Action{{Kind: KindCommit, File: "sub/notes.txt", Content: "hello\n", Message: "Add notes"}},
+		WorkDir: dir,
+		Out:     &out,
+		In:      strings.NewReader(""),
+		Gt:      (&fakeGt{}).run,
+	}
+	if err := a.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "sub", "notes.txt"))
+	if err != nil || string(data) != "hello\n" {
+		t.Fatalf("notes.txt = %q, %v", data, err)
+	}
+	cmd := exec.Command("git", "log", "-1", "--format=%s")
+	cmd.Dir = dir
+	subject, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git log: %v", err)
+	}
+	if got := strings.TrimSpace(string(subject)); got != "Add notes" {
+		t.Errorf("last commit = %q, want %q (output: %s)", got, "Add notes", out.String())
+	}
+}
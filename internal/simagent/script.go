@@ -0,0 +1,315 @@
+// Package simagent implements the "sim" agent preset: a deterministic,
+// offline stand-in for an LLM agent. It reads its prime and hook the way a
+// real agent does, then plays back a scripted list of behaviors (complete a
+// step, commit a file, crash, hang, run out of context, hit a rate limit) so
+// whole-town scenarios can run in CI without network access.
+package simagent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvScript names a script file that overrides the town's settings/sim.yaml.
+const EnvScript = "GT_SIM_SCRIPT"
+
+// ScriptFile is the town-level script location, relative to the town root.
+const ScriptFile = "settings/sim.yaml"
+
+// Kind identifies a scripted behavior.
+type Kind string
+
+// Supported behaviors.
+const (
+	KindPrime         Kind = "prime"          // gt prime
+	KindHook          Kind = "hook"           // gt hook
+	KindCompleteStep  Kind = "complete_step"  // gt mol step done <current step>
+	KindCommit        Kind = "commit"         // write a file and git commit it
+	KindRun           Kind = "run"            // any other gt command
+	KindSleep         Kind = "sleep"          // pause
+	KindCrash         Kind = "crash"          // exit non-zero
+	KindHang          Kind = "hang"           // stop responding
+	KindExceedContext Kind = "exceed_context" // print a context-exhausted error and stall
+	KindRateLimit     Kind = "rate_limit"     // print a rate-limit message and stall
+	KindDone          Kind = "done"           // gt done
+	KindHandoff       Kind = "handoff"        // gt handoff
+	KindIdle          Kind = "idle"           // wait for nudges
+)
+
+// Action is one scripted behavior. In YAML an action is either a bare kind
+// ("prime") or a single-key map whose value is the kind's main argument
+// ("sleep: 2s") or a map of its fields ("commit: {file: a.txt}").
+type Action struct {
+	Kind Kind
+
+	// Count is how many steps complete_step closes; 0 means all of them.
+	Count int
+
+	// File, Content and Message describe a commit.
+	File    string
+	Content string
+	Message string
+
+	// Args are the gt arguments for run, without the leading "gt".
+	Args []string
+
+	// Duration is the pause for sleep and the delay before a crash.
+	Duration time.Duration
+
+	// Code is the crash exit code (default 1).
+	Code int
+
+	// Text overrides the message printed by exceed_context and rate_limit.
+	Text string
+}
+
+// actionFields is the map form of an action's arguments.
+type actionFields struct {
+	Count    string   `yaml:"count"`
+	File     string   `yaml:"file"`
+	Content  string   `yaml:"content"`
+	Message  string   `yaml:"message"`
+	Command  string   `yaml:"command"`
+	Args     []string `yaml:"args"`
+	After    string   `yaml:"after"`
+	Duration string   `yaml:"duration"`
+	Code     int      `yaml:"code"`
+	Text     string   `yaml:"text"`
+}
+
+// UnmarshalYAML decodes the bare and single-key map forms of an action.
+func (a *Action) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		a.Kind = Kind(node.Value)
+	case yaml.MappingNode:
+		if len(node.Content) != 2 {
+			return fmt.Errorf("line %d: action must have exactly one key", node.Line)
+		}
+		a.Kind = Kind(node.Content[0].Value)
+		arg := node.Content[1]
+		var f actionFields
+		switch arg.Kind {
+		case yaml.ScalarNode:
+			if err := a.setScalar(arg.Value); err != nil {
+				return fmt.Errorf("line %d: %w", arg.Line, err)
+			}
+			return a.validate(node.Line)
+		case yaml.MappingNode:
+			if err := arg.Decode(&f); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("line %d: %s: expected a value or a map", arg.Line, a.Kind)
+		}
+		if err := a.setFields(f); err != nil {
+			return fmt.Errorf("line %d: %w", arg.Line, err)
+		}
+	default:
+		return fmt.Errorf("line %d: action must be a name or a single-key map", node.Line)
+	}
+	return a.validate(node.Line)
+}
+
+// setScalar applies the shorthand argument of a single-key action.
+func (a *Action) setScalar(v string) error {
+	switch a.Kind {
+	case KindCompleteStep:
+		return a.setCount(v)
+	case KindCommit:
+		a.File = v
+	case KindRun:
+		a.Args = commandArgs(v)
+	case KindSleep, KindCrash:
+		return a.setDuration(v)
+	case KindExceedContext, KindRateLimit:
+		a.Text = v
+	default:
+		return fmt.Errorf("%s takes no argument", a.Kind)
+	}
+	return nil
+}
+
+// setFields applies the map form of an action's arguments.
+func (a *Action) setFields(f actionFields) error {
+	if f.Count != "" {
+		if err := a.setCount(f.Count); err != nil {
+			return err
+		}
+	}
+	a.File, a.Content, a.Message = f.File, f.Content, f.Message
+	a.Args = f.Args
+	if f.Command != "" {
+		a.Args = commandArgs(f.Command)
+	}
+	for _, d := range []string{f.After, f.Duration} {
+		if d != "" {
+			if err := a.setDuration(d); err != nil {
+				return err
+			}
+		}
+	}
+	a.Code = f.Code
+	a.Text = f.Text
+	return nil
+}
+
+func (a *Action) setCount(v string) error {
+	if v == "all" {
+		a.Count = 0
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return fmt.Errorf("%s: count must be a positive number or \"all\", got %q", a.Kind, v)
+	}
+	a.Count = n
+	return nil
+}
+
+func (a *Action) setDuration(v string) error {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", a.Kind, err)
+	}
+	a.Duration = d
+	return nil
+}
+
+// validate checks that the action is a known kind with its required fields.
+func (a *Action) validate(line int) error {
+	switch a.Kind {
+	case KindPrime, KindHook, KindCompleteStep, KindSleep, KindCrash, KindHang,
+		KindExceedContext, KindRateLimit, KindDone, KindHandoff, KindIdle:
+	case KindCommit:
+		if a.File == "" {
+			return fmt.Errorf("line %d: commit requires a file", line)
+		}
+	case KindRun:
+		if len(a.Args) == 0 {
+			return fmt.Errorf("line %d: run requires a command", line)
+		}
+	default:
+		return fmt.Errorf("line %d: unknown action %q", line, a.Kind)
+	}
+	return nil
+}
+
+// commandArgs splits a run command into gt arguments, dropping a leading "gt".
+func commandArgs(command string) []string {
+	args := strings.Fields(command)
+	if len(args) > 0 && args[0] == "gt" {
+		args = args[1:]
+	}
+	return args
+}
+
+// Script maps agents to the actions they play back.
+//
+//	agents:
+//	  gastown/polecats/Toast: [prime, hook, {crash: 30s}]
+//	  gastown/polecat: [prime, hook, complete_step, done]
+//	  witness: [prime, idle]
+//	default: [prime, hook, idle]
+//
+// Keys are tried from most to least specific: the agent's full GT_ROLE
+// address, "<rig>/<role>", then the bare role.
+type Script struct {
+	Agents  map[string][]Action `yaml:"agents"`
+	Default []Action            `yaml:"default"`
+}
+
+// DefaultScript is used when no script is configured: read prime and hook,
+// then wait for nudges like an idle agent.
+func DefaultScript() *Script {
+	return &Script{Default: []Action{{Kind: KindPrime}, {Kind: KindHook}, {Kind: KindIdle}}}
+}
+
+// Parse decodes a script.
+func Parse(data []byte) (*Script, error) {
+	var s Script
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing sim script: %w", err)
+	}
+	return &s, nil
+}
+
+// Load reads and decodes a script file.
+func Load(path string) (*Script, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is operator-configured
+	if err != nil {
+		return nil, fmt.Errorf("reading sim script: %w", err)
+	}
+	return Parse(data)
+}
+
+// ScriptPath resolves which script to play: an explicit path, then
+// $GT_SIM_SCRIPT, then the town's settings/sim.yaml. Returns "" when none
+// is configured and the default script applies.
+func ScriptPath(explicit, townRoot string) string {
+	if explicit != "" {
+		return explicit
+	}
+	if p := os.Getenv(EnvScript); p != "" {
+		return p
+	}
+	if townRoot != "" {
+		p := filepath.Join(townRoot, ScriptFile)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// Identity is who the sim agent is playing, taken from the session env.
+type Identity struct {
+	// Address is the compound GT_ROLE value (e.g. "gastown/polecats/Toast").
+	Address string
+	// Rig is the agent's rig, empty for town-level agents.
+	Rig string
+	// Role is the bare role (polecat, crew, witness, refinery, mayor, deacon, boot).
+	Role string
+}
+
+// IdentityFromEnv reads the identity Gas Town sets for every agent session.
+func IdentityFromEnv() Identity {
+	id := Identity{Address: os.Getenv("GT_ROLE"), Rig: os.Getenv("GT_RIG")}
+	switch {
+	case os.Getenv("GT_POLECAT") != "":
+		id.Role = "polecat"
+	case os.Getenv("GT_CREW") != "":
+		id.Role = "crew"
+	case id.Address != "":
+		id.Role = id.Address[strings.LastIndex(id.Address, "/")+1:]
+	}
+	return id
+}
+
+// ActionsFor returns the actions for an agent, falling back to the script's
+// default and then to DefaultScript.
+func (s *Script) ActionsFor(id Identity) []Action {
+	keys := []string{id.Address}
+	if id.Rig != "" && id.Role != "" {
+		keys = append(keys, id.Rig+"/"+id.Role)
+	}
+	keys = append(keys, id.Role)
+	for _, k := range keys {
+		if k == "" {
+			continue
+		}
+		if actions, ok := s.Agents[k]; ok {
+			return actions
+		}
+	}
+	if len(s.Default) > 0 {
+		return s.Default
+	}
+	return DefaultScript().Default
+}
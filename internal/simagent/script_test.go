@@ -0,0 +1,163 @@
+package simagent
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	s, err := Parse([]byte(`
+agents:
+  gastown/polecat:
+    - prime
+    - complete_step: 2
+    - commit: {file: a/b.txt, content: "hi", message: "Add b"}
+    - run: gt mail check --inject
+    - run: {args: [mail, send, mayor/, -s, "two words"]}
+    - crash: {after: 1s, code: 3}
+    - rate_limit
+default:
+  - sleep: 250ms
+  - complete_step: all
+  - exceed_context: "context window exceeded"
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := []Action{
+		{Kind: KindPrime},
+		{Kind: KindCompleteStep, Count: 2},
+		{Kind: KindCommit, File: "a/b.txt", Content: "hi", Message: "Add b"},
+		{Kind: KindRun, Args: []string{"mail", "check", "--inject"}},
+		{Kind: KindRun, Args: []string{"mail", "send", "mayor/", "-s", "two words"}},
+		{Kind: KindCrash, Duration: time.Second, Code: 3},
+		{Kind: KindRateLimit},
+	}
+	if got := s.Agents["gastown/polecat"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("polecat actions =\n%+v\nwant\n%+v", got, want)
+	}
+
+	wantDefault := []Action{
+		{Kind: KindSleep, Duration: 250 * time.Millisecond},
+		{Kind: KindCompleteStep},
+		{Kind: KindExceedContext, Text: "context window exceeded"},
+	}
+	if !reflect.DeepEqual(s.Default, wantDefault) {
+		t.Errorf("default actions = %+v, want %+v", s.Default, wantDefault)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"unknown action":  "default: [fly]",
+		"missing file":    "default: [commit]",
+		"missing command": "default: [run]",
+		"bad count":       "default: [{complete_step: some}]",
+		"bad duration":    "default: [{sleep: soon}]",
+		"extra argument":  "default: [{prime: now}]",
+		"two keys":        "default: [{sleep: 1s, hang: x}]",
+	}
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(src)); err == nil {
+				t.Errorf("Parse(%q) succeeded, want error", src)
+			}
+		})
+	}
+}
+
+func TestActionsFor(t *testing.T) {
+	s := &Script{
+		Agents: map[string][]Action{
+			"gastown/polecats/Toast": {{Kind: KindCrash}},
+			"gastown/polecat":        {{Kind: KindDone}},
+			"polecat":                {{Kind: KindHang}},
+			"witness":                {{Kind: KindIdle}},
+		},
+		Default: []Action{{Kind: KindPrime}},
+	}
+
+	tests := []struct {
+		name string
+		id   Identity
+		want Kind
+	}{
+		{"full address", Identity{Address: "gastown/polecats/Toast", Rig: "gastown", Role: "polecat"}, KindCrash},
+		{"rig and role", Identity{Address: "gastown/polecats/Nux", Rig: "gastown", Role: "polecat"}, KindDone},
+		{"bare role", Identity{Address: "beads/polecats/Nux", Rig: "beads", Role: "polecat"}, KindHang},
+		{"other role", Identity{Address: "beads/witness", Rig: "beads", Role: "witness"}, KindIdle},
+		{"default", Identity{Address: "mayor", Role: "mayor"}, KindPrime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.ActionsFor(tt.id); got[0].Kind != tt.want {
+				t.Errorf("ActionsFor(%+v) = %v, want %v", tt.id, got[0].Kind, tt.want)
+			}
+		})
+	}
+
+	if got := (&Script{}).ActionsFor(Identity{Role: "mayor"}); !reflect.DeepEqual(got, DefaultScript().Default) {
+		t.Errorf("empty script actions = %+v, want the default script", got)
+	}
+}
+
+func TestIdentityFromEnv(t *testing.T) {
+	t.Setenv("GT_ROLE", "gastown/polecats/Toast")
+	t.Setenv("GT_RIG", "gastown")
+	t.Setenv("GT_POLECAT", "Toast")
+	t.Setenv("GT_CREW", "")
+	if got, want := IdentityFromEnv(), (Identity{Address: "gastown/polecats/Toast", Rig: "gastown", Role: "polecat"}); got != want {
+		t.Errorf("polecat identity = %+v, want %+v", got, want)
+	}
+
+	t.Setenv("GT_ROLE", "deacon/boot")
+	t.Setenv("GT_RIG", "")
+	t.Setenv("GT_POLECAT", "")
+	if got := IdentityFromEnv(); got.Role != "boot" {
+		t.Errorf("boot role = %q, want boot", got.Role)
+	}
+}
+
+func TestScriptPath(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv(EnvScript, "")
+
+	if got := ScriptPath("", townRoot); got != "" {
+		t.Errorf("ScriptPath with no script = %q, want empty", got)
+	}
+
+	townScript := filepath.Join(townRoot, ScriptFile)
+	if err := os.MkdirAll(filepath.Dir(townScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(townScript, []byte("default: [idle]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := ScriptPath("", townRoot); got != townScript {
+		t.Errorf("ScriptPath = %q, want town script %q", got, townScript)
+	}
+
+	t.Setenv(EnvScript, "/tmp/env.yaml")
+	if got := ScriptPath("", townRoot); got != "/tmp/env.yaml" {
+		t.Errorf("ScriptPath = %q, want env script", got)
+	}
+	if got := ScriptPath("/tmp/flag.yaml", townRoot); got != "/tmp/flag.yaml" {
+		t.Errorf("ScriptPath = %q, want explicit script", got)
+	}
+
+	s, err := Load(townScript)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(s.Default) != 1 || s.Default[0].Kind != KindIdle {
+		t.Errorf("Load default = %+v", s.Default)
+	}
+	if _, err := Load(filepath.Join(townRoot, "missing.yaml")); err == nil || !strings.Contains(err.Error(), "reading sim script") {
+		t.Errorf("Load missing = %v, want read error", err)
+	}
+}
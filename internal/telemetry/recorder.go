@@ -90,6 +90,7 @@ type recorderInstruments struct {
 	agentStateTotal       metric.Int64Counter
 	polecatTotal          metric.Int64Counter
 	polecatRemoveTotal    metric.Int64Counter
+	polecatPoolTotal      metric.Int64Counter
 	slingTotal            metric.Int64Counter
 	mailTotal             metric.Int64Counter
 	nudgeTotal            metric.Int64Counter
@@ -180,6 +181,9 @@ func initInstruments() {
 		inst.polecatRemoveTotal, _ = m.Int64Counter("gastown.polecat.removes.total",
 			metric.WithDescription("Total polecat removals"),
 		)
+		inst.polecatPoolTotal, _ = m.Int64Counter("gastown.polecat.pool.total",
+			metric.WithDescription("Total polecat dispatches on pooled rigs, by hit or miss"),
+		)
 		inst.slingTotal, _ = m.Int64Counter("gastown.sling.dispatches.total",
 			metric.WithDescription("Total sling work dispatches"),
 		)
@@ -552,6 +556,28 @@ func RecordPolecatRemove(ctx context.Context, name string, err error) {
 	)
 }
 
+// RecordPolecatPool records a polecat dispatch on a rig with a warm pool
+// (metrics + log event). A hit handed out a warm polecat; a miss had to
+// prepare one from cold.
+func RecordPolecatPool(ctx context.Context, rig, name string, hit bool) {
+	initInstruments()
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	inst.polecatPoolTotal.Add(ctx, 1,
+		metric.WithAttributes(
+			attribute.String("rig", rig),
+			attribute.String("result", result),
+		),
+	)
+	emit(ctx, "polecat.pool", otellog.SeverityInfo,
+		otellog.String("rig", rig),
+		otellog.String("name", name),
+		otellog.String("result", result),
+	)
+}
+
 // RecordSling records a sling work dispatch (metrics + log event).
 func RecordSling(ctx context.Context, bead, target string, err error) {
 	initInstruments()
@@ -238,6 +238,14 @@ func TestRecordDaemonRestart(t *testing.T) {
 	RecordDaemonRestart(ctx, "polecat")
 }
 
+func TestRecordPolecatPool(t *testing.T) {
+	resetInstruments(t)
+	ctx := context.Background()
+
+	RecordPolecatPool(ctx, "gastown", "furiosa", true)
+	RecordPolecatPool(ctx, "gastown", "nux", false)
+}
+
 func TestRecordFormulaInstantiate(t *testing.T) {
 	resetInstruments(t)
 	ctx := context.Background()
@@ -111,7 +111,7 @@ func EnsureDoltContainerForTestMain() error {
 
 // RequireDoltContainer ensures a shared Dolt container is running. Skips the
 // test if Docker is not available.
-func RequireDoltContainer(t *testing.T) {
+func RequireDoltContainer(t testing.TB) {
 	t.Helper()
 	if !isDockerAvailable() {
 		t.Skip("Docker not available, skipping test")
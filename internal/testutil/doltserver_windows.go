@@ -24,7 +24,7 @@ func EnsureDoltContainerForTestMain() error {
 }
 
 // RequireDoltContainer is not supported on Windows CI.
-func RequireDoltContainer(t *testing.T) {
+func RequireDoltContainer(t testing.TB) {
 	t.Helper()
 	t.Skip("Docker not available on Windows CI")
 }
@@ -0,0 +1,276 @@
+package townbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// townPaths are the town-level files and directories a bundle carries,
+// relative to the town root. Runtime state (.runtime/, logs, quota and
+// session state) is deliberately left behind.
+var townPaths = []string{
+	"mayor/town.json",
+	"mayor/rigs.json",
+	"mayor/config.json",
+	"mayor/accounts.json",
+	"mayor/daemon.json",
+	"settings",
+	"hooks/registry.toml",
+	".beads/metadata.json",
+	".beads/config.yaml",
+	".beads/routes.jsonl",
+	".beads/formulas",
+	"plugins",
+}
+
+// rigPaths are the per-rig files and directories a bundle carries,
+// relative to the rig directory. The clones themselves come back from git.
+var rigPaths = []string{
+	"settings",
+	"plugins",
+}
+
+// ExportOptions controls what Export puts in a bundle.
+type ExportOptions struct {
+	// Redact replaces secrets (tokens, passwords, API keys, credentials in
+	// URLs) with Redacted.
+	Redact bool
+	// Dolt includes a SQL dump of every town and rig database.
+	Dolt bool
+	// GTVersion is recorded in the manifest.
+	GTVersion string
+	// Dump dumps one database as SQL. Nil uses DumpDatabase.
+	Dump func(dataDir, database string) ([]byte, error)
+}
+
+// entry is one file on its way into the archive.
+type entry struct {
+	name string
+	mode int64
+	data []byte
+}
+
+// Export writes a gzipped tar bundle of the town at townRoot to w and
+// returns its manifest.
+func Export(townRoot string, w io.Writer, opts ExportOptions) (*Manifest, error) {
+	home, _ := os.UserHomeDir()
+	m := &Manifest{
+		Version:    FormatVersion,
+		CreatedAt:  time.Now().UTC(),
+		GTVersion:  opts.GTVersion,
+		SourceRoot: townRoot,
+		SourceHome: home,
+		Files:      []File{},
+		Rigs:       []Rig{},
+	}
+	if town, err := config.LoadTownConfig(constants.MayorTownPath(townRoot)); err == nil {
+		m.TownName = town.Name
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return nil, fmt.Errorf("loading rigs config: %w", err)
+	}
+	var rigNames []string
+	if rigsConfig != nil {
+		for name, r := range rigsConfig.Rigs {
+			rig := Rig{Name: name, GitURL: r.GitURL, PushURL: r.PushURL, UpstreamURL: r.UpstreamURL}
+			if r.BeadsConfig != nil {
+				rig.Prefix = r.BeadsConfig.Prefix
+			}
+			if opts.Redact {
+				rig.GitURL, rig.PushURL, rig.UpstreamURL = util.RedactURL(rig.GitURL), util.RedactURL(rig.PushURL), util.RedactURL(rig.UpstreamURL)
+			}
+			m.Rigs = append(m.Rigs, rig)
+			rigNames = append(rigNames, name)
+		}
+	}
+	sort.Slice(m.Rigs, func(i, j int) bool { return m.Rigs[i].Name < m.Rigs[j].Name })
+
+	paths, err := collect(townRoot, townPaths)
+	if err != nil {
+		return nil, err
+	}
+	owner := make(map[string]string)
+	for _, name := range rigNames {
+		var rel []string
+		for _, p := range rigPaths {
+			rel = append(rel, name+"/"+p)
+		}
+		rigFiles, err := collect(townRoot, rel)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range rigFiles {
+			owner[p] = name
+		}
+		paths = append(paths, rigFiles...)
+	}
+	sort.Strings(paths)
+
+	var entries []entry
+	for _, p := range paths {
+		full := filepath.Join(townRoot, filepath.FromSlash(p))
+		info, err := os.Stat(full)
+		if err != nil {
+			return nil, err
+		}
+		if info.Size() > maxFileSize {
+			return nil, fmt.Errorf("%s is too large to bundle (%d bytes)", p, info.Size())
+		}
+		data, err := os.ReadFile(full)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Redact {
+			var fields []string
+			data, fields = redact(p, data)
+			for _, f := range fields {
+				m.Redacted = append(m.Redacted, p+": "+f)
+			}
+		}
+		m.Files = append(m.Files, File{
+			Path:   p,
+			Rig:    owner[p],
+			Mode:   uint32(info.Mode().Perm()),
+			Size:   int64(len(data)),
+			SHA256: checksum(data),
+		})
+		entries = append(entries, entry{name: filesDir + p, mode: int64(info.Mode().Perm()), data: data})
+	}
+
+	if opts.Dolt {
+		dump := opts.Dump
+		if dump == nil {
+			dump = DumpDatabase
+		}
+		dataDir := doltserver.DefaultConfig(townRoot).DataDir
+		dbs, err := doltserver.ListDatabases(townRoot)
+		if err != nil {
+			return nil, fmt.Errorf("listing databases: %w", err)
+		}
+		sort.Strings(dbs)
+		for _, db := range dbs {
+			if doltserver.IsSystemDatabase(db) {
+				continue
+			}
+			data, err := dump(dataDir, db)
+			if err != nil {
+				return nil, fmt.Errorf("dumping database %s: %w", db, err)
+			}
+			d := Dump{Database: db, Size: int64(len(data)), SHA256: checksum(data)}
+			m.Dumps = append(m.Dumps, d)
+			entries = append(entries, entry{name: d.archivePath(), mode: 0644, data: data})
+		}
+	}
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	entries = append([]entry{{name: ManifestName, mode: 0644, data: manifest}}, entries...)
+	if err := writeArchive(w, entries, m.CreatedAt); err != nil {
+		return nil, fmt.Errorf("writing bundle: %w", err)
+	}
+	return m, nil
+}
+
+// collect returns the regular files under each of rel (files or
+// directories, relative to root) as slash-separated relative paths.
+// Missing paths are skipped; symlinks and .git directories are not followed.
+func collect(root string, rel []string) ([]string, error) {
+	var out []string
+	for _, r := range rel {
+		start := filepath.Join(root, filepath.FromSlash(r))
+		err := filepath.WalkDir(start, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) && p == start {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() {
+				if d.Name() == ".git" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			relPath, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			out = append(out, filepath.ToSlash(relPath))
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("collecting %s: %w", r, err)
+		}
+	}
+	return out, nil
+}
+
+func writeArchive(w io.Writer, entries []entry, modTime time.Time) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{
+			Name:    e.name,
+			Mode:    e.mode,
+			Size:    int64(len(e.data)),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(e.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// DumpDatabase dumps one Dolt database as SQL with dolt dump. When the
+// server is running, dolt routes the dump through it.
+func DumpDatabase(dataDir, database string) ([]byte, error) {
+	tmp, err := os.MkdirTemp("", "gt-dump-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	out := filepath.Join(tmp, database+".sql")
+	cmd := exec.Command("dolt", "dump", "--result-format", "sql", "--file-name", out, "--force")
+	cmd.Dir = filepath.Join(dataDir, database)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("dolt dump: %w\n%s", err, output)
+	}
+	return os.ReadFile(out)
+}
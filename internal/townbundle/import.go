@@ -0,0 +1,302 @@
+package townbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxFileSize bounds a bundled config file (and the manifest), which Read
+// keeps in memory. Dolt dumps are spooled to disk instead and only have to
+// match the size the manifest declares for them.
+const maxFileSize = 16 << 20
+
+// Bundle is a validated bundle. Config files are held in memory; Dolt dumps
+// are spooled to a temporary directory that Close removes.
+type Bundle struct {
+	Manifest *Manifest
+	files    map[string][]byte // by archive path
+	dumps    map[string]string // archive path -> spooled dump file
+	tmpDir   string
+}
+
+// expected is what the manifest says an archive entry holds.
+type expected struct {
+	size int64
+	sum  string
+	dump bool
+	seen bool
+}
+
+// Read streams and validates a gzipped tar bundle. The manifest must come
+// first and be a supported version; every later entry must be listed in it,
+// and is checked against its declared size and checksum as it is read, so
+// nothing unlisted or oversized is ever buffered. Call Close when done.
+func Read(r io.Reader) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a town bundle: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("not a town bundle: %w", err)
+	}
+	if hdr.Name != ManifestName || hdr.Typeflag != tar.TypeReg {
+		return nil, fmt.Errorf("not a town bundle: %s is not the first entry", ManifestName)
+	}
+	if hdr.Size > maxFileSize {
+		return nil, fmt.Errorf("%s in bundle is too large (%d bytes)", ManifestName, hdr.Size)
+	}
+	raw, err := io.ReadAll(tr)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", ManifestName, err)
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(raw, m); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	want := make(map[string]*expected, len(m.Files)+len(m.Dumps))
+	for _, f := range m.Files {
+		want[f.archivePath()] = &expected{size: f.Size, sum: f.SHA256}
+	}
+	for _, d := range m.Dumps {
+		want[d.archivePath()] = &expected{size: d.Size, sum: d.SHA256, dump: true}
+	}
+
+	b := &Bundle{Manifest: m, files: make(map[string][]byte), dumps: make(map[string]string)}
+	if err := b.readEntries(tr, want); err != nil {
+		b.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *Bundle) readEntries(tr *tar.Reader, want map[string]*expected) error {
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("reading bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return fmt.Errorf("unexpected %q in bundle: only regular files are allowed", hdr.Name)
+		}
+		e, ok := want[hdr.Name]
+		if !ok {
+			return fmt.Errorf("bundle contains %s, which the manifest does not list", hdr.Name)
+		}
+		if e.seen {
+			return fmt.Errorf("duplicate %q in bundle", hdr.Name)
+		}
+		e.seen = true
+		if hdr.Size != e.size {
+			return fmt.Errorf("checksum mismatch for %s: bundle is corrupt or was modified", hdr.Name)
+		}
+		if e.dump {
+			err = b.spoolDump(hdr.Name, tr, e)
+		} else {
+			err = b.readFile(hdr.Name, tr, e)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(want) {
+		if !want[name].seen {
+			return fmt.Errorf("bundle is missing %s", name)
+		}
+	}
+	return nil
+}
+
+func (b *Bundle) readFile(name string, r io.Reader, e *expected) error {
+	if e.size > maxFileSize {
+		return fmt.Errorf("%s in bundle is too large (%d bytes)", name, e.size)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+	if checksum(data) != e.sum {
+		return fmt.Errorf("checksum mismatch for %s: bundle is corrupt or was modified", name)
+	}
+	b.files[name] = data
+	return nil
+}
+
+func (b *Bundle) spoolDump(name string, r io.Reader, e *expected) error {
+	if b.tmpDir == "" {
+		dir, err := os.MkdirTemp("", "gt-bundle-*")
+		if err != nil {
+			return err
+		}
+		b.tmpDir = dir
+	}
+	f, err := os.CreateTemp(b.tmpDir, "dump-*.sql")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), r); err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+	if hex.EncodeToString(h.Sum(nil)) != e.sum {
+		return fmt.Errorf("checksum mismatch for %s: bundle is corrupt or was modified", name)
+	}
+	b.dumps[name] = f.Name()
+	return f.Close()
+}
+
+// ReadFile reads and validates the bundle at path.
+func ReadFile(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Close removes the spooled Dolt dumps.
+func (b *Bundle) Close() {
+	if b.tmpDir != "" {
+		_ = os.RemoveAll(b.tmpDir)
+	}
+}
+
+// OpenDump opens the SQL dump of a database. It errors if the bundle has
+// none.
+func (b *Bundle) OpenDump(database string) (io.ReadCloser, error) {
+	p, ok := b.dumps[Dump{Database: database}.archivePath()]
+	if !ok {
+		return nil, fmt.Errorf("bundle has no dump of %s", database)
+	}
+	return os.Open(p)
+}
+
+// InstallTown writes the bundle's town-level files (everything not inside
+// a rig) under townRoot, rewriting the exporting machine's town root and
+// home directory to townRoot and home. It returns the paths written.
+func (b *Bundle) InstallTown(townRoot, home string) ([]string, error) {
+	return b.install(townRoot, home, "")
+}
+
+// InstallRig writes one rig's files under townRoot. Call it after the rig
+// has been re-cloned: the rig directory must not exist before the clone.
+func (b *Bundle) InstallRig(townRoot, home, rig string) ([]string, error) {
+	return b.install(townRoot, home, rig)
+}
+
+func (b *Bundle) install(townRoot, home, rig string) ([]string, error) {
+	rewrite := b.Manifest.Rewriter(townRoot, home)
+	var written []string
+	for _, f := range b.Manifest.Files {
+		if f.Rig != rig {
+			continue
+		}
+		data := b.files[f.archivePath()]
+		if isText(f.Path) {
+			data = []byte(rewrite.Replace(string(data)))
+		}
+		dst := filepath.Join(townRoot, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return written, err
+		}
+		mode := os.FileMode(f.Mode).Perm()
+		if mode == 0 {
+			mode = 0644
+		}
+		if err := os.WriteFile(dst, data, mode); err != nil {
+			return written, fmt.Errorf("writing %s: %w", f.Path, err)
+		}
+		written = append(written, f.Path)
+	}
+	return written, nil
+}
+
+// Rewriter maps the exporting machine's absolute paths onto townRoot and
+// home. The town root is tried first: it usually lives under home.
+func (m *Manifest) Rewriter(townRoot, home string) *strings.Replacer {
+	var pairs []string
+	if m.SourceRoot != "" && m.SourceRoot != townRoot {
+		pairs = append(pairs, m.SourceRoot, townRoot)
+	}
+	if m.SourceHome != "" && home != "" && m.SourceHome != home {
+		pairs = append(pairs, m.SourceHome, home)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// isText reports whether a bundled file is text that may embed paths.
+func isText(p string) bool {
+	switch path.Ext(p) {
+	case ".json", ".jsonl", ".toml", ".yaml", ".yml", ".txt", ".md", ".sh", ".tmpl", "":
+		return true
+	}
+	return false
+}
+
+// RestoreDatabase creates a Dolt database under dataDir from a SQL dump
+// and commits the result. The Dolt server must be stopped: the server
+// picks the database up when it next starts.
+func RestoreDatabase(dataDir, database string, dump io.Reader) error {
+	dir := filepath.Join(dataDir, database)
+	if _, err := os.Stat(filepath.Join(dir, ".dolt")); err == nil {
+		return fmt.Errorf("database %s already exists", database)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	run := func(stdin io.Reader, args ...string) error {
+		cmd := exec.Command("dolt", args...)
+		cmd.Dir = dir
+		if stdin != nil {
+			cmd.Stdin = stdin
+		}
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("dolt %s: %w\n%s", args[0], err, output)
+		}
+		return nil
+	}
+	if err := run(nil, "init"); err != nil {
+		return err
+	}
+	if err := run(dump, "sql"); err != nil {
+		return err
+	}
+	if err := run(nil, "add", "-A"); err != nil {
+		return err
+	}
+	return run(nil, "commit", "--allow-empty", "-m", "Restore from town bundle")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
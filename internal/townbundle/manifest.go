@@ -0,0 +1,121 @@
+// Package townbundle exports a town's configuration to a single versioned
+// archive and restores it elsewhere, for moving a workspace to a new machine
+// or cloning it. A bundle holds the town's config files (mayor/, settings/,
+// hooks registry, formulas, plugins and per-rig settings), a manifest with
+// checksums and the rigs' git URLs, and optionally SQL dumps of the Dolt
+// databases. Rig clones are not bundled: import re-clones them.
+package townbundle
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// FormatVersion is the bundle format this package writes. Read rejects
+// bundles written by a newer format.
+const FormatVersion = 1
+
+// Archive layout.
+const (
+	ManifestName = "manifest.json"
+	filesDir     = "town/"
+	dumpsDir     = "dolt/"
+)
+
+// Redacted replaces secret values in redacted bundles.
+const Redacted = "<redacted>"
+
+// Manifest describes a bundle's contents.
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	GTVersion string    `json:"gt_version,omitempty"`
+	TownName  string    `json:"town_name,omitempty"`
+	// SourceRoot and SourceHome are the town root and home directory on the
+	// exporting machine. Import rewrites them in text files.
+	SourceRoot string `json:"source_root"`
+	SourceHome string `json:"source_home,omitempty"`
+	Files      []File `json:"files"`
+	Rigs       []Rig  `json:"rigs"`
+	Dumps      []Dump `json:"dumps,omitempty"`
+	// Redacted lists "<file>: <key path>" for every value replaced with
+	// Redacted; import reports them so they can be filled back in.
+	Redacted []string `json:"redacted,omitempty"`
+}
+
+// File is a config file, relative to the town root.
+type File struct {
+	Path string `json:"path"`
+	// Rig is set for files inside a rig directory; they are restored after
+	// the rig is re-cloned.
+	Rig    string `json:"rig,omitempty"`
+	Mode   uint32 `json:"mode"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Rig is what import needs to re-clone a rig.
+type Rig struct {
+	Name        string `json:"name"`
+	GitURL      string `json:"git_url"`
+	PushURL     string `json:"push_url,omitempty"`
+	UpstreamURL string `json:"upstream_url,omitempty"`
+	Prefix      string `json:"prefix,omitempty"`
+}
+
+// Dump is a SQL dump of one Dolt database.
+type Dump struct {
+	Database string `json:"database"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+}
+
+func (d Dump) archivePath() string { return dumpsDir + d.Database + ".sql" }
+
+func (f File) archivePath() string { return filesDir + f.Path }
+
+// validate checks the manifest itself: the format version and that every
+// path stays inside the town.
+func (m *Manifest) validate() error {
+	if m.Version < 1 || m.Version > FormatVersion {
+		return fmt.Errorf("unsupported bundle version %d (this gt reads up to %d)", m.Version, FormatVersion)
+	}
+	seen := make(map[string]bool, len(m.Files))
+	for _, f := range m.Files {
+		if !safePath(f.Path) {
+			return fmt.Errorf("unsafe path in manifest: %q", f.Path)
+		}
+		if seen[f.Path] {
+			return fmt.Errorf("duplicate path in manifest: %q", f.Path)
+		}
+		seen[f.Path] = true
+		if f.Rig != "" && !strings.HasPrefix(f.Path, f.Rig+"/") {
+			return fmt.Errorf("file %q is not inside rig %q", f.Path, f.Rig)
+		}
+	}
+	for _, r := range m.Rigs {
+		if !safePath(r.Name) || strings.Contains(r.Name, "/") {
+			return fmt.Errorf("invalid rig name in manifest: %q", r.Name)
+		}
+		if r.GitURL == "" {
+			return fmt.Errorf("rig %q has no git URL", r.Name)
+		}
+	}
+	for _, d := range m.Dumps {
+		if !safePath(d.Database) || strings.Contains(d.Database, "/") {
+			return fmt.Errorf("invalid database name in manifest: %q", d.Database)
+		}
+	}
+	return nil
+}
+
+// safePath reports whether p is a clean relative path that cannot escape
+// the directory it is joined to.
+func safePath(p string) bool {
+	if p == "" || strings.Contains(p, `\`) || path.IsAbs(p) || path.Clean(p) != p {
+		return false
+	}
+	return p != ".." && !strings.HasPrefix(p, "../")
+}
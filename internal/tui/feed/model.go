@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -87,6 +88,7 @@ type Model struct {
 	events      []Event
 	convoyState *ConvoyState
 	townRoot    string
+	contextCfg  *config.ContextThresholds
 
 	// UI state
 	keys     KeyMap
@@ -112,7 +114,7 @@ type Model struct {
 	closeOnce sync.Once
 
 	// mu protects all fields read by View() from concurrent access:
-	// events, rigs, convoyState, eventChan, townRoot, width, height,
+	// events, rigs, convoyState, eventChan, townRoot, contextCfg, width, height,
 	// focusedPanel, showHelp, help, filter, viewMode, problemAgents,
 	// selectedProblem, selectedBeadID, problemsError, lastProblemsCheck,
 	// and all viewports. Write lock is held during Update/handleKey
@@ -152,11 +154,14 @@ func NewModelWithProblemsView(bd *beads.Beads) *Model {
 	return m
 }
 
-// SetTownRoot sets the town root for convoy fetching.
+// SetTownRoot sets the town root for convoy fetching and loads the town's
+// context-window thresholds for the problems view.
 // Safe to call concurrently with the Bubble Tea event loop.
 func (m *Model) SetTownRoot(townRoot string) {
+	contextCfg := config.LoadOperationalConfig(townRoot).GetContextConfig()
 	m.mu.Lock()
 	m.townRoot = townRoot
+	m.contextCfg = contextCfg
 	m.mu.Unlock()
 }
 
@@ -249,14 +254,19 @@ func (m *Model) convoyRefreshTick() tea.Cmd {
 	})
 }
 
-// fetchProblems returns a command that fetches problem agent data
+// fetchProblems returns a command that fetches problem agent data.
+// Captures contextCfg under the read lock to avoid racing with SetTownRoot.
 func (m *Model) fetchProblems() tea.Cmd {
 	detector := m.stuckDetector
+	m.mu.RLock()
+	contextCfg := m.contextCfg
+	m.mu.RUnlock()
 	return func() tea.Msg {
 		agents, err := detector.CheckAll()
 		if err != nil {
 			return problemsUpdateMsg{fetched: true, err: err}
 		}
+		detector.CheckContext(agents, contextCfg)
 		return problemsUpdateMsg{agents: agents, fetched: true}
 	}
 }
@@ -6,11 +6,14 @@
 package feed
 
 import (
+	"errors"
 	"strconv"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/ctxpressure"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -24,6 +27,14 @@ type HealthDataSource interface {
 	IsSessionAlive(sessionName string) (bool, error)
 }
 
+// ContextDataSource is optionally implemented by a HealthDataSource that can
+// read an agent session's context-window pressure.
+type ContextDataSource interface {
+	// ContextPressure returns the session's latest context reading, or nil
+	// when it is unknown.
+	ContextPressure(sessionName string, cfg *config.ContextThresholds) (*ctxpressure.Reading, error)
+}
+
 // AgentState represents the possible states for a GasTown agent.
 // Ordered by priority (most urgent first) for sorting.
 type AgentState int
@@ -122,6 +133,14 @@ type ProblemAgent struct {
 	ActionHint    string
 	CurrentBeadID string
 	HasHookedWork bool
+
+	// Context is the agent's context-window pressure; nil when unknown.
+	Context *ctxpressure.Reading
+	// ContextOver is set when Context is at or over the handoff threshold.
+	ContextOver bool
+	// ContextUnsupported is set when the agent's context pressure can't be
+	// read at all (see ctxpressure.ErrUnsupported).
+	ContextUnsupported bool
 }
 
 // NeedsAttention returns true if agent requires user action.
@@ -185,6 +204,31 @@ func (d *StuckDetector) CheckAll() ([]*ProblemAgent, error) {
 	return agents, nil
 }
 
+// CheckContext fills in the context-window pressure of live agents, when the
+// data source can read it. cfg supplies model windows and the handoff
+// threshold.
+func (d *StuckDetector) CheckContext(agents []*ProblemAgent, cfg *config.ContextThresholds) {
+	source, ok := d.source.(ContextDataSource)
+	if !ok {
+		return
+	}
+	for _, agent := range agents {
+		if agent.State == StateZombie {
+			continue
+		}
+		reading, err := source.ContextPressure(agent.SessionID, cfg)
+		if errors.Is(err, ctxpressure.ErrUnsupported) {
+			agent.ContextUnsupported = true
+			continue
+		}
+		if err != nil || reading == nil {
+			continue
+		}
+		agent.Context = reading
+		agent.ContextOver = reading.Over(cfg.HandoffThresholdV())
+	}
+}
+
 // analyzeAgent determines the health state of a single agent from its bead data.
 func (d *StuckDetector) analyzeAgent(id string, issue *beads.Issue) *ProblemAgent {
 	rig, role, name, ok := beads.ParseAgentBeadID(id)
@@ -343,3 +387,7 @@ func (s *defaultHealthSource) IsSessionAlive(sessionName string) (bool, error) {
 	status := s.tmux.CheckSessionHealth(sessionName, 0)
 	return status == tmux.SessionHealthy, nil
 }
+
+func (s *defaultHealthSource) ContextPressure(sessionName string, cfg *config.ContextThresholds) (*ctxpressure.Reading, error) {
+	return ctxpressure.ForSession(s.tmux, sessionName, cfg)
+}
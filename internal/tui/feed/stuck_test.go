@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/ctxpressure"
 )
 
 // mockHealthSource is a test double for HealthDataSource
@@ -788,3 +790,59 @@ func TestNudgeTarget(t *testing.T) {
 		})
 	}
 }
+
+// mockContextSource adds context readings (tokens by session) to mockHealthSource.
+type mockContextSource struct {
+	*mockHealthSource
+	tokens      map[string]int
+	unsupported map[string]bool
+}
+
+func (m *mockContextSource) ContextPressure(sessionName string, cfg *config.ContextThresholds) (*ctxpressure.Reading, error) {
+	if m.unsupported[sessionName] {
+		return nil, ctxpressure.ErrUnsupported
+	}
+	tokens, ok := m.tokens[sessionName]
+	if !ok {
+		return nil, nil
+	}
+	return &ctxpressure.Reading{Session: sessionName, Tokens: tokens, Window: cfg.WindowFor("")}, nil
+}
+
+// TestCheckContext tests that live agents get context readings flagged against the threshold
+func TestCheckContext(t *testing.T) {
+	agents := []*ProblemAgent{
+		{Name: "Toast", SessionID: "gt-Toast", State: StateWorking},
+		{Name: "Pearl", SessionID: "gt-Pearl", State: StateIdle},
+		{Name: "Ghost", SessionID: "gt-Ghost", State: StateZombie},
+		{Name: "Quiet", SessionID: "gt-Quiet", State: StateIdle},
+		{Name: "Gem", SessionID: "gt-Gem", State: StateWorking},
+	}
+	source := &mockContextSource{
+		mockHealthSource: newMockHealthSource(),
+		tokens:           map[string]int{"gt-Toast": 170_000, "gt-Pearl": 50_000, "gt-Ghost": 190_000},
+		unsupported:      map[string]bool{"gt-Gem": true},
+	}
+
+	NewStuckDetectorWithSource(source).CheckContext(agents, &config.ContextThresholds{})
+
+	if agents[0].Context == nil || agents[0].Context.Percent() != 85 || !agents[0].ContextOver {
+		t.Errorf("Toast: Context = %+v, over = %v; want 85%% over threshold", agents[0].Context, agents[0].ContextOver)
+	}
+	if agents[1].Context == nil || agents[1].ContextOver {
+		t.Errorf("Pearl: Context = %+v, over = %v; want reading under threshold", agents[1].Context, agents[1].ContextOver)
+	}
+	if agents[2].Context != nil || agents[3].Context != nil {
+		t.Errorf("zombie or unknown agents got readings: %+v, %+v", agents[2].Context, agents[3].Context)
+	}
+	if agents[3].ContextUnsupported || !agents[4].ContextUnsupported || agents[4].Context != nil {
+		t.Errorf("ContextUnsupported: Quiet = %v, Gem = %v; want only Gem", agents[3].ContextUnsupported, agents[4].ContextUnsupported)
+	}
+
+	// A source without context support leaves agents untouched.
+	plain := []*ProblemAgent{{SessionID: "gt-Toast", State: StateWorking}}
+	NewStuckDetectorWithSource(newMockHealthSource()).CheckContext(plain, nil)
+	if plain[0].Context != nil {
+		t.Errorf("plain source: Context = %+v, want nil", plain[0].Context)
+	}
+}
@@ -168,6 +168,22 @@ func (m *Model) renderProblemsContent() string {
 		lines = append(lines, "")
 	}
 
+	// CONTEXT PRESSURE section: live agents at or over the handoff threshold
+	var pressured []string
+	for _, agent := range m.problemAgents {
+		if agent.ContextOver && !agent.State.NeedsAttention() {
+			pressured = append(pressured, fmt.Sprintf("  %-12s  %s  %s  %s",
+				agent.Name, StalledStyle.Render(agent.Context.Badge()),
+				TimestampStyle.Render(fmt.Sprintf("%dk/%dk", agent.Context.Tokens/1000, agent.Context.Window/1000)),
+				RigStyle.Render(agent.Rig)))
+		}
+	}
+	if len(pressured) > 0 {
+		lines = append(lines, ProblemsHeaderStyle.Render(fmt.Sprintf("CONTEXT PRESSURE (%d)", len(pressured))))
+		lines = append(lines, pressured...)
+		lines = append(lines, "")
+	}
+
 	// WORKING section (collapsed dots by rig)
 	if len(workingAgents) > 0 {
 		lines = append(lines, WorkingHeaderStyle.Render(fmt.Sprintf("WORKING (%d)", len(workingAgents))))
@@ -240,7 +256,19 @@ func (m *Model) renderProblemAgent(agent *ProblemAgent, selected bool) string {
 		rigPart = RigStyle.Render(agent.Rig)
 	}
 
-	return prefix + namePart + "  " + statePart + "  " + TimestampStyle.Render(reasonPart) + "  " + beadPart + "  " + rigPart
+	// Context-window pressure (if known)
+	contextPart := ""
+	if agent.Context != nil {
+		contextPart = TimestampStyle.Render(agent.Context.Badge())
+		if agent.ContextOver {
+			contextPart = StalledStyle.Render(agent.Context.Badge())
+		}
+		contextPart += "  "
+	} else if agent.ContextUnsupported {
+		contextPart = TimestampStyle.Render("🧠 unsupported") + "  "
+	}
+
+	return prefix + namePart + "  " + statePart + "  " + TimestampStyle.Render(reasonPart) + "  " + contextPart + beadPart + "  " + rigPart
 }
 
 // getStateStyle returns the appropriate style for an agent state
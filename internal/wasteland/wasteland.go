@@ -5,6 +5,11 @@
 // to the commons' rigs table, and contribute wanted work items and
 // completions through DoltHub's fork/PR/merge primitives.
 //
+// A commons can also live on any other Dolt remote (file://, a self-hosted
+// remotesapi over http(s)://, or an S3-compatible aws:// store). There is no
+// fork API off DoltHub, so forking is a clone of the commons pushed to a
+// remote the rig owns; see NewRemoteService.
+//
 // See ~/hop/docs/wasteland/design.md for the full design.
 package wasteland
 
@@ -26,12 +31,18 @@ var ErrNotJoined = errors.New("rig has not joined a wasteland")
 
 // Config holds the wasteland configuration for a rig.
 type Config struct {
-	// Upstream is the DoltHub path of the upstream commons (e.g., "steveyegge/wl-commons").
+	// Upstream is the DoltHub path of the upstream commons (e.g., "steveyegge/wl-commons"),
+	// or its Dolt remote URL (e.g., "file:///srv/dolt/wl-commons").
 	Upstream string `json:"upstream"`
 
 	// ForkOrg is the DoltHub org where the fork lives (e.g., "alice-dev").
+	// For commons on other remotes it is the rig handle.
 	ForkOrg string `json:"fork_org"`
 
+	// ForkURL is the Dolt remote URL of the fork when the commons is not
+	// on DoltHub. Empty for DoltHub forks.
+	ForkURL string `json:"fork_url,omitempty"`
+
 	// ForkDB is the database name of the fork (e.g., "wl-commons").
 	ForkDB string `json:"fork_db"`
 
@@ -83,8 +94,23 @@ var dolthubAPIBase = "https://www.dolthub.com/api/v1alpha1"
 // dolthubRemoteBase is the Dolt remote API base URL.
 const dolthubRemoteBase = "https://doltremoteapi.dolthub.com"
 
+// remoteSchemes are the Dolt remote URL schemes a commons may live on.
+var remoteSchemes = []string{"file", "http", "https", "aws", "gs", "oci"}
+
+// IsRemoteURL reports whether upstream is a Dolt remote URL rather than a
+// DoltHub "org/database" path.
+func IsRemoteURL(upstream string) bool {
+	return strings.Contains(upstream, "://")
+}
+
 // ParseUpstream parses an upstream path like "steveyegge/wl-commons" into org and db.
+// For a remote URL, db is the last path element and org is derived from the
+// host, or for file:// URLs from the parent directory, so the local clone
+// gets a stable path.
 func ParseUpstream(upstream string) (org, db string, err error) {
+	if IsRemoteURL(upstream) {
+		return parseRemoteURL(upstream)
+	}
 	parts := strings.SplitN(upstream, "/", 2)
 	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
 		return "", "", fmt.Errorf("invalid upstream path %q: expected format 'org/database'", upstream)
@@ -92,6 +118,64 @@ func ParseUpstream(upstream string) (org, db string, err error) {
 	return parts[0], parts[1], nil
 }
 
+// UpstreamURL returns the Dolt remote URL to clone an upstream from: the
+// upstream itself when it is a remote URL, its DoltHub remote otherwise.
+func UpstreamURL(upstream string) (string, error) {
+	org, db, err := ParseUpstream(upstream)
+	if err != nil {
+		return "", err
+	}
+	if IsRemoteURL(upstream) {
+		return upstream, nil
+	}
+	return fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, org, db), nil
+}
+
+func parseRemoteURL(remote string) (org, db string, err error) {
+	scheme, rest, _ := strings.Cut(remote, "://")
+	supported := false
+	for _, s := range remoteSchemes {
+		if scheme == s {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return "", "", fmt.Errorf("unsupported remote URL %q: scheme must be one of %s", remote, strings.Join(remoteSchemes, ", "))
+	}
+
+	host, path, _ := strings.Cut(rest, "/")
+	path = strings.TrimRight(path, "/")
+	if path == "" {
+		return "", "", fmt.Errorf("invalid remote URL %q: no database path", remote)
+	}
+	parent, db := "", path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		parent, db = path[:i], path[i+1:]
+	}
+	switch {
+	case scheme == "file" && strings.Trim(parent, "/") != "":
+		// Distinct directories get distinct clones: file:///srv/dolt/wl-commons
+		// is org "srv-dolt".
+		org = sanitizeOrg(strings.Trim(parent, "/"))
+	case host != "":
+		org = sanitizeOrg(host)
+	default:
+		org = scheme
+	}
+	return org, db, nil
+}
+
+// sanitizeOrg maps s to a single path element for the local clone path.
+func sanitizeOrg(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, s)
+}
+
 // ForkDoltHubRepo forks a DoltHub database to the target org.
 // Uses the DoltHub fork API endpoint.
 func ForkDoltHubRepo(fromOrg, fromDB, toOrg, token string) error {
@@ -143,8 +227,12 @@ func ForkDoltHubRepo(fromOrg, fromDB, toOrg, token string) error {
 // CloneLocally clones a DoltHub database to a local directory.
 // Returns the absolute path to the clone.
 func CloneLocally(org, db, targetDir string) error {
-	remoteURL := fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, org, db)
+	return CloneURL(fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, org, db), targetDir)
+}
 
+// CloneURL clones a database from any Dolt remote URL to a local directory.
+// An existing clone is left as is.
+func CloneURL(remoteURL, targetDir string) error {
 	if err := os.MkdirAll(filepath.Dir(targetDir), 0755); err != nil {
 		return fmt.Errorf("creating parent directory: %w", err)
 	}
@@ -219,21 +307,25 @@ func PushToOrigin(localDir string) error {
 
 // AddUpstreamRemote adds the upstream commons as a remote named "upstream".
 func AddUpstreamRemote(localDir, upstreamOrg, upstreamDB string) error {
-	url := fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, upstreamOrg, upstreamDB)
+	return AddRemote(localDir, "upstream", fmt.Sprintf("%s/%s/%s", dolthubRemoteBase, upstreamOrg, upstreamDB))
+}
 
-	// Check if upstream remote already exists
+// AddRemote adds a named remote to the local clone. An existing remote of
+// the same name is left as is.
+func AddRemote(localDir, name, url string) error {
+	// Check if the remote already exists
 	checkCmd := exec.Command("dolt", "remote", "-v")
 	checkCmd.Dir = localDir
 	output, err := checkCmd.CombinedOutput()
 	if err == nil {
 		for _, line := range strings.Split(string(output), "\n") {
-			if strings.HasPrefix(strings.TrimSpace(line), "upstream") {
+			if fields := strings.Fields(line); len(fields) > 0 && fields[0] == name {
 				return nil // already exists
 			}
 		}
 	}
 
-	cmd := exec.Command("dolt", "remote", "add", "upstream", url)
+	cmd := exec.Command("dolt", "remote", "add", name, url)
 	cmd.Dir = localDir
 	output, err = cmd.CombinedOutput()
 	if err != nil {
@@ -241,11 +333,90 @@ func AddUpstreamRemote(localDir, upstreamOrg, upstreamDB string) error {
 		if strings.Contains(strings.ToLower(msg), "already exists") {
 			return nil
 		}
-		return fmt.Errorf("dolt remote add upstream: %w (%s)", err, msg)
+		return fmt.Errorf("dolt remote add %s: %w (%s)", name, err, msg)
 	}
 	return nil
 }
 
+// CloneAndPush forks a commons on a generic Dolt remote: it clones the
+// upstream into a scratch directory and pushes it to the fork remote.
+// A fork that already exists is left alone, the same way DoltHub treats
+// forking into an org that already has the database. A push the fork
+// rejects for any other reason is an error.
+func CloneAndPush(upstreamURL, forkURL string) error {
+	tmpDir, err := os.MkdirTemp("", "wl-fork-*")
+	if err != nil {
+		return fmt.Errorf("creating scratch directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cloneDir := filepath.Join(tmpDir, "commons")
+	if err := CloneURL(upstreamURL, cloneDir); err != nil {
+		return err
+	}
+	if err := AddRemote(cloneDir, "fork", forkURL); err != nil {
+		return err
+	}
+
+	// Dolt file remotes write into an existing directory.
+	if path, ok := strings.CutPrefix(forkURL, "file://"); ok {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return fmt.Errorf("creating fork directory: %w", err)
+		}
+	}
+
+	if remoteHasMain(cloneDir, "fork") {
+		return nil // fork already exists
+	}
+
+	cmd := exec.Command("dolt", "push", "fork", "main")
+	cmd.Dir = cloneDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(output))
+		if pushUpToDate(msg) {
+			return nil
+		}
+		return fmt.Errorf("dolt push %s: %w (%s)", forkURL, err, msg)
+	}
+	return nil
+}
+
+// remoteHasMain reports whether the named remote of the clone in dir has a
+// main branch. A remote that can't be fetched (e.g. doesn't exist yet) has
+// none.
+func remoteHasMain(dir, remote string) bool {
+	fetch := exec.Command("dolt", "fetch", remote)
+	fetch.Dir = dir
+	if err := fetch.Run(); err != nil {
+		return false
+	}
+	branches := exec.Command("dolt", "branch", "-r")
+	branches.Dir = dir
+	out, err := branches.Output()
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		name := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		if name == "remotes/"+remote+"/main" || name == remote+"/main" {
+			return true
+		}
+	}
+	return false
+}
+
+// pushUpToDate reports whether a failed dolt push only failed because the
+// remote already has the branch as pushed.
+func pushUpToDate(output string) bool {
+	lower := strings.ToLower(output)
+	if strings.Contains(lower, "rejected") || strings.Contains(lower, "non-fast-forward") {
+		return false
+	}
+	return strings.Contains(lower, "up to date") || strings.Contains(lower, "up-to-date") ||
+		strings.Contains(lower, "already exists")
+}
+
 // WastelandDir returns the directory where wasteland data is stored for a town.
 func WastelandDir(townRoot string) string {
 	return filepath.Join(townRoot, ".wasteland")
@@ -287,6 +458,10 @@ type Service struct {
 	CLI        DoltCLI
 	Config     ConfigStore
 	OnProgress func(step string) // optional callback for progress reporting
+
+	// ForkURL is recorded in the saved config for forks that are not on
+	// DoltHub (set by NewRemoteService).
+	ForkURL string
 }
 
 // Join orchestrates the wasteland join workflow: fork -> clone -> add upstream -> register -> push -> save config.
@@ -342,6 +517,7 @@ func (s *Service) Join(upstream, forkOrg, token, handle, displayName, ownerEmail
 		Upstream:  upstream,
 		ForkOrg:   forkOrg,
 		ForkDB:    upstreamDB,
+		ForkURL:   s.ForkURL,
 		LocalDir:  localDir,
 		RigHandle: handle,
 		JoinedAt:  time.Now(),
@@ -376,6 +552,30 @@ func (e *execDoltCLI) AddUpstreamRemote(localDir, upstreamOrg, upstreamDB string
 	return AddUpstreamRemote(localDir, upstreamOrg, upstreamDB)
 }
 
+// remoteForkAPI implements DoltHubAPI for commons on generic Dolt remotes.
+// The org and database arguments are ignored: the remotes are fixed URLs.
+type remoteForkAPI struct {
+	upstreamURL, forkURL string
+}
+
+func (r *remoteForkAPI) ForkRepo(fromOrg, fromDB, toOrg, token string) error {
+	return CloneAndPush(r.upstreamURL, r.forkURL)
+}
+
+// remoteDoltCLI implements DoltCLI for commons on generic Dolt remotes,
+// cloning from the fork URL and tracking the upstream URL.
+type remoteDoltCLI struct {
+	execDoltCLI
+	upstreamURL, forkURL string
+}
+
+func (r *remoteDoltCLI) Clone(org, db, targetDir string) error {
+	return CloneURL(r.forkURL, targetDir)
+}
+func (r *remoteDoltCLI) AddUpstreamRemote(localDir, upstreamOrg, upstreamDB string) error {
+	return AddRemote(localDir, "upstream", r.upstreamURL)
+}
+
 // fileConfigStore implements ConfigStore using filesystem persistence.
 type fileConfigStore struct{}
 
@@ -394,3 +594,15 @@ func NewService() *Service {
 		Config: &fileConfigStore{},
 	}
 }
+
+// NewRemoteService creates a Service for a commons on a generic Dolt remote
+// (file://, http(s):// remotesapi, aws:// and so on). Forking clones the
+// upstream and pushes it to forkURL; no DoltHub credentials are needed.
+func NewRemoteService(upstreamURL, forkURL string) *Service {
+	return &Service{
+		API:     &remoteForkAPI{upstreamURL: upstreamURL, forkURL: forkURL},
+		CLI:     &remoteDoltCLI{upstreamURL: upstreamURL, forkURL: forkURL},
+		Config:  &fileConfigStore{},
+		ForkURL: forkURL,
+	}
+}
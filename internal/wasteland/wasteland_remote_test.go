@@ -0,0 +1,108 @@
+package wasteland
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// dolt runs a dolt command in dir and returns its trimmed output.
+func dolt(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("dolt", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("dolt %s: %v (%s)", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// fileCommons publishes a minimal commons to a file:// remote and returns
+// its URL along with the directory of the publishing clone.
+func fileCommons(t *testing.T) (url, srcDir string) {
+	t.Helper()
+	if _, err := exec.LookPath("dolt"); err != nil {
+		t.Skip("dolt not found in PATH")
+	}
+	root := t.TempDir()
+	t.Setenv("DOLT_ROOT_PATH", filepath.Join(root, "dolt-home"))
+	dolt(t, root, "config", "--global", "--add", "user.name", "test")
+	dolt(t, root, "config", "--global", "--add", "user.email", "test@example.com")
+
+	srcDir = filepath.Join(root, "src")
+	url = "file://" + filepath.Join(root, "remotes", "wl-commons")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	dolt(t, srcDir, "init")
+	dolt(t, srcDir, "sql", "-q", `CREATE TABLE rigs (
+		handle VARCHAR(255) PRIMARY KEY, display_name VARCHAR(255), dolthub_org VARCHAR(255),
+		owner_email VARCHAR(255), gt_version VARCHAR(32), trust_level INT,
+		registered_at TIMESTAMP, last_seen TIMESTAMP)`)
+	dolt(t, srcDir, "sql", "-q", "CREATE TABLE wanted (id VARCHAR(64) PRIMARY KEY, title TEXT)")
+	dolt(t, srcDir, "add", ".")
+	dolt(t, srcDir, "commit", "-m", "commons schema")
+	if err := AddRemote(srcDir, "origin", url); err != nil {
+		t.Fatal(err)
+	}
+	dolt(t, srcDir, "push", "origin", "main")
+	return url, srcDir
+}
+
+func TestJoinRemote_FileURL(t *testing.T) {
+	upstreamURL, srcDir := fileCommons(t)
+	townRoot := t.TempDir()
+	forkURL := "file://" + filepath.Join(t.TempDir(), "forks", "alice")
+
+	svc := NewRemoteService(upstreamURL, forkURL)
+	svc.Config = NewFakeConfigStore()
+	cfg, err := svc.Join(upstreamURL, "alice-rig", "", "alice-rig", "Alice", "alice@example.com", "dev", townRoot)
+	if err != nil {
+		t.Fatalf("Join() error: %v", err)
+	}
+	if cfg.ForkURL != forkURL || cfg.ForkDB != "wl-commons" {
+		t.Errorf("config = %+v", cfg)
+	}
+
+	// The registration reached the fork, not the upstream.
+	check := filepath.Join(t.TempDir(), "check")
+	if err := CloneURL(forkURL, check); err != nil {
+		t.Fatal(err)
+	}
+	if got := dolt(t, check, "sql", "-r", "csv", "-q", "SELECT handle FROM rigs"); !strings.Contains(got, "alice-rig") {
+		t.Errorf("fork rigs = %q, want alice-rig registered", got)
+	}
+	dolt(t, srcDir, "pull", "origin", "main")
+	if got := dolt(t, srcDir, "sql", "-r", "csv", "-q", "SELECT COUNT(*) FROM rigs"); !strings.HasSuffix(got, "0") {
+		t.Errorf("upstream rigs count = %q, want 0", got)
+	}
+
+	// gt wl sync pulls new upstream work into the local clone.
+	dolt(t, srcDir, "sql", "-q", "INSERT INTO wanted VALUES ('w-1', 'Fix the pump')")
+	dolt(t, srcDir, "add", ".")
+	dolt(t, srcDir, "commit", "-m", "post w-1")
+	dolt(t, srcDir, "push", "origin", "main")
+	dolt(t, cfg.LocalDir, "pull", "upstream", "main")
+	if got := dolt(t, cfg.LocalDir, "sql", "-r", "csv", "-q", "SELECT title FROM wanted"); !strings.Contains(got, "Fix the pump") {
+		t.Errorf("local wanted after sync = %q", got)
+	}
+
+	// Joining again with an existing fork is a no-op fork.
+	if err := CloneAndPush(upstreamURL, forkURL); err != nil {
+		t.Errorf("CloneAndPush onto existing fork: %v", err)
+	}
+}
+
+func TestCloneAndPush_FailedPushIsError(t *testing.T) {
+	upstreamURL, _ := fileCommons(t)
+	blocker := filepath.Join(t.TempDir(), "not-a-dir")
+	if err := os.WriteFile(blocker, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := CloneAndPush(upstreamURL, "file://"+filepath.Join(blocker, "fork")); err == nil {
+		t.Error("CloneAndPush to an unwritable fork should fail")
+	}
+}
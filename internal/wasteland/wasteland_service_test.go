@@ -227,3 +227,25 @@ func TestJoin_InvalidUpstream(t *testing.T) {
 		t.Fatal("Join() expected error for invalid upstream")
 	}
 }
+
+func TestJoin_RecordsForkURL(t *testing.T) {
+	t.Parallel()
+	cfgStore := NewFakeConfigStore()
+	svc := &Service{
+		API:     NewFakeDoltHubAPI(),
+		CLI:     NewFakeDoltCLI(),
+		Config:  cfgStore,
+		ForkURL: "file:///srv/dolt/forks/alice",
+	}
+
+	cfg, err := svc.Join("file:///srv/dolt/wl-commons", "alice-rig", "", "alice-rig", "Alice", "alice@example.com", "dev", "/tmp/town")
+	if err != nil {
+		t.Fatalf("Join() error: %v", err)
+	}
+	if cfg.ForkURL != "file:///srv/dolt/forks/alice" || cfg.ForkDB != "wl-commons" {
+		t.Errorf("config = %+v, want fork URL and db recorded", cfg)
+	}
+	if want := LocalCloneDir("/tmp/town", "srv-dolt", "wl-commons"); cfg.LocalDir != want {
+		t.Errorf("LocalDir = %q, want %q", cfg.LocalDir, want)
+	}
+}
@@ -24,6 +24,14 @@ func TestParseUpstream(t *testing.T) {
 		{"empty db", "steveyegge/", "", "", true},
 		{"empty", "", "", "", true},
 		{"multiple slashes", "a/b/c", "a", "b/c", false},
+		{"file URL", "file:///srv/dolt/wl-commons", "srv-dolt", "wl-commons", false},
+		{"file URL trailing slash", "file:///srv/dolt/wl-commons/", "srv-dolt", "wl-commons", false},
+		{"file URL other directory", "file:///home/alice/forks/wl-commons", "home-alice-forks", "wl-commons", false},
+		{"file URL at root", "file:///wl-commons", "file", "wl-commons", false},
+		{"remotesapi URL", "https://dolt.corp:50051/team/wl-commons", "dolt.corp-50051", "wl-commons", false},
+		{"aws URL", "aws://[wl-table:wl-bucket]/wl-commons", "-wl-table-wl-bucket-", "wl-commons", false},
+		{"unsupported scheme", "ftp://host/wl-commons", "", "", true},
+		{"URL without database", "file:///", "", "", true},
 	}
 
 	for _, tt := range tests {
@@ -49,6 +57,44 @@ func TestParseUpstream(t *testing.T) {
 	}
 }
 
+func TestUpstreamURL(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"hop/wl-commons", "https://doltremoteapi.dolthub.com/hop/wl-commons"},
+		{"file:///srv/dolt/wl-commons", "file:///srv/dolt/wl-commons"},
+		{"https://dolt.corp:50051/team/wl-commons", "https://dolt.corp:50051/team/wl-commons"},
+	}
+	for _, tt := range tests {
+		got, err := UpstreamURL(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("UpstreamURL(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+	if _, err := UpstreamURL("wl-commons"); err == nil {
+		t.Error("UpstreamURL(wl-commons) expected error")
+	}
+}
+
+func TestPushUpToDate(t *testing.T) {
+	tests := []struct {
+		output string
+		want   bool
+	}{
+		{"Everything up-to-date", true},
+		{"branch main is already up to date", true},
+		{"error: failed to push some refs\n ! [rejected] main -> main (non-fast-forward)", false},
+		{"hint: Updates were rejected because the tip of your current branch is behind", false},
+		{"permission denied", false},
+	}
+	for _, tt := range tests {
+		if got := pushUpToDate(tt.output); got != tt.want {
+			t.Errorf("pushUpToDate(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+}
+
 func TestConfigSaveLoad(t *testing.T) {
 	tmpDir := t.TempDir()
 	mayorDir := filepath.Join(tmpDir, "mayor")
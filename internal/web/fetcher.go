@@ -46,12 +46,17 @@ func runCmd(timeout time.Duration, name string, args ...string) (*bytes.Buffer,
 var fetcherRunCmd = runCmd
 
 // runBdCmd executes a bd command with the configured cmdTimeout in the specified beads directory.
+// Read-only list/show queries against the default bd are served in-process
+// when possible (see beads.QueryJSON).
 func (f *LiveConvoyFetcher) runBdCmd(beadsDir string, args ...string) (*bytes.Buffer, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), f.cmdTimeout)
 	defer cancel()
 
 	bin := f.bdBin
 	if bin == "" {
+		if out, ok := beads.QueryJSON(ctx, beads.ResolveBeadsDir(beadsDir), args); ok {
+			return bytes.NewBuffer(out), nil
+		}
 		bin = "bd"
 	}
 	cmd := exec.CommandContext(ctx, bin, args...)
@@ -1,6 +1,7 @@
 package witness
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -62,9 +63,19 @@ type BdCli struct {
 }
 
 // DefaultBdCli returns a BdCli that shells out to the real bd binary.
+// Read-only list/show queries are served in-process when possible (see
+// beads.QueryJSON), which keeps patrol from spawning a bd per agent bead.
 func DefaultBdCli() *BdCli {
 	return &BdCli{
 		Exec: func(workDir string, args ...string) (string, error) {
+			// bd itself honors an inherited BEADS_DIR over the workDir's.
+			beadsDir := os.Getenv("BEADS_DIR")
+			if beadsDir == "" {
+				beadsDir = beads.ResolveBeadsDir(workDir)
+			}
+			if out, ok := beads.QueryJSON(context.Background(), beadsDir, args); ok {
+				return string(out), nil
+			}
 			return util.ExecWithOutput(workDir, "bd", args...)
 		},
 		Run: func(workDir string, args ...string) error {
@@ -383,7 +394,9 @@ func handleMergedCleanupStatus(_, _, polecatName, cleanupStatus, wispID string,
 }
 
 // HandleMergeFailed processes a MERGE_FAILED message from the Refinery.
-// Notifies the polecat that their merge was rejected and rework is needed.
+// Notifies the polecat that their merge was rejected and rework is needed,
+// unless only known-flaky tests failed: the MR is then requeued by the
+// Refinery and there is nothing for the polecat to fix.
 func HandleMergeFailed(workDir, rigName string, msg *mail.Message, router *mail.Router) *HandlerResult {
 	result := &HandlerResult{
 		MessageID:    msg.ID,
@@ -397,6 +410,13 @@ func HandleMergeFailed(workDir, rigName string, msg *mail.Message, router *mail.
 		return result
 	}
 
+	if payload.FailureType == FailureTypeFlaky {
+		result.Handled = true
+		result.Action = fmt.Sprintf("known-flaky failure for %s (%s), no rework requested",
+			payload.PolecatName, strings.Join(payload.FlakyTests, ", "))
+		return result
+	}
+
 	// Nudge the polecat about the failure instead of sending permanent mail.
 	initRegistryFromWorkDir(workDir)
 	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), payload.PolecatName)
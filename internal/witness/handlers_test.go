@@ -10,6 +10,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -1799,3 +1800,23 @@ func TestZombieAgentSelfReportedStuck_Classification(t *testing.T) {
 	}
 }
 
+
+func TestHandleMergeFailed_FlakyNoRework(t *testing.T) {
+	t.Parallel()
+	msg := &mail.Message{
+		ID:      "msg-1",
+		Subject: "MERGE_FAILED nux",
+		Body:    "Branch: polecat/nux\nFailureType: flaky\nFlaky-Tests: p.TestRace\nError: tests failed",
+	}
+
+	result := HandleMergeFailed(t.TempDir(), "testrig", msg, nil)
+	if result.Error != nil {
+		t.Fatalf("HandleMergeFailed() error = %v", result.Error)
+	}
+	if !result.Handled {
+		t.Error("flaky MERGE_FAILED should be handled")
+	}
+	if !strings.Contains(result.Action, "no rework") || !strings.Contains(result.Action, "p.TestRace") {
+		t.Errorf("Action = %q, want a no-rework note naming the flaky test", result.Action)
+	}
+}
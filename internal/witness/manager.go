@@ -47,8 +47,8 @@ func NewManager(r *rig.Rig) *Manager {
 // ZFC: tmux session existence is the source of truth for session state,
 // but agent liveness determines if the session is actually functional.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
-	status := t.CheckSessionHealth(m.SessionName(), 0)
+	sessionName := m.SessionName()
+	status := session.Health(session.BackendFor(m.townRoot(), sessionName), sessionName, 0)
 	return status == tmux.SessionHealthy, nil
 }
 
@@ -58,8 +58,8 @@ func (m *Manager) IsRunning() (bool, error) {
 // Returns the detailed ZombieStatus for callers that need to distinguish
 // between different failure modes.
 func (m *Manager) IsHealthy(maxInactivity time.Duration) tmux.ZombieStatus {
-	t := tmux.NewTmux()
-	return t.CheckSessionHealth(m.SessionName(), maxInactivity)
+	sessionName := m.SessionName()
+	return session.Health(session.BackendFor(m.townRoot(), sessionName), sessionName, maxInactivity)
 }
 
 // SessionName returns the tmux session name for this witness.
@@ -70,8 +70,8 @@ func (m *Manager) SessionName() string {
 // Status returns information about the witness session.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
+	t := session.BackendFor(m.townRoot(), sessionID)
 
 	running, err := t.HasSession(sessionID)
 	if err != nil {
@@ -81,7 +81,7 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	return session.Info(t, sessionID)
 }
 
 // witnessDir returns the working directory for the witness.
@@ -107,8 +107,8 @@ func (m *Manager) witnessDir() string {
 // envOverrides are KEY=VALUE pairs that override all other env var sources.
 // ZFC-compliant: no state file, tmux session is source of truth.
 func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []string) error {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
+	t := session.BackendFor(m.townRoot(), sessionID)
 
 	if foreground {
 		// Foreground mode is deprecated - patrol logic moved to mol-witness-patrol
@@ -128,20 +128,20 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 		// dead during initialization. Record session creation time, wait
 		// briefly, then re-verify before killing to avoid destroying a
 		// session that just became healthy.
-		createdAt, _ := t.GetSessionCreatedUnix(sessionID)
+		createdAt := session.CreatedUnix(t, sessionID)
 		time.Sleep(constants.ZombieKillGracePeriod)
 
 		// Re-check: abort kill if agent started or session was replaced
 		if t.IsAgentAlive(sessionID) {
 			return ErrAlreadyRunning
 		}
-		if createdNow, _ := t.GetSessionCreatedUnix(sessionID); createdAt > 0 && createdNow != createdAt {
+		if createdNow := session.CreatedUnix(t, sessionID); createdAt > 0 && createdNow != createdAt {
 			// Session was replaced between checks — another process already
 			// handled the zombie. Treat as already running; caller can retry.
 			return ErrAlreadyRunning
 		}
 
-		if err := t.KillSession(sessionID); err != nil {
+		if err := session.Kill(t, sessionID); err != nil {
 			return fmt.Errorf("killing zombie session: %w", err)
 		}
 	}
@@ -189,6 +189,7 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
+	t = session.NewBackend(townRoot)
 	if err := t.NewSessionWithCommand(sessionID, witnessDir, command); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
@@ -220,17 +221,17 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 
 	// Apply Gas Town theming (non-fatal: theming failure doesn't affect operation)
 	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "witness", "witness")
+	_ = session.ApplyTheme(t, sessionID, theme, m.rig.Name, "witness", "witness")
 
 	// Wait for Claude to start - fatal if Claude fails to launch
-	if err := t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+	if err := session.WaitForCommand(t, sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 		// Kill the zombie session before returning error
 		_ = t.KillSessionWithProcesses(sessionID)
 		return fmt.Errorf("waiting for witness to start: %w", err)
 	}
 
 	// Accept startup dialogs (workspace trust + bypass permissions) if they appear.
-	if err := t.AcceptStartupDialogs(sessionID); err != nil {
+	if err := session.AcceptStartupDialogs(t, sessionID); err != nil {
 		log.Printf("warning: accepting startup dialogs for %s: %v", sessionID, err)
 	}
 
@@ -314,8 +315,8 @@ func buildWitnessStartCommand(rigPath, rigName, townRoot, sessionName, agentOver
 // Stop stops the witness.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
+	t := session.BackendFor(m.townRoot(), sessionID)
 
 	// Check if tmux session exists
 	running, _ := t.HasSession(sessionID)
@@ -324,5 +325,5 @@ func (m *Manager) Stop() error {
 	}
 
 	// Kill the tmux session
-	return t.KillSession(sessionID)
+	return session.Kill(t, sessionID)
 }
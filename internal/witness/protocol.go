@@ -125,11 +125,16 @@ type MergeFailedPayload struct {
 	PolecatName string
 	Branch      string
 	IssueID     string
-	FailureType string // "build", "test", "lint", etc.
+	FailureType string // "build", "test", "lint", FailureTypeFlaky, etc.
 	Error       string
+	FlakyTests  []string // known-flaky tests among the failures
 	FailedAt    time.Time
 }
 
+// FailureTypeFlaky marks a merge failure caused entirely by known-flaky
+// tests. The MR stays queued for retry and no rework is requested.
+const FailureTypeFlaky = "flaky"
+
 // SwarmStartPayload contains parsed data from a SWARM_START message.
 type SwarmStartPayload struct {
 	SwarmID   string
@@ -281,6 +286,7 @@ func ParseMerged(subject, body string) (*MergedPayload, error) {
 //	Issue: <issue-id>
 //	FailureType: <type>
 //	Error: <error-message>
+//	Flaky-Tests: <test>, <test>   (optional)
 func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 	matches := PatternMergeFailed.FindStringSubmatch(subject)
 	if len(matches) < 2 {
@@ -304,6 +310,10 @@ func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 			payload.FailureType = strings.TrimSpace(strings.TrimPrefix(line, "FailureType:"))
 		case strings.HasPrefix(line, "Error:"):
 			payload.Error = strings.TrimSpace(strings.TrimPrefix(line, "Error:"))
+		case strings.HasPrefix(line, "Flaky-Tests:"):
+			if tests := strings.TrimSpace(strings.TrimPrefix(line, "Flaky-Tests:")); tests != "" {
+				payload.FlakyTests = strings.Split(tests, ", ")
+			}
 		}
 	}
 
@@ -521,3 +521,22 @@ func TestExitTypeMatchesPolecatDonePayload(t *testing.T) {
 		}
 	}
 }
+
+func TestParseMergeFailed_FlakyTests(t *testing.T) {
+	t.Parallel()
+	body := `Branch: feature-nux
+FailureType: flaky
+Flaky-Tests: p.TestRace, p.TestTimer
+Error: tests failed`
+
+	payload, err := ParseMergeFailed("MERGE_FAILED nux", body)
+	if err != nil {
+		t.Fatalf("ParseMergeFailed() error = %v", err)
+	}
+	if payload.FailureType != FailureTypeFlaky {
+		t.Errorf("FailureType = %q, want %q", payload.FailureType, FailureTypeFlaky)
+	}
+	if len(payload.FlakyTests) != 2 || payload.FlakyTests[1] != "p.TestTimer" {
+		t.Errorf("FlakyTests = %v, want [p.TestRace p.TestTimer]", payload.FlakyTests)
+	}
+}